@@ -0,0 +1,50 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	stdtesting "testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+// allocsRuns holds the number of calls over which CheckAllocs averages
+// the number of allocations.
+const allocsRuns = 100
+
+// CheckAllocs checks that f performs at most max heap allocations per
+// call, and returns whether it does. The allocations are counted with
+// testing.AllocsPerRun, averaged over a number of calls after a first
+// warm-up call, so an allocation budget can be enforced by the tests
+// rather than only watched in benchmarks:
+//
+//	buf := make([]byte, 0, 64)
+//	testing.CheckAllocs(c, 0, func() {
+//		buf = strconv.AppendInt(buf[:0], 12345, 10)
+//	})
+//
+// The race detector makes code allocate more, so when it is enabled a
+// failure notes that the count may be inflated by it. Tests with exact
+// budgets may want to skip the check when RaceEnabled is true.
+func CheckAllocs(c *gc.C, max int, f func()) bool {
+	allocs := stdtesting.AllocsPerRun(allocsRuns, f)
+	if allocs <= float64(max) {
+		c.Logf("%v allocations per call, within the budget of %d", allocs, max)
+		return true
+	}
+	msg := "%v allocations per call, over the budget of %d"
+	if RaceEnabled {
+		msg += "; the race detector is enabled, which adds allocations, so the budget may only be exceeded under -race"
+	}
+	c.Errorf(msg, allocs, max)
+	return false
+}
+
+// AssertAllocs is like CheckAllocs, but stops the test if f performs
+// more than max allocations per call.
+func AssertAllocs(c *gc.C, max int, f func()) {
+	if !CheckAllocs(c, max, f) {
+		c.FailNow()
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"strconv"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type allocsSuite struct{}
+
+var _ = gc.Suite(&allocsSuite{})
+
+// allocSink keeps allocations made by tests on the heap.
+var allocSink []byte
+
+func (*allocsSuite) TestWithinBudget(c *gc.C) {
+	if testing.RaceEnabled {
+		c.Skip("the race detector adds allocations")
+	}
+	buf := make([]byte, 0, 64)
+	ok := testing.CheckAllocs(c, 0, func() {
+		buf = strconv.AppendInt(buf[:0], 12345, 10)
+	})
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(c.GetTestLog(), gc.Equals, "0 allocations per call, within the budget of 0\n")
+
+	testing.AssertAllocs(c, 2, func() {
+		allocSink = make([]byte, 1024)
+	})
+}
+
+// failingAllocsSuite is run by TestOverBudget rather than being
+// registered with gocheck.
+type failingAllocsSuite struct{}
+
+func (*failingAllocsSuite) TestAllocs(c *gc.C) {
+	testing.AssertAllocs(c, 1, func() {
+		allocSink = make([]byte, 1024)
+		allocSink = make([]byte, 2048)
+	})
+	c.Fatalf("AssertAllocs did not stop the test")
+}
+
+func (*allocsSuite) TestOverBudget(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&failingAllocsSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	expect := `(?s).*\.\.\. Error: 2 allocations per call, over the budget of 1\n.*`
+	if testing.RaceEnabled {
+		expect = `(?s).*\.\.\. Error: \d+ allocations per call, over the budget of 1; the race detector is enabled.*`
+	}
+	c.Assert(output.String(), gc.Matches, expect)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*did not stop the test.*`)
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	stdtesting "testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+var (
+	updateBenchmarks   = flag.Bool("bench.update", false, "Record benchmark baselines instead of checking against them")
+	benchmarkThreshold = flag.Float64("bench.threshold", 0.2, "Fraction by which benchmark results may exceed their baselines before the check fails")
+)
+
+// benchmarkDir holds the directory containing benchmark baseline
+// files. It is found when the package is initialised, as tests may
+// change the working directory.
+var benchmarkDir = func() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.Join("testdata", "benchmarks")
+	}
+	return filepath.Join(wd, "testdata", "benchmarks")
+}()
+
+// BenchmarkBaseline holds the results of a benchmark recorded by
+// CheckBenchmark.
+type BenchmarkBaseline struct {
+	NsPerOp     int64 `json:"ns-per-op"`
+	AllocsPerOp int64 `json:"allocs-per-op"`
+	BytesPerOp  int64 `json:"bytes-per-op"`
+}
+
+// CheckBenchmark runs the benchmark f with testing.Benchmark, and
+// checks that its time and allocations per operation have not
+// regressed from the baseline stored for it by more than the threshold
+// given with the -bench.threshold flag, 20% by default. It returns
+// whether the check succeeded. Performance regressions are then caught
+// by go test rather than by comparing benchmark output by hand:
+//
+//	func (s *parserSuite) TestParsePerformance(c *gc.C) {
+//		testing.CheckBenchmark(c, "", func(b *stdtesting.B) {
+//			for i := 0; i < b.N; i++ {
+//				Parse(input)
+//			}
+//		})
+//	}
+//
+// Baselines are stored in the testdata/benchmarks directory of the
+// package being tested, in a file named after the test and the given
+// name, as CheckSnapshot names snapshot files. When the tests are run
+// with the -bench.update flag, the baselines are recorded instead of
+// checked. They should be recorded on the kind of machine that runs
+// the checks, as timings vary between machines.
+//
+// The benchmark runs for the time given with the -test.benchtime flag,
+// 1s by default. The race detector slows code down and makes it
+// allocate more, so the benchmark is not run when it is enabled.
+func CheckBenchmark(c *gc.C, name string, f func(b *stdtesting.B)) bool {
+	if RaceEnabled {
+		c.Logf("benchmark not checked, as the race detector is enabled")
+		return true
+	}
+	result := stdtesting.Benchmark(func(b *stdtesting.B) {
+		b.ReportAllocs()
+		f(b)
+	})
+	if result.N == 0 {
+		c.Errorf("benchmark failed")
+		return false
+	}
+	obtained := BenchmarkBaseline{
+		NsPerOp:     result.NsPerOp(),
+		AllocsPerOp: result.AllocsPerOp(),
+		BytesPerOp:  result.AllocedBytesPerOp(),
+	}
+	return checkBenchmarkBaseline(c, testFilePath(c, benchmarkDir, name, ".json"), obtained)
+}
+
+// AssertBenchmark is like CheckBenchmark, but stops the test if the
+// check fails.
+func AssertBenchmark(c *gc.C, name string, f func(b *stdtesting.B)) {
+	if !CheckBenchmark(c, name, f) {
+		c.FailNow()
+	}
+}
+
+// checkBenchmarkBaseline checks the obtained benchmark results against
+// the baseline at path, or records them when baselines are being
+// updated.
+func checkBenchmarkBaseline(c *gc.C, path string, obtained BenchmarkBaseline) bool {
+	c.Logf("benchmark: %d ns/op, %d allocs/op, %d B/op", obtained.NsPerOp, obtained.AllocsPerOp, obtained.BytesPerOp)
+	if *updateBenchmarks {
+		data, err := json.MarshalIndent(obtained, "", "\t")
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0755)
+		}
+		if err == nil {
+			err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+		}
+		return c.Check(err, gc.IsNil, gc.Commentf("cannot record benchmark baseline"))
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		c.Errorf("no benchmark baseline at %s; run the tests with -bench.update to record it", path)
+		return false
+	}
+	if !c.Check(err, gc.IsNil) {
+		return false
+	}
+	var baseline BenchmarkBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		c.Errorf("cannot parse benchmark baseline %s: %v", path, err)
+		return false
+	}
+	ok := true
+	for _, m := range []struct {
+		unit               string
+		obtained, baseline int64
+	}{
+		{"ns/op", obtained.NsPerOp, baseline.NsPerOp},
+		{"allocs/op", obtained.AllocsPerOp, baseline.AllocsPerOp},
+		{"B/op", obtained.BytesPerOp, baseline.BytesPerOp},
+	} {
+		if float64(m.obtained) <= float64(m.baseline)*(1+*benchmarkThreshold) {
+			continue
+		}
+		ok = false
+		change := ""
+		if m.baseline > 0 {
+			change = fmt.Sprintf(" (+%.0f%%)", float64(m.obtained-m.baseline)/float64(m.baseline)*100)
+		}
+		c.Errorf("benchmark regressed: %d %s against a baseline of %d%s, over the threshold of %.0f%%",
+			m.obtained, m.unit, m.baseline, change, *benchmarkThreshold*100)
+	}
+	if !ok {
+		c.Logf("baseline at %s; run the tests with -bench.update to record the new results if the regression is expected", path)
+	}
+	return ok
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	stdtesting "testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+type benchBaselineSuite struct {
+	CleanupSuite
+	dir string
+}
+
+var _ = gc.Suite(&benchBaselineSuite{})
+
+func (s *benchBaselineSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.PatchValue(&benchmarkDir, s.dir)
+	s.PatchValue(updateBenchmarks, false)
+	s.PatchValue(benchmarkThreshold, 0.2)
+	benchtime := flag.Lookup("test.benchtime")
+	c.Assert(benchtime, gc.NotNil)
+	orig := benchtime.Value.String()
+	c.Assert(benchtime.Value.Set("10ms"), gc.IsNil)
+	s.AddCleanup(func(*gc.C) {
+		benchtime.Value.Set(orig)
+	})
+}
+
+// writeBaseline writes the baseline for the current test.
+func (s *benchBaselineSuite) writeBaseline(c *gc.C, baseline BenchmarkBaseline) string {
+	data, err := json.Marshal(baseline)
+	c.Assert(err, gc.IsNil)
+	path := filepath.Join(s.dir, c.TestName()+".json")
+	err = ioutil.WriteFile(path, data, 0644)
+	c.Assert(err, gc.IsNil)
+	return path
+}
+
+var benchSink []byte
+
+func allocatingBenchmark(b *stdtesting.B) {
+	for i := 0; i < b.N; i++ {
+		benchSink = make([]byte, 64)
+	}
+}
+
+func (s *benchBaselineSuite) TestUpdate(c *gc.C) {
+	if RaceEnabled {
+		c.Skip("benchmarks are not run under the race detector")
+	}
+	s.PatchValue(updateBenchmarks, true)
+	c.Assert(CheckBenchmark(c, "alloc", allocatingBenchmark), gc.Equals, true)
+
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, "benchBaselineSuite.TestUpdate.alloc.json"))
+	c.Assert(err, gc.IsNil)
+	var baseline BenchmarkBaseline
+	err = json.Unmarshal(data, &baseline)
+	c.Assert(err, gc.IsNil)
+	c.Assert(baseline.NsPerOp > 0, gc.Equals, true)
+	c.Assert(baseline.AllocsPerOp, gc.Equals, int64(1))
+	c.Assert(baseline.BytesPerOp, gc.Equals, int64(64))
+	c.Assert(c.GetTestLog(), gc.Matches, `benchmark: \d+ ns/op, 1 allocs/op, 64 B/op\n`)
+
+	// The recorded baseline is then checked against. The threshold
+	// is raised so that noisy timings do not fail the test.
+	s.PatchValue(updateBenchmarks, false)
+	s.PatchValue(benchmarkThreshold, 100.0)
+	c.Assert(CheckBenchmark(c, "alloc", allocatingBenchmark), gc.Equals, true)
+}
+
+func (s *benchBaselineSuite) TestWithinThreshold(c *gc.C) {
+	path := s.writeBaseline(c, BenchmarkBaseline{NsPerOp: 100, AllocsPerOp: 5, BytesPerOp: 0})
+	ok := checkBenchmarkBaseline(c, path, BenchmarkBaseline{NsPerOp: 120, AllocsPerOp: 2, BytesPerOp: 0})
+	c.Assert(ok, gc.Equals, true)
+}
+
+// regressedBenchmarkSuite is run by TestRegressed rather than being
+// registered with gocheck.
+type regressedBenchmarkSuite struct {
+	path string
+}
+
+func (s *regressedBenchmarkSuite) TestRegressed(c *gc.C) {
+	checkBenchmarkBaseline(c, s.path, BenchmarkBaseline{NsPerOp: 150, AllocsPerOp: 5, BytesPerOp: 16})
+}
+
+func (s *benchBaselineSuite) TestRegressed(c *gc.C) {
+	path := s.writeBaseline(c, BenchmarkBaseline{NsPerOp: 100, AllocsPerOp: 5, BytesPerOp: 0})
+	var output bytes.Buffer
+	result := gc.Run(&regressedBenchmarkSuite{path}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`benchmark regressed: 150 ns/op against a baseline of 100 \(\+50%\), over the threshold of 20%\n.*`+
+		`benchmark regressed: 16 B/op against a baseline of 0, over the threshold of 20%\n.*`+
+		`baseline at .*; run the tests with -bench.update to record the new results if the regression is expected\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*allocs/op against.*`)
+}
+
+func (s *benchBaselineSuite) TestThresholdFlag(c *gc.C) {
+	s.PatchValue(benchmarkThreshold, 0.6)
+	path := s.writeBaseline(c, BenchmarkBaseline{NsPerOp: 100})
+	c.Assert(checkBenchmarkBaseline(c, path, BenchmarkBaseline{NsPerOp: 150}), gc.Equals, true)
+}
+
+func (s *benchBaselineSuite) TestNoBaseline(c *gc.C) {
+	c.ExpectFailure("no baseline")
+	checkBenchmarkBaseline(c, filepath.Join(s.dir, "missing.json"), BenchmarkBaseline{})
+}
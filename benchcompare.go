@@ -0,0 +1,216 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	stdtesting "testing"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+var benchmarkCount = flag.Int("bench.count", 6, "Number of times CompareBenchmarks runs each benchmark")
+
+// benchmarkSignificance holds the p-value below which a difference
+// between two benchmarks is taken to be significant, as used by
+// benchstat.
+const benchmarkSignificance = 0.05
+
+// BenchmarkComparison holds the result of comparing two benchmarks
+// with CompareBenchmarks.
+type BenchmarkComparison struct {
+	// Old and New hold the time per operation of each run of the old
+	// and new benchmarks, in nanoseconds.
+	Old, New []float64
+
+	// OldMedian and NewMedian hold the median time per operation of
+	// the old and new benchmarks, in nanoseconds.
+	OldMedian, NewMedian float64
+
+	// Delta holds the change in the median time per operation from
+	// the old benchmark to the new one, as a fraction of the old. It
+	// is negative if the new benchmark is faster.
+	Delta float64
+
+	// P holds the p-value of the Mann-Whitney U test of whether the
+	// times of the two benchmarks differ.
+	P float64
+}
+
+// Significant reports whether the difference between the benchmarks
+// is statistically significant, with a p-value below 0.05.
+func (cmp BenchmarkComparison) Significant() bool {
+	return cmp.P < benchmarkSignificance
+}
+
+// String returns the comparison in the style of benchstat, such as
+// "1.2µs/op -> 900ns/op: -25.00% (p=0.005 n=6+6)".
+func (cmp BenchmarkComparison) String() string {
+	return fmt.Sprintf("%v/op -> %v/op: %+.2f%% (p=%.3f n=%d+%d)",
+		time.Duration(cmp.OldMedian), time.Duration(cmp.NewMedian),
+		cmp.Delta*100, cmp.P, len(cmp.Old), len(cmp.New))
+}
+
+// CompareBenchmarks runs the benchmarks old and new with
+// testing.Benchmark, alternating between them so that both are
+// equally affected by changes in the load on the machine, and compares
+// their times per operation as benchstat does. Each benchmark is run
+// the number of times given with the -bench.count flag, 6 by default,
+// and each run takes the time given with the -test.benchtime flag.
+func CompareBenchmarks(old, new func(b *stdtesting.B)) (BenchmarkComparison, error) {
+	count := *benchmarkCount
+	if count < 2 {
+		return BenchmarkComparison{}, fmt.Errorf("cannot compare benchmarks run %d times", count)
+	}
+	var cmp BenchmarkComparison
+	for i := 0; i < count; i++ {
+		for _, bench := range []struct {
+			name string
+			f    func(b *stdtesting.B)
+			ns   *[]float64
+		}{
+			{"old", old, &cmp.Old},
+			{"new", new, &cmp.New},
+		} {
+			result := stdtesting.Benchmark(bench.f)
+			if result.N == 0 {
+				return BenchmarkComparison{}, fmt.Errorf("%s benchmark failed", bench.name)
+			}
+			*bench.ns = append(*bench.ns, float64(result.T.Nanoseconds())/float64(result.N))
+		}
+	}
+	cmp.OldMedian = median(cmp.Old)
+	cmp.NewMedian = median(cmp.New)
+	if cmp.OldMedian > 0 {
+		cmp.Delta = (cmp.NewMedian - cmp.OldMedian) / cmp.OldMedian
+	}
+	cmp.P = mannWhitneyU(cmp.Old, cmp.New)
+	return cmp, nil
+}
+
+// CheckFaster compares the benchmarks old and new with
+// CompareBenchmarks, and checks that new is significantly faster than
+// old, taking at least minSpeedup less time per operation, as a
+// fraction of the time taken by old. It returns whether the check
+// succeeded. This guards an optimisation against being lost:
+//
+//	func (s *parserSuite) TestFastPathFaster(c *gc.C) {
+//		testing.CheckFaster(c, 0.2, func(b *stdtesting.B) {
+//			for i := 0; i < b.N; i++ {
+//				parseSlow(input)
+//			}
+//		}, func(b *stdtesting.B) {
+//			for i := 0; i < b.N; i++ {
+//				parseFast(input)
+//			}
+//		})
+//	}
+//
+// Unlike CheckBenchmark, no baseline is needed, as both benchmarks run
+// on the same machine. The race detector slows code down unevenly, so
+// the benchmarks are not run when it is enabled.
+func CheckFaster(c *gc.C, minSpeedup float64, old, new func(b *stdtesting.B)) bool {
+	if RaceEnabled {
+		c.Logf("benchmarks not compared, as the race detector is enabled")
+		return true
+	}
+	cmp, err := CompareBenchmarks(old, new)
+	if err != nil {
+		c.Errorf("%v", err)
+		return false
+	}
+	return checkFaster(c, minSpeedup, cmp)
+}
+
+// AssertFaster is like CheckFaster, but stops the test if the check
+// fails.
+func AssertFaster(c *gc.C, minSpeedup float64, old, new func(b *stdtesting.B)) {
+	if !CheckFaster(c, minSpeedup, old, new) {
+		c.FailNow()
+	}
+}
+
+// checkFaster checks that the comparison shows the new benchmark to be
+// significantly faster than the old one by at least minSpeedup.
+func checkFaster(c *gc.C, minSpeedup float64, cmp BenchmarkComparison) bool {
+	c.Logf("benchmark comparison: %v", cmp)
+	switch {
+	case !cmp.Significant():
+		c.Errorf("new benchmark is not significantly different from old (p=%.3f, needs p<%v)", cmp.P, benchmarkSignificance)
+	case cmp.Delta > -minSpeedup:
+		c.Errorf("new benchmark takes %+.0f%% time per operation, not at most %+.0f%%", cmp.Delta*100, -minSpeedup*100)
+	default:
+		return true
+	}
+	return false
+}
+
+// median returns the median of xs.
+func median(xs []float64) float64 {
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// mannWhitneyU returns the two-sided p-value of the Mann-Whitney U
+// test of whether xs and ys come from the same distribution, using the
+// normal approximation with corrections for ties and continuity.
+func mannWhitneyU(xs, ys []float64) float64 {
+	type sample struct {
+		value float64
+		fromX bool
+	}
+	samples := make([]sample, 0, len(xs)+len(ys))
+	for _, x := range xs {
+		samples = append(samples, sample{x, true})
+	}
+	for _, y := range ys {
+		samples = append(samples, sample{y, false})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].value < samples[j].value
+	})
+	// Rank the samples from 1, giving tied samples the mean of their
+	// ranks.
+	var rankSumX, tieCorrection float64
+	for i := 0; i < len(samples); {
+		j := i
+		for j < len(samples) && samples[j].value == samples[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for _, s := range samples[i:j] {
+			if s.fromX {
+				rankSumX += rank
+			}
+		}
+		t := float64(j - i)
+		tieCorrection += t*t*t - t
+		i = j
+	}
+	n1, n2 := float64(len(xs)), float64(len(ys))
+	n := n1 + n2
+	u := rankSumX - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		return 1
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
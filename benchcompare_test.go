@@ -0,0 +1,154 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"flag"
+	stdtesting "testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+type benchCompareSuite struct {
+	CleanupSuite
+}
+
+var _ = gc.Suite(&benchCompareSuite{})
+
+func (s *benchCompareSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.PatchValue(benchmarkCount, 6)
+	benchtime := flag.Lookup("test.benchtime")
+	c.Assert(benchtime, gc.NotNil)
+	orig := benchtime.Value.String()
+	c.Assert(benchtime.Value.Set("10ms"), gc.IsNil)
+	s.AddCleanup(func(*gc.C) {
+		benchtime.Value.Set(orig)
+	})
+}
+
+var benchCompareSink int
+
+// spinBenchmark returns a benchmark that does n units of work per
+// operation.
+func spinBenchmark(n int) func(b *stdtesting.B) {
+	return func(b *stdtesting.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				benchCompareSink += j
+			}
+		}
+	}
+}
+
+func (s *benchCompareSuite) TestCompareBenchmarks(c *gc.C) {
+	cmp, err := CompareBenchmarks(spinBenchmark(10000), spinBenchmark(1000))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmp.Old, gc.HasLen, 6)
+	c.Assert(cmp.New, gc.HasLen, 6)
+	c.Assert(cmp.NewMedian < cmp.OldMedian, gc.Equals, true)
+	c.Assert(cmp.Delta < 0, gc.Equals, true)
+}
+
+func (s *benchCompareSuite) TestCheckFaster(c *gc.C) {
+	if RaceEnabled {
+		c.Skip("benchmarks are not run under the race detector")
+	}
+	ok := CheckFaster(c, 0.5, spinBenchmark(10000), spinBenchmark(1000))
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(c.GetTestLog(), gc.Matches, `benchmark comparison: .*/op -> .*/op: -\d+\.\d\d% \(p=0\.\d+ n=6\+6\)\n`)
+}
+
+func (s *benchCompareSuite) TestCountTooSmall(c *gc.C) {
+	s.PatchValue(benchmarkCount, 1)
+	_, err := CompareBenchmarks(spinBenchmark(1), spinBenchmark(1))
+	c.Assert(err, gc.ErrorMatches, "cannot compare benchmarks run 1 times")
+}
+
+func (s *benchCompareSuite) TestFailedBenchmark(c *gc.C) {
+	_, err := CompareBenchmarks(spinBenchmark(1), func(b *stdtesting.B) {
+		b.Fatal("broken")
+	})
+	c.Assert(err, gc.ErrorMatches, "new benchmark failed")
+}
+
+var mannWhitneyUTests = []struct {
+	about  string
+	xs, ys []float64
+	p      float64
+}{{
+	about: "separated samples",
+	xs:    []float64{1, 2, 3, 4, 5, 6},
+	ys:    []float64{7, 8, 9, 10, 11, 12},
+	p:     0.0051,
+}, {
+	about: "identical samples",
+	xs:    []float64{5, 5, 5},
+	ys:    []float64{5, 5, 5},
+	p:     1,
+}, {
+	about: "interleaved samples",
+	xs:    []float64{1, 3, 5, 7, 9, 11},
+	ys:    []float64{2, 4, 6, 8, 10, 12},
+	p:     0.6889,
+}, {
+	about: "ties between samples",
+	xs:    []float64{1, 1, 2, 2, 3, 3},
+	ys:    []float64{3, 3, 4, 4, 5, 5},
+	p:     0.0109,
+}}
+
+func (*benchCompareSuite) TestMannWhitneyU(c *gc.C) {
+	for i, test := range mannWhitneyUTests {
+		c.Logf("test %d: %s", i, test.about)
+		p := mannWhitneyU(test.xs, test.ys)
+		c.Check(p, gc.Equals, mannWhitneyU(test.ys, test.xs))
+		c.Check(p > test.p-0.0001 && p < test.p+0.0001, gc.Equals, true, gc.Commentf("p=%v", p))
+	}
+}
+
+func (*benchCompareSuite) TestMedian(c *gc.C) {
+	c.Assert(median([]float64{3, 1, 2}), gc.Equals, 2.0)
+	c.Assert(median([]float64{4, 1, 3, 2}), gc.Equals, 2.5)
+	c.Assert(median(nil), gc.Equals, 0.0)
+}
+
+// notFasterSuite is run by TestNotFaster rather than being registered
+// with gocheck.
+type notFasterSuite struct{}
+
+func (*notFasterSuite) TestNotSignificant(c *gc.C) {
+	checkFaster(c, 0.2, BenchmarkComparison{
+		Old:       []float64{100, 100},
+		New:       []float64{50, 50},
+		OldMedian: 100,
+		NewMedian: 50,
+		Delta:     -0.5,
+		P:         0.2,
+	})
+}
+
+func (*notFasterSuite) TestTooSlow(c *gc.C) {
+	checkFaster(c, 0.2, BenchmarkComparison{
+		Old:       []float64{1000, 1000, 1000, 1000, 1000, 1000},
+		New:       []float64{900, 900, 900, 900, 900, 900},
+		OldMedian: 1000,
+		NewMedian: 900,
+		Delta:     -0.1,
+		P:         0.002,
+	})
+}
+
+func (*benchCompareSuite) TestNotFaster(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&notFasterSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 2)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`benchmark comparison: 100ns/op -> 50ns/op: -50\.00% \(p=0\.200 n=2\+2\)\n.*`+
+		`new benchmark is not significantly different from old \(p=0\.200, needs p<0\.05\)\n.*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`benchmark comparison: 1µs/op -> 900ns/op: -10\.00% \(p=0\.002 n=6\+6\)\n.*`+
+		`new benchmark takes -10% time per operation, not at most -20%\n.*`)
+}
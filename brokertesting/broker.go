@@ -0,0 +1,367 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package brokertesting provides an in-memory publish/subscribe
+// message broker, so that event-driven code can be tested without a
+// real broker.
+package brokertesting
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+// Message holds a message published to a Broker.
+type Message struct {
+	// ID holds the position of the message in the order of
+	// publication, starting at 1. It is set by the broker.
+	ID int
+
+	// Topic holds the topic to which the message was published.
+	Topic string
+
+	// Data holds the message body.
+	Data []byte
+
+	// Header holds any message headers.
+	Header map[string]string
+
+	// Attempt holds the number of times the message has been
+	// delivered to the subscription, including this delivery. It is
+	// zero in messages returned by Published.
+	Attempt int
+
+	// Redelivered reports whether the message has been delivered to
+	// the subscription before.
+	Redelivered bool
+}
+
+// String returns a description of the message for use in test logs.
+func (m Message) String() string {
+	return fmt.Sprintf("#%d %s %q", m.ID, m.Topic, m.Data)
+}
+
+// Handler is called to deliver a message to a subscription. If it
+// returns an error, the message is negatively acknowledged and will be
+// redelivered.
+type Handler func(m Message) error
+
+// Order arranges the messages pending for a subscription into the order
+// in which they are delivered.
+type Order func(msgs []Message)
+
+// Reversed is an Order that delivers pending messages newest first.
+func Reversed(msgs []Message) {
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+}
+
+// Shuffled returns an Order that delivers pending messages in a random
+// order chosen with r. Using a generator from testing.NewSeededRand
+// makes failures reproducible.
+func Shuffled(r *rand.Rand) Order {
+	return func(msgs []Message) {
+		r.Shuffle(len(msgs), func(i, j int) {
+			msgs[i], msgs[j] = msgs[j], msgs[i]
+		})
+	}
+}
+
+// Broker is an in-memory message broker. Published messages are queued
+// for each matching subscription, and delivered to the subscriptions'
+// handlers when the test calls Deliver, so that tests control exactly
+// when and in what order messages arrive:
+//
+//	broker := brokertesting.NewBroker()
+//	broker.Order = brokertesting.Shuffled(testing.NewSeededRand(c))
+//	broker.Subscribe("orders.>", consumer.Handle)
+//	broker.Publish("orders.created", []byte(`{"id": 1}`))
+//	broker.Deliver()
+//	broker.AssertPublished(c, "invoices.*", `{"order": 1}`)
+//
+// Topics are made of tokens separated by dots. In subscription
+// patterns, as with NATS subjects, "*" matches any single token and
+// ">" matches one or more trailing tokens.
+type Broker struct {
+	// Order holds the order in which the messages pending for each
+	// subscription are delivered. If it is nil, they are delivered
+	// in the order they were published.
+	Order Order
+
+	// MaxDeliveries holds the number of times a message is delivered
+	// to a subscription whose handler keeps failing before it is
+	// moved to the dead letters. If it is zero, 3 is used.
+	MaxDeliveries int
+
+	mu            sync.Mutex
+	subscriptions []*Subscription
+	published     []Message
+	deadLetters   []Message
+	redeliverNext int
+	// changed is closed and replaced when a message is published.
+	changed chan struct{}
+}
+
+// Subscription is a subscription to the topics matching a pattern.
+type Subscription struct {
+	broker  *Broker
+	pattern string
+	handler Handler
+	pending []Message
+}
+
+// NewBroker returns a new Broker with no subscriptions.
+func NewBroker() *Broker {
+	return &Broker{
+		changed: make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes the handler to messages published to topics
+// matching the pattern, from now on.
+func (b *Broker) Subscribe(pattern string, h Handler) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub := &Subscription{
+		broker:  b,
+		pattern: pattern,
+		handler: h,
+	}
+	b.subscriptions = append(b.subscriptions, sub)
+	return sub
+}
+
+// Unsubscribe cancels the subscription. Any messages pending for it are
+// discarded.
+func (sub *Subscription) Unsubscribe() {
+	b := sub.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.subscriptions {
+		if s == sub {
+			b.subscriptions = append(b.subscriptions[:i:i], b.subscriptions[i+1:]...)
+			break
+		}
+	}
+	sub.pending = nil
+}
+
+// Pending returns the number of messages waiting to be delivered to the
+// subscription.
+func (sub *Subscription) Pending() int {
+	sub.broker.mu.Lock()
+	defer sub.broker.mu.Unlock()
+	return len(sub.pending)
+}
+
+// Publish publishes a message with the given topic and body.
+func (b *Broker) Publish(topic string, data []byte) {
+	b.PublishMessage(Message{Topic: topic, Data: data})
+}
+
+// PublishMessage publishes a message. Its ID and delivery fields are
+// ignored.
+func (b *Broker) PublishMessage(m Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m.ID = len(b.published) + 1
+	m.Data = append([]byte(nil), m.Data...)
+	m.Header = copyHeader(m.Header)
+	m.Attempt = 0
+	m.Redelivered = false
+	b.published = append(b.published, m)
+	for _, sub := range b.subscriptions {
+		if Match(sub.pattern, m.Topic) {
+			sub.pending = append(sub.pending, m)
+		}
+	}
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// RedeliverNext causes the next n messages that are successfully
+// handled to be delivered again, as a broker with at-least-once
+// delivery may do, so that tests can check that handlers are
+// idempotent.
+func (b *Broker) RedeliverNext(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.redeliverNext += n
+}
+
+// Deliver delivers the pending messages to each subscription in turn,
+// calling its handler once for each message, and returns the number of
+// deliveries made. Messages published by handlers, and messages to be
+// redelivered, are delivered too, until no messages are pending.
+//
+// Handlers are called in the calling goroutine, without any locks held.
+func (b *Broker) Deliver() int {
+	n := 0
+	for {
+		sub, batch := b.takePending()
+		if sub == nil {
+			return n
+		}
+		for _, m := range batch {
+			m.Attempt++
+			m.Redelivered = m.Attempt > 1
+			err := sub.handler(m)
+			n++
+			b.delivered(sub, m, err)
+		}
+	}
+}
+
+// takePending removes and returns the messages pending for the first
+// subscription that has any, in delivery order.
+func (b *Broker) takePending() (*Subscription, []Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subscriptions {
+		if len(sub.pending) == 0 {
+			continue
+		}
+		batch := sub.pending
+		sub.pending = nil
+		if b.Order != nil {
+			b.Order(batch)
+		}
+		return sub, batch
+	}
+	return nil, nil
+}
+
+// delivered records the result of delivering m to sub.
+func (b *Broker) delivered(sub *Subscription, m Message, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		if b.redeliverNext == 0 {
+			return
+		}
+		b.redeliverNext--
+	} else if m.Attempt >= b.maxDeliveries() {
+		b.deadLetters = append(b.deadLetters, m)
+		return
+	}
+	if !b.subscribed(sub) {
+		return
+	}
+	sub.pending = append(sub.pending, m)
+}
+
+func (b *Broker) subscribed(sub *Subscription) bool {
+	for _, s := range b.subscriptions {
+		if s == sub {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Broker) maxDeliveries() int {
+	if b.MaxDeliveries == 0 {
+		return 3
+	}
+	return b.MaxDeliveries
+}
+
+// Published returns the messages published so far to topics matching
+// the pattern, in order. If pattern is ">", all messages are returned.
+func (b *Broker) Published(pattern string) []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.matching(pattern)
+}
+
+func (b *Broker) matching(pattern string) []Message {
+	var msgs []Message
+	for _, m := range b.published {
+		if Match(pattern, m.Topic) {
+			m.Data = append([]byte(nil), m.Data...)
+			m.Header = copyHeader(m.Header)
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs
+}
+
+// DeadLetters returns the messages that were not handled successfully
+// within MaxDeliveries attempts, as they were last delivered.
+func (b *Broker) DeadLetters() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Message(nil), b.deadLetters...)
+}
+
+// AssertPublished checks that the bodies of the messages published to
+// topics matching the pattern are exactly those given, in order. On
+// failure, the test reports a diff of the bodies.
+func (b *Broker) AssertPublished(c *gc.C, pattern string, data ...string) {
+	c.Assert(bodies(b.Published(pattern)), jc.ListEquals, data, gc.Commentf("messages published to %s", pattern))
+}
+
+// WaitPublished waits until at least n messages have been published to
+// topics matching the pattern, and returns them. It is useful when the
+// code under test publishes from another goroutine. The test fails if
+// the messages are not published within testing.LongWait.
+func (b *Broker) WaitPublished(c *gc.C, pattern string, n int) []Message {
+	timeout := time.After(testing.LongWait)
+	for {
+		b.mu.Lock()
+		msgs := b.matching(pattern)
+		changed := b.changed
+		b.mu.Unlock()
+		if len(msgs) >= n {
+			return msgs
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			c.Fatalf("timed out waiting for %d messages published to %s; got %q", n, pattern, bodies(msgs))
+		}
+	}
+}
+
+func bodies(msgs []Message) []string {
+	data := make([]string, len(msgs))
+	for i, m := range msgs {
+		data[i] = string(m.Data)
+	}
+	return data
+}
+
+// Match reports whether the topic matches the subscription pattern.
+func Match(pattern, topic string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	topicTokens := strings.Split(topic, ".")
+	for i, p := range patternTokens {
+		if p == ">" && i == len(patternTokens)-1 {
+			return len(topicTokens) > i
+		}
+		if i >= len(topicTokens) || (p != "*" && p != topicTokens[i]) {
+			return false
+		}
+	}
+	return len(topicTokens) == len(patternTokens)
+}
+
+func copyHeader(h map[string]string) map[string]string {
+	if h == nil {
+		return nil
+	}
+	c := make(map[string]string, len(h))
+	for k, v := range h {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package brokertesting_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/brokertesting"
+	jc "github.com/juju/testing/checkers"
+)
+
+type brokerSuite struct{}
+
+var _ = gc.Suite(&brokerSuite{})
+
+var matchTests = []struct {
+	pattern string
+	topic   string
+	match   bool
+}{
+	{"orders.created", "orders.created", true},
+	{"orders.created", "orders.deleted", false},
+	{"orders.created", "orders", false},
+	{"orders.*", "orders.created", true},
+	{"orders.*", "orders", false},
+	{"orders.*", "orders.created.eu", false},
+	{"*.created", "orders.created", true},
+	{"orders.>", "orders.created", true},
+	{"orders.>", "orders.created.eu", true},
+	{"orders.>", "orders", false},
+	{">", "orders", true},
+	{">", "orders.created", true},
+	{"orders.>.eu", "orders.created.eu", false},
+}
+
+func (*brokerSuite) TestMatch(c *gc.C) {
+	for i, test := range matchTests {
+		c.Logf("test %d: %s %s", i, test.pattern, test.topic)
+		c.Check(brokertesting.Match(test.pattern, test.topic), gc.Equals, test.match)
+	}
+}
+
+// recorder records the bodies of the messages it handles.
+type recorder struct {
+	bodies []string
+}
+
+func (r *recorder) handle(m brokertesting.Message) error {
+	r.bodies = append(r.bodies, string(m.Data))
+	return nil
+}
+
+func (*brokerSuite) TestDeliver(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	var created, all recorder
+	broker.Subscribe("orders.created", created.handle)
+	sub := broker.Subscribe("orders.>", all.handle)
+	broker.Publish("orders.created", []byte("1"))
+	broker.Publish("orders.deleted", []byte("2"))
+	broker.Publish("invoices.created", []byte("3"))
+	c.Assert(sub.Pending(), gc.Equals, 2)
+	c.Assert(created.bodies, gc.HasLen, 0)
+
+	c.Assert(broker.Deliver(), gc.Equals, 3)
+	c.Assert(created.bodies, jc.DeepEquals, []string{"1"})
+	c.Assert(all.bodies, jc.DeepEquals, []string{"1", "2"})
+	c.Assert(sub.Pending(), gc.Equals, 0)
+	c.Assert(broker.Deliver(), gc.Equals, 0)
+
+	sub.Unsubscribe()
+	broker.Publish("orders.created", []byte("4"))
+	broker.Deliver()
+	c.Assert(created.bodies, jc.DeepEquals, []string{"1", "4"})
+	c.Assert(all.bodies, jc.DeepEquals, []string{"1", "2"})
+}
+
+func (*brokerSuite) TestDeliverPublishedByHandlers(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	broker.Subscribe("orders.created", func(m brokertesting.Message) error {
+		broker.Publish("invoices.created", m.Data)
+		return nil
+	})
+	var invoices recorder
+	broker.Subscribe("invoices.*", invoices.handle)
+	broker.Publish("orders.created", []byte("1"))
+	c.Assert(broker.Deliver(), gc.Equals, 2)
+	c.Assert(invoices.bodies, jc.DeepEquals, []string{"1"})
+}
+
+func (*brokerSuite) TestOrder(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	var r recorder
+	broker.Subscribe(">", r.handle)
+	publish := func() {
+		for i := 1; i <= 5; i++ {
+			broker.Publish("t", []byte(fmt.Sprint(i)))
+		}
+	}
+	publish()
+	broker.Deliver()
+	c.Assert(r.bodies, jc.DeepEquals, []string{"1", "2", "3", "4", "5"})
+
+	r.bodies = nil
+	broker.Order = brokertesting.Reversed
+	publish()
+	broker.Deliver()
+	c.Assert(r.bodies, jc.DeepEquals, []string{"5", "4", "3", "2", "1"})
+
+	r.bodies = nil
+	broker.Order = brokertesting.Shuffled(rand.New(rand.NewSource(1)))
+	publish()
+	broker.Deliver()
+	c.Assert(r.bodies, gc.Not(jc.DeepEquals), []string{"1", "2", "3", "4", "5"})
+	sort.Strings(r.bodies)
+	c.Assert(r.bodies, jc.DeepEquals, []string{"1", "2", "3", "4", "5"})
+}
+
+func (*brokerSuite) TestRedeliveryOnFailure(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	var deliveries []brokertesting.Message
+	broker.Subscribe("t", func(m brokertesting.Message) error {
+		deliveries = append(deliveries, m)
+		if string(m.Data) == "bad" || m.Attempt < 2 {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	})
+	broker.Publish("t", []byte("good"))
+	broker.Publish("t", []byte("bad"))
+	c.Assert(broker.Deliver(), gc.Equals, 5)
+
+	var attempts []string
+	for _, m := range deliveries {
+		attempts = append(attempts, fmt.Sprintf("%s %d %v", m.Data, m.Attempt, m.Redelivered))
+	}
+	c.Assert(attempts, jc.DeepEquals, []string{
+		"good 1 false",
+		"bad 1 false",
+		"good 2 true",
+		"bad 2 true",
+		"bad 3 true",
+	})
+	dead := broker.DeadLetters()
+	c.Assert(dead, gc.HasLen, 1)
+	c.Assert(string(dead[0].Data), gc.Equals, "bad")
+	c.Assert(dead[0].Attempt, gc.Equals, 3)
+}
+
+func (*brokerSuite) TestMaxDeliveries(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	broker.MaxDeliveries = 1
+	broker.Subscribe("t", func(m brokertesting.Message) error {
+		return fmt.Errorf("failed")
+	})
+	broker.Publish("t", []byte("x"))
+	c.Assert(broker.Deliver(), gc.Equals, 1)
+	c.Assert(broker.DeadLetters(), gc.HasLen, 1)
+}
+
+func (*brokerSuite) TestRedeliverNext(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	var deliveries []brokertesting.Message
+	broker.Subscribe("t", func(m brokertesting.Message) error {
+		deliveries = append(deliveries, m)
+		return nil
+	})
+	broker.RedeliverNext(1)
+	broker.Publish("t", []byte("1"))
+	broker.Publish("t", []byte("2"))
+	c.Assert(broker.Deliver(), gc.Equals, 3)
+	c.Assert(deliveries[2].ID, gc.Equals, 1)
+	c.Assert(deliveries[2].Redelivered, jc.IsTrue)
+}
+
+func (*brokerSuite) TestPublished(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	broker.PublishMessage(brokertesting.Message{
+		Topic:  "orders.created",
+		Data:   []byte("1"),
+		Header: map[string]string{"trace": "abc"},
+	})
+	broker.Publish("invoices.created", []byte("2"))
+	c.Assert(broker.Published(">"), jc.DeepEquals, []brokertesting.Message{{
+		ID:     1,
+		Topic:  "orders.created",
+		Data:   []byte("1"),
+		Header: map[string]string{"trace": "abc"},
+	}, {
+		ID:    2,
+		Topic: "invoices.created",
+		Data:  []byte("2"),
+	}})
+	broker.AssertPublished(c, "orders.*", "1")
+	broker.AssertPublished(c, "*.created", "1", "2")
+	broker.AssertPublished(c, "deliveries.>")
+}
+
+func (*brokerSuite) TestAssertPublishedFailure(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	broker.Publish("t", []byte("1"))
+	c.ExpectFailure("different message published")
+	broker.AssertPublished(c, "t", "2")
+}
+
+func (*brokerSuite) TestWaitPublished(c *gc.C) {
+	broker := brokertesting.NewBroker()
+	go func() {
+		for i := 0; i < 3; i++ {
+			broker.Publish("t", []byte(fmt.Sprint(i)))
+		}
+	}()
+	msgs := broker.WaitPublished(c, "t", 3)
+	c.Assert(msgs, gc.HasLen, 3)
+}
@@ -5,7 +5,10 @@
 package checkers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -222,3 +225,81 @@ func (checker *samePathChecker) Check(params []interface{}, names []string) (res
 	}
 	return false, fmt.Sprintf("Not the same file")
 }
+
+// FileHasSize checker
+
+type fileHasSizeChecker struct {
+	*gc.CheckerInfo
+}
+
+// FileHasSize checks that the file with the obtained name has the
+// expected size in bytes, which may be given as any integer type.
+var FileHasSize gc.Checker = &fileHasSizeChecker{
+	&gc.CheckerInfo{Name: "FileHasSize", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *fileHasSizeChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	filename, isString := stringOrStringer(params[0])
+	if !isString {
+		return false, fmt.Sprintf("obtained value is not a string and has no .String(), %T:%#v", params[0], params[0])
+	}
+	expected := reflect.ValueOf(params[1])
+	var size int64
+	switch expected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = expected.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = int64(expected.Uint())
+	default:
+		return false, fmt.Sprintf("expected value must be an integer, got %T", params[1])
+	}
+	fileInfo, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return false, fmt.Sprintf("%s does not exist", filename)
+	} else if err != nil {
+		return false, fmt.Sprintf("other stat error: %v", err)
+	}
+	if fileInfo.Size() != size {
+		return false, fmt.Sprintf("%s has size %d, want %d", filename, fileInfo.Size(), size)
+	}
+	return true, ""
+}
+
+// FileHasSHA256 checker
+
+type fileHasSHA256Checker struct {
+	*gc.CheckerInfo
+}
+
+// FileHasSHA256 checks that the contents of the file with the obtained
+// name have the expected SHA-256 digest, given as a hex string. The
+// file is read in a streaming fashion, so it may be arbitrarily large.
+var FileHasSHA256 gc.Checker = &fileHasSHA256Checker{
+	&gc.CheckerInfo{Name: "FileHasSHA256", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *fileHasSHA256Checker) Check(params []interface{}, names []string) (result bool, error string) {
+	filename, isString := stringOrStringer(params[0])
+	if !isString {
+		return false, fmt.Sprintf("obtained value is not a string and has no .String(), %T:%#v", params[0], params[0])
+	}
+	expected, ok := params[1].(string)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be a hex string, got %T", params[1])
+	}
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return false, fmt.Sprintf("%s does not exist", filename)
+	} else if err != nil {
+		return false, fmt.Sprintf("cannot open file: %v", err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return false, fmt.Sprintf("cannot read file: %v", err)
+	}
+	if digest := hex.EncodeToString(hash.Sum(nil)); digest != strings.ToLower(expected) {
+		return false, fmt.Sprintf("%s has SHA-256 %s, want %s", filename, digest, expected)
+	}
+	return true, ""
+}
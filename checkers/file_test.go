@@ -286,3 +286,42 @@ func (s *SamePathWindowsSuite) TestSamePathShortenedPathsConsistent(c *gc.C) {
 	c.Assert(result, jc.IsTrue)
 	c.Assert(message, gc.Equals, "")
 }
+
+func (s *FileSuite) TestFileHasSize(c *gc.C) {
+	name := filepath.Join(c.MkDir(), "file")
+	err := ioutil.WriteFile(name, []byte("hello"), 0644)
+	c.Assert(err, gc.IsNil)
+	c.Assert(name, jc.FileHasSize, 5)
+	c.Assert(name, jc.FileHasSize, int64(5))
+	c.Assert(name, jc.FileHasSize, uint8(5))
+
+	result, message := jc.FileHasSize.Check([]interface{}{name, 6}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, name+" has size 5, want 6")
+
+	result, message = jc.FileHasSize.Check([]interface{}{name, "5"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "expected value must be an integer, got string")
+
+	missing := filepath.Join(c.MkDir(), "missing")
+	result, message = jc.FileHasSize.Check([]interface{}{missing, 0}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, missing+" does not exist")
+}
+
+func (s *FileSuite) TestFileHasSHA256(c *gc.C) {
+	name := filepath.Join(c.MkDir(), "file")
+	err := ioutil.WriteFile(name, []byte("hello"), 0644)
+	c.Assert(err, gc.IsNil)
+	const digest = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	c.Assert(name, jc.FileHasSHA256, digest)
+	c.Assert(name, jc.FileHasSHA256, strings.ToUpper(digest))
+
+	result, message := jc.FileHasSHA256.Check([]interface{}{name, "abcd"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, name+" has SHA-256 "+digest+", want abcd")
+
+	result, message = jc.FileHasSHA256.Check([]interface{}{42, digest}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "obtained value is not a string and has no .String(), int:42")
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package checkers
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+type listEqualsChecker struct {
+	*gc.CheckerInfo
+}
+
+// ListEquals checks that the obtained slice or array holds the same
+// elements as the expected one, in the same order, comparing elements
+// as DeepEquals does. On failure it reports a line-by-line diff of the
+// two lists, in which elements only present in the expected list are
+// prefixed with "-" and elements only present in the obtained list are
+// prefixed with "+". For example:
+//
+//	  [0] "a"
+//	- [1] "b"
+//	+ [1] "x"
+//	  [2] "c"
+//
+// A nil slice is considered equal to an empty slice.
+//
+// Computing the diff takes time and memory proportional to the product
+// of the lengths of the lists, so it is bounded by DiffMemoryLimit and
+// DiffTimeLimit. Over those limits, only a summary of the difference is
+// reported, so that a failing check of very long lists cannot hang
+// the tests.
+var ListEquals gc.Checker = &listEqualsChecker{
+	&gc.CheckerInfo{Name: "ListEquals", Params: []string{"obtained", "expected"}},
+}
+
+// DiffMemoryLimit holds the most memory, in bytes, that ListEquals
+// may use to compute a diff of two lists. If it is zero, the memory
+// used is not limited.
+var DiffMemoryLimit int64 = 64 << 20
+
+// DiffTimeLimit holds the longest that ListEquals may spend computing
+// a diff of two lists. If it is zero, the time spent is not limited.
+var DiffTimeLimit = 10 * time.Second
+
+func (checker *listEqualsChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	obtained, err := listValue(params[0])
+	if err != "" {
+		return false, "obtained value " + err
+	}
+	expected, err := listValue(params[1])
+	if err != "" {
+		return false, "expected value " + err
+	}
+	if obtained.IsValid() && expected.IsValid() && obtained.Type() != expected.Type() {
+		return false, fmt.Sprintf("type mismatch %s vs %s", obtained.Type(), expected.Type())
+	}
+	diff, equal := listDiff(obtained, expected)
+	if equal {
+		return true, ""
+	}
+	return false, diff
+}
+
+// listValue returns the reflect.Value of the given list, or an invalid
+// value if the list is nil.
+func listValue(list interface{}) (reflect.Value, string) {
+	if list == nil {
+		return reflect.Value{}, ""
+	}
+	v := reflect.ValueOf(list)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v, ""
+	}
+	return v, fmt.Sprintf("must be a slice or array, got %T", list)
+}
+
+// listDiff returns a description of the difference between the
+// obtained and expected lists, and whether they are equal. Either value
+// may be invalid, in which case it is treated as an empty list. The
+// description is a diff of the lists unless computing one would exceed
+// DiffMemoryLimit or DiffTimeLimit, in which case it is a summary.
+func listDiff(obtained, expected reflect.Value) (string, bool) {
+	obtainedLen, expectedLen := listLen(obtained), listLen(expected)
+	equal := func(i, j int) bool {
+		ok, _ := DeepEqual(interfaceOf(obtained.Index(i)), interfaceOf(expected.Index(j)))
+		return ok
+	}
+
+	// Find the first difference, which is all that is needed to
+	// tell whether the lists are equal.
+	first := 0
+	for first < obtainedLen && first < expectedLen && equal(first, first) {
+		first++
+	}
+	if first == obtainedLen && first == expectedLen {
+		return "", true
+	}
+	suppressed := func(reason string) (string, bool) {
+		return fmt.Sprintf("lists differ; diff suppressed, %d elements obtained and %d expected, first difference at [%d] (%s)",
+			obtainedLen, expectedLen, first, reason), false
+	}
+
+	// lcs[i][j] holds the length of the longest common subsequence
+	// of obtained[i:] and expected[j:].
+	memory := int64(obtainedLen+1) * int64(expectedLen+1) * strconv.IntSize / 8
+	if DiffMemoryLimit > 0 && memory > DiffMemoryLimit {
+		return suppressed(fmt.Sprintf("diff needs %d bytes, over DiffMemoryLimit of %d", memory, DiffMemoryLimit))
+	}
+	var deadline time.Time
+	if DiffTimeLimit > 0 {
+		deadline = time.Now().Add(DiffTimeLimit)
+	}
+	lcs := make([][]int, obtainedLen+1)
+	for i := range lcs {
+		lcs[i] = make([]int, expectedLen+1)
+	}
+	for i := obtainedLen - 1; i >= 0; i-- {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return suppressed(fmt.Sprintf("diff took longer than DiffTimeLimit of %v", DiffTimeLimit))
+		}
+		for j := expectedLen - 1; j >= 0; j-- {
+			switch {
+			case equal(i, j):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var buf strings.Builder
+	buf.WriteString("difference:\n")
+	line := func(prefix string, index int, v reflect.Value) {
+		fmt.Fprintf(&buf, "  %s [%d] %s\n", prefix, index, formatElement(v))
+	}
+	i, j := 0, 0
+	for i < obtainedLen || j < expectedLen {
+		switch {
+		case i < obtainedLen && j < expectedLen && equal(i, j):
+			line(" ", j, expected.Index(j))
+			i++
+			j++
+		case j < expectedLen && (i == obtainedLen || lcs[i][j+1] >= lcs[i+1][j]):
+			line("-", j, expected.Index(j))
+			j++
+		default:
+			line("+", i, obtained.Index(i))
+			i++
+		}
+	}
+	return buf.String(), false
+}
+
+// formatElement formats a list element for display in a diff, using
+// its String method if it has one.
+func formatElement(v reflect.Value) (s string) {
+	e := interfaceOf(v)
+	if stringer, ok := e.(fmt.Stringer); ok {
+		defer func() {
+			// The String method may panic, for example when
+			// called on a nil pointer.
+			if recover() != nil {
+				s = fmt.Sprintf("%#v", e)
+			}
+		}()
+		return stringer.String()
+	}
+	return fmt.Sprintf("%#v", e)
+}
+
+func listLen(v reflect.Value) int {
+	if !v.IsValid() {
+		return 0
+	}
+	return v.Len()
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package checkers_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+type ListSuite struct{}
+
+var _ = gc.Suite(&ListSuite{})
+
+var listEqualsTests = []struct {
+	about    string
+	obtained interface{}
+	expected interface{}
+	result   bool
+	msg      string
+}{{
+	about:    "equal lists",
+	obtained: []string{"a", "b"},
+	expected: []string{"a", "b"},
+	result:   true,
+}, {
+	about:    "nil and empty lists",
+	obtained: []string(nil),
+	expected: []string{},
+	result:   true,
+}, {
+	about:    "untyped nil",
+	obtained: []int{},
+	expected: nil,
+	result:   true,
+}, {
+	about:    "arrays",
+	obtained: [2]int{1, 2},
+	expected: [2]int{1, 2},
+	result:   true,
+}, {
+	about:    "elements compared deeply",
+	obtained: [][]int{{1}, nil},
+	expected: [][]int{{1}, {}},
+	result:   true,
+}, {
+	about:    "changed element",
+	obtained: []string{"a", "x", "c"},
+	expected: []string{"a", "b", "c"},
+	msg: `difference:
+    \[0\] "a"
+  - \[1\] "b"
+  \+ \[1\] "x"
+    \[2\] "c"
+`,
+}, {
+	about:    "missing elements",
+	obtained: []int{2},
+	expected: []int{1, 2, 3},
+	msg: `difference:
+  - \[0\] 1
+    \[1\] 2
+  - \[2\] 3
+`,
+}, {
+	about:    "extra elements",
+	obtained: []int{1, 2, 3},
+	expected: []int{2},
+	msg: `difference:
+  \+ \[0\] 1
+    \[0\] 2
+  \+ \[2\] 3
+`,
+}, {
+	about:    "type mismatch",
+	obtained: []int{1},
+	expected: []string{"1"},
+	msg:      `type mismatch \[\]int vs \[\]string`,
+}, {
+	about:    "obtained not a list",
+	obtained: 1,
+	expected: []int{1},
+	msg:      `obtained value must be a slice or array, got int`,
+}}
+
+func (s *ListSuite) TestListEquals(c *gc.C) {
+	for i, test := range listEqualsTests {
+		c.Logf("test %d: %s", i, test.about)
+		result, msg := jc.ListEquals.Check([]interface{}{test.obtained, test.expected}, nil)
+		c.Check(result, gc.Equals, test.result)
+		c.Check(msg, gc.Matches, test.msg)
+	}
+}
+
+func (s *ListSuite) TestDiffMemoryLimit(c *gc.C) {
+	defer func(limit int64) {
+		jc.DiffMemoryLimit = limit
+	}(jc.DiffMemoryLimit)
+	jc.DiffMemoryLimit = 1 << 20
+	obtained := make([]int, 1000)
+	expected := make([]int, 1000)
+	expected[10] = 1
+	result, msg := jc.ListEquals.Check([]interface{}{obtained, expected}, nil)
+	c.Check(result, gc.Equals, false)
+	c.Check(msg, gc.Matches, `lists differ; diff suppressed, 1000 elements obtained and 1000 expected, first difference at \[10\] \(diff needs \d+ bytes, over DiffMemoryLimit of 1048576\)`)
+
+	// Equal lists are still found to be equal.
+	result, _ = jc.ListEquals.Check([]interface{}{obtained, obtained}, nil)
+	c.Check(result, gc.Equals, true)
+
+	jc.DiffMemoryLimit = 0
+	result, msg = jc.ListEquals.Check([]interface{}{obtained, expected}, nil)
+	c.Check(result, gc.Equals, false)
+	c.Check(msg, gc.Matches, `(?s)difference:\n.*  - \[10\] 1\n.*`)
+}
+
+func (s *ListSuite) TestDiffTimeLimit(c *gc.C) {
+	defer func(limit time.Duration) {
+		jc.DiffTimeLimit = limit
+	}(jc.DiffTimeLimit)
+	jc.DiffTimeLimit = time.Nanosecond
+	result, msg := jc.ListEquals.Check([]interface{}{[]int{1, 2}, []int{1, 2, 3}}, nil)
+	c.Check(result, gc.Equals, false)
+	c.Check(msg, gc.Equals, `lists differ; diff suppressed, 2 elements obtained and 3 expected, first difference at [2] (diff took longer than DiffTimeLimit of 1ns)`)
+}
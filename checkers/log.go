@@ -46,9 +46,13 @@ type logMatches struct {
 
 func (checker *logMatches) Check(params []interface{}, _ []string) (result bool, error string) {
 	var obtained SimpleMessages
-	switch params[0].(type) {
+	switch param := params[0].(type) {
 	case []loggo.Entry:
-		obtained = logToSimpleMessages(params[0].([]loggo.Entry))
+		obtained = logToSimpleMessages(param)
+	case []SimpleMessage:
+		obtained = SimpleMessages(param)
+	case SimpleMessages:
+		obtained = param
 	default:
 		return false, "Obtained value must be of type []loggo.Entry or SimpleMessage"
 	}
@@ -71,28 +75,53 @@ func (checker *logMatches) Check(params []interface{}, _ []string) (result bool,
 		return false, "Expected value must be of type []string or []SimpleMessage"
 	}
 
-	obtainedSinceLastMatch := obtained
-	for len(expected) > 0 && len(obtained) >= len(expected) {
-		var msg SimpleMessage
-		msg, obtained = obtained[0], obtained[1:]
-		expect := expected[0]
+	// matched[i] records whether obtained[i] satisfied an expectation.
+	matched := make([]bool, len(obtained))
+	remaining := expected
+	for i, msg := range obtained {
+		if len(remaining) == 0 {
+			break
+		}
+		expect := remaining[0]
 		if expect.Level != loggo.UNSPECIFIED && msg.Level != expect.Level {
 			continue
 		}
-		if matched, err := regexp.MatchString(expect.Message, msg.Message); err != nil {
+		if ok, err := regexp.MatchString(expect.Message, msg.Message); err != nil {
 			return false, fmt.Sprintf("bad message regexp %q: %v", expect.Message, err)
-		} else if !matched {
+		} else if !ok {
 			continue
 		}
-		expected = expected[1:]
-		obtainedSinceLastMatch = obtained
+		matched[i] = true
+		remaining = remaining[1:]
+	}
+	if len(remaining) == 0 {
+		return true, ""
+	}
+	return false, logDiff(obtained, matched, remaining)
+}
+
+// logDiff formats the result of a failed log match. Each obtained
+// message is shown, prefixed with "+" if it did not satisfy any
+// expectation, followed by the unmatched expectations prefixed
+// with "-".
+func logDiff(obtained SimpleMessages, matched []bool, unmatched SimpleMessages) string {
+	var buf strings.Builder
+	buf.WriteString("unmatched log expectations:\n")
+	for i, msg := range obtained {
+		prefix := "+"
+		if matched[i] {
+			prefix = " "
+		}
+		fmt.Fprintf(&buf, "  %s %s\n", prefix, msg)
 	}
-	if len(obtained) < len(expected) {
-		params[0] = obtainedSinceLastMatch
-		params[1] = expected
-		return false, ""
+	for _, expect := range unmatched {
+		level := "ANY"
+		if expect.Level != loggo.UNSPECIFIED {
+			level = expect.Level.String()
+		}
+		fmt.Fprintf(&buf, "  - %s %q\n", level, expect.Message)
 	}
-	return true, ""
+	return buf.String()
 }
 
 // LogMatches checks whether a given TestLogValues actually contains the log
@@ -103,6 +132,76 @@ func (checker *logMatches) Check(params []interface{}, _ []string) (result bool,
 //
 // The log may contain additional messages before and after each of the specified
 // expected messages.
+//
+// On failure, the obtained messages are listed with those that did not
+// satisfy an expectation prefixed with "+", followed by the expectations
+// that were not met, prefixed with "-".
 var LogMatches gc.Checker = &logMatches{
 	&gc.CheckerInfo{Name: "LogMatches", Params: []string{"obtained", "expected"}},
 }
+
+type noWarningsLogged struct {
+	*gc.CheckerInfo
+}
+
+func (checker *noWarningsLogged) Check(params []interface{}, _ []string) (result bool, error string) {
+	var obtained SimpleMessages
+	switch param := params[0].(type) {
+	case []loggo.Entry:
+		obtained = logToSimpleMessages(param)
+	case []SimpleMessage:
+		obtained = SimpleMessages(param)
+	case SimpleMessages:
+		obtained = param
+	default:
+		return false, "Obtained value must be of type []loggo.Entry or SimpleMessage"
+	}
+	var allowed []*regexp.Regexp
+	switch param := params[1].(type) {
+	case nil:
+	case []string:
+		for _, s := range param {
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return false, fmt.Sprintf("bad message regexp %q: %v", s, err)
+			}
+			allowed = append(allowed, re)
+		}
+	default:
+		return false, "Allowed value must be of type []string"
+	}
+
+	var buf strings.Builder
+outer:
+	for _, msg := range obtained {
+		if msg.Level < loggo.WARNING {
+			continue
+		}
+		for _, re := range allowed {
+			if re.MatchString(msg.Message) {
+				continue outer
+			}
+		}
+		if buf.Len() == 0 {
+			buf.WriteString("unexpected warnings logged:\n")
+		}
+		fmt.Fprintf(&buf, "  %s\n", msg)
+	}
+	if buf.Len() == 0 {
+		return true, ""
+	}
+	return false, buf.String()
+}
+
+// NoWarningsLogged checks that a log holds no messages at WARNING
+// level or above, other than those matching any of the allowed
+// regular expressions, which may be nil. This catches errors that are
+// logged and otherwise ignored by the code under test:
+//
+//	c.Check(s.LogEntries(), jc.NoWarningsLogged, []string{"connection .* lost"})
+//
+// The obtained value may be a []loggo.Entry or a []SimpleMessage, as
+// for LogMatches. On failure, the offending messages are listed.
+var NoWarningsLogged gc.Checker = &noWarningsLogged{
+	&gc.CheckerInfo{Name: "NoWarningsLogged", Params: []string{"obtained", "allowed"}},
+}
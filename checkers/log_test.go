@@ -192,3 +192,85 @@ func (s *LogMatchesSuite) TestLogMatchesFailsOnInvalidRegex(c *gc.C) {
 	c.Assert(result, gc.Equals, false)
 	c.Assert(err, gc.Equals, "bad message regexp \"[]foo\": error parsing regexp: missing closing ]: `[]foo`")
 }
+
+func (s *LogMatchesSuite) TestLogMatchesAcceptsSimpleMessages(c *gc.C) {
+	log := jc.SimpleMessages{
+		{loggo.INFO, "foo bar"},
+		{loggo.DEBUG, "12345"},
+	}
+	c.Check(log, jc.LogMatches, []jc.SimpleMessage{{loggo.DEBUG, "123.*"}})
+	c.Check([]jc.SimpleMessage(log), jc.LogMatches, []string{"foo", "12345"})
+}
+
+func (s *LogMatchesSuite) TestLogMatchesReportsDifference(c *gc.C) {
+	obtained := []loggo.Entry{
+		{Level: loggo.INFO, Message: "starting"},
+		{Level: loggo.DEBUG, Message: "connecting"},
+		{Level: loggo.ERROR, Message: "connection refused"},
+	}
+	expected := []jc.SimpleMessage{
+		{loggo.INFO, "start.*"},
+		{loggo.WARNING, "connection .*"},
+		{loggo.UNSPECIFIED, "stopped"},
+	}
+	result, err := jc.LogMatches.Check([]interface{}{obtained, expected}, nil)
+	c.Assert(result, gc.Equals, false)
+	c.Assert(err, gc.Equals, `unmatched log expectations:
+    INFO starting
+  + DEBUG connecting
+  + ERROR connection refused
+  - WARNING "connection .*"
+  - ANY "stopped"
+`)
+}
+
+type NoWarningsLoggedSuite struct{}
+
+var _ = gc.Suite(&NoWarningsLoggedSuite{})
+
+func (s *NoWarningsLoggedSuite) TestNoWarnings(c *gc.C) {
+	log := []loggo.Entry{
+		{Level: loggo.DEBUG, Message: "foo"},
+		{Level: loggo.INFO, Message: "bar"},
+	}
+	c.Check(log, jc.NoWarningsLogged, nil)
+	c.Check([]loggo.Entry(nil), jc.NoWarningsLogged, nil)
+}
+
+func (s *NoWarningsLoggedSuite) TestWarnings(c *gc.C) {
+	log := jc.SimpleMessages{
+		{loggo.INFO, "starting"},
+		{loggo.WARNING, "connection 1 lost"},
+		{loggo.ERROR, "cannot write: disk full"},
+		{loggo.CRITICAL, "giving up"},
+	}
+	result, err := jc.NoWarningsLogged.Check([]interface{}{log, nil}, nil)
+	c.Assert(result, gc.Equals, false)
+	c.Assert(err, gc.Equals, `unexpected warnings logged:
+  WARNING connection 1 lost
+  ERROR cannot write: disk full
+  CRITICAL giving up
+`)
+
+	result, err = jc.NoWarningsLogged.Check([]interface{}{log, []string{"connection .* lost", "^giving"}}, nil)
+	c.Assert(result, gc.Equals, false)
+	c.Assert(err, gc.Equals, `unexpected warnings logged:
+  ERROR cannot write: disk full
+`)
+
+	c.Check(log, jc.NoWarningsLogged, []string{"connection .* lost", "disk full", "giving up"})
+}
+
+func (s *NoWarningsLoggedSuite) TestBadParams(c *gc.C) {
+	result, err := jc.NoWarningsLogged.Check([]interface{}{"foo", nil}, nil)
+	c.Assert(result, gc.Equals, false)
+	c.Assert(err, gc.Equals, "Obtained value must be of type []loggo.Entry or SimpleMessage")
+
+	result, err = jc.NoWarningsLogged.Check([]interface{}{[]loggo.Entry{}, "foo"}, nil)
+	c.Assert(result, gc.Equals, false)
+	c.Assert(err, gc.Equals, "Allowed value must be of type []string")
+
+	result, err = jc.NoWarningsLogged.Check([]interface{}{[]loggo.Entry{}, []string{"[]foo"}}, nil)
+	c.Assert(result, gc.Equals, false)
+	c.Assert(err, gc.Equals, "bad message regexp \"[]foo\": error parsing regexp: missing closing ]: `[]foo`")
+}
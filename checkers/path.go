@@ -0,0 +1,82 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package checkers
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// PathEquals checker
+
+type pathEqualsChecker struct {
+	*gc.CheckerInfo
+	caseSensitive bool
+}
+
+// PathEquals checks that the obtained path names the same path as the
+// expected one, without consulting the file system. Before comparing,
+// both "/" and "\" are treated as separators, repeated separators and
+// trailing separators are removed, "." and ".." elements are resolved,
+// and, on platforms whose file systems are usually case-insensitive
+// (windows and darwin), case is ignored. On failure the normalized
+// paths are shown alongside the originals.
+//
+// Use SamePath to check whether two paths refer to the same file.
+var PathEquals gc.Checker = &pathEqualsChecker{
+	CheckerInfo:   &gc.CheckerInfo{Name: "PathEquals", Params: []string{"obtained", "expected"}},
+	caseSensitive: runtime.GOOS != "windows" && runtime.GOOS != "darwin",
+}
+
+// PathEqualsCase returns a checker that behaves like PathEquals but
+// compares case as specified regardless of the current platform.
+func PathEqualsCase(caseSensitive bool) gc.Checker {
+	return &pathEqualsChecker{
+		CheckerInfo:   &gc.CheckerInfo{Name: "PathEqualsCase", Params: []string{"obtained", "expected"}},
+		caseSensitive: caseSensitive,
+	}
+}
+
+func (checker *pathEqualsChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	obtained, isStr := stringOrStringer(params[0])
+	if !isStr {
+		return false, fmt.Sprintf("obtained value is not a string and has no .String(), %T:%#v", params[0], params[0])
+	}
+	expected, isStr := stringOrStringer(params[1])
+	if !isStr {
+		return false, fmt.Sprintf("expected value is not a string and has no .String(), %T:%#v", params[1], params[1])
+	}
+	normObtained := checker.normalize(obtained)
+	normExpected := checker.normalize(expected)
+	if normObtained == normExpected {
+		return true, ""
+	}
+	applied := `separators converted to "/", path cleaned`
+	if !checker.caseSensitive {
+		applied += ", case folded"
+	}
+	return false, fmt.Sprintf("paths differ after normalization (%s):\n"+
+		"obtained %q -> %q\n"+
+		"expected %q -> %q", applied, obtained, normObtained, expected, normExpected)
+}
+
+// normalize returns p in the form in which it is compared.
+func (checker *pathEqualsChecker) normalize(p string) string {
+	p = strings.Replace(p, `\`, "/", -1)
+	// Keep the leading separators of a UNC path, which Clean
+	// would collapse.
+	unc := strings.HasPrefix(p, "//") && !strings.HasPrefix(p, "///")
+	p = path.Clean(p)
+	if unc {
+		p = "/" + p
+	}
+	if !checker.caseSensitive {
+		p = strings.ToLower(p)
+	}
+	return p
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package checkers_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+type PathSuite struct{}
+
+var _ = gc.Suite(&PathSuite{})
+
+var pathEqualsTests = []struct {
+	about         string
+	obtained      string
+	expected      string
+	caseSensitive bool
+	equal         bool
+}{{
+	about:    "identical",
+	obtained: "/a/b",
+	expected: "/a/b",
+	equal:    true,
+}, {
+	about:    "backslashes",
+	obtained: `C:\foo\bar`,
+	expected: "C:/foo/bar",
+	equal:    true,
+}, {
+	about:    "trailing separators",
+	obtained: "/a/b/",
+	expected: `/a/b\`,
+	equal:    true,
+}, {
+	about:    "repeated separators and dots",
+	obtained: "a//b/./c/../d",
+	expected: "a/b/d",
+	equal:    true,
+}, {
+	about:    "root",
+	obtained: "/",
+	expected: `\`,
+	equal:    true,
+}, {
+	about:    "UNC path",
+	obtained: `\\server\share\`,
+	expected: "//server/share",
+	equal:    true,
+}, {
+	about:    "UNC path is not rooted path",
+	obtained: `\\server\share`,
+	expected: "/server/share",
+}, {
+	about:    "different paths",
+	obtained: "/a/b",
+	expected: "/a/c",
+}, {
+	about:    "case folded",
+	obtained: `C:\Users\Foo`,
+	expected: "c:/users/foo",
+	equal:    true,
+}, {
+	about:         "case sensitive",
+	obtained:      `C:\Users\Foo`,
+	expected:      "c:/users/foo",
+	caseSensitive: true,
+}}
+
+func (s *PathSuite) TestPathEqualsCase(c *gc.C) {
+	for i, test := range pathEqualsTests {
+		c.Logf("test %d: %s", i, test.about)
+		result, _ := jc.PathEqualsCase(test.caseSensitive).Check([]interface{}{test.obtained, test.expected}, nil)
+		c.Check(result, gc.Equals, test.equal)
+	}
+}
+
+func (s *PathSuite) TestPathEquals(c *gc.C) {
+	c.Assert(`a\b\`, jc.PathEquals, "a/b")
+	c.Assert("a/b", gc.Not(jc.PathEquals), "a/c")
+}
+
+func (s *PathSuite) TestPathEqualsMessage(c *gc.C) {
+	result, message := jc.PathEqualsCase(false).Check([]interface{}{`C:\A\`, "c:/b"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, `paths differ after normalization (separators converted to "/", path cleaned, case folded):
+obtained "C:\\A\\" -> "c:/a"
+expected "c:/b" -> "c:/b"`)
+
+	result, message = jc.PathEqualsCase(true).Check([]interface{}{"A", "a"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, `paths differ after normalization (separators converted to "/", path cleaned):
+obtained "A" -> "A"
+expected "a" -> "a"`)
+}
+
+func (s *PathSuite) TestPathEqualsBadParams(c *gc.C) {
+	result, message := jc.PathEquals.Check([]interface{}{42, "a"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "obtained value is not a string and has no .String(), int:42")
+
+	result, message = jc.PathEquals.Check([]interface{}{"a", 42}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "expected value is not a string and has no .String(), int:42")
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package checkers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// modeBits holds the file mode bits compared by HasFileMode.
+const modeBits = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+
+type hasFileModeChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasFileMode checks that the file with the obtained name has the
+// expected permission bits, including the setuid, setgid and sticky
+// bits, given as an os.FileMode. Symbolic links are followed. For
+// example:
+//
+//	c.Assert(path, jc.HasFileMode, os.FileMode(0600))
+//
+// fails with a message such as "file has mode 0644, want 0600".
+var HasFileMode gc.Checker = &hasFileModeChecker{
+	&gc.CheckerInfo{Name: "HasFileMode", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *hasFileModeChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	filename, isString := stringOrStringer(params[0])
+	if !isString {
+		return false, fmt.Sprintf("obtained value is not a string and has no .String(), %T:%#v", params[0], params[0])
+	}
+	expected, ok := params[1].(os.FileMode)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be an os.FileMode, got %T", params[1])
+	}
+	fileInfo, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return false, fmt.Sprintf("%s does not exist", filename)
+	} else if err != nil {
+		return false, fmt.Sprintf("other stat error: %v", err)
+	}
+	if got := fileInfo.Mode() & modeBits; got != expected&modeBits {
+		return false, fmt.Sprintf("%s has mode %s, want %s", filename, formatMode(got), formatMode(expected&modeBits))
+	}
+	return true, ""
+}
+
+type hasRequestedModeChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasRequestedMode checks that the file with the obtained name has the
+// mode that it would be given if created with the expected mode, an
+// os.FileMode, under the current process umask. This allows tests of
+// code that creates files to pass whatever umask they are run with;
+// use testing.PatchUmask to run them under a particular one. For
+// example:
+//
+//	c.Assert(path, jc.HasRequestedMode, os.FileMode(0666))
+//
+// fails with a message such as
+// "file has mode 0600, want 0644 (0666 with umask 0022)".
+var HasRequestedMode gc.Checker = &hasRequestedModeChecker{
+	&gc.CheckerInfo{Name: "HasRequestedMode", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *hasRequestedModeChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	requested, ok := params[1].(os.FileMode)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be an os.FileMode, got %T", params[1])
+	}
+	umask := processUmask()
+	// The umask only applies to the permission bits.
+	expected := requested & modeBits &^ umask.Perm()
+	result, error = HasFileMode.Check([]interface{}{params[0], expected}, names)
+	if !result && strings.Contains(error, " has mode ") {
+		error += fmt.Sprintf(" (%s with umask %s)", formatMode(requested&modeBits), formatMode(umask))
+	}
+	return result, error
+}
+
+// formatMode formats file mode bits in octal, as chmod accepts them.
+func formatMode(mode os.FileMode) string {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 01000
+	}
+	return fmt.Sprintf("%04o", bits)
+}
+
+type isOwnedByChecker struct {
+	*gc.CheckerInfo
+}
+
+// IsOwnedBy checks that the file with the obtained name is owned by the
+// given user and group ids. An id of -1 is not checked. For example:
+//
+//	c.Assert(path, jc.IsOwnedBy, 0, -1)
+//
+// checks that path is owned by root, in any group. Symbolic links are
+// not followed. Ownership cannot be checked on Windows.
+var IsOwnedBy gc.Checker = &isOwnedByChecker{
+	&gc.CheckerInfo{Name: "IsOwnedBy", Params: []string{"obtained", "uid", "gid"}},
+}
+
+func (checker *isOwnedByChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	filename, isString := stringOrStringer(params[0])
+	if !isString {
+		return false, fmt.Sprintf("obtained value is not a string and has no .String(), %T:%#v", params[0], params[0])
+	}
+	uid, ok := params[1].(int)
+	if !ok {
+		return false, fmt.Sprintf("uid must be an int, got %T", params[1])
+	}
+	gid, ok := params[2].(int)
+	if !ok {
+		return false, fmt.Sprintf("gid must be an int, got %T", params[2])
+	}
+	fileInfo, err := os.Lstat(filename)
+	if os.IsNotExist(err) {
+		return false, fmt.Sprintf("%s does not exist", filename)
+	} else if err != nil {
+		return false, fmt.Sprintf("other stat error: %v", err)
+	}
+	gotUID, gotGID, ok := fileOwner(fileInfo)
+	if !ok {
+		return false, "file ownership is not supported on this platform"
+	}
+	if uid != -1 && gotUID != uid || gid != -1 && gotGID != gid {
+		return false, fmt.Sprintf("%s is owned by %d:%d, want %s:%s", filename, gotUID, gotGID, formatID(uid), formatID(gid))
+	}
+	return true, ""
+}
+
+func formatID(id int) string {
+	if id == -1 {
+		return "*"
+	}
+	return fmt.Sprint(id)
+}
+
+type hasXattrChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasXattr checks that the file with the obtained name has the named
+// extended attribute with the given value. For example:
+//
+//	c.Assert(path, jc.HasXattr, "user.checksum", "abc123")
+//
+// Extended attributes can only be checked on Linux.
+var HasXattr gc.Checker = &hasXattrChecker{
+	&gc.CheckerInfo{Name: "HasXattr", Params: []string{"obtained", "name", "value"}},
+}
+
+func (checker *hasXattrChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	filename, isString := stringOrStringer(params[0])
+	if !isString {
+		return false, fmt.Sprintf("obtained value is not a string and has no .String(), %T:%#v", params[0], params[0])
+	}
+	name, ok := params[1].(string)
+	if !ok {
+		return false, fmt.Sprintf("attribute name must be a string, got %T", params[1])
+	}
+	expected, ok := params[2].(string)
+	if !ok {
+		return false, fmt.Sprintf("attribute value must be a string, got %T", params[2])
+	}
+	value, err := getXattr(filename, name)
+	if err != nil {
+		return false, fmt.Sprintf("cannot get attribute %q of %s: %v", name, filename, err)
+	}
+	if value != expected {
+		return false, fmt.Sprintf("%s has attribute %s=%q, want %q", filename, name, value, expected)
+	}
+	return true, ""
+}
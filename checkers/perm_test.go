@@ -0,0 +1,109 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package checkers_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type PermSuite struct{}
+
+var _ = gc.Suite(&PermSuite{})
+
+func (s *PermSuite) TestHasFileMode(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("file permissions are not supported on windows")
+	}
+	name := filepath.Join(c.MkDir(), "file")
+	err := ioutil.WriteFile(name, nil, 0644)
+	c.Assert(err, gc.IsNil)
+	err = os.Chmod(name, 0640|os.ModeSetgid)
+	c.Assert(err, gc.IsNil)
+	c.Assert(name, jc.HasFileMode, 0640|os.ModeSetgid)
+
+	result, message := jc.HasFileMode.Check([]interface{}{name, os.FileMode(0600)}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, name+" has mode 2640, want 0600")
+
+	result, message = jc.HasFileMode.Check([]interface{}{name, 0600}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "expected value must be an os.FileMode, got int")
+}
+
+func (s *PermSuite) TestHasFileModeMissing(c *gc.C) {
+	name := filepath.Join(c.MkDir(), "missing")
+	result, message := jc.HasFileMode.Check([]interface{}{name, os.FileMode(0600)}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, name+" does not exist")
+}
+
+func (s *PermSuite) TestHasRequestedMode(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("file permissions are not supported on windows")
+	}
+	for _, umask := range []os.FileMode{0, 022, 077} {
+		c.Logf("umask %04o", umask)
+		restore := testing.PatchUmask(umask)
+		name := filepath.Join(c.MkDir(), "file")
+		err := ioutil.WriteFile(name, nil, 0666)
+		c.Check(err, gc.IsNil)
+		c.Check(name, jc.HasRequestedMode, os.FileMode(0666))
+		restore()
+	}
+}
+
+func (s *PermSuite) TestHasRequestedModeMismatch(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("file permissions are not supported on windows")
+	}
+	restore := testing.PatchUmask(022)
+	defer restore()
+	name := filepath.Join(c.MkDir(), "file")
+	err := ioutil.WriteFile(name, nil, 0600)
+	c.Assert(err, gc.IsNil)
+
+	result, message := jc.HasRequestedMode.Check([]interface{}{name, os.FileMode(0666)}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, name+" has mode 0600, want 0644 (0666 with umask 0022)")
+
+	result, message = jc.HasRequestedMode.Check([]interface{}{name, 0666}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "expected value must be an os.FileMode, got int")
+}
+
+func (s *PermSuite) TestIsOwnedBy(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("file ownership is not supported on windows")
+	}
+	name := filepath.Join(c.MkDir(), "file")
+	err := ioutil.WriteFile(name, nil, 0644)
+	c.Assert(err, gc.IsNil)
+	uid, gid := os.Getuid(), os.Getgid()
+	c.Assert(name, jc.IsOwnedBy, uid, gid)
+	c.Assert(name, jc.IsOwnedBy, uid, -1)
+	c.Assert(name, jc.IsOwnedBy, -1, -1)
+
+	result, message := jc.IsOwnedBy.Check([]interface{}{name, uid + 1, -1}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, fmt.Sprintf("%s is owned by %d:%d, want %d:*", name, uid, gid, uid+1))
+
+	result, message = jc.IsOwnedBy.Check([]interface{}{name, "root", -1}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "uid must be an int, got string")
+}
+
+func (s *PermSuite) TestHasXattrBadParams(c *gc.C) {
+	result, message := jc.HasXattr.Check([]interface{}{"foo", 1, "x"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "attribute name must be a string, got int")
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !windows
+
+package checkers
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the user and group ids of the owner of the file
+// described by info.
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(st.Uid), int(st.Gid), true
+}
+
+// processUmask returns the process umask. There is no way of reading
+// the umask without setting it, so it is briefly set to zero.
+func processUmask() os.FileMode {
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+	return os.FileMode(mask)
+}
@@ -0,0 +1,19 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package checkers
+
+import (
+	"os"
+)
+
+// fileOwner always fails, as files do not have numeric owners on
+// windows.
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
+
+// processUmask always returns zero, as there is no umask on windows.
+func processUmask() os.FileMode {
+	return 0
+}
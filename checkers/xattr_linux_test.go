@@ -0,0 +1,34 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package checkers_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"syscall"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+func (s *PermSuite) TestHasXattr(c *gc.C) {
+	name := filepath.Join(c.MkDir(), "file")
+	err := ioutil.WriteFile(name, nil, 0644)
+	c.Assert(err, gc.IsNil)
+	err = syscall.Setxattr(name, "user.test", []byte("value"), 0)
+	if err == syscall.ENOTSUP {
+		c.Skip("extended attributes are not supported by the file system")
+	}
+	c.Assert(err, gc.IsNil)
+	c.Assert(name, jc.HasXattr, "user.test", "value")
+
+	result, message := jc.HasXattr.Check([]interface{}{name, "user.test", "other"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, name+` has attribute user.test="value", want "other"`)
+
+	result, message = jc.HasXattr.Check([]interface{}{name, "user.missing", ""}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Matches, `cannot get attribute "user.missing" of .*: no data available`)
+}
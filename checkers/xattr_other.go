@@ -0,0 +1,16 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !linux
+
+package checkers
+
+import (
+	"errors"
+)
+
+// getXattr always fails, as extended attributes are only supported
+// on linux.
+func getXattr(path, name string) (string, error) {
+	return "", errors.New("extended attributes are not supported on this platform")
+}
@@ -4,6 +4,8 @@
 package testing
 
 import (
+	"math/rand"
+	"os"
 	"os/exec"
 
 	gc "gopkg.in/check.v1"
@@ -110,6 +112,15 @@ func (s *CleanupSuite) PatchEnvironment(name, value string) {
 	s.AddCleanup(func(*gc.C) { restore() })
 }
 
+// PatchEnvironmentVars sets or unsets each of the given environment
+// variables. The old values are restored at test tear down time using
+// a cleanup function. See the package function of the same name for
+// the constraints on its use.
+func (s *CleanupSuite) PatchEnvironmentVars(vars map[string]EnvValue) {
+	restore := PatchEnvironmentVars(vars)
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
 // PatchEnvPathPrepend prepends the given path to the environment $PATH and restores the
 // original path on test teardown.
 func (s *CleanupSuite) PatchEnvPathPrepend(dir string) {
@@ -138,3 +149,39 @@ func (s *CleanupSuite) HookCommandOutput(
 	s.AddCleanup(func(*gc.C) { restore() })
 	return result
 }
+
+// PatchUserHome is like the package function of the same name, with
+// the environment changes restored when the test finishes.
+func (s *CleanupSuite) PatchUserHome(c *gc.C) *UserHome {
+	return PatchUserHome(c, s)
+}
+
+// PatchSeededRand is like the package function of the same name, with
+// the original generator restored when the test finishes. It returns
+// the new generator.
+func (s *CleanupSuite) PatchSeededRand(c *gc.C, dest **rand.Rand) *rand.Rand {
+	restore := PatchSeededRand(c, dest)
+	s.AddCleanup(func(*gc.C) { restore() })
+	return *dest
+}
+
+// PatchTimezone is like the package function of the same name, with
+// the original time zone restored when the test finishes. The same
+// caveats about concurrent use of the time package apply.
+func (s *CleanupSuite) PatchTimezone(c *gc.C, name string) {
+	restore := PatchTimezone(c, name)
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+// PatchUmask is like the package function of the same name, with the
+// original umask restored when the test finishes.
+func (s *CleanupSuite) PatchUmask(mask os.FileMode) {
+	restore := PatchUmask(mask)
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+// PatchWd is like the package function of the same name, with the
+// original working directory restored when the test finishes.
+func (s *CleanupSuite) PatchWd(c *gc.C, dir string) {
+	s.AddCleanup(patchWd(c, dir))
+}
@@ -97,6 +97,19 @@ func (s *cleanupSuite) TestPatchValueFunction(c *gc.C) {
 	s.SetUpTest(c)
 }
 
+func (s *cleanupSuite) TestSuitePatch(c *gc.C) {
+	i := 42
+	testing.SuitePatch(s, &i, 0)
+	c.Assert(i, gc.Equals, 0)
+
+	s.TearDownTest(c)
+	c.Assert(i, gc.Equals, 42)
+
+	// SetUpTest resets the cleanup stack, this stops the cleanup functions
+	// being called again.
+	s.SetUpTest(c)
+}
+
 // noopCleanup is a simple function that does nothing that can be passed to
 // AddCleanup
 func noopCleanup(*gc.C) {
@@ -190,6 +190,175 @@ func ReadEchoArgs(c *gc.C, execName string) string {
 	return actual
 }
 
+// FakeExecResponse holds the behaviour of a single invocation of an
+// executable patched with PatchFakeExecutable.
+type FakeExecResponse struct {
+	// Stdout is the value written to stdout.
+	Stdout string
+	// Stderr is the value written to stderr.
+	Stderr string
+	// ExitCode is the exit code of the executable.
+	ExitCode int
+}
+
+// FakeExecInvocation records a single invocation of an executable
+// patched with PatchFakeExecutable.
+type FakeExecInvocation struct {
+	// Args holds the arguments passed to the executable, not
+	// including the executable name itself.
+	Args []string
+	// Env holds the environment the executable was run with.
+	Env map[string]string
+	// Stdin holds everything read from the executable's standard
+	// input.
+	Stdin string
+}
+
+// FakeExecutable is an executable created by PatchFakeExecutable.
+type FakeExecutable struct {
+	// Path holds the full path of the executable.
+	Path string
+}
+
+// fakeExecutableUnix records the invocation in numbered files under
+// $0.calls and then replays the response from the directory with the
+// same number under $0.responses, falling back to the last response
+// once they run out. It uses only bash builtins, as it is usually run
+// with PATH holding nothing but its own directory, as set by
+// OsEnvSuite. The copy function copies its input to its output, NUL
+// bytes included.
+const fakeExecutableUnix = `#!/bin/bash --norc
+shopt -s nullglob
+copy() {
+	local chunk
+	while IFS= read -r -d '' chunk; do
+		printf '%s\0' "$chunk"
+	done
+	printf '%s' "$chunk"
+}
+calls="$0.calls"
+existing=("$calls"/*.args)
+n=${#existing[@]}
+call="$calls/$n"
+: > "$call.args"
+if [ $# -gt 0 ]; then
+	printf '%s\0' "$@" > "$call.args"
+fi
+for name in $(compgen -e); do
+	printf '%s=%s\0' "$name" "${!name}"
+done > "$call.env"
+if [ ! -t 0 ]; then
+	copy > "$call.stdin"
+fi
+response="$0.responses/$n"
+if [ ! -d "$response" ]; then
+	response="$0.responses/last"
+fi
+copy < "$response/stdout"
+copy < "$response/stderr" >&2
+read -r code < "$response/exitcode"
+exit $code
+`
+
+// PatchFakeExecutable creates an executable called 'execName' in a new
+// test directory and that directory is added to the path. Each time
+// the executable is run, it records its arguments, environment and
+// standard input, and then writes the stdout and stderr and exits with
+// the exit code from the next of the given responses. Once the
+// responses are exhausted, the last one is repeated; if there are no
+// responses, the executable prints nothing and exits successfully.
+//
+// The invocations can be retrieved with the Invocations method. The
+// executable is a bash script and is not safe to run concurrently with
+// itself. PatchFakeExecutable is not supported on windows, where it
+// skips the test.
+func PatchFakeExecutable(c *gc.C, patcher EnvironmentPatcher, execName string, responses ...FakeExecResponse) *FakeExecutable {
+	if runtime.GOOS == "windows" {
+		c.Skip("PatchFakeExecutable is not supported on windows")
+	}
+	dir := c.MkDir()
+	patcher.PatchEnvironment("PATH", joinPathLists(dir, os.Getenv("PATH")))
+	filename := filepath.Join(dir, execName)
+	err := ioutil.WriteFile(filename, []byte(fakeExecutableUnix), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Mkdir(filename+".calls", 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	if len(responses) == 0 {
+		responses = []FakeExecResponse{{}}
+	}
+	writeResponse := func(name string, resp FakeExecResponse) {
+		respDir := filepath.Join(filename+".responses", name)
+		err := os.MkdirAll(respDir, 0755)
+		c.Assert(err, jc.ErrorIsNil)
+		for file, data := range map[string]string{
+			"stdout":   resp.Stdout,
+			"stderr":   resp.Stderr,
+			"exitcode": strconv.Itoa(resp.ExitCode),
+		} {
+			err := ioutil.WriteFile(filepath.Join(respDir, file), []byte(data), 0644)
+			c.Assert(err, jc.ErrorIsNil)
+		}
+	}
+	for i, resp := range responses {
+		writeResponse(strconv.Itoa(i), resp)
+	}
+	writeResponse("last", responses[len(responses)-1])
+	return &FakeExecutable{Path: filename}
+}
+
+// Invocations returns all the recorded invocations of the executable,
+// in the order they were made.
+func (e *FakeExecutable) Invocations(c *gc.C) []FakeExecInvocation {
+	argFiles, err := filepath.Glob(filepath.Join(e.Path+".calls", "*.args"))
+	c.Assert(err, jc.ErrorIsNil)
+	if len(argFiles) == 0 {
+		return nil
+	}
+	invocations := make([]FakeExecInvocation, len(argFiles))
+	for i := range argFiles {
+		call := filepath.Join(e.Path+".calls", strconv.Itoa(i))
+		args, err := ioutil.ReadFile(call + ".args")
+		c.Assert(err, jc.ErrorIsNil)
+		env, err := ioutil.ReadFile(call + ".env")
+		c.Assert(err, jc.ErrorIsNil)
+		stdin, err := ioutil.ReadFile(call + ".stdin")
+		if err != nil && !os.IsNotExist(err) {
+			c.Assert(err, jc.ErrorIsNil)
+		}
+		inv := FakeExecInvocation{
+			Args:  splitNul(string(args)),
+			Env:   make(map[string]string),
+			Stdin: string(stdin),
+		}
+		for _, kv := range splitNul(string(env)) {
+			parts := strings.SplitN(kv, "=", 2)
+			inv.Env[parts[0]] = parts[1]
+		}
+		invocations[i] = inv
+	}
+	return invocations
+}
+
+// CheckArgs checks that the executable was invoked once for each of
+// the given argument lists, in order.
+func (e *FakeExecutable) CheckArgs(c *gc.C, expected ...[]string) {
+	invocations := e.Invocations(c)
+	obtained := make([][]string, len(invocations))
+	for i, inv := range invocations {
+		obtained[i] = inv.Args
+	}
+	c.Check(obtained, jc.DeepEquals, expected)
+}
+
+// splitNul splits a sequence of NUL-terminated strings.
+func splitNul(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\x00"), "\x00")
+}
+
 // PatchExecHelper is a type that helps you patch out calls to executables by
 // patching out the exec.Command function that creates the exec.Cmd to call
 // them. This is very similar to PatchExecutable above, except it works on
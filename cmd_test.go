@@ -73,6 +73,78 @@ func (s *cmdSuite) TestPatchExecutableThrowError(c *gc.C) {
 	c.Assert(output, gc.Equals, "failing")
 }
 
+func (s *cmdSuite) TestPatchFakeExecutable(c *gc.C) {
+	fake := testing.PatchFakeExecutable(c, s, testFunc, testing.FakeExecResponse{
+		Stdout: "first stdout",
+		Stderr: "first stderr",
+	}, testing.FakeExecResponse{
+		Stdout:   "second stdout",
+		ExitCode: 3,
+	})
+
+	cmd := exec.Command(testFunc, "foo", "bar baz")
+	cmd.Stdin = strings.NewReader("some input")
+	cmd.Env = append(os.Environ(), "TESTING_FAKE_EXEC=value")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	err := cmd.Run()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stdout.String(), gc.Equals, "first stdout")
+	c.Check(stderr.String(), gc.Equals, "first stderr")
+
+	for i := 0; i < 2; i++ {
+		out, err := exec.Command(testFunc).Output()
+		c.Check(err, gc.ErrorMatches, "exit status 3")
+		c.Check(string(out), gc.Equals, "second stdout")
+	}
+
+	invocations := fake.Invocations(c)
+	c.Assert(invocations, gc.HasLen, 3)
+	c.Check(invocations[0].Args, gc.DeepEquals, []string{"foo", "bar baz"})
+	c.Check(invocations[0].Env["TESTING_FAKE_EXEC"], gc.Equals, "value")
+	c.Check(invocations[0].Stdin, gc.Equals, "some input")
+	c.Check(invocations[1].Stdin, gc.Equals, "")
+	fake.CheckArgs(c, []string{"foo", "bar baz"}, nil, nil)
+}
+
+func (s *cmdSuite) TestPatchFakeExecutableNoResponses(c *gc.C) {
+	fake := testing.PatchFakeExecutable(c, s, testFunc)
+	c.Check(fake.Invocations(c), gc.HasLen, 0)
+	output := runCommand(c, testFunc, "arg")
+	c.Check(output, gc.Equals, "")
+	fake.CheckArgs(c, []string{"arg"})
+}
+
+// fakeExecIsolationSuite checks that PatchFakeExecutable works in an
+// IsolationSuite, which leaves PATH holding only the fake's directory.
+type fakeExecIsolationSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&fakeExecIsolationSuite{})
+
+func (s *fakeExecIsolationSuite) TestPatchFakeExecutable(c *gc.C) {
+	fake := testing.PatchFakeExecutable(c, s, testFunc, testing.FakeExecResponse{
+		Stdout:   "out\x00put\n\n",
+		Stderr:   "error\n",
+		ExitCode: 2,
+	})
+
+	cmd := exec.Command(testFunc, "foo")
+	cmd.Stdin = strings.NewReader("in\x00put\n")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	err := cmd.Run()
+	c.Assert(err, gc.ErrorMatches, "exit status 2")
+	c.Check(stdout.String(), gc.Equals, "out\x00put\n\n")
+	c.Check(stderr.String(), gc.Equals, "error\n")
+
+	invocations := fake.Invocations(c)
+	c.Assert(invocations, gc.HasLen, 1)
+	c.Check(invocations[0].Args, gc.DeepEquals, []string{"foo"})
+	c.Check(invocations[0].Stdin, gc.Equals, "in\x00put\n")
+}
+
 func (s *cmdSuite) TestCaptureOutput(c *gc.C) {
 	f := func() {
 		_, err := fmt.Fprint(os.Stderr, "this is stderr")
@@ -0,0 +1,82 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	gc "gopkg.in/check.v1"
+)
+
+type outputChecker struct {
+	*gc.CheckerInfo
+	stream string
+	match  bool
+}
+
+// StdoutEquals checks that the standard output captured by the
+// obtained *Context equals the expected string.
+var StdoutEquals gc.Checker = &outputChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "StdoutEquals", Params: []string{"obtained", "expected"}},
+	stream:      "stdout",
+}
+
+// StderrEquals checks that the standard error captured by the
+// obtained *Context equals the expected string.
+var StderrEquals gc.Checker = &outputChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "StderrEquals", Params: []string{"obtained", "expected"}},
+	stream:      "stderr",
+}
+
+// StdoutMatches checks that the standard output captured by the
+// obtained *Context matches the regular expression, which must match
+// the whole output, as with gc.Matches.
+var StdoutMatches gc.Checker = &outputChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "StdoutMatches", Params: []string{"obtained", "regex"}},
+	stream:      "stdout",
+	match:       true,
+}
+
+// StderrMatches checks that the standard error captured by the
+// obtained *Context matches the regular expression, which must match
+// the whole output, as with gc.Matches.
+var StderrMatches gc.Checker = &outputChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "StderrMatches", Params: []string{"obtained", "regex"}},
+	stream:      "stderr",
+	match:       true,
+}
+
+func (checker *outputChecker) Check(params []interface{}, _ []string) (result bool, error string) {
+	ctx, ok := params[0].(*Context)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type *cmdtesting.Context, got %T", params[0])
+	}
+	expected, ok := params[1].(string)
+	if !ok {
+		return false, fmt.Sprintf("%s must be a string, got %T", checker.Params[1], params[1])
+	}
+	var buf *bytes.Buffer
+	if checker.stream == "stdout" {
+		buf = ctx.Stdout
+	} else {
+		buf = ctx.Stderr
+	}
+	output := buf.String()
+	if !checker.match {
+		if output == expected {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%s is %q", checker.stream, output)
+	}
+	re, err := regexp.Compile("^(?:" + expected + ")$")
+	if err != nil {
+		return false, fmt.Sprintf("cannot compile regex: %v", err)
+	}
+	if re.MatchString(output) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("%s is %q", checker.stream, output)
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/cmdtesting"
+)
+
+type checkerSuite struct{}
+
+var _ = gc.Suite(&checkerSuite{})
+
+var checkerTests = []struct {
+	about    string
+	checker  gc.Checker
+	obtained interface{}
+	expected interface{}
+	message  string
+}{{
+	about:    "StdoutEquals with matching output",
+	checker:  cmdtesting.StdoutEquals,
+	expected: "out\n",
+}, {
+	about:    "StdoutEquals with different output",
+	checker:  cmdtesting.StdoutEquals,
+	expected: "out",
+	message:  `stdout is "out\\n"`,
+}, {
+	about:    "StderrEquals with matching output",
+	checker:  cmdtesting.StderrEquals,
+	expected: "ERROR failed\n",
+}, {
+	about:    "StderrEquals with different output",
+	checker:  cmdtesting.StderrEquals,
+	expected: "out\n",
+	message:  `stderr is "ERROR failed\\n"`,
+}, {
+	about:    "StdoutMatches with matching output",
+	checker:  cmdtesting.StdoutMatches,
+	expected: `o.t\n`,
+}, {
+	about:    "StdoutMatches is anchored",
+	checker:  cmdtesting.StdoutMatches,
+	expected: `o`,
+	message:  `stdout is "out\\n"`,
+}, {
+	about:    "StderrMatches with matching output",
+	checker:  cmdtesting.StderrMatches,
+	expected: `ERROR .*\n`,
+}, {
+	about:    "StderrMatches with an invalid regex",
+	checker:  cmdtesting.StderrMatches,
+	expected: `(`,
+	message:  `cannot compile regex: .*`,
+}, {
+	about:    "obtained value of the wrong type",
+	checker:  cmdtesting.StdoutEquals,
+	obtained: "ctx",
+	expected: "out\n",
+	message:  `obtained value must be of type \*cmdtesting.Context, got string`,
+}, {
+	about:    "expected value of the wrong type",
+	checker:  cmdtesting.StdoutEquals,
+	expected: 1,
+	message:  `expected must be a string, got int`,
+}, {
+	about:    "regex of the wrong type",
+	checker:  cmdtesting.StderrMatches,
+	expected: 1,
+	message:  `regex must be a string, got int`,
+}}
+
+func (*checkerSuite) TestCheckers(c *gc.C) {
+	ctx := cmdtesting.NewContext(c)
+	ctx.Stdout.WriteString("out\n")
+	ctx.Stderr.WriteString("ERROR failed\n")
+	for i, test := range checkerTests {
+		c.Logf("test %d: %s", i, test.about)
+		obtained := test.obtained
+		if obtained == nil {
+			obtained = ctx
+		}
+		result, message := test.checker.Check([]interface{}{obtained, test.expected}, nil)
+		c.Check(result, gc.Equals, test.message == "")
+		c.Check(message, gc.Matches, test.message)
+	}
+}
+
+func (*checkerSuite) TestCheckersWithRunCommand(c *gc.C) {
+	ctx, code := cmdtesting.RunCommand(c, echo, "hello")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(ctx, cmdtesting.StdoutEquals, "hello\n")
+	c.Assert(ctx, cmdtesting.StderrEquals, "")
+
+	ctx, code = cmdtesting.RunCommand(c, echo)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(ctx, cmdtesting.StdoutEquals, "")
+	c.Assert(ctx, cmdtesting.StderrMatches, "ERROR no .*\n")
+}
+
+func (*checkerSuite) TestStdoutEqualsFailure(c *gc.C) {
+	ctx, _ := cmdtesting.RunCommand(c, echo, "hello")
+	c.ExpectFailure("stdout differs")
+	c.Check(ctx, cmdtesting.StdoutEquals, "goodbye\n")
+}
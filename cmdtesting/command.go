@@ -0,0 +1,116 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package cmdtesting provides the plumbing for testing command line
+// commands: a Context holding in-memory standard streams, a working
+// directory and an environment, a way to run commands in it, and
+// checkers for their output.
+package cmdtesting
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Context holds the environment in which a command runs. Commands
+// under test should use it instead of the process's standard streams,
+// working directory and environment, so that tests can run them in
+// isolation.
+type Context struct {
+	// Dir holds the working directory.
+	Dir string
+
+	// Env holds the environment variables.
+	Env map[string]string
+
+	// Stdin holds the command's standard input.
+	Stdin io.Reader
+
+	// Stdout and Stderr capture the command's output.
+	Stdout *bytes.Buffer
+	Stderr *bytes.Buffer
+}
+
+// NewContext returns a new Context with empty standard input, an empty
+// environment, and a new temporary working directory.
+func NewContext(c *gc.C) *Context {
+	return &Context{
+		Dir:    c.MkDir(),
+		Env:    make(map[string]string),
+		Stdin:  strings.NewReader(""),
+		Stdout: new(bytes.Buffer),
+		Stderr: new(bytes.Buffer),
+	}
+}
+
+// Getenv returns the value of the environment variable named by key, or
+// the empty string if it is not set.
+func (ctx *Context) Getenv(key string) string {
+	return ctx.Env[key]
+}
+
+// Setenv sets the environment variable named by key.
+func (ctx *Context) Setenv(key, value string) {
+	ctx.Env[key] = value
+}
+
+// AbsPath returns path as an absolute path, relative to the context's
+// working directory if it is not already absolute.
+func (ctx *Context) AbsPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(ctx.Dir, path)
+}
+
+// Command is implemented by commands that can be run with RunCommand.
+// A command's exit code is 0 if Run returns nil. Otherwise, it is
+// taken from the error's ExitCode method, if it has one, as
+// *exec.ExitError does, and is 1 if not.
+type Command interface {
+	Run(ctx *Context, args []string) error
+}
+
+// CommandFunc adapts a function to the Command interface, so that a
+// command's entry point can be run with RunCommand:
+//
+//	cmd := cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+//		return app.Main(args, ctx.Stdin, ctx.Stdout, ctx.Stderr)
+//	})
+type CommandFunc func(ctx *Context, args []string) error
+
+// Run implements Command.Run by calling f.
+func (f CommandFunc) Run(ctx *Context, args []string) error {
+	return f(ctx, args)
+}
+
+// RunCommand runs the command with the given arguments in a new
+// Context, and returns the context, holding the command's output, and
+// the command's exit code. If the command returns an error, the error
+// is written to the context's standard error, prefixed with "ERROR ",
+// as a command line tool would report it.
+func RunCommand(c *gc.C, cmd Command, args ...string) (*Context, int) {
+	ctx := NewContext(c)
+	return ctx, RunCommandInContext(c, ctx, cmd, args...)
+}
+
+// RunCommandInContext is like RunCommand, but runs the command in the
+// given context, so that its input, working directory and environment
+// can be set up first.
+func RunCommandInContext(c *gc.C, ctx *Context, cmd Command, args ...string) int {
+	c.Logf("running command: %s", strings.Join(args, " "))
+	err := cmd.Run(ctx, args)
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintf(ctx.Stderr, "ERROR %v\n", err)
+	if e, ok := err.(interface{ ExitCode() int }); ok {
+		return e.ExitCode()
+	}
+	return 1
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/cmdtesting"
+)
+
+type commandSuite struct{}
+
+var _ = gc.Suite(&commandSuite{})
+
+// echo writes its arguments to stdout, or fails if there are none.
+var echo = cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no arguments")
+	}
+	fmt.Fprintln(ctx.Stdout, strings.Join(args, " "))
+	return nil
+})
+
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func (e exitError) ExitCode() int {
+	return int(e)
+}
+
+func (*commandSuite) TestRunCommand(c *gc.C) {
+	ctx, code := cmdtesting.RunCommand(c, echo, "hello", "world")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(ctx.Stdout.String(), gc.Equals, "hello world\n")
+	c.Assert(ctx.Stderr.String(), gc.Equals, "")
+}
+
+func (*commandSuite) TestRunCommandError(c *gc.C) {
+	ctx, code := cmdtesting.RunCommand(c, echo)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(ctx.Stdout.String(), gc.Equals, "")
+	c.Assert(ctx.Stderr.String(), gc.Equals, "ERROR no arguments\n")
+}
+
+func (*commandSuite) TestRunCommandExitCode(c *gc.C) {
+	cmd := cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+		return exitError(2)
+	})
+	ctx, code := cmdtesting.RunCommand(c, cmd)
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(ctx.Stderr.String(), gc.Equals, "ERROR exit status 2\n")
+}
+
+func (*commandSuite) TestRunCommandInContext(c *gc.C) {
+	cmd := cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+		data, err := io.ReadAll(ctx.Stdin)
+		if err != nil {
+			return err
+		}
+		greeting := ctx.Getenv("GREETING") + " " + string(data)
+		return os.WriteFile(ctx.AbsPath(args[0]), []byte(greeting), 0644)
+	})
+	ctx := cmdtesting.NewContext(c)
+	ctx.Setenv("GREETING", "hello")
+	ctx.Stdin = strings.NewReader("world")
+	code := cmdtesting.RunCommandInContext(c, ctx, cmd, "greeting.txt")
+	c.Assert(code, gc.Equals, 0)
+	data, err := os.ReadFile(filepath.Join(ctx.Dir, "greeting.txt"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "hello world")
+}
+
+func (*commandSuite) TestNewContext(c *gc.C) {
+	ctx := cmdtesting.NewContext(c)
+	info, err := os.Stat(ctx.Dir)
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.IsDir(), gc.Equals, true)
+	c.Assert(ctx.Getenv("HOME"), gc.Equals, "")
+	c.Assert(ctx.AbsPath("/etc/hosts"), gc.Equals, "/etc/hosts")
+	c.Assert(ctx.AbsPath("a/b"), gc.Equals, filepath.Join(ctx.Dir, "a", "b"))
+}
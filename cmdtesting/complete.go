@@ -0,0 +1,89 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting
+
+import (
+	"strconv"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// Shell identifies the shell on whose behalf a command is asked for
+// completions.
+type Shell string
+
+const (
+	// Bash requests completions as bash does for a command
+	// registered with "complete -C": the command line is passed in
+	// the COMP_LINE and COMP_POINT environment variables, and the
+	// arguments are the command name, the word being completed and
+	// the word before it.
+	Bash Shell = "bash"
+
+	// Zsh requests completions as zsh does when using bash
+	// completions through bashcompinit, which is the same as Bash.
+	Zsh Shell = "zsh"
+
+	// Fish requests completions as fish does when the command is run
+	// with the command line in COMP_LINE, and no arguments.
+	Fish Shell = "fish"
+)
+
+// Complete runs the command's completion entry point as the shell would
+// when the user presses tab at the end of the given command line, and
+// returns the completions that it writes to standard output, one per
+// line. The command must exit successfully.
+func Complete(c *gc.C, cmd Command, shell Shell, line string) []string {
+	ctx := NewContext(c)
+	ctx.Setenv("COMP_LINE", line)
+	ctx.Setenv("COMP_POINT", strconv.Itoa(len(line)))
+	ctx.Setenv("SHELL", "/bin/"+string(shell))
+	var args []string
+	switch shell {
+	case Bash, Zsh:
+		ctx.Setenv("COMP_TYPE", "9")
+		ctx.Setenv("COMP_KEY", "9")
+		words := strings.Fields(line)
+		if line == "" || strings.HasSuffix(line, " ") {
+			words = append(words, "")
+		}
+		prev := ""
+		if len(words) > 1 {
+			prev = words[len(words)-2]
+		}
+		args = []string{words[0], words[len(words)-1], prev}
+	case Fish:
+	default:
+		c.Fatalf("unknown shell %q", shell)
+	}
+	code := RunCommandInContext(c, ctx, cmd, args...)
+	c.Assert(code, gc.Equals, 0, gc.Commentf("completing %q; stderr: %q", line, ctx.Stderr.String()))
+	output := strings.TrimSuffix(ctx.Stdout.String(), "\n")
+	if output == "" {
+		return []string{}
+	}
+	return strings.Split(output, "\n")
+}
+
+// CheckCompletions checks that the completions offered for the command
+// line, as returned by Complete, are the expected ones, in order. It
+// returns whether the check succeeded.
+func CheckCompletions(c *gc.C, cmd Command, shell Shell, line string, expected ...string) bool {
+	if expected == nil {
+		expected = []string{}
+	}
+	completions := Complete(c, cmd, shell, line)
+	return c.Check(completions, jc.ListEquals, expected, gc.Commentf("completing %q with %s", line, shell))
+}
+
+// AssertCompletions is like CheckCompletions, but stops the test if the
+// check fails.
+func AssertCompletions(c *gc.C, cmd Command, shell Shell, line string, expected ...string) {
+	if !CheckCompletions(c, cmd, shell, line, expected...) {
+		c.FailNow()
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/cmdtesting"
+)
+
+type completeSuite struct{}
+
+var _ = gc.Suite(&completeSuite{})
+
+var subcommands = []struct {
+	name, help string
+}{
+	{"deploy", "Deploy an application"},
+	{"destroy", "Destroy an application"},
+	{"status", "Show status"},
+}
+
+// completer completes the subcommands of "app", and the values of the
+// --region flag, from COMP_LINE, adding descriptions for fish.
+var completer = cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+	line := ctx.Getenv("COMP_LINE")
+	if line == "" {
+		return errors.New("COMP_LINE not set")
+	}
+	words := strings.Fields(line)
+	current := ""
+	if !strings.HasSuffix(line, " ") {
+		current, words = words[len(words)-1], words[:len(words)-1]
+	}
+	if words[len(words)-1] == "--region" {
+		for _, r := range []string{"eu-west-1", "us-east-1"} {
+			if strings.HasPrefix(r, current) {
+				fmt.Fprintln(ctx.Stdout, r)
+			}
+		}
+		return nil
+	}
+	if len(words) > 1 {
+		return nil
+	}
+	for _, sub := range subcommands {
+		if !strings.HasPrefix(sub.name, current) {
+			continue
+		}
+		if strings.HasSuffix(ctx.Getenv("SHELL"), "fish") {
+			fmt.Fprintf(ctx.Stdout, "%s\t%s\n", sub.name, sub.help)
+		} else {
+			fmt.Fprintln(ctx.Stdout, sub.name)
+		}
+	}
+	return nil
+})
+
+func (*completeSuite) TestComplete(c *gc.C) {
+	c.Assert(cmdtesting.Complete(c, completer, cmdtesting.Bash, "app d"), jc.DeepEquals, []string{"deploy", "destroy"})
+	c.Assert(cmdtesting.Complete(c, completer, cmdtesting.Bash, "app deploy "), jc.DeepEquals, []string{})
+}
+
+func (*completeSuite) TestCompletions(c *gc.C) {
+	cmdtesting.AssertCompletions(c, completer, cmdtesting.Bash, "app ", "deploy", "destroy", "status")
+	cmdtesting.AssertCompletions(c, completer, cmdtesting.Zsh, "app st", "status")
+	cmdtesting.AssertCompletions(c, completer, cmdtesting.Bash, "app deploy --region ", "eu-west-1", "us-east-1")
+	cmdtesting.AssertCompletions(c, completer, cmdtesting.Bash, "app deploy --region u", "us-east-1")
+	cmdtesting.AssertCompletions(c, completer, cmdtesting.Fish, "app de",
+		"deploy\tDeploy an application",
+		"destroy\tDestroy an application",
+	)
+	cmdtesting.AssertCompletions(c, completer, cmdtesting.Bash, "app status ")
+}
+
+var bashEnvTests = []struct {
+	line string
+	args []string
+}{
+	{"app ", []string{"app", "", "app"}},
+	{"app de", []string{"app", "de", "app"}},
+	{"app deploy --region ", []string{"app", "", "--region"}},
+	{"app", []string{"app", "app", ""}},
+}
+
+func (*completeSuite) TestBashEnvironment(c *gc.C) {
+	for i, test := range bashEnvTests {
+		c.Logf("test %d: %q", i, test.line)
+		var args []string
+		var env map[string]string
+		cmd := cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, a []string) error {
+			args, env = a, ctx.Env
+			return nil
+		})
+		cmdtesting.Complete(c, cmd, cmdtesting.Bash, test.line)
+		c.Check(args, jc.DeepEquals, test.args)
+		c.Check(env, jc.DeepEquals, map[string]string{
+			"COMP_LINE":  test.line,
+			"COMP_POINT": fmt.Sprint(len(test.line)),
+			"COMP_TYPE":  "9",
+			"COMP_KEY":   "9",
+			"SHELL":      "/bin/bash",
+		})
+	}
+}
+
+func (*completeSuite) TestFishEnvironment(c *gc.C) {
+	var args []string
+	var env map[string]string
+	cmd := cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, a []string) error {
+		args, env = a, ctx.Env
+		return nil
+	})
+	cmdtesting.Complete(c, cmd, cmdtesting.Fish, "app de")
+	c.Assert(args, gc.HasLen, 0)
+	c.Assert(env, jc.DeepEquals, map[string]string{
+		"COMP_LINE":  "app de",
+		"COMP_POINT": "6",
+		"SHELL":      "/bin/fish",
+	})
+}
+
+func (*completeSuite) TestCompletionsMismatch(c *gc.C) {
+	c.ExpectFailure("completions differ")
+	cmdtesting.CheckCompletions(c, completer, cmdtesting.Bash, "app d", "deploy")
+}
+
+func (*completeSuite) TestCompleteFails(c *gc.C) {
+	cmd := cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+		return errors.New("cannot complete")
+	})
+	c.ExpectFailure("the completion command failed")
+	cmdtesting.Complete(c, cmd, cmdtesting.Bash, "app ")
+}
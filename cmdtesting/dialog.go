@@ -0,0 +1,184 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+// Dialog scripts the standard input of an interactive command. Each
+// time the command reads its input, the output it has written since
+// the previous response must end with the next expected prompt, and
+// the dialog answers with the prompt's response:
+//
+//	var dialog cmdtesting.Dialog
+//	dialog.Expect(`Username: `, "alice")
+//	dialog.Expect(`Password: `, "secret")
+//	dialog.Expect(`Log in to .* as alice\? \[y/N\] `, "y")
+//	ctx, code := dialog.RunCommand(c, cmd, "login")
+//
+// The test fails, showing a transcript of the dialog so far, if the
+// command asks for input when no prompt, or a different prompt, is
+// expected, if it exits before all the expected prompts are answered,
+// or if it takes longer than Timeout to ask for input or exit.
+//
+// Commands must write each prompt before reading the response, from
+// the same goroutine.
+type Dialog struct {
+	// Timeout holds how long the command may take to ask for the next
+	// input, or to exit after the last response. If it is zero,
+	// testing.LongWait is used.
+	Timeout time.Duration
+
+	mu           sync.Mutex
+	ctx          *Context
+	expectations []expectation
+	stdoutPos    int
+	stderrPos    int
+	pending      string
+	transcript   strings.Builder
+	err          error
+	progress     chan struct{}
+}
+
+type expectation struct {
+	prompt   *regexp.Regexp
+	source   string
+	response string
+}
+
+// Expect adds a prompt to the dialog. The prompt is a regular
+// expression, which must match the end of the command's output since
+// the previous response. The response is given to the command
+// followed by a newline.
+func (d *Dialog) Expect(prompt, response string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expectations = append(d.expectations, expectation{
+		prompt:   regexp.MustCompile("(?:" + prompt + ")$"),
+		source:   prompt,
+		response: response,
+	})
+}
+
+// RunCommand is like the RunCommand function, but the command's
+// standard input is scripted by the dialog.
+func (d *Dialog) RunCommand(c *gc.C, cmd Command, args ...string) (*Context, int) {
+	ctx := NewContext(c)
+	return ctx, d.RunCommandInContext(c, ctx, cmd, args...)
+}
+
+// RunCommandInContext is like the RunCommandInContext function, but
+// the command's standard input is scripted by the dialog, replacing
+// the context's Stdin.
+//
+// If the command times out, the test fails immediately, leaving the
+// command's goroutine running.
+func (d *Dialog) RunCommandInContext(c *gc.C, ctx *Context, cmd Command, args ...string) int {
+	d.mu.Lock()
+	d.ctx = ctx
+	d.progress = make(chan struct{}, 1)
+	d.mu.Unlock()
+	ctx.Stdin = dialogReader{d}
+
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = testing.LongWait
+	}
+	done := make(chan int, 1)
+	go func() {
+		done <- RunCommandInContext(c, ctx, cmd, args...)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case code := <-done:
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.readOutput()
+			if d.err == nil && len(d.expectations) > 0 {
+				d.err = fmt.Errorf("command exited without prompting for %q", d.expectations[0].source)
+			}
+			if d.err != nil {
+				c.Fatalf("%v; transcript:\n%s", d.err, d.transcript.String())
+			}
+			return code
+		case <-d.progress:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(timeout)
+		case <-timer.C:
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			what := "exit"
+			if len(d.expectations) > 0 {
+				what = fmt.Sprintf("prompt for %q", d.expectations[0].source)
+			}
+			c.Fatalf("command did not %s within %v; transcript:\n%s", what, timeout, d.transcript.String())
+		}
+	}
+}
+
+// readOutput adds the command's output since it was last read to the
+// transcript, and returns it. It must be called with d.mu held, from
+// the command's goroutine or after the command has exited.
+func (d *Dialog) readOutput() string {
+	stdout := d.ctx.Stdout.String()
+	stderr := d.ctx.Stderr.String()
+	output := stdout[d.stdoutPos:] + stderr[d.stderrPos:]
+	d.stdoutPos, d.stderrPos = len(stdout), len(stderr)
+	d.transcript.WriteString(output)
+	return output
+}
+
+// errUnexpectedPrompt is returned to the command when it asks for input
+// that the dialog does not expect.
+var errUnexpectedPrompt = errors.New("cmdtesting: unexpected prompt")
+
+// dialogReader reads a command's standard input from a dialog.
+type dialogReader struct {
+	d *Dialog
+}
+
+func (r dialogReader) Read(p []byte) (int, error) {
+	d := r.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case d.progress <- struct{}{}:
+	default:
+	}
+	if d.pending == "" {
+		if d.err != nil {
+			return 0, errUnexpectedPrompt
+		}
+		output := d.readOutput()
+		if len(d.expectations) == 0 {
+			d.err = errors.New("unexpected prompt after the last expected one")
+			return 0, errUnexpectedPrompt
+		}
+		e := d.expectations[0]
+		if !e.prompt.MatchString(output) {
+			d.err = fmt.Errorf("unexpected prompt, expected %q", e.source)
+			return 0, errUnexpectedPrompt
+		}
+		d.expectations = d.expectations[1:]
+		d.pending = e.response + "\n"
+		d.transcript.WriteString(d.pending)
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
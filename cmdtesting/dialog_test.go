@@ -0,0 +1,103 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/cmdtesting"
+)
+
+type dialogSuite struct{}
+
+var _ = gc.Suite(&dialogSuite{})
+
+// login asks for a username and password, and then for confirmation.
+var login = cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+	r := bufio.NewReader(ctx.Stdin)
+	ask := func(prompt string) (string, error) {
+		fmt.Fprint(ctx.Stderr, prompt)
+		line, err := r.ReadString('\n')
+		return strings.TrimSuffix(line, "\n"), err
+	}
+	fmt.Fprintln(ctx.Stdout, "Logging in to example.com")
+	user, err := ask("Username: ")
+	if err != nil {
+		return err
+	}
+	if _, err := ask("Password: "); err != nil {
+		return err
+	}
+	answer, err := ask(fmt.Sprintf("Log in as %s? [y/N] ", user))
+	if err != nil {
+		return err
+	}
+	if answer != "y" {
+		return errors.New("login cancelled")
+	}
+	fmt.Fprintf(ctx.Stdout, "Logged in as %s\n", user)
+	return nil
+})
+
+func (*dialogSuite) TestDialog(c *gc.C) {
+	var dialog cmdtesting.Dialog
+	dialog.Expect(`Username: `, "alice")
+	dialog.Expect(`Password: `, "secret")
+	dialog.Expect(`Log in as alice\? \[y/N\] `, "y")
+	ctx, code := dialog.RunCommand(c, login)
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(ctx, cmdtesting.StdoutEquals, "Logging in to example.com\nLogged in as alice\n")
+	c.Assert(ctx, cmdtesting.StderrEquals, "Username: Password: Log in as alice? [y/N] ")
+}
+
+func (*dialogSuite) TestDialogCommandFails(c *gc.C) {
+	var dialog cmdtesting.Dialog
+	dialog.Expect(`Username: `, "alice")
+	dialog.Expect(`Password: `, "secret")
+	dialog.Expect(`\[y/N\] `, "n")
+	ctx, code := dialog.RunCommand(c, login)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(ctx, cmdtesting.StderrMatches, `.*ERROR login cancelled\n`)
+}
+
+func (*dialogSuite) TestDialogWrongPrompt(c *gc.C) {
+	var dialog cmdtesting.Dialog
+	dialog.Expect(`Username: `, "alice")
+	dialog.Expect(`Token: `, "1234")
+	c.ExpectFailure(`unexpected prompt, expected "Token: "`)
+	dialog.RunCommand(c, login)
+}
+
+func (*dialogSuite) TestDialogUnexpectedPrompt(c *gc.C) {
+	var dialog cmdtesting.Dialog
+	dialog.Expect(`Username: `, "alice")
+	c.ExpectFailure("unexpected prompt after the last expected one")
+	dialog.RunCommand(c, login)
+}
+
+func (*dialogSuite) TestDialogUnansweredPrompt(c *gc.C) {
+	var dialog cmdtesting.Dialog
+	dialog.Expect(`Username: `, "alice")
+	c.ExpectFailure(`command exited without prompting for "Username: "`)
+	dialog.RunCommand(c, echo, "hello")
+}
+
+func (*dialogSuite) TestDialogTimeout(c *gc.C) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+	cmd := cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+		<-unblock
+		return nil
+	})
+	dialog := cmdtesting.Dialog{Timeout: 10 * time.Millisecond}
+	dialog.Expect(`Username: `, "alice")
+	c.ExpectFailure(`command did not prompt for "Username: " within 10ms`)
+	dialog.RunCommand(c, cmd)
+}
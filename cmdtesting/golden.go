@@ -0,0 +1,105 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+// Normalizer rewrites a command's output before it is compared with a
+// golden file, typically to replace details that vary from run to run
+// with placeholders.
+type Normalizer func(output string) string
+
+// ReplaceString returns a Normalizer that replaces every occurrence of
+// old with new.
+func ReplaceString(old, new string) Normalizer {
+	return func(output string) string {
+		return strings.ReplaceAll(output, old, new)
+	}
+}
+
+// ReplaceRegexp returns a Normalizer that replaces every match of the
+// regular expression with repl, which may refer to submatches as
+// regexp.Regexp.ReplaceAllString does.
+func ReplaceRegexp(pattern, repl string) Normalizer {
+	re := regexp.MustCompile(pattern)
+	return func(output string) string {
+		return re.ReplaceAllString(output, repl)
+	}
+}
+
+// Timestamps replaces RFC 3339 timestamps, and timestamps that use a
+// space instead of the "T", with "<timestamp>".
+var Timestamps = ReplaceRegexp(
+	`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`,
+	"<timestamp>",
+)
+
+// Versions replaces semantic versions, such as "1.2.3" or
+// "v2.0.0-beta.1", with "<version>".
+var Versions = ReplaceRegexp(
+	`\bv?\d+\.\d+\.\d+(-[0-9A-Za-z.]+)?(\+[0-9A-Za-z.]+)?\b`,
+	"<version>",
+)
+
+// TempPaths replaces paths within the system's temporary directory,
+// such as the working directories of contexts, with "<tmp>".
+var TempPaths = func() Normalizer {
+	tmp := filepath.Clean(os.TempDir())
+	return ReplaceRegexp(regexp.QuoteMeta(tmp)+`(/[^\s'"`+"`"+`,;:()]*)?`, "<tmp>")
+}()
+
+// ReplaceDir returns a Normalizer that replaces the context's working
+// directory with "<dir>", leaving the paths of files within it
+// distinguishable.
+func ReplaceDir(ctx *Context) Normalizer {
+	return ReplaceString(ctx.Dir, "<dir>")
+}
+
+// CheckGolden checks the standard output and standard error captured by
+// the context against golden files, after applying the normalizers in
+// order. It returns whether the check succeeded.
+//
+// The golden files are stored as text snapshots, as by
+// testing.CheckTextSnapshot, named after the test and the given name,
+// such as "mySuite.TestHelp.stdout.txt" and
+// "mySuite.TestHelp.stderr.txt" when name is empty. They are created or
+// updated when the tests are run with the -snapshot.update flag:
+//
+//	ctx, code := cmdtesting.RunCommand(c, cmd, "status", "--format", "tabular")
+//	c.Assert(code, gc.Equals, 0)
+//	cmdtesting.CheckGolden(c, "", ctx, cmdtesting.ReplaceDir(ctx), cmdtesting.Timestamps)
+func CheckGolden(c *gc.C, name string, ctx *Context, normalizers ...Normalizer) bool {
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+	ok := testing.CheckTextSnapshot(c, prefix+"stdout", Normalize(ctx.Stdout.String(), normalizers...))
+	return testing.CheckTextSnapshot(c, prefix+"stderr", Normalize(ctx.Stderr.String(), normalizers...)) && ok
+}
+
+// AssertGolden is like CheckGolden, but stops the test if the check
+// fails.
+func AssertGolden(c *gc.C, name string, ctx *Context, normalizers ...Normalizer) {
+	if !CheckGolden(c, name, ctx, normalizers...) {
+		c.FailNow()
+	}
+}
+
+// Normalize returns the output after applying the normalizers in
+// order.
+func Normalize(output string, normalizers ...Normalizer) string {
+	for _, n := range normalizers {
+		output = n(output)
+	}
+	return output
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/cmdtesting"
+)
+
+type goldenSuite struct{}
+
+var _ = gc.Suite(&goldenSuite{})
+
+var normalizerTests = []struct {
+	about      string
+	normalizer cmdtesting.Normalizer
+	output     string
+	expect     string
+}{{
+	about:      "ReplaceString",
+	normalizer: cmdtesting.ReplaceString("alice", "<user>"),
+	output:     "alice logged in as alice\n",
+	expect:     "<user> logged in as <user>\n",
+}, {
+	about:      "ReplaceRegexp with a submatch",
+	normalizer: cmdtesting.ReplaceRegexp(`id-(\d+)`, "id-<$1>"),
+	output:     "created id-42\n",
+	expect:     "created id-<42>\n",
+}, {
+	about:      "Timestamps in UTC",
+	normalizer: cmdtesting.Timestamps,
+	output:     "started at 2026-01-02T03:04:05Z\n",
+	expect:     "started at <timestamp>\n",
+}, {
+	about:      "Timestamps with fractional seconds and an offset",
+	normalizer: cmdtesting.Timestamps,
+	output:     "2026-01-02T03:04:05.123456+01:00 message",
+	expect:     "<timestamp> message",
+}, {
+	about:      "Timestamps separated by a space",
+	normalizer: cmdtesting.Timestamps,
+	output:     "Since: 2026-01-02 03:04:05",
+	expect:     "Since: <timestamp>",
+}, {
+	about:      "Versions",
+	normalizer: cmdtesting.Versions,
+	output:     "app 1.2.3 (agent v2.0.0-beta.1+build.7), protocol 3.1",
+	expect:     "app <version> (agent <version>), protocol 3.1",
+}, {
+	about:      "TempPaths",
+	normalizer: cmdtesting.TempPaths,
+	output:     fmt.Sprintf("wrote %s, %s\n", filepath.Join(os.TempDir(), "x", "y.yaml"), "/etc/hosts"),
+	expect:     "wrote <tmp>, /etc/hosts\n",
+}}
+
+func (*goldenSuite) TestNormalizers(c *gc.C) {
+	for i, test := range normalizerTests {
+		c.Logf("test %d: %s", i, test.about)
+		c.Check(test.normalizer(test.output), gc.Equals, test.expect)
+	}
+}
+
+func (*goldenSuite) TestNormalize(c *gc.C) {
+	output := cmdtesting.Normalize("v1.2.3 at 2026-01-02T03:04:05Z",
+		cmdtesting.Versions,
+		cmdtesting.Timestamps,
+		cmdtesting.ReplaceString("<", "{"),
+	)
+	c.Assert(output, gc.Equals, "{version> at {timestamp>")
+}
+
+func (*goldenSuite) TestReplaceDir(c *gc.C) {
+	ctx := cmdtesting.NewContext(c)
+	normalize := cmdtesting.ReplaceDir(ctx)
+	c.Assert(normalize(filepath.Join(ctx.Dir, "config.yaml")), gc.Equals, "<dir>"+string(filepath.Separator)+"config.yaml")
+}
+
+// status prints a table, including details that vary between runs.
+var status = cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+	fmt.Fprintf(ctx.Stdout, "%-8s %-8s %s\n", "NAME", "VERSION", "SINCE")
+	fmt.Fprintf(ctx.Stdout, "%-8s %-8s %s\n", "web", "1.4.2", "2026-10-15T09:30:00Z")
+	fmt.Fprintf(ctx.Stdout, "%-8s %-8s %s\n", "db", "14.10.0", "2026-10-14T18:02:11Z")
+	fmt.Fprintf(ctx.Stderr, "WARNING using config %s\n", ctx.AbsPath("config.yaml"))
+	return nil
+})
+
+func (*goldenSuite) TestGolden(c *gc.C) {
+	ctx, code := cmdtesting.RunCommand(c, status)
+	c.Assert(code, gc.Equals, 0)
+	cmdtesting.AssertGolden(c, "", ctx, cmdtesting.ReplaceDir(ctx), cmdtesting.Timestamps, cmdtesting.Versions)
+}
+
+func (*goldenSuite) TestGoldenMismatch(c *gc.C) {
+	ctx, code := cmdtesting.RunCommand(c, status)
+	c.Assert(code, gc.Equals, 0)
+	c.ExpectFailure("output differs from the golden file")
+	cmdtesting.CheckGolden(c, "", ctx, cmdtesting.ReplaceDir(ctx))
+}
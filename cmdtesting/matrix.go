@@ -0,0 +1,155 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Variant is one of the alternatives of a Dimension of a Matrix.
+type Variant struct {
+	// Name identifies the variant within its dimension.
+	Name string
+
+	// Env holds environment variables to set.
+	Env map[string]string
+
+	// Args holds arguments, such as flags, to add to the command line.
+	Args []string
+
+	// Files holds the contents of files, such as configuration files,
+	// to create, keyed by their paths relative to the working
+	// directory.
+	Files map[string]string
+}
+
+// Dimension is one of the ways in which the runs of a Matrix vary.
+type Dimension struct {
+	// Name identifies the dimension.
+	Name string
+
+	// Variants holds the dimension's alternatives.
+	Variants []Variant
+}
+
+// Combination identifies a run of a Matrix, made with one variant of
+// each dimension.
+type Combination struct {
+	// Name holds the name of the combination, such as
+	// "env=set flag=unset".
+	Name string
+
+	// Variants maps the name of each dimension to the name of its
+	// variant in the combination.
+	Variants map[string]string
+}
+
+// Matrix runs a command once for each combination of the variants of
+// its dimensions, and checks the result of each run with the same
+// function. It is useful for covering precedence rules, such as those
+// between flags, environment variables and configuration files,
+// without writing out every combination:
+//
+//	cmdtesting.Matrix{
+//		Command: cmd,
+//		Args:    []string{"show-region"},
+//		Dimensions: []cmdtesting.Dimension{{
+//			Name: "flag",
+//			Variants: []cmdtesting.Variant{
+//				{Name: "unset"},
+//				{Name: "set", Args: []string{"--region", "from-flag"}},
+//			},
+//		}, {
+//			Name: "env",
+//			Variants: []cmdtesting.Variant{
+//				{Name: "unset"},
+//				{Name: "set", Env: map[string]string{"REGION": "from-env"}},
+//			},
+//		}},
+//		Check: func(c *gc.C, comb cmdtesting.Combination, ctx *cmdtesting.Context, code int) {
+//			expect := "default"
+//			if comb.Variants["flag"] == "set" {
+//				expect = "from-flag"
+//			} else if comb.Variants["env"] == "set" {
+//				expect = "from-env"
+//			}
+//			c.Check(ctx, cmdtesting.StdoutEquals, expect+"\n")
+//		},
+//	}.Run(c)
+type Matrix struct {
+	// Command holds the command to run.
+	Command Command
+
+	// Args holds the arguments common to every run. The arguments of
+	// each run are Args followed by the Args of the combination's
+	// variants, in the order of their dimensions.
+	Args []string
+
+	// Dimensions holds the ways in which the runs vary. Where the
+	// variants of different dimensions set the same environment
+	// variable or file, the later dimension's variant wins.
+	Dimensions []Dimension
+
+	// Check checks the result of running the command with a
+	// combination.
+	Check func(c *gc.C, comb Combination, ctx *Context, code int)
+}
+
+// Run runs the command with every combination, each in a new Context,
+// with the first dimension varying slowest.
+func (m Matrix) Run(c *gc.C) {
+	for i, variants := range m.combinations(c) {
+		comb := Combination{
+			Variants: make(map[string]string),
+		}
+		var names []string
+		for j, v := range variants {
+			dim := m.Dimensions[j].Name
+			names = append(names, dim+"="+v.Name)
+			comb.Variants[dim] = v.Name
+		}
+		comb.Name = strings.Join(names, " ")
+		c.Logf("combination %d: %s", i, comb.Name)
+
+		ctx := NewContext(c)
+		args := append([]string(nil), m.Args...)
+		for _, v := range variants {
+			for k, val := range v.Env {
+				ctx.Setenv(k, val)
+			}
+			for path, content := range v.Files {
+				path = ctx.AbsPath(path)
+				err := os.MkdirAll(filepath.Dir(path), 0755)
+				c.Assert(err, gc.IsNil)
+				err = os.WriteFile(path, []byte(content), 0644)
+				c.Assert(err, gc.IsNil)
+			}
+			args = append(args, v.Args...)
+		}
+		code := RunCommandInContext(c, ctx, m.Command, args...)
+		m.Check(c, comb, ctx, code)
+	}
+}
+
+// combinations returns every combination of the dimensions' variants.
+func (m Matrix) combinations(c *gc.C) [][]Variant {
+	combs := [][]Variant{nil}
+	for _, dim := range m.Dimensions {
+		if len(dim.Variants) == 0 {
+			c.Fatalf("dimension %q has no variants", dim.Name)
+		}
+		var next [][]Variant
+		for _, comb := range combs {
+			for _, v := range dim.Variants {
+				next = append(next, append(comb[:len(comb):len(comb)], v))
+			}
+		}
+		combs = next
+	}
+	return combs
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cmdtesting_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/cmdtesting"
+)
+
+type matrixSuite struct{}
+
+var _ = gc.Suite(&matrixSuite{})
+
+// showRegion prints the region, taken from the --region flag, the
+// REGION environment variable or the region.conf file, in that order
+// of precedence.
+var showRegion = cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+	fs := flag.NewFlagSet("show-region", flag.ContinueOnError)
+	fs.SetOutput(ctx.Stderr)
+	region := fs.String("region", "", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *region == "" {
+		*region = ctx.Getenv("REGION")
+	}
+	if *region == "" {
+		data, err := os.ReadFile(ctx.AbsPath("region.conf"))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		*region = strings.TrimSpace(string(data))
+	}
+	if *region == "" {
+		*region = "default"
+	}
+	fmt.Fprintln(ctx.Stdout, *region)
+	return nil
+})
+
+func (*matrixSuite) TestMatrix(c *gc.C) {
+	var names []string
+	cmdtesting.Matrix{
+		Command: showRegion,
+		Dimensions: []cmdtesting.Dimension{{
+			Name: "flag",
+			Variants: []cmdtesting.Variant{
+				{Name: "unset"},
+				{Name: "set", Args: []string{"--region", "from-flag"}},
+			},
+		}, {
+			Name: "env",
+			Variants: []cmdtesting.Variant{
+				{Name: "unset"},
+				{Name: "set", Env: map[string]string{"REGION": "from-env"}},
+			},
+		}, {
+			Name: "config",
+			Variants: []cmdtesting.Variant{
+				{Name: "none"},
+				{Name: "file", Files: map[string]string{"region.conf": "from-file\n"}},
+			},
+		}},
+		Check: func(c *gc.C, comb cmdtesting.Combination, ctx *cmdtesting.Context, code int) {
+			names = append(names, comb.Name)
+			expect := "default"
+			switch {
+			case comb.Variants["flag"] == "set":
+				expect = "from-flag"
+			case comb.Variants["env"] == "set":
+				expect = "from-env"
+			case comb.Variants["config"] == "file":
+				expect = "from-file"
+			}
+			c.Check(code, gc.Equals, 0)
+			c.Check(ctx, cmdtesting.StdoutEquals, expect+"\n")
+		},
+	}.Run(c)
+	c.Assert(names, jc.DeepEquals, []string{
+		"flag=unset env=unset config=none",
+		"flag=unset env=unset config=file",
+		"flag=unset env=set config=none",
+		"flag=unset env=set config=file",
+		"flag=set env=unset config=none",
+		"flag=set env=unset config=file",
+		"flag=set env=set config=none",
+		"flag=set env=set config=file",
+	})
+}
+
+func (*matrixSuite) TestMatrixArgs(c *gc.C) {
+	var args []string
+	cmdtesting.Matrix{
+		Command: cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, a []string) error {
+			args = append(args, strings.Join(a, " "))
+			return nil
+		}),
+		Args: []string{"deploy"},
+		Dimensions: []cmdtesting.Dimension{{
+			Name:     "a",
+			Variants: []cmdtesting.Variant{{Name: "1", Args: []string{"-a"}}},
+		}, {
+			Name:     "b",
+			Variants: []cmdtesting.Variant{{Name: "1", Args: []string{"-b", "x"}}, {Name: "2"}},
+		}},
+		Check: func(c *gc.C, comb cmdtesting.Combination, ctx *cmdtesting.Context, code int) {},
+	}.Run(c)
+	c.Assert(args, jc.DeepEquals, []string{"deploy -a -b x", "deploy -a"})
+}
+
+func (*matrixSuite) TestMatrixNestedFiles(c *gc.C) {
+	cmdtesting.Matrix{
+		Command: cmdtesting.CommandFunc(func(ctx *cmdtesting.Context, args []string) error {
+			data, err := os.ReadFile(ctx.AbsPath(".config/app/config.yaml"))
+			if err != nil {
+				return err
+			}
+			ctx.Stdout.Write(data)
+			return nil
+		}),
+		Dimensions: []cmdtesting.Dimension{{
+			Name: "config",
+			Variants: []cmdtesting.Variant{{
+				Name:  "nested",
+				Files: map[string]string{".config/app/config.yaml": "a: b\n"},
+			}},
+		}},
+		Check: func(c *gc.C, comb cmdtesting.Combination, ctx *cmdtesting.Context, code int) {
+			c.Check(code, gc.Equals, 0)
+			c.Check(ctx, cmdtesting.StdoutEquals, "a: b\n")
+		},
+	}.Run(c)
+}
+
+func (*matrixSuite) TestMatrixEmptyDimension(c *gc.C) {
+	c.ExpectFailure(`dimension "flag" has no variants`)
+	cmdtesting.Matrix{
+		Command:    showRegion,
+		Dimensions: []cmdtesting.Dimension{{Name: "flag"}},
+		Check:      func(c *gc.C, comb cmdtesting.Combination, ctx *cmdtesting.Context, code int) {},
+	}.Run(c)
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package cobratesting provides helpers for testing commands built with
+// github.com/spf13/cobra, building on cmdtesting.
+package cobratesting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/cmdtesting"
+)
+
+// Result holds the result of running a cobra command.
+type Result struct {
+	// Context holds the context in which the command ran, including
+	// its output. Cobra prints errors to Context.Stderr and usage to
+	// Context.Stdout.
+	Context *cmdtesting.Context
+
+	// Command holds the command that was executed, which may be a
+	// subcommand of the root command.
+	Command *cobra.Command
+
+	// Err holds the error returned by executing the command.
+	Err error
+
+	// Code holds the exit code that a program would exit with if it
+	// exited with 1 when executing the command failed. If Err has an
+	// ExitCode method, as *exec.ExitError does, Code is its result
+	// instead.
+	Code int
+}
+
+// RunCommand builds a new root command with newRoot, executes it with
+// the given arguments in a new cmdtesting.Context, and returns the
+// result. Building the command afresh for each run ensures that no flag
+// values are left over from earlier runs:
+//
+//	res := cobratesting.RunCommand(c, cli.NewRootCommand, "deploy", "--force", "web")
+//	c.Assert(res.Err, gc.IsNil)
+//	c.Assert(res.Command.Name(), gc.Equals, "deploy")
+//	c.Assert(res.Context, cmdtesting.StdoutEquals, "deployed web\n")
+func RunCommand(c *gc.C, newRoot func() *cobra.Command, args ...string) *Result {
+	return RunCommandInContext(c, cmdtesting.NewContext(c), newRoot, args...)
+}
+
+// RunCommandInContext is like RunCommand, but runs the command in the
+// given context. The command's standard streams are taken from the
+// context; commands that use the context's working directory or
+// environment can be built by a newRoot function that refers to it.
+func RunCommandInContext(c *gc.C, ctx *cmdtesting.Context, newRoot func() *cobra.Command, args ...string) *Result {
+	c.Logf("running command: %s", strings.Join(args, " "))
+	root := newRoot()
+	root.SetArgs(append([]string{}, args...))
+	root.SetIn(ctx.Stdin)
+	root.SetOut(ctx.Stdout)
+	root.SetErr(ctx.Stderr)
+	cmd, err := root.ExecuteC()
+	res := &Result{
+		Context: ctx,
+		Command: cmd,
+		Err:     err,
+	}
+	if err != nil {
+		res.Code = 1
+		if e, ok := err.(interface{ ExitCode() int }); ok {
+			res.Code = e.ExitCode()
+		}
+	}
+	return res
+}
+
+// Complete requests shell completions from a new root command built
+// with newRoot, as a shell would when the user presses tab after
+// typing the given arguments; the last argument is the word being
+// completed, and may be empty. It returns the completions, including
+// any descriptions following a tab, and the completion directive.
+//
+// Completion functions registered with ValidArgsFunction and
+// RegisterFlagCompletionFunc are both exercised this way:
+//
+//	completions, directive := cobratesting.Complete(c, cli.NewRootCommand, "deploy", "--region", "")
+//	c.Assert(completions, jc.DeepEquals, []string{"eu-west-1", "us-east-1"})
+//	c.Assert(directive, gc.Equals, cobra.ShellCompDirectiveNoFileComp)
+func Complete(c *gc.C, newRoot func() *cobra.Command, args ...string) ([]string, cobra.ShellCompDirective) {
+	res := RunCommand(c, newRoot, append([]string{cobra.ShellCompRequestCmd}, args...)...)
+	c.Assert(res.Err, gc.IsNil, gc.Commentf("stderr: %q", res.Context.Stderr.String()))
+	lines := strings.Split(strings.TrimSuffix(res.Context.Stdout.String(), "\n"), "\n")
+	last := lines[len(lines)-1]
+	c.Assert(strings.HasPrefix(last, ":"), gc.Equals, true, gc.Commentf("no completion directive in output %q", res.Context.Stdout.String()))
+	directive, err := strconv.Atoi(last[1:])
+	c.Assert(err, gc.IsNil)
+	return lines[:len(lines)-1], cobra.ShellCompDirective(directive)
+}
+
+// FindCommand returns the subcommand of root with the given path of
+// command names, failing the test if there is none.
+func FindCommand(c *gc.C, root *cobra.Command, path ...string) *cobra.Command {
+	cmd, rest, err := root.Find(path)
+	c.Assert(err, gc.IsNil)
+	if len(rest) > 0 {
+		c.Fatalf("command %q not found", strings.Join(path, " "))
+	}
+	return cmd
+}
+
+// LookupFlag returns the flag with the given name that applies to the
+// command, including persistent flags inherited from its parents,
+// failing the test if there is none. The flag can then be checked:
+//
+//	f := cobratesting.LookupFlag(c, cmd, "region")
+//	c.Assert(f.Shorthand, gc.Equals, "r")
+//	c.Assert(f.DefValue, gc.Equals, "us-east-1")
+func LookupFlag(c *gc.C, cmd *cobra.Command, name string) *pflag.Flag {
+	f := cmd.Flag(name)
+	if f == nil {
+		c.Fatalf("command %q has no flag %q", cmd.CommandPath(), name)
+	}
+	return f
+}
+
+type hasFlagChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasFlag checks that the obtained *cobra.Command has a flag with the
+// given name, including persistent flags inherited from its parents.
+var HasFlag gc.Checker = &hasFlagChecker{
+	&gc.CheckerInfo{Name: "HasFlag", Params: []string{"obtained", "name"}},
+}
+
+func (checker *hasFlagChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	cmd, ok := params[0].(*cobra.Command)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type *cobra.Command, got %T", params[0])
+	}
+	name, ok := params[1].(string)
+	if !ok {
+		return false, fmt.Sprintf("name must be a string, got %T", params[1])
+	}
+	if cmd.Flag(name) != nil {
+		return true, ""
+	}
+	var flags []string
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		flags = append(flags, f.Name)
+	})
+	cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		flags = append(flags, f.Name)
+	})
+	return false, fmt.Sprintf("flags: %s", strings.Join(flags, ", "))
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package cobratesting_test
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/cmdtesting"
+	"github.com/juju/testing/cobratesting"
+)
+
+type cobraSuite struct{}
+
+var _ = gc.Suite(&cobraSuite{})
+
+// newRoot returns the root command of a small CLI.
+func newRoot() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "app",
+		SilenceUsage: true,
+	}
+	root.PersistentFlags().BoolP("verbose", "v", false, "show more output")
+
+	var region string
+	var force bool
+	deploy := &cobra.Command{
+		Use:     "deploy <name>",
+		Aliases: []string{"dep"},
+		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"web\tthe web server", "db\tthe database"}, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if args[0] == "broken" {
+				return errors.New("cannot deploy broken")
+			}
+			if args[0] == "exit" {
+				return exec.Command("/bin/sh", "-c", "exit 3").Run()
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "deployed %s to %s (force=%v)\n", args[0], region, force)
+			return nil
+		},
+	}
+	deploy.Flags().StringVarP(&region, "region", "r", "us-east-1", "region to deploy to")
+	deploy.Flags().BoolVar(&force, "force", false, "deploy even if unhealthy")
+	deploy.RegisterFlagCompletionFunc("region", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"eu-west-1", "us-east-1"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	root.AddCommand(deploy)
+	return root
+}
+
+func (*cobraSuite) TestRunCommand(c *gc.C) {
+	res := cobratesting.RunCommand(c, newRoot, "deploy", "--force", "web")
+	c.Assert(res.Err, gc.IsNil)
+	c.Assert(res.Code, gc.Equals, 0)
+	c.Assert(res.Command.Name(), gc.Equals, "deploy")
+	c.Assert(res.Context, cmdtesting.StdoutEquals, "deployed web to us-east-1 (force=true)\n")
+	c.Assert(res.Context, cmdtesting.StderrEquals, "")
+}
+
+func (*cobraSuite) TestRunCommandFresh(c *gc.C) {
+	res := cobratesting.RunCommand(c, newRoot, "deploy", "-r", "eu-west-1", "--force", "web")
+	c.Assert(res.Err, gc.IsNil)
+	// Flag values from the first run do not leak into the second.
+	res = cobratesting.RunCommand(c, newRoot, "deploy", "web")
+	c.Assert(res.Err, gc.IsNil)
+	c.Assert(res.Context, cmdtesting.StdoutEquals, "deployed web to us-east-1 (force=false)\n")
+}
+
+func (*cobraSuite) TestRunCommandError(c *gc.C) {
+	res := cobratesting.RunCommand(c, newRoot, "deploy", "broken")
+	c.Assert(res.Err, gc.ErrorMatches, "cannot deploy broken")
+	c.Assert(res.Code, gc.Equals, 1)
+	c.Assert(res.Context, cmdtesting.StderrEquals, "Error: cannot deploy broken\n")
+}
+
+func (*cobraSuite) TestRunCommandExitCode(c *gc.C) {
+	res := cobratesting.RunCommand(c, newRoot, "deploy", "exit")
+	c.Assert(res.Code, gc.Equals, 3)
+}
+
+func (*cobraSuite) TestRunCommandUsage(c *gc.C) {
+	newRoot := func() *cobra.Command {
+		root := newRoot()
+		root.SilenceUsage = false
+		return root
+	}
+	res := cobratesting.RunCommand(c, newRoot, "deploy", "--bogus", "web")
+	c.Assert(res.Err, gc.ErrorMatches, "unknown flag: --bogus")
+	c.Assert(res.Code, gc.Equals, 1)
+	c.Assert(res.Context, cmdtesting.StderrEquals, "Error: unknown flag: --bogus\n")
+	// Cobra prints usage to the command's output stream.
+	c.Assert(res.Context, cmdtesting.StdoutMatches, `(?s)Usage:\n  app deploy <name> \[flags\].*`)
+}
+
+func (*cobraSuite) TestRunCommandInContext(c *gc.C) {
+	ctx := cmdtesting.NewContext(c)
+	ctx.Setenv("APP_REGION", "eu-west-1")
+	newRoot := func() *cobra.Command {
+		return &cobra.Command{
+			Use: "app",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				fmt.Fprintln(cmd.OutOrStdout(), ctx.Getenv("APP_REGION"))
+				return nil
+			},
+		}
+	}
+	res := cobratesting.RunCommandInContext(c, ctx, newRoot)
+	c.Assert(res.Err, gc.IsNil)
+	c.Assert(res.Context, gc.Equals, ctx)
+	c.Assert(ctx, cmdtesting.StdoutEquals, "eu-west-1\n")
+}
+
+func (*cobraSuite) TestComplete(c *gc.C) {
+	completions, directive := cobratesting.Complete(c, newRoot, "deploy", "")
+	c.Assert(completions, jc.DeepEquals, []string{"web\tthe web server", "db\tthe database"})
+	c.Assert(directive, gc.Equals, cobra.ShellCompDirectiveNoFileComp)
+}
+
+func (*cobraSuite) TestCompleteFlag(c *gc.C) {
+	completions, directive := cobratesting.Complete(c, newRoot, "deploy", "--region", "")
+	c.Assert(completions, jc.DeepEquals, []string{"eu-west-1", "us-east-1"})
+	c.Assert(directive, gc.Equals, cobra.ShellCompDirectiveNoFileComp)
+}
+
+func (*cobraSuite) TestFindCommand(c *gc.C) {
+	root := newRoot()
+	c.Assert(cobratesting.FindCommand(c, root, "deploy").Name(), gc.Equals, "deploy")
+	c.Assert(cobratesting.FindCommand(c, root, "dep").Name(), gc.Equals, "deploy")
+}
+
+func (*cobraSuite) TestFindCommandMissing(c *gc.C) {
+	c.ExpectFailure("there is no destroy command")
+	cobratesting.FindCommand(c, newRoot(), "destroy")
+}
+
+func (*cobraSuite) TestLookupFlag(c *gc.C) {
+	deploy := cobratesting.FindCommand(c, newRoot(), "deploy")
+	f := cobratesting.LookupFlag(c, deploy, "region")
+	c.Assert(f.Shorthand, gc.Equals, "r")
+	c.Assert(f.DefValue, gc.Equals, "us-east-1")
+	// Persistent flags of parents are found too.
+	f = cobratesting.LookupFlag(c, deploy, "verbose")
+	c.Assert(f.Shorthand, gc.Equals, "v")
+}
+
+func (*cobraSuite) TestLookupFlagMissing(c *gc.C) {
+	c.ExpectFailure("there is no bogus flag")
+	cobratesting.LookupFlag(c, newRoot(), "bogus")
+}
+
+var hasFlagTests = []struct {
+	about    string
+	obtained interface{}
+	name     interface{}
+	message  string
+}{{
+	about: "local flag",
+	name:  "force",
+}, {
+	about: "inherited flag",
+	name:  "verbose",
+}, {
+	about:   "missing flag",
+	name:    "bogus",
+	message: `flags: force, region, verbose`,
+}, {
+	about:    "obtained value of the wrong type",
+	obtained: "deploy",
+	name:     "force",
+	message:  `obtained value must be of type \*cobra.Command, got string`,
+}, {
+	about:   "name of the wrong type",
+	name:    1,
+	message: `name must be a string, got int`,
+}}
+
+func (*cobraSuite) TestHasFlag(c *gc.C) {
+	deploy := cobratesting.FindCommand(c, newRoot(), "deploy")
+	for i, test := range hasFlagTests {
+		c.Logf("test %d: %s", i, test.about)
+		obtained := test.obtained
+		if obtained == nil {
+			obtained = deploy
+		}
+		result, message := cobratesting.HasFlag.Check([]interface{}{obtained, test.name}, nil)
+		c.Check(result, gc.Equals, test.message == "")
+		c.Check(message, gc.Matches, test.message)
+	}
+	c.Assert(deploy, cobratesting.HasFlag, "region")
+	c.Assert(deploy, gc.Not(cobratesting.HasFlag), "bogus")
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package ctxtesting
+
+import (
+	"fmt"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+type deadlineWithinChecker struct {
+	*gc.CheckerInfo
+}
+
+// DeadlineWithin checks that the context of the obtained *Call has a
+// deadline the expected time.Duration after the call was made, give or
+// take the given tolerance. For example, to check that a 5s timeout
+// given by the caller reached the store:
+//
+//	c.Assert(r.Calls()[0], ctxtesting.DeadlineWithin, 5*time.Second, 100*time.Millisecond)
+var DeadlineWithin gc.Checker = &deadlineWithinChecker{
+	&gc.CheckerInfo{Name: "DeadlineWithin", Params: []string{"obtained", "expected", "tolerance"}},
+}
+
+func (checker *deadlineWithinChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	call, ok := params[0].(*Call)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type *ctxtesting.Call, got %T", params[0])
+	}
+	expected, ok := params[1].(time.Duration)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be of type time.Duration, got %T", params[1])
+	}
+	tolerance, ok := params[2].(time.Duration)
+	if !ok {
+		return false, fmt.Sprintf("tolerance must be of type time.Duration, got %T", params[2])
+	}
+	if !call.HasDeadline {
+		return false, fmt.Sprintf("context of %s has no deadline", call.Name)
+	}
+	got := call.Deadline.Sub(call.Time)
+	if diff := got - expected; diff < -tolerance || diff > tolerance {
+		return false, fmt.Sprintf("context of %s has deadline %v after call, expected %v±%v", call.Name, got, expected, tolerance)
+	}
+	return true, ""
+}
+
+type hasNoDeadlineChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasNoDeadline checks that the context of the obtained *Call has no
+// deadline.
+var HasNoDeadline gc.Checker = &hasNoDeadlineChecker{
+	&gc.CheckerInfo{Name: "HasNoDeadline", Params: []string{"obtained"}},
+}
+
+func (checker *hasNoDeadlineChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	call, ok := params[0].(*Call)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type *ctxtesting.Call, got %T", params[0])
+	}
+	if call.HasDeadline {
+		return false, fmt.Sprintf("context of %s has deadline %v after call", call.Name, call.Deadline.Sub(call.Time))
+	}
+	return true, ""
+}
+
+// CheckCancelledWithin checks that the context of call is done within
+// d of the wall clock time of the check, failing the test if it is not,
+// and returns whether it is. It is called once the test has cancelled
+// the context given to the code under test, to check that the
+// cancellation was passed on:
+//
+//	cancel()
+//	ctxtesting.CheckCancelledWithin(c, r.Calls()[0], 100*time.Millisecond)
+func CheckCancelledWithin(c *gc.C, call *Call, d time.Duration) bool {
+	select {
+	case <-call.done:
+		return true
+	case <-time.After(d):
+	}
+	c.Errorf("context of %s not cancelled within %v", call.Name, d)
+	return false
+}
+
+// AssertCancelledWithin is like CheckCancelledWithin, except that the
+// test is stopped if the context is not cancelled in time.
+func AssertCancelledWithin(c *gc.C, call *Call, d time.Duration) {
+	if !CheckCancelledWithin(c, call, d) {
+		c.FailNow()
+	}
+}
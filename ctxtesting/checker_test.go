@@ -0,0 +1,107 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package ctxtesting_test
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/ctxtesting"
+	"github.com/juju/testing/testclock"
+)
+
+type checkerSuite struct{}
+
+var _ = gc.Suite(&checkerSuite{})
+
+// store stands for code under test that should pass its caller's
+// context on to its backend.
+type store struct {
+	backend func(ctx context.Context, key string) error
+}
+
+func (s store) Get(ctx context.Context, key string) error {
+	return s.backend(ctx, key)
+}
+
+func (s store) GetDetached(ctx context.Context, key string) error {
+	return s.backend(context.Background(), key)
+}
+
+func (*checkerSuite) TestDeadlineWithin(c *gc.C) {
+	r := ctxtesting.NewRecorder(nil)
+	s := store{backend: ctxtesting.Wrap(r, "backend", func(context.Context, string) error { return nil })}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.Get(ctx, "foo")
+	s.GetDetached(ctx, "foo")
+
+	calls := r.Calls()
+	c.Assert(calls[0], ctxtesting.DeadlineWithin, 5*time.Second, testing.LongWait)
+	c.Assert(calls[1], ctxtesting.HasNoDeadline)
+
+	result, msg := ctxtesting.DeadlineWithin.Check([]interface{}{calls[1], 5 * time.Second, time.Second}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, "context of backend has no deadline")
+}
+
+func (*checkerSuite) TestDeadlineWithinTolerance(c *gc.C) {
+	clk := testclock.NewClock(start)
+	r := ctxtesting.NewRecorder(clk)
+	ctx, cancel := context.WithDeadline(context.Background(), start.Add(5*time.Second))
+	defer cancel()
+	call := r.Record("Get", ctx)
+
+	c.Check(call, ctxtesting.DeadlineWithin, 5*time.Second, time.Duration(0))
+	c.Check(call, ctxtesting.DeadlineWithin, 4900*time.Millisecond, 100*time.Millisecond)
+	result, msg := ctxtesting.DeadlineWithin.Check([]interface{}{call, 4 * time.Second, 500 * time.Millisecond}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, "context of Get has deadline 5s after call, expected 4s±500ms")
+	result, msg = ctxtesting.HasNoDeadline.Check([]interface{}{call}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, "context of Get has deadline 5s after call")
+
+	result, msg = ctxtesting.DeadlineWithin.Check([]interface{}{call, 4, time.Second}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, "expected value must be of type time.Duration, got int")
+	result, msg = ctxtesting.DeadlineWithin.Check([]interface{}{ctx, time.Second, time.Second}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, "obtained value must be of type *ctxtesting.Call, got *context.timerCtx")
+}
+
+func (*checkerSuite) TestCancelledWithin(c *gc.C) {
+	r := ctxtesting.NewRecorder(nil)
+	s := store{backend: ctxtesting.Wrap(r, "backend", func(context.Context, string) error { return nil })}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Get(ctx, "foo")
+	cancel()
+	c.Assert(ctxtesting.CheckCancelledWithin(c, r.Calls()[0], testing.LongWait), jc.IsTrue)
+}
+
+// detachedSuite is run by the tests of checkerSuite rather than being
+// registered with gocheck.
+type detachedSuite struct{}
+
+func (*detachedSuite) TestDetached(c *gc.C) {
+	r := ctxtesting.NewRecorder(nil)
+	s := store{backend: ctxtesting.Wrap(r, "backend", func(context.Context, string) error { return nil })}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.GetDetached(ctx, "foo")
+	cancel()
+	ctxtesting.AssertCancelledWithin(c, r.Calls()[0], testing.ShortWait)
+	c.Errorf("not reached")
+}
+
+func (*checkerSuite) TestNotCancelledWithin(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&detachedSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, `(?s).*\.\.\. Error: context of backend not cancelled within 50ms\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*not reached.*`)
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package ctxtesting records the contexts passed to the functions and
+// methods of code under test, and provides checkers for making
+// assertions about their deadlines and cancellation, so that tests can
+// catch code that drops a caller's deadline or fails to pass on its
+// cancellation.
+package ctxtesting
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// Recorder records the contexts passed to the functions it wraps.
+type Recorder struct {
+	clock clock.Clock
+
+	mu    sync.Mutex
+	calls []*Call
+	// changed is closed, and replaced, whenever a call is recorded.
+	changed chan struct{}
+}
+
+// NewRecorder returns a Recorder that timestamps calls using clk, or
+// clock.WallClock if it is nil. As the deadlines of contexts created
+// with context.WithTimeout are measured by the wall clock, another
+// clock should only be used if the code under test sets deadlines
+// with it.
+func NewRecorder(clk clock.Clock) *Recorder {
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	return &Recorder{
+		clock:   clk,
+		changed: make(chan struct{}),
+	}
+}
+
+// Call holds a context recorded by a Recorder.
+type Call struct {
+	// Name holds the name given to Record.
+	Name string
+
+	// Time holds the time of the call.
+	Time time.Time
+
+	// Deadline holds the deadline of the context, and HasDeadline
+	// whether it has one.
+	Deadline    time.Time
+	HasDeadline bool
+
+	// Context holds the recorded context.
+	Context context.Context
+
+	clock clock.Clock
+
+	mu sync.Mutex
+	// doneAt holds the time the context was seen to be done, and err
+	// its error then.
+	doneAt time.Time
+	err    error
+	done   chan struct{}
+}
+
+// Record records a call of the named function or method with the given
+// context, and returns it. A wrapper for an interface calls it in each
+// method before calling the wrapped implementation:
+//
+//	func (s *recordingStore) Get(ctx context.Context, key string) (string, error) {
+//		s.recorder.Record("Get", ctx)
+//		return s.Store.Get(ctx, key)
+//	}
+func (r *Recorder) Record(name string, ctx context.Context) *Call {
+	call := &Call{
+		Name:    name,
+		Time:    r.clock.Now(),
+		Context: ctx,
+		clock:   r.clock,
+		done:    make(chan struct{}),
+	}
+	call.Deadline, call.HasDeadline = ctx.Deadline()
+	// context.AfterFunc starts no goroutine until the context is done,
+	// so contexts that are never cancelled do not leak one.
+	context.AfterFunc(ctx, call.markDone)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+	close(r.changed)
+	r.changed = make(chan struct{})
+	return call
+}
+
+// Wrap returns a function that records its context, under the given
+// name, and then calls f, which must be a function whose first
+// parameter is a context.Context. It panics otherwise. Calls made with
+// a nil context are not recorded. For example:
+//
+//	fetch := ctxtesting.Wrap(r, "fetch", client.Fetch)
+func Wrap[F any](r *Recorder, name string, f F) F {
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	if ft.Kind() != reflect.Func || ft.NumIn() == 0 || ft.In(0) != ctxType {
+		panic(fmt.Errorf("cannot wrap %T: first parameter is not a context.Context", f))
+	}
+	if fv.IsNil() {
+		panic(fmt.Errorf("cannot wrap nil %T", f))
+	}
+	wrapped := reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		ctx, _ := args[0].Interface().(context.Context)
+		if ctx != nil {
+			r.Record(name, ctx)
+		}
+		if ft.IsVariadic() {
+			return fv.CallSlice(args)
+		}
+		return fv.Call(args)
+	})
+	return wrapped.Interface().(F)
+}
+
+// Calls returns the calls recorded so far, in the order they were
+// made.
+func (r *Recorder) Calls() []*Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Call(nil), r.calls...)
+}
+
+// WaitCalls waits until at least n calls have been recorded, and
+// returns the calls recorded. The test fails if they are not recorded
+// within the given timeout, measured by the wall clock.
+func (r *Recorder) WaitCalls(c *gc.C, n int, timeout time.Duration) []*Call {
+	deadline := time.After(timeout)
+	for {
+		r.mu.Lock()
+		calls := append([]*Call(nil), r.calls...)
+		changed := r.changed
+		r.mu.Unlock()
+		if len(calls) >= n {
+			return calls
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			c.Fatalf("got %d calls after waiting %s: wanted %d", len(calls), timeout, n)
+		}
+	}
+}
+
+// markDone records that the call's context is done.
+func (call *Call) markDone() {
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	call.doneAt = call.clock.Now()
+	call.err = call.Context.Err()
+	close(call.done)
+}
+
+// DoneAt returns the time the call's context was seen to be done, or
+// false if it is not yet done.
+func (call *Call) DoneAt() (time.Time, bool) {
+	select {
+	case <-call.done:
+	default:
+		return time.Time{}, false
+	}
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	return call.doneAt, true
+}
+
+// Err returns the error of the call's context when it was seen to be
+// done, or nil if it is not yet done.
+func (call *Call) Err() error {
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	return call.err
+}
+
+// String returns a description of the call and its context, such as
+// "fetch with deadline 5s after call, done (context canceled) 1.2s
+// after call".
+func (call *Call) String() string {
+	desc := call.Name
+	if call.HasDeadline {
+		desc += fmt.Sprintf(" with deadline %v after call", call.Deadline.Sub(call.Time))
+	} else {
+		desc += " with no deadline"
+	}
+	if at, ok := call.DoneAt(); ok {
+		desc += fmt.Sprintf(", done (%v) %v after call", call.Err(), at.Sub(call.Time))
+	}
+	return desc
+}
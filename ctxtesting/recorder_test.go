@@ -0,0 +1,102 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package ctxtesting_test
+
+import (
+	"context"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/ctxtesting"
+	"github.com/juju/testing/testclock"
+)
+
+type recorderSuite struct{}
+
+var _ = gc.Suite(&recorderSuite{})
+
+// start is the time of the test clocks, an hour in the future so that
+// contexts with deadlines measured from it are not done before the
+// tests cancel them.
+var start = time.Now().Add(time.Hour)
+
+func (*recorderSuite) TestRecord(c *gc.C) {
+	clk := testclock.NewClock(start)
+	r := ctxtesting.NewRecorder(clk)
+	ctx, cancel := context.WithDeadline(context.Background(), start.Add(5*time.Second))
+	call := r.Record("Get", ctx)
+	c.Assert(call.Name, gc.Equals, "Get")
+	c.Assert(call.Time, gc.Equals, start)
+	c.Assert(call.Context, gc.Equals, ctx)
+	c.Assert(call.HasDeadline, jc.IsTrue)
+	c.Assert(call.Deadline, gc.Equals, start.Add(5*time.Second))
+	_, done := call.DoneAt()
+	c.Assert(done, jc.IsFalse)
+	c.Assert(call.Err(), jc.ErrorIsNil)
+	c.Assert(call.String(), gc.Equals, "Get with deadline 5s after call")
+
+	clk.Advance(time.Second)
+	cancel()
+	ctxtesting.AssertCancelledWithin(c, call, testing.LongWait)
+	at, done := call.DoneAt()
+	c.Assert(done, jc.IsTrue)
+	c.Assert(at, gc.Equals, start.Add(time.Second))
+	c.Assert(call.Err(), gc.Equals, context.Canceled)
+	c.Assert(call.String(), gc.Equals, "Get with deadline 5s after call, done (context canceled) 1s after call")
+	c.Assert(r.Calls(), jc.DeepEquals, []*ctxtesting.Call{call})
+}
+
+func (*recorderSuite) TestWrap(c *gc.C) {
+	r := ctxtesting.NewRecorder(nil)
+	get := ctxtesting.Wrap(r, "get", func(ctx context.Context, key string) (string, error) {
+		return "value of " + key, nil
+	})
+	join := ctxtesting.Wrap(r, "join", func(ctx context.Context, parts ...string) int {
+		return len(parts)
+	})
+	v, err := get(context.Background(), "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(v, gc.Equals, "value of foo")
+	c.Assert(join(context.Background(), "a", "b"), gc.Equals, 2)
+	// Calls with a nil context are passed on but not recorded.
+	c.Assert(join(nil), gc.Equals, 0)
+
+	calls := r.Calls()
+	c.Assert(calls, gc.HasLen, 2)
+	c.Assert(calls[0].Name, gc.Equals, "get")
+	c.Assert(calls[0].HasDeadline, jc.IsFalse)
+	c.Assert(calls[1].Name, gc.Equals, "join")
+}
+
+func (*recorderSuite) TestWrapBadFunc(c *gc.C) {
+	r := ctxtesting.NewRecorder(nil)
+	c.Assert(func() { ctxtesting.Wrap(r, "f", func(string) {}) }, gc.PanicMatches,
+		`cannot wrap func\(string\): first parameter is not a context.Context`)
+	c.Assert(func() { ctxtesting.Wrap(r, "f", 1) }, gc.PanicMatches,
+		`cannot wrap int: first parameter is not a context.Context`)
+	var f func(context.Context)
+	c.Assert(func() { ctxtesting.Wrap(r, "f", f) }, gc.PanicMatches,
+		`cannot wrap nil func\(context.Context\)`)
+}
+
+func (*recorderSuite) TestWaitCalls(c *gc.C) {
+	r := ctxtesting.NewRecorder(nil)
+	go func() {
+		r.Record("a", context.Background())
+		r.Record("b", context.Background())
+	}()
+	calls := r.WaitCalls(c, 2, testing.LongWait)
+	c.Assert(calls, gc.HasLen, 2)
+	c.Assert(calls[1].Name, gc.Equals, "b")
+}
+
+func (*recorderSuite) TestWaitCallsTimeout(c *gc.C) {
+	r := ctxtesting.NewRecorder(nil)
+	r.Record("a", context.Background())
+	c.ExpectFailure("too few calls")
+	r.WaitCalls(c, 2, testing.ShortWait)
+}
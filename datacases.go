@@ -0,0 +1,261 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// testdataDir holds the testdata directory of the package being
+// tested. It is found when the package is initialised, as tests may
+// change the working directory.
+var testdataDir = func() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "testdata"
+	}
+	return filepath.Join(wd, "testdata")
+}()
+
+// dataChecker is a checker that may be named by a DataCase.
+type dataChecker struct {
+	checker gc.Checker
+
+	// typed reports whether the expected value is decoded into the
+	// type of the obtained value before being checked, as the checker
+	// compares values of the same type.
+	typed bool
+}
+
+// dataCheckers holds the checkers that may be named by a DataCase.
+var dataCheckers = map[string]dataChecker{
+	"Equals":       {gc.Equals, true},
+	"DeepEquals":   {jc.DeepEquals, true},
+	"SameContents": {jc.SameContents, true},
+	"Matches":      {gc.Matches, false},
+	"ErrorMatches": {gc.ErrorMatches, false},
+	"HasLen":       {gc.HasLen, false},
+	"Contains":     {jc.Contains, false},
+	"HasPrefix":    {jc.HasPrefix, false},
+	"HasSuffix":    {jc.HasSuffix, false},
+	"JSONEquals":   {jc.JSONEquals, false},
+	"YAMLEquals":   {jc.YAMLEquals, false},
+	"IsNil":        {gc.IsNil, false},
+	"NotNil":       {gc.NotNil, false},
+	"ErrorIsNil":   {jc.ErrorIsNil, false},
+	"IsTrue":       {jc.IsTrue, false},
+	"IsFalse":      {jc.IsFalse, false},
+}
+
+// DataCase is a test case loaded from a testdata file by LoadDataCases,
+// holding the input of the code under test, the expected result, and
+// the name of the checker that compares the result with it.
+type DataCase struct {
+	// About describes the test case. If it is not given in the file,
+	// it is set to the file name and index of the case.
+	About string `yaml:"about" json:"about"`
+
+	// Tags holds the tags of the test case, which RunTable uses to
+	// skip cases not selected by the current tag filter.
+	Tags []string `yaml:"tags" json:"tags"`
+
+	// Input holds the input of the code under test. It is decoded
+	// into a Go value with DecodeInput.
+	Input interface{} `yaml:"input" json:"input"`
+
+	// Expect holds the expected result.
+	Expect interface{} `yaml:"expect" json:"expect"`
+
+	// Checker holds the name of the gocheck checker used by Check to
+	// compare the obtained result with Expect, such as "DeepEquals"
+	// (the default), "Matches" or "ErrorMatches". Checkers that take
+	// no expected value, such as "ErrorIsNil", ignore Expect.
+	Checker string `yaml:"checker" json:"checker"`
+
+	// XFail holds a reference to the known bug, such as a bug tracker
+	// URL, because of which the case is expected to fail. RunTable
+	// runs such cases with XFail.
+	XFail string `yaml:"xfail" json:"xfail"`
+
+	// File holds the path of the file that the case was loaded from,
+	// relative to the testdata directory.
+	File string `yaml:"-" json:"-"`
+}
+
+// LoadDataCases loads the test cases from the files in the testdata
+// directory of the package being tested that match the given pattern,
+// in order of file name. A file holds a list of cases in YAML, with a
+// ".yaml" or ".yml" extension, or in JSON, with a ".json" extension:
+//
+//	# testdata/sum/cases.yaml
+//	- about: a simple sum
+//	  input: "1 + 2"
+//	  expect: 3
+//	- about: a missing operand
+//	  input: "1 +"
+//	  expect: "unexpected end of input"
+//	  checker: ErrorMatches
+//	  tags: [errors]
+//
+// This allows conformance cases to be added without changing any test
+// code. The test fails if no files match, or if a file holds unknown
+// fields or names an unknown checker.
+func LoadDataCases(c *gc.C, pattern string) []DataCase {
+	paths, err := filepath.Glob(filepath.Join(testdataDir, pattern))
+	c.Assert(err, gc.IsNil)
+	if len(paths) == 0 {
+		c.Fatalf("no test data files match %q in %s", pattern, testdataDir)
+	}
+	sort.Strings(paths)
+	var cases []DataCase
+	for _, path := range paths {
+		cases = append(cases, loadDataFile(c, path)...)
+	}
+	return cases
+}
+
+// RunDataCases loads the test cases with LoadDataCases and runs them
+// with RunTable:
+//
+//	testing.RunDataCases(c, "sum/*.yaml", func(c *gc.C, test testing.DataCase) {
+//		var input string
+//		test.DecodeInput(c, &input)
+//		result, err := sum(input)
+//		if err != nil {
+//			test.Check(c, err)
+//			return
+//		}
+//		test.Check(c, result)
+//	})
+func RunDataCases(c *gc.C, pattern string, f func(c *gc.C, test DataCase)) {
+	RunTable(c, LoadDataCases(c, pattern), f)
+}
+
+// loadDataFile loads the test cases in a single file.
+func loadDataFile(c *gc.C, path string) []DataCase {
+	file, err := filepath.Rel(testdataDir, path)
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, gc.IsNil)
+	var cases []DataCase
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, &cases)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		dec.UseNumber()
+		err = dec.Decode(&cases)
+	default:
+		c.Fatalf("cannot load test cases from %s: unknown file extension %q", file, ext)
+	}
+	c.Assert(err, gc.IsNil, gc.Commentf("cannot load test cases from %s", file))
+	for i := range cases {
+		test := &cases[i]
+		test.File = file
+		if test.About == "" {
+			test.About = fmt.Sprintf("%s[%d]", file, i)
+		}
+		if test.Checker == "" {
+			test.Checker = "DeepEquals"
+		}
+		if _, ok := dataCheckers[test.Checker]; !ok {
+			c.Fatalf("%s: case %d: unknown checker %q", file, i, test.Checker)
+		}
+		test.Input = normaliseData(test.Input)
+		test.Expect = normaliseData(test.Expect)
+	}
+	return cases
+}
+
+// normaliseData converts a value decoded from YAML or JSON so that
+// values decoded from either are the same: maps have string keys, and
+// numbers are int if possible and float64 otherwise.
+func normaliseData(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = normaliseData(value)
+		}
+		return m
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = normaliseData(value)
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = normaliseData(value)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil && int64(int(n)) == n {
+			return int(n)
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// DecodeInput decodes the case's input into the value pointed to by v,
+// as encoding/json would decode it.
+func (test DataCase) DecodeInput(c *gc.C, v interface{}) {
+	err := decodeData(test.Input, v)
+	c.Assert(err, gc.IsNil, gc.Commentf("cannot decode input of %s as %T", test.About, v))
+}
+
+// Check checks the obtained result with the case's checker and expected
+// value, and returns whether the check succeeded. For checkers that
+// compare values of the same type, such as DeepEquals, the expected
+// value is first decoded into the type of the obtained value.
+func (test DataCase) Check(c *gc.C, obtained interface{}) bool {
+	checker := dataCheckers[test.Checker]
+	comment := gc.Commentf("test data %s: %s", test.File, test.About)
+	if len(checker.checker.Info().Params) == 1 {
+		return c.Check(obtained, checker.checker, comment)
+	}
+	expected := test.Expect
+	if checker.typed && obtained != nil {
+		v := reflect.New(reflect.TypeOf(obtained))
+		if err := decodeData(test.Expect, v.Interface()); err != nil {
+			c.Errorf("cannot decode expected value of %s as %T: %v", test.About, obtained, err)
+			return false
+		}
+		expected = v.Elem().Interface()
+	}
+	return c.Check(obtained, checker.checker, expected, comment)
+}
+
+// Assert is like Check, but stops the test if the check fails.
+func (test DataCase) Assert(c *gc.C, obtained interface{}) {
+	if !test.Check(c, obtained) {
+		c.FailNow()
+	}
+}
+
+// decodeData decodes a value loaded from a test data file into the
+// value pointed to by v.
+func decodeData(data interface{}, v interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
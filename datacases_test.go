@@ -0,0 +1,240 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+type dataCasesSuite struct {
+	CleanupSuite
+	dir string
+}
+
+var _ = gc.Suite(&dataCasesSuite{})
+
+func (s *dataCasesSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.PatchValue(&testdataDir, s.dir)
+	s.PatchEnvironment("TEST_TAGS", "")
+}
+
+func (s *dataCasesSuite) writeFile(c *gc.C, name, data string) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, name), []byte(data), 0644)
+	c.Assert(err, gc.IsNil)
+}
+
+// sum returns the sum of the space-separated integers in s.
+func sum(s string) (int, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, errors.New("no numbers")
+	}
+	total := 0
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+const sumYAML = `
+- about: one number
+  input: "1"
+  expect: 1
+- input: "1 2 3"
+  expect: 6
+  checker: Equals
+- about: no numbers
+  input: ""
+  expect: no numbers
+  checker: ErrorMatches
+- about: slow
+  input: "4"
+  expect: 4
+  tags: [slow]
+`
+
+const sumJSON = `[
+	{"about": "negative", "input": "-1 -2", "expect": -3},
+	{"about": "not a number", "input": "1 x", "expect": ".*invalid syntax", "checker": "ErrorMatches"}
+]`
+
+func (s *dataCasesSuite) TestLoadDataCases(c *gc.C) {
+	s.writeFile(c, "sum.yaml", sumYAML)
+	s.writeFile(c, "sum.json", sumJSON)
+	cases := LoadDataCases(c, "sum.*")
+	c.Assert(cases, jc.DeepEquals, []DataCase{{
+		About:   "negative",
+		Input:   "-1 -2",
+		Expect:  -3,
+		Checker: "DeepEquals",
+		File:    "sum.json",
+	}, {
+		About:   "not a number",
+		Input:   "1 x",
+		Expect:  ".*invalid syntax",
+		Checker: "ErrorMatches",
+		File:    "sum.json",
+	}, {
+		About:   "one number",
+		Input:   "1",
+		Expect:  1,
+		Checker: "DeepEquals",
+		File:    "sum.yaml",
+	}, {
+		About:   "sum.yaml[1]",
+		Input:   "1 2 3",
+		Expect:  6,
+		Checker: "Equals",
+		File:    "sum.yaml",
+	}, {
+		About:   "no numbers",
+		Input:   "",
+		Expect:  "no numbers",
+		Checker: "ErrorMatches",
+		File:    "sum.yaml",
+	}, {
+		About:   "slow",
+		Tags:    []string{"slow"},
+		Input:   "4",
+		Expect:  4,
+		Checker: "DeepEquals",
+		File:    "sum.yaml",
+	}})
+}
+
+func (s *dataCasesSuite) TestRunDataCases(c *gc.C) {
+	s.PatchEnvironment("TEST_TAGS", "!slow")
+	s.writeFile(c, "sum.yaml", sumYAML)
+	s.writeFile(c, "sum.json", sumJSON)
+	var ran []string
+	RunDataCases(c, "sum.*", func(c *gc.C, test DataCase) {
+		ran = append(ran, test.About)
+		var input string
+		test.DecodeInput(c, &input)
+		result, err := sum(input)
+		if err != nil {
+			test.Check(c, err)
+			return
+		}
+		test.Check(c, result)
+	})
+	c.Assert(ran, jc.DeepEquals, []string{"negative", "not a number", "one number", "sum.yaml[1]", "no numbers"})
+}
+
+type size struct {
+	Width, Height int
+}
+
+func (s *dataCasesSuite) TestStructuredData(c *gc.C) {
+	s.writeFile(c, "sizes.yaml", `
+- input: {width: 1, height: 2}
+  expect: {width: 2, height: 4}
+- input: [{width: 1}, {height: 1}]
+  expect: 2
+  checker: HasLen
+- input: {width: 0}
+  checker: ErrorIsNil
+`)
+	cases := LoadDataCases(c, "sizes.yaml")
+	c.Assert(cases, gc.HasLen, 3)
+
+	var sz size
+	cases[0].DecodeInput(c, &sz)
+	c.Assert(sz, gc.Equals, size{1, 2})
+	c.Assert(cases[0].Check(c, size{sz.Width * 2, sz.Height * 2}), gc.Equals, true)
+
+	var sizes []size
+	cases[1].DecodeInput(c, &sizes)
+	c.Assert(sizes, jc.DeepEquals, []size{{Width: 1}, {Height: 1}})
+	c.Assert(cases[1].Check(c, sizes), gc.Equals, true)
+
+	c.Assert(cases[2].Check(c, nil), gc.Equals, true)
+}
+
+// dataCasesRunnerSuite runs a single test function so that its
+// failures can be checked. It is run by dataCasesSuite rather than
+// being registered with gocheck.
+type dataCasesRunnerSuite struct {
+	f func(c *gc.C)
+}
+
+func (s *dataCasesRunnerSuite) TestRun(c *gc.C) {
+	s.f(c)
+}
+
+func runDataCasesFailure(c *gc.C, f func(c *gc.C)) string {
+	var output bytes.Buffer
+	result := gc.Run(&dataCasesRunnerSuite{f}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	return output.String()
+}
+
+var dataCasesErrorTests = []struct {
+	about  string
+	file   string
+	data   string
+	expect string
+}{{
+	about:  "unknown checker",
+	file:   "cases.yaml",
+	data:   "- {input: 1, checker: Frobs}",
+	expect: `(?s).*cases.yaml: case 0: unknown checker "Frobs".*`,
+}, {
+	about:  "unknown YAML field",
+	file:   "cases.yaml",
+	data:   "- {input: 1, expected: 1}",
+	expect: `(?s).*field expected not found.*cannot load test cases from cases.yaml.*`,
+}, {
+	about:  "unknown JSON field",
+	file:   "cases.json",
+	data:   `[{"input": 1, "expected": 1}]`,
+	expect: `(?s).*unknown field .*expected.*cannot load test cases from cases.json.*`,
+}, {
+	about:  "unknown extension",
+	file:   "cases.txt",
+	data:   "",
+	expect: `(?s).*cannot load test cases from cases.txt: unknown file extension ".txt".*`,
+}}
+
+func (s *dataCasesSuite) TestLoadDataCasesErrors(c *gc.C) {
+	for i, test := range dataCasesErrorTests {
+		c.Logf("test %d: %s", i, test.about)
+		s.writeFile(c, test.file, test.data)
+		output := runDataCasesFailure(c, func(c *gc.C) {
+			LoadDataCases(c, test.file)
+		})
+		c.Check(output, gc.Matches, test.expect)
+	}
+}
+
+func (s *dataCasesSuite) TestLoadDataCasesNoFiles(c *gc.C) {
+	output := runDataCasesFailure(c, func(c *gc.C) {
+		LoadDataCases(c, "*.yaml")
+	})
+	c.Assert(output, gc.Matches, `(?s).*no test data files match "\*.yaml" in .*`)
+}
+
+func (s *dataCasesSuite) TestCheckFailure(c *gc.C) {
+	s.writeFile(c, "cases.yaml", "- {about: wrong, expect: 3}")
+	test := LoadDataCases(c, "cases.yaml")[0]
+	output := runDataCasesFailure(c, func(c *gc.C) {
+		test.Check(c, 4)
+	})
+	c.Assert(output, gc.Matches, `(?s).*obtained int = 4\n.*expected int = 3\n.*test data cases.yaml: wrong.*`)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/juju/loggo"
+	gc "gopkg.in/check.v1"
+)
+
+// checkDeprecations checks the entries logged and the output written
+// to stderr during a test for the suite's deprecation markers.
+func (s *LoggingSuite) checkDeprecations(c *gc.C, entries []loggo.Entry, stderr []byte) {
+	markers := make([]*regexp.Regexp, len(s.DeprecationMarkers))
+	for i, marker := range s.DeprecationMarkers {
+		re, err := regexp.Compile(marker)
+		if err != nil {
+			c.Errorf("bad deprecation marker %q: %v", marker, err)
+			return
+		}
+		markers[i] = re
+	}
+	matches := func(s string) bool {
+		for _, re := range markers {
+			if re.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+	var found []string
+	for _, entry := range entries {
+		if matches(entry.Message) {
+			found = append(found, fmt.Sprintf("log: %s %s %s", entry.Level, entry.Module, entry.Message))
+		}
+	}
+	for _, line := range strings.Split(string(stderr), "\n") {
+		if line != "" && matches(line) {
+			found = append(found, "stderr: "+line)
+		}
+	}
+	if len(found) == 0 {
+		return
+	}
+	msg := fmt.Sprintf("deprecated code used:\n  %s", strings.Join(found, "\n  "))
+	if s.ReportDeprecations {
+		c.Logf("%s", msg)
+	} else {
+		c.Errorf("%s", msg)
+	}
+}
+
+// stderrCapture records what is written to os.Stderr, while still
+// passing it through.
+type stderrCapture struct {
+	orig *os.File
+	w    *os.File
+	buf  bytes.Buffer
+	done chan struct{}
+}
+
+// captureStderr replaces os.Stderr with a pipe from which the output is
+// copied to the original os.Stderr and recorded. Only writes made
+// through the os.Stderr variable are captured, so not, for example,
+// the output of subprocesses given the original file.
+func captureStderr() (*stderrCapture, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	capture := &stderrCapture{
+		orig: os.Stderr,
+		w:    w,
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(capture.done)
+		defer r.Close()
+		io.Copy(io.MultiWriter(&capture.buf, capture.orig), r)
+	}()
+	os.Stderr = w
+	return capture, nil
+}
+
+// stop restores os.Stderr and returns what was written to it.
+func (capture *stderrCapture) stop() []byte {
+	os.Stderr = capture.orig
+	capture.w.Close()
+	<-capture.done
+	return capture.buf.Bytes()
+}
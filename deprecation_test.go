@@ -0,0 +1,75 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/juju/loggo"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+type deprecationSuite struct{}
+
+var _ = gc.Suite(&deprecationSuite{})
+
+// deprecatedSuite is run by the tests of deprecationSuite rather than
+// being registered with gocheck.
+type deprecatedSuite struct {
+	LoggingSuite
+}
+
+func (*deprecatedSuite) TestCurrent(c *gc.C) {
+	loggo.GetLogger("test").Infof("using the current API")
+}
+
+func (*deprecatedSuite) TestDeprecated(c *gc.C) {
+	loggo.GetLogger("test").Warningf("Client.Dial is deprecated; use Client.Connect")
+	log.Printf("Open is deprecated")
+	fmt.Fprintln(os.Stderr, "Flag --old-format has been deprecated, use --format")
+}
+
+func (*deprecationSuite) TestFailsOnDeprecations(c *gc.C) {
+	orig := os.Stderr
+	var output bytes.Buffer
+	result := gc.Run(&deprecatedSuite{LoggingSuite{
+		DeprecationMarkers: []string{`(?i)\bdeprecated\b`},
+	}}, &gc.RunConf{Output: &output})
+	c.Assert(result.Passed(), jc.IsFalse)
+	c.Assert(output.String(), gc.Matches, `(?s).*deprecatedSuite\.TearDownTest\n.*`+
+		`\.\.\. Error: deprecated code used:\n`+
+		`  log: WARNING test Client\.Dial is deprecated; use Client\.Connect\n`+
+		`  log: INFO stdlog Open is deprecated\n`+
+		`  stderr: Flag --old-format has been deprecated, use --format\n.*`+
+		`PANIC: .*deprecatedSuite\.TestDeprecated\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*log: INFO test using the current API.*`)
+	c.Assert(os.Stderr, gc.Equals, orig)
+}
+
+func (*deprecationSuite) TestReportDeprecations(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&deprecatedSuite{LoggingSuite{
+		DeprecationMarkers: []string{"deprecated"},
+		ReportDeprecations: true,
+	}}, &gc.RunConf{Output: &output, Stream: true})
+	c.Assert(result.Passed(), jc.IsTrue)
+	c.Assert(output.String(), gc.Matches, `(?s).*deprecated code used:\n`+
+		`  log: WARNING test Client\.Dial is deprecated; use Client\.Connect\n`+
+		`  log: INFO stdlog Open is deprecated\n`+
+		`  stderr: Flag --old-format has been deprecated, use --format\n.*`)
+}
+
+func (*deprecationSuite) TestBadMarker(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&deprecatedSuite{LoggingSuite{
+		DeprecationMarkers: []string{"["},
+	}}, &gc.RunConf{Output: &output})
+	c.Assert(result.Passed(), jc.IsFalse)
+	c.Assert(output.String(), gc.Matches, `(?s).*bad deprecation marker "\[": .*`)
+}
@@ -0,0 +1,291 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package dnstesting provides a fake DNS server for testing code that
+// resolves names with a net.Resolver.
+package dnstesting
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/juju/testing"
+)
+
+// Query records a question asked of a Resolver.
+type Query struct {
+	// Name holds the fully qualified name queried, without
+	// the trailing dot.
+	Name string
+
+	// Type holds the record type queried, such as "A", "AAAA",
+	// "SRV" or "TXT".
+	Type string
+}
+
+// Resolver answers DNS queries from programmed records. Use
+// NetResolver to obtain a net.Resolver that sends its queries to
+// it, or PatchDefaultResolver to install that as net.DefaultResolver.
+//
+// A name with no records at all is reported as not existing
+// (NXDOMAIN). A name with records, but none of the queried type,
+// gets an empty answer.
+//
+// Note that the Go resolver consults /etc/hosts before sending any
+// queries, and appends the search domains from /etc/resolv.conf to
+// names with few dots, so tests should use fully qualified names
+// that are not in /etc/hosts.
+type Resolver struct {
+	mu      sync.Mutex
+	names   map[string]*records
+	latency time.Duration
+	queries []Query
+}
+
+type records struct {
+	a    []net.IP
+	aaaa []net.IP
+	srv  []*net.SRV
+	txt  []string
+}
+
+// NewResolver returns a Resolver with no records.
+func NewResolver() *Resolver {
+	return &Resolver{
+		names: make(map[string]*records),
+	}
+}
+
+// AddHost adds address records for the given name. IPv4 addresses
+// are returned for A queries and IPv6 addresses for AAAA queries.
+// It panics if an address cannot be parsed.
+func (r *Resolver) AddHost(name string, addrs ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recs := r.recordsFor(name)
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			panic("invalid IP address " + addr)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			recs.a = append(recs.a, ip4)
+		} else {
+			recs.aaaa = append(recs.aaaa, ip)
+		}
+	}
+}
+
+// AddSRV adds SRV records for the given name, which should include
+// the service and protocol labels, for example "_ldap._tcp.example.com".
+func (r *Resolver) AddSRV(name string, srvs ...*net.SRV) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recs := r.recordsFor(name)
+	recs.srv = append(recs.srv, srvs...)
+}
+
+// AddTXT adds a TXT record for each of the given strings.
+func (r *Resolver) AddTXT(name string, txts ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recs := r.recordsFor(name)
+	recs.txt = append(recs.txt, txts...)
+}
+
+// Remove removes all the records for the given name, so that it is
+// subsequently reported as not existing.
+func (r *Resolver) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.names, canonicalName(name))
+}
+
+// SetLatency sets the time taken to answer each query. The
+// net.Resolver's context deadline applies as usual, so this can be
+// used to test timeouts.
+func (r *Resolver) SetLatency(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latency = d
+}
+
+// Queries returns the queries received so far, in order.
+func (r *Resolver) Queries() []Query {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Query(nil), r.queries...)
+}
+
+// QueriedNames returns the distinct names queried so far, in the
+// order they were first queried.
+func (r *Resolver) QueriedNames() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var names []string
+	seen := make(map[string]bool)
+	for _, q := range r.queries {
+		if !seen[q.Name] {
+			seen[q.Name] = true
+			names = append(names, q.Name)
+		}
+	}
+	return names
+}
+
+// ResetQueries discards the queries recorded so far.
+func (r *Resolver) ResetQueries() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = nil
+}
+
+// NetResolver returns a net.Resolver that sends all its queries to r.
+func (r *Resolver) NetResolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(_ context.Context, network, address string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go r.serve(server)
+			return client, nil
+		},
+	}
+}
+
+// PatchDefaultResolver sets net.DefaultResolver to r.NetResolver()
+// and returns a function that restores the original.
+func (r *Resolver) PatchDefaultResolver() testing.Restorer {
+	return testing.PatchValue(&net.DefaultResolver, r.NetResolver())
+}
+
+func (r *Resolver) recordsFor(name string) *records {
+	name = canonicalName(name)
+	recs := r.names[name]
+	if recs == nil {
+		recs = &records{}
+		r.names[name] = recs
+	}
+	return recs
+}
+
+// serve answers queries on conn until it is closed. The connection
+// is not a net.PacketConn, so the Go resolver uses the TCP framing
+// of a two byte length before each message.
+func (r *Resolver) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		resp, err := r.answer(req)
+		if err != nil {
+			return
+		}
+		r.mu.Lock()
+		latency := r.latency
+		r.mu.Unlock()
+		if latency > 0 {
+			time.Sleep(latency)
+		}
+		binary.BigEndian.PutUint16(length[:], uint16(len(resp)))
+		if _, err := conn.Write(append(length[:], resp...)); err != nil {
+			return
+		}
+	}
+}
+
+// answer returns the response to the given DNS request message.
+func (r *Resolver) answer(req []byte) ([]byte, error) {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(req); err != nil {
+		return nil, err
+	}
+	resp := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 msg.ID,
+			Response:           true,
+			Authoritative:      true,
+			RecursionDesired:   msg.RecursionDesired,
+			RecursionAvailable: true,
+		},
+		Questions: msg.Questions,
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, q := range msg.Questions {
+		name := canonicalName(q.Name.String())
+		r.queries = append(r.queries, Query{
+			Name: name,
+			Type: strings.TrimPrefix(q.Type.String(), "Type"),
+		})
+		recs := r.names[name]
+		if recs == nil {
+			resp.RCode = dnsmessage.RCodeNameError
+			continue
+		}
+		hdr := dnsmessage.ResourceHeader{
+			Name:  q.Name,
+			Class: dnsmessage.ClassINET,
+			TTL:   60,
+		}
+		switch q.Type {
+		case dnsmessage.TypeA:
+			for _, ip := range recs.a {
+				var body dnsmessage.AResource
+				copy(body.A[:], ip)
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &body})
+			}
+		case dnsmessage.TypeAAAA:
+			for _, ip := range recs.aaaa {
+				var body dnsmessage.AAAAResource
+				copy(body.AAAA[:], ip)
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &body})
+			}
+		case dnsmessage.TypeSRV:
+			for _, srv := range recs.srv {
+				target, err := dnsmessage.NewName(fqdn(srv.Target))
+				if err != nil {
+					return nil, err
+				}
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.SRVResource{
+					Priority: srv.Priority,
+					Weight:   srv.Weight,
+					Port:     srv.Port,
+					Target:   target,
+				}})
+			}
+		case dnsmessage.TypeTXT:
+			for _, txt := range recs.txt {
+				resp.Answers = append(resp.Answers, dnsmessage.Resource{Header: hdr, Body: &dnsmessage.TXTResource{
+					TXT: []string{txt},
+				}})
+			}
+		}
+	}
+	return resp.Pack()
+}
+
+// canonicalName returns the lower case form of name without any
+// trailing dot.
+func canonicalName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
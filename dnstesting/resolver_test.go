@@ -0,0 +1,103 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package dnstesting_test
+
+import (
+	"context"
+	"net"
+	"sort"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/dnstesting"
+)
+
+type resolverSuite struct {
+	testing.IsolationSuite
+	resolver *dnstesting.Resolver
+	ctx      context.Context
+}
+
+var _ = gc.Suite(&resolverSuite{})
+
+func (s *resolverSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.resolver = dnstesting.NewResolver()
+	ctx, cancel := context.WithTimeout(context.Background(), testing.LongWait)
+	s.AddCleanup(func(*gc.C) { cancel() })
+	s.ctx = ctx
+}
+
+func (s *resolverSuite) TestLookupHost(c *gc.C) {
+	s.resolver.AddHost("db.example.test", "10.0.0.1", "10.0.0.2", "2001:db8::1")
+	addrs, err := s.resolver.NetResolver().LookupHost(s.ctx, "db.example.test.")
+	c.Assert(err, jc.ErrorIsNil)
+	sort.Strings(addrs)
+	c.Assert(addrs, jc.DeepEquals, []string{"10.0.0.1", "10.0.0.2", "2001:db8::1"})
+	c.Assert(s.resolver.QueriedNames(), jc.DeepEquals, []string{"db.example.test"})
+	c.Assert(s.resolver.Queries(), jc.SameContents, []dnstesting.Query{
+		{Name: "db.example.test", Type: "A"},
+		{Name: "db.example.test", Type: "AAAA"},
+	})
+}
+
+func (s *resolverSuite) TestNXDOMAIN(c *gc.C) {
+	_, err := s.resolver.NetResolver().LookupHost(s.ctx, "missing.example.test.")
+	c.Assert(err, gc.FitsTypeOf, &net.DNSError{})
+	c.Assert(err.(*net.DNSError).IsNotFound, jc.IsTrue)
+
+	s.resolver.AddHost("gone.example.test", "10.0.0.1")
+	s.resolver.Remove("gone.example.test")
+	_, err = s.resolver.NetResolver().LookupHost(s.ctx, "gone.example.test.")
+	c.Assert(err.(*net.DNSError).IsNotFound, jc.IsTrue)
+}
+
+func (s *resolverSuite) TestLookupSRV(c *gc.C) {
+	s.resolver.AddSRV("_ldap._tcp.example.test",
+		&net.SRV{Target: "ldap1.example.test.", Port: 389, Priority: 10, Weight: 5},
+		&net.SRV{Target: "ldap2.example.test", Port: 636, Priority: 20, Weight: 5},
+	)
+	cname, srvs, err := s.resolver.NetResolver().LookupSRV(s.ctx, "ldap", "tcp", "example.test.")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cname, gc.Equals, "_ldap._tcp.example.test.")
+	c.Assert(srvs, jc.DeepEquals, []*net.SRV{
+		{Target: "ldap1.example.test.", Port: 389, Priority: 10, Weight: 5},
+		{Target: "ldap2.example.test.", Port: 636, Priority: 20, Weight: 5},
+	})
+	c.Assert(s.resolver.Queries(), jc.DeepEquals, []dnstesting.Query{
+		{Name: "_ldap._tcp.example.test", Type: "SRV"},
+	})
+}
+
+func (s *resolverSuite) TestLookupTXT(c *gc.C) {
+	s.resolver.AddTXT("example.test", "v=spf1 -all", "hello")
+	txts, err := s.resolver.NetResolver().LookupTXT(s.ctx, "example.test.")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(txts, jc.DeepEquals, []string{"v=spf1 -all", "hello"})
+}
+
+func (s *resolverSuite) TestLatency(c *gc.C) {
+	s.resolver.AddHost("slow.example.test", "10.0.0.1")
+	s.resolver.SetLatency(time.Second)
+	ctx, cancel := context.WithTimeout(s.ctx, testing.ShortWait)
+	defer cancel()
+	_, err := s.resolver.NetResolver().LookupHost(ctx, "slow.example.test.")
+	c.Assert(err, gc.FitsTypeOf, &net.DNSError{})
+	c.Assert(err.(*net.DNSError).IsTimeout, jc.IsTrue)
+}
+
+func (s *resolverSuite) TestPatchDefaultResolver(c *gc.C) {
+	s.resolver.AddHost("api.example.test", "192.0.2.7")
+	restore := s.resolver.PatchDefaultResolver()
+	addrs, err := net.DefaultResolver.LookupHost(s.ctx, "api.example.test.")
+	restore()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"192.0.2.7"})
+
+	s.resolver.ResetQueries()
+	c.Assert(s.resolver.Queries(), gc.HasLen, 0)
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build dqlite
+
+package dqlitetesting
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/canonical/go-dqlite/app"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+// Node is a member of a Cluster.
+type Node struct {
+	// Dir holds the node's data directory, which persists when
+	// the node is stopped, so that it can be restarted.
+	Dir string
+
+	// Address holds the loopback address on which the node
+	// listens.
+	Address string
+
+	app *app.App
+}
+
+// Running reports whether the node is running.
+func (n *Node) Running() bool {
+	return n.app != nil
+}
+
+// Cluster is an in-process dqlite cluster of one to three nodes, each
+// listening on a reserved loopback port and storing its data in a
+// temporary directory:
+//
+//	cluster := dqlitetesting.NewCluster(c, 3)
+//	defer cluster.Close()
+//	db := cluster.Open(c, "test")
+//	... use db ...
+//	cluster.Stop(c, cluster.LeaderIndex(c))
+//	... check that db recovers, once a new leader is elected ...
+//
+// Connections returned by Open follow the leader, so they keep
+// working while a majority of nodes is running.
+type Cluster struct {
+	// Nodes holds the cluster's nodes. The first node bootstrapped
+	// the cluster.
+	Nodes []*Node
+
+	// Timeout holds how long to wait for nodes to become ready and
+	// for a leader to be elected. If it is zero, testing.LongWait
+	// is used.
+	Timeout time.Duration
+}
+
+// NewCluster starts a cluster of n nodes, where n is between 1 and 3,
+// and waits until every node is ready. The caller should call Close
+// when finished with it.
+func NewCluster(c *gc.C, n int) *Cluster {
+	if n < 1 || n > 3 {
+		c.Fatalf("cannot start dqlite cluster of %d nodes; must be 1 to 3", n)
+	}
+	cluster := &Cluster{}
+	for i := 0; i < n; i++ {
+		cluster.Nodes = append(cluster.Nodes, &Node{
+			Dir:     c.MkDir(),
+			Address: net.JoinHostPort("127.0.0.1", strconv.Itoa(testing.ReservePort(c))),
+		})
+	}
+	for i, node := range cluster.Nodes {
+		options := []app.Option{app.WithAddress(node.Address)}
+		if i > 0 {
+			options = append(options, app.WithCluster([]string{cluster.Nodes[0].Address}))
+		}
+		if err := cluster.start(node, options...); err != nil {
+			cluster.Close()
+			c.Fatalf("cannot start dqlite node %d: %v", i, err)
+		}
+	}
+	return cluster
+}
+
+// start starts the node's app and waits until it is ready.
+func (cluster *Cluster) start(node *Node, options ...app.Option) error {
+	a, err := app.New(node.Dir, options...)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cluster.timeout())
+	defer cancel()
+	if err := a.Ready(ctx); err != nil {
+		a.Close()
+		return err
+	}
+	node.app = a
+	return nil
+}
+
+func (cluster *Cluster) timeout() time.Duration {
+	if cluster.Timeout == 0 {
+		return testing.LongWait
+	}
+	return cluster.Timeout
+}
+
+// Open returns a connection to the named database, through the first
+// running node. Queries are sent to whichever node is leader. The
+// caller should close the connection when finished with it.
+func (cluster *Cluster) Open(c *gc.C, name string) *sql.DB {
+	node := cluster.running(c)
+	ctx, cancel := context.WithTimeout(context.Background(), cluster.timeout())
+	defer cancel()
+	db, err := node.app.Open(ctx, name)
+	c.Assert(err, gc.IsNil)
+	return db
+}
+
+// LeaderIndex waits until the cluster has a leader and returns its
+// index in Nodes.
+func (cluster *Cluster) LeaderIndex(c *gc.C) int {
+	node := cluster.running(c)
+	ctx, cancel := context.WithTimeout(context.Background(), cluster.timeout())
+	defer cancel()
+	client, err := node.app.Leader(ctx)
+	c.Assert(err, gc.IsNil, gc.Commentf("no dqlite leader elected"))
+	defer client.Close()
+	info, err := client.Leader(ctx)
+	c.Assert(err, gc.IsNil)
+	for i, node := range cluster.Nodes {
+		if node.Address == info.Address {
+			return i
+		}
+	}
+	c.Fatalf("dqlite leader %q is not a node of the cluster", info.Address)
+	return -1
+}
+
+// Leader waits until the cluster has a leader and returns it.
+func (cluster *Cluster) Leader(c *gc.C) *Node {
+	return cluster.Nodes[cluster.LeaderIndex(c)]
+}
+
+// Stop stops the node with the given index, keeping its data so that
+// it can be restarted. A leader hands over leadership as it stops; to
+// the rest of the cluster, the stopped node is simply unreachable.
+func (cluster *Cluster) Stop(c *gc.C, index int) {
+	node := cluster.Nodes[index]
+	if node.app == nil {
+		c.Fatalf("dqlite node %d is not running", index)
+	}
+	err := node.app.Close()
+	node.app = nil
+	c.Assert(err, gc.IsNil)
+}
+
+// Restart restarts the stopped node with the given index, from its
+// existing data, and waits until it is ready.
+func (cluster *Cluster) Restart(c *gc.C, index int) {
+	node := cluster.Nodes[index]
+	if node.app != nil {
+		c.Fatalf("dqlite node %d is already running", index)
+	}
+	err := cluster.start(node, app.WithAddress(node.Address))
+	c.Assert(err, gc.IsNil, gc.Commentf("cannot restart dqlite node %d", index))
+}
+
+// Close stops every running node.
+func (cluster *Cluster) Close() {
+	// Stop the nodes in reverse order, so that the bootstrap node,
+	// which is often leader, goes last.
+	for i := len(cluster.Nodes) - 1; i >= 0; i-- {
+		if node := cluster.Nodes[i]; node.app != nil {
+			node.app.Close()
+			node.app = nil
+		}
+	}
+}
+
+// running returns the first running node.
+func (cluster *Cluster) running(c *gc.C) *Node {
+	for _, node := range cluster.Nodes {
+		if node.app != nil {
+			return node
+		}
+	}
+	c.Fatalf("no dqlite node is running")
+	return nil
+}
+
+// String returns the addresses of the cluster's nodes.
+func (cluster *Cluster) String() string {
+	addrs := make([]string, len(cluster.Nodes))
+	for i, node := range cluster.Nodes {
+		addrs[i] = node.Address
+		if node.app == nil {
+			addrs[i] += " (stopped)"
+		}
+	}
+	return fmt.Sprint(addrs)
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build dqlite
+
+package dqlitetesting_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/dqlitetesting"
+)
+
+type clusterSuite struct {
+	dqlitetesting.DqliteSuite
+}
+
+var _ = gc.Suite(&clusterSuite{
+	DqliteSuite: dqlitetesting.DqliteSuite{NodeCount: 3},
+})
+
+func (s *clusterSuite) TestLeaderFailover(c *gc.C) {
+	_, err := s.DB.Exec("CREATE TABLE widgets (name TEXT)")
+	c.Assert(err, gc.IsNil)
+	_, err = s.DB.Exec("INSERT INTO widgets VALUES ('sprocket')")
+	c.Assert(err, gc.IsNil)
+
+	leader := s.Cluster.LeaderIndex(c)
+	s.Cluster.Stop(c, leader)
+	c.Assert(s.Cluster.Nodes[leader].Running(), gc.Equals, false)
+	c.Assert(s.Cluster.LeaderIndex(c), gc.Not(gc.Equals), leader)
+
+	// The remaining nodes hold the data.
+	db := s.Cluster.Open(c, "test")
+	defer db.Close()
+	var name string
+	err = db.QueryRow("SELECT name FROM widgets").Scan(&name)
+	c.Assert(err, gc.IsNil)
+	c.Assert(name, gc.Equals, "sprocket")
+
+	s.Cluster.Restart(c, leader)
+	c.Assert(s.Cluster.Nodes[leader].Running(), gc.Equals, true)
+	_, err = db.Exec("INSERT INTO widgets VALUES ('cog')")
+	c.Assert(err, gc.IsNil)
+}
+
+type singleNodeSuite struct{}
+
+var _ = gc.Suite(&singleNodeSuite{})
+
+func (s *singleNodeSuite) TestSingleNode(c *gc.C) {
+	cluster := dqlitetesting.NewCluster(c, 1)
+	defer cluster.Close()
+	c.Assert(cluster.LeaderIndex(c), gc.Equals, 0)
+	db := cluster.Open(c, "test")
+	defer db.Close()
+	var n int
+	err := db.QueryRow("SELECT 1").Scan(&n)
+	c.Assert(err, gc.IsNil)
+	c.Assert(n, gc.Equals, 1)
+}
+
+func (s *singleNodeSuite) TestTooManyNodes(c *gc.C) {
+	c.ExpectFailure("at most three nodes are supported")
+	dqlitetesting.NewCluster(c, 4)
+}
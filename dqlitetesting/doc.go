@@ -0,0 +1,18 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package dqlitetesting runs small in-process dqlite clusters for
+// testing data access code that must survive the loss of a node.
+//
+// The package requires the dqlite C library and its go-dqlite
+// bindings, so it is a separate module, which keeps go-dqlite out of
+// the dependencies of github.com/juju/testing, and is only built with
+// the dqlite and libsqlite3 build tags:
+//
+//	cd dqlitetesting
+//	go test -tags libsqlite3,dqlite ./...
+//
+// Without them the package is empty. Within this repository, the
+// module uses the github.com/juju/testing package from the parent
+// directory.
+package dqlitetesting
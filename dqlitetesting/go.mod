@@ -0,0 +1,11 @@
+module github.com/juju/testing/dqlitetesting
+
+go 1.21
+
+require (
+	github.com/canonical/go-dqlite v1.21.0
+	github.com/juju/testing v0.0.0-00010101000000-000000000000
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
+)
+
+replace github.com/juju/testing => ../
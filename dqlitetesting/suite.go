@@ -0,0 +1,51 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build dqlite
+
+package dqlitetesting
+
+import (
+	"database/sql"
+
+	gc "gopkg.in/check.v1"
+)
+
+// DqliteSuite gives each test a new cluster of NodeCount nodes, and a
+// connection to a database on it.
+type DqliteSuite struct {
+	// NodeCount holds the number of nodes in each test's cluster.
+	// If it is zero, a single node is used.
+	NodeCount int
+
+	// Cluster holds the current test's cluster.
+	Cluster *Cluster
+
+	// DB holds a connection to the "test" database on the current
+	// test's cluster.
+	DB *sql.DB
+}
+
+func (s *DqliteSuite) SetUpSuite(c *gc.C) {}
+
+func (s *DqliteSuite) TearDownSuite(c *gc.C) {}
+
+func (s *DqliteSuite) SetUpTest(c *gc.C) {
+	n := s.NodeCount
+	if n == 0 {
+		n = 1
+	}
+	s.Cluster = NewCluster(c, n)
+	s.DB = s.Cluster.Open(c, "test")
+}
+
+func (s *DqliteSuite) TearDownTest(c *gc.C) {
+	if s.DB != nil {
+		s.DB.Close()
+		s.DB = nil
+	}
+	if s.Cluster != nil {
+		s.Cluster.Close()
+		s.Cluster = nil
+	}
+}
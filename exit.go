@@ -0,0 +1,229 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	gc "gopkg.in/check.v1"
+)
+
+// ExitInterceptor stands in for os.Exit so that tests can check the
+// exit code of code under test without the test process exiting. Code
+// under test should call os.Exit through a package variable, which
+// tests patch to use an interceptor:
+//
+//	var osExit = os.Exit
+//
+//	...
+//
+//	exits := testing.NewExitInterceptor()
+//	s.PatchValue(&osExit, exits.Exit)
+//	exits.Run(func() {
+//		main()
+//	})
+//	c.Assert(exits, testing.ExitedWith, 2)
+//
+// Code that cannot be changed to call os.Exit through a variable can
+// be tested with RunSubprocess instead.
+type ExitInterceptor struct {
+	mu      sync.Mutex
+	running bool
+	exited  bool
+	code    int
+	// runner holds the id of the goroutine started by Run.
+	runner uint64
+	// done is closed when Exit is called from a goroutine other than
+	// the one started by Run.
+	done chan struct{}
+}
+
+// NewExitInterceptor returns a new ExitInterceptor.
+func NewExitInterceptor() *ExitInterceptor {
+	return &ExitInterceptor{}
+}
+
+// Run calls f in a new goroutine, and waits until f returns or calls
+// Exit. If Exit is called by f's goroutine, Run returns once that
+// goroutine's deferred calls have run; if it is called by another
+// goroutine, Run returns straight away, leaving f's goroutine running.
+func (e *ExitInterceptor) Run(f func()) {
+	e.mu.Lock()
+	e.running = true
+	e.exited = false
+	e.code = 0
+	e.done = make(chan struct{})
+	done := e.done
+	e.mu.Unlock()
+
+	returned := make(chan struct{})
+	go func() {
+		defer close(returned)
+		e.mu.Lock()
+		e.runner = goroutineID()
+		e.mu.Unlock()
+		f()
+	}()
+	select {
+	case <-returned:
+	case <-done:
+	}
+	e.mu.Lock()
+	e.running = false
+	e.mu.Unlock()
+}
+
+// Exit records the exit code, and stops the calling goroutine, so that
+// Run returns. Unlike os.Exit, it runs the goroutine's deferred calls,
+// and other goroutines started by the code under test keep running.
+// Exit panics if it is called when Run is not running.
+func (e *ExitInterceptor) Exit(code int) {
+	e.mu.Lock()
+	if !e.running {
+		e.mu.Unlock()
+		panic(fmt.Sprintf("testing: Exit(%d) called outside ExitInterceptor.Run", code))
+	}
+	if !e.exited {
+		e.exited = true
+		e.code = code
+		if goroutineID() != e.runner {
+			// Run's goroutine may never return, so Run cannot wait
+			// for it.
+			close(e.done)
+		}
+	}
+	e.mu.Unlock()
+	runtime.Goexit()
+}
+
+// goroutineID returns the id of the calling goroutine, as shown in the
+// first line of its stack trace, "goroutine 1 [running]:".
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	field := strings.Fields(strings.TrimPrefix(string(buf), "goroutine "))[0]
+	id, err := strconv.ParseUint(field, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("cannot parse goroutine id from %q", buf))
+	}
+	return id
+}
+
+// ExitCode returns the code with which Exit was called during the last
+// call to Run, and whether it was called.
+func (e *ExitInterceptor) ExitCode() (code int, exited bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.code, e.exited
+}
+
+// SubprocessResult holds the result of RunSubprocess.
+type SubprocessResult struct {
+	// Code holds the subprocess's exit code.
+	Code int
+
+	// Exited reports whether the function exited the subprocess,
+	// rather than returning.
+	Exited bool
+
+	// Stdout and Stderr hold the subprocess's output.
+	Stdout string
+	Stderr string
+}
+
+// ExitCode returns the code with which the subprocess exited, and
+// whether the function exited it.
+func (r *SubprocessResult) ExitCode() (code int, exited bool) {
+	return r.Code, r.Exited
+}
+
+// subprocessEnvKey holds the environment variable that tells a test
+// binary run by RunSubprocess which test's function to run.
+const subprocessEnvKey = "JUJU_TESTING_SUBPROCESS"
+
+// subprocessReturnedEnvKey holds the environment variable naming the
+// file that the subprocess creates if the function returns.
+const subprocessReturnedEnvKey = "JUJU_TESTING_SUBPROCESS_RETURNED"
+
+// RunSubprocess calls f in a new process, and returns its exit code and
+// output. It is for testing code that calls os.Exit directly, which
+// cannot use an ExitInterceptor.
+//
+// The subprocess runs the test binary again, with -check.f selecting
+// the current test, so the test runs up to the call to RunSubprocess
+// in both processes; in the subprocess, RunSubprocess calls f and then
+// exits with code 0 if f returns. A test may call RunSubprocess only
+// once, and the code before the call should have no effects outside
+// the process.
+func RunSubprocess(c *gc.C, f func()) *SubprocessResult {
+	if os.Getenv(subprocessEnvKey) == c.TestName() {
+		f()
+		if path := os.Getenv(subprocessReturnedEnvKey); path != "" {
+			os.WriteFile(path, nil, 0644)
+		}
+		os.Exit(0)
+	}
+	returnedPath := filepath.Join(c.MkDir(), "returned")
+	cmd := exec.Command(os.Args[0], "-check.f=^"+regexp.QuoteMeta(c.TestName())+"$")
+	cmd.Env = append(os.Environ(),
+		subprocessEnvKey+"="+c.TestName(),
+		subprocessReturnedEnvKey+"="+returnedPath,
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	result := &SubprocessResult{
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.Code = exitErr.ExitCode()
+	} else {
+		c.Assert(err, gc.IsNil)
+	}
+	_, err = os.Stat(returnedPath)
+	result.Exited = os.IsNotExist(err)
+	return result
+}
+
+type exitedWithChecker struct {
+	*gc.CheckerInfo
+}
+
+// ExitedWith checks that the obtained *ExitInterceptor or
+// *SubprocessResult records an exit with the expected code.
+var ExitedWith gc.Checker = &exitedWithChecker{
+	&gc.CheckerInfo{Name: "ExitedWith", Params: []string{"obtained", "code"}},
+}
+
+func (checker *exitedWithChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	obtained, ok := params[0].(interface{ ExitCode() (int, bool) })
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type *testing.ExitInterceptor or *testing.SubprocessResult, got %T", params[0])
+	}
+	expected, ok := params[1].(int)
+	if !ok {
+		return false, fmt.Sprintf("code must be an int, got %T", params[1])
+	}
+	code, exited := obtained.ExitCode()
+	if !exited {
+		return false, "the code did not exit"
+	}
+	if code != expected {
+		return false, fmt.Sprintf("exit code is %d", code)
+	}
+	return true, ""
+}
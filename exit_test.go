@@ -0,0 +1,151 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"fmt"
+	"os"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+type exitSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&exitSuite{})
+
+var osExit = os.Exit
+
+// exitingMain stands in for a main function that exits with the code
+// given by its argument, if it is not zero.
+func exitingMain(code int) {
+	if code != 0 {
+		osExit(code)
+	}
+}
+
+func (s *exitSuite) TestExitInterceptor(c *gc.C) {
+	exits := testing.NewExitInterceptor()
+	s.PatchValue(&osExit, exits.Exit)
+	deferred := false
+	exits.Run(func() {
+		defer func() { deferred = true }()
+		exitingMain(2)
+		c.Errorf("exit did not stop the code")
+	})
+	c.Assert(exits, testing.ExitedWith, 2)
+	c.Assert(deferred, gc.Equals, true)
+	code, exited := exits.ExitCode()
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(exited, gc.Equals, true)
+}
+
+func (s *exitSuite) TestExitInterceptorNoExit(c *gc.C) {
+	exits := testing.NewExitInterceptor()
+	s.PatchValue(&osExit, exits.Exit)
+	exits.Run(func() {
+		exitingMain(0)
+	})
+	code, exited := exits.ExitCode()
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(exited, gc.Equals, false)
+	c.Assert(exits, gc.Not(testing.ExitedWith), 0)
+}
+
+func (s *exitSuite) TestExitInterceptorResetByRun(c *gc.C) {
+	exits := testing.NewExitInterceptor()
+	s.PatchValue(&osExit, exits.Exit)
+	exits.Run(func() { exitingMain(1) })
+	c.Assert(exits, testing.ExitedWith, 1)
+	exits.Run(func() { exitingMain(0) })
+	_, exited := exits.ExitCode()
+	c.Assert(exited, gc.Equals, false)
+}
+
+func (s *exitSuite) TestExitInterceptorFromGoroutine(c *gc.C) {
+	exits := testing.NewExitInterceptor()
+	block := make(chan struct{})
+	defer close(block)
+	exits.Run(func() {
+		go exits.Exit(3)
+		<-block
+	})
+	c.Assert(exits, testing.ExitedWith, 3)
+}
+
+func (s *exitSuite) TestExitOutsideRun(c *gc.C) {
+	exits := testing.NewExitInterceptor()
+	c.Assert(func() { exits.Exit(1) }, gc.PanicMatches, `testing: Exit\(1\) called outside ExitInterceptor.Run`)
+}
+
+func (s *exitSuite) TestRunSubprocessExit(c *gc.C) {
+	result := testing.RunSubprocess(c, func() {
+		fmt.Println("hello")
+		fmt.Fprintln(os.Stderr, "fatal error")
+		os.Exit(4)
+	})
+	c.Assert(result, testing.ExitedWith, 4)
+	c.Assert(result.Stdout, gc.Equals, "hello\n")
+	c.Assert(result.Stderr, gc.Equals, "fatal error\n")
+}
+
+func (s *exitSuite) TestRunSubprocessReturn(c *gc.C) {
+	result := testing.RunSubprocess(c, func() {
+		fmt.Println("hello")
+	})
+	c.Assert(result.Code, gc.Equals, 0)
+	c.Assert(result.Exited, gc.Equals, false)
+	c.Assert(result.Stdout, gc.Equals, "hello\n")
+	c.Assert(result, gc.Not(testing.ExitedWith), 0)
+}
+
+func (s *exitSuite) TestRunSubprocessExitZero(c *gc.C) {
+	result := testing.RunSubprocess(c, func() {
+		os.Exit(0)
+	})
+	c.Assert(result, testing.ExitedWith, 0)
+}
+
+var exitedWithTests = []struct {
+	about    string
+	obtained interface{}
+	code     interface{}
+	message  string
+}{{
+	about:    "matching code",
+	obtained: &testing.SubprocessResult{Code: 1, Exited: true},
+	code:     1,
+}, {
+	about:    "different code",
+	obtained: &testing.SubprocessResult{Code: 1, Exited: true},
+	code:     2,
+	message:  "exit code is 1",
+}, {
+	about:    "no exit",
+	obtained: &testing.SubprocessResult{},
+	code:     0,
+	message:  "the code did not exit",
+}, {
+	about:    "obtained value of the wrong type",
+	obtained: 1,
+	code:     1,
+	message:  `obtained value must be of type \*testing.ExitInterceptor or \*testing.SubprocessResult, got int`,
+}, {
+	about:    "code of the wrong type",
+	obtained: &testing.SubprocessResult{Code: 1, Exited: true},
+	code:     "1",
+	message:  "code must be an int, got string",
+}}
+
+func (s *exitSuite) TestExitedWith(c *gc.C) {
+	for i, test := range exitedWithTests {
+		c.Logf("test %d: %s", i, test.about)
+		result, message := testing.ExitedWith.Check([]interface{}{test.obtained, test.code}, nil)
+		c.Check(result, gc.Equals, test.message == "")
+		c.Check(message, gc.Matches, test.message)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"errors"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+)
+
+// FDLeakSuite fails any test that leaves new file descriptors open when
+// it finishes, reporting what each leaked descriptor refers to. This
+// catches files, sockets and pipes that a test, or the code under
+// test, forgot to close.
+//
+// The check only runs on Linux and macOS; target names are only
+// available on Linux. Descriptors opened by the Go runtime itself,
+// such as the network poller, are ignored.
+//
+// Since it runs in TearDownTest, FDLeakSuite should be torn down after
+// any suite whose cleanups close descriptors.
+type FDLeakSuite struct {
+	fds map[int]string
+}
+
+// errFDsNotSupported is returned by openFDs when open file descriptors
+// cannot be listed on the current platform.
+var errFDsNotSupported = errors.New("listing open file descriptors not supported")
+
+func (s *FDLeakSuite) SetUpSuite(c *gc.C) {}
+
+func (s *FDLeakSuite) TearDownSuite(c *gc.C) {}
+
+func (s *FDLeakSuite) SetUpTest(c *gc.C) {
+	fds, err := openFDs()
+	if err == errFDsNotSupported {
+		s.fds = nil
+		return
+	}
+	c.Assert(err, gc.IsNil)
+	s.fds = fds
+}
+
+func (s *FDLeakSuite) TearDownTest(c *gc.C) {
+	if s.fds == nil {
+		return
+	}
+	before := s.fds
+	s.fds = nil
+	after, err := openFDs()
+	c.Assert(err, gc.IsNil)
+	var leaked []int
+	for fd, target := range after {
+		if oldTarget, ok := before[fd]; ok && oldTarget == target {
+			continue
+		}
+		if runtimeFDTargets[target] {
+			continue
+		}
+		leaked = append(leaked, fd)
+	}
+	sort.Ints(leaked)
+	for _, fd := range leaked {
+		c.Errorf("test leaked file descriptor %d: %s", fd, after[fd])
+	}
+}
+
+// runtimeFDTargets holds the targets of file descriptors that the Go
+// runtime opens on demand and never closes.
+var runtimeFDTargets = map[string]bool{
+	"anon_inode:[eventpoll]": true,
+	"anon_inode:[eventfd]":   true,
+}
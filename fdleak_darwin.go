@@ -0,0 +1,55 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// openFDs returns the open file descriptors of the current process.
+// The descriptors' targets are not available, so each is described
+// by its file type only.
+func openFDs() (map[int]string, error) {
+	dir, err := os.Open("/dev/fd")
+	if err != nil {
+		return nil, err
+	}
+	names, err := dir.Readdirnames(-1)
+	dirFD := int(dir.Fd())
+	dir.Close()
+	if err != nil {
+		return nil, err
+	}
+	fds := make(map[int]string)
+	for _, name := range names {
+		fd, err := strconv.Atoi(name)
+		if err != nil || fd == dirFD {
+			continue
+		}
+		var st syscall.Stat_t
+		if err := syscall.Fstat(fd, &st); err != nil {
+			continue
+		}
+		fds[fd] = fdType(st.Mode)
+	}
+	return fds, nil
+}
+
+func fdType(mode uint16) string {
+	switch mode & syscall.S_IFMT {
+	case syscall.S_IFREG:
+		return "file"
+	case syscall.S_IFDIR:
+		return "directory"
+	case syscall.S_IFIFO:
+		return "pipe"
+	case syscall.S_IFSOCK:
+		return "socket"
+	case syscall.S_IFCHR:
+		return "character device"
+	}
+	return "unknown"
+}
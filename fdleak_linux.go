@@ -0,0 +1,34 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"os"
+	"strconv"
+)
+
+// openFDs returns the open file descriptors of the current process,
+// mapped to what they refer to.
+func openFDs() (map[int]string, error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return nil, err
+	}
+	fds := make(map[int]string)
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink("/proc/self/fd/" + entry.Name())
+		if err != nil {
+			// The descriptor was closed after the directory was
+			// read, most likely because it was the one used to
+			// read the directory.
+			continue
+		}
+		fds[fd] = target
+	}
+	return fds, nil
+}
@@ -0,0 +1,11 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !linux && !darwin
+
+package testing
+
+// openFDs always returns errFDsNotSupported.
+func openFDs() (map[int]string, error) {
+	return nil, errFDsNotSupported
+}
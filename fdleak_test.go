@@ -0,0 +1,60 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build linux || darwin
+
+package testing_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type fdLeakSuite struct {
+	suite testing.FDLeakSuite
+}
+
+var _ = gc.Suite(&fdLeakSuite{})
+
+func (s *fdLeakSuite) SetUpTest(c *gc.C) {
+	s.suite = testing.FDLeakSuite{}
+	s.suite.SetUpSuite(c)
+	s.suite.SetUpTest(c)
+}
+
+func (s *fdLeakSuite) TearDownTest(c *gc.C) {
+	s.suite.TearDownSuite(c)
+}
+
+func (s *fdLeakSuite) TestNoLeaks(c *gc.C) {
+	f, err := os.Create(filepath.Join(c.MkDir(), "file"))
+	c.Assert(err, jc.ErrorIsNil)
+	f.Close()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	l.Close()
+	s.suite.TearDownTest(c)
+}
+
+func (s *fdLeakSuite) TestLeakedFile(c *gc.C) {
+	f, err := os.Create(filepath.Join(c.MkDir(), "file"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	c.ExpectFailure("file was not closed")
+	s.suite.TearDownTest(c)
+}
+
+func (s *fdLeakSuite) TestLeakedPipe(c *gc.C) {
+	r, w, err := os.Pipe()
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	defer w.Close()
+	c.ExpectFailure("pipe was not closed")
+	s.suite.TearDownTest(c)
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// archiveModTime is the modification time given to archive members,
+// so that archives built from the same entries are identical.
+var archiveModTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// TarEntry wraps an Entry with extra header fields to use when it is
+// written to a tar archive. It is otherwise the same as the wrapped
+// entry.
+type TarEntry struct {
+	Entry
+
+	// PAXRecords holds PAX extended header records, such as
+	// "SCHILY.xattr.user.name".
+	PAXRecords map[string]string
+
+	Uid, Gid     int
+	Uname, Gname string
+}
+
+// WriteTar writes a tar archive holding the given entries to w.
+// Dir, File, LargeFile and Symlink entries are supported, and may
+// be wrapped in a TarEntry.
+func WriteTar(c *gc.C, w io.Writer, entries ...Entry) {
+	tw := tar.NewWriter(w)
+	for _, entry := range entries {
+		hdr := &tar.Header{
+			Name:    entry.GetPath(),
+			ModTime: archiveModTime,
+		}
+		if te, ok := entry.(TarEntry); ok {
+			hdr.PAXRecords = te.PAXRecords
+			hdr.Uid, hdr.Gid = te.Uid, te.Gid
+			hdr.Uname, hdr.Gname = te.Uname, te.Gname
+			if len(te.PAXRecords) > 0 {
+				hdr.Format = tar.FormatPAX
+			}
+			entry = te.Entry
+		}
+		content := archiveMember(c, entry)
+		hdr.Mode = int64(content.mode.Perm())
+		switch {
+		case content.mode.IsDir():
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name = strings.TrimSuffix(hdr.Name, "/") + "/"
+		case content.mode&os.ModeSymlink != 0:
+			hdr.Typeflag = tar.TypeSymlink
+			hdr.Linkname = content.link
+		default:
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = content.size
+		}
+		err := tw.WriteHeader(hdr)
+		c.Assert(err, gc.IsNil, gc.Commentf("entry %q", entry.GetPath()))
+		if content.data != nil {
+			_, err = io.Copy(tw, content.data)
+			c.Assert(err, gc.IsNil, gc.Commentf("entry %q", entry.GetPath()))
+		}
+	}
+	err := tw.Close()
+	c.Assert(err, gc.IsNil)
+}
+
+// TarBytes returns a tar archive holding the given entries, as
+// written by WriteTar.
+func TarBytes(c *gc.C, entries ...Entry) []byte {
+	var buf bytes.Buffer
+	WriteTar(c, &buf, entries...)
+	return buf.Bytes()
+}
+
+// WriteZip writes a zip archive holding the given entries to w.
+// Dir, File, LargeFile and Symlink entries are supported. Symbolic
+// links are stored as Unix tools store them, with the link target
+// as the member's contents.
+// Entries wrapped in a TarEntry are written without the extra
+// tar header fields.
+func WriteZip(c *gc.C, w io.Writer, entries ...Entry) {
+	zw := zip.NewWriter(w)
+	for _, entry := range entries {
+		if te, ok := entry.(TarEntry); ok {
+			entry = te.Entry
+		}
+		content := archiveMember(c, entry)
+		hdr := &zip.FileHeader{
+			Name:     entry.GetPath(),
+			Method:   zip.Deflate,
+			Modified: archiveModTime,
+		}
+		hdr.SetMode(content.mode)
+		data := content.data
+		switch {
+		case content.mode.IsDir():
+			hdr.Name = strings.TrimSuffix(hdr.Name, "/") + "/"
+			hdr.Method = zip.Store
+		case content.mode&os.ModeSymlink != 0:
+			data = strings.NewReader(content.link)
+		}
+		fw, err := zw.CreateHeader(hdr)
+		c.Assert(err, gc.IsNil, gc.Commentf("entry %q", entry.GetPath()))
+		if data != nil {
+			_, err = io.Copy(fw, data)
+			c.Assert(err, gc.IsNil, gc.Commentf("entry %q", entry.GetPath()))
+		}
+	}
+	err := zw.Close()
+	c.Assert(err, gc.IsNil)
+}
+
+// ZipBytes returns a zip archive holding the given entries, as
+// written by WriteZip.
+func ZipBytes(c *gc.C, entries ...Entry) []byte {
+	var buf bytes.Buffer
+	WriteZip(c, &buf, entries...)
+	return buf.Bytes()
+}
+
+// member holds what is needed to write an entry to an archive.
+type member struct {
+	mode os.FileMode
+	size int64
+	data io.Reader
+	link string
+}
+
+func archiveMember(c *gc.C, entry Entry) member {
+	switch entry := entry.(type) {
+	case Dir:
+		return member{mode: os.ModeDir | entry.Perm}
+	case File:
+		return member{mode: entry.Perm, size: int64(len(entry.Data)), data: strings.NewReader(entry.Data)}
+	case LargeFile:
+		return member{mode: entry.Perm, size: entry.Size, data: entry.Content()}
+	case Symlink:
+		return member{mode: os.ModeSymlink | 0777, link: entry.Link}
+	}
+	c.Fatalf("cannot archive entry %q of type %T", entry.GetPath(), entry)
+	panic("unreachable")
+}
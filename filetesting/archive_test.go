@@ -0,0 +1,110 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type ArchiveSuite struct{}
+
+var _ = gc.Suite(&ArchiveSuite{})
+
+var archiveEntries = []ft.Entry{
+	ft.Dir{Path: "dir", Perm: 0755},
+	ft.File{Path: "dir/file", Data: "hello", Perm: 0640},
+	ft.LargeFile{Path: "dir/large", Size: 100000, Seed: 1, Perm: 0600},
+	ft.Symlink{Path: "link", Link: "dir/file"},
+	ft.TarEntry{
+		Entry:      ft.File{Path: "attrs", Data: "x", Perm: 0644},
+		PAXRecords: map[string]string{"SCHILY.xattr.user.test": "value"},
+		Uid:        1000,
+		Uname:      "ubuntu",
+	},
+}
+
+type archived struct {
+	name string
+	mode os.FileMode
+	data string
+}
+
+func (s *ArchiveSuite) TestTar(c *gc.C) {
+	data := ft.TarBytes(c, archiveEntries...)
+	c.Assert(ft.TarBytes(c, archiveEntries...), jc.DeepEquals, data)
+
+	large, err := ioutil.ReadAll(archiveEntries[2].(ft.LargeFile).Content())
+	c.Assert(err, jc.ErrorIsNil)
+
+	var got []archived
+	var attrsHdr *tar.Header
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		content, err := ioutil.ReadAll(tr)
+		c.Assert(err, jc.ErrorIsNil)
+		if hdr.Typeflag == tar.TypeSymlink {
+			content = []byte(hdr.Linkname)
+		}
+		if hdr.Name == "attrs" {
+			attrsHdr = hdr
+		}
+		got = append(got, archived{hdr.Name, hdr.FileInfo().Mode(), string(content)})
+	}
+	c.Assert(got, jc.DeepEquals, []archived{
+		{"dir/", os.ModeDir | 0755, ""},
+		{"dir/file", 0640, "hello"},
+		{"dir/large", 0600, string(large)},
+		{"link", os.ModeSymlink | 0777, "dir/file"},
+		{"attrs", 0644, "x"},
+	})
+	c.Assert(attrsHdr.PAXRecords["SCHILY.xattr.user.test"], gc.Equals, "value")
+	c.Assert(attrsHdr.Uid, gc.Equals, 1000)
+	c.Assert(attrsHdr.Uname, gc.Equals, "ubuntu")
+}
+
+func (s *ArchiveSuite) TestZip(c *gc.C) {
+	data := ft.ZipBytes(c, archiveEntries...)
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	var got []archived
+	for _, f := range zr.File {
+		r, err := f.Open()
+		c.Assert(err, jc.ErrorIsNil)
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		c.Assert(err, jc.ErrorIsNil)
+		if f.Name == "dir/large" {
+			c.Assert(content, gc.HasLen, 100000)
+			content = nil
+		}
+		got = append(got, archived{f.Name, f.Mode(), string(content)})
+	}
+	c.Assert(got, jc.DeepEquals, []archived{
+		{"dir/", os.ModeDir | 0755, ""},
+		{"dir/file", 0640, "hello"},
+		{"dir/large", 0600, ""},
+		{"link", os.ModeSymlink | 0777, "dir/file"},
+		{"attrs", 0644, "x"},
+	})
+}
+
+func (s *ArchiveSuite) TestRemovedNotSupported(c *gc.C) {
+	c.ExpectFailure("Removed entries cannot be archived")
+	ft.TarBytes(c, ft.Removed{Path: "foo"})
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// LinkFS is implemented by file systems, such as MemFS, that can
+// report on symbolic links.
+type LinkFS interface {
+	fs.FS
+	Lstat(name string) (fs.FileInfo, error)
+	ReadLink(name string) (string, error)
+}
+
+// CheckFS checks that each of the entries matches the contents of
+// fsys, as Entries.Check does for a directory on disk. Symlink entries
+// can only be checked if fsys implements LinkFS.
+func CheckFS(c *gc.C, fsys fs.FS, entries ...Entry) {
+	for _, entry := range entries {
+		comment := gc.Commentf("entry %q", entry.GetPath())
+		switch entry := entry.(type) {
+		case Dir:
+			info, err := fs.Stat(fsys, entry.Path)
+			if !c.Check(err, gc.IsNil, comment) {
+				continue
+			}
+			c.Check(info.IsDir(), gc.Equals, true, comment)
+			c.Check(info.Mode().Perm(), gc.Equals, entry.Perm, comment)
+		case File:
+			info, err := fs.Stat(fsys, entry.Path)
+			if !c.Check(err, gc.IsNil, comment) {
+				continue
+			}
+			c.Check(info.Mode().IsRegular(), gc.Equals, true, comment)
+			c.Check(info.Mode().Perm(), gc.Equals, entry.Perm, comment)
+			data, err := fs.ReadFile(fsys, entry.Path)
+			c.Check(err, gc.IsNil, comment)
+			c.Check(string(data), gc.Equals, entry.Data, comment)
+		case Symlink:
+			lfs, ok := fsys.(LinkFS)
+			if !ok {
+				c.Errorf("cannot check symlink %q in %T", entry.Path, fsys)
+				continue
+			}
+			link, err := lfs.ReadLink(entry.Path)
+			c.Check(err, gc.IsNil, comment)
+			c.Check(link, gc.Equals, entry.Link, comment)
+		case Removed:
+			_, err := fs.Stat(fsys, entry.Path)
+			c.Check(err, jc.Satisfies, isNotExistFS, comment)
+		default:
+			c.Errorf("unsupported entry type %T", entry)
+		}
+	}
+}
+
+// isNotExistFS is like isNotExist for errors from fs.FS, where a path
+// whose parent is a file is reported as not existing.
+func isNotExistFS(err error) bool {
+	return err != nil && (errors.Is(err, fs.ErrNotExist) || isNotExist(err))
+}
+
+type fsContentsChecker struct {
+	*gc.CheckerInfo
+}
+
+// FSContents checks that the obtained fs.FS holds exactly the regular
+// files in the expected map[string]string, which maps file names to
+// their contents. Directories are not compared. On failure it lists
+// the files that are missing, unexpected or have different contents.
+var FSContents gc.Checker = &fsContentsChecker{
+	&gc.CheckerInfo{Name: "FSContents", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *fsContentsChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	fsys, ok := params[0].(fs.FS)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be an fs.FS, got %T", params[0])
+	}
+	expected, ok := params[1].(map[string]string)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be a map[string]string, got %T", params[1])
+	}
+	obtained, err := readFSFiles(fsys)
+	if err != nil {
+		return false, fmt.Sprintf("cannot read file system: %v", err)
+	}
+	var diffs []string
+	for name, data := range expected {
+		got, ok := obtained[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("  missing file %q", name))
+		case got != data:
+			diffs = append(diffs, fmt.Sprintf("  file %q has contents %q, want %q", name, got, data))
+		}
+	}
+	for name := range obtained {
+		if _, ok := expected[name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("  unexpected file %q", name))
+		}
+	}
+	if len(diffs) == 0 {
+		return true, ""
+	}
+	sort.Strings(diffs)
+	return false, "file system differs:\n" + strings.Join(diffs, "\n")
+}
+
+// readFSFiles returns the contents of every regular file in fsys,
+// keyed by name.
+func readFSFiles(fsys fs.FS) (map[string]string, error) {
+	files := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		files[path] = string(data)
+		return nil
+	})
+	return files, err
+}
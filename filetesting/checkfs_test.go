@@ -0,0 +1,102 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"os"
+	"testing/fstest"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type CheckFSSuite struct{}
+
+var _ = gc.Suite(&CheckFSSuite{})
+
+var checkFSEntries = ft.Entries{
+	ft.Dir{Path: "dir", Perm: 0755},
+	ft.File{Path: "dir/file", Data: "data", Perm: 0644},
+	ft.Symlink{Path: "dir/link", Link: "file"},
+}
+
+func (s *CheckFSSuite) TestCheckFS(c *gc.C) {
+	m := ft.NewMemFS(c, checkFSEntries...)
+	ft.CheckFS(c, m, checkFSEntries...)
+	ft.CheckFS(c, m, ft.Removed{Path: "dir/missing"}, ft.Removed{Path: "dir/file/sub"})
+}
+
+func (s *CheckFSSuite) TestCheckFSOnDisk(c *gc.C) {
+	dir := c.MkDir()
+	checkFSEntries.Create(c, dir)
+	// os.DirFS cannot report on symlinks.
+	ft.CheckFS(c, os.DirFS(dir), checkFSEntries[:2]...)
+}
+
+func (s *CheckFSSuite) TestCheckFSFailureBadPerm(c *gc.C) {
+	m := ft.NewMemFS(c, checkFSEntries...)
+	c.ExpectFailure("directory has different permissions")
+	ft.CheckFS(c, m, ft.Dir{Path: "dir", Perm: 0700})
+}
+
+func (s *CheckFSSuite) TestCheckFSFailureBadData(c *gc.C) {
+	m := ft.NewMemFS(c, checkFSEntries...)
+	c.ExpectFailure("file has different contents")
+	ft.CheckFS(c, m, ft.File{Path: "dir/file", Data: "other", Perm: 0644})
+}
+
+func (s *CheckFSSuite) TestCheckFSFailureNotDir(c *gc.C) {
+	m := ft.NewMemFS(c, checkFSEntries...)
+	c.ExpectFailure("file is not a directory")
+	ft.CheckFS(c, m, ft.Dir{Path: "dir/file", Perm: 0644})
+}
+
+func (s *CheckFSSuite) TestCheckFSFailureBadLink(c *gc.C) {
+	m := ft.NewMemFS(c, checkFSEntries...)
+	c.ExpectFailure("symlink has a different target")
+	ft.CheckFS(c, m, ft.Symlink{Path: "dir/link", Link: "elsewhere"})
+}
+
+func (s *CheckFSSuite) TestCheckFSFailureNotRemoved(c *gc.C) {
+	m := ft.NewMemFS(c, checkFSEntries...)
+	c.ExpectFailure("file exists")
+	ft.CheckFS(c, m, ft.Removed{Path: "dir/file"})
+}
+
+func (s *CheckFSSuite) TestCheckFSSymlinkUnsupported(c *gc.C) {
+	c.ExpectFailure("symlinks cannot be checked in a MapFS")
+	ft.CheckFS(c, fstest.MapFS{}, ft.Symlink{Path: "link", Link: "target"})
+}
+
+func (s *CheckFSSuite) TestFSContents(c *gc.C) {
+	m := ft.NewMemFS(c, checkFSEntries...)
+	c.Assert(m, ft.FSContents, map[string]string{"dir/file": "data"})
+
+	m = ft.MapMemFS(map[string]string{
+		"a": "1",
+		"b": "2",
+		"c": "3",
+	})
+	result, msg := ft.FSContents.Check([]interface{}{m, map[string]string{
+		"a": "1",
+		"b": "two",
+		"d": "4",
+	}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, `file system differs:
+  file "b" has contents "2", want "two"
+  missing file "d"
+  unexpected file "c"`)
+}
+
+func (s *CheckFSSuite) TestFSContentsBadParams(c *gc.C) {
+	result, msg := ft.FSContents.Check([]interface{}{"foo", map[string]string{}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, "obtained value must be an fs.FS, got string")
+	result, msg = ft.FSContents.Check([]interface{}{ft.MapMemFS(nil), []string{}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, "expected value must be a map[string]string, got []string")
+}
@@ -1,6 +1,21 @@
 // Copyright 2013, 2014 Canonical Ltd.
 // Licensed under the LGPLv3, see LICENCE file for details.
 
+// Package filetesting provides declarative descriptions of directory
+// trees, which can be used both to create a tree for a test and to
+// check that a tree has the expected contents:
+//
+//	entries := filetesting.Entries{
+//		filetesting.Dir{Path: "charm", Perm: 0755},
+//		filetesting.File{Path: "charm/metadata.yaml", Data: "name: foo\n", Perm: 0644},
+//		filetesting.Symlink{Path: "charm/hooks", Link: "../hooks"},
+//	}
+//	entries.Create(c, baseDir)
+//	...
+//	entries.Check(c, baseDir)
+//
+// Check reports every entry that does not match, each with a comment
+// naming the path concerned, rather than stopping at the first.
 package filetesting
 
 import (
@@ -0,0 +1,165 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// defaultFixtureCacheDir holds the directory used by
+// DefaultFixtureCache. It is worked out when the package is
+// initialised, as OsEnvSuite clears the environment.
+var defaultFixtureCacheDir = fixtureCacheDir()
+
+func fixtureCacheDir() string {
+	if dir := os.Getenv("TEST_FIXTURE_CACHE"); dir != "" {
+		return dir
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "juju-testing", "fixtures")
+	}
+	return filepath.Join(os.TempDir(), "juju-testing-fixtures")
+}
+
+// FixtureCache holds expensive fixtures, such as downloaded images or
+// generated archives, in a content-addressed directory that is shared
+// between test runs, so that each fixture is only made once.
+//
+// Each fixture is stored under the SHA-256 digest of its contents and
+// is verified against that digest whenever it is used; a corrupted
+// fixture is made again. Fixtures are found by a key that should
+// describe everything the contents depend on, such as a URL or the
+// parameters and version of a generator, so that changing any of
+// them makes a new fixture rather than reusing a stale one.
+//
+// Fixtures are written atomically, so a cache may be shared by tests
+// running in parallel. Their files are read-only and must not be
+// changed by tests.
+type FixtureCache struct {
+	Dir string
+}
+
+// DefaultFixtureCache returns a cache in $TEST_FIXTURE_CACHE if that is
+// set, and otherwise in the user's cache directory.
+func DefaultFixtureCache() *FixtureCache {
+	return &FixtureCache{Dir: defaultFixtureCacheDir}
+}
+
+// Get returns the path of the fixture with the given key, calling
+// generate to write its contents if it is not already in the cache.
+func (fc *FixtureCache) Get(c *gc.C, key string, generate func(w io.Writer) error) string {
+	keyPath := filepath.Join(fc.Dir, "keys", hashString(key))
+	if digest, err := ioutil.ReadFile(keyPath); err == nil {
+		if path, ok := fc.verified(c, strings.TrimSpace(string(digest))); ok {
+			return path
+		}
+	}
+	digest := fc.generate(c, "", generate)
+	fc.writeAtomic(c, keyPath, func(w io.Writer) error {
+		_, err := io.WriteString(w, digest+"\n")
+		return err
+	})
+	return fc.objectPath(digest)
+}
+
+// GetSHA256 returns the path of the fixture whose contents have the
+// given hex-encoded SHA-256 digest, calling generate to write them if
+// they are not already in the cache. The test fails if the contents
+// written do not have that digest, which makes it suitable for
+// fixtures downloaded from elsewhere.
+func (fc *FixtureCache) GetSHA256(c *gc.C, digest string, generate func(w io.Writer) error) string {
+	digest = strings.ToLower(digest)
+	if path, ok := fc.verified(c, digest); ok {
+		return path
+	}
+	fc.generate(c, digest, generate)
+	return fc.objectPath(digest)
+}
+
+// verified returns the path of the fixture with the given digest and
+// reports whether it is in the cache with the right contents.
+func (fc *FixtureCache) verified(c *gc.C, digest string) (string, bool) {
+	path := fc.objectPath(digest)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return "", false
+	}
+	c.Assert(err, gc.IsNil)
+	defer f.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	c.Assert(err, gc.IsNil)
+	if hex.EncodeToString(hash.Sum(nil)) != digest {
+		c.Logf("fixture %s is corrupt; making it again", path)
+		return "", false
+	}
+	return path, true
+}
+
+// generate stores the output of generate in the cache and returns its
+// digest, which must be want if that is not empty.
+func (fc *FixtureCache) generate(c *gc.C, want string, generate func(w io.Writer) error) string {
+	objects := filepath.Join(fc.Dir, "objects")
+	err := os.MkdirAll(objects, 0755)
+	c.Assert(err, gc.IsNil)
+	f, err := ioutil.TempFile(objects, ".tmp-")
+	c.Assert(err, gc.IsNil)
+	defer os.Remove(f.Name())
+	hash := sha256.New()
+	err = generate(io.MultiWriter(f, hash))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	c.Assert(err, gc.IsNil)
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if want != "" && digest != want {
+		c.Fatalf("fixture has SHA-256 %s, want %s", digest, want)
+	}
+	err = os.Chmod(f.Name(), 0444)
+	c.Assert(err, gc.IsNil)
+	path := fc.objectPath(digest)
+	if err := os.Rename(f.Name(), path); err != nil {
+		// Replacing a read-only file fails on windows, so
+		// remove any corrupt copy first.
+		os.Remove(path)
+		err = os.Rename(f.Name(), path)
+		c.Assert(err, gc.IsNil)
+	}
+	return digest
+}
+
+// writeAtomic writes the output of write to path by way of a
+// temporary file, so that readers never see it partly written.
+func (fc *FixtureCache) writeAtomic(c *gc.C, path string, write func(w io.Writer) error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	c.Assert(err, gc.IsNil)
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	c.Assert(err, gc.IsNil)
+	defer os.Remove(f.Name())
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	c.Assert(err, gc.IsNil)
+	err = os.Rename(f.Name(), path)
+	c.Assert(err, gc.IsNil)
+}
+
+func (fc *FixtureCache) objectPath(digest string) string {
+	return filepath.Join(fc.Dir, "objects", digest)
+}
+
+// hashString returns the hex-encoded SHA-256 digest of s.
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type FixtureCacheSuite struct {
+	cache *ft.FixtureCache
+	calls int
+}
+
+var _ = gc.Suite(&FixtureCacheSuite{})
+
+func (s *FixtureCacheSuite) SetUpTest(c *gc.C) {
+	s.cache = &ft.FixtureCache{Dir: c.MkDir()}
+	s.calls = 0
+}
+
+func (s *FixtureCacheSuite) generator(content string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		s.calls++
+		_, err := io.WriteString(w, content)
+		return err
+	}
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *FixtureCacheSuite) TestGet(c *gc.C) {
+	path := s.cache.Get(c, "fixture v1", s.generator("content"))
+	c.Assert(path, jc.FileHasSHA256, sha256Hex("content"))
+	c.Assert(s.calls, gc.Equals, 1)
+
+	// A second cache in the same directory, as used by a later
+	// test run, finds the fixture without generating it again.
+	again := (&ft.FixtureCache{Dir: s.cache.Dir}).Get(c, "fixture v1", s.generator("content"))
+	c.Assert(again, gc.Equals, path)
+	c.Assert(s.calls, gc.Equals, 1)
+}
+
+func (s *FixtureCacheSuite) TestGetReadOnly(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("file permissions are not supported on windows")
+	}
+	path := s.cache.Get(c, "fixture", s.generator("content"))
+	c.Assert(path, jc.HasFileMode, os.FileMode(0444))
+}
+
+func (s *FixtureCacheSuite) TestGetNewKey(c *gc.C) {
+	path1 := s.cache.Get(c, "fixture v1", s.generator("content"))
+	path2 := s.cache.Get(c, "fixture v2", s.generator("other content"))
+	c.Assert(s.calls, gc.Equals, 2)
+	c.Assert(path2, gc.Not(gc.Equals), path1)
+	c.Assert(path1, jc.FileHasSHA256, sha256Hex("content"))
+	c.Assert(path2, jc.FileHasSHA256, sha256Hex("other content"))
+}
+
+func (s *FixtureCacheSuite) TestGetSharesContent(c *gc.C) {
+	path1 := s.cache.Get(c, "fixture v1", s.generator("content"))
+	path2 := s.cache.Get(c, "fixture v2", s.generator("content"))
+	c.Assert(path2, gc.Equals, path1)
+}
+
+func (s *FixtureCacheSuite) TestGetCorrupt(c *gc.C) {
+	path := s.cache.Get(c, "fixture", s.generator("content"))
+	err := os.Chmod(path, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path, []byte("corrupt"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	again := s.cache.Get(c, "fixture", s.generator("content"))
+	c.Assert(again, gc.Equals, path)
+	c.Assert(s.calls, gc.Equals, 2)
+	c.Assert(path, jc.FileHasSHA256, sha256Hex("content"))
+}
+
+func (s *FixtureCacheSuite) TestGetSHA256(c *gc.C) {
+	digest := sha256Hex("downloaded")
+	path := s.cache.GetSHA256(c, digest, s.generator("downloaded"))
+	c.Assert(path, jc.FileHasSHA256, digest)
+	again := s.cache.GetSHA256(c, digest, s.generator("downloaded"))
+	c.Assert(again, gc.Equals, path)
+	c.Assert(s.calls, gc.Equals, 1)
+}
+
+func (s *FixtureCacheSuite) TestGetSHA256Mismatch(c *gc.C) {
+	c.ExpectFailure("generated content does not match digest")
+	s.cache.GetSHA256(c, sha256Hex("expected"), s.generator("other"))
+}
+
+func (s *FixtureCacheSuite) TestDefaultFixtureCache(c *gc.C) {
+	c.Assert(ft.DefaultFixtureCache().Dir, gc.Not(gc.Equals), "")
+}
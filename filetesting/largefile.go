@@ -0,0 +1,117 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"os"
+	"runtime"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// SeededContent returns a reader that produces size bytes of
+// pseudo-random data generated from seed. The same seed always
+// produces the same data.
+func SeededContent(seed, size int64) io.Reader {
+	return io.LimitReader(rand.New(rand.NewSource(seed)), size)
+}
+
+// PatternContent returns a reader that produces size bytes consisting
+// of pattern repeated, with the final repetition truncated as needed.
+// It panics if pattern is empty.
+func PatternContent(pattern string, size int64) io.Reader {
+	if pattern == "" {
+		panic("empty pattern")
+	}
+	return io.LimitReader(&patternReader{pattern: pattern}, size)
+}
+
+type patternReader struct {
+	pattern string
+	offset  int
+}
+
+func (r *patternReader) Read(buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		copied := copy(buf[n:], r.pattern[r.offset:])
+		n += copied
+		r.offset = (r.offset + copied) % len(r.pattern)
+	}
+	return n, nil
+}
+
+// LargeFile is an Entry for a file whose contents are generated as
+// they are written, so that files of any size can be created without
+// holding them in memory. The contents are repetitions of Pattern if
+// it is set, and otherwise pseudo-random data generated from Seed.
+// The Path field should use "/" as the path separator.
+type LargeFile struct {
+	Path    string
+	Size    int64
+	Seed    int64
+	Pattern string
+	Perm    os.FileMode
+}
+
+var _ Entry = LargeFile{}
+
+func (f LargeFile) GetPath() string {
+	return f.Path
+}
+
+// Content returns a reader that produces the contents of the file.
+func (f LargeFile) Content() io.Reader {
+	if f.Pattern != "" {
+		return PatternContent(f.Pattern, f.Size)
+	}
+	return SeededContent(f.Seed, f.Size)
+}
+
+// SHA256 returns the hex-encoded SHA-256 digest of the contents of
+// the file.
+func (f LargeFile) SHA256() string {
+	hash := sha256.New()
+	io.Copy(hash, f.Content())
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (f LargeFile) Create(c *gc.C, basePath string) Entry {
+	path := join(basePath, f.Path)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Perm)
+	c.Assert(err, gc.IsNil)
+	defer file.Close()
+	_, err = io.Copy(file, f.Content())
+	c.Assert(err, gc.IsNil)
+	err = file.Chmod(f.Perm)
+	c.Assert(err, gc.IsNil)
+	return f
+}
+
+// Check checks that the file exists with the right permissions, size
+// and contents, comparing the contents by their SHA-256 digest.
+func (f LargeFile) Check(c *gc.C, basePath string) Entry {
+	path := join(basePath, f.Path)
+	fileInfo, err := os.Lstat(path)
+	comment := gc.Commentf("file %q", path)
+	if !c.Check(err, gc.IsNil, comment) {
+		return f
+	}
+	// Skip until we implement proper permissions checking
+	if runtime.GOOS != "windows" {
+		mode := fileInfo.Mode()
+		c.Check(mode&os.ModeType, gc.Equals, os.FileMode(0), comment)
+		c.Check(mode&os.ModePerm, gc.Equals, f.Perm, comment)
+	}
+	if c.Check(path, jc.FileHasSize, f.Size, comment) {
+		c.Check(path, jc.FileHasSHA256, f.SHA256(), comment)
+	}
+	return f
+}
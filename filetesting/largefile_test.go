@@ -0,0 +1,64 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type LargeFileSuite struct {
+	ft.TempDirSuite
+}
+
+var _ = gc.Suite(&LargeFileSuite{})
+
+func (s *LargeFileSuite) TestSeededContent(c *gc.C) {
+	data1, err := ioutil.ReadAll(ft.SeededContent(99, 10000))
+	c.Assert(err, jc.ErrorIsNil)
+	data2, err := ioutil.ReadAll(ft.SeededContent(99, 10000))
+	c.Assert(err, jc.ErrorIsNil)
+	data3, err := ioutil.ReadAll(ft.SeededContent(100, 10000))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data1, gc.HasLen, 10000)
+	c.Assert(bytes.Equal(data1, data2), jc.IsTrue)
+	c.Assert(bytes.Equal(data1, data3), jc.IsFalse)
+}
+
+func (s *LargeFileSuite) TestPatternContent(c *gc.C) {
+	data, err := ioutil.ReadAll(ft.PatternContent("abc", 10))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "abcabcabca")
+}
+
+func (s *LargeFileSuite) TestCreateAndCheck(c *gc.C) {
+	entries := ft.Entries{
+		ft.LargeFile{Path: "random", Size: 3<<20 + 7, Seed: 42, Perm: 0644},
+		ft.LargeFile{Path: "pattern", Size: 1 << 20, Pattern: "0123456789", Perm: 0600},
+	}
+	s.Create(c, entries...)
+	s.AssertTree(c, entries...)
+	c.Assert(s.Path("random"), jc.FileHasSize, 3<<20+7)
+
+	data, err := ioutil.ReadFile(s.Path("pattern"))
+	c.Assert(err, jc.ErrorIsNil)
+	sum := sha256.Sum256(data)
+	c.Assert(entries[1].(ft.LargeFile).SHA256(), gc.Equals, hex.EncodeToString(sum[:]))
+}
+
+func (s *LargeFileSuite) TestCheckFailure(c *gc.C) {
+	ft.LargeFile{Path: "file", Size: 1000, Seed: 1, Perm: 0644}.Create(c, s.Dir)
+	err := os.Chmod(s.Path("file"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	c.ExpectFailure("contents generated from a different seed")
+	ft.LargeFile{Path: "file", Size: 1000, Seed: 2, Perm: 0644}.Check(c, s.Dir)
+}
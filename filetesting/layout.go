@@ -0,0 +1,351 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Hardlink is an Entry that allows hard links to be created and
+// verified. Link holds the path, relative to the same base path, of
+// the file that Path is linked to; it must be created first. Both
+// fields should use "/" as the path separator.
+type Hardlink struct {
+	Path string
+	Link string
+}
+
+var _ Entry = Hardlink{}
+
+func (h Hardlink) GetPath() string {
+	return h.Path
+}
+
+func (h Hardlink) Create(c *gc.C, basePath string) Entry {
+	err := os.Link(join(basePath, h.Link), join(basePath, h.Path))
+	c.Assert(err, gc.IsNil)
+	return h
+}
+
+func (h Hardlink) Check(c *gc.C, basePath string) Entry {
+	path := join(basePath, h.Path)
+	comment := gc.Commentf("hardlink %q", path)
+	info, err := os.Lstat(path)
+	if !c.Check(err, gc.IsNil, comment) {
+		return h
+	}
+	target, err := os.Lstat(join(basePath, h.Link))
+	if !c.Check(err, gc.IsNil, comment) {
+		return h
+	}
+	if !os.SameFile(info, target) {
+		c.Errorf("%q is not hard linked to %q", path, join(basePath, h.Link))
+	}
+	return h
+}
+
+// Region describes a range of bytes in a file.
+type Region struct {
+	Offset int64
+	Length int64
+}
+
+func (r Region) String() string {
+	return fmt.Sprintf("%d+%d", r.Offset, r.Length)
+}
+
+// SparseFile is an Entry that allows sparse files to be created and
+// verified. The file has the given size and holds each string in Data
+// at its offset, with holes elsewhere. The Path field should use "/"
+// as the path separator.
+//
+// Check verifies that the file is still sparse, that is that it has no
+// data regions away from those holding Data, only on platforms and
+// file systems that report holes; elsewhere only the logical contents
+// are compared.
+type SparseFile struct {
+	Path string
+	Size int64
+	Data map[int64]string
+	Perm os.FileMode
+}
+
+var _ Entry = SparseFile{}
+
+func (f SparseFile) GetPath() string {
+	return f.Path
+}
+
+// Content returns the logical contents of the file.
+func (f SparseFile) Content() []byte {
+	data := make([]byte, f.Size)
+	for offset, s := range f.Data {
+		copy(data[offset:], s)
+	}
+	return data
+}
+
+func (f SparseFile) Create(c *gc.C, basePath string) Entry {
+	path := join(basePath, f.Path)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Perm)
+	c.Assert(err, gc.IsNil)
+	defer file.Close()
+	err = file.Truncate(f.Size)
+	c.Assert(err, gc.IsNil)
+	for offset, s := range f.Data {
+		_, err := file.WriteAt([]byte(s), offset)
+		c.Assert(err, gc.IsNil)
+	}
+	err = file.Chmod(f.Perm)
+	c.Assert(err, gc.IsNil)
+	return f
+}
+
+func (f SparseFile) Check(c *gc.C, basePath string) Entry {
+	path := join(basePath, f.Path)
+	fileInfo, err := os.Lstat(path)
+	comment := gc.Commentf("sparse file %q", path)
+	if !c.Check(err, gc.IsNil, comment) {
+		return f
+	}
+	// Skip until we implement proper permissions checking
+	if runtime.GOOS != "windows" {
+		mode := fileInfo.Mode()
+		c.Check(mode&os.ModeType, gc.Equals, os.FileMode(0), comment)
+		c.Check(mode&os.ModePerm, gc.Equals, f.Perm, comment)
+	}
+	data, err := ioutil.ReadFile(path)
+	c.Check(err, gc.IsNil, comment)
+	if !bytes.Equal(data, f.Content()) {
+		c.Errorf("%q does not have the expected contents", path)
+		return f
+	}
+	regions, err := DataRegions(path)
+	if !c.Check(err, gc.IsNil, comment) {
+		return f
+	}
+	for _, region := range regions {
+		if !f.holdsData(region) {
+			c.Errorf("%q has data region %v where a hole was expected", path, region)
+		}
+	}
+	return f
+}
+
+// sparseAlignment holds the granularity with which file systems are
+// assumed to allocate the data in a sparse file.
+const sparseAlignment = 64 * 1024
+
+// holdsData reports whether the given data region lies within the
+// file's Data, allowing for the data to be allocated in whole blocks.
+func (f SparseFile) holdsData(region Region) bool {
+	var covered []Region
+	for offset, s := range f.Data {
+		start := offset / sparseAlignment * sparseAlignment
+		end := (offset + int64(len(s)) + sparseAlignment - 1) / sparseAlignment * sparseAlignment
+		covered = append(covered, Region{start, end - start})
+	}
+	sort.Slice(covered, func(i, j int) bool {
+		return covered[i].Offset < covered[j].Offset
+	})
+	pos, end := region.Offset, region.Offset+region.Length
+	for _, r := range covered {
+		if r.Offset > pos {
+			break
+		}
+		if r.Offset+r.Length > pos {
+			pos = r.Offset + r.Length
+		}
+	}
+	return pos >= end
+}
+
+// DataRegions returns the regions of the named file that hold data,
+// as opposed to holes, in offset order. Where holes cannot be
+// detected, the whole file is reported as one region.
+func DataRegions(path string) ([]Region, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return []Region{}, nil
+	}
+	regions, err := dataRegions(f, info.Size())
+	if err == errHolesNotSupported {
+		return []Region{{0, info.Size()}}, nil
+	}
+	return regions, err
+}
+
+// CompareOptions holds options for CompareTrees.
+type CompareOptions struct {
+	// IgnoreHardlinks causes files to be compared without regard
+	// to which of them are hard linked together.
+	IgnoreHardlinks bool
+
+	// IgnoreSparse causes files to be compared only by their
+	// logical contents, without regard to the holes in them.
+	IgnoreSparse bool
+}
+
+// CompareTrees checks that the directory tree rooted at obtained has
+// the same layout as that rooted at expected: the same entries with
+// the same types, permissions, contents and symlink targets, and,
+// unless disabled in opts, the same hard link groupings and the same
+// data regions in sparse files. This lets backup and restore tests
+// check physical layout as well as logical contents.
+func CompareTrees(c *gc.C, obtained, expected string, opts CompareOptions) {
+	obtainedTree, err := readTree(obtained)
+	c.Assert(err, gc.IsNil)
+	expectedTree, err := readTree(expected)
+	c.Assert(err, gc.IsNil)
+
+	for _, rel := range sortedKeys(expectedTree) {
+		if _, ok := obtainedTree[rel]; !ok {
+			c.Errorf("missing entry %q in %q", rel, obtained)
+		}
+	}
+	for _, rel := range sortedKeys(obtainedTree) {
+		got := obtainedTree[rel]
+		want, ok := expectedTree[rel]
+		if !ok {
+			c.Errorf("unexpected entry %q in %q", rel, obtained)
+			continue
+		}
+		compareTreeEntry(c, rel, got, want, opts)
+	}
+	if !opts.IgnoreHardlinks {
+		obtainedGroups := hardlinkGroups(obtainedTree)
+		expectedGroups := hardlinkGroups(expectedTree)
+		for _, rel := range sortedKeys(obtainedTree) {
+			if _, ok := expectedTree[rel]; !ok {
+				continue
+			}
+			got, want := obtainedGroups[rel], expectedGroups[rel]
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				c.Errorf("%q is hard linked with %q, want %q", rel, got, want)
+			}
+		}
+	}
+}
+
+// treeEntry holds what CompareTrees knows about an entry.
+type treeEntry struct {
+	path string
+	info fs.FileInfo
+}
+
+func compareTreeEntry(c *gc.C, rel string, got, want treeEntry, opts CompareOptions) {
+	gotMode, wantMode := got.info.Mode(), want.info.Mode()
+	if gotMode.Type() != wantMode.Type() {
+		c.Errorf("%q has type %v, want %v", rel, gotMode.Type(), wantMode.Type())
+		return
+	}
+	// Skip until we implement proper permissions checking
+	if runtime.GOOS != "windows" && gotMode.Perm() != wantMode.Perm() {
+		c.Errorf("%q has permissions %v, want %v", rel, gotMode.Perm(), wantMode.Perm())
+	}
+	switch {
+	case gotMode&os.ModeSymlink != 0:
+		gotLink, err := os.Readlink(got.path)
+		c.Assert(err, gc.IsNil)
+		wantLink, err := os.Readlink(want.path)
+		c.Assert(err, gc.IsNil)
+		if gotLink != wantLink {
+			c.Errorf("%q links to %q, want %q", rel, gotLink, wantLink)
+		}
+	case gotMode.IsRegular():
+		gotData, err := ioutil.ReadFile(got.path)
+		c.Assert(err, gc.IsNil)
+		wantData, err := ioutil.ReadFile(want.path)
+		c.Assert(err, gc.IsNil)
+		if !bytes.Equal(gotData, wantData) {
+			c.Errorf("%q has different contents", rel)
+			return
+		}
+		if opts.IgnoreSparse {
+			return
+		}
+		gotRegions, err := DataRegions(got.path)
+		c.Assert(err, gc.IsNil)
+		wantRegions, err := DataRegions(want.path)
+		c.Assert(err, gc.IsNil)
+		if fmt.Sprint(gotRegions) != fmt.Sprint(wantRegions) {
+			c.Errorf("%q has data regions %v, want %v", rel, gotRegions, wantRegions)
+		}
+	}
+}
+
+// readTree returns every entry under dir, keyed by slash-separated
+// relative path.
+func readTree(dir string) (map[string]treeEntry, error) {
+	tree := make(map[string]treeEntry)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		tree[filepath.ToSlash(rel)] = treeEntry{path, info}
+		return nil
+	})
+	return tree, err
+}
+
+// hardlinkGroups returns, for each regular file in tree, the sorted
+// paths of the other files in tree that are hard linked to it.
+func hardlinkGroups(tree map[string]treeEntry) map[string][]string {
+	var files []string
+	for _, rel := range sortedKeys(tree) {
+		if tree[rel].info.Mode().IsRegular() {
+			files = append(files, rel)
+		}
+	}
+	groups := make(map[string][]string)
+	for i, a := range files {
+		for _, b := range files[i+1:] {
+			if os.SameFile(tree[a].info, tree[b].info) {
+				groups[a] = append(groups[a], b)
+				groups[b] = append(groups[b], a)
+			}
+		}
+	}
+	for _, group := range groups {
+		sort.Strings(group)
+	}
+	return groups
+}
+
+func sortedKeys(tree map[string]treeEntry) []string {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type LayoutSuite struct{}
+
+var _ = gc.Suite(&LayoutSuite{})
+
+const sparseSize = 4 << 20
+
+var sparse = ft.SparseFile{
+	Path: "sparse",
+	Size: sparseSize,
+	Data: map[int64]string{0: "start", sparseSize - 3: "end"},
+	Perm: 0644,
+}
+
+// skipUnlessHoles skips the test unless the file system holding dir
+// reports holes in sparse files.
+func skipUnlessHoles(c *gc.C, dir string) {
+	sparse.Create(c, dir)
+	regions, err := ft.DataRegions(filepath.Join(dir, sparse.Path))
+	c.Assert(err, jc.ErrorIsNil)
+	if len(regions) < 2 {
+		c.Skip("file system does not report holes")
+	}
+}
+
+func (s *LayoutSuite) TestHardlink(c *gc.C) {
+	dir := c.MkDir()
+	entries := ft.Entries{
+		ft.File{Path: "file", Data: "data", Perm: 0644},
+		ft.Hardlink{Path: "link", Link: "file"},
+	}
+	entries.Create(c, dir)
+	ft.AssertTree(c, dir, entries)
+}
+
+func (s *LayoutSuite) TestHardlinkCheckFailure(c *gc.C) {
+	dir := c.MkDir()
+	ft.Entries{
+		ft.File{Path: "file", Data: "data", Perm: 0644},
+		ft.File{Path: "copy", Data: "data", Perm: 0644},
+	}.Create(c, dir)
+	c.ExpectFailure("copy is not a hard link")
+	ft.Hardlink{Path: "copy", Link: "file"}.Check(c, dir)
+}
+
+func (s *LayoutSuite) TestSparseFile(c *gc.C) {
+	dir := c.MkDir()
+	sparse.Create(c, dir)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "sparse"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.HasLen, sparseSize)
+	c.Assert(string(data[:5]), gc.Equals, "start")
+	c.Assert(string(data[sparseSize-3:]), gc.Equals, "end")
+	sparse.Check(c, dir)
+}
+
+func (s *LayoutSuite) TestSparseFileFilled(c *gc.C) {
+	dir := c.MkDir()
+	skipUnlessHoles(c, dir)
+	err := ioutil.WriteFile(filepath.Join(dir, "sparse"), sparse.Content(), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	c.ExpectFailure("file is no longer sparse")
+	sparse.Check(c, dir)
+}
+
+func (s *LayoutSuite) TestDataRegionsEmpty(c *gc.C) {
+	dir := c.MkDir()
+	ft.File{Path: "empty", Perm: 0644}.Create(c, dir)
+	regions, err := ft.DataRegions(filepath.Join(dir, "empty"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(regions, gc.HasLen, 0)
+}
+
+func (s *LayoutSuite) TestCompareTreesEqual(c *gc.C) {
+	entries := ft.Entries{
+		ft.Dir{Path: "dir", Perm: 0755},
+		ft.File{Path: "dir/file", Data: "data", Perm: 0644},
+		ft.Hardlink{Path: "dir/link", Link: "dir/file"},
+		ft.Symlink{Path: "symlink", Link: "dir/file"},
+		sparse,
+	}
+	expected, obtained := c.MkDir(), c.MkDir()
+	entries.Create(c, expected)
+	entries.Create(c, obtained)
+	ft.CompareTrees(c, obtained, expected, ft.CompareOptions{})
+}
+
+func (s *LayoutSuite) TestCompareTreesContents(c *gc.C) {
+	expected, obtained := c.MkDir(), c.MkDir()
+	ft.Entries{
+		ft.File{Path: "a", Data: "a", Perm: 0644},
+		ft.File{Path: "b", Data: "b", Perm: 0644},
+		ft.Symlink{Path: "link", Link: "a"},
+	}.Create(c, expected)
+	ft.Entries{
+		ft.File{Path: "a", Data: "other", Perm: 0644},
+		ft.File{Path: "c", Data: "c", Perm: 0644},
+		ft.Symlink{Path: "link", Link: "c"},
+	}.Create(c, obtained)
+	c.ExpectFailure("trees differ")
+	ft.CompareTrees(c, obtained, expected, ft.CompareOptions{})
+}
+
+func (s *LayoutSuite) TestCompareTreesHardlinks(c *gc.C) {
+	expected, obtained := c.MkDir(), c.MkDir()
+	ft.Entries{
+		ft.File{Path: "file", Data: "data", Perm: 0644},
+		ft.Hardlink{Path: "link", Link: "file"},
+	}.Create(c, expected)
+	ft.Entries{
+		ft.File{Path: "file", Data: "data", Perm: 0644},
+		ft.File{Path: "link", Data: "data", Perm: 0644},
+	}.Create(c, obtained)
+	ft.CompareTrees(c, obtained, expected, ft.CompareOptions{IgnoreHardlinks: true})
+	c.ExpectFailure("hard links were broken")
+	ft.CompareTrees(c, obtained, expected, ft.CompareOptions{})
+}
+
+func (s *LayoutSuite) TestCompareTreesSparse(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("holes are only detected on linux")
+	}
+	expected, obtained := c.MkDir(), c.MkDir()
+	skipUnlessHoles(c, expected)
+	err := ioutil.WriteFile(filepath.Join(obtained, "sparse"), sparse.Content(), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ft.CompareTrees(c, obtained, expected, ft.CompareOptions{IgnoreSparse: true})
+	c.ExpectFailure("file is no longer sparse")
+	ft.CompareTrees(c, obtained, expected, ft.CompareOptions{})
+}
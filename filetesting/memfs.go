@@ -0,0 +1,304 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing/fstest"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// WriteFS is a file system that can be modified. Code that manipulates
+// files can accept a WriteFS so that it can be tested with a MemFS.
+type WriteFS interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+	Remove(name string) error
+	RemoveAll(name string) error
+	Symlink(oldname, newname string) error
+}
+
+// MemFS is an in-memory WriteFS. It is safe for concurrent use. As
+// with fs.FS, names are slash-separated and unrooted, for example
+// "dir/file.txt".
+//
+// Symbolic links can be inspected with Lstat and ReadLink. They are
+// followed when opening files only from Go 1.25, where fstest.MapFS,
+// which MemFS is built on, supports them.
+type MemFS struct {
+	mu    sync.Mutex
+	files fstest.MapFS
+}
+
+var _ WriteFS = (*MemFS)(nil)
+
+// NewMemFS returns a MemFS holding the given entries, which are
+// created in order as Entries.Create would create them on disk.
+// Parent directories are created as needed.
+func NewMemFS(c *gc.C, entries ...Entry) *MemFS {
+	m := &MemFS{files: make(fstest.MapFS)}
+	for _, entry := range entries {
+		var err error
+		switch entry := entry.(type) {
+		case Dir:
+			err = m.MkdirAll(entry.Path, entry.Perm)
+		case File:
+			err = m.MkdirAll(path.Dir(entry.Path), 0755)
+			if err == nil {
+				err = m.WriteFile(entry.Path, []byte(entry.Data), entry.Perm)
+			}
+		case Symlink:
+			err = m.MkdirAll(path.Dir(entry.Path), 0755)
+			if err == nil {
+				err = m.Symlink(entry.Link, entry.Path)
+			}
+		case Removed:
+			err = m.RemoveAll(entry.Path)
+		default:
+			c.Fatalf("unsupported entry type %T", entry)
+		}
+		c.Assert(err, gc.IsNil, gc.Commentf("entry %q", entry.GetPath()))
+	}
+	return m
+}
+
+// MapMemFS returns a MemFS holding a file for each element of files,
+// which maps file names to their contents. The files have mode 0644.
+func MapMemFS(files map[string]string) *MemFS {
+	m := &MemFS{files: make(fstest.MapFS)}
+	for name, data := range files {
+		m.files[name] = &fstest.MapFile{
+			Data: []byte(data),
+			Mode: 0644,
+		}
+	}
+	return m
+}
+
+// Open implements fs.FS.
+func (m *MemFS) Open(name string) (fs.File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.files.Open(name)
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.files.ReadFile(name)
+}
+
+// Stat implements fs.StatFS.
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.files.Stat(name)
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.files.ReadDir(name)
+}
+
+// Lstat returns information about the named file. If the file is a
+// symbolic link, it describes the link itself.
+func (m *MemFS) Lstat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f := m.files[name]; f != nil && f.Mode&fs.ModeSymlink != 0 {
+		return &linkInfo{name: path.Base(name), file: f}, nil
+	}
+	return m.files.Stat(name)
+}
+
+// ReadLink returns the target of the named symbolic link.
+func (m *MemFS) ReadLink(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f := m.files[name]
+	if f == nil || f.Mode&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return string(f.Data), nil
+}
+
+// WriteFile writes data to the named file, creating it with the given
+// permissions if necessary. The parent directory must already exist.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkCreate("open", name); err != nil {
+		return err
+	}
+	if f := m.files[name]; f != nil {
+		if f.Mode.IsDir() {
+			return &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+		}
+		perm = f.Mode.Perm()
+	}
+	// Files are replaced rather than modified so that files
+	// already opened are unaffected.
+	m.files[name] = &fstest.MapFile{
+		Data:    append([]byte(nil), data...),
+		Mode:    perm.Perm(),
+		ModTime: time.Now(),
+	}
+	return nil
+}
+
+// MkdirAll creates the named directory, and any missing parents, with
+// the given permissions.
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return nil
+	}
+	parts := strings.Split(name, "/")
+	for i := range parts {
+		dir := strings.Join(parts[:i+1], "/")
+		if info, err := m.files.Stat(dir); err == nil {
+			if !info.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: dir, Err: errNotDir}
+			}
+			if m.files[dir] != nil {
+				continue
+			}
+		}
+		m.files[dir] = &fstest.MapFile{
+			Mode:    fs.ModeDir | perm.Perm(),
+			ModTime: time.Now(),
+		}
+	}
+	return nil
+}
+
+// Remove removes the named file or empty directory.
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	info, err := m.files.Stat(name)
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if info.IsDir() && len(m.children(name)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+// RemoveAll removes the named file or directory and anything it
+// contains. It returns nil if name does not exist.
+func (m *MemFS) RemoveAll(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "removeall", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, child := range m.children(name) {
+		delete(m.files, child)
+	}
+	delete(m.files, name)
+	return nil
+}
+
+// Symlink creates newname as a symbolic link to oldname.
+func (m *MemFS) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkCreate("symlink", newname); err != nil {
+		return err
+	}
+	if m.files[newname] != nil {
+		return &fs.PathError{Op: "symlink", Path: newname, Err: fs.ErrExist}
+	}
+	m.files[newname] = &fstest.MapFile{
+		Data:    []byte(oldname),
+		Mode:    fs.ModeSymlink | 0777,
+		ModTime: time.Now(),
+	}
+	return nil
+}
+
+// Files returns the names of all the files and symbolic links in
+// the file system, in lexical order.
+func (m *MemFS) Files() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var names []string
+	for name, f := range m.files {
+		if !f.Mode.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkCreate checks that name can be created: that it is valid and
+// that its parent is an existing directory. It must be called with
+// m.mu held.
+func (m *MemFS) checkCreate(op, name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := m.files.Stat(path.Dir(name))
+	if err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if !info.IsDir() {
+		return &fs.PathError{Op: op, Path: name, Err: errNotDir}
+	}
+	return nil
+}
+
+// children returns the names of all the entries below the named
+// directory. It must be called with m.mu held.
+func (m *MemFS) children(dir string) []string {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+	var names []string
+	for name := range m.files {
+		if strings.HasPrefix(name, prefix) && name != dir {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// linkInfo describes a symbolic link in a MemFS.
+type linkInfo struct {
+	name string
+	file *fstest.MapFile
+}
+
+func (i *linkInfo) Name() string       { return i.name }
+func (i *linkInfo) Size() int64        { return int64(len(i.file.Data)) }
+func (i *linkInfo) Mode() fs.FileMode  { return i.file.Mode }
+func (i *linkInfo) ModTime() time.Time { return i.file.ModTime }
+func (i *linkInfo) IsDir() bool        { return false }
+func (i *linkInfo) Sys() interface{}   { return nil }
+
+var (
+	errIsDir    = errors.New("is a directory")
+	errNotDir   = errors.New("not a directory")
+	errNotEmpty = errors.New("directory not empty")
+)
@@ -0,0 +1,120 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing/fstest"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type MemFSSuite struct{}
+
+var _ = gc.Suite(&MemFSSuite{})
+
+func (s *MemFSSuite) TestNewMemFS(c *gc.C) {
+	m := ft.NewMemFS(c,
+		ft.Dir{Path: "etc", Perm: 0750},
+		ft.File{Path: "etc/conf/app.yaml", Data: "a: 1\n", Perm: 0640},
+		ft.File{Path: "tmp/scratch", Data: "x", Perm: 0644},
+		ft.Symlink{Path: "etc/current", Link: "conf"},
+		ft.Removed{Path: "tmp"},
+	)
+	c.Assert(m.Files(), jc.DeepEquals, []string{"etc/conf/app.yaml", "etc/current"})
+	data, err := m.ReadFile("etc/conf/app.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "a: 1\n")
+	link, err := m.ReadLink("etc/current")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(link, gc.Equals, "conf")
+
+	err = fstest.TestFS(m, "etc/conf/app.yaml")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MemFSSuite) TestMapMemFS(c *gc.C) {
+	m := ft.MapMemFS(map[string]string{
+		"a.txt":     "a",
+		"dir/b.txt": "b",
+	})
+	err := fstest.TestFS(m, "a.txt", "dir/b.txt")
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := m.Stat("dir")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.IsDir(), jc.IsTrue)
+}
+
+func (s *MemFSSuite) TestWriteFile(c *gc.C) {
+	m := ft.MapMemFS(nil)
+	err := m.WriteFile("missing/file", []byte("x"), 0644)
+	c.Assert(errors.Is(err, fs.ErrNotExist), jc.IsTrue)
+
+	err = m.WriteFile("file", []byte("one"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	f, err := m.Open("file")
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+
+	// Rewriting keeps the permissions and does not affect
+	// files already open.
+	err = m.WriteFile("file", []byte("two"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	buf := make([]byte, 10)
+	n, _ := f.Read(buf)
+	c.Assert(string(buf[:n]), gc.Equals, "one")
+	ft.CheckFS(c, m, ft.File{Path: "file", Data: "two", Perm: 0600})
+
+	err = m.WriteFile("file/sub", []byte("x"), 0644)
+	c.Assert(err, gc.ErrorMatches, "open file/sub: not a directory")
+	err = m.WriteFile("/abs", []byte("x"), 0644)
+	c.Assert(errors.Is(err, fs.ErrInvalid), jc.IsTrue)
+}
+
+func (s *MemFSSuite) TestMkdirAll(c *gc.C) {
+	m := ft.MapMemFS(map[string]string{"file": ""})
+	err := m.MkdirAll("a/b/c", 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.MkdirAll("a/b", 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	ft.CheckFS(c, m, ft.Dir{Path: "a/b/c", Perm: 0700}, ft.Dir{Path: "a/b", Perm: 0700})
+	err = m.MkdirAll("file/sub", 0755)
+	c.Assert(err, gc.ErrorMatches, "mkdir file: not a directory")
+}
+
+func (s *MemFSSuite) TestRemove(c *gc.C) {
+	m := ft.MapMemFS(map[string]string{
+		"dir/a":     "a",
+		"dir/sub/b": "b",
+		"other":     "",
+	})
+	err := m.Remove("dir")
+	c.Assert(err, gc.ErrorMatches, "remove dir: directory not empty")
+	err = m.Remove("dir/a")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Remove("dir/a")
+	c.Assert(errors.Is(err, fs.ErrNotExist), jc.IsTrue)
+	err = m.RemoveAll("dir")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.RemoveAll("dir")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Files(), jc.DeepEquals, []string{"other"})
+}
+
+func (s *MemFSSuite) TestSymlink(c *gc.C) {
+	m := ft.MapMemFS(map[string]string{"target": "x"})
+	err := m.Symlink("target", "link")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.Symlink("target", "link")
+	c.Assert(errors.Is(err, fs.ErrExist), jc.IsTrue)
+	info, err := m.Lstat("link")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode()&fs.ModeSymlink, gc.Not(gc.Equals), fs.FileMode(0))
+	_, err = m.ReadLink("target")
+	c.Assert(err, gc.ErrorMatches, "readlink target: invalid argument")
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Whence values for lseek that are not defined by the syscall package.
+const (
+	seekData = 3
+	seekHole = 4
+)
+
+var errHolesNotSupported = errors.New("holes not supported")
+
+// dataRegions returns the data regions of f, which has the given size,
+// by seeking between data and holes.
+func dataRegions(f *os.File, size int64) ([]Region, error) {
+	regions := []Region{}
+	for offset := int64(0); offset < size; {
+		start, err := f.Seek(offset, seekData)
+		if errors.Is(err, syscall.ENXIO) {
+			// There is no more data.
+			break
+		}
+		if errors.Is(err, syscall.EINVAL) {
+			return nil, errHolesNotSupported
+		}
+		if err != nil {
+			return nil, err
+		}
+		end, err := f.Seek(start, seekHole)
+		if err != nil {
+			return nil, err
+		}
+		regions = append(regions, Region{start, end - start})
+		offset = end
+	}
+	return regions, nil
+}
@@ -0,0 +1,19 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !linux
+
+package filetesting
+
+import (
+	"errors"
+	"os"
+)
+
+var errHolesNotSupported = errors.New("holes not supported")
+
+// dataRegions always returns errHolesNotSupported, as holes are
+// only detected on linux.
+func dataRegions(f *os.File, size int64) ([]Region, error) {
+	return nil, errHolesNotSupported
+}
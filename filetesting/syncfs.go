@@ -0,0 +1,235 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+// SyncFS holds the file system operations used by code that writes
+// files crash-safely. Such code can take a SyncFS so that tests can
+// substitute a RecordingFS for OSSyncFS.
+type SyncFS interface {
+	OpenFile(name string, flag int, perm os.FileMode) (SyncFile, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+}
+
+// SyncFile is a file opened by a SyncFS. It is implemented by *os.File.
+type SyncFile interface {
+	io.Writer
+	io.Closer
+	Sync() error
+	Name() string
+}
+
+// OSSyncFS implements SyncFS using the os package.
+type OSSyncFS struct{}
+
+var _ SyncFS = OSSyncFS{}
+
+func (OSSyncFS) OpenFile(name string, flag int, perm os.FileMode) (SyncFile, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (OSSyncFS) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+func (OSSyncFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// RecordingFS is a SyncFS that records every call made on it, and on
+// the files it opens, before passing the call on to FS. A call fails
+// without being passed on if Stub has an error queued for it.
+//
+// The recorded calls are OpenFile(name, flag, perm), Write(name, data),
+// Sync(name), Close(name), Rename(oldpath, newpath) and Remove(name),
+// where name is the name the file was opened with.
+type RecordingFS struct {
+	Stub *testing.Stub
+	FS   SyncFS
+}
+
+var _ SyncFS = (*RecordingFS)(nil)
+
+// NewRecordingFS returns a RecordingFS wrapping fs, or OSSyncFS if
+// fs is nil.
+func NewRecordingFS(fs SyncFS) *RecordingFS {
+	if fs == nil {
+		fs = OSSyncFS{}
+	}
+	return &RecordingFS{
+		Stub: &testing.Stub{},
+		FS:   fs,
+	}
+}
+
+func (r *RecordingFS) OpenFile(name string, flag int, perm os.FileMode) (SyncFile, error) {
+	r.Stub.AddCall("OpenFile", name, flag, perm)
+	if err := r.Stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	f, err := r.FS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingFile{
+		stub: r.Stub,
+		name: name,
+		file: f,
+	}, nil
+}
+
+func (r *RecordingFS) Rename(oldpath, newpath string) error {
+	r.Stub.AddCall("Rename", oldpath, newpath)
+	if err := r.Stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	return r.FS.Rename(oldpath, newpath)
+}
+
+func (r *RecordingFS) Remove(name string) error {
+	r.Stub.AddCall("Remove", name)
+	if err := r.Stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	return r.FS.Remove(name)
+}
+
+// CheckAtomicWrite checks that path was written atomically: that it
+// was never opened for writing, and that the last file renamed onto
+// it was synced after it was last written, and closed, before the
+// rename. It reports whether the checks passed.
+func (r *RecordingFS) CheckAtomicWrite(c *gc.C, path string) bool {
+	calls := r.Stub.Calls()
+	path = filepath.Clean(path)
+	ok := true
+	rename := -1
+	var tmp string
+	for i, call := range calls {
+		switch call.FuncName {
+		case "OpenFile":
+			if sameFile(call.Args[0], path) && call.Args[1].(int)&(os.O_WRONLY|os.O_RDWR) != 0 {
+				c.Errorf("%q opened for writing in place", path)
+				ok = false
+			}
+		case "Rename":
+			if sameFile(call.Args[1], path) {
+				rename, tmp = i, call.Args[0].(string)
+			}
+		}
+	}
+	if rename < 0 {
+		c.Errorf("%q was not renamed into place", path)
+		return false
+	}
+	written, synced, closed := -1, -1, -1
+	for i, call := range calls[:rename] {
+		if len(call.Args) == 0 || !sameFile(call.Args[0], tmp) {
+			continue
+		}
+		switch call.FuncName {
+		case "OpenFile":
+			written, synced, closed = -1, -1, -1
+		case "Write":
+			written = i
+		case "Sync":
+			synced = i
+		case "Close":
+			closed = i
+		}
+	}
+	if written < 0 {
+		c.Errorf("%q renamed onto %q was not written", tmp, path)
+		return false
+	}
+	if synced < written {
+		c.Errorf("%q not synced before being renamed onto %q", tmp, path)
+		ok = false
+	}
+	if closed < written {
+		c.Errorf("%q not closed before being renamed onto %q", tmp, path)
+		ok = false
+	}
+	return ok
+}
+
+// CheckDirSynced checks that the directory containing path was synced
+// after the last file was renamed onto path, so that the rename itself
+// is durable. It reports whether the check passed.
+func (r *RecordingFS) CheckDirSynced(c *gc.C, path string) bool {
+	calls := r.Stub.Calls()
+	path = filepath.Clean(path)
+	dir := filepath.Dir(path)
+	rename := -1
+	for i, call := range calls {
+		if call.FuncName == "Rename" && sameFile(call.Args[1], path) {
+			rename = i
+		}
+	}
+	if rename < 0 {
+		c.Errorf("%q was not renamed into place", path)
+		return false
+	}
+	for _, call := range calls[rename+1:] {
+		if call.FuncName == "Sync" && sameFile(call.Args[0], dir) {
+			return true
+		}
+	}
+	c.Errorf("directory %q not synced after rename onto %q", dir, path)
+	return false
+}
+
+// sameFile reports whether the recorded argument arg names path.
+func sameFile(arg interface{}, path string) bool {
+	name, ok := arg.(string)
+	return ok && filepath.Clean(name) == path
+}
+
+// recordingFile is the SyncFile returned by RecordingFS.OpenFile.
+type recordingFile struct {
+	stub *testing.Stub
+	name string
+	file SyncFile
+}
+
+func (f *recordingFile) Write(data []byte) (int, error) {
+	f.stub.AddCall("Write", f.name, append([]byte(nil), data...))
+	if err := f.stub.NextErr(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return f.file.Write(data)
+}
+
+func (f *recordingFile) Sync() error {
+	f.stub.AddCall("Sync", f.name)
+	if err := f.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	return f.file.Sync()
+}
+
+func (f *recordingFile) Close() error {
+	f.stub.AddCall("Close", f.name)
+	if err := f.stub.NextErr(); err != nil {
+		// Close the underlying file anyway so that it is not
+		// leaked by the test.
+		f.file.Close()
+		return errors.Trace(err)
+	}
+	return f.file.Close()
+}
+
+func (f *recordingFile) Name() string {
+	return f.file.Name()
+}
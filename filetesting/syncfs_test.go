@@ -0,0 +1,116 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type SyncFSSuite struct{}
+
+var _ = gc.Suite(&SyncFSSuite{})
+
+// writeFile writes data to path in the way that crash-safe code should,
+// with optional steps omitted.
+func writeFile(fs ft.SyncFS, path string, data string, sync, syncDir bool) error {
+	tmp := path + ".tmp"
+	f, err := fs.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte(data)); err != nil {
+		f.Close()
+		return err
+	}
+	if sync {
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := fs.Rename(tmp, path); err != nil {
+		return err
+	}
+	if !syncDir {
+		return nil
+	}
+	dir, err := fs.OpenFile(filepath.Dir(path), os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+func (s *SyncFSSuite) TestAtomicWrite(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "config")
+	fs := ft.NewRecordingFS(nil)
+	err := writeFile(fs, path, "data", true, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(fs.CheckAtomicWrite(c, path), jc.IsTrue)
+	c.Assert(fs.CheckDirSynced(c, path), jc.IsTrue)
+	fs.Stub.CheckCallNames(c, "OpenFile", "Write", "Sync", "Close", "Rename", "OpenFile", "Sync", "Close")
+	fs.Stub.CheckCall(c, 1, "Write", path+".tmp", []byte("data"))
+	ft.File{Path: "config", Data: "data", Perm: 0644}.Check(c, filepath.Dir(path))
+}
+
+func (s *SyncFSSuite) TestNotSynced(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "config")
+	fs := ft.NewRecordingFS(nil)
+	err := writeFile(fs, path, "data", false, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.ExpectFailure("file was not synced")
+	fs.CheckAtomicWrite(c, path)
+}
+
+func (s *SyncFSSuite) TestDirNotSynced(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "config")
+	fs := ft.NewRecordingFS(nil)
+	err := writeFile(fs, path, "data", true, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fs.CheckAtomicWrite(c, path), jc.IsTrue)
+
+	c.ExpectFailure("directory was not synced")
+	fs.CheckDirSynced(c, path)
+}
+
+func (s *SyncFSSuite) TestWrittenInPlace(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "config")
+	fs := ft.NewRecordingFS(nil)
+	f, err := fs.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = f.Write([]byte("data"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = f.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.ExpectFailure("file was not renamed into place")
+	fs.CheckAtomicWrite(c, path)
+}
+
+func (s *SyncFSSuite) TestInjectedErrors(c *gc.C) {
+	dir := c.MkDir()
+	path := filepath.Join(dir, "config")
+	fs := ft.NewRecordingFS(nil)
+	failure := errors.New("disk full")
+	fs.Stub.SetErrors(nil, nil, failure)
+	err := writeFile(fs, path, "data", true, true)
+	c.Assert(errors.Cause(err), gc.Equals, failure)
+
+	// The failed sync must leave the original file untouched.
+	fs.Stub.CheckCallNames(c, "OpenFile", "Write", "Sync", "Close")
+	ft.Removed{Path: "config"}.Check(c, dir)
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+)
+
+// TempDirSuite provides a fresh temporary directory for each test.
+// If the test calls ExpectTree, the directory is checked against the
+// expected entries when the test finishes.
+type TempDirSuite struct {
+	// Dir holds the temporary directory for the current test.
+	Dir string
+
+	expected Entries
+}
+
+func (s *TempDirSuite) SetUpSuite(c *gc.C) {}
+
+func (s *TempDirSuite) TearDownSuite(c *gc.C) {}
+
+func (s *TempDirSuite) SetUpTest(c *gc.C) {
+	s.Dir = c.MkDir()
+	s.expected = nil
+}
+
+func (s *TempDirSuite) TearDownTest(c *gc.C) {
+	if s.expected != nil {
+		AssertTree(c, s.Dir, s.expected)
+		s.expected = nil
+	}
+}
+
+// Path joins the given slash-separated path elements to the
+// temporary directory.
+func (s *TempDirSuite) Path(elems ...string) string {
+	return join(s.Dir, path.Join(elems...))
+}
+
+// Create creates the given entries in the temporary directory.
+func (s *TempDirSuite) Create(c *gc.C, entries ...Entry) {
+	Entries(entries).Create(c, s.Dir)
+}
+
+// AssertTree checks that the temporary directory holds exactly the
+// given entries, as the package function of the same name does.
+func (s *TempDirSuite) AssertTree(c *gc.C, entries ...Entry) {
+	AssertTree(c, s.Dir, entries)
+}
+
+// ExpectTree arranges for the temporary directory to be checked
+// against the given entries with AssertTree when the test finishes,
+// replacing any earlier expectation.
+func (s *TempDirSuite) ExpectTree(entries ...Entry) {
+	s.expected = append(Entries{}, entries...)
+}
+
+// AssertTree checks each of the expected entries, as Entries.Check
+// does, and also fails if the directory holds anything not described
+// by them. Parent directories of expected entries need not be listed;
+// Removed entries only check that the path is absent.
+func AssertTree(c *gc.C, dir string, expected Entries) {
+	expected.Check(c, dir)
+	allowed := make(map[string]bool)
+	for _, entry := range expected {
+		if _, ok := entry.(Removed); ok {
+			continue
+		}
+		for p := path.Clean(entry.GetPath()); p != "." && p != "/"; p = path.Dir(p) {
+			allowed[p] = true
+		}
+	}
+	var unexpected []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." || allowed[rel] {
+			return nil
+		}
+		unexpected = append(unexpected, rel)
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	c.Assert(err, gc.IsNil)
+	sort.Strings(unexpected)
+	for _, p := range unexpected {
+		c.Errorf("unexpected entry %q in %q", p, dir)
+	}
+}
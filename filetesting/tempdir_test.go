@@ -0,0 +1,88 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type TempDirSuite struct {
+	ft.TempDirSuite
+}
+
+var _ = gc.Suite(&TempDirSuite{})
+
+var previousTempDir string
+
+func (s *TempDirSuite) TestFreshDir(c *gc.C) {
+	c.Assert(s.Dir, jc.IsDirectory)
+	c.Assert(s.Dir, gc.Not(gc.Equals), previousTempDir)
+	previousTempDir = s.Dir
+	entries, err := ioutil.ReadDir(s.Dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
+
+func (s *TempDirSuite) TestFreshDirAgain(c *gc.C) {
+	s.TestFreshDir(c)
+}
+
+func (s *TempDirSuite) TestPath(c *gc.C) {
+	c.Assert(s.Path("a/b", "c"), gc.Equals, filepath.Join(s.Dir, "a", "b", "c"))
+	c.Assert(s.Path(), gc.Equals, s.Dir)
+}
+
+func (s *TempDirSuite) TestAssertTree(c *gc.C) {
+	s.Create(c,
+		ft.Dir{Path: "a", Perm: 0755},
+		ft.Dir{Path: "a/b", Perm: 0755},
+		ft.File{Path: "a/b/file", Data: "data", Perm: 0644},
+		ft.Symlink{Path: "link", Link: "a"},
+	)
+	s.AssertTree(c,
+		ft.File{Path: "a/b/file", Data: "data", Perm: 0644},
+		ft.Symlink{Path: "link", Link: "a"},
+		ft.Removed{Path: "gone"},
+	)
+}
+
+func (s *TempDirSuite) TestAssertTreeUnexpected(c *gc.C) {
+	s.Create(c,
+		ft.Dir{Path: "a", Perm: 0755},
+		ft.File{Path: "a/file", Data: "data", Perm: 0644},
+		ft.File{Path: "a/extra", Data: "", Perm: 0644},
+	)
+	c.ExpectFailure("a/extra is not expected")
+	s.AssertTree(c, ft.File{Path: "a/file", Data: "data", Perm: 0644})
+}
+
+func (s *TempDirSuite) TestExpectTree(c *gc.C) {
+	s.ExpectTree(ft.File{Path: "out", Data: "result", Perm: 0644})
+	err := ioutil.WriteFile(s.Path("out"), []byte("result"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Chmod(s.Path("out"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type expectTreeSuite struct {
+	suite ft.TempDirSuite
+}
+
+var _ = gc.Suite(&expectTreeSuite{})
+
+func (s *expectTreeSuite) TestExpectTreeFailsAtTearDown(c *gc.C) {
+	s.suite.SetUpTest(c)
+	s.suite.ExpectTree(ft.File{Path: "out", Data: "result", Perm: 0644})
+	err := ioutil.WriteFile(s.suite.Path("leftover"), nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	c.ExpectFailure("out is missing and leftover is unexpected")
+	s.suite.TearDownTest(c)
+}
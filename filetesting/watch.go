@@ -0,0 +1,304 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting
+
+import (
+	"bytes"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+// EventOp describes the kind of change seen by a Watcher.
+type EventOp int
+
+const (
+	Create EventOp = iota + 1
+	Modify
+	Delete
+)
+
+func (op EventOp) String() string {
+	switch op {
+	case Create:
+		return "create"
+	case Modify:
+		return "modify"
+	case Delete:
+		return "delete"
+	}
+	return "unknown"
+}
+
+// Event describes a single change to a watched directory. Path is
+// slash-separated and relative to the watched directory.
+type Event struct {
+	Path string
+	Op   EventOp
+}
+
+// WatchPollInterval holds how often a Watcher scans its directory.
+var WatchPollInterval = 10 * time.Millisecond
+
+// Watcher records the changes made to a directory tree while a test
+// runs, so that the test can assert on the files written or removed
+// by hot-reload or configuration watching code.
+//
+// Changes are found by polling, so several changes to the same path
+// between two scans are seen as one, and changes to different paths
+// within a scan are reported in path order. Conversely, a file
+// rewritten in place may be seen as modified more than once, as it is
+// truncated before being written; AssertCoalescedEvents allows for
+// this.
+type Watcher struct {
+	dir string
+
+	mu      sync.Mutex
+	events  []Event
+	err     error
+	changed chan struct{}
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// fileState holds what a Watcher knows about a path.
+type fileState struct {
+	mode    os.FileMode
+	size    int64
+	modTime time.Time
+	data    []byte
+}
+
+// Watch starts watching the tree rooted at dir. Changes made after
+// Watch returns will be recorded. The caller must call Stop when
+// finished with the watcher.
+func Watch(c *gc.C, dir string) *Watcher {
+	state, err := scanTree(dir)
+	c.Assert(err, gc.IsNil)
+	w := &Watcher{
+		dir:     dir,
+		changed: make(chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.loop(state)
+	return w
+}
+
+// Stop stops the watcher, recording any changes made before it was
+// called.
+func (w *Watcher) Stop() {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	<-w.done
+}
+
+// Events returns the changes seen so far.
+func (w *Watcher) Events() []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Event(nil), w.events...)
+}
+
+// AssertEvents waits until the watcher has seen the expected changes,
+// in order, and fails the test if it has not done so within LongWait
+// or if it has seen any others.
+func (w *Watcher) AssertEvents(c *gc.C, expected ...Event) {
+	w.assertEvents(c, func(events []Event) []Event { return events }, expected)
+}
+
+// AssertCoalescedEvents is like AssertEvents but compares the
+// expected changes with the result of CoalesceEvents, so that a test
+// need not depend on how the code under test writes each file.
+func (w *Watcher) AssertCoalescedEvents(c *gc.C, expected ...Event) {
+	w.assertEvents(c, CoalesceEvents, expected)
+}
+
+func (w *Watcher) assertEvents(c *gc.C, filter func([]Event) []Event, expected []Event) {
+	if expected == nil {
+		expected = []Event{}
+	}
+	timeout := time.After(testing.LongWait)
+	for {
+		w.mu.Lock()
+		changed, err := w.changed, w.err
+		events := filter(append([]Event{}, w.events...))
+		w.mu.Unlock()
+		c.Assert(err, gc.IsNil)
+		if len(events) >= len(expected) {
+			// Allow a short time for any unexpected trailing
+			// changes to be seen before comparing.
+			time.Sleep(2 * WatchPollInterval)
+			w.mu.Lock()
+			events = filter(append([]Event{}, w.events...))
+			w.mu.Unlock()
+			c.Assert(events, jc.DeepEquals, expected)
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			c.Assert(events, jc.DeepEquals, expected, gc.Commentf("timed out waiting for events"))
+			return
+		}
+	}
+}
+
+func (w *Watcher) loop(state map[string]fileState) {
+	defer close(w.done)
+	for {
+		var stopped bool
+		select {
+		case <-w.stop:
+			stopped = true
+		case <-time.After(WatchPollInterval):
+		}
+		newState, err := scanTree(w.dir)
+		if err != nil {
+			w.mu.Lock()
+			w.err = err
+			w.mu.Unlock()
+			return
+		}
+		if events := diffTrees(state, newState); len(events) > 0 {
+			w.mu.Lock()
+			w.events = append(w.events, events...)
+			close(w.changed)
+			w.changed = make(chan struct{})
+			w.mu.Unlock()
+		}
+		state = newState
+		if stopped {
+			return
+		}
+	}
+}
+
+// scanTree returns the state of every entry under dir, keyed by
+// slash-separated relative path.
+func scanTree(dir string) (map[string]fileState, error) {
+	state := make(map[string]fileState)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			// Removed while we were scanning.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		info, err := d.Info()
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Only the mode of other entries is compared, as the size
+		// and modification time of a directory change whenever its
+		// entries do.
+		fst := fileState{mode: info.Mode()}
+		if info.Mode().IsRegular() {
+			fst.size = info.Size()
+			fst.modTime = info.ModTime()
+			// Compare contents as well, as a rewrite may not change
+			// the size or, on coarse file systems, the modification
+			// time.
+			fst.data, err = ioutil.ReadFile(path)
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		state[filepath.ToSlash(rel)] = fst
+		return nil
+	})
+	return state, err
+}
+
+// diffTrees returns the changes from old to new in path order.
+func diffTrees(old, new map[string]fileState) []Event {
+	var events []Event
+	for path, newState := range new {
+		oldState, ok := old[path]
+		switch {
+		case !ok:
+			events = append(events, Event{path, Create})
+		case oldState.mode.Type() != newState.mode.Type():
+			events = append(events, Event{path, Delete}, Event{path, Create})
+		case oldState.mode != newState.mode ||
+			oldState.size != newState.size ||
+			!oldState.modTime.Equal(newState.modTime) ||
+			!bytes.Equal(oldState.data, newState.data):
+			events = append(events, Event{path, Modify})
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			events = append(events, Event{path, Delete})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Path < events[j].Path
+	})
+	return events
+}
+
+// CoalesceEvents returns the net change to each path described by
+// events, in the order each path was first changed. A path that is
+// created and then modified is reported as created, one that is
+// deleted and created again as modified, and one that is created and
+// then deleted is not reported at all.
+func CoalesceEvents(events []Event) []Event {
+	var paths []string
+	net := make(map[string]EventOp)
+	for _, e := range events {
+		op, ok := net[e.Path]
+		if !ok {
+			paths = append(paths, e.Path)
+			net[e.Path] = e.Op
+			continue
+		}
+		switch {
+		case op == Create && e.Op == Delete:
+			op = 0
+		case op == Create:
+		case op == Delete && e.Op == Create:
+			op = Modify
+		case op == 0 && e.Op == Create:
+			op = Create
+		default:
+			op = e.Op
+		}
+		net[e.Path] = op
+	}
+	result := []Event{}
+	for _, path := range paths {
+		if op := net[path]; op != 0 {
+			result = append(result, Event{path, op})
+		}
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package filetesting_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	ft "github.com/juju/testing/filetesting"
+)
+
+type WatchSuite struct{}
+
+var _ = gc.Suite(&WatchSuite{})
+
+func (s *WatchSuite) TestFileChanges(c *gc.C) {
+	dir := c.MkDir()
+	ft.File{Path: "existing", Data: "a", Perm: 0644}.Create(c, dir)
+	ft.File{Path: "gone", Data: "a", Perm: 0644}.Create(c, dir)
+	w := ft.Watch(c, dir)
+	defer w.Stop()
+
+	ft.File{Path: "new", Data: "b", Perm: 0644}.Create(c, dir)
+	w.AssertCoalescedEvents(c, ft.Event{"new", ft.Create})
+
+	// Writing a file may be seen as more than one change, as it is
+	// created or truncated before being written.
+	err := ioutil.WriteFile(filepath.Join(dir, "existing"), []byte("b"), 0644)
+	c.Assert(err, gc.IsNil)
+	w.AssertCoalescedEvents(c,
+		ft.Event{"new", ft.Create},
+		ft.Event{"existing", ft.Modify},
+	)
+
+	err = os.Remove(filepath.Join(dir, "gone"))
+	c.Assert(err, gc.IsNil)
+	w.AssertCoalescedEvents(c,
+		ft.Event{"new", ft.Create},
+		ft.Event{"existing", ft.Modify},
+		ft.Event{"gone", ft.Delete},
+	)
+}
+
+func (s *WatchSuite) TestSubdirectories(c *gc.C) {
+	dir := c.MkDir()
+	w := ft.Watch(c, dir)
+	defer w.Stop()
+
+	ft.Dir{Path: "sub", Perm: 0755}.Create(c, dir)
+	w.AssertEvents(c, ft.Event{"sub", ft.Create})
+	ft.File{Path: "sub/file", Data: "x", Perm: 0644}.Create(c, dir)
+	w.AssertCoalescedEvents(c,
+		ft.Event{"sub", ft.Create},
+		ft.Event{"sub/file", ft.Create},
+	)
+}
+
+func (s *WatchSuite) TestAssertEventsUnexpected(c *gc.C) {
+	dir := c.MkDir()
+	w := ft.Watch(c, dir)
+	defer w.Stop()
+
+	ft.File{Path: "a", Data: "a", Perm: 0644}.Create(c, dir)
+	ft.File{Path: "b", Data: "b", Perm: 0644}.Create(c, dir)
+	w.Stop()
+	c.ExpectFailure("events do not match")
+	w.AssertEvents(c, ft.Event{"a", ft.Create})
+}
+
+func (s *WatchSuite) TestStopRecordsPendingChanges(c *gc.C) {
+	dir := c.MkDir()
+	w := ft.Watch(c, dir)
+	ft.File{Path: "a", Data: "a", Perm: 0644}.Create(c, dir)
+	w.Stop()
+	w.Stop()
+	c.Assert(ft.CoalesceEvents(w.Events()), jc.DeepEquals, []ft.Event{{"a", ft.Create}})
+}
+
+func (s *WatchSuite) TestAssertCoalescedEvents(c *gc.C) {
+	dir := c.MkDir()
+	ft.File{Path: "config", Data: "old", Perm: 0644}.Create(c, dir)
+	w := ft.Watch(c, dir)
+	defer w.Stop()
+
+	// Write the configuration atomically, as much reloading code
+	// expects.
+	ft.File{Path: "config.tmp", Data: "new", Perm: 0644}.Create(c, dir)
+	w.AssertCoalescedEvents(c, ft.Event{"config.tmp", ft.Create})
+	err := os.Rename(filepath.Join(dir, "config.tmp"), filepath.Join(dir, "config"))
+	c.Assert(err, gc.IsNil)
+	w.AssertCoalescedEvents(c, ft.Event{"config", ft.Modify})
+}
+
+var coalesceTests = []struct {
+	about    string
+	events   []ft.Event
+	expected []ft.Event
+}{{
+	about:    "no events",
+	expected: []ft.Event{},
+}, {
+	about: "create then modify",
+	events: []ft.Event{
+		{"a", ft.Create},
+		{"a", ft.Modify},
+		{"a", ft.Modify},
+	},
+	expected: []ft.Event{{"a", ft.Create}},
+}, {
+	about: "repeated modify",
+	events: []ft.Event{
+		{"a", ft.Modify},
+		{"b", ft.Create},
+		{"a", ft.Modify},
+	},
+	expected: []ft.Event{{"a", ft.Modify}, {"b", ft.Create}},
+}, {
+	about: "create then delete",
+	events: []ft.Event{
+		{"a", ft.Create},
+		{"a", ft.Modify},
+		{"a", ft.Delete},
+	},
+	expected: []ft.Event{},
+}, {
+	about: "create, delete and create again",
+	events: []ft.Event{
+		{"a", ft.Create},
+		{"a", ft.Delete},
+		{"a", ft.Create},
+	},
+	expected: []ft.Event{{"a", ft.Create}},
+}, {
+	about: "delete then create",
+	events: []ft.Event{
+		{"a", ft.Delete},
+		{"a", ft.Create},
+	},
+	expected: []ft.Event{{"a", ft.Modify}},
+}, {
+	about: "modify then delete",
+	events: []ft.Event{
+		{"a", ft.Modify},
+		{"a", ft.Delete},
+	},
+	expected: []ft.Event{{"a", ft.Delete}},
+}}
+
+func (s *WatchSuite) TestCoalesceEvents(c *gc.C) {
+	for i, test := range coalesceTests {
+		c.Logf("test %d: %s", i, test.about)
+		c.Check(ft.CoalesceEvents(test.events), jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *WatchSuite) TestEventOpString(c *gc.C) {
+	c.Assert(ft.Create.String(), gc.Equals, "create")
+	c.Assert(ft.Modify.String(), gc.Equals, "modify")
+	c.Assert(ft.Delete.String(), gc.Equals, "delete")
+}
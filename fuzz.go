@@ -0,0 +1,132 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	stdtesting "testing"
+)
+
+// The native fuzzer only generates arguments of basic types, so
+// structured values are fuzzed as their JSON encoding, held in a single
+// []byte argument. The fuzzer mutates the encoding, and the fuzz target
+// decodes it again, skipping inputs that do not decode to a valid
+// value.
+
+// AddFuzzCases adds the given values to the seed corpus of f, each as
+// the JSON encoding of the value. The fuzz target should be run with
+// FuzzValues to decode them again.
+func AddFuzzCases[T any](f *stdtesting.F, cases ...T) {
+	f.Helper()
+	for i, v := range cases {
+		data, err := json.Marshal(v)
+		if err != nil {
+			f.Fatalf("cannot marshal fuzz case %d: %v", i, err)
+		}
+		f.Add(data)
+	}
+}
+
+// AddFuzzTable adds a value taken from each of the given table test
+// cases to the seed corpus of f, so that the cases used by RunTable
+// also seed the fuzzer:
+//
+//	func FuzzParse(f *stdtesting.F) {
+//		testing.AddFuzzTable(f, parseTests, func(test parseTest) string {
+//			return test.input
+//		})
+//		testing.FuzzValues(f, nil, func(t *stdtesting.T, input string) {
+//			...
+//		})
+//	}
+func AddFuzzTable[C, T any](f *stdtesting.F, cases []C, input func(test C) T) {
+	f.Helper()
+	values := make([]T, len(cases))
+	for i, test := range cases {
+		values[i] = input(test)
+	}
+	AddFuzzCases(f, values...)
+}
+
+// FuzzValues runs the fuzz target with values of type T, decoded from
+// the fuzzer's input with UnmarshalFuzzValue. Inputs that cannot be
+// decoded, or for which validate returns an error, are skipped, so
+// that the target is only given valid values. The validate function
+// may be nil.
+func FuzzValues[T any](f *stdtesting.F, validate func(v T) error, target func(t *stdtesting.T, v T)) {
+	f.Fuzz(func(t *stdtesting.T, data []byte) {
+		v, err := UnmarshalFuzzValue(data, validate)
+		if err != nil {
+			t.Skip(err)
+		}
+		target(t, v)
+	})
+}
+
+// UnmarshalFuzzValue decodes a value of type T from its JSON encoding,
+// as added to a seed corpus by AddFuzzCases. It returns an error if the
+// data holds anything other than a single value of that type, if it
+// holds unknown fields, or if validate, when not nil, returns an error
+// for the value.
+func UnmarshalFuzzValue[T any](data []byte, validate func(v T) error) (T, error) {
+	var v T
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return v, fmt.Errorf("invalid fuzz value: %v", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return v, fmt.Errorf("invalid fuzz value: unexpected data after value")
+	}
+	if validate != nil {
+		if err := validate(v); err != nil {
+			return v, fmt.Errorf("invalid fuzz value: %v", err)
+		}
+	}
+	return v, nil
+}
+
+// MarshalFuzzCorpusEntry returns the contents of a seed corpus file
+// holding the JSON encoding of v, in the format read by go test from
+// the testdata/fuzz/FuzzXxx directory of a package.
+func MarshalFuzzCorpusEntry[T any](v T) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("go test fuzz v1\n[]byte(%q)\n", data)), nil
+}
+
+// WriteFuzzCorpus writes each of the given values to a seed corpus
+// file in dir, which is created if necessary. The files are named
+// after the hash of their contents, as the fuzzer names the files of
+// failing inputs, so writing a value again does not duplicate it.
+// Corpus files are written to testdata/fuzz/FuzzXxx to be checked in
+// and used by the fuzz test FuzzXxx, where they seed the fuzzer
+// without being listed in the code:
+//
+//	err := testing.WriteFuzzCorpus("testdata/fuzz/FuzzParse", cases...)
+func WriteFuzzCorpus[T any](dir string, cases ...T) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for i, v := range cases {
+		data, err := MarshalFuzzCorpusEntry(v)
+		if err != nil {
+			return fmt.Errorf("cannot marshal fuzz case %d: %v", i, err)
+		}
+		name := fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
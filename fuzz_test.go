@@ -0,0 +1,143 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	stdtesting "testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type fuzzSuite struct{}
+
+var _ = gc.Suite(&fuzzSuite{})
+
+type fuzzPoint struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Label  string `json:"label,omitempty"`
+}
+
+func validatePoint(p fuzzPoint) error {
+	if p.Width < 0 || p.Height < 0 {
+		return fmt.Errorf("negative size %dx%d", p.Width, p.Height)
+	}
+	return nil
+}
+
+var unmarshalFuzzValueTests = []struct {
+	about       string
+	data        string
+	expect      fuzzPoint
+	expectError string
+}{{
+	about:  "valid value",
+	data:   `{"width": 2, "height": 3, "label": "x"}`,
+	expect: fuzzPoint{Width: 2, Height: 3, Label: "x"},
+}, {
+	about:       "invalid JSON",
+	data:        `{"width": 2`,
+	expectError: `invalid fuzz value: unexpected EOF`,
+}, {
+	about:       "unknown field",
+	data:        `{"width": 2, "depth": 3}`,
+	expectError: `invalid fuzz value: json: unknown field "depth"`,
+}, {
+	about:       "trailing data",
+	data:        `{"width": 2} {}`,
+	expectError: `invalid fuzz value: unexpected data after value`,
+}, {
+	about:       "rejected by validate",
+	data:        `{"width": -1}`,
+	expectError: `invalid fuzz value: negative size -1x0`,
+}}
+
+func (*fuzzSuite) TestUnmarshalFuzzValue(c *gc.C) {
+	for i, test := range unmarshalFuzzValueTests {
+		c.Logf("test %d: %s", i, test.about)
+		v, err := testing.UnmarshalFuzzValue([]byte(test.data), validatePoint)
+		if test.expectError != "" {
+			c.Check(err, gc.ErrorMatches, test.expectError)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(v, jc.DeepEquals, test.expect)
+	}
+}
+
+func (*fuzzSuite) TestUnmarshalFuzzValueNilValidate(c *gc.C) {
+	v, err := testing.UnmarshalFuzzValue[fuzzPoint]([]byte(`{"width": -1}`), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(v, jc.DeepEquals, fuzzPoint{Width: -1})
+}
+
+func (*fuzzSuite) TestMarshalFuzzCorpusEntry(c *gc.C) {
+	data, err := testing.MarshalFuzzCorpusEntry(fuzzPoint{Width: 1, Height: 2, Label: `a"b`})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "go test fuzz v1\n"+`[]byte("{\"width\":1,\"height\":2,\"label\":\"a\\\"b\"}")`+"\n")
+
+	_, err = testing.MarshalFuzzCorpusEntry(func() {})
+	c.Assert(err, gc.ErrorMatches, `json: unsupported type: func\(\)`)
+}
+
+func (*fuzzSuite) TestWriteFuzzCorpus(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), "testdata", "fuzz", "FuzzPoint")
+	points := []fuzzPoint{{Width: 1}, {Height: 2}}
+	err := testing.WriteFuzzCorpus(dir, points...)
+	c.Assert(err, jc.ErrorIsNil)
+	// Writing the same values again does not add files.
+	err = testing.WriteFuzzCorpus(dir, points...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	infos, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	var entries []string
+	for _, info := range infos {
+		c.Check(info.Name(), gc.Matches, `[0-9a-f]{16}`)
+		data, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		c.Assert(err, jc.ErrorIsNil)
+		entries = append(entries, string(data))
+	}
+	var expect []string
+	for _, p := range points {
+		data, err := testing.MarshalFuzzCorpusEntry(p)
+		c.Assert(err, jc.ErrorIsNil)
+		expect = append(expect, string(data))
+	}
+	c.Assert(entries, jc.SameContents, expect)
+}
+
+type fuzzPointTest struct {
+	about string
+	point fuzzPoint
+}
+
+var fuzzPointTests = []fuzzPointTest{{
+	about: "empty",
+}, {
+	about: "square",
+	point: fuzzPoint{Width: 3, Height: 3, Label: "square"},
+}, {
+	about: "invalid",
+	point: fuzzPoint{Width: -1},
+}}
+
+// FuzzPoint checks that the table test cases seed the fuzzer, and that
+// the fuzz target is only given values that are valid.
+func FuzzPoint(f *stdtesting.F) {
+	testing.AddFuzzTable(f, fuzzPointTests, func(test fuzzPointTest) fuzzPoint {
+		return test.point
+	})
+	testing.FuzzValues(f, validatePoint, func(t *stdtesting.T, p fuzzPoint) {
+		if err := validatePoint(p); err != nil {
+			t.Fatalf("fuzz target given invalid value: %v", err)
+		}
+	})
+}
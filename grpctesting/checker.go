@@ -0,0 +1,237 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package grpctesting
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	gc "gopkg.in/check.v1"
+)
+
+// String returns a description of the call.
+func (call Call) String() string {
+	s := call.Method
+	if len(call.Requests) > 0 {
+		s += " " + formatMessage(call.Requests[0])
+	}
+	return s + " -> " + status.Code(call.Err).String()
+}
+
+// CallMatch describes an RPC expected by the HasCall and CallsMatch
+// checkers.
+type CallMatch struct {
+	// Method holds the expected full method name. If it is empty,
+	// any method matches.
+	Method string
+
+	// Request, if not nil, holds the expected request message,
+	// which is compared with proto.Equal. For RPCs that receive
+	// several messages, it is compared with the first.
+	Request proto.Message
+
+	// Metadata holds metadata entries that the call must have.
+	// The call may have other entries too.
+	Metadata metadata.MD
+
+	// Code holds the expected status code of the call. The zero
+	// value, codes.OK, expects the call to succeed.
+	Code codes.Code
+
+	// Details, if not nil, holds the expected details of the
+	// call's error status, compared in order with proto.Equal.
+	Details []proto.Message
+}
+
+// String returns a description of the match.
+func (m CallMatch) String() string {
+	s := m.Method
+	if s == "" {
+		s = "*"
+	}
+	if m.Request != nil {
+		s += " " + formatMessage(m.Request)
+	}
+	return s + " -> " + m.Code.String()
+}
+
+// mismatches returns a description of each way in which call does
+// not satisfy m.
+func (m CallMatch) mismatches(call Call) []string {
+	var problems []string
+	if m.Method != "" && call.Method != m.Method {
+		problems = append(problems, fmt.Sprintf("method is %s, want %s", call.Method, m.Method))
+	}
+	if m.Request != nil {
+		switch {
+		case len(call.Requests) == 0:
+			problems = append(problems, "no request received")
+		case !messageEqual(call.Requests[0], m.Request):
+			problems = append(problems, fmt.Sprintf("request is %s, want %s", formatMessage(call.Requests[0]), formatMessage(m.Request)))
+		}
+	}
+	for key, values := range m.Metadata {
+		got := call.Metadata.Get(key)
+		if !hasValues(got, values) {
+			problems = append(problems, fmt.Sprintf("metadata %q is %q, want %q", key, got, values))
+		}
+	}
+	st := status.Convert(call.Err)
+	if st.Code() != m.Code {
+		problems = append(problems, fmt.Sprintf("code is %s (%q), want %s", st.Code(), st.Message(), m.Code))
+	}
+	if m.Details != nil {
+		details := st.Details()
+		ok := len(details) == len(m.Details)
+		for i := 0; ok && i < len(details); i++ {
+			ok = messageEqual(details[i], m.Details[i])
+		}
+		if !ok {
+			problems = append(problems, fmt.Sprintf("details are %s, want %s", formatMessages(details), formatMessages(protoMessages(m.Details))))
+		}
+	}
+	return problems
+}
+
+// messageEqual reports whether got is a proto message equal to want.
+func messageEqual(got interface{}, want proto.Message) bool {
+	msg, ok := got.(proto.Message)
+	return ok && proto.Equal(msg, want)
+}
+
+// formatMessage returns a compact description of a message.
+func formatMessage(m interface{}) string {
+	if msg, ok := m.(proto.Message); ok {
+		return fmt.Sprintf("%s{%v}", proto.MessageName(msg), msg)
+	}
+	if err, ok := m.(error); ok {
+		return fmt.Sprintf("error(%q)", err.Error())
+	}
+	return fmt.Sprintf("%T{%v}", m, m)
+}
+
+// formatMessages returns a compact description of a list of messages.
+func formatMessages(ms []interface{}) string {
+	parts := make([]string, len(ms))
+	for i, m := range ms {
+		parts[i] = formatMessage(m)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// protoMessages converts ms to a slice of interface{}.
+func protoMessages(ms []proto.Message) []interface{} {
+	result := make([]interface{}, len(ms))
+	for i, m := range ms {
+		result[i] = m
+	}
+	return result
+}
+
+// hasValues reports whether got holds all the values in want.
+func hasValues(got, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, g := range got {
+			if g == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type hasCallChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasCall checks that at least one of the obtained []Call satisfies
+// the expected CallMatch.
+var HasCall gc.Checker = &hasCallChecker{
+	&gc.CheckerInfo{Name: "HasCall", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *hasCallChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	calls, ok := params[0].([]Call)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []grpctesting.Call, got %T", params[0])
+	}
+	expect, ok := params[1].(CallMatch)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be of type grpctesting.CallMatch, got %T", params[1])
+	}
+	for _, call := range calls {
+		if len(expect.mismatches(call)) == 0 {
+			return true, ""
+		}
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "no call matches %s; calls:\n", expect)
+	for _, call := range calls {
+		fmt.Fprintf(&buf, "    %s\n", call)
+	}
+	return false, buf.String()
+}
+
+type callsMatchChecker struct {
+	*gc.CheckerInfo
+}
+
+// CallsMatch checks that the obtained []Call satisfies the expected
+// []CallMatch exactly: there must be one call for each expectation,
+// in the same order.
+//
+// On failure, each call is listed with its expectation; those that
+// did not match are prefixed with "-" for the expectation and "+" for
+// the call, followed by the differences between them.
+var CallsMatch gc.Checker = &callsMatchChecker{
+	&gc.CheckerInfo{Name: "CallsMatch", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *callsMatchChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	calls, ok := params[0].([]Call)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []grpctesting.Call, got %T", params[0])
+	}
+	expected, ok := params[1].([]CallMatch)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be of type []grpctesting.CallMatch, got %T", params[1])
+	}
+	ok = len(calls) == len(expected)
+	for i := 0; ok && i < len(calls); i++ {
+		ok = len(expected[i].mismatches(calls[i])) == 0
+	}
+	if ok {
+		return true, ""
+	}
+	var buf strings.Builder
+	buf.WriteString("calls do not match:\n")
+	for i := 0; i < len(calls) || i < len(expected); i++ {
+		switch {
+		case i >= len(expected):
+			fmt.Fprintf(&buf, "  + %s\n", calls[i])
+		case i >= len(calls):
+			fmt.Fprintf(&buf, "  - %s\n", expected[i])
+		default:
+			problems := expected[i].mismatches(calls[i])
+			if len(problems) == 0 {
+				fmt.Fprintf(&buf, "    %s\n", calls[i])
+				continue
+			}
+			fmt.Fprintf(&buf, "  - %s\n  + %s\n", expected[i], calls[i])
+			for _, p := range problems {
+				fmt.Fprintf(&buf, "      %s\n", p)
+			}
+		}
+	}
+	return false, buf.String()
+}
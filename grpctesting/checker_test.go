@@ -0,0 +1,163 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package grpctesting_test
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/grpctesting"
+)
+
+type checkerSuite struct{}
+
+var _ = gc.Suite(&checkerSuite{})
+
+const checkMethod = "/grpc.health.v1.Health/Check"
+
+func checkRequest(service string) *healthpb.HealthCheckRequest {
+	return &healthpb.HealthCheckRequest{Service: service}
+}
+
+func notFound(c *gc.C, details ...proto.Message) error {
+	st := status.New(codes.NotFound, "unknown service")
+	if len(details) > 0 {
+		var err error
+		v1 := make([]protoadapt.MessageV1, len(details))
+		for i, d := range details {
+			v1[i] = protoadapt.MessageV1Of(d)
+		}
+		st, err = st.WithDetails(v1...)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return st.Err()
+}
+
+func (s *checkerSuite) TestCallMatchFields(c *gc.C) {
+	call := grpctesting.Call{
+		Method:   checkMethod,
+		Requests: []interface{}{checkRequest("widgets")},
+		Metadata: metadata.Pairs("authorization", "Bearer x", "x-trace", "1", "x-trace", "2"),
+		Err:      notFound(c, checkRequest("detail")),
+	}
+	tests := []struct {
+		about    string
+		match    grpctesting.CallMatch
+		mismatch string
+	}{{
+		about: "everything matches",
+		match: grpctesting.CallMatch{
+			Method:   checkMethod,
+			Request:  checkRequest("widgets"),
+			Metadata: metadata.Pairs("Authorization", "Bearer x", "x-trace", "2"),
+			Code:     codes.NotFound,
+			Details:  []proto.Message{checkRequest("detail")},
+		},
+	}, {
+		about: "only code given",
+		match: grpctesting.CallMatch{Code: codes.NotFound},
+	}, {
+		about:    "wrong method",
+		match:    grpctesting.CallMatch{Method: "/grpc.health.v1.Health/Watch", Code: codes.NotFound},
+		mismatch: `method is /grpc.health.v1.Health/Check, want /grpc.health.v1.Health/Watch`,
+	}, {
+		about:    "wrong request",
+		match:    grpctesting.CallMatch{Request: checkRequest("gadgets"), Code: codes.NotFound},
+		mismatch: `request is grpc.health.v1.HealthCheckRequest{service:\s*"widgets"}, want grpc.health.v1.HealthCheckRequest{service:\s*"gadgets"}`,
+	}, {
+		about:    "missing metadata",
+		match:    grpctesting.CallMatch{Metadata: metadata.Pairs("x-trace", "3"), Code: codes.NotFound},
+		mismatch: `metadata "x-trace" is \["1" "2"\], want \["3"\]`,
+	}, {
+		about:    "wrong code",
+		match:    grpctesting.CallMatch{},
+		mismatch: `code is NotFound \("unknown service"\), want OK`,
+	}, {
+		about:    "wrong details",
+		match:    grpctesting.CallMatch{Code: codes.NotFound, Details: []proto.Message{}},
+		mismatch: `details are \[grpc.health.v1.HealthCheckRequest{service:\s*"detail"}\], want \[\]`,
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		result, message := grpctesting.HasCall.Check([]interface{}{[]grpctesting.Call{call}, test.match}, nil)
+		c.Check(result, gc.Equals, test.mismatch == "")
+		if test.mismatch == "" {
+			continue
+		}
+		c.Check(message, gc.Matches, `no call matches .*; calls:\n    /grpc.health.v1.Health/Check .* -> NotFound\n`)
+		result, message = grpctesting.CallsMatch.Check([]interface{}{[]grpctesting.Call{call}, []grpctesting.CallMatch{test.match}}, nil)
+		c.Check(result, jc.IsFalse)
+		c.Check(message, gc.Matches, `calls do not match:\n  - .*\n  \+ .*\n      `+test.mismatch+`\n`)
+	}
+}
+
+func (s *checkerSuite) TestCallsMatchSequence(c *gc.C) {
+	calls := []grpctesting.Call{{
+		Method:   checkMethod,
+		Requests: []interface{}{checkRequest("a")},
+	}, {
+		Method:   checkMethod,
+		Requests: []interface{}{checkRequest("c")},
+	}}
+	c.Assert(calls, grpctesting.CallsMatch, []grpctesting.CallMatch{
+		{Method: checkMethod, Request: checkRequest("a")},
+		{Method: checkMethod},
+	})
+
+	result, message := grpctesting.CallsMatch.Check([]interface{}{calls, []grpctesting.CallMatch{
+		{Request: checkRequest("a")},
+		{Request: checkRequest("b")},
+		{Method: checkMethod},
+	}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Matches, `calls do not match:
+    /grpc.health.v1.Health/Check .*"a".* -> OK
+  - \* .*"b".* -> OK
+  \+ /grpc.health.v1.Health/Check .*"c".* -> OK
+      request is .*
+  - /grpc.health.v1.Health/Check -> OK
+`)
+}
+
+func (s *checkerSuite) TestWrongTypes(c *gc.C) {
+	result, message := grpctesting.HasCall.Check([]interface{}{"x", grpctesting.CallMatch{}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "obtained value must be of type []grpctesting.Call, got string")
+	result, message = grpctesting.CallsMatch.Check([]interface{}{[]grpctesting.Call{}, grpctesting.CallMatch{}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "expected value must be of type []grpctesting.CallMatch, got grpctesting.CallMatch")
+}
+
+func (s *checkerSuite) TestRecordedCalls(c *gc.C) {
+	srv := grpctesting.NewServer(c, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	})
+	defer srv.Close()
+	client := healthpb.NewHealthClient(srv.Conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "42")
+	_, err := client.Check(ctx, checkRequest(""))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.Check(ctx, checkRequest("unknown"))
+	c.Assert(err, gc.NotNil)
+
+	c.Assert(srv.Calls(), grpctesting.CallsMatch, []grpctesting.CallMatch{{
+		Method:   checkMethod,
+		Request:  checkRequest(""),
+		Metadata: metadata.Pairs("x-request-id", "42"),
+	}, {
+		Method:  checkMethod,
+		Request: checkRequest("unknown"),
+		Code:    codes.NotFound,
+	}})
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package grpctesting runs gRPC servers in process, over an in-memory
+// listener, and records the RPCs made to them.
+package grpctesting
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+// bufferSize holds the size of the in-memory connection buffers.
+const bufferSize = 1 << 20
+
+// Call holds an RPC recorded by a Server.
+type Call struct {
+	// Method holds the full name of the method called, such as
+	// "/grpc.health.v1.Health/Check".
+	Method string
+
+	// Requests holds the messages received from the client. For a
+	// unary RPC, it holds exactly one message.
+	Requests []interface{}
+
+	// Responses holds the messages sent to the client.
+	Responses []interface{}
+
+	// Metadata holds the metadata sent by the client.
+	Metadata metadata.MD
+
+	// Err holds the error returned by the method, if any. Its
+	// code can be found with status.Code.
+	Err error
+}
+
+// Server is a gRPC server listening on an in-memory connection, with
+// a client connection to it:
+//
+//	srv := grpctesting.NewServer(c, func(s *grpc.Server) {
+//		pb.RegisterWidgetsServer(s, &fakeWidgets{})
+//	})
+//	defer srv.Close()
+//	client := pb.NewWidgetsClient(srv.Conn)
+//	... run code using client ...
+//	c.Assert(srv.Methods(), jc.DeepEquals, []string{"/widgets.Widgets/List"})
+//
+// All RPCs made to the server are recorded, whatever their outcome.
+type Server struct {
+	*grpc.Server
+
+	// Conn holds a client connection to the server.
+	Conn *grpc.ClientConn
+
+	listener *bufconn.Listener
+
+	mu    sync.Mutex
+	calls []Call
+	// changed is closed and replaced when a call is recorded.
+	changed chan struct{}
+}
+
+// NewServer starts a server with the given options, calling register
+// to register service implementations with it before it starts
+// serving. The caller should call Close when finished with it.
+//
+// RPCs are recorded by interceptors that run before those added with
+// grpc.ChainUnaryInterceptor and grpc.ChainStreamInterceptor, so calls
+// rejected by those are recorded too. Interceptors set with
+// grpc.UnaryInterceptor and grpc.StreamInterceptor run first of all.
+func NewServer(c *gc.C, register func(*grpc.Server), opts ...grpc.ServerOption) *Server {
+	srv := &Server{
+		listener: bufconn.Listen(bufferSize),
+		changed:  make(chan struct{}),
+	}
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(srv.unaryInterceptor),
+		grpc.ChainStreamInterceptor(srv.streamInterceptor),
+	}, opts...)
+	srv.Server = grpc.NewServer(opts...)
+	register(srv.Server)
+	go srv.Serve(srv.listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return srv.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		srv.Server.Stop()
+		c.Fatalf("cannot connect to gRPC server: %v", err)
+	}
+	srv.Conn = conn
+	return srv
+}
+
+// Cleaner is implemented by testing.CleanupSuite and the suites that
+// embed it.
+type Cleaner interface {
+	AddCleanup(func(*gc.C))
+}
+
+// StartServer is like NewServer but closes the server automatically
+// when the suite's current test finishes.
+func StartServer(c *gc.C, suite Cleaner, register func(*grpc.Server), opts ...grpc.ServerOption) *Server {
+	srv := NewServer(c, register, opts...)
+	suite.AddCleanup(func(*gc.C) { srv.Close() })
+	return srv
+}
+
+// Close closes the client connection and stops the server, closing
+// any RPCs that are still running.
+func (srv *Server) Close() {
+	srv.Conn.Close()
+	srv.Server.Stop()
+}
+
+// Calls returns the RPCs made to the server so far, in the order in
+// which they finished.
+func (srv *Server) Calls() []Call {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]Call(nil), srv.calls...)
+}
+
+// Methods returns the method names of the RPCs made to the server so
+// far, in the order in which they finished.
+func (srv *Server) Methods() []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	methods := make([]string, len(srv.calls))
+	for i, call := range srv.calls {
+		methods[i] = call.Method
+	}
+	return methods
+}
+
+// WaitCalls waits until at least n RPCs have finished, and returns
+// them. It is useful when streaming RPCs finish after the client has
+// stopped waiting for them.
+func (srv *Server) WaitCalls(c *gc.C, n int) []Call {
+	timeout := time.After(testing.LongWait)
+	for {
+		srv.mu.Lock()
+		calls := append([]Call(nil), srv.calls...)
+		changed := srv.changed
+		srv.mu.Unlock()
+		if len(calls) >= n {
+			return calls
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			c.Fatalf("timed out waiting for %d calls; got %d", n, len(calls))
+		}
+	}
+}
+
+// ResetCalls discards the RPCs recorded so far.
+func (srv *Server) ResetCalls() {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.calls = nil
+}
+
+// CheckCall checks that the RPC at the given index in Calls was made
+// to the given method and failed with the given status code, which
+// is codes.OK for success.
+func (srv *Server) CheckCall(c *gc.C, index int, method string, code codes.Code) {
+	calls := srv.Calls()
+	if !c.Check(index < len(calls), gc.Equals, true, gc.Commentf("only %d calls were made: %q", len(calls), srv.Methods())) {
+		return
+	}
+	call := calls[index]
+	c.Check(call.Method, gc.Equals, method)
+	c.Check(status.Code(call.Err), gc.Equals, code, gc.Commentf("error: %v", call.Err))
+}
+
+func (srv *Server) record(call Call) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.calls = append(srv.calls, call)
+	close(srv.changed)
+	srv.changed = make(chan struct{})
+}
+
+func (srv *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	call := Call{
+		Method:   info.FullMethod,
+		Requests: []interface{}{req},
+		Metadata: incomingMetadata(ctx),
+		Err:      err,
+	}
+	if err == nil {
+		call.Responses = []interface{}{resp}
+	}
+	srv.record(call)
+	return resp, err
+}
+
+func (srv *Server) streamInterceptor(impl interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	stream := &recordingStream{ServerStream: ss}
+	err := handler(impl, stream)
+	stream.mu.Lock()
+	call := Call{
+		Method:    info.FullMethod,
+		Requests:  stream.requests,
+		Responses: stream.responses,
+		Metadata:  incomingMetadata(ss.Context()),
+		Err:       err,
+	}
+	stream.mu.Unlock()
+	srv.record(call)
+	return err
+}
+
+// incomingMetadata returns a copy of the metadata received with the
+// RPC running in ctx.
+func incomingMetadata(ctx context.Context) metadata.MD {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return md.Copy()
+}
+
+// recordingStream records the messages passing through a server
+// stream.
+type recordingStream struct {
+	grpc.ServerStream
+
+	mu        sync.Mutex
+	requests  []interface{}
+	responses []interface{}
+}
+
+func (s *recordingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, m)
+	return nil
+}
+
+func (s *recordingStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, m)
+	return nil
+}
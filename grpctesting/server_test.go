@@ -0,0 +1,109 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package grpctesting_test
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/grpctesting"
+)
+
+type serverSuite struct {
+	testing.CleanupSuite
+	health *health.Server
+	srv    *grpctesting.Server
+	client healthpb.HealthClient
+}
+
+var _ = gc.Suite(&serverSuite{})
+
+func (s *serverSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.health = health.NewServer()
+	s.health.SetServingStatus("widgets", healthpb.HealthCheckResponse_SERVING)
+	s.srv = grpctesting.StartServer(c, s, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, s.health)
+	})
+	s.client = healthpb.NewHealthClient(s.srv.Conn)
+}
+
+func (s *serverSuite) TestUnaryCalls(c *gc.C) {
+	resp, err := s.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "widgets"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Status, gc.Equals, healthpb.HealthCheckResponse_SERVING)
+	_, err = s.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "gadgets"})
+	c.Assert(status.Code(err), gc.Equals, codes.NotFound)
+
+	c.Assert(s.srv.Methods(), jc.DeepEquals, []string{
+		"/grpc.health.v1.Health/Check",
+		"/grpc.health.v1.Health/Check",
+	})
+	s.srv.CheckCall(c, 0, "/grpc.health.v1.Health/Check", codes.OK)
+	s.srv.CheckCall(c, 1, "/grpc.health.v1.Health/Check", codes.NotFound)
+
+	calls := s.srv.Calls()
+	c.Assert(calls[0].Requests, gc.HasLen, 1)
+	c.Assert(calls[0].Requests[0].(*healthpb.HealthCheckRequest).Service, gc.Equals, "widgets")
+	c.Assert(calls[0].Responses, gc.HasLen, 1)
+	c.Assert(calls[0].Responses[0].(*healthpb.HealthCheckResponse).Status, gc.Equals, healthpb.HealthCheckResponse_SERVING)
+	c.Assert(calls[1].Responses, gc.HasLen, 0)
+
+	s.srv.ResetCalls()
+	c.Assert(s.srv.Calls(), gc.HasLen, 0)
+}
+
+func (s *serverSuite) TestStreamingCall(c *gc.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := s.client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "widgets"})
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := stream.Recv()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Status, gc.Equals, healthpb.HealthCheckResponse_SERVING)
+
+	s.health.SetServingStatus("widgets", healthpb.HealthCheckResponse_NOT_SERVING)
+	resp, err = stream.Recv()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Status, gc.Equals, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	// The call is recorded when the server finishes it.
+	cancel()
+	_, err = stream.Recv()
+	c.Assert(err, gc.Not(gc.Equals), io.EOF)
+	call := s.srv.WaitCalls(c, 1)[0]
+	s.srv.CheckCall(c, 0, "/grpc.health.v1.Health/Watch", codes.Canceled)
+	c.Assert(call.Requests, gc.HasLen, 1)
+	c.Assert(call.Responses, gc.HasLen, 2)
+}
+
+func (s *serverSuite) TestCheckCallOutOfRange(c *gc.C) {
+	c.ExpectFailure("no calls were made")
+	s.srv.CheckCall(c, 0, "/grpc.health.v1.Health/Check", codes.OK)
+}
+
+func (s *serverSuite) TestServerOptions(c *gc.C) {
+	var intercepted []string
+	srv := grpctesting.NewServer(c, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, s.health)
+	}, grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		intercepted = append(intercepted, info.FullMethod)
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}))
+	defer srv.Close()
+
+	_, err := healthpb.NewHealthClient(srv.Conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	c.Assert(status.Code(err), gc.Equals, codes.PermissionDenied)
+	c.Assert(intercepted, jc.DeepEquals, []string{"/grpc.health.v1.Health/Check"})
+	// The recording interceptor runs first, so sees the rejection.
+	srv.CheckCall(c, 0, "/grpc.health.v1.Health/Check", codes.PermissionDenied)
+}
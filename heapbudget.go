@@ -0,0 +1,178 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+const (
+	// heapProfileRate holds the memory profile rate used while
+	// measuring heap growth, so that the allocation sites of the
+	// growth are sampled finely enough to be reported.
+	heapProfileRate = 4096
+
+	// heapReportSites holds how many of the allocation sites with
+	// the largest growth are reported when the heap grows over its
+	// budget.
+	heapReportSites = 5
+)
+
+// CheckHeapGrowth runs f and checks that the live heap grew by at most
+// budget bytes, and returns whether it did. Garbage is collected before
+// and after f runs, so only memory still reachable after f returns is
+// counted; a test should keep what it loads reachable, and must not run
+// other tests in parallel:
+//
+//	var entities []Entity
+//	testing.CheckHeapGrowth(c, 50<<20, func() {
+//		entities = store.LoadAll(10000)
+//	})
+//	runtime.KeepAlive(entities)
+//
+// If the heap grows by more than budget, the allocation sites
+// responsible for the most growth are reported, as found by the memory
+// profiler, which samples allocations, so the sizes it reports are
+// estimates.
+func CheckHeapGrowth(c *gc.C, budget int64, f func()) bool {
+	oldRate := runtime.MemProfileRate
+	runtime.MemProfileRate = heapProfileRate
+	defer func() {
+		runtime.MemProfileRate = oldRate
+	}()
+
+	before := stableHeap()
+	beforeProfile := heapProfile()
+	f()
+	after := stableHeap()
+	growth := int64(after) - int64(before)
+	if growth < 0 {
+		growth = 0
+	}
+	if growth <= budget {
+		c.Logf("heap grew by %s, within its budget of %s", formatBytes(growth), formatBytes(budget))
+		return true
+	}
+	sites := heapGrowthSites(beforeProfile, heapProfile())
+	if len(sites) > heapReportSites {
+		sites = sites[:heapReportSites]
+	}
+	var largest strings.Builder
+	for _, site := range sites {
+		fmt.Fprintf(&largest, "\n  %s (%s in %d objects)", site.location, formatBytes(site.bytes), site.objects)
+	}
+	c.Errorf("heap grew by %s, over its budget of %s; largest growth by allocation site:%s",
+		formatBytes(growth), formatBytes(budget), largest.String())
+	return false
+}
+
+// AssertHeapGrowth is like CheckHeapGrowth, but stops the test if the
+// heap grew by more than budget bytes.
+func AssertHeapGrowth(c *gc.C, budget int64, f func()) {
+	if !CheckHeapGrowth(c, budget, f) {
+		c.FailNow()
+	}
+}
+
+// stableHeap collects garbage until the size of the live heap settles,
+// and returns it.
+func stableHeap() uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	size := stats.HeapAlloc
+	// Finalizers and the sweeping of the previous cycle may free more
+	// memory, so collect again until the heap stops shrinking.
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc >= size {
+			break
+		}
+		size = stats.HeapAlloc
+	}
+	return size
+}
+
+// heapSite holds the live memory allocated at an allocation site.
+type heapSite struct {
+	location string
+	bytes    int64
+	objects  int64
+}
+
+// heapProfile returns the live memory of each allocation site in the
+// memory profile, as of the last garbage collection.
+func heapProfile() map[[32]uintptr]heapSite {
+	var records []runtime.MemProfileRecord
+	n, _ := runtime.MemProfile(nil, true)
+	for {
+		records = make([]runtime.MemProfileRecord, n+50)
+		var ok bool
+		n, ok = runtime.MemProfile(records, true)
+		if ok {
+			records = records[:n]
+			break
+		}
+	}
+	sites := make(map[[32]uintptr]heapSite)
+	for _, r := range records {
+		site := sites[r.Stack0]
+		site.bytes += r.InUseBytes()
+		site.objects += r.InUseObjects()
+		sites[r.Stack0] = site
+	}
+	return sites
+}
+
+// heapGrowthSites returns the allocation sites whose live memory grew
+// between the before and after profiles, with the most growth first.
+func heapGrowthSites(before, after map[[32]uintptr]heapSite) []heapSite {
+	byLocation := make(map[string]heapSite)
+	for stack, site := range after {
+		location := allocationLocation(stack)
+		grown := byLocation[location]
+		grown.location = location
+		grown.bytes += site.bytes - before[stack].bytes
+		grown.objects += site.objects - before[stack].objects
+		byLocation[location] = grown
+	}
+	var sites []heapSite
+	for _, site := range byLocation {
+		if site.bytes > 0 {
+			sites = append(sites, site)
+		}
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		return sites[i].bytes > sites[j].bytes
+	})
+	return sites
+}
+
+// allocationLocation returns the first function outside the runtime in
+// the given stack, where the allocation was made.
+func allocationLocation(stack [32]uintptr) string {
+	var pcs []uintptr
+	for _, pc := range stack {
+		if pc == 0 {
+			break
+		}
+		pcs = append(pcs, pc)
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			return fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"runtime"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type heapBudgetSuite struct{}
+
+var _ = gc.Suite(&heapBudgetSuite{})
+
+// allocateHeap returns n blocks of 8 KiB.
+func allocateHeap(n int) [][]byte {
+	blocks := make([][]byte, n)
+	for i := range blocks {
+		blocks[i] = make([]byte, 8<<10)
+	}
+	return blocks
+}
+
+func (*heapBudgetSuite) TestWithinBudget(c *gc.C) {
+	var blocks [][]byte
+	ok := testing.CheckHeapGrowth(c, 4<<20, func() {
+		blocks = allocateHeap(128)
+	})
+	runtime.KeepAlive(blocks)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(c.GetTestLog(), gc.Matches, `heap grew by .*, within its budget of 4\.0 MiB\n`)
+}
+
+func (*heapBudgetSuite) TestGarbageNotCounted(c *gc.C) {
+	testing.AssertHeapGrowth(c, 1<<20, func() {
+		allocateHeap(1024)
+	})
+}
+
+// heapBudgetFailureSuite is run by TestOverBudget rather than being
+// registered with gocheck.
+type heapBudgetFailureSuite struct{}
+
+// heapSink keeps the blocks allocated by heapBudgetFailureSuite
+// reachable.
+var heapSink [][]byte
+
+func (*heapBudgetFailureSuite) TestHeapGrowth(c *gc.C) {
+	defer func() {
+		heapSink = nil
+	}()
+	testing.AssertHeapGrowth(c, 1<<20, func() {
+		heapSink = allocateHeap(1024)
+	})
+	c.Fatalf("AssertHeapGrowth did not stop the test")
+}
+
+func (*heapBudgetSuite) TestOverBudget(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&heapBudgetFailureSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`heap grew by [0-9.]+ MiB, over its budget of 1\.0 MiB; largest growth by allocation site:\n`+
+		`\s+github\.com/juju/testing_test\.allocateHeap \(.*heapbudget_test\.go:\d+\) \([0-9.]+ MiB in \d+ objects\)\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*did not stop the test.*`)
+}
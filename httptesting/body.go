@@ -0,0 +1,131 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// requestBody returns the body of the obtained request, which may be
+// an *http.Request, a Request or a *Request.
+//
+// The body of an *http.Request is read in full and replaced with a
+// buffered copy, so that it can be inspected by further checks and
+// read again by the code under test.
+func requestBody(obtained interface{}) (string, error) {
+	switch r := obtained.(type) {
+	case Request:
+		return r.Body, nil
+	case *Request:
+		return r.Body, nil
+	case *http.Request:
+		if r.Body == nil || r.Body == http.NoBody {
+			return "", nil
+		}
+		data, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("cannot read request body: %v", err)
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(data))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(data)), nil
+		}
+		return string(data), nil
+	}
+	return "", fmt.Errorf("obtained value must be of type *http.Request or httptesting.Request, got %T", obtained)
+}
+
+type bodyJSONEqualsChecker struct {
+	*gc.CheckerInfo
+}
+
+// BodyJSONEquals checks that the body of the obtained request, when
+// unmarshaled as JSON, is equal to the expected value. The obtained
+// value may be an *http.Request or a Request recorded by a Server or
+// Transport; the body of an *http.Request is buffered so that it may
+// be checked more than once.
+var BodyJSONEquals gc.Checker = &bodyJSONEqualsChecker{
+	&gc.CheckerInfo{Name: "BodyJSONEquals", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *bodyJSONEqualsChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	body, err := requestBody(params[0])
+	if err != nil {
+		return false, err.Error()
+	}
+	return jc.JSONEquals.Check([]interface{}{body, params[1]}, names)
+}
+
+type bodyFormEqualsChecker struct {
+	*gc.CheckerInfo
+}
+
+// BodyFormEquals checks that the body of the obtained request, when
+// parsed as a URL-encoded form, holds exactly the expected url.Values.
+// The order of the parameters is ignored. The obtained value is as
+// for BodyJSONEquals.
+var BodyFormEquals gc.Checker = &bodyFormEqualsChecker{
+	&gc.CheckerInfo{Name: "BodyFormEquals", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *bodyFormEqualsChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	body, err := requestBody(params[0])
+	if err != nil {
+		return false, err.Error()
+	}
+	expected, ok := params[1].(url.Values)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be of type url.Values, got %T", params[1])
+	}
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		return false, fmt.Sprintf("cannot parse obtained body as form: %v; %q", err, body)
+	}
+	if ok, err := jc.DeepEqual(form, expected); !ok {
+		return false, fmt.Sprintf("form mismatch: %v; body %q", err, body)
+	}
+	return true, ""
+}
+
+type bodyMatchesChecker struct {
+	*gc.CheckerInfo
+}
+
+// BodyMatches checks that the body of the obtained request matches
+// the expected regular expression. As with gc.Matches, the expression
+// must match the whole body. The obtained value is as for
+// BodyJSONEquals.
+var BodyMatches gc.Checker = &bodyMatchesChecker{
+	&gc.CheckerInfo{Name: "BodyMatches", Params: []string{"obtained", "regex"}},
+}
+
+func (checker *bodyMatchesChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	body, err := requestBody(params[0])
+	if err != nil {
+		return false, err.Error()
+	}
+	pattern, ok := params[1].(string)
+	if !ok {
+		return false, fmt.Sprintf("regex must be a string, got %T", params[1])
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return false, fmt.Sprintf("cannot compile regex: %v", err)
+	}
+	if !re.MatchString(body) {
+		return false, fmt.Sprintf("body %q does not match", body)
+	}
+	return true, ""
+}
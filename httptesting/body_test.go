@@ -0,0 +1,140 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+)
+
+type bodySuite struct{}
+
+var _ = gc.Suite(&bodySuite{})
+
+func newRequest(c *gc.C, body string) *http.Request {
+	req, err := http.NewRequest("POST", "http://example.com/", strings.NewReader(body))
+	c.Assert(err, jc.ErrorIsNil)
+	return req
+}
+
+var bodyCheckerTests = []struct {
+	about    string
+	checker  gc.Checker
+	obtained interface{}
+	expected interface{}
+	result   bool
+	message  string
+}{{
+	about:    "json equal",
+	checker:  httptesting.BodyJSONEquals,
+	obtained: httptesting.Request{Body: `{"a": [1, 2]}`},
+	expected: map[string][]int{"a": {1, 2}},
+	result:   true,
+}, {
+	about:    "json not equal",
+	checker:  httptesting.BodyJSONEquals,
+	obtained: &httptesting.Request{Body: `{"a": 1}`},
+	expected: map[string]int{"a": 2},
+	message:  `mismatch at \["a"\]: unequal; obtained 1; expected 2`,
+}, {
+	about:    "json invalid",
+	checker:  httptesting.BodyJSONEquals,
+	obtained: httptesting.Request{Body: `{`},
+	expected: map[string]int{},
+	message:  `cannot unmarshal obtained contents: unexpected end of JSON input; "{"`,
+}, {
+	about:    "form equal in any order",
+	checker:  httptesting.BodyFormEquals,
+	obtained: httptesting.Request{Body: `b=2&a=1&a=3`},
+	expected: url.Values{"a": {"1", "3"}, "b": {"2"}},
+	result:   true,
+}, {
+	about:    "form not equal",
+	checker:  httptesting.BodyFormEquals,
+	obtained: httptesting.Request{Body: `a=1`},
+	expected: url.Values{"a": {"2"}},
+	message:  `form mismatch: mismatch at \["a"\]\[0\]: unequal; obtained "1"; expected "2"; body "a=1"`,
+}, {
+	about:    "form expected wrong type",
+	checker:  httptesting.BodyFormEquals,
+	obtained: httptesting.Request{Body: `a=1`},
+	expected: map[string]string{"a": "1"},
+	message:  `expected value must be of type url.Values, got map\[string\]string`,
+}, {
+	about:    "matches",
+	checker:  httptesting.BodyMatches,
+	obtained: httptesting.Request{Body: "token=abc123"},
+	expected: `token=[a-z0-9]+`,
+	result:   true,
+}, {
+	about:    "matches whole body only",
+	checker:  httptesting.BodyMatches,
+	obtained: httptesting.Request{Body: "token=abc123"},
+	expected: `abc`,
+	message:  `body "token=abc123" does not match`,
+}, {
+	about:    "bad regex",
+	checker:  httptesting.BodyMatches,
+	obtained: httptesting.Request{},
+	expected: `(`,
+	message:  `cannot compile regex: .*`,
+}, {
+	about:    "obtained wrong type",
+	checker:  httptesting.BodyMatches,
+	obtained: "body",
+	expected: `body`,
+	message:  `obtained value must be of type \*http.Request or httptesting.Request, got string`,
+}}
+
+func (s *bodySuite) TestCheckers(c *gc.C) {
+	for i, test := range bodyCheckerTests {
+		c.Logf("test %d: %s", i, test.about)
+		result, message := test.checker.Check([]interface{}{test.obtained, test.expected}, nil)
+		c.Check(result, gc.Equals, test.result)
+		c.Check(message, gc.Matches, test.message)
+	}
+}
+
+func (s *bodySuite) TestHTTPRequestBodyBuffered(c *gc.C) {
+	req := newRequest(c, `{"name": "w", "n": 1}`)
+	c.Assert(req, httptesting.BodyJSONEquals, map[string]interface{}{"name": "w", "n": 1})
+	c.Assert(req, httptesting.BodyMatches, `.*"name": "w".*`)
+
+	// The body can still be read by the code under test.
+	data, err := ioutil.ReadAll(req.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, `{"name": "w", "n": 1}`)
+	body, err := req.GetBody()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err = ioutil.ReadAll(body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, `{"name": "w", "n": 1}`)
+}
+
+func (s *bodySuite) TestHTTPRequestNoBody(c *gc.C) {
+	req, err := http.NewRequest("GET", "http://example.com/", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(req, httptesting.BodyMatches, ``)
+	c.Assert(req, httptesting.BodyFormEquals, url.Values{})
+}
+
+func (s *bodySuite) TestRecordedRequests(c *gc.C) {
+	srv := httptesting.NewServer()
+	defer srv.Close()
+	srv.Handle("POST", "/login", httptesting.Response{})
+	resp, err := http.PostForm(srv.URL+"/login", url.Values{"user": {"bob"}, "password": {"secret"}})
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+
+	requests := srv.Requests()
+	c.Assert(requests, gc.HasLen, 1)
+	c.Assert(requests[0], httptesting.BodyFormEquals, url.Values{"user": {"bob"}, "password": {"secret"}})
+}
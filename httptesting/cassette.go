@@ -0,0 +1,263 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+)
+
+// CassetteMode specifies whether a Cassette records or replays HTTP
+// interactions.
+type CassetteMode int
+
+const (
+	// Replay mode serves responses from the cassette file, and
+	// fails any request that was not recorded.
+	Replay CassetteMode = iota
+
+	// Record mode makes real requests, and saves the interactions
+	// to the cassette file.
+	Record
+)
+
+// String returns the name of the mode.
+func (m CassetteMode) String() string {
+	switch m {
+	case Replay:
+		return "replay"
+	case Record:
+		return "record"
+	}
+	return fmt.Sprintf("CassetteMode(%d)", int(m))
+}
+
+// DefaultCassetteMode holds the mode used by NewCassette. It is Record
+// if the TEST_HTTP_RECORD environment variable is set to a non-empty
+// value when the package is initialized, and Replay otherwise.
+var DefaultCassetteMode = func() CassetteMode {
+	if os.Getenv("TEST_HTTP_RECORD") != "" {
+		return Record
+	}
+	return Replay
+}()
+
+// SensitiveHeaders holds the headers whose values DefaultSanitize
+// replaces.
+var SensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// Redacted is the value that DefaultSanitize substitutes for
+// sensitive header values.
+const Redacted = "REDACTED"
+
+// DefaultSanitize replaces the values of the SensitiveHeaders in the
+// request and response with Redacted.
+func DefaultSanitize(e *Exchange) {
+	for _, h := range []http.Header{e.Request.Header, e.Response.Header} {
+		for _, name := range SensitiveHeaders {
+			if values := h.Values(name); len(values) > 0 {
+				redacted := make([]string, len(values))
+				for i := range redacted {
+					redacted[i] = Redacted
+				}
+				h[http.CanonicalHeaderKey(name)] = redacted
+			}
+		}
+	}
+}
+
+// Cassette is an http.RoundTripper that records HTTP interactions
+// with a real server to a YAML file, and replays them later, so that
+// tests of code using external APIs can run quickly and
+// deterministically:
+//
+//	cas := httptesting.NewCassette(c, "testdata/widgets.yaml")
+//	defer cas.Finish(c)
+//	client := cas.Client()
+//	... run code using client ...
+//
+// Run the test with TEST_HTTP_RECORD=1 to make real requests and
+// record them; without it, the recorded responses are replayed.
+//
+// When replaying, a request is served the response of the first
+// unused recorded interaction with the same method, host, path,
+// query and body; any other request fails. Headers are not compared,
+// because they often hold credentials and timestamps.
+type Cassette struct {
+	// Path holds the path of the cassette file.
+	Path string
+
+	// Mode holds whether the cassette is recording or replaying.
+	Mode CassetteMode
+
+	// Transport is used to make real requests when recording.
+	// If it is nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// Sanitize is called on each interaction before it is saved,
+	// to remove secrets. If it is nil, DefaultSanitize is used.
+	Sanitize func(*Exchange)
+
+	mu        sync.Mutex
+	exchanges []Exchange
+	used      []bool
+	// unmatched holds requests for which nothing was recorded.
+	unmatched []Request
+}
+
+var _ http.RoundTripper = (*Cassette)(nil)
+
+// NewCassette returns a cassette using the file at the given path,
+// in DefaultCassetteMode. When replaying, the file is read
+// immediately.
+func NewCassette(c *gc.C, path string) *Cassette {
+	return NewCassetteWithMode(c, path, DefaultCassetteMode)
+}
+
+// NewCassetteWithMode is like NewCassette but uses the given mode.
+func NewCassetteWithMode(c *gc.C, path string, mode CassetteMode) *Cassette {
+	cas := &Cassette{
+		Path: path,
+		Mode: mode,
+	}
+	if mode == Replay {
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			c.Fatalf("cassette %s not found; run the test with TEST_HTTP_RECORD=1 to record it", path)
+		}
+		c.Assert(err, gc.IsNil)
+		err = yaml.Unmarshal(data, &cas.exchanges)
+		c.Assert(err, gc.IsNil, gc.Commentf("cannot parse cassette %s", path))
+		cas.used = make([]bool, len(cas.exchanges))
+	}
+	return cas
+}
+
+// Client returns an HTTP client that uses the cassette.
+func (cas *Cassette) Client() *http.Client {
+	return &http.Client{Transport: cas}
+}
+
+// Exchanges returns the interactions recorded so far, or those loaded
+// from the cassette file when replaying.
+func (cas *Cassette) Exchanges() []Exchange {
+	cas.mu.Lock()
+	defer cas.mu.Unlock()
+	return append([]Exchange(nil), cas.exchanges...)
+}
+
+// Finish completes the use of the cassette. When recording, it saves
+// the interactions to the cassette file, creating its directory if
+// needed. When replaying, it checks that every request was matched and
+// every recorded interaction was used.
+func (cas *Cassette) Finish(c *gc.C) {
+	cas.mu.Lock()
+	defer cas.mu.Unlock()
+	if cas.Mode == Record {
+		data, err := yaml.Marshal(cas.exchanges)
+		c.Assert(err, gc.IsNil)
+		err = os.MkdirAll(filepath.Dir(cas.Path), 0755)
+		c.Assert(err, gc.IsNil)
+		err = ioutil.WriteFile(cas.Path, data, 0644)
+		c.Assert(err, gc.IsNil)
+		return
+	}
+	for _, r := range cas.unmatched {
+		c.Errorf("cassette %s has no interaction for %s", cas.Path, r)
+	}
+	for i, used := range cas.used {
+		if !used {
+			c.Errorf("cassette %s interaction %d was not used: %s", cas.Path, i, cas.exchanges[i].Request)
+		}
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (cas *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	r, err := clientRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if cas.Mode == Record {
+		return cas.record(req, r)
+	}
+	cas.mu.Lock()
+	defer cas.mu.Unlock()
+	for i, e := range cas.exchanges {
+		if !cas.used[i] && sameRequest(e.Request, r) {
+			cas.used[i] = true
+			if e.Error != "" {
+				return nil, fmt.Errorf("%s", e.Error)
+			}
+			return e.Response.httpResponse(req), nil
+		}
+	}
+	cas.unmatched = append(cas.unmatched, r)
+	return nil, fmt.Errorf("cassette %s has no interaction for %s", cas.Path, r)
+}
+
+// record makes the request r and records the interaction.
+func (cas *Cassette) record(req *http.Request, r Request) (*http.Response, error) {
+	transport := cas.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	e := Exchange{Request: r}
+	resp, err := transport.RoundTrip(req)
+	if err == nil {
+		var body []byte
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		e.Response = Response{
+			Status: resp.StatusCode,
+			Header: resp.Header.Clone(),
+			Body:   string(body),
+		}
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	// Sanitize a copy, so that the caller sees the real
+	// response.
+	saved := e
+	saved.Request.Header = e.Request.Header.Clone()
+	saved.Response.Header = e.Response.Header.Clone()
+	sanitize := cas.Sanitize
+	if sanitize == nil {
+		sanitize = DefaultSanitize
+	}
+	sanitize(&saved)
+	cas.mu.Lock()
+	cas.exchanges = append(cas.exchanges, saved)
+	cas.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return e.Response.httpResponse(req), nil
+}
+
+// sameRequest reports whether the recorded request a matches the
+// request b, ignoring headers.
+func sameRequest(a, b Request) bool {
+	return a.Method == b.Method &&
+		a.Host == b.Host &&
+		a.Path == b.Path &&
+		a.Body == b.Body &&
+		(len(a.Query) == 0 && len(b.Query) == 0 || reflect.DeepEqual(a.Query, b.Query))
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+)
+
+type cassetteSuite struct {
+	upstream *httptesting.Server
+	path     string
+}
+
+var _ = gc.Suite(&cassetteSuite{})
+
+func (s *cassetteSuite) SetUpTest(c *gc.C) {
+	s.upstream = httptesting.NewServer()
+	s.upstream.Handle("GET", "/widgets", httptesting.Response{
+		Header: http.Header{"Content-Type": {"application/json"}, "Set-Cookie": {"session=secret"}},
+		Body:   `[{"id": 1}]`,
+	})
+	s.upstream.Handle("POST", "/widgets", httptesting.Response{
+		Status: http.StatusCreated,
+		Body:   `{"id": 2}`,
+	})
+	s.path = filepath.Join(c.MkDir(), "cassettes", "widgets.yaml")
+}
+
+func (s *cassetteSuite) TearDownTest(c *gc.C) {
+	s.upstream.Close()
+}
+
+// exercise makes the requests of a client under test.
+func (s *cassetteSuite) exercise(c *gc.C, client *http.Client) {
+	req, err := http.NewRequest("GET", s.upstream.URL+"/widgets?limit=10", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := client.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Assert(string(body), gc.Equals, `[{"id": 1}]`)
+
+	resp, err = client.Post(s.upstream.URL+"/widgets", "application/json", strings.NewReader(`{"name": "w"}`))
+	c.Assert(err, jc.ErrorIsNil)
+	body, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusCreated)
+	c.Assert(string(body), gc.Equals, `{"id": 2}`)
+}
+
+func (s *cassetteSuite) record(c *gc.C) {
+	cas := httptesting.NewCassetteWithMode(c, s.path, httptesting.Record)
+	s.exercise(c, cas.Client())
+	cas.Finish(c)
+}
+
+func (s *cassetteSuite) TestRecordAndReplay(c *gc.C) {
+	s.record(c)
+	c.Assert(s.upstream.Requests(), gc.HasLen, 2)
+
+	// The upstream is not used when replaying.
+	s.upstream.ResetRequests()
+	cas := httptesting.NewCassetteWithMode(c, s.path, httptesting.Replay)
+	s.exercise(c, cas.Client())
+	cas.Finish(c)
+	c.Assert(s.upstream.Requests(), gc.HasLen, 0)
+}
+
+func (s *cassetteSuite) TestRecordSanitizes(c *gc.C) {
+	s.record(c)
+	data, err := ioutil.ReadFile(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Not(jc.Contains), "Bearer token")
+	c.Assert(string(data), gc.Not(jc.Contains), "session=secret")
+
+	cas := httptesting.NewCassetteWithMode(c, s.path, httptesting.Replay)
+	exchanges := cas.Exchanges()
+	c.Assert(exchanges, gc.HasLen, 2)
+	c.Assert(exchanges[0].Request.Header.Get("Authorization"), gc.Equals, httptesting.Redacted)
+	c.Assert(exchanges[0].Response.Header.Get("Set-Cookie"), gc.Equals, httptesting.Redacted)
+	c.Assert(exchanges[1].Request.Body, gc.Equals, `{"name": "w"}`)
+}
+
+func (s *cassetteSuite) TestCustomSanitize(c *gc.C) {
+	cas := httptesting.NewCassetteWithMode(c, s.path, httptesting.Record)
+	cas.Sanitize = func(e *httptesting.Exchange) {
+		httptesting.DefaultSanitize(e)
+		e.Response.Body = strings.Replace(e.Response.Body, "1", "N", -1)
+	}
+	s.exercise(c, cas.Client())
+	cas.Finish(c)
+	exchanges := httptesting.NewCassetteWithMode(c, s.path, httptesting.Replay).Exchanges()
+	c.Assert(exchanges[0].Response.Body, gc.Equals, `[{"id": N}]`)
+}
+
+func (s *cassetteSuite) TestReplayUnmatchedRequest(c *gc.C) {
+	s.record(c)
+	cas := httptesting.NewCassetteWithMode(c, s.path, httptesting.Replay)
+	_, err := cas.Client().Get(s.upstream.URL + "/gadgets")
+	c.Assert(err, gc.ErrorMatches, `Get ".*/gadgets": cassette .*widgets.yaml has no interaction for GET /gadgets`)
+
+	// The query is significant.
+	_, err = cas.Client().Get(s.upstream.URL + "/widgets?limit=20")
+	c.Assert(err, gc.ErrorMatches, `.* has no interaction for GET /widgets\?limit=20`)
+	c.ExpectFailure("requests were unmatched and interactions unused")
+	cas.Finish(c)
+}
+
+func (s *cassetteSuite) TestReplayInteractionsUsedOnce(c *gc.C) {
+	s.record(c)
+	cas := httptesting.NewCassetteWithMode(c, s.path, httptesting.Replay)
+	s.exercise(c, cas.Client())
+	_, err := cas.Client().Post(s.upstream.URL+"/widgets", "application/json", strings.NewReader(`{"name": "w"}`))
+	c.Assert(err, gc.ErrorMatches, `.* has no interaction for POST /widgets .*`)
+}
+
+func (s *cassetteSuite) TestReplayMissingCassette(c *gc.C) {
+	c.ExpectFailure("cassette file does not exist")
+	httptesting.NewCassetteWithMode(c, s.path, httptesting.Replay)
+}
+
+func (s *cassetteSuite) TestRecordError(c *gc.C) {
+	s.upstream.Close()
+	cas := httptesting.NewCassetteWithMode(c, s.path, httptesting.Record)
+	_, err := cas.Client().Get(s.upstream.URL + "/widgets")
+	c.Assert(err, gc.ErrorMatches, ".*connection refused")
+	cas.Finish(c)
+
+	cas = httptesting.NewCassetteWithMode(c, s.path, httptesting.Replay)
+	_, err = cas.Client().Get(s.upstream.URL + "/widgets")
+	c.Assert(err, gc.ErrorMatches, ".*connection refused")
+	cas.Finish(c)
+}
+
+func (s *cassetteSuite) TestCassetteModeString(c *gc.C) {
+	c.Assert(httptesting.Replay.String(), gc.Equals, "replay")
+	c.Assert(httptesting.Record.String(), gc.Equals, "record")
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// RequestMatch describes a request expected by the HasRequest and
+// RequestsMatch checkers. Empty fields match anything.
+type RequestMatch struct {
+	// Method holds the expected request method.
+	Method string
+
+	// Host holds the expected host, including any port.
+	Host string
+
+	// Path holds the expected URL path.
+	Path string
+
+	// Query holds query parameters that the request must have.
+	// The request may have other parameters too.
+	Query url.Values
+
+	// Header holds headers that the request must have.
+	// The request may have other headers too.
+	Header http.Header
+
+	// Body holds the expected body of the request.
+	Body string
+
+	// JSONBody, if not nil, holds a value that the request
+	// body must be equal to when both are marshaled as JSON.
+	// If it is specified, Body is ignored.
+	JSONBody interface{}
+}
+
+// String returns a description of the match.
+func (m RequestMatch) String() string {
+	method := m.Method
+	if method == "" {
+		method = "ANY"
+	}
+	path := m.Path
+	if path == "" {
+		path = "*"
+	}
+	if m.Host != "" {
+		path = "//" + m.Host + path
+	}
+	s := method + " " + path
+	if len(m.Query) > 0 {
+		s += "?" + m.Query.Encode()
+	}
+	if m.JSONBody != nil {
+		s += fmt.Sprintf(" json:%v", m.JSONBody)
+	} else if m.Body != "" {
+		s += fmt.Sprintf(" %q", m.Body)
+	}
+	return s
+}
+
+// matches reports whether the request satisfies m.
+func (m RequestMatch) matches(r Request) bool {
+	if m.Method != "" && r.Method != m.Method {
+		return false
+	}
+	if m.Host != "" && r.Host != m.Host {
+		return false
+	}
+	if m.Path != "" && r.Path != m.Path {
+		return false
+	}
+	for key, values := range m.Query {
+		if !hasValues(r.Query[key], values) {
+			return false
+		}
+	}
+	for key, values := range m.Header {
+		if !hasValues(r.Header[http.CanonicalHeaderKey(key)], values) {
+			return false
+		}
+	}
+	if m.JSONBody != nil {
+		ok, _ := jc.JSONEquals.Check([]interface{}{r.Body, m.JSONBody}, nil)
+		return ok
+	}
+	return m.Body == "" || r.Body == m.Body
+}
+
+// hasValues reports whether got holds all the values in want.
+func hasValues(got, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, g := range got {
+			if g == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type hasRequestChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasRequest checks that at least one of the obtained []Request
+// satisfies the expected RequestMatch.
+var HasRequest gc.Checker = &hasRequestChecker{
+	&gc.CheckerInfo{Name: "HasRequest", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *hasRequestChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	requests, ok := params[0].([]Request)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []httptesting.Request, got %T", params[0])
+	}
+	expect, ok := params[1].(RequestMatch)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be of type httptesting.RequestMatch, got %T", params[1])
+	}
+	for _, r := range requests {
+		if expect.matches(r) {
+			return true, ""
+		}
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "no request matches %s; requests:\n", expect)
+	for _, r := range requests {
+		fmt.Fprintf(&buf, "    %s\n", r)
+	}
+	return false, buf.String()
+}
+
+type requestsMatchChecker struct {
+	*gc.CheckerInfo
+}
+
+// RequestsMatch checks that the obtained []Request satisfies the
+// expected []RequestMatch exactly: there must be one request for each
+// expectation, in the same order.
+//
+// On failure, each request is listed with its expectation; those that
+// did not match are prefixed with "-" for the expectation and "+" for
+// the request.
+var RequestsMatch gc.Checker = &requestsMatchChecker{
+	&gc.CheckerInfo{Name: "RequestsMatch", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *requestsMatchChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	requests, ok := params[0].([]Request)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []httptesting.Request, got %T", params[0])
+	}
+	expected, ok := params[1].([]RequestMatch)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be of type []httptesting.RequestMatch, got %T", params[1])
+	}
+	ok = len(requests) == len(expected)
+	for i := 0; ok && i < len(requests); i++ {
+		ok = expected[i].matches(requests[i])
+	}
+	if ok {
+		return true, ""
+	}
+	var buf strings.Builder
+	buf.WriteString("requests do not match:\n")
+	for i := 0; i < len(requests) || i < len(expected); i++ {
+		switch {
+		case i >= len(expected):
+			fmt.Fprintf(&buf, "  + %s\n", requests[i])
+		case i >= len(requests):
+			fmt.Fprintf(&buf, "  - %s\n", expected[i])
+		case expected[i].matches(requests[i]):
+			fmt.Fprintf(&buf, "    %s\n", requests[i])
+		default:
+			fmt.Fprintf(&buf, "  - %s\n  + %s\n", expected[i], requests[i])
+		}
+	}
+	return false, buf.String()
+}
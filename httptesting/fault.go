@@ -0,0 +1,158 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Fault describes a failure injected by a Server, for testing how
+// clients retry and back off. By default the Response is served as
+// usual; the other fields make things worse.
+type Fault struct {
+	// Response holds the response to serve, typically with a
+	// status such as http.StatusInternalServerError or
+	// http.StatusTooManyRequests and perhaps a Retry-After header.
+	Response
+
+	// Delay holds how long to wait before responding. The wait is
+	// abandoned if the client gives up on the request.
+	Delay time.Duration
+
+	// Drop causes the connection to be closed without any response
+	// being sent. Note that net/http clients may themselves retry
+	// an idempotent request that fails this way on a reused
+	// connection.
+	Drop bool
+
+	// Truncate causes the connection to be closed part of the way
+	// through the response body; the headers announce the full
+	// length of the body, but only half of it is sent.
+	Truncate bool
+
+	// Malformed causes a response to be sent that is not valid
+	// HTTP.
+	Malformed bool
+}
+
+// HandleFault registers the fault to be served for the next n requests
+// with the given method and path. As with Handle, responses are served
+// in the order they are registered, so a fault followed by a success
+// can be set up with:
+//
+//	srv.HandleFault("GET", "/v1/widgets", 2, httptesting.Fault{
+//		Response: httptesting.Response{Status: http.StatusServiceUnavailable},
+//	})
+//	srv.Handle("GET", "/v1/widgets", httptesting.Response{Body: `[]`})
+//
+// If no response is registered after the fault, it is served to all
+// remaining requests.
+func (s *Server) HandleFault(method, path string, n int, f Fault) {
+	for i := 0; i < n; i++ {
+		s.HandleFunc(method, path, f.serveHTTP)
+	}
+}
+
+func (f Fault) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-req.Context().Done():
+			return
+		}
+	}
+	if !f.Drop && !f.Truncate && !f.Malformed {
+		f.Response.serveHTTP(w)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "cannot hijack connection", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot hijack connection: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+	switch {
+	case f.Malformed:
+		rw.WriteString("HTTP/1.1 two hundred OK\r\nContent-Length: 0\r\n\r\n")
+	case f.Truncate:
+		status := f.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		// Always announce at least one byte, so that the body is
+		// incomplete even when empty.
+		length := len(f.Body)
+		if length == 0 {
+			length = 1
+		}
+		fmt.Fprintf(rw, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+		f.Header.Write(rw)
+		fmt.Fprintf(rw, "Content-Length: %d\r\n\r\n", length)
+		rw.WriteString(f.Body[:len(f.Body)/2])
+	}
+	rw.Flush()
+}
+
+// serveHTTP writes the response to w.
+func (resp Response) serveHTTP(w http.ResponseWriter) {
+	for key, values := range resp.Header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(resp.Body))
+}
+
+// Attempts returns the number of requests made so far with the given
+// method and path. If method is empty, requests with any method are
+// counted.
+func (s *Server) Attempts(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if (method == "" || r.Method == method) && r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// CheckRetries checks that the client retried requests with the given
+// method and path the given number of times; that is, that it made
+// retries+1 requests in all. On failure, all the requests made to the
+// server are listed.
+func (s *Server) CheckRetries(c *gc.C, method, path string, retries int) bool {
+	attempts := s.Attempts(method, path)
+	if attempts == retries+1 {
+		return true
+	}
+	if method == "" {
+		method = "ANY"
+	}
+	var buf strings.Builder
+	if attempts == 0 {
+		fmt.Fprintf(&buf, "client made no %s %s requests, want %d retries; requests:\n", method, path, retries)
+	} else {
+		fmt.Fprintf(&buf, "client retried %s %s %d times, want %d; requests:\n", method, path, attempts-1, retries)
+	}
+	for _, r := range s.Requests() {
+		fmt.Fprintf(&buf, "    %s\n", r)
+	}
+	c.Error(buf.String())
+	return false
+}
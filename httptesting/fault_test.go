@@ -0,0 +1,157 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+)
+
+type faultSuite struct {
+	srv    *httptesting.Server
+	client *http.Client
+}
+
+var _ = gc.Suite(&faultSuite{})
+
+func (s *faultSuite) SetUpTest(c *gc.C) {
+	s.srv = httptesting.NewServer()
+	// Keep-alives are disabled so that net/http does not retry
+	// requests on dropped connections behind our back.
+	s.client = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+}
+
+func (s *faultSuite) TearDownTest(c *gc.C) {
+	s.srv.Close()
+}
+
+// getWithRetries gets the given path, retrying up to maxRetries times
+// on errors and 5xx and 429 responses, as a client under test might.
+func (s *faultSuite) getWithRetries(path string, maxRetries int) (status int, body string, err error) {
+	for i := 0; ; i++ {
+		var resp *http.Response
+		resp, err = s.client.Get(s.srv.URL + path)
+		if err == nil {
+			var data []byte
+			data, err = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			status, body = resp.StatusCode, string(data)
+		}
+		retry := err != nil || status >= 500 || status == http.StatusTooManyRequests
+		if !retry || i >= maxRetries {
+			return status, body, err
+		}
+	}
+}
+
+func (s *faultSuite) TestStatusFaults(c *gc.C) {
+	s.srv.HandleFault("GET", "/widgets", 2, httptesting.Fault{
+		Response: httptesting.Response{Status: http.StatusInternalServerError},
+	})
+	s.srv.HandleFault("GET", "/widgets", 1, httptesting.Fault{
+		Response: httptesting.Response{
+			Status: http.StatusTooManyRequests,
+			Header: http.Header{"Retry-After": {"1"}},
+		},
+	})
+	s.srv.Handle("GET", "/widgets", httptesting.Response{Body: `[]`})
+
+	status, body, err := s.getWithRetries("/widgets", 5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, `[]`)
+	c.Assert(s.srv.Attempts("GET", "/widgets"), gc.Equals, 4)
+	s.srv.CheckRetries(c, "GET", "/widgets", 3)
+}
+
+func (s *faultSuite) TestFaultRepeats(c *gc.C) {
+	s.srv.HandleFault("", "/widgets", 1, httptesting.Fault{
+		Response: httptesting.Response{Status: http.StatusBadGateway},
+	})
+	status, _, err := s.getWithRetries("/widgets", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, http.StatusBadGateway)
+	s.srv.CheckRetries(c, "", "/widgets", 2)
+}
+
+func (s *faultSuite) TestDrop(c *gc.C) {
+	s.srv.HandleFault("GET", "/widgets", 1, httptesting.Fault{Drop: true})
+	_, err := s.client.Get(s.srv.URL + "/widgets")
+	c.Assert(err, gc.ErrorMatches, `Get ".*": EOF`)
+}
+
+func (s *faultSuite) TestTruncate(c *gc.C) {
+	s.srv.HandleFault("GET", "/widgets", 1, httptesting.Fault{
+		Response: httptesting.Response{
+			Status: http.StatusOK,
+			Header: http.Header{"Content-Type": {"application/json"}},
+			Body:   `[{"id": 1}, {"id": 2}]`,
+		},
+		Truncate: true,
+	})
+	resp, err := s.client.Get(s.srv.URL + "/widgets")
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "application/json")
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, gc.ErrorMatches, "unexpected EOF")
+	c.Assert(string(data), gc.Equals, `[{"id": 1},`)
+}
+
+func (s *faultSuite) TestTruncateEmptyBody(c *gc.C) {
+	s.srv.HandleFault("GET", "/widgets", 1, httptesting.Fault{Truncate: true})
+	resp, err := s.client.Get(s.srv.URL + "/widgets")
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	_, err = ioutil.ReadAll(resp.Body)
+	c.Assert(err, gc.ErrorMatches, "unexpected EOF")
+}
+
+func (s *faultSuite) TestMalformed(c *gc.C) {
+	s.srv.HandleFault("GET", "/widgets", 1, httptesting.Fault{Malformed: true})
+	_, err := s.client.Get(s.srv.URL + "/widgets")
+	c.Assert(err, gc.ErrorMatches, `Get ".*": .*malformed HTTP status code.*`)
+}
+
+func (s *faultSuite) TestDelay(c *gc.C) {
+	s.srv.HandleFault("GET", "/slow", 1, httptesting.Fault{Delay: 100 * time.Millisecond})
+	start := time.Now()
+	status, _, err := s.getWithRetries("/slow", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(time.Since(start) >= 100*time.Millisecond, jc.IsTrue)
+}
+
+func (s *faultSuite) TestDelayAbandoned(c *gc.C) {
+	s.srv.HandleFault("GET", "/slow", 1, httptesting.Fault{Delay: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", s.srv.URL+"/slow", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.client.Do(req)
+	c.Assert(err, gc.ErrorMatches, `.*context deadline exceeded.*`)
+	// Closing the server in TearDownTest would hang if the handler
+	// was still waiting.
+}
+
+func (s *faultSuite) TestCheckRetriesFailure(c *gc.C) {
+	s.srv.Handle("GET", "/widgets", httptesting.Response{})
+	_, _, err := s.getWithRetries("/widgets", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.ExpectFailure("client made one request, not three")
+	s.srv.CheckRetries(c, "GET", "/widgets", 2)
+}
+
+func (s *faultSuite) TestCheckRetriesNoRequests(c *gc.C) {
+	c.ExpectFailure("client made no requests")
+	s.srv.CheckRetries(c, "", "/widgets", 0)
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Exchange holds a request recorded by a Proxy or Cassette and the
+// response received for it.
+type Exchange struct {
+	Request  Request
+	Response Response
+
+	// Error holds the error encountered sending the request
+	// upstream, if any. A Proxy sends the client a 502 Bad Gateway
+	// response, which is held in Response; a Cassette returns the
+	// error to the client.
+	Error string `json:",omitempty" yaml:",omitempty"`
+}
+
+// Proxy is an HTTP proxy that records each request made through it
+// along with the response from upstream, so that the traffic between
+// client code and a real or fake server can be inspected:
+//
+//	p := httptesting.NewProxy(c, upstreamURL)
+//	defer p.Close()
+//	... run the client against p.URL ...
+//	c.Assert(p.Requests(), httptesting.HasRequest, httptesting.RequestMatch{Path: "/v1/widgets"})
+//	p.Save(c, "testdata/widgets.json")
+//
+// Saved exchanges can later be served without the upstream by
+// Server.Replay.
+type Proxy struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// NewProxy starts and returns a new Proxy. If upstream is not empty,
+// the proxy acts as a reverse proxy, and requests to it are sent on to
+// the upstream URL. Otherwise it acts as a forward proxy, suitable
+// for use with http.ProxyURL; HTTPS requests, which use CONNECT, are
+// not supported. The caller should call Close when finished with it.
+func NewProxy(c *gc.C, upstream string) *Proxy {
+	var target *url.URL
+	if upstream != "" {
+		var err error
+		target, err = url.Parse(upstream)
+		c.Assert(err, gc.IsNil)
+	}
+	p := &Proxy{}
+	rp := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			if target != nil {
+				req.URL.Scheme = target.Scheme
+				req.URL.Host = target.Host
+				req.URL.Path = singleJoiningSlash(target.Path, req.URL.Path)
+				req.Host = target.Host
+			}
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return fmt.Errorf("cannot read response body: %v", err)
+			}
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			p.record(resp.Request, Response{
+				Status: resp.StatusCode,
+				Header: resp.Header.Clone(),
+				Body:   string(body),
+			}, nil)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			resp := Response{
+				Status: http.StatusBadGateway,
+				Body:   fmt.Sprintf("proxy error: %v\n", err),
+			}
+			p.record(req, resp, err)
+			resp.serveHTTP(w)
+		},
+	}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Buffer the body so that it can be recorded as well as
+		// sent upstream.
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("cannot read request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req = req.WithContext(withRequestBody(req.Context(), body))
+		rp.ServeHTTP(w, req)
+	}))
+	return p
+}
+
+// record records an exchange for the given outgoing request.
+func (p *Proxy) record(req *http.Request, resp Response, err error) {
+	e := Exchange{
+		Request: Request{
+			Method: req.Method,
+			Host:   req.URL.Host,
+			Path:   req.URL.Path,
+			Query:  req.URL.Query(),
+			Header: req.Header.Clone(),
+			Body:   string(requestBodyFromContext(req.Context())),
+		},
+		Response: resp,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exchanges = append(p.exchanges, e)
+}
+
+// Exchanges returns the exchanges recorded so far, in the order in
+// which they completed.
+func (p *Proxy) Exchanges() []Exchange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Exchange(nil), p.exchanges...)
+}
+
+// Requests returns the requests recorded so far, as sent upstream,
+// for use with the HasRequest and RequestsMatch checkers.
+func (p *Proxy) Requests() []Request {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	requests := make([]Request, len(p.exchanges))
+	for i, e := range p.exchanges {
+		requests[i] = e.Request
+	}
+	return requests
+}
+
+// Save writes the exchanges recorded so far to the named file as JSON.
+func (p *Proxy) Save(c *gc.C, path string) {
+	data, err := json.MarshalIndent(p.Exchanges(), "", "\t")
+	c.Assert(err, gc.IsNil)
+	err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+	c.Assert(err, gc.IsNil)
+}
+
+// LoadExchanges reads exchanges saved by Proxy.Save.
+func LoadExchanges(c *gc.C, path string) []Exchange {
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, gc.IsNil)
+	var exchanges []Exchange
+	err = json.Unmarshal(data, &exchanges)
+	c.Assert(err, gc.IsNil, gc.Commentf("cannot parse exchanges from %s", path))
+	return exchanges
+}
+
+// Replay registers the responses from the given exchanges, so that
+// the server answers the same requests in the same way. Responses
+// for the same method and path are served in the order recorded.
+// Exchanges in which the request failed are skipped.
+func (s *Server) Replay(exchanges []Exchange) {
+	for _, e := range exchanges {
+		if e.Error != "" {
+			continue
+		}
+		s.Handle(e.Request.Method, e.Request.Path, e.Response)
+	}
+}
+
+// singleJoiningSlash joins a and b with exactly one slash between
+// them, as httputil.NewSingleHostReverseProxy does.
+func singleJoiningSlash(a, b string) string {
+	switch aslash, bslash := len(a) > 0 && a[len(a)-1] == '/', len(b) > 0 && b[0] == '/'; {
+	case aslash && bslash:
+		return a + b[1:]
+	case !aslash && !bslash && a != "" && b != "":
+		return a + "/" + b
+	}
+	return a + b
+}
+
+type requestBodyKey struct{}
+
+// withRequestBody returns a context holding the body of the request
+// being proxied.
+func withRequestBody(ctx context.Context, body []byte) context.Context {
+	return context.WithValue(ctx, requestBodyKey{}, body)
+}
+
+// requestBodyFromContext returns the body stored by withRequestBody.
+func requestBodyFromContext(ctx context.Context) []byte {
+	body, _ := ctx.Value(requestBodyKey{}).([]byte)
+	return body
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+)
+
+type proxySuite struct {
+	upstream *httptesting.Server
+}
+
+var _ = gc.Suite(&proxySuite{})
+
+func (s *proxySuite) SetUpTest(c *gc.C) {
+	s.upstream = httptesting.NewServer()
+	s.upstream.Handle("GET", "/api/widgets", httptesting.Response{
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   `[{"id": 1}]`,
+	})
+	s.upstream.Handle("POST", "/api/widgets", httptesting.Response{
+		Status: http.StatusCreated,
+		Body:   `{"id": 2}`,
+	})
+}
+
+func (s *proxySuite) TearDownTest(c *gc.C) {
+	s.upstream.Close()
+}
+
+func get(c *gc.C, client *http.Client, url string) (int, string) {
+	resp, err := client.Get(url)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp.StatusCode, string(data)
+}
+
+func (s *proxySuite) TestReverseProxy(c *gc.C) {
+	p := httptesting.NewProxy(c, s.upstream.URL+"/api")
+	defer p.Close()
+
+	status, body := get(c, http.DefaultClient, p.URL+"/widgets?limit=1")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, `[{"id": 1}]`)
+	resp, err := http.Post(p.URL+"/widgets", "application/json", strings.NewReader(`{"name": "w"}`))
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusCreated)
+
+	exchanges := p.Exchanges()
+	c.Assert(exchanges, gc.HasLen, 2)
+	c.Assert(exchanges[0].Request.Path, gc.Equals, "/api/widgets")
+	c.Assert(exchanges[0].Request.Query, jc.DeepEquals, url.Values{"limit": {"1"}})
+	c.Assert(exchanges[0].Response.Status, gc.Equals, http.StatusOK)
+	c.Assert(exchanges[0].Response.Header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Assert(exchanges[0].Response.Body, gc.Equals, `[{"id": 1}]`)
+	c.Assert(exchanges[1].Response.Status, gc.Equals, http.StatusCreated)
+	c.Assert(exchanges[1].Response.Body, gc.Equals, `{"id": 2}`)
+
+	c.Assert(p.Requests(), httptesting.RequestsMatch, []httptesting.RequestMatch{
+		{Method: "GET", Path: "/api/widgets"},
+		{Method: "POST", Path: "/api/widgets", JSONBody: map[string]string{"name": "w"}},
+	})
+	// The upstream saw the same requests.
+	c.Assert(s.upstream.Requests(), gc.HasLen, 2)
+	c.Assert(s.upstream.Requests()[1].Body, gc.Equals, `{"name": "w"}`)
+}
+
+func (s *proxySuite) TestForwardProxy(c *gc.C) {
+	p := httptesting.NewProxy(c, "")
+	defer p.Close()
+	proxyURL, err := url.Parse(p.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	status, body := get(c, client, s.upstream.URL+"/api/widgets")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, `[{"id": 1}]`)
+	c.Assert(p.Requests(), httptesting.RequestsMatch, []httptesting.RequestMatch{
+		{Method: "GET", Host: strings.TrimPrefix(s.upstream.URL, "http://"), Path: "/api/widgets"},
+	})
+}
+
+func (s *proxySuite) TestUpstreamError(c *gc.C) {
+	s.upstream.Close()
+	p := httptesting.NewProxy(c, s.upstream.URL)
+	defer p.Close()
+
+	status, _ := get(c, http.DefaultClient, p.URL+"/api/widgets")
+	c.Assert(status, gc.Equals, http.StatusBadGateway)
+	exchanges := p.Exchanges()
+	c.Assert(exchanges, gc.HasLen, 1)
+	c.Assert(exchanges[0].Error, gc.Matches, ".*connection refused")
+	c.Assert(exchanges[0].Response.Status, gc.Equals, http.StatusBadGateway)
+}
+
+func (s *proxySuite) TestSaveAndReplay(c *gc.C) {
+	p := httptesting.NewProxy(c, s.upstream.URL)
+	defer p.Close()
+	get(c, http.DefaultClient, p.URL+"/api/widgets")
+	path := filepath.Join(c.MkDir(), "exchanges.json")
+	p.Save(c, path)
+
+	exchanges := httptesting.LoadExchanges(c, path)
+	c.Assert(exchanges, jc.DeepEquals, p.Exchanges())
+
+	replay := httptesting.NewServer()
+	defer replay.Close()
+	replay.Replay(exchanges)
+	status, body := get(c, http.DefaultClient, replay.URL+"/api/widgets")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, `[{"id": 1}]`)
+}
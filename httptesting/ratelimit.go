@@ -0,0 +1,211 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// RateRecorder records the time of each request passing through a
+// handler or transport that it wraps, so that tests can check the
+// rate at which a client makes requests. Times are taken from a
+// clock, which is typically the testclock.Clock that also drives the
+// client's rate limiter, so that no real time need pass:
+//
+//	clock := testclock.NewClock(time.Time{})
+//	rec := httptesting.NewRateRecorder(clock)
+//	client := &http.Client{Transport: rec.Transport(t)}
+//	... run the rate-limited client, advancing clock ...
+//	c.Assert(rec.Times(), httptesting.RateAtMost, httptesting.Rate{N: 10, Per: time.Second})
+type RateRecorder struct {
+	clock clock.Clock
+
+	mu    sync.Mutex
+	times []time.Time
+}
+
+// NewRateRecorder returns a RateRecorder that takes times from the
+// given clock. If clk is nil, clock.WallClock is used.
+func NewRateRecorder(clk clock.Clock) *RateRecorder {
+	return &RateRecorder{clock: clockOrWall(clk)}
+}
+
+// Handler returns a handler that records the time of each request and
+// then passes it to h.
+func (r *RateRecorder) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.Record()
+		h.ServeHTTP(w, req)
+	})
+}
+
+// Transport returns a round tripper that records the time of each
+// request and then passes it to t. If t is nil,
+// http.DefaultTransport is used.
+func (r *RateRecorder) Transport(t http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		r.Record()
+		return t.RoundTrip(req)
+	})
+}
+
+// Record records a request at the current time. It may be used to
+// record calls that do not pass through HTTP.
+func (r *RateRecorder) Record() {
+	now := r.clock.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.times = append(r.times, now)
+}
+
+// Times returns the times of the requests recorded so far.
+func (r *RateRecorder) Times() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Time(nil), r.times...)
+}
+
+// Reset discards the times recorded so far.
+func (r *RateRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.times = nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Rate describes a request rate of N requests per period.
+type Rate struct {
+	N   int
+	Per time.Duration
+}
+
+// String returns a description of the rate.
+func (r Rate) String() string {
+	return fmt.Sprintf("%d requests per %v", r.N, r.Per)
+}
+
+type rateAtMostChecker struct {
+	*gc.CheckerInfo
+}
+
+// RateAtMost checks that the obtained []time.Time, as returned by
+// RateRecorder.Times, never exceeds the expected Rate: that no period
+// of length Rate.Per holds more than Rate.N of the times.
+var RateAtMost gc.Checker = &rateAtMostChecker{
+	&gc.CheckerInfo{Name: "RateAtMost", Params: []string{"obtained", "rate"}},
+}
+
+func (checker *rateAtMostChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	times, ok := params[0].([]time.Time)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []time.Time, got %T", params[0])
+	}
+	rate, ok := params[1].(Rate)
+	if !ok {
+		return false, fmt.Sprintf("rate must be of type httptesting.Rate, got %T", params[1])
+	}
+	if rate.N < 1 || rate.Per <= 0 {
+		return false, fmt.Sprintf("invalid rate %v", rate)
+	}
+	times = sortedTimes(times)
+	// The window starting at each time holds too many requests if
+	// it includes the time N places later.
+	for i := 0; i+rate.N < len(times); i++ {
+		if d := times[i+rate.N].Sub(times[i]); d < rate.Per {
+			return false, fmt.Sprintf("%d requests in %v, exceeding %v, starting with request %d; intervals:\n%s",
+				rate.N+1, d, rate, i, formatIntervals(times))
+		}
+	}
+	return true, ""
+}
+
+// Backoff describes a pattern of requests in which a client makes a
+// burst of requests, then backs off.
+type Backoff struct {
+	// Burst holds the number of requests that may be made at any
+	// times before the client must back off.
+	Burst int
+
+	// MinDelay holds the minimum delay between the requests that
+	// follow the burst.
+	MinDelay time.Duration
+}
+
+type backsOffChecker struct {
+	*gc.CheckerInfo
+}
+
+// BacksOff checks that the obtained []time.Time, as returned by
+// RateRecorder.Times, follows the expected Backoff: after the first
+// Backoff.Burst requests, each request must follow the previous one
+// by at least Backoff.MinDelay, and by at least as long as the
+// previous interval.
+var BacksOff gc.Checker = &backsOffChecker{
+	&gc.CheckerInfo{Name: "BacksOff", Params: []string{"obtained", "backoff"}},
+}
+
+func (checker *backsOffChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	times, ok := params[0].([]time.Time)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []time.Time, got %T", params[0])
+	}
+	backoff, ok := params[1].(Backoff)
+	if !ok {
+		return false, fmt.Sprintf("backoff must be of type httptesting.Backoff, got %T", params[1])
+	}
+	times = sortedTimes(times)
+	var prev time.Duration
+	for i := backoff.Burst; i < len(times); i++ {
+		if i == 0 {
+			continue
+		}
+		d := times[i].Sub(times[i-1])
+		switch {
+		case d < backoff.MinDelay:
+			return false, fmt.Sprintf("request %d followed the previous one after %v, less than %v; intervals:\n%s",
+				i, d, backoff.MinDelay, formatIntervals(times))
+		case d < prev:
+			return false, fmt.Sprintf("request %d followed the previous one after %v, less than the previous interval of %v; intervals:\n%s",
+				i, d, prev, formatIntervals(times))
+		}
+		prev = d
+	}
+	return true, ""
+}
+
+// sortedTimes returns a sorted copy of times, which may be out of
+// order when recorded by concurrent requests.
+func sortedTimes(times []time.Time) []time.Time {
+	times = append([]time.Time(nil), times...)
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	return times
+}
+
+// formatIntervals returns the intervals between the given times, one
+// per line.
+func formatIntervals(times []time.Time) string {
+	var buf strings.Builder
+	for i := 1; i < len(times); i++ {
+		fmt.Fprintf(&buf, "    %d: +%v\n", i, times[i].Sub(times[i-1]))
+	}
+	return buf.String()
+}
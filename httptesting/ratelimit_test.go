@@ -0,0 +1,158 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+	"github.com/juju/testing/testclock"
+)
+
+type rateLimitSuite struct{}
+
+var _ = gc.Suite(&rateLimitSuite{})
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// timesAt returns the times at the given offsets in milliseconds
+// from epoch.
+func timesAt(offsets ...int) []time.Time {
+	times := make([]time.Time, len(offsets))
+	for i, ms := range offsets {
+		times[i] = epoch.Add(time.Duration(ms) * time.Millisecond)
+	}
+	return times
+}
+
+func (s *rateLimitSuite) TestRecorderHandler(c *gc.C) {
+	clock := testclock.NewClock(epoch)
+	rec := httptesting.NewRateRecorder(clock)
+	h := rec.Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		c.Assert(w.Code, gc.Equals, http.StatusTeapot)
+		clock.Advance(100 * time.Millisecond)
+	}
+	c.Assert(rec.Times(), jc.DeepEquals, timesAt(0, 100, 200))
+	rec.Reset()
+	c.Assert(rec.Times(), gc.HasLen, 0)
+}
+
+func (s *rateLimitSuite) TestRecorderTransport(c *gc.C) {
+	clock := testclock.NewClock(epoch)
+	rec := httptesting.NewRateRecorder(clock)
+	t := httptesting.NewTransport()
+	t.Expect(httptesting.RequestMatch{Path: "/a"}, httptesting.Response{})
+	t.Expect(httptesting.RequestMatch{Path: "/b"}, httptesting.Response{})
+	client := &http.Client{Transport: rec.Transport(t)}
+	_, err := client.Get("http://example.com/a")
+	c.Assert(err, jc.ErrorIsNil)
+	clock.Advance(time.Second)
+	_, err = client.Get("http://example.com/b")
+	c.Assert(err, jc.ErrorIsNil)
+	t.Check(c)
+	c.Assert(rec.Times(), jc.DeepEquals, timesAt(0, 1000))
+}
+
+var rateCheckerTests = []struct {
+	about    string
+	checker  gc.Checker
+	times    []time.Time
+	expected interface{}
+	message  string
+}{{
+	about:    "rate respected",
+	checker:  httptesting.RateAtMost,
+	times:    timesAt(0, 0, 1000, 1000, 2000),
+	expected: httptesting.Rate{N: 2, Per: time.Second},
+}, {
+	about:    "rate exceeded",
+	checker:  httptesting.RateAtMost,
+	times:    timesAt(0, 600, 900, 1700),
+	expected: httptesting.Rate{N: 2, Per: time.Second},
+	message: `3 requests in 900ms, exceeding 2 requests per 1s, starting with request 0; intervals:
+    1: \+600ms
+    2: \+300ms
+    3: \+800ms
+`,
+}, {
+	about:    "times out of order",
+	checker:  httptesting.RateAtMost,
+	times:    timesAt(1000, 0),
+	expected: httptesting.Rate{N: 1, Per: time.Second},
+}, {
+	about:    "invalid rate",
+	checker:  httptesting.RateAtMost,
+	times:    nil,
+	expected: httptesting.Rate{},
+	message:  `invalid rate 0 requests per 0s`,
+}, {
+	about:    "rate of wrong type",
+	checker:  httptesting.RateAtMost,
+	times:    nil,
+	expected: 10,
+	message:  `rate must be of type httptesting.Rate, got int`,
+}, {
+	about:    "burst then backoff",
+	checker:  httptesting.BacksOff,
+	times:    timesAt(0, 1, 2, 102, 302, 702),
+	expected: httptesting.Backoff{Burst: 3, MinDelay: 100 * time.Millisecond},
+}, {
+	about:    "delay too short",
+	checker:  httptesting.BacksOff,
+	times:    timesAt(0, 1, 2, 52),
+	expected: httptesting.Backoff{Burst: 3, MinDelay: 100 * time.Millisecond},
+	message:  `request 3 followed the previous one after 50ms, less than 100ms; intervals:\n(.|\n)*`,
+}, {
+	about:    "burst too long",
+	checker:  httptesting.BacksOff,
+	times:    timesAt(0, 1, 2, 102),
+	expected: httptesting.Backoff{Burst: 2, MinDelay: 100 * time.Millisecond},
+	message:  `request 2 followed the previous one after 1ms, less than 100ms; intervals:\n(.|\n)*`,
+}, {
+	about:    "interval shrinks",
+	checker:  httptesting.BacksOff,
+	times:    timesAt(0, 200, 300),
+	expected: httptesting.Backoff{MinDelay: 100 * time.Millisecond},
+	message:  `request 2 followed the previous one after 100ms, less than the previous interval of 200ms; intervals:\n(.|\n)*`,
+}}
+
+func (s *rateLimitSuite) TestCheckers(c *gc.C) {
+	for i, test := range rateCheckerTests {
+		c.Logf("test %d: %s", i, test.about)
+		result, message := test.checker.Check([]interface{}{test.times, test.expected}, nil)
+		c.Check(result, gc.Equals, test.message == "")
+		c.Check(message, gc.Matches, test.message)
+	}
+}
+
+func (s *rateLimitSuite) TestRateLimitedClient(c *gc.C) {
+	// A client that waits 200ms between requests, using the fake
+	// clock, makes no more than 5 requests per second.
+	clock := testclock.NewClock(epoch)
+	rec := httptesting.NewRateRecorder(clock)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			rec.Record()
+			<-clock.After(200 * time.Millisecond)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		clock.WaitAdvance(c, 200*time.Millisecond, time.Second, 1)
+	}
+	<-done
+	c.Assert(rec.Times(), httptesting.RateAtMost, httptesting.Rate{N: 5, Per: time.Second})
+	c.Assert(rec.Times(), gc.Not(httptesting.RateAtMost), httptesting.Rate{N: 4, Per: time.Second})
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+)
+
+// Response holds a canned response served by a Server.
+type Response struct {
+	// Status holds the status code of the response.
+	// If it is zero, http.StatusOK is used.
+	Status int
+
+	// Header holds headers to add to the response.
+	Header http.Header `yaml:",omitempty"`
+
+	// Body holds the body of the response.
+	Body string `yaml:",omitempty"`
+}
+
+// Request holds a request recorded by a Server or Transport.
+type Request struct {
+	Method string
+	Host   string
+	Path   string
+	Query  url.Values  `yaml:",omitempty"`
+	Header http.Header `yaml:",omitempty"`
+	Body   string      `yaml:",omitempty"`
+}
+
+// String returns a description of the request.
+func (r Request) String() string {
+	s := r.Method + " " + r.Path
+	if len(r.Query) > 0 {
+		s += "?" + r.Query.Encode()
+	}
+	if r.Body != "" {
+		s += fmt.Sprintf(" %q", r.Body)
+	}
+	return s
+}
+
+// Server is an HTTP test server that serves canned responses and
+// records every request made to it. It is intended to stand in for
+// the remote API in tests of API clients:
+//
+//	srv := httptesting.NewServer()
+//	defer srv.Close()
+//	srv.Handle("GET", "/v1/widgets", httptesting.Response{Body: `[]`})
+//	... run the client against srv.URL ...
+//	c.Assert(srv.Requests(), httptesting.RequestsMatch, []httptesting.RequestMatch{
+//		{Method: "GET", Path: "/v1/widgets"},
+//	})
+//
+// A request for which no response has been registered gets a 404
+// response, and is recorded like any other.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   map[route][]http.Handler
+	requests []Request
+}
+
+// route identifies the requests served by a handler. An empty method
+// matches any method.
+type route struct {
+	method string
+	path   string
+}
+
+// NewServer starts and returns a new Server. The caller should call
+// Close when finished with it.
+func NewServer() *Server {
+	s := &Server{
+		routes: make(map[route][]http.Handler),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Handle registers a response for requests with the given method and
+// path. If method is empty, requests with any method match. If
+// several responses are registered for the same method and path, they
+// are served in turn, with the last being served to all remaining
+// requests.
+func (s *Server) Handle(method, path string, resp Response) {
+	s.HandleFunc(method, path, func(w http.ResponseWriter, req *http.Request) {
+		resp.serveHTTP(w)
+	})
+}
+
+// HandleFunc is like Handle but serves requests with the given
+// handler function. The request body has already been read, but is
+// still available to the handler.
+func (s *Server) HandleFunc(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := route{method, path}
+	s.routes[r] = append(s.routes[r], handler)
+}
+
+// Requests returns the requests made to the server so far, in the
+// order in which they were received.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests discards the requests recorded so far.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: req.Method,
+		Host:   req.Host,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query(),
+		Header: req.Header.Clone(),
+		Body:   string(body),
+	})
+	handler := s.nextHandler(req)
+	s.mu.Unlock()
+
+	if handler == nil {
+		http.Error(w, fmt.Sprintf("no response registered for %s %s", req.Method, req.URL.Path), http.StatusNotFound)
+		return
+	}
+	handler.ServeHTTP(w, req)
+}
+
+// nextHandler returns the handler for the given request, preferring
+// one registered for its method. It must be called with s.mu held.
+func (s *Server) nextHandler(req *http.Request) http.Handler {
+	for _, r := range []route{{req.Method, req.URL.Path}, {"", req.URL.Path}} {
+		handlers := s.routes[r]
+		if len(handlers) == 0 {
+			continue
+		}
+		if len(handlers) > 1 {
+			s.routes[r] = handlers[1:]
+		}
+		return handlers[0]
+	}
+	return nil
+}
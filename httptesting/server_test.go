@@ -0,0 +1,192 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+)
+
+type serverSuite struct {
+	srv *httptesting.Server
+}
+
+var _ = gc.Suite(&serverSuite{})
+
+func (s *serverSuite) SetUpTest(c *gc.C) {
+	s.srv = httptesting.NewServer()
+}
+
+func (s *serverSuite) TearDownTest(c *gc.C) {
+	s.srv.Close()
+}
+
+func (s *serverSuite) do(c *gc.C, method, path, body string) (int, string) {
+	req, err := http.NewRequest(method, s.srv.URL+path, strings.NewReader(body))
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("X-Test", "value")
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp.StatusCode, string(data)
+}
+
+func (s *serverSuite) TestHandle(c *gc.C) {
+	s.srv.Handle("GET", "/widgets", httptesting.Response{
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   `[]`,
+	})
+	s.srv.Handle("POST", "/widgets", httptesting.Response{
+		Status: http.StatusCreated,
+		Body:   `{"id":1}`,
+	})
+	status, body := s.do(c, "GET", "/widgets", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, `[]`)
+	status, body = s.do(c, "POST", "/widgets", `{"name":"w"}`)
+	c.Assert(status, gc.Equals, http.StatusCreated)
+	c.Assert(body, gc.Equals, `{"id":1}`)
+}
+
+func (s *serverSuite) TestHandleSequence(c *gc.C) {
+	s.srv.Handle("", "/status", httptesting.Response{Status: http.StatusServiceUnavailable})
+	s.srv.Handle("", "/status", httptesting.Response{Body: "ok"})
+	for _, want := range []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK} {
+		status, _ := s.do(c, "GET", "/status", "")
+		c.Assert(status, gc.Equals, want)
+	}
+}
+
+func (s *serverSuite) TestHandleMethodPreferred(c *gc.C) {
+	s.srv.Handle("", "/x", httptesting.Response{Body: "any"})
+	s.srv.Handle("PUT", "/x", httptesting.Response{Body: "put"})
+	_, body := s.do(c, "PUT", "/x", "")
+	c.Assert(body, gc.Equals, "put")
+	_, body = s.do(c, "DELETE", "/x", "")
+	c.Assert(body, gc.Equals, "any")
+}
+
+func (s *serverSuite) TestHandleFunc(c *gc.C) {
+	s.srv.HandleFunc("POST", "/echo", func(w http.ResponseWriter, req *http.Request) {
+		data, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		w.Write(data)
+	})
+	_, body := s.do(c, "POST", "/echo", "hello")
+	c.Assert(body, gc.Equals, "hello")
+	c.Assert(s.srv.Requests()[0].Body, gc.Equals, "hello")
+}
+
+func (s *serverSuite) TestNotRegistered(c *gc.C) {
+	status, body := s.do(c, "GET", "/missing", "")
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+	c.Assert(body, gc.Equals, "no response registered for GET /missing\n")
+	c.Assert(s.srv.Requests(), gc.HasLen, 1)
+}
+
+func (s *serverSuite) TestRequests(c *gc.C) {
+	s.do(c, "GET", "/a?x=1&y=2", "")
+	s.do(c, "POST", "/b", `{"k": "v"}`)
+	requests := s.srv.Requests()
+	c.Assert(requests, gc.HasLen, 2)
+	c.Assert(requests[0].Method, gc.Equals, "GET")
+	c.Assert(requests[0].Path, gc.Equals, "/a")
+	c.Assert(requests[0].Query, jc.DeepEquals, url.Values{"x": {"1"}, "y": {"2"}})
+	c.Assert(requests[0].Header.Get("X-Test"), gc.Equals, "value")
+	c.Assert(requests[1].String(), gc.Equals, `POST /b "{\"k\": \"v\"}"`)
+
+	c.Assert(requests, httptesting.RequestsMatch, []httptesting.RequestMatch{{
+		Method: "GET",
+		Path:   "/a",
+		Query:  url.Values{"x": {"1"}},
+		Header: http.Header{"x-test": {"value"}},
+	}, {
+		Method:   "POST",
+		JSONBody: map[string]string{"k": "v"},
+	}})
+	c.Assert(requests, httptesting.HasRequest, httptesting.RequestMatch{Path: "/b"})
+
+	s.srv.ResetRequests()
+	c.Assert(s.srv.Requests(), gc.HasLen, 0)
+}
+
+var requestsMatchTests = []struct {
+	about    string
+	expected []httptesting.RequestMatch
+	message  string
+}{{
+	about: "wrong method",
+	expected: []httptesting.RequestMatch{
+		{Method: "GET", Path: "/a"},
+		{Method: "GET", Path: "/b"},
+	},
+	message: `requests do not match:
+    GET /a
+  - GET /b
+  + POST /b "body"
+`,
+}, {
+	about: "missing request",
+	expected: []httptesting.RequestMatch{
+		{Path: "/a"},
+		{Path: "/b"},
+		{Method: "DELETE"},
+	},
+	message: `requests do not match:
+    GET /a
+    POST /b "body"
+  - DELETE *
+`,
+}, {
+	about:    "extra request",
+	expected: []httptesting.RequestMatch{{Path: "/a"}},
+	message: `requests do not match:
+    GET /a
+  + POST /b "body"
+`,
+}, {
+	about: "wrong body",
+	expected: []httptesting.RequestMatch{
+		{Path: "/a"},
+		{Body: "other"},
+	},
+	message: `requests do not match:
+    GET /a
+  - ANY * "other"
+  + POST /b "body"
+`,
+}}
+
+func (s *serverSuite) TestRequestsMatchFailure(c *gc.C) {
+	requests := []httptesting.Request{
+		{Method: "GET", Path: "/a"},
+		{Method: "POST", Path: "/b", Body: "body"},
+	}
+	for i, test := range requestsMatchTests {
+		c.Logf("test %d: %s", i, test.about)
+		result, message := httptesting.RequestsMatch.Check([]interface{}{requests, test.expected}, nil)
+		c.Check(result, jc.IsFalse)
+		c.Check(message, gc.Equals, test.message)
+	}
+}
+
+func (s *serverSuite) TestHasRequestFailure(c *gc.C) {
+	requests := []httptesting.Request{{Method: "GET", Path: "/a"}}
+	result, message := httptesting.HasRequest.Check([]interface{}{requests, httptesting.RequestMatch{Method: "PUT"}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "no request matches PUT *; requests:\n    GET /a\n")
+
+	result, message = httptesting.HasRequest.Check([]interface{}{"foo", httptesting.RequestMatch{}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, "obtained value must be of type []httptesting.Request, got string")
+}
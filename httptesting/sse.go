@@ -0,0 +1,394 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// SSEEvent holds a server-sent event.
+type SSEEvent struct {
+	// ID holds the event's id field, if any. Events read by an
+	// SSEClient hold the last ID sent on the stream, as in the
+	// browser's EventSource API.
+	ID string
+
+	// Event holds the event type. It is empty for the default
+	// type, "message".
+	Event string
+
+	// Data holds the event data. Multi-line data is sent as
+	// several data fields.
+	Data string
+
+	// Retry holds the reconnection time in milliseconds, if any.
+	Retry int
+}
+
+// String returns the event in wire format.
+func (ev SSEEvent) String() string {
+	var buf strings.Builder
+	if ev.ID != "" {
+		fmt.Fprintf(&buf, "id: %s\n", ev.ID)
+	}
+	if ev.Event != "" {
+		fmt.Fprintf(&buf, "event: %s\n", ev.Event)
+	}
+	if ev.Retry != 0 {
+		fmt.Fprintf(&buf, "retry: %d\n", ev.Retry)
+	}
+	for _, line := range strings.Split(ev.Data, "\n") {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+	buf.WriteString("\n")
+	return buf.String()
+}
+
+// SSEServer is a test server for clients of server-sent event
+// streams. Each request to it starts a stream, which the test scripts:
+//
+//	srv := httptesting.NewSSEServer()
+//	defer srv.Close()
+//	... start the client, connecting to srv.URL ...
+//	stream := srv.Accept(c)
+//	stream.Send(c, httptesting.SSEEvent{Event: "update", Data: `{"id": 1}`})
+//	stream.Close()
+//
+// Waits are timed with Clock, so that a test using a testclock.Clock
+// controls them; they time out after Timeout.
+type SSEServer struct {
+	*httptest.Server
+
+	// Clock is used to time waits. If it is nil,
+	// clock.WallClock is used.
+	Clock clock.Clock
+
+	// Timeout holds how long to wait for a client to connect. If
+	// it is zero, testing.LongWait is used.
+	Timeout time.Duration
+
+	streams chan *SSEStream
+}
+
+// NewSSEServer starts and returns a new SSEServer. The caller should
+// call Close when finished with it.
+func NewSSEServer() *SSEServer {
+	s := &SSEServer{
+		streams: make(chan *SSEStream, 10),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Accept waits for a client to connect and returns its stream.
+func (s *SSEServer) Accept(c *gc.C) *SSEStream {
+	select {
+	case stream := <-s.streams:
+		return stream
+	case <-clockOrWall(s.Clock).After(timeoutOrLongWait(s.Timeout)):
+		c.Fatalf("timed out waiting for event stream client")
+		return nil
+	}
+}
+
+// Close closes any streams that are still open and shuts down the
+// server.
+func (s *SSEServer) Close() {
+	s.Server.CloseClientConnections()
+	s.Server.Close()
+}
+
+func (s *SSEServer) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	stream := &SSEStream{
+		Request: req,
+		w:       w,
+		flusher: flusher,
+		closed:  make(chan struct{}),
+	}
+	s.streams <- stream
+	select {
+	case <-stream.closed:
+	case <-req.Context().Done():
+	}
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	stream.done = true
+}
+
+// SSEStream is an event stream served by an SSEServer.
+type SSEStream struct {
+	// Request holds the client's request.
+	Request *http.Request
+
+	mu        sync.Mutex
+	w         io.Writer
+	flusher   http.Flusher
+	done      bool
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Send writes the event to the stream and flushes it to the client.
+func (stream *SSEStream) Send(c *gc.C, ev SSEEvent) {
+	stream.Write(c, ev)
+	stream.Flush(c)
+}
+
+// Write writes the event to the stream without flushing it, so that
+// the test can control when the client receives it. Events may be
+// written in pieces by writing their wire form with WriteRaw.
+func (stream *SSEStream) Write(c *gc.C, ev SSEEvent) {
+	stream.WriteRaw(c, ev.String())
+}
+
+// WriteRaw writes the given text to the stream without flushing it.
+func (stream *SSEStream) WriteRaw(c *gc.C, text string) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.done {
+		c.Fatalf("event stream has been closed")
+	}
+	_, err := io.WriteString(stream.w, text)
+	c.Assert(err, gc.IsNil)
+}
+
+// Comment writes a comment line, as used for keep-alives, to the
+// stream and flushes it.
+func (stream *SSEStream) Comment(c *gc.C, text string) {
+	stream.WriteRaw(c, ": "+text+"\n")
+	stream.Flush(c)
+}
+
+// Flush sends everything written so far to the client.
+func (stream *SSEStream) Flush(c *gc.C) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.done {
+		c.Fatalf("event stream has been closed")
+	}
+	stream.flusher.Flush()
+}
+
+// Close ends the stream, completing the response.
+func (stream *SSEStream) Close() {
+	stream.closeOnce.Do(func() {
+		close(stream.closed)
+	})
+}
+
+// SSEClient reads server-sent events from a response, for testing
+// event stream endpoints:
+//
+//	client := httptesting.DialSSE(c, srv.URL+"/events")
+//	defer client.Close()
+//	client.AssertEvent(c, httptesting.SSEEvent{Event: "update", Data: `{"id": 1}`})
+//
+// Waits are timed with Clock, so that a test using a testclock.Clock
+// controls them; they time out after Timeout.
+type SSEClient struct {
+	// Response holds the response holding the stream.
+	Response *http.Response
+
+	// Clock is used to time waits. If it is nil,
+	// clock.WallClock is used.
+	Clock clock.Clock
+
+	// Timeout holds how long to wait for an event. If it is zero,
+	// testing.LongWait is used.
+	Timeout time.Duration
+
+	events chan SSEEvent
+	// done is closed when the stream ends, after err is set.
+	done chan struct{}
+	err  error
+}
+
+// DialSSE makes a GET request for the event stream at the given URL
+// and returns a client reading from it. The response must have status
+// 200 and content type text/event-stream.
+func DialSSE(c *gc.C, url string) *SSEClient {
+	req, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, gc.IsNil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, gc.IsNil)
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		c.Fatalf("unexpected response status %q from event stream", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		resp.Body.Close()
+		c.Fatalf("unexpected content type %q for event stream", ct)
+	}
+	return NewSSEClient(resp)
+}
+
+// NewSSEClient returns a client reading events from the body of the
+// given response.
+func NewSSEClient(resp *http.Response) *SSEClient {
+	client := &SSEClient{
+		Response: resp,
+		events:   make(chan SSEEvent, 100),
+		done:     make(chan struct{}),
+	}
+	go client.readLoop()
+	return client
+}
+
+// Close closes the response body.
+func (client *SSEClient) Close() error {
+	return client.Response.Body.Close()
+}
+
+// Next waits for the next event, failing the test if none arrives in
+// time or the stream ends first.
+func (client *SSEClient) Next(c *gc.C) SSEEvent {
+	select {
+	case ev := <-client.events:
+		return ev
+	case <-client.done:
+		// Deliver any event received before the stream ended.
+		select {
+		case ev := <-client.events:
+			return ev
+		default:
+		}
+		c.Fatalf("event stream ended while waiting for event: %v", client.err)
+	case <-clockOrWall(client.Clock).After(timeoutOrLongWait(client.Timeout)):
+		c.Fatalf("timed out waiting for event")
+	}
+	return SSEEvent{}
+}
+
+// AssertEvent checks that the next event is equal to expected.
+func (client *SSEClient) AssertEvent(c *gc.C, expected SSEEvent) {
+	c.Assert(client.Next(c), jc.DeepEquals, expected)
+}
+
+// AssertData checks that the next event has the given data,
+// whatever its other fields.
+func (client *SSEClient) AssertData(c *gc.C, data string) {
+	ev := client.Next(c)
+	c.Assert(ev.Data, gc.Equals, data, gc.Commentf("event %#v", ev))
+}
+
+// AssertNoEvent checks that no event arrives within the given
+// duration, as measured by the client's clock.
+func (client *SSEClient) AssertNoEvent(c *gc.C, d time.Duration) {
+	select {
+	case ev := <-client.events:
+		c.Fatalf("unexpected event %#v", ev)
+	case <-clockOrWall(client.Clock).After(d):
+	}
+}
+
+// AssertEnded checks that the stream ends cleanly, without any
+// further events.
+func (client *SSEClient) AssertEnded(c *gc.C) {
+	select {
+	case ev := <-client.events:
+		c.Fatalf("unexpected event %#v", ev)
+	case <-client.done:
+		select {
+		case ev := <-client.events:
+			c.Fatalf("unexpected event %#v", ev)
+		default:
+		}
+		c.Assert(client.err, gc.IsNil)
+	case <-clockOrWall(client.Clock).After(timeoutOrLongWait(client.Timeout)):
+		c.Fatalf("timed out waiting for event stream to end")
+	}
+}
+
+// readLoop parses events from the response body until it ends.
+func (client *SSEClient) readLoop() {
+	defer close(client.done)
+	scanner := bufio.NewScanner(client.Response.Body)
+	scanner.Split(scanSSELines)
+	var ev SSEEvent
+	var data []string
+	var lastID string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// Events without data are not dispatched.
+			if data != nil {
+				ev.ID = lastID
+				ev.Data = strings.Join(data, "\n")
+				client.events <- ev
+			}
+			ev, data = SSEEvent{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "id":
+			lastID = value
+		case "event":
+			ev.Event = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if n, err := strconv.Atoi(value); err == nil {
+				ev.Retry = n
+			}
+		}
+	}
+	client.err = scanner.Err()
+}
+
+// scanSSELines is a bufio.SplitFunc that splits lines ending in
+// "\r\n", "\n" or "\r", as event streams may use any of them.
+func scanSSELines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	for i, b := range data {
+		switch b {
+		case '\n':
+			return i + 1, data[:i], nil
+		case '\r':
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if atEOF {
+				return i + 1, data[:i], nil
+			}
+			// Wait to see whether "\n" follows.
+			return 0, nil, nil
+		}
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
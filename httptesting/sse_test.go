@@ -0,0 +1,123 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	"github.com/juju/testing/httptesting"
+	"github.com/juju/testing/testclock"
+)
+
+type sseSuite struct {
+	srv *httptesting.SSEServer
+}
+
+var _ = gc.Suite(&sseSuite{})
+
+func (s *sseSuite) SetUpTest(c *gc.C) {
+	s.srv = httptesting.NewSSEServer()
+}
+
+func (s *sseSuite) TearDownTest(c *gc.C) {
+	s.srv.Close()
+}
+
+func (s *sseSuite) TestEventString(c *gc.C) {
+	ev := httptesting.SSEEvent{ID: "7", Event: "update", Data: "line1\nline2", Retry: 500}
+	c.Assert(ev.String(), gc.Equals, "id: 7\nevent: update\nretry: 500\ndata: line1\ndata: line2\n\n")
+	c.Assert(httptesting.SSEEvent{}.String(), gc.Equals, "data: \n\n")
+}
+
+func (s *sseSuite) TestSendEvents(c *gc.C) {
+	client := httptesting.DialSSE(c, s.srv.URL+"/events")
+	defer client.Close()
+	stream := s.srv.Accept(c)
+	c.Assert(stream.Request.URL.Path, gc.Equals, "/events")
+	c.Assert(stream.Request.Header.Get("Accept"), gc.Equals, "text/event-stream")
+
+	stream.Send(c, httptesting.SSEEvent{Data: "hello"})
+	client.AssertEvent(c, httptesting.SSEEvent{Data: "hello"})
+
+	stream.Comment(c, "keep-alive")
+	stream.Send(c, httptesting.SSEEvent{ID: "1", Event: "update", Data: "a\nb", Retry: 100})
+	client.AssertEvent(c, httptesting.SSEEvent{ID: "1", Event: "update", Data: "a\nb", Retry: 100})
+
+	// The last ID persists.
+	stream.Send(c, httptesting.SSEEvent{Data: "again"})
+	client.AssertEvent(c, httptesting.SSEEvent{ID: "1", Data: "again"})
+
+	stream.Close()
+	client.AssertEnded(c)
+}
+
+func (s *sseSuite) TestFlushTiming(c *gc.C) {
+	client := httptesting.DialSSE(c, s.srv.URL)
+	defer client.Close()
+	stream := s.srv.Accept(c)
+
+	stream.Write(c, httptesting.SSEEvent{Data: "buffered"})
+	client.AssertNoEvent(c, testing.ShortWait)
+	stream.Flush(c)
+	client.AssertData(c, "buffered")
+
+	// An event split across flushes is only dispatched when
+	// complete.
+	stream.WriteRaw(c, "data: part")
+	stream.Flush(c)
+	client.AssertNoEvent(c, testing.ShortWait)
+	stream.WriteRaw(c, "ial\r\n\r\n")
+	stream.Flush(c)
+	client.AssertData(c, "partial")
+}
+
+func (s *sseSuite) TestNextAfterEnd(c *gc.C) {
+	client := httptesting.DialSSE(c, s.srv.URL)
+	defer client.Close()
+	stream := s.srv.Accept(c)
+	stream.Send(c, httptesting.SSEEvent{Data: "last"})
+	stream.Close()
+	client.AssertData(c, "last")
+	c.ExpectFailure("stream ended before another event")
+	client.Next(c)
+}
+
+func (s *sseSuite) TestNextTimeout(c *gc.C) {
+	client := httptesting.DialSSE(c, s.srv.URL)
+	defer client.Close()
+	s.srv.Accept(c)
+
+	clock := testclock.NewClock(time.Now())
+	client.Clock = clock
+	client.Timeout = time.Minute
+	go clock.WaitAdvance(c, time.Minute, time.Second, 1)
+	c.ExpectFailure("no event arrives before the fake clock passes the timeout")
+	client.Next(c)
+}
+
+func (s *sseSuite) TestNewSSEClient(c *gc.C) {
+	resp := &http.Response{
+		Body: ioutil.NopCloser(strings.NewReader(": comment\rdata: x\r\rdata\nevent: e\n\nid: 3\n\ndata: incomplete")),
+	}
+	client := httptesting.NewSSEClient(resp)
+	client.AssertEvent(c, httptesting.SSEEvent{Data: "x"})
+	// A data field with no colon holds empty data; an event with
+	// only an ID is not dispatched.
+	client.AssertEvent(c, httptesting.SSEEvent{Event: "e"})
+	client.AssertEnded(c)
+}
+
+func (s *sseSuite) TestDialWrongContentType(c *gc.C) {
+	srv := httptesting.NewServer()
+	defer srv.Close()
+	srv.Handle("GET", "/", httptesting.Response{Body: "not events"})
+	c.ExpectFailure("response is not an event stream")
+	httptesting.DialSSE(c, srv.URL)
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Transport is an http.RoundTripper that serves scripted responses
+// to an expected sequence of requests, so that client code can be
+// tested without a server:
+//
+//	t := httptesting.NewTransport()
+//	t.Expect(httptesting.RequestMatch{Method: "GET", Path: "/v1/widgets"}, httptesting.Response{Body: `[]`})
+//	client := &http.Client{Transport: t}
+//	... run the client ...
+//	t.Check(c)
+//
+// Each request must match the next expectation; any other request
+// fails with an error and leaves the expectation in place. Check
+// fails the test if any request was unexpected or any expectation
+// was not met, showing the expected and actual request sequences.
+type Transport struct {
+	mu       sync.Mutex
+	expected []expectation
+	next     int
+	requests []Request
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// expectation holds an expected request and the outcome to return
+// for it.
+type expectation struct {
+	match RequestMatch
+	resp  Response
+	err   error
+}
+
+// NewTransport returns a Transport with no expectations.
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+// Expect adds an expected request, to which resp will be returned.
+func (t *Transport) Expect(match RequestMatch, resp Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expected = append(t.expected, expectation{match: match, resp: resp})
+}
+
+// ExpectError adds an expected request, for which RoundTrip will
+// return err, as if the connection failed.
+func (t *Transport) ExpectError(match RequestMatch, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expected = append(t.expected, expectation{match: match, err: err})
+}
+
+// Client returns an HTTP client that uses the transport.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Requests returns the requests made so far, including unexpected
+// ones.
+func (t *Transport) Requests() []Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Request(nil), t.requests...)
+}
+
+// Check checks that the requests made so far are exactly those
+// expected, in order.
+func (t *Transport) Check(c *gc.C) {
+	t.mu.Lock()
+	matches := make([]RequestMatch, len(t.expected))
+	for i, e := range t.expected {
+		matches[i] = e.match
+	}
+	requests := append([]Request(nil), t.requests...)
+	t.mu.Unlock()
+	c.Check(requests, RequestsMatch, matches)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r, err := clientRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = append(t.requests, r)
+	if t.next >= len(t.expected) || !t.expected[t.next].match.matches(r) {
+		return nil, fmt.Errorf("unexpected request %s", r)
+	}
+	e := t.expected[t.next]
+	t.next++
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.resp.httpResponse(req), nil
+}
+
+// clientRequest returns a record of a request made by a client. The
+// request body is read, and replaced so that it can be read again.
+func clientRequest(req *http.Request) (Request, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return Request{}, fmt.Errorf("cannot read request body: %v", err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	return Request{
+		Method: req.Method,
+		Host:   req.URL.Host,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query(),
+		Header: req.Header.Clone(),
+		Body:   string(body),
+	}, nil
+}
+
+// httpResponse returns resp as a response to req.
+func (resp Response) httpResponse(req *http.Request) *http.Response {
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := make(http.Header)
+	for key, values := range resp.Header {
+		header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+)
+
+type transportSuite struct{}
+
+var _ = gc.Suite(&transportSuite{})
+
+func (s *transportSuite) TestExpectedRequests(c *gc.C) {
+	t := httptesting.NewTransport()
+	t.Expect(httptesting.RequestMatch{
+		Method: "GET",
+		Host:   "api.example.com",
+		Path:   "/v1/widgets",
+	}, httptesting.Response{
+		Header: http.Header{"content-type": {"application/json"}},
+		Body:   `[]`,
+	})
+	t.Expect(httptesting.RequestMatch{
+		Method:   "POST",
+		Path:     "/v1/widgets",
+		JSONBody: map[string]string{"name": "w"},
+	}, httptesting.Response{
+		Status: http.StatusCreated,
+	})
+	client := t.Client()
+
+	resp, err := client.Get("https://api.example.com/v1/widgets")
+	c.Assert(err, jc.ErrorIsNil)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Assert(string(body), gc.Equals, `[]`)
+
+	resp, err = client.Post("https://api.example.com/v1/widgets", "application/json", strings.NewReader(`{"name": "w"}`))
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusCreated)
+	c.Assert(resp.Status, gc.Equals, "201 Created")
+
+	t.Check(c)
+}
+
+func (s *transportSuite) TestExpectError(c *gc.C) {
+	t := httptesting.NewTransport()
+	failure := errors.New("connection refused")
+	t.ExpectError(httptesting.RequestMatch{Path: "/"}, failure)
+	_, err := t.Client().Get("http://example.com/")
+	c.Assert(errors.Is(err, failure), jc.IsTrue)
+	t.Check(c)
+}
+
+func (s *transportSuite) TestUnexpectedRequest(c *gc.C) {
+	t := httptesting.NewTransport()
+	t.Expect(httptesting.RequestMatch{Method: "GET", Path: "/a"}, httptesting.Response{})
+	_, err := t.Client().Get("http://example.com/b")
+	c.Assert(err, gc.ErrorMatches, `Get "http://example.com/b": unexpected request GET /b`)
+
+	// The expectation is still in place.
+	_, err = t.Client().Get("http://example.com/a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(t.Requests(), gc.HasLen, 2)
+	c.ExpectFailure("an unexpected request was made")
+	t.Check(c)
+}
+
+func (s *transportSuite) TestMissingRequest(c *gc.C) {
+	t := httptesting.NewTransport()
+	t.Expect(httptesting.RequestMatch{Path: "/a"}, httptesting.Response{})
+	t.Expect(httptesting.RequestMatch{Path: "/b"}, httptesting.Response{})
+	_, err := t.Client().Get("http://example.com/a")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = t.Client().Get("http://example.com/c")
+	c.Assert(err, gc.NotNil)
+
+	matches := []httptesting.RequestMatch{{Path: "/a"}, {Path: "/b"}}
+	result, message := httptesting.RequestsMatch.Check([]interface{}{t.Requests(), matches}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(message, gc.Equals, `requests do not match:
+    GET /a
+  - ANY /b
+  + GET /c
+`)
+}
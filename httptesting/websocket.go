@@ -0,0 +1,439 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+// WebSocket opcodes, as defined by RFC 6455.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xa
+)
+
+// websocketGUID is the value appended to the client's key when
+// computing Sec-WebSocket-Accept.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket close codes commonly used in tests.
+const (
+	CloseNormal    = 1000
+	CloseGoingAway = 1001
+	CloseNoStatus  = 1005
+)
+
+// WebSocketServer is a test server that accepts WebSocket connections
+// and hands each one to the test, which scripts the messages to send
+// and asserts on those received:
+//
+//	srv := httptesting.NewWebSocketServer()
+//	defer srv.Close()
+//	... start the client, connecting to srv.WSURL("/stream") ...
+//	conn := srv.Accept(c)
+//	conn.WriteText(c, `{"event": "start"}`)
+//	conn.AssertJSON(c, map[string]string{"ack": "start"})
+//	conn.Close(c, httptesting.CloseNormal, "")
+//
+// Waits are timed with Clock, so that a test using a testclock.Clock
+// controls them; they time out after Timeout.
+type WebSocketServer struct {
+	*httptest.Server
+
+	// Clock is used to time waits. If it is nil,
+	// clock.WallClock is used.
+	Clock clock.Clock
+
+	// Timeout holds how long to wait for a connection or a
+	// message. If it is zero, testing.LongWait is used.
+	Timeout time.Duration
+
+	conns chan *WebSocketConn
+
+	mu       sync.Mutex
+	accepted []*WebSocketConn
+}
+
+// NewWebSocketServer starts and returns a new WebSocketServer. The
+// caller should call Close when finished with it.
+func NewWebSocketServer() *WebSocketServer {
+	s := &WebSocketServer{
+		conns: make(chan *WebSocketConn, 10),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// WSURL returns the ws:// URL for the given path on the server.
+func (s *WebSocketServer) WSURL(path string) string {
+	return "ws" + strings.TrimPrefix(s.URL, "http") + path
+}
+
+// Accept waits for a client to connect and returns the connection.
+func (s *WebSocketServer) Accept(c *gc.C) *WebSocketConn {
+	select {
+	case conn := <-s.conns:
+		return conn
+	case <-s.clock().After(s.timeout()):
+		c.Fatalf("timed out waiting for websocket connection")
+		return nil
+	}
+}
+
+// Close closes any connections that are still open and shuts down
+// the server.
+func (s *WebSocketServer) Close() {
+	s.mu.Lock()
+	accepted := s.accepted
+	s.accepted = nil
+	s.mu.Unlock()
+	for _, conn := range accepted {
+		conn.conn.Close()
+	}
+	s.Server.Close()
+}
+
+func (s *WebSocketServer) clock() clock.Clock {
+	return clockOrWall(s.Clock)
+}
+
+func (s *WebSocketServer) timeout() time.Duration {
+	return timeoutOrLongWait(s.Timeout)
+}
+
+// clockOrWall returns clk, or the wall clock if it is nil.
+func clockOrWall(clk clock.Clock) clock.Clock {
+	if clk == nil {
+		return clock.WallClock
+	}
+	return clk
+}
+
+// timeoutOrLongWait returns d, or testing.LongWait if it is zero.
+func timeoutOrLongWait(d time.Duration) time.Duration {
+	if d == 0 {
+		return testing.LongWait
+	}
+	return d
+}
+
+func (s *WebSocketServer) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(req.Header, "Connection", "upgrade") ||
+		!headerContains(req.Header, "Upgrade", "websocket") || key == "" {
+		http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "cannot hijack connection", http.StatusInternalServerError)
+		return
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot hijack connection: %v", err), http.StatusInternalServerError)
+		return
+	}
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return
+	}
+	conn := &WebSocketConn{
+		Request:  req,
+		srv:      s,
+		conn:     netConn,
+		r:        rw.Reader,
+		messages: make(chan WebSocketMessage, 100),
+		closed:   make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.accepted = append(s.accepted, conn)
+	s.mu.Unlock()
+	go conn.readLoop()
+	s.conns <- conn
+}
+
+// headerContains reports whether the comma-separated header holds
+// the given token, ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h[name] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WebSocketMessage holds a message received from a WebSocket client.
+type WebSocketMessage struct {
+	// Binary reports whether the message was sent as binary,
+	// rather than text.
+	Binary bool
+
+	// Data holds the contents of the message.
+	Data []byte
+}
+
+// WebSocketConn is a server-side WebSocket connection accepted by a
+// WebSocketServer. Pings from the client are answered automatically.
+type WebSocketConn struct {
+	// Request holds the client's handshake request.
+	Request *http.Request
+
+	srv  *WebSocketServer
+	conn net.Conn
+	r    *bufio.Reader
+
+	writeMu sync.Mutex
+
+	messages chan WebSocketMessage
+	// closed is closed when the reader stops, after closeCode,
+	// closeReason and readErr have been set.
+	closed      chan struct{}
+	closeCode   int
+	closeReason string
+	gotClose    bool
+	readErr     error
+}
+
+// WriteText sends a text message to the client.
+func (conn *WebSocketConn) WriteText(c *gc.C, text string) {
+	err := conn.writeFrame(wsText, []byte(text))
+	c.Assert(err, gc.IsNil)
+}
+
+// WriteBinary sends a binary message to the client.
+func (conn *WebSocketConn) WriteBinary(c *gc.C, data []byte) {
+	err := conn.writeFrame(wsBinary, data)
+	c.Assert(err, gc.IsNil)
+}
+
+// WriteJSON sends v, marshaled as JSON, as a text message.
+func (conn *WebSocketConn) WriteJSON(c *gc.C, v interface{}) {
+	data, err := json.Marshal(v)
+	c.Assert(err, gc.IsNil)
+	conn.WriteText(c, string(data))
+}
+
+// ReadMessage waits for the next message from the client, failing
+// the test if none arrives in time or the client closes the
+// connection first.
+func (conn *WebSocketConn) ReadMessage(c *gc.C) WebSocketMessage {
+	select {
+	case m := <-conn.messages:
+		return m
+	case <-conn.closed:
+		// Deliver any message received before the close.
+		select {
+		case m := <-conn.messages:
+			return m
+		default:
+		}
+		if conn.gotClose {
+			c.Fatalf("websocket closed by client (code %d %q) while waiting for message", conn.closeCode, conn.closeReason)
+		}
+		c.Fatalf("websocket connection failed while waiting for message: %v", conn.readErr)
+	case <-conn.srv.clock().After(conn.srv.timeout()):
+		c.Fatalf("timed out waiting for websocket message")
+	}
+	return WebSocketMessage{}
+}
+
+// AssertText checks that the next message from the client is a text
+// message with the given contents.
+func (conn *WebSocketConn) AssertText(c *gc.C, expected string) {
+	m := conn.ReadMessage(c)
+	c.Assert(m.Binary, gc.Equals, false, gc.Commentf("binary message %q", m.Data))
+	c.Assert(string(m.Data), gc.Equals, expected)
+}
+
+// AssertBinary checks that the next message from the client is a
+// binary message with the given contents.
+func (conn *WebSocketConn) AssertBinary(c *gc.C, expected []byte) {
+	m := conn.ReadMessage(c)
+	c.Assert(m.Binary, gc.Equals, true, gc.Commentf("text message %q", m.Data))
+	c.Assert(m.Data, jc.DeepEquals, expected)
+}
+
+// AssertJSON checks that the next message from the client is a text
+// message holding JSON equal to expected when that is marshaled.
+func (conn *WebSocketConn) AssertJSON(c *gc.C, expected interface{}) {
+	m := conn.ReadMessage(c)
+	c.Assert(m.Binary, gc.Equals, false, gc.Commentf("binary message %q", m.Data))
+	c.Assert(string(m.Data), jc.JSONEquals, expected)
+}
+
+// Close starts a close handshake with the given code and reason, and
+// checks that the client completes it by replying with a close frame.
+// Any messages that have not been read are discarded.
+func (conn *WebSocketConn) Close(c *gc.C, code int, reason string) {
+	err := conn.writeFrame(wsClose, closePayload(code, reason))
+	c.Assert(err, gc.IsNil)
+	conn.waitClosed(c)
+	conn.conn.Close()
+	c.Assert(conn.gotClose, jc.IsTrue, gc.Commentf("client did not complete close handshake: %v", conn.readErr))
+}
+
+// AssertClosed waits for the client to start a close handshake and
+// checks that it sends the given code, then completes the handshake.
+func (conn *WebSocketConn) AssertClosed(c *gc.C, code int) {
+	conn.waitClosed(c)
+	defer conn.conn.Close()
+	if !conn.gotClose {
+		c.Fatalf("websocket connection failed without close handshake: %v", conn.readErr)
+	}
+	err := conn.writeFrame(wsClose, closePayload(conn.closeCode, ""))
+	c.Check(err, gc.IsNil)
+	if conn.closeCode != code {
+		c.Fatalf("websocket closed with code %d %q, want %d", conn.closeCode, conn.closeReason, code)
+	}
+}
+
+func (conn *WebSocketConn) waitClosed(c *gc.C) {
+	select {
+	case <-conn.closed:
+	case <-conn.srv.clock().After(conn.srv.timeout()):
+		c.Fatalf("timed out waiting for websocket close")
+	}
+}
+
+// closePayload returns the payload of a close frame.
+func closePayload(code int, reason string) []byte {
+	if code == CloseNoStatus {
+		return nil
+	}
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	return append(payload, reason...)
+}
+
+// readLoop reads frames from the client until it closes the
+// connection, assembling them into messages.
+func (conn *WebSocketConn) readLoop() {
+	defer close(conn.closed)
+	var message []byte
+	var binaryMessage bool
+	for {
+		fin, opcode, payload, err := conn.readFrame()
+		if err != nil {
+			conn.readErr = err
+			return
+		}
+		switch opcode {
+		case wsText, wsBinary:
+			message, binaryMessage = payload, opcode == wsBinary
+		case wsContinuation:
+			message = append(message, payload...)
+		case wsPing:
+			conn.writeFrame(wsPong, payload)
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			conn.gotClose = true
+			conn.closeCode = CloseNoStatus
+			if len(payload) >= 2 {
+				conn.closeCode = int(binary.BigEndian.Uint16(payload))
+				conn.closeReason = string(payload[2:])
+			}
+			return
+		default:
+			conn.readErr = fmt.Errorf("unknown opcode %#x", opcode)
+			return
+		}
+		if fin {
+			conn.messages <- WebSocketMessage{Binary: binaryMessage, Data: message}
+			message = nil
+		}
+	}
+}
+
+// readFrame reads a single frame from the client.
+func (conn *WebSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(conn.r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(conn.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(conn.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if !masked {
+		return false, 0, nil, fmt.Errorf("client sent unmasked frame")
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(conn.r, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(conn.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame sends a single unfragmented frame to the client.
+func (conn *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	conn.writeMu.Lock()
+	defer conn.writeMu.Unlock()
+	frame := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126, byte(n>>8), byte(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+	_, err := conn.conn.Write(frame)
+	return err
+}
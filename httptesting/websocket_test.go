@@ -0,0 +1,164 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package httptesting_test
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/httptesting"
+	"github.com/juju/testing/testclock"
+)
+
+type websocketSuite struct{}
+
+var _ = gc.Suite(&websocketSuite{})
+
+func dialWebSocket(c *gc.C, srv *httptesting.WebSocketServer, path string) *websocket.Conn {
+	ws, err := websocket.Dial(srv.WSURL(path), "", srv.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	return ws
+}
+
+func (s *websocketSuite) TestExchangeMessages(c *gc.C) {
+	srv := httptesting.NewWebSocketServer()
+	defer srv.Close()
+	ws := dialWebSocket(c, srv, "/stream")
+	defer ws.Close()
+
+	conn := srv.Accept(c)
+	c.Assert(conn.Request.URL.Path, gc.Equals, "/stream")
+
+	conn.WriteText(c, "hello")
+	var text string
+	err := websocket.Message.Receive(ws, &text)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(text, gc.Equals, "hello")
+
+	conn.WriteJSON(c, map[string]int{"n": 1})
+	var v map[string]int
+	err = websocket.JSON.Receive(ws, &v)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(v, jc.DeepEquals, map[string]int{"n": 1})
+
+	err = websocket.Message.Send(ws, "reply")
+	c.Assert(err, jc.ErrorIsNil)
+	conn.AssertText(c, "reply")
+
+	err = websocket.Message.Send(ws, []byte{0, 1, 2})
+	c.Assert(err, jc.ErrorIsNil)
+	conn.AssertBinary(c, []byte{0, 1, 2})
+
+	err = websocket.JSON.Send(ws, map[string]string{"ack": "start"})
+	c.Assert(err, jc.ErrorIsNil)
+	conn.AssertJSON(c, map[string]string{"ack": "start"})
+
+	big := make([]byte, 70000)
+	for i := range big {
+		big[i] = byte(i)
+	}
+	err = websocket.Message.Send(ws, big)
+	c.Assert(err, jc.ErrorIsNil)
+	conn.AssertBinary(c, big)
+}
+
+func (s *websocketSuite) TestServerClose(c *gc.C) {
+	srv := httptesting.NewWebSocketServer()
+	defer srv.Close()
+	ws := dialWebSocket(c, srv, "/")
+	conn := srv.Accept(c)
+
+	// The x/net client sees the close frame as EOF and completes
+	// the handshake when it closes the connection.
+	go func() {
+		var text string
+		err := websocket.Message.Receive(ws, &text)
+		c.Check(err, gc.Equals, io.EOF)
+		ws.Close()
+	}()
+	conn.Close(c, httptesting.CloseNormal, "bye")
+}
+
+func (s *websocketSuite) TestServerCloseNoReply(c *gc.C) {
+	srv := httptesting.NewWebSocketServer()
+	defer srv.Close()
+	srv.Timeout = testing.ShortWait
+	ws := dialWebSocket(c, srv, "/")
+	defer ws.Close()
+	conn := srv.Accept(c)
+
+	c.ExpectFailure("client never completes the close handshake")
+	conn.Close(c, httptesting.CloseNormal, "bye")
+}
+
+func (s *websocketSuite) TestClientClose(c *gc.C) {
+	srv := httptesting.NewWebSocketServer()
+	defer srv.Close()
+	ws := dialWebSocket(c, srv, "/")
+	conn := srv.Accept(c)
+
+	err := ws.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	conn.AssertClosed(c, httptesting.CloseNormal)
+}
+
+func (s *websocketSuite) TestClientCloseWrongCode(c *gc.C) {
+	srv := httptesting.NewWebSocketServer()
+	defer srv.Close()
+	ws := dialWebSocket(c, srv, "/")
+	conn := srv.Accept(c)
+
+	err := ws.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.ExpectFailure("client closed with the wrong code")
+	conn.AssertClosed(c, httptesting.CloseGoingAway)
+}
+
+func (s *websocketSuite) TestReadAfterClientClose(c *gc.C) {
+	srv := httptesting.NewWebSocketServer()
+	defer srv.Close()
+	ws := dialWebSocket(c, srv, "/")
+	conn := srv.Accept(c)
+
+	err := websocket.Message.Send(ws, "last")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ws.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	conn.AssertText(c, "last")
+	c.ExpectFailure("client closed while waiting for a message")
+	conn.ReadMessage(c)
+}
+
+func (s *websocketSuite) TestReadTimeout(c *gc.C) {
+	clock := testclock.NewClock(time.Now())
+	srv := httptesting.NewWebSocketServer()
+	defer srv.Close()
+	srv.Clock = clock
+	srv.Timeout = time.Minute
+	ws := dialWebSocket(c, srv, "/")
+	defer ws.Close()
+
+	conn := srv.Accept(c)
+	// Accept has left a timer behind; the other is ReadMessage's.
+	go func() {
+		clock.WaitAdvance(c, time.Minute, time.Second, 2)
+	}()
+	c.ExpectFailure("no message arrives before the fake clock passes the timeout")
+	conn.ReadMessage(c)
+}
+
+func (s *websocketSuite) TestNotWebSocket(c *gc.C) {
+	srv := httptesting.NewWebSocketServer()
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusBadRequest)
+}
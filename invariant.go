@@ -0,0 +1,96 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// InvariantSuite may be embedded in a suite to check invariants, such
+// as "no orphaned rows" or "state machine in a valid state", after
+// every test in the suite. The first test that breaks an invariant then
+// fails, rather than a later test that trips over the broken state.
+// Invariants are usually added in SetUpSuite:
+//
+//	type storeSuite struct {
+//		testing.InvariantSuite
+//		store *Store
+//	}
+//
+//	func (s *storeSuite) SetUpSuite(c *gc.C) {
+//		s.InvariantSuite.SetUpSuite(c)
+//		s.AddInvariant("no orphaned rows", func(c *gc.C) {
+//			c.Assert(s.store.OrphanedRows(), gc.HasLen, 0)
+//		})
+//	}
+//
+// Suites that define their own fixture methods must call those of
+// InvariantSuite, and should call TearDownTest after tearing down
+// anything else, so that the invariants are checked once the test has
+// been cleaned up.
+type InvariantSuite struct {
+	invariants []*invariant
+}
+
+// invariant holds an invariant added with AddInvariant.
+type invariant struct {
+	name  string
+	check func(c *gc.C)
+}
+
+func (s *InvariantSuite) SetUpSuite(c *gc.C) {
+	s.invariants = nil
+}
+
+func (s *InvariantSuite) TearDownSuite(c *gc.C) {
+	s.invariants = nil
+}
+
+func (s *InvariantSuite) SetUpTest(c *gc.C) {}
+
+// TearDownTest checks the suite's invariants, failing the test if any
+// of them does not hold.
+func (s *InvariantSuite) TearDownTest(c *gc.C) {
+	s.CheckInvariants(c)
+}
+
+// AddInvariant adds an invariant, which is checked after every
+// following test in the suite, until the suite is torn down. The check
+// function should fail the *gc.C it is given if the invariant does not
+// hold; it must not use the test's own *gc.C.
+func (s *InvariantSuite) AddInvariant(name string, check func(c *gc.C)) {
+	s.invariants = append(s.invariants, &invariant{name: name, check: check})
+}
+
+// CheckInvariants checks the suite's invariants, failing the test if
+// any of them does not hold, and returns whether they all held. It is
+// called by TearDownTest, where a failure names the test that broke
+// the invariant and, as gocheck treats it as a failure of the fixture,
+// stops the remaining tests in the suite from running, as they would
+// start from the broken state. It may also be called within a test to
+// check the invariants part way through.
+func (s *InvariantSuite) CheckInvariants(c *gc.C) bool {
+	ok := true
+	for _, inv := range s.invariants {
+		var output bytes.Buffer
+		result := gc.Run(&invariantSuite{inv.check}, &gc.RunConf{Output: &output})
+		if !result.Passed() {
+			ok = false
+			c.Errorf("invariant %q broken by %s:\n%s", inv.name, c.TestName(), indent(strings.TrimSpace(output.String())))
+		}
+	}
+	return ok
+}
+
+// invariantSuite checks a single invariant.
+type invariantSuite struct {
+	check func(c *gc.C)
+}
+
+func (s *invariantSuite) TestInvariant(c *gc.C) {
+	s.check(c)
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type invariantSuite struct{}
+
+var _ = gc.Suite(&invariantSuite{})
+
+// counterSuite is run by the tests of invariantSuite rather than being
+// registered with gocheck. Its tests run in alphabetical order.
+type counterSuite struct {
+	testing.InvariantSuite
+	counter int
+	checked []string
+}
+
+func (s *counterSuite) SetUpSuite(c *gc.C) {
+	s.InvariantSuite.SetUpSuite(c)
+	s.AddInvariant("counter is not negative", func(c *gc.C) {
+		c.Assert(s.counter >= 0, jc.IsTrue, gc.Commentf("counter is %d", s.counter))
+	})
+	s.AddInvariant("counter is below ten", func(c *gc.C) {
+		c.Assert(s.counter < 10, jc.IsTrue, gc.Commentf("counter is %d", s.counter))
+	})
+}
+
+func (s *counterSuite) TearDownTest(c *gc.C) {
+	s.checked = append(s.checked, c.TestName())
+	s.InvariantSuite.TearDownTest(c)
+}
+
+func (s *counterSuite) TestA(c *gc.C) {
+	s.counter = 5
+}
+
+func (s *counterSuite) TestB(c *gc.C) {
+	s.counter = 1
+	c.Check(s.CheckInvariants(c), jc.IsTrue)
+	s.counter = 20
+	c.Check(s.CheckInvariants(c), jc.IsFalse)
+	s.counter = -1
+}
+
+func (s *counterSuite) TestC(c *gc.C) {}
+
+func (*invariantSuite) TestInvariantsChecked(c *gc.C) {
+	var output bytes.Buffer
+	suite := &counterSuite{}
+	result := gc.Run(suite, &gc.RunConf{Output: &output})
+	c.Assert(result.Passed(), jc.IsFalse)
+	// The broken invariant stops TestC from running.
+	c.Assert(suite.checked, jc.DeepEquals, []string{
+		"counterSuite.TestA",
+		"counterSuite.TestB",
+	})
+	out := output.String()
+	c.Check(out, gc.Not(gc.Matches), `(?s).*counterSuite\.TestA.*`)
+	c.Check(out, gc.Matches, `(?s).*FAIL: .*counterSuite\.TestB\n.*`+
+		`invariant "counter is below ten" broken by counterSuite\.TestB:\n`+
+		`.*counter is 20\n.*`)
+	c.Check(out, gc.Matches, `(?s).*FAIL: .*counterSuite\.TearDownTest\n.*`+
+		`invariant "counter is not negative" broken by counterSuite\.TestB:\n`+
+		`.*counter is -1\n.*`)
+	c.Check(strings.Count(out, `invariant "counter is below ten"`), gc.Equals, 1)
+}
+
+func (*invariantSuite) TestInvariantsClearedAtTearDownSuite(c *gc.C) {
+	suite := &counterSuite{}
+	gc.Run(suite, &gc.RunConf{Output: &bytes.Buffer{}})
+	c.Assert(suite.CheckInvariants(c), jc.IsTrue)
+}
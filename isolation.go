@@ -4,15 +4,44 @@
 package testing
 
 import (
+	"os"
+	"strings"
+
 	gc "gopkg.in/check.v1"
 )
 
 // IsolationSuite isolates the tests from the underlaying system environment,
 // sets up test logging and exposes cleanup facilities.
+//
+// The environment variables, working directory, umask and any values
+// patched with PatchValue are recorded at the start of each test. Once
+// the test's cleanup functions have run, the environment is restored,
+// and if the working directory or umask have changed, or if a patched
+// value has not been restored, the test fails and the original state
+// is put back so that it cannot leak into the next test.
+//
+// When tests are run in random order (see RunSuites), each test logs
+// the name of the test that ran before it, and the tests that leak
+// state log how to run them again in the same order.
 type IsolationSuite struct {
 	OsEnvSuite
 	CleanupSuite
 	LoggingSuite
+
+	snapshot *isolationSnapshot
+}
+
+// lastIsolatedTest holds the name of the last test run by an
+// IsolationSuite.
+var lastIsolatedTest string
+
+// isolationSnapshot holds the process-wide state recorded by
+// IsolationSuite at the start of a test.
+type isolationSnapshot struct {
+	environ map[string]string
+	wd      string
+	umask   int
+	patches map[int]bool
 }
 
 func (s *IsolationSuite) SetUpSuite(c *gc.C) {
@@ -31,10 +60,84 @@ func (s *IsolationSuite) SetUpTest(c *gc.C) {
 	s.OsEnvSuite.SetUpTest(c)
 	s.CleanupSuite.SetUpTest(c)
 	s.LoggingSuite.SetUpTest(c)
+	s.snapshot = takeIsolationSnapshot(c)
+	if _, shuffle, _ := shuffleSeed(); shuffle {
+		if lastIsolatedTest != "" {
+			c.Logf("tests are shuffled; previous test: %s", lastIsolatedTest)
+		} else {
+			c.Logf("tests are shuffled; this is the first test")
+		}
+	}
 }
 
 func (s *IsolationSuite) TearDownTest(c *gc.C) {
 	s.LoggingSuite.TearDownTest(c)
 	s.CleanupSuite.TearDownTest(c)
+	s.checkLeaks(c)
 	s.OsEnvSuite.TearDownTest(c)
+	lastIsolatedTest = c.TestName()
+}
+
+func takeIsolationSnapshot(c *gc.C) *isolationSnapshot {
+	wd, err := os.Getwd()
+	c.Assert(err, gc.IsNil)
+	return &isolationSnapshot{
+		environ: environMap(),
+		wd:      wd,
+		umask:   currentUmask(),
+		patches: activePatches.ids(),
+	}
+}
+
+// checkLeaks compares the current process state with the snapshot
+// taken in SetUpTest, failing the test for every difference that would
+// otherwise leak into later tests, and restores the recorded state.
+func (s *IsolationSuite) checkLeaks(c *gc.C) {
+	snapshot := s.snapshot
+	if snapshot == nil {
+		// SetUpTest did not complete.
+		return
+	}
+	s.snapshot = nil
+	leaked := false
+
+	for _, patch := range activePatches.since(snapshot.patches) {
+		leaked = true
+		c.Errorf("test leaked patch: %s", patch.description)
+		patch.restore()
+	}
+	if wd, err := os.Getwd(); err != nil || wd != snapshot.wd {
+		leaked = true
+		c.Errorf("test leaked working directory change: %q, want %q", wd, snapshot.wd)
+		if err := os.Chdir(snapshot.wd); err != nil {
+			c.Errorf("cannot restore working directory: %v", err)
+		}
+	}
+	if umask := currentUmask(); umask != snapshot.umask {
+		leaked = true
+		c.Errorf("test leaked umask change: %#o, want %#o", umask, snapshot.umask)
+		setUmask(snapshot.umask)
+	}
+	restoreEnviron(snapshot.environ)
+	if seed, shuffle, _ := shuffleSeed(); shuffle && leaked {
+		c.Logf("tests are shuffled; run with -shuffle.order=%d to run them in the same order", seed)
+	}
+}
+
+// environMap returns the current environment as a map.
+func environMap() map[string]string {
+	environ := make(map[string]string)
+	for _, envvar := range os.Environ() {
+		parts := strings.SplitN(envvar, "=", 2)
+		environ[parts[0]] = parts[1]
+	}
+	return environ
+}
+
+// restoreEnviron sets the environment to exactly the given variables.
+func restoreEnviron(environ map[string]string) {
+	os.Clearenv()
+	for name, value := range environ {
+		os.Setenv(name, value)
+	}
 }
@@ -0,0 +1,79 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"os"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type isolationSuite struct {
+	suite testing.IsolationSuite
+}
+
+var _ = gc.Suite(&isolationSuite{})
+
+func (s *isolationSuite) SetUpTest(c *gc.C) {
+	s.suite = testing.IsolationSuite{}
+	s.suite.SetUpSuite(c)
+	s.suite.SetUpTest(c)
+}
+
+func (s *isolationSuite) TearDownTest(c *gc.C) {
+	s.suite.TearDownSuite(c)
+}
+
+func (s *isolationSuite) TestNoLeaks(c *gc.C) {
+	i := 1
+	s.suite.PatchValue(&i, 2)
+	s.suite.PatchEnvironment("TESTING_ISOLATION", "value")
+	s.suite.TearDownTest(c)
+	c.Assert(i, gc.Equals, 1)
+}
+
+func (s *isolationSuite) TestEnvironmentRestored(c *gc.C) {
+	err := os.Setenv("TESTING_ISOLATION", "value")
+	c.Assert(err, jc.ErrorIsNil)
+	s.suite.TearDownTest(c)
+	_, ok := os.LookupEnv("TESTING_ISOLATION")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *isolationSuite) TestLeakedPatch(c *gc.C) {
+	i := 1
+	testing.PatchValue(&i, 2)
+	defer func() {
+		c.Assert(i, gc.Equals, 1)
+	}()
+	c.ExpectFailure("patch was not restored")
+	s.suite.TearDownTest(c)
+}
+
+func (s *isolationSuite) TestPatchBeforeTestNotLeaked(c *gc.C) {
+	s.suite.TearDownTest(c)
+	i := 1
+	restore := testing.PatchValue(&i, 2)
+	defer restore()
+	s.suite.SetUpTest(c)
+	s.suite.TearDownTest(c)
+	c.Assert(i, gc.Equals, 2)
+}
+
+func (s *isolationSuite) TestLeakedWorkingDirectory(c *gc.C) {
+	wd, err := os.Getwd()
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Chdir(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		newWd, err := os.Getwd()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(newWd, gc.Equals, wd)
+	}()
+	c.ExpectFailure("working directory was not restored")
+	s.suite.TearDownTest(c)
+}
@@ -0,0 +1,23 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !windows
+
+package testing_test
+
+import (
+	"syscall"
+
+	gc "gopkg.in/check.v1"
+)
+
+func (s *isolationSuite) TestLeakedUmask(c *gc.C) {
+	old := syscall.Umask(0)
+	syscall.Umask(old ^ 0077)
+	defer func() {
+		mask := syscall.Umask(old)
+		c.Assert(mask, gc.Equals, old)
+	}()
+	c.ExpectFailure("umask was not restored")
+	s.suite.TearDownTest(c)
+}
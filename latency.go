@@ -0,0 +1,130 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// Percentile is a bound on a percentile of the latency of an operation,
+// checked by CheckLatency.
+type Percentile struct {
+	// P holds the percentile, such as 95 for the 95th percentile.
+	P float64
+
+	// Max holds the duration within which that percentile of the
+	// operations must finish.
+	Max time.Duration
+}
+
+// P50 returns a bound of max on the median latency.
+func P50(max time.Duration) Percentile {
+	return Percentile{P: 50, Max: max}
+}
+
+// P95 returns a bound of max on the 95th percentile latency.
+func P95(max time.Duration) Percentile {
+	return Percentile{P: 95, Max: max}
+}
+
+// P99 returns a bound of max on the 99th percentile latency.
+func P99(max time.Duration) Percentile {
+	return Percentile{P: 99, Max: max}
+}
+
+// latencyReportPercentiles holds the percentiles of the distribution of
+// latencies reported when CheckLatency fails.
+var latencyReportPercentiles = []float64{0, 50, 90, 95, 99, 100}
+
+// CheckLatency runs op the given number of times, timing each run with
+// clk, and checks that each of the given percentiles of the latencies
+// is within its bound, reporting the distribution of the latencies if
+// not. It returns whether the check succeeded. A bound on a percentile
+// over many runs is much less likely to fail because of a slow CI
+// machine than a bound on a single run:
+//
+//	testing.CheckLatency(c, nil, 200, func() {
+//		client.Get(key)
+//	}, testing.P50(5*time.Millisecond), testing.P95(20*time.Millisecond))
+//
+// If clk is nil, clock.WallClock is used. With a testclock.Clock, the
+// latency is the time by which op, or the code under test, advances the
+// clock.
+func CheckLatency(c *gc.C, clk clock.Clock, runs int, op func(), bounds ...Percentile) bool {
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	if runs < 1 {
+		c.Errorf("cannot check latency with %d runs", runs)
+		return false
+	}
+	latencies := make([]time.Duration, runs)
+	for i := range latencies {
+		start := clk.Now()
+		op()
+		latencies[i] = clk.Now().Sub(start)
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	ok := true
+	var measured []string
+	for _, bound := range bounds {
+		latency := percentile(latencies, bound.P)
+		measured = append(measured, fmt.Sprintf("%s %v (bound %v)", percentileName(bound.P), latency, bound.Max))
+		if latency > bound.Max {
+			ok = false
+			c.Errorf("%s latency %v over its bound of %v", percentileName(bound.P), latency, bound.Max)
+		}
+	}
+	if ok {
+		c.Logf("latency over %d runs: %s", runs, strings.Join(measured, ", "))
+		return true
+	}
+	var distribution strings.Builder
+	for _, p := range latencyReportPercentiles {
+		fmt.Fprintf(&distribution, "\n  %s %v", percentileName(p), percentile(latencies, p))
+	}
+	c.Logf("latency distribution over %d runs:%s", runs, distribution.String())
+	return false
+}
+
+// AssertLatency is like CheckLatency, but stops the test if any
+// percentile of the latencies is over its bound.
+func AssertLatency(c *gc.C, clk clock.Clock, runs int, op func(), bounds ...Percentile) {
+	if !CheckLatency(c, clk, runs, op, bounds...) {
+		c.FailNow()
+	}
+}
+
+// percentile returns the pth percentile of the sorted latencies, by
+// the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// percentileName returns the name of the pth percentile, such as "p95".
+func percentileName(p float64) string {
+	switch p {
+	case 0:
+		return "min"
+	case 100:
+		return "max"
+	}
+	return fmt.Sprintf("p%v", p)
+}
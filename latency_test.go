@@ -0,0 +1,85 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+)
+
+type latencySuite struct{}
+
+var _ = gc.Suite(&latencySuite{})
+
+// steppingOp returns a clock and an operation that advances it by 1ms
+// more on each run, so that the latencies of 100 runs are 1ms to 100ms.
+func steppingOp() (*testclock.Clock, func()) {
+	clk := testclock.NewClock(time.Time{})
+	n := 0
+	return clk, func() {
+		n++
+		clk.Advance(time.Duration(n) * time.Millisecond)
+	}
+}
+
+func (*latencySuite) TestWithinBounds(c *gc.C) {
+	clk, op := steppingOp()
+	ok := testing.CheckLatency(c, clk, 100, op, testing.P50(50*time.Millisecond), testing.P95(95*time.Millisecond))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(c.GetTestLog(), gc.Equals, "latency over 100 runs: p50 50ms (bound 50ms), p95 95ms (bound 95ms)\n")
+}
+
+func (*latencySuite) TestWallClock(c *gc.C) {
+	runs := 0
+	testing.AssertLatency(c, nil, 10, func() {
+		runs++
+	}, testing.P99(time.Second))
+	c.Assert(runs, gc.Equals, 10)
+}
+
+func (*latencySuite) TestCustomPercentile(c *gc.C) {
+	clk, op := steppingOp()
+	ok := testing.CheckLatency(c, clk, 100, op, testing.Percentile{P: 99.9, Max: 100 * time.Millisecond})
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(c.GetTestLog(), gc.Equals, "latency over 100 runs: p99.9 100ms (bound 100ms)\n")
+}
+
+// slowLatencySuite is run by TestOverBound rather than being registered
+// with gocheck.
+type slowLatencySuite struct{}
+
+func (*slowLatencySuite) TestLatency(c *gc.C) {
+	clk, op := steppingOp()
+	testing.AssertLatency(c, clk, 100, op, testing.P50(60*time.Millisecond), testing.P95(90*time.Millisecond), testing.P99(98*time.Millisecond))
+	c.Fatalf("AssertLatency did not stop the test")
+}
+
+func (*slowLatencySuite) TestNoRuns(c *gc.C) {
+	testing.CheckLatency(c, nil, 0, func() {}, testing.P50(time.Second))
+}
+
+func (*latencySuite) TestOverBound(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&slowLatencySuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 2)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`\.\.\. Error: p95 latency 95ms over its bound of 90ms\n.*`+
+		`\.\.\. Error: p99 latency 99ms over its bound of 98ms\n.*`+
+		`latency distribution over 100 runs:\n`+
+		`  min 1ms\n`+
+		`  p50 50ms\n`+
+		`  p90 90ms\n`+
+		`  p95 95ms\n`+
+		`  p99 99ms\n`+
+		`  max 100ms\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*p50 latency.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*did not stop the test.*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*cannot check latency with 0 runs\n.*`)
+}
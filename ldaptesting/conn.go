@@ -0,0 +1,335 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package ldaptesting
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+// Protocol operation tags, from RFC 4511.
+const (
+	opBindRequest      = 0
+	opBindResponse     = 1
+	opUnbindRequest    = 2
+	opSearchRequest    = 3
+	opSearchResultItem = 4
+	opSearchResultDone = 5
+	opModifyRequest    = 6
+	opAddRequest       = 8
+	opDelRequest       = 10
+	opModDNRequest     = 12
+	opCompareRequest   = 14
+	opAbandonRequest   = 16
+	opExtendedRequest  = 23
+	opExtendedResponse = 24
+)
+
+// Result codes.
+const (
+	resultSuccess                  = 0
+	resultProtocolError            = 2
+	resultSizeLimitExceeded        = 4
+	resultAuthMethodNotSupported   = 7
+	resultNoSuchObject             = 32
+	resultInvalidCredentials       = 49
+	resultInsufficientAccessRights = 50
+	resultUnwillingToPerform       = 53
+)
+
+// Search scopes.
+const (
+	scopeBaseObject  = 0
+	scopeSingleLevel = 1
+)
+
+// Extended operation OIDs.
+const (
+	oidStartTLS = "1.3.6.1.4.1.1466.20037"
+	oidWhoAmI   = "1.3.6.1.4.1.4203.1.11.3"
+)
+
+// Filter tags.
+const (
+	filterAnd            = 0
+	filterOr             = 1
+	filterNot            = 2
+	filterEqualityMatch  = 3
+	filterSubstrings     = 4
+	filterGreaterOrEqual = 5
+	filterLessOrEqual    = 6
+	filterPresent        = 7
+	filterApproxMatch    = 8
+)
+
+// serverConn serves a single client connection.
+type serverConn struct {
+	srv  *Server
+	conn net.Conn
+	// boundDN holds the DN with which the client has bound, if any.
+	boundDN string
+}
+
+func (sc *serverConn) serve() {
+	for {
+		packet, err := ber.ReadPacket(sc.conn)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) < 2 {
+			return
+		}
+		id, err := ber.ParseInt64(packet.Children[0].Data.Bytes())
+		if err != nil {
+			return
+		}
+		op := packet.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			return
+		}
+		switch op.Tag {
+		case opBindRequest:
+			sc.bind(id, op)
+		case opUnbindRequest:
+			return
+		case opSearchRequest:
+			sc.search(id, op)
+		case opExtendedRequest:
+			if !sc.extended(id, op) {
+				return
+			}
+		case opAbandonRequest:
+			// Requests are served synchronously, so there is
+			// never anything to abandon.
+		case opModifyRequest, opAddRequest, opDelRequest, opModDNRequest, opCompareRequest:
+			sc.writeResult(id, op.Tag+1, resultUnwillingToPerform, "the directory is read-only")
+		default:
+			return
+		}
+	}
+}
+
+func (sc *serverConn) bind(id int64, op *ber.Packet) {
+	if len(op.Children) < 3 {
+		sc.writeResult(id, opBindResponse, resultProtocolError, "malformed bind request")
+		return
+	}
+	dn := op.Children[1].Data.String()
+	auth := op.Children[2]
+	if auth.ClassType != ber.ClassContext || auth.Tag != 0 {
+		sc.writeResult(id, opBindResponse, resultAuthMethodNotSupported, "only simple binds are supported")
+		return
+	}
+	password := auth.Data.String()
+	sc.boundDN = ""
+	if dn == "" && password == "" {
+		sc.srv.recordBind("", true)
+		sc.writeResult(id, opBindResponse, resultSuccess, "")
+		return
+	}
+	ok := sc.srv.checkPassword(dn, password)
+	sc.srv.recordBind(dn, ok)
+	if !ok {
+		sc.writeResult(id, opBindResponse, resultInvalidCredentials, "")
+		return
+	}
+	sc.boundDN = dn
+	sc.writeResult(id, opBindResponse, resultSuccess, "")
+}
+
+func (sc *serverConn) search(id int64, op *ber.Packet) {
+	if len(op.Children) < 8 {
+		sc.writeResult(id, opSearchResultDone, resultProtocolError, "malformed search request")
+		return
+	}
+	if sc.srv.DenyAnonymous && sc.boundDN == "" {
+		sc.writeResult(id, opSearchResultDone, resultInsufficientAccessRights, "anonymous searches are not allowed")
+		return
+	}
+	base := op.Children[0].Data.String()
+	scope, _ := ber.ParseInt64(op.Children[1].Data.Bytes())
+	sizeLimit, _ := ber.ParseInt64(op.Children[3].Data.Bytes())
+	typesOnly := len(op.Children[5].Data.Bytes()) > 0 && op.Children[5].Data.Bytes()[0] != 0
+	filter := op.Children[6]
+	var attrs []string
+	for _, a := range op.Children[7].Children {
+		attrs = append(attrs, a.Data.String())
+	}
+	entries, ok := sc.srv.search(base, scope, filter)
+	if !ok {
+		sc.writeResult(id, opSearchResultDone, resultNoSuchObject, "")
+		return
+	}
+	for i, e := range entries {
+		if sizeLimit > 0 && int64(i) == sizeLimit {
+			sc.writeResult(id, opSearchResultDone, resultSizeLimitExceeded, "")
+			return
+		}
+		sc.write(id, searchResultEntry(e, attrs, typesOnly))
+	}
+	sc.writeResult(id, opSearchResultDone, resultSuccess, "")
+}
+
+// extended serves an extended request, and reports whether the
+// connection should continue to be served.
+func (sc *serverConn) extended(id int64, op *ber.Packet) bool {
+	if len(op.Children) < 1 {
+		sc.writeResult(id, opExtendedResponse, resultProtocolError, "malformed extended request")
+		return true
+	}
+	switch op.Children[0].Data.String() {
+	case oidStartTLS:
+		if _, ok := sc.conn.(*tls.Conn); ok {
+			sc.writeResult(id, opExtendedResponse, resultProtocolError, "TLS is already in use")
+			return true
+		}
+		sc.writeResult(id, opExtendedResponse, resultSuccess, "")
+		tlsConn := tls.Server(sc.conn, sc.srv.tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return false
+		}
+		sc.conn = tlsConn
+	case oidWhoAmI:
+		resp := result(opExtendedResponse, resultSuccess, "")
+		authzID := ""
+		if sc.boundDN != "" {
+			authzID = "dn:" + sc.boundDN
+		}
+		resp.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 11, authzID, "responseValue"))
+		sc.write(id, resp)
+	default:
+		sc.writeResult(id, opExtendedResponse, resultProtocolError, "unsupported extended operation")
+	}
+	return true
+}
+
+// searchResultEntry returns a SearchResultEntry holding the requested
+// attributes of e. If no attributes are requested, or "*" is, all
+// attributes are returned; "1.1" requests none.
+func searchResultEntry(e Entry, requested []string, typesOnly bool) *ber.Packet {
+	all := len(requested) == 0
+	for _, name := range requested {
+		if name == "*" {
+			all = true
+		}
+	}
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, opSearchResultItem, nil, "SearchResultEntry")
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, "objectName"))
+	attrs := ber.NewSequence("attributes")
+	for _, name := range e.attributeNames() {
+		if !all && !containsFold(requested, name) {
+			continue
+		}
+		attr := ber.NewSequence("attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		if !typesOnly {
+			for _, v := range e.Attributes[name] {
+				vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+			}
+		}
+		attr.AppendChild(vals)
+		attrs.AppendChild(attr)
+	}
+	p.AppendChild(attrs)
+	return p
+}
+
+// matchFilter reports whether the entry matches the filter.
+func matchFilter(f *ber.Packet, e Entry) bool {
+	switch f.Tag {
+	case filterAnd:
+		for _, child := range f.Children {
+			if !matchFilter(child, e) {
+				return false
+			}
+		}
+		return true
+	case filterOr:
+		for _, child := range f.Children {
+			if matchFilter(child, e) {
+				return true
+			}
+		}
+		return false
+	case filterNot:
+		return len(f.Children) == 1 && !matchFilter(f.Children[0], e)
+	case filterPresent:
+		name := f.Data.String()
+		return strings.EqualFold(name, "objectClass") || len(e.Get(name)) > 0
+	case filterEqualityMatch, filterApproxMatch, filterGreaterOrEqual, filterLessOrEqual:
+		if len(f.Children) != 2 {
+			return false
+		}
+		assertion := f.Children[1].Data.String()
+		for _, v := range e.Get(f.Children[0].Data.String()) {
+			cmp := compareValues(v, assertion)
+			switch {
+			case f.Tag == filterGreaterOrEqual && cmp >= 0,
+				f.Tag == filterLessOrEqual && cmp <= 0,
+				(f.Tag == filterEqualityMatch || f.Tag == filterApproxMatch) && cmp == 0:
+				return true
+			}
+		}
+		return false
+	case filterSubstrings:
+		if len(f.Children) != 2 {
+			return false
+		}
+		var initial, final string
+		var any []string
+		for _, s := range f.Children[1].Children {
+			switch s.Tag {
+			case 0:
+				initial = s.Data.String()
+			case 1:
+				any = append(any, s.Data.String())
+			case 2:
+				final = s.Data.String()
+			}
+		}
+		for _, v := range e.Get(f.Children[0].Data.String()) {
+			if matchSubstrings(v, initial, any, final) {
+				return true
+			}
+		}
+		return false
+	}
+	// Extensible matches are not supported.
+	return false
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// result returns an LDAPResult with the given protocol operation tag.
+func result(tag ber.Tag, code int64, message string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "LDAPResult")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "resultCode"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "diagnosticMessage"))
+	return p
+}
+
+func (sc *serverConn) writeResult(id int64, tag ber.Tag, code int64, message string) {
+	sc.write(id, result(tag, code, message))
+}
+
+// write writes an LDAPMessage holding the given protocol operation.
+// Write errors are ignored; the next read fails too.
+func (sc *serverConn) write(id int64, op *ber.Packet) {
+	msg := ber.NewSequence("LDAPMessage")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "messageID"))
+	msg.AppendChild(op)
+	sc.conn.Write(msg.Bytes())
+}
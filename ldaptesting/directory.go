@@ -0,0 +1,193 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package ldaptesting
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Entry holds a directory entry.
+type Entry struct {
+	// DN holds the entry's distinguished name, such as
+	// "uid=alice,ou=people,dc=example,dc=com".
+	DN string
+
+	// Attributes maps attribute names to their values. Attribute
+	// names are matched case-insensitively.
+	Attributes map[string][]string
+}
+
+// Get returns the values of the named attribute, matching the name
+// case-insensitively.
+func (e Entry) Get(name string) []string {
+	for n, values := range e.Attributes {
+		if strings.EqualFold(n, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// attributeNames returns the names of the entry's attributes, in
+// order.
+func (e Entry) attributeNames() []string {
+	names := make([]string, 0, len(e.Attributes))
+	for name := range e.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func copyEntry(e Entry) Entry {
+	attrs := make(map[string][]string, len(e.Attributes))
+	for name, values := range e.Attributes {
+		attrs[name] = append([]string(nil), values...)
+	}
+	return Entry{DN: e.DN, Attributes: attrs}
+}
+
+// normalizeDN returns a canonical form of the DN for comparisons: in
+// lower case, without spaces around the separators.
+func normalizeDN(dn string) string {
+	rdns := splitDN(dn)
+	for i, rdn := range rdns {
+		if eq := strings.Index(rdn, "="); eq >= 0 {
+			rdn = strings.TrimSpace(rdn[:eq]) + "=" + strings.TrimSpace(rdn[eq+1:])
+		}
+		rdns[i] = strings.ToLower(strings.TrimSpace(rdn))
+	}
+	return strings.Join(rdns, ",")
+}
+
+// splitDN splits the DN into its RDNs, respecting escaped commas.
+func splitDN(dn string) []string {
+	if strings.TrimSpace(dn) == "" {
+		return nil
+	}
+	var rdns []string
+	start := 0
+	for i := 0; i < len(dn); i++ {
+		switch dn[i] {
+		case '\\':
+			i++
+		case ',':
+			rdns = append(rdns, dn[start:i])
+			start = i + 1
+		}
+	}
+	return append(rdns, dn[start:])
+}
+
+// inScope reports whether the entry with normalized DN dn is within the
+// search scope of the normalized base DN.
+func inScope(dn, base string, scope int64) bool {
+	switch scope {
+	case scopeBaseObject:
+		return dn == base
+	case scopeSingleLevel:
+		if base == "" {
+			return len(splitDN(dn)) == 1
+		}
+		return strings.HasSuffix(dn, ","+base) && len(splitDN(dn)) == len(splitDN(base))+1
+	default:
+		return base == "" || dn == base || strings.HasSuffix(dn, ","+base)
+	}
+}
+
+// compareValues compares two attribute values, numerically if both are
+// integers and case-insensitively otherwise.
+func compareValues(a, b string) int {
+	if x, err := strconv.ParseInt(a, 10, 64); err == nil {
+		if y, err := strconv.ParseInt(b, 10, 64); err == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// matchSubstrings reports whether value matches the substrings
+// assertion, ignoring case.
+func matchSubstrings(value, initial string, any []string, final string) bool {
+	value = strings.ToLower(value)
+	if !strings.HasPrefix(value, strings.ToLower(initial)) {
+		return false
+	}
+	value = value[len(initial):]
+	for _, s := range any {
+		i := strings.Index(value, strings.ToLower(s))
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(s):]
+	}
+	return strings.HasSuffix(value, strings.ToLower(final))
+}
+
+// parseLDIF parses entries in LDAP Data Interchange Format. Only
+// content records are supported, not change records.
+func parseLDIF(ldif string) ([]Entry, error) {
+	// Unfold continuation lines, which start with a space.
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(ldif))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, " ") && len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	var entries []Entry
+	var entry *Entry
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.TrimSpace(line) == "":
+			entry = nil
+			continue
+		case strings.HasPrefix(line, "version:") && entry == nil:
+			continue
+		}
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: missing colon in %q", i+1, line)
+		}
+		name, value := line[:colon], line[colon+1:]
+		if strings.HasPrefix(value, ":") {
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid base64 value: %v", i+1, err)
+			}
+			value = string(data)
+		} else {
+			value = strings.TrimSpace(value)
+		}
+		if entry == nil {
+			if !strings.EqualFold(name, "dn") {
+				return nil, fmt.Errorf("line %d: entry does not start with dn", i+1)
+			}
+			entries = append(entries, Entry{DN: value, Attributes: make(map[string][]string)})
+			entry = &entries[len(entries)-1]
+			continue
+		}
+		if strings.EqualFold(name, "changetype") {
+			return nil, fmt.Errorf("line %d: change records are not supported", i+1)
+		}
+		entry.Attributes[name] = append(entry.Attributes[name], value)
+	}
+	return entries, nil
+}
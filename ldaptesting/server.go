@@ -0,0 +1,293 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package ldaptesting provides a small in-process LDAP server, so that
+// code that authenticates or authorizes users against a directory can
+// be tested hermetically.
+package ldaptesting
+
+import (
+	"crypto/tls"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/tlstesting"
+)
+
+// Bind records an attempt to bind to a Server.
+type Bind struct {
+	// DN holds the DN with which the client tried to bind. It is
+	// empty for anonymous binds.
+	DN string
+
+	// OK reports whether the bind succeeded.
+	OK bool
+}
+
+// Server is an in-process LDAP server listening on a loopback port. It
+// supports simple binds, searches, the StartTLS and "Who am I?"
+// extended operations, and TLS:
+//
+//	srv := ldaptesting.NewServer(c)
+//	defer srv.Close()
+//	srv.LoadLDIF(c, `
+//	dn: uid=alice,ou=people,dc=example,dc=com
+//	objectClass: inetOrgPerson
+//	uid: alice
+//	userPassword: secret
+//	`)
+//	... configure the code under test with srv.URL() ...
+//
+// A simple bind succeeds if the entry with the given DN has a
+// userPassword attribute holding the password, in plain text.
+// Anonymous binds always succeed. The directory cannot be modified
+// over LDAP; requests to do so fail with unwillingToPerform.
+//
+// All attribute values are compared case-insensitively, and values
+// that are both integers are ordered numerically. Every entry is
+// treated as having an objectClass attribute, so that the common
+// filter (objectClass=*) matches every entry.
+type Server struct {
+	// CA holds the certificate authority that issued the server's
+	// certificate, which is valid for 127.0.0.1 and localhost.
+	CA *tlstesting.CA
+
+	// DenyAnonymous causes searches to fail with
+	// insufficientAccessRights unless the client has bound with a
+	// DN.
+	DenyAnonymous bool
+
+	tlsConfig *tls.Config
+	listener  net.Listener
+	useTLS    bool
+	wg        sync.WaitGroup
+
+	mu      sync.Mutex
+	entries map[string]Entry
+	binds   []Bind
+	conns   map[net.Conn]bool
+	closed  bool
+}
+
+// NewServer starts and returns a new Server with an empty directory,
+// serving plain LDAP. Clients may use StartTLS to switch to TLS. The
+// caller should call Close when finished with it.
+func NewServer(c *gc.C) *Server {
+	return newServer(c, false)
+}
+
+// NewTLSServer is like NewServer, but the server serves LDAP over TLS
+// (ldaps).
+func NewTLSServer(c *gc.C) *Server {
+	return newServer(c, true)
+}
+
+func newServer(c *gc.C, useTLS bool) *Server {
+	ca := tlstesting.NewCA(c, "ldaptesting CA")
+	srv := &Server{
+		CA:        ca,
+		tlsConfig: tlstesting.ServerConfig(ca.NewServerCert(c, "127.0.0.1", "localhost")),
+		useTLS:    useTLS,
+		entries:   make(map[string]Entry),
+		conns:     make(map[net.Conn]bool),
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, gc.IsNil)
+	if useTLS {
+		listener = tls.NewListener(listener, srv.tlsConfig)
+	}
+	srv.listener = listener
+	srv.wg.Add(1)
+	go srv.serve()
+	return srv
+}
+
+// Addr returns the address on which the server listens.
+func (srv *Server) Addr() string {
+	return srv.listener.Addr().String()
+}
+
+// URL returns the server's URL, such as ldap://127.0.0.1:1234.
+func (srv *Server) URL() string {
+	if srv.useTLS {
+		return "ldaps://" + srv.Addr()
+	}
+	return "ldap://" + srv.Addr()
+}
+
+// ClientTLSConfig returns a TLS configuration for clients that trusts
+// the server's certificate.
+func (srv *Server) ClientTLSConfig() *tls.Config {
+	config := tlstesting.ClientConfig(srv.CA, nil)
+	config.ServerName = "127.0.0.1"
+	return config
+}
+
+// Close stops the server, closing any client connections, and waits
+// for their goroutines to finish.
+func (srv *Server) Close() {
+	srv.mu.Lock()
+	srv.closed = true
+	srv.listener.Close()
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	srv.mu.Unlock()
+	srv.wg.Wait()
+}
+
+// Add adds entries to the directory, replacing any with the same DN.
+// The parents of an entry need not exist.
+func (srv *Server) Add(entries ...Entry) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for _, e := range entries {
+		srv.entries[normalizeDN(e.DN)] = copyEntry(e)
+	}
+}
+
+// LoadLDIF adds the entries in the given LDIF content records to the
+// directory. Leading whitespace common to every line is removed first,
+// so that the LDIF can be indented in the test source.
+func (srv *Server) LoadLDIF(c *gc.C, ldif string) {
+	entries, err := parseLDIF(dedent(ldif))
+	c.Assert(err, gc.IsNil)
+	srv.Add(entries...)
+}
+
+// Remove removes the entry with the given DN, if it exists.
+func (srv *Server) Remove(dn string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	delete(srv.entries, normalizeDN(dn))
+}
+
+// Entry returns the entry with the given DN, and whether it exists.
+func (srv *Server) Entry(dn string) (Entry, bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	e, ok := srv.entries[normalizeDN(dn)]
+	if !ok {
+		return Entry{}, false
+	}
+	return copyEntry(e), true
+}
+
+// Binds returns the bind attempts made so far, in order.
+func (srv *Server) Binds() []Bind {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]Bind(nil), srv.binds...)
+}
+
+// search returns the entries within the scope of the base DN that
+// match the filter, in DN order, or false if the base does not exist.
+func (srv *Server) search(base string, scope int64, f *ber.Packet) ([]Entry, bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	base = normalizeDN(base)
+	if _, ok := srv.entries[base]; !ok && base != "" {
+		return nil, false
+	}
+	dns := make([]string, 0, len(srv.entries))
+	for dn := range srv.entries {
+		dns = append(dns, dn)
+	}
+	sort.Strings(dns)
+	var result []Entry
+	for _, dn := range dns {
+		e := srv.entries[dn]
+		if inScope(dn, base, scope) && matchFilter(f, e) {
+			result = append(result, copyEntry(e))
+		}
+	}
+	return result, true
+}
+
+// checkPassword reports whether the entry with the given DN has the
+// given password.
+func (srv *Server) checkPassword(dn, password string) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	e, ok := srv.entries[normalizeDN(dn)]
+	if !ok {
+		return false
+	}
+	for _, p := range e.Get("userPassword") {
+		if p == password {
+			return true
+		}
+	}
+	return false
+}
+
+func (srv *Server) recordBind(dn string, ok bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.binds = append(srv.binds, Bind{DN: dn, OK: ok})
+}
+
+func (srv *Server) serve() {
+	defer srv.wg.Done()
+	for {
+		conn, err := srv.listener.Accept()
+		if err != nil {
+			return
+		}
+		srv.mu.Lock()
+		if srv.closed {
+			srv.mu.Unlock()
+			conn.Close()
+			return
+		}
+		srv.conns[conn] = true
+		srv.wg.Add(1)
+		srv.mu.Unlock()
+		go func() {
+			defer srv.wg.Done()
+			sc := &serverConn{srv: srv, conn: conn}
+			sc.serve()
+			srv.mu.Lock()
+			delete(srv.conns, conn)
+			srv.mu.Unlock()
+			sc.conn.Close()
+		}()
+	}
+}
+
+// dedent removes the leading whitespace common to all non-blank lines
+// of s.
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix = indent
+			first = false
+		} else {
+			prefix = commonPrefix(prefix, indent)
+		}
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, prefix)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
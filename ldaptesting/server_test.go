@@ -0,0 +1,311 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package ldaptesting_test
+
+import (
+	"github.com/go-ldap/ldap/v3"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/ldaptesting"
+)
+
+type serverSuite struct {
+	srv *ldaptesting.Server
+}
+
+var _ = gc.Suite(&serverSuite{})
+
+const testLDIF = `
+	# The directory used by most tests.
+	dn: dc=example,dc=com
+	objectClass: domain
+	dc: example
+
+	dn: ou=people,dc=example,dc=com
+	objectClass: organizationalUnit
+	ou: people
+
+	dn: uid=alice,ou=people,dc=example,dc=com
+	objectClass: inetOrgPerson
+	uid: alice
+	cn: Alice Liddell
+	mail: alice@example.com
+	employeeNumber: 7
+	userPassword: rabbit
+
+	dn: uid=bob,ou=people,dc=example,dc=com
+	objectClass: inetOrgPerson
+	uid: bob
+	cn: Bob Builder
+	employeeNumber: 12
+	userPassword:: Y2FuIHdlIGZpeCBpdA==
+
+	dn: ou=groups,dc=example,dc=com
+	objectClass: organizationalUnit
+	ou: groups
+
+	dn: cn=admins,ou=groups,dc=example,dc=com
+	objectClass: groupOfNames
+	cn: admins
+	member: uid=alice,ou=people,dc=example,dc=com
+	description: People who may administer
+	  the example systems
+`
+
+func (s *serverSuite) SetUpTest(c *gc.C) {
+	s.srv = ldaptesting.NewServer(c)
+	s.srv.LoadLDIF(c, testLDIF)
+}
+
+func (s *serverSuite) TearDownTest(c *gc.C) {
+	s.srv.Close()
+}
+
+func (s *serverSuite) dial(c *gc.C) *ldap.Conn {
+	conn, err := ldap.DialURL(s.srv.URL())
+	c.Assert(err, gc.IsNil)
+	return conn
+}
+
+func (s *serverSuite) TestLoadLDIF(c *gc.C) {
+	e, ok := s.srv.Entry("UID=bob, OU=people, DC=example, DC=com")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(e.DN, gc.Equals, "uid=bob,ou=people,dc=example,dc=com")
+	c.Assert(e.Get("userpassword"), jc.DeepEquals, []string{"can we fix it"})
+	e, ok = s.srv.Entry("cn=admins,ou=groups,dc=example,dc=com")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(e.Get("description"), jc.DeepEquals, []string{"People who may administer the example systems"})
+
+	s.srv.Remove("cn=admins,ou=groups,dc=example,dc=com")
+	_, ok = s.srv.Entry("cn=admins,ou=groups,dc=example,dc=com")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *serverSuite) TestLoadLDIFError(c *gc.C) {
+	c.ExpectFailure("LDIF without dn is rejected")
+	s.srv.LoadLDIF(c, "cn: nobody\n")
+}
+
+func (s *serverSuite) TestBind(c *gc.C) {
+	conn := s.dial(c)
+	defer conn.Close()
+	err := conn.Bind("uid=alice,ou=people,dc=example,dc=com", "rabbit")
+	c.Assert(err, gc.IsNil)
+	err = conn.Bind("uid=bob,ou=people,dc=example,dc=com", "can we fix it")
+	c.Assert(err, gc.IsNil)
+	err = conn.Bind("uid=alice,ou=people,dc=example,dc=com", "hatter")
+	c.Assert(ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials), jc.IsTrue, gc.Commentf("%v", err))
+	err = conn.Bind("uid=nobody,dc=example,dc=com", "x")
+	c.Assert(ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials), jc.IsTrue, gc.Commentf("%v", err))
+	err = conn.UnauthenticatedBind("")
+	c.Assert(err, gc.IsNil)
+
+	c.Assert(s.srv.Binds(), jc.DeepEquals, []ldaptesting.Bind{
+		{DN: "uid=alice,ou=people,dc=example,dc=com", OK: true},
+		{DN: "uid=bob,ou=people,dc=example,dc=com", OK: true},
+		{DN: "uid=alice,ou=people,dc=example,dc=com", OK: false},
+		{DN: "uid=nobody,dc=example,dc=com", OK: false},
+		{DN: "", OK: true},
+	})
+}
+
+func (s *serverSuite) TestWhoAmI(c *gc.C) {
+	conn := s.dial(c)
+	defer conn.Close()
+	result, err := conn.WhoAmI(nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.AuthzID, gc.Equals, "")
+	err = conn.Bind("uid=alice,ou=people,dc=example,dc=com", "rabbit")
+	c.Assert(err, gc.IsNil)
+	result, err = conn.WhoAmI(nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.AuthzID, gc.Equals, "dn:uid=alice,ou=people,dc=example,dc=com")
+}
+
+// search returns the DNs of the entries found by the search.
+func search(c *gc.C, conn *ldap.Conn, base string, scope int, filter string) []string {
+	result, err := conn.Search(ldap.NewSearchRequest(base, scope, ldap.NeverDerefAliases, 0, 0, false, filter, nil, nil))
+	c.Assert(err, gc.IsNil)
+	var dns []string
+	for _, e := range result.Entries {
+		dns = append(dns, e.DN)
+	}
+	return dns
+}
+
+var searchTests = []struct {
+	about  string
+	base   string
+	scope  int
+	filter string
+	expect []string
+}{{
+	about:  "all entries",
+	base:   "dc=example,dc=com",
+	scope:  ldap.ScopeWholeSubtree,
+	filter: "(objectClass=*)",
+	expect: []string{
+		"cn=admins,ou=groups,dc=example,dc=com",
+		"dc=example,dc=com",
+		"ou=groups,dc=example,dc=com",
+		"ou=people,dc=example,dc=com",
+		"uid=alice,ou=people,dc=example,dc=com",
+		"uid=bob,ou=people,dc=example,dc=com",
+	},
+}, {
+	about:  "base object",
+	base:   "ou=people,dc=example,dc=com",
+	scope:  ldap.ScopeBaseObject,
+	filter: "(objectClass=*)",
+	expect: []string{"ou=people,dc=example,dc=com"},
+}, {
+	about:  "single level",
+	base:   "dc=example,dc=com",
+	scope:  ldap.ScopeSingleLevel,
+	filter: "(objectClass=organizationalUnit)",
+	expect: []string{"ou=groups,dc=example,dc=com", "ou=people,dc=example,dc=com"},
+}, {
+	about:  "equality is case-insensitive",
+	base:   "dc=example,dc=com",
+	scope:  ldap.ScopeWholeSubtree,
+	filter: "(uid=ALICE)",
+	expect: []string{"uid=alice,ou=people,dc=example,dc=com"},
+}, {
+	about:  "and, or and not",
+	base:   "dc=example,dc=com",
+	scope:  ldap.ScopeWholeSubtree,
+	filter: "(&(objectClass=inetOrgPerson)(|(uid=alice)(uid=bob))(!(mail=*)))",
+	expect: []string{"uid=bob,ou=people,dc=example,dc=com"},
+}, {
+	about:  "substrings",
+	base:   "dc=example,dc=com",
+	scope:  ldap.ScopeWholeSubtree,
+	filter: "(cn=b*b*der)",
+	expect: []string{"uid=bob,ou=people,dc=example,dc=com"},
+}, {
+	about:  "numeric ordering",
+	base:   "dc=example,dc=com",
+	scope:  ldap.ScopeWholeSubtree,
+	filter: "(employeeNumber>=10)",
+	expect: []string{"uid=bob,ou=people,dc=example,dc=com"},
+}, {
+	about:  "less or equal",
+	base:   "dc=example,dc=com",
+	scope:  ldap.ScopeWholeSubtree,
+	filter: "(employeeNumber<=10)",
+	expect: []string{"uid=alice,ou=people,dc=example,dc=com"},
+}, {
+	about:  "group membership",
+	base:   "ou=groups,dc=example,dc=com",
+	scope:  ldap.ScopeWholeSubtree,
+	filter: "(member=uid=alice,ou=people,dc=example,dc=com)",
+	expect: []string{"cn=admins,ou=groups,dc=example,dc=com"},
+}, {
+	about:  "no matches",
+	base:   "dc=example,dc=com",
+	scope:  ldap.ScopeWholeSubtree,
+	filter: "(uid=carol)",
+}}
+
+func (s *serverSuite) TestSearch(c *gc.C) {
+	conn := s.dial(c)
+	defer conn.Close()
+	for i, test := range searchTests {
+		c.Logf("test %d: %s", i, test.about)
+		c.Check(search(c, conn, test.base, test.scope, test.filter), jc.DeepEquals, test.expect)
+	}
+}
+
+func (s *serverSuite) TestSearchAttributes(c *gc.C) {
+	conn := s.dial(c)
+	defer conn.Close()
+	result, err := conn.Search(ldap.NewSearchRequest(
+		"ou=people,dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		"(uid=alice)", []string{"CN", "mail"}, nil,
+	))
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+	e := result.Entries[0]
+	c.Assert(e.Attributes, gc.HasLen, 2)
+	c.Assert(e.GetAttributeValue("cn"), gc.Equals, "Alice Liddell")
+	c.Assert(e.GetAttributeValue("mail"), gc.Equals, "alice@example.com")
+
+	result, err = conn.Search(ldap.NewSearchRequest(
+		"ou=people,dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, true,
+		"(uid=alice)", nil, nil,
+	))
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Entries[0].Attributes, gc.HasLen, 6)
+	c.Assert(result.Entries[0].GetAttributeValues("uid"), gc.HasLen, 0)
+}
+
+func (s *serverSuite) TestSearchErrors(c *gc.C) {
+	conn := s.dial(c)
+	defer conn.Close()
+	_, err := conn.Search(ldap.NewSearchRequest(
+		"dc=nowhere", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)", nil, nil,
+	))
+	c.Assert(ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject), jc.IsTrue, gc.Commentf("%v", err))
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		"dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false, "(objectClass=*)", nil, nil,
+	))
+	c.Assert(ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded), jc.IsTrue, gc.Commentf("%v", err))
+	c.Assert(result.Entries, gc.HasLen, 2)
+
+	err = conn.Del(ldap.NewDelRequest("uid=bob,ou=people,dc=example,dc=com", nil))
+	c.Assert(ldap.IsErrorWithCode(err, ldap.LDAPResultUnwillingToPerform), jc.IsTrue, gc.Commentf("%v", err))
+	_, ok := s.srv.Entry("uid=bob,ou=people,dc=example,dc=com")
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *serverSuite) TestDenyAnonymous(c *gc.C) {
+	s.srv.DenyAnonymous = true
+	conn := s.dial(c)
+	defer conn.Close()
+	req := ldap.NewSearchRequest(
+		"dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, "(uid=bob)", nil, nil,
+	)
+	_, err := conn.Search(req)
+	c.Assert(ldap.IsErrorWithCode(err, ldap.LDAPResultInsufficientAccessRights), jc.IsTrue, gc.Commentf("%v", err))
+	err = conn.Bind("uid=alice,ou=people,dc=example,dc=com", "rabbit")
+	c.Assert(err, gc.IsNil)
+	result, err := conn.Search(req)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+}
+
+func (s *serverSuite) TestStartTLS(c *gc.C) {
+	conn := s.dial(c)
+	defer conn.Close()
+	err := conn.StartTLS(s.srv.ClientTLSConfig())
+	c.Assert(err, gc.IsNil)
+	_, ok := conn.TLSConnectionState()
+	c.Assert(ok, jc.IsTrue)
+	err = conn.Bind("uid=alice,ou=people,dc=example,dc=com", "rabbit")
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *serverSuite) TestTLSServer(c *gc.C) {
+	srv := ldaptesting.NewTLSServer(c)
+	defer srv.Close()
+	srv.Add(ldaptesting.Entry{
+		DN: "uid=carol,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"uid":          {"carol"},
+			"userPassword": {"pw"},
+		},
+	})
+	c.Assert(srv.URL(), jc.HasPrefix, "ldaps://127.0.0.1:")
+	conn, err := ldap.DialURL(srv.URL(), ldap.DialWithTLSConfig(srv.ClientTLSConfig()))
+	c.Assert(err, gc.IsNil)
+	defer conn.Close()
+	err = conn.Bind("uid=carol,dc=example,dc=com", "pw")
+	c.Assert(err, gc.IsNil)
+
+	// Clients that do not trust the server's CA cannot connect.
+	_, err = ldap.DialURL(srv.URL())
+	c.Assert(err, gc.ErrorMatches, ".*certificate.*")
+}
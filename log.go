@@ -6,18 +6,93 @@ package testing
 import (
 	"flag"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
 )
 
 var logLocation = flag.Bool("loggo.location", false, "Also log the location of the loggo call")
 
 // LoggingSuite redirects the juju logger to the test logger
 // when embedded in a gocheck suite type.
-type LoggingSuite struct{}
+//
+// The entries logged during each test, including those written
+// with the standard library's log package, are also captured and
+// are available from LogEntries, so that they can be checked with
+// checkers.LogMatches:
+//
+//	c.Check(s.LogEntries(), jc.LogMatches, []jc.SimpleMessage{
+//		{loggo.WARNING, "connection .* lost"},
+//	})
+//
+// If CheckNoWarnings is set, each test also fails if it logs anything
+// at WARNING level or above, other than messages matching one of
+// AllowedWarnings, so that errors which are logged and then ignored
+// are not passed over:
+//
+//	var _ = gc.Suite(&workerSuite{
+//		LoggingSuite: testing.LoggingSuite{
+//			CheckNoWarnings: true,
+//			AllowedWarnings: []string{"connection .* lost"},
+//		},
+//	})
+//
+// If DeprecationMarkers is set, each test also fails if the code under
+// test logs a message, or writes a line to os.Stderr, that shows it
+// used something deprecated, so that migrations away from deprecated
+// code can be driven by the tests:
+//
+//	var _ = gc.Suite(&clientSuite{
+//		LoggingSuite: testing.LoggingSuite{
+//			DeprecationMarkers: []string{`(?i)\bdeprecated\b`},
+//		},
+//	})
+//
+// The checks are made in TearDownTest, so as with any fixture failure,
+// gocheck does not run the suite's remaining tests after one fails.
+type LoggingSuite struct {
+	// CheckNoWarnings holds whether to fail tests that log messages
+	// at WARNING level or above.
+	CheckNoWarnings bool
+
+	// AllowedWarnings holds regular expressions matching messages
+	// that may be logged at WARNING level or above even when
+	// CheckNoWarnings is set.
+	AllowedWarnings []string
+
+	// DeprecationMarkers holds regular expressions matching messages
+	// that show deprecated code was used.
+	DeprecationMarkers []string
+
+	// ReportDeprecations holds whether uses of deprecated code found
+	// with DeprecationMarkers are only logged, rather than failing
+	// the test.
+	ReportDeprecations bool
+
+	capture       *captureWriter
+	restoreStdLog func()
+	stderr        *stderrCapture
+
+	// waited holds the number of captured entries up to and
+	// including the last one returned by WaitLogEntry.
+	waited int
+}
+
+// captureWriterName is the name of the loggo writer used by
+// LoggingSuite to capture log entries.
+const captureWriterName = "loggingsuite-capture"
+
+// stdLogModule holds the module name given to entries captured
+// from the standard library's log package.
+const stdLogModule = "stdlog"
 
 type gocheckWriter struct {
 	c *gc.C
@@ -55,9 +130,95 @@ func (s *LoggingSuite) TearDownSuite(c *gc.C) {
 
 func (s *LoggingSuite) SetUpTest(c *gc.C) {
 	s.setUp(c)
+	s.capture = newCaptureWriter()
+	s.waited = 0
+	err := loggo.RegisterWriter(captureWriterName, s.capture)
+	c.Assert(err, gc.IsNil)
+	s.restoreStdLog = redirectStdLog(&stdLogWriter{
+		writers: []loggo.Writer{s.capture, &gocheckWriter{c}},
+	})
+	if len(s.DeprecationMarkers) > 0 {
+		s.stderr, err = captureStderr()
+		c.Assert(err, gc.IsNil)
+	}
 }
 
 func (s *LoggingSuite) TearDownTest(c *gc.C) {
+	if s.restoreStdLog != nil {
+		s.restoreStdLog()
+		s.restoreStdLog = nil
+	}
+	loggo.RemoveWriter(captureWriterName)
+	var stderr []byte
+	if s.stderr != nil {
+		stderr = s.stderr.stop()
+		s.stderr = nil
+	}
+	if s.capture == nil {
+		return
+	}
+	if s.CheckNoWarnings {
+		params := []interface{}{s.capture.Log(), s.AllowedWarnings}
+		if ok, msg := jc.NoWarningsLogged.Check(params, nil); !ok {
+			c.Errorf("%s", strings.TrimSuffix(msg, "\n"))
+		}
+	}
+	if len(s.DeprecationMarkers) > 0 {
+		s.checkDeprecations(c, s.capture.Log(), stderr)
+	}
+}
+
+// LogEntries returns the log entries captured so far in the current
+// test. Entries written with the standard library's log package are
+// recorded at INFO level with the module "stdlog".
+func (s *LoggingSuite) LogEntries() []loggo.Entry {
+	if s.capture == nil {
+		return nil
+	}
+	return s.capture.Log()
+}
+
+// ClearLogEntries discards the log entries captured so far in the
+// current test.
+func (s *LoggingSuite) ClearLogEntries() {
+	if s.capture != nil {
+		s.capture.Clear()
+	}
+	s.waited = 0
+}
+
+// redirectStdLog sends the output of the standard library's default
+// logger to w, without any prefix or flags, and returns a function
+// that restores the previous configuration.
+func redirectStdLog(w io.Writer) func() {
+	oldOutput, oldFlags, oldPrefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(w)
+	log.SetFlags(0)
+	log.SetPrefix("")
+	return func() {
+		log.SetOutput(oldOutput)
+		log.SetFlags(oldFlags)
+		log.SetPrefix(oldPrefix)
+	}
+}
+
+// stdLogWriter converts the output of the standard library's log
+// package into loggo entries and passes them to the given writers.
+type stdLogWriter struct {
+	writers []loggo.Writer
+}
+
+func (w *stdLogWriter) Write(data []byte) (int, error) {
+	entry := loggo.Entry{
+		Level:     loggo.INFO,
+		Module:    stdLogModule,
+		Timestamp: time.Now(),
+		Message:   strings.TrimSuffix(string(data), "\n"),
+	}
+	for _, writer := range w.writers {
+		writer.Write(entry)
+	}
+	return len(data), nil
 }
 
 type discardWriter struct{}
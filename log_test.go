@@ -4,9 +4,14 @@
 package testing
 
 import (
+	"bytes"
+	"log"
+
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/loggo"
+
+	jc "github.com/juju/testing/checkers"
 )
 
 type logSuite struct{}
@@ -45,3 +50,65 @@ func (*logSuite) TestLog(c *gc.C) {
 	c.Assert(logger.EffectiveLogLevel(), gc.Equals, loggo.WARNING)
 	c.Assert(jujuLogger.EffectiveLogLevel(), gc.Equals, loggo.WARNING)
 }
+
+func (*logSuite) TestLogEntriesCaptured(c *gc.C) {
+	var suite LoggingSuite
+	suite.SetUpSuite(c)
+	defer suite.TearDownSuite(c)
+	suite.SetUpTest(c)
+
+	logger := loggo.GetLogger("test")
+	logger.Infof("message 1")
+	log.Printf("message 2")
+	c.Assert(suite.LogEntries(), jc.LogMatches, []jc.SimpleMessage{
+		{Level: loggo.INFO, Message: "message 1"},
+		{Level: loggo.INFO, Message: "message 2"},
+	})
+	c.Assert(suite.LogEntries()[1].Module, gc.Equals, "stdlog")
+
+	suite.ClearLogEntries()
+	logger.Warningf("message 3")
+	c.Assert(suite.LogEntries(), gc.HasLen, 1)
+
+	suite.TearDownTest(c)
+	log.Printf("after test")
+	c.Assert(suite.LogEntries(), gc.HasLen, 1)
+	c.Assert(log.Writer(), gc.Not(gc.FitsTypeOf), &stdLogWriter{})
+}
+
+// warningsSuite is run by TestCheckNoWarnings rather than being
+// registered with gocheck.
+type warningsSuite struct {
+	LoggingSuite
+}
+
+func (*warningsSuite) TestAllowedWarning(c *gc.C) {
+	loggo.GetLogger("test").Warningf("connection 1 lost")
+}
+
+func (*warningsSuite) TestInfo(c *gc.C) {
+	loggo.GetLogger("test").Infof("all is well")
+}
+
+func (*warningsSuite) TestSwallowedError(c *gc.C) {
+	loggo.GetLogger("test").Errorf("cannot write: disk full")
+}
+
+func (*logSuite) TestCheckNoWarnings(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&warningsSuite{LoggingSuite{
+		CheckNoWarnings: true,
+		AllowedWarnings: []string{"connection .* lost"},
+	}}, &gc.RunConf{Output: &output})
+	c.Assert(result.Passed(), jc.IsFalse)
+	c.Assert(output.String(), gc.Matches, `(?s).*warningsSuite\.TearDownTest\n.*`+
+		`\.\.\. Error: unexpected warnings logged:\n`+
+		`  ERROR cannot write: disk full\n.*`+
+		`PANIC: .*warningsSuite\.TestSwallowedError\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*  WARNING connection 1 lost\n.*`)
+
+	// Without CheckNoWarnings, logged errors are not checked.
+	output.Reset()
+	result = gc.Run(&warningsSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Passed(), jc.IsTrue)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/loggo"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// captureWriter records log entries, as loggo.TestWriter does, and
+// signals when each one is written.
+type captureWriter struct {
+	loggo.TestWriter
+
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+func newCaptureWriter() *captureWriter {
+	return &captureWriter{
+		changed: make(chan struct{}),
+	}
+}
+
+// Write implements loggo.Writer.
+func (w *captureWriter) Write(entry loggo.Entry) {
+	w.TestWriter.Write(entry)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// changes returns a channel that is closed when the next entry is
+// written.
+func (w *captureWriter) changes() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.changed
+}
+
+// WaitLogEntry waits until an entry matching expect is logged, and
+// returns it, so that a test of an asynchronous worker can wait for the
+// worker to reach a milestone rather than sleeping:
+//
+//	worker := StartWorker(clk)
+//	s.WaitLogEntry(c, clk, 0, jc.SimpleMessage{Level: loggo.INFO, Message: "connected to .*"})
+//
+// As with checkers.LogMatches, the message of expect is a regular
+// expression, and if its level is loggo.UNSPECIFIED, entries at any
+// level match. Only entries logged after the one last returned by
+// WaitLogEntry in the test are considered, so a milestone that recurs
+// can be waited for repeatedly.
+//
+// The test fails if no such entry is logged within timeout, or
+// testing.LongWait if it is zero, as measured by clk. If clk is nil,
+// clock.WallClock is used; with a testclock.Clock, the timeout only
+// passes as the test advances the clock.
+func (s *LoggingSuite) WaitLogEntry(c *gc.C, clk clock.Clock, timeout time.Duration, expect jc.SimpleMessage) loggo.Entry {
+	if s.capture == nil {
+		c.Fatalf("WaitLogEntry called outside a test")
+	}
+	re, err := regexp.Compile(expect.Message)
+	if err != nil {
+		c.Fatalf("bad message regexp %q: %v", expect.Message, err)
+	}
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	if timeout == 0 {
+		timeout = LongWait
+	}
+	timedOut := clk.After(timeout)
+	for {
+		// Get the channel before reading the entries, so that an
+		// entry written in between is not missed.
+		changed := s.capture.changes()
+		entries := s.capture.Log()
+		if s.waited > len(entries) {
+			// The entries were cleared.
+			s.waited = 0
+		}
+		for i := s.waited; i < len(entries); i++ {
+			entry := entries[i]
+			if expect.Level != loggo.UNSPECIFIED && entry.Level != expect.Level {
+				continue
+			}
+			if re.MatchString(entry.Message) {
+				s.waited = i + 1
+				return entry
+			}
+		}
+		select {
+		case <-changed:
+		case <-timedOut:
+			c.Fatalf("timed out after %v waiting for log entry matching %s; entries logged:\n%s",
+				timeout, formatExpectedEntry(expect), formatEntries(s.capture.Log()[s.waited:]))
+		}
+	}
+}
+
+// formatExpectedEntry describes an expected log entry.
+func formatExpectedEntry(expect jc.SimpleMessage) string {
+	level := "ANY"
+	if expect.Level != loggo.UNSPECIFIED {
+		level = expect.Level.String()
+	}
+	return fmt.Sprintf("%s %q", level, expect.Message)
+}
+
+// formatEntries formats log entries one per line, indented.
+func formatEntries(entries []loggo.Entry) string {
+	if len(entries) == 0 {
+		return "  (none)"
+	}
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = fmt.Sprintf("  %s %s %s", entry.Level, entry.Module, entry.Message)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/loggo"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+)
+
+type logWaitSuite struct {
+	LoggingSuite
+}
+
+var _ = gc.Suite(&logWaitSuite{})
+
+func (s *logWaitSuite) TestAlreadyLogged(c *gc.C) {
+	loggo.GetLogger("test").Infof("worker started")
+	entry := s.WaitLogEntry(c, nil, 0, jc.SimpleMessage{Level: loggo.UNSPECIFIED, Message: "worker .*"})
+	c.Assert(entry.Message, gc.Equals, "worker started")
+	c.Assert(entry.Module, gc.Equals, "test")
+}
+
+func (s *logWaitSuite) TestWaitsForEntry(c *gc.C) {
+	go func() {
+		logger := loggo.GetLogger("worker")
+		logger.Debugf("connecting")
+		time.Sleep(ShortWait)
+		logger.Infof("connected")
+	}()
+	entry := s.WaitLogEntry(c, nil, 0, jc.SimpleMessage{Level: loggo.INFO, Message: "^connect"})
+	c.Assert(entry.Message, gc.Equals, "connected")
+}
+
+func (s *logWaitSuite) TestRecurringEntry(c *gc.C) {
+	logger := loggo.GetLogger("worker")
+	logger.Infof("tick 1")
+	logger.Infof("tick 2")
+	c.Assert(s.WaitLogEntry(c, nil, 0, jc.SimpleMessage{Level: loggo.INFO, Message: "tick"}).Message, gc.Equals, "tick 1")
+	c.Assert(s.WaitLogEntry(c, nil, 0, jc.SimpleMessage{Level: loggo.INFO, Message: "tick"}).Message, gc.Equals, "tick 2")
+	go logger.Infof("tick 3")
+	c.Assert(s.WaitLogEntry(c, nil, 0, jc.SimpleMessage{Level: loggo.INFO, Message: "tick"}).Message, gc.Equals, "tick 3")
+
+	s.ClearLogEntries()
+	logger.Infof("tick 4")
+	c.Assert(s.WaitLogEntry(c, nil, 0, jc.SimpleMessage{Level: loggo.INFO, Message: "tick"}).Message, gc.Equals, "tick 4")
+}
+
+// logTimeoutSuite is run by TestTimeout rather than being registered
+// with gocheck.
+type logTimeoutSuite struct {
+	LoggingSuite
+}
+
+func (s *logTimeoutSuite) TestTimeout(c *gc.C) {
+	clk := testclock.NewClock(time.Time{})
+	go func() {
+		for clk.WaiterCount() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		clk.Advance(time.Minute)
+	}()
+	loggo.GetLogger("worker").Warningf("cannot connect")
+	s.WaitLogEntry(c, clk, time.Minute, jc.SimpleMessage{Level: loggo.INFO, Message: "connected"})
+	c.Fatalf("WaitLogEntry did not stop the test")
+}
+
+func (s *logTimeoutSuite) TestBadRegexp(c *gc.C) {
+	s.WaitLogEntry(c, nil, 0, jc.SimpleMessage{Level: loggo.INFO, Message: "["})
+}
+
+func (*logWaitSuite) TestTimeout(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&logTimeoutSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 2)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`timed out after 1m0s waiting for log entry matching INFO "connected"; entries logged:\n`+
+		`  WARNING worker cannot connect\n.*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*bad message regexp "\[": .*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*did not stop the test.*`)
+}
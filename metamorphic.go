@@ -0,0 +1,96 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"math/rand"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// Relation is a metamorphic relation of a function with inputs of type
+// T and outputs of type R: a relation between the function's output for
+// an input and its output for a follow-up input derived from it, such
+// as "adding a no-op filter does not change the results" or "shuffling
+// the input does not change the results". Metamorphic relations can be
+// checked when the correct output for an input is not known.
+type Relation[T, R any] struct {
+	// Name describes the relation.
+	Name string
+
+	// Transform returns the follow-up input derived from input. It
+	// may make random choices with r, such as the order of a shuffle.
+	// It must not change input, as the function is also called with
+	// it.
+	Transform func(r *rand.Rand, input T) T
+
+	// Check checks the output of the function for the follow-up
+	// input against its output for the original input, failing the
+	// test that it is given if the relation does not hold. If it is
+	// nil, the outputs must be equal, as checked by jc.DeepEquals.
+	Check func(c *gc.C, output, followUpOutput R)
+}
+
+// CheckMetamorphic checks that the given metamorphic relations hold for
+// the function f, with inputs generated by gen, as CheckProperty checks
+// properties. For each relation that does not hold, the input for which
+// it fails is shrunk to the simplest one for which it still fails,
+// which is reported along with the relation and the follow-up input.
+// CheckMetamorphic returns whether all the relations held:
+//
+//	testing.CheckMetamorphic(c, testing.GenSlice(genRecord), search, testing.Relation[[]Record, []Record]{
+//		Name: "shuffling the records does not change the results",
+//		Transform: func(r *rand.Rand, records []Record) []Record {
+//			shuffled := append([]Record(nil), records...)
+//			r.Shuffle(len(shuffled), func(i, j int) {
+//				shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+//			})
+//			return shuffled
+//		},
+//		Check: func(c *gc.C, results, shuffledResults []Record) {
+//			c.Assert(shuffledResults, jc.SameContents, results)
+//		},
+//	})
+//
+// The follow-up input of each relation is transformed with a generator
+// that is seeded in the same way for every input, so that the same
+// choices are made while the input is shrunk.
+func CheckMetamorphic[T, R any](c *gc.C, gen Gen[T], f func(input T) R, relations ...Relation[T, R]) bool {
+	r := NewSeededRand(c)
+	ok := true
+	for _, rel := range relations {
+		rel := rel
+		transformSeed := r.Int63()
+		followUp := func(input T) T {
+			return rel.Transform(rand.New(rand.NewSource(transformSeed)), input)
+		}
+		failure := findPropertyFailure(r, gen, func(c *gc.C, input T) {
+			output := f(input)
+			followUpOutput := f(followUp(input))
+			if rel.Check == nil {
+				c.Assert(followUpOutput, jc.DeepEquals, output)
+				return
+			}
+			rel.Check(c, output, followUpOutput)
+		})
+		if failure == nil {
+			c.Logf("relation %q held for %d inputs", rel.Name, *propertyRuns)
+			continue
+		}
+		ok = false
+		c.Errorf("relation %q failed after %d runs with input:\n\t%#v\nfollow-up input:\n\t%#v\nshrunk %d times from:\n\t%#v\nfailure:\n%s",
+			rel.Name, failure.runs, failure.input, followUp(failure.input), failure.shrinks, failure.original, indent(failure.output))
+	}
+	return ok
+}
+
+// AssertMetamorphic is like CheckMetamorphic, but stops the test if any
+// of the relations does not hold.
+func AssertMetamorphic[T, R any](c *gc.C, gen Gen[T], f func(input T) R, relations ...Relation[T, R]) {
+	if !CheckMetamorphic(c, gen, f, relations...) {
+		c.FailNow()
+	}
+}
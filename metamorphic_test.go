@@ -0,0 +1,131 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type metamorphicSuite struct{}
+
+var _ = gc.Suite(&metamorphicSuite{})
+
+func sum(v []int) int {
+	total := 0
+	for _, n := range v {
+		total += n
+	}
+	return total
+}
+
+// firstPositive returns the first positive number in v, or 0 if there
+// is none.
+func firstPositive(v []int) int {
+	for _, n := range v {
+		if n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+var shuffleInts = testing.Relation[[]int, int]{
+	Name: "shuffling the input does not change the result",
+	Transform: func(r *rand.Rand, v []int) []int {
+		shuffled := append([]int(nil), v...)
+		r.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+		return shuffled
+	},
+}
+
+var reverseInts = testing.Relation[[]int, int]{
+	Name: "reversing the input does not change the result",
+	Transform: func(r *rand.Rand, v []int) []int {
+		reversed := make([]int, len(v))
+		for i, n := range v {
+			reversed[len(v)-1-i] = n
+		}
+		return reversed
+	},
+}
+
+var appendZero = testing.Relation[[]int, int]{
+	Name: "appending zero does not decrease the result",
+	Transform: func(r *rand.Rand, v []int) []int {
+		return append(append([]int(nil), v...), 0)
+	},
+	Check: func(c *gc.C, output, followUpOutput int) {
+		c.Assert(followUpOutput, jc.GreaterThan, output-1)
+	},
+}
+
+func (*metamorphicSuite) TestRelationsHold(c *gc.C) {
+	gen := testing.GenSlice(testing.GenInt(-100, 100))
+	ok := testing.CheckMetamorphic(c, gen, sum, shuffleInts, reverseInts, appendZero)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(c.GetTestLog(), gc.Matches, `random seed -?\d+ .*\n`+
+		`relation "shuffling the input does not change the result" held for 100 inputs\n`+
+		`relation "reversing the input does not change the result" held for 100 inputs\n`+
+		`relation "appending zero does not decrease the result" held for 100 inputs\n`)
+}
+
+func (*metamorphicSuite) TestTransformRepeatable(c *gc.C) {
+	var shuffles [][]int
+	record := testing.Relation[[]int, []int]{
+		Name: "sorting is permutation-invariant",
+		Transform: func(r *rand.Rand, v []int) []int {
+			shuffled := shuffleInts.Transform(r, v)
+			shuffles = append(shuffles, shuffled)
+			return shuffled
+		},
+	}
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	gen := testing.Gen[[]int]{
+		Generate: func(r *rand.Rand, size int) []int { return input },
+	}
+	sorted := func(v []int) []int {
+		s := append([]int(nil), v...)
+		sort.Ints(s)
+		return s
+	}
+	testing.AssertMetamorphic(c, gen, sorted, record)
+	c.Assert(shuffles, gc.HasLen, 100)
+	for _, s := range shuffles {
+		c.Assert(s, jc.DeepEquals, shuffles[0])
+	}
+}
+
+// failingMetamorphicSuite is run by TestRelationFails rather than being
+// registered with gocheck.
+type failingMetamorphicSuite struct{}
+
+func (*failingMetamorphicSuite) TestRelations(c *gc.C) {
+	gen := testing.GenSlice(testing.GenInt(-1000, 1000))
+	testing.AssertMetamorphic(c, gen, firstPositive, appendZero, reverseInts)
+	c.Fatalf("AssertMetamorphic did not stop the test")
+}
+
+func (*metamorphicSuite) TestRelationFails(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&failingMetamorphicSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`relation "reversing the input does not change the result" failed after \d+ runs with input:\n`+
+		`\t\[\]int\{(1, 2|2, 1)\}\n`+
+		`follow-up input:\n`+
+		`\t\[\]int\{(2, 1|1, 2)\}\n`+
+		`shrunk \d+ times from:\n\t\[\]int\{.*\}\n`+
+		`failure:\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*relation "appending zero[^\n]*failed.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*did not stop the test.*`)
+}
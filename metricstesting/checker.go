@@ -0,0 +1,138 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package metricstesting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+type seriesChecker struct {
+	*gc.CheckerInfo
+	exact bool
+}
+
+// ContainsSeries checks that the obtained metrics include each of the
+// expected []Series, with the same name, labels and value. The
+// obtained metrics may be a *Scrape, or a string or []byte holding
+// metrics in the text exposition format. Other series are ignored.
+//
+// On failure, the series with the expected names are listed, with
+// expected series that were not observed prefixed with "-" and
+// observed series whose values differ prefixed with "+". For example:
+//
+//	  http_requests_total{code="200"} 3
+//	- http_requests_total{code="500"} 1
+//	+ http_requests_total{code="500"} 2
+//
+// To check how much a counter has increased during a test, check the
+// Delta of scrapes taken before and after it.
+var ContainsSeries gc.Checker = &seriesChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "ContainsSeries", Params: []string{"obtained", "expected"}},
+}
+
+// SeriesEquals is like ContainsSeries, but also checks that there are
+// no other series with any of the expected names, so that the label
+// sets of a metric can be checked. Unexpected series are prefixed with
+// "+" on failure.
+var SeriesEquals gc.Checker = &seriesChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "SeriesEquals", Params: []string{"obtained", "expected"}},
+	exact:       true,
+}
+
+func (checker *seriesChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	var scrape *Scrape
+	switch obtained := params[0].(type) {
+	case *Scrape:
+		scrape = obtained
+	case string:
+		s, err := Parse([]byte(obtained))
+		if err != nil {
+			return false, fmt.Sprintf("cannot parse obtained metrics: %v", err)
+		}
+		scrape = s
+	case []byte:
+		s, err := Parse(obtained)
+		if err != nil {
+			return false, fmt.Sprintf("cannot parse obtained metrics: %v", err)
+		}
+		scrape = s
+	default:
+		return false, fmt.Sprintf("obtained value must be *metricstesting.Scrape, string or []byte, got %T", params[0])
+	}
+	if scrape == nil {
+		return false, "obtained value is nil"
+	}
+	expected, ok := params[1].([]Series)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be []metricstesting.Series, got %T", params[1])
+	}
+	diff, ok := seriesDiff(scrape.Series, expected, checker.exact)
+	if ok {
+		return true, ""
+	}
+	return false, "series differ:\n" + diff
+}
+
+// seriesDiff returns a diff of the obtained series with the names of
+// the expected ones against the expected series, and whether they
+// match. If exact is true, obtained series that were not expected are
+// a mismatch.
+func seriesDiff(obtained, expected []Series, exact bool) (string, bool) {
+	names := make(map[string]bool)
+	want := make(map[string]Series)
+	for _, series := range expected {
+		names[series.Name] = true
+		want[series.key()] = series
+	}
+	got := make(map[string]Series)
+	for _, series := range obtained {
+		if names[series.Name] {
+			got[series.key()] = series
+		}
+	}
+	keys := make([]string, 0, len(want)+len(got))
+	for key := range want {
+		keys = append(keys, key)
+	}
+	for key := range got {
+		if _, ok := want[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	ok := true
+	var buf strings.Builder
+	for _, key := range keys {
+		w, wanted := want[key]
+		g, observed := got[key]
+		switch {
+		case wanted && observed && sameValue(w.Value, g.Value):
+			fmt.Fprintf(&buf, "    %s\n", g)
+		case wanted:
+			ok = false
+			fmt.Fprintf(&buf, "  - %s\n", w)
+			if observed {
+				fmt.Fprintf(&buf, "  + %s\n", g)
+			}
+		case exact:
+			ok = false
+			fmt.Fprintf(&buf, "  + %s\n", g)
+		default:
+			fmt.Fprintf(&buf, "    %s\n", g)
+		}
+	}
+	return buf.String(), ok
+}
+
+// sameValue reports whether two sample values are the same, treating
+// NaN as equal to itself.
+func sameValue(a, b float64) bool {
+	return a == b || math.IsNaN(a) && math.IsNaN(b)
+}
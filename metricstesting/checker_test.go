@@ -0,0 +1,95 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package metricstesting_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/metricstesting"
+)
+
+type checkerSuite struct{}
+
+var _ = gc.Suite(&checkerSuite{})
+
+const requests = `
+requests_total{code="200"} 3
+requests_total{code="404"} 2
+requests_total{code="500"} 2
+queue_length 7
+`
+
+func (*checkerSuite) TestContainsSeries(c *gc.C) {
+	c.Check(requests, metricstesting.ContainsSeries, []metricstesting.Series{
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "200"}, Value: 3},
+		{Name: "queue_length", Value: 7},
+	})
+	c.Check([]byte(requests), metricstesting.ContainsSeries, []metricstesting.Series(nil))
+
+	result, msg := metricstesting.ContainsSeries.Check([]interface{}{requests, []metricstesting.Series{
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "200"}, Value: 3},
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "500"}, Value: 1},
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "503"}, Value: 1},
+	}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, `series differ:
+    requests_total{code="200"} 3
+    requests_total{code="404"} 2
+  - requests_total{code="500"} 1
+  + requests_total{code="500"} 2
+  - requests_total{code="503"} 1
+`)
+}
+
+func (*checkerSuite) TestSeriesEquals(c *gc.C) {
+	scrape, err := metricstesting.Parse([]byte(requests))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(scrape, metricstesting.SeriesEquals, []metricstesting.Series{
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "200"}, Value: 3},
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "404"}, Value: 2},
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "500"}, Value: 2},
+	})
+
+	result, msg := metricstesting.SeriesEquals.Check([]interface{}{scrape, []metricstesting.Series{
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "200"}, Value: 3},
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "500"}, Value: 2},
+	}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, `series differ:
+    requests_total{code="200"} 3
+  + requests_total{code="404"} 2
+    requests_total{code="500"} 2
+`)
+}
+
+func (*checkerSuite) TestDelta(c *gc.C) {
+	before, err := metricstesting.Parse([]byte(requests))
+	c.Assert(err, jc.ErrorIsNil)
+	after, err := metricstesting.Parse([]byte(`
+requests_total{code="200"} 4
+requests_total{code="404"} 2
+requests_total{code="500"} 2
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(metricstesting.Delta(before, after), metricstesting.SeriesEquals, []metricstesting.Series{
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "200"}, Value: 1},
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "404"}, Value: 0},
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "500"}, Value: 0},
+	})
+}
+
+func (*checkerSuite) TestBadParams(c *gc.C) {
+	result, msg := metricstesting.ContainsSeries.Check([]interface{}{1, []metricstesting.Series{}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, "obtained value must be *metricstesting.Scrape, string or []byte, got int")
+
+	result, msg = metricstesting.ContainsSeries.Check([]interface{}{"foo\n", []metricstesting.Series{}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, `cannot parse obtained metrics: line 1: cannot parse sample "foo"`)
+
+	result, msg = metricstesting.ContainsSeries.Check([]interface{}{requests, "foo"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, "expected value must be []metricstesting.Series, got string")
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package metricstesting provides checkers for the metrics that code
+// exposes in the Prometheus text exposition format, so that counters,
+// gauges and histograms can be asserted on in tests.
+//
+// The metrics of a prometheus Registry can be scraped through its HTTP
+// handler:
+//
+//	scrape, err := metricstesting.ScrapeHandler(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+//	c.Assert(err, jc.ErrorIsNil)
+//	c.Check(scrape, metricstesting.ContainsSeries, []metricstesting.Series{
+//		{Name: "http_requests_total", Labels: metricstesting.Labels{"code": "200"}, Value: 3},
+//	})
+package metricstesting
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Labels holds the labels of a series, by name.
+type Labels map[string]string
+
+// String returns the labels in the exposition format, sorted by name,
+// such as `{code="200",method="GET"}`, or "" if there are none.
+func (l Labels) String() string {
+	if len(l) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%s", name, strconv.Quote(l[name]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Series holds a single sample of a metric. The series of a histogram
+// are named with the suffixes _bucket, _sum and _count, as in the
+// exposition format.
+type Series struct {
+	Name   string
+	Labels Labels
+	Value  float64
+}
+
+// String returns the series as it would appear in the exposition
+// format, such as `http_requests_total{code="200"} 3`.
+func (s Series) String() string {
+	return s.key() + " " + strconv.FormatFloat(s.Value, 'g', -1, 64)
+}
+
+// key returns the name and labels of the series, which identify it.
+func (s Series) key() string {
+	return s.Name + s.Labels.String()
+}
+
+// Scrape holds the metrics read from a Prometheus text exposition.
+type Scrape struct {
+	// Types holds the type of each metric family that was declared
+	// with a TYPE comment, such as "counter" or "histogram", by
+	// name.
+	Types map[string]string
+
+	// Series holds the series in the order in which they appeared.
+	Series []Series
+}
+
+// Parse parses metrics in the Prometheus text exposition format.
+func Parse(data []byte) (*Scrape, error) {
+	scrape := &Scrape{
+		Types: make(map[string]string),
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			fields := strings.Fields(line)
+			if len(fields) >= 4 && fields[1] == "TYPE" {
+				scrape.Types[fields[2]] = fields[3]
+			}
+			continue
+		}
+		series, err := parseSeries(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		scrape.Series = append(scrape.Series, series)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return scrape, nil
+}
+
+// ScrapeHandler scrapes the metrics served by h, such as the handler
+// returned by promhttp.HandlerFor, and parses them.
+func ScrapeHandler(h http.Handler) (*Scrape, error) {
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	// Ask for the text format rather than the protobuf one.
+	req.Header.Set("Accept", "text/plain")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return nil, fmt.Errorf("cannot scrape metrics: status %d: %s", rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+	return Parse(rec.Body.Bytes())
+}
+
+// parseSeries parses a sample line of the exposition format.
+func parseSeries(line string) (Series, error) {
+	var series Series
+	end := strings.IndexAny(line, "{ \t")
+	if end <= 0 {
+		return Series{}, fmt.Errorf("cannot parse sample %q", line)
+	}
+	series.Name, line = line[:end], line[end:]
+	if strings.HasPrefix(line, "{") {
+		labels, rest, err := parseLabels(line[1:])
+		if err != nil {
+			return Series{}, fmt.Errorf("cannot parse labels of %s: %v", series.Name, err)
+		}
+		series.Labels, line = labels, rest
+	}
+	fields := strings.Fields(line)
+	// The value may be followed by a timestamp, which is ignored.
+	if len(fields) != 1 && len(fields) != 2 {
+		return Series{}, fmt.Errorf("cannot parse value of %s from %q", series.Name, line)
+	}
+	value, err := parseValue(fields[0])
+	if err != nil {
+		return Series{}, fmt.Errorf("cannot parse value of %s: %v", series.Name, err)
+	}
+	series.Value = value
+	return series, nil
+}
+
+// parseLabels parses the labels that follow the opening brace of a
+// sample, returning them and the rest of the line after the closing
+// brace.
+func parseLabels(s string) (Labels, string, error) {
+	labels := make(Labels)
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+		eq := strings.Index(s, "=")
+		if eq <= 0 || len(s) < eq+2 || s[eq+1] != '"' {
+			return nil, "", fmt.Errorf("expected label name and quoted value in %q", s)
+		}
+		name := strings.TrimSpace(s[:eq])
+		s = s[eq+2:]
+		var value strings.Builder
+		for {
+			if s == "" {
+				return nil, "", fmt.Errorf("unterminated value of label %s", name)
+			}
+			c := s[0]
+			s = s[1:]
+			if c == '"' {
+				break
+			}
+			if c == '\\' && s != "" {
+				c, s = s[0], s[1:]
+				if c == 'n' {
+					c = '\n'
+				}
+			}
+			value.WriteByte(c)
+		}
+		labels[name] = value.String()
+		s = strings.TrimLeft(s, " \t")
+		s = strings.TrimPrefix(s, ",")
+	}
+}
+
+// parseValue parses a sample value, which may be NaN or an infinity.
+func parseValue(s string) (float64, error) {
+	switch s {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// Find returns the series with the given name whose labels include all
+// of the given ones.
+func (s *Scrape) Find(name string, labels Labels) []Series {
+	var found []Series
+	for _, series := range s.Series {
+		if series.Name == name && hasLabels(series.Labels, labels) {
+			found = append(found, series)
+		}
+	}
+	return found
+}
+
+// Value returns the value of the series with the given name and
+// exactly the given labels, and whether there is one.
+func (s *Scrape) Value(name string, labels Labels) (float64, bool) {
+	key := Series{Name: name, Labels: labels}.key()
+	for _, series := range s.Series {
+		if series.key() == key {
+			return series.Value, true
+		}
+	}
+	return 0, false
+}
+
+// Histogram holds the samples of a histogram.
+type Histogram struct {
+	// Buckets holds the cumulative count of observations in each
+	// bucket, by upper bound.
+	Buckets map[float64]float64
+
+	Sum   float64
+	Count float64
+}
+
+// Histogram returns the histogram with the given name and labels, not
+// including the le label of its buckets.
+func (s *Scrape) Histogram(name string, labels Labels) (Histogram, error) {
+	h := Histogram{
+		Buckets: make(map[float64]float64),
+	}
+	for _, series := range s.Find(name+"_bucket", labels) {
+		if len(series.Labels) != len(labels)+1 {
+			continue
+		}
+		le, err := parseValue(series.Labels["le"])
+		if err != nil {
+			return Histogram{}, fmt.Errorf("bad bucket bound of %s: %v", series, err)
+		}
+		h.Buckets[le] = series.Value
+	}
+	var ok bool
+	if h.Sum, ok = s.Value(name+"_sum", labels); !ok {
+		return Histogram{}, fmt.Errorf("histogram %s%s not found", name, labels)
+	}
+	if h.Count, ok = s.Value(name+"_count", labels); !ok {
+		return Histogram{}, fmt.Errorf("histogram %s%s has no count", name, labels)
+	}
+	return h, nil
+}
+
+// Delta returns the change in each series from before to after, such as
+// the increase in a counter over a test. Series that are not in before
+// are taken to have been zero.
+func Delta(before, after *Scrape) *Scrape {
+	previous := make(map[string]float64)
+	for _, series := range before.Series {
+		previous[series.key()] = series.Value
+	}
+	delta := &Scrape{
+		Types:  after.Types,
+		Series: make([]Series, len(after.Series)),
+	}
+	for i, series := range after.Series {
+		series.Value -= previous[series.key()]
+		delta.Series[i] = series
+	}
+	return delta
+}
+
+// hasLabels reports whether labels includes all of the wanted labels.
+func hasLabels(labels, want Labels) bool {
+	for name, value := range want {
+		if v, ok := labels[name]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package metricstesting_test
+
+import (
+	"math"
+	"net/http"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/metricstesting"
+)
+
+type metricsSuite struct{}
+
+var _ = gc.Suite(&metricsSuite{})
+
+const exposition = `
+# HELP http_requests_total The number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{code="200",method="GET"} 3
+http_requests_total{method="POST", code="500"} 1 1700000000000
+# TYPE queue_length gauge
+queue_length 7
+# TYPE request_seconds histogram
+request_seconds_bucket{le="0.1"} 2
+request_seconds_bucket{le="1"} 3
+request_seconds_bucket{le="+Inf"} 4
+request_seconds_sum 2.75
+request_seconds_count 4
+escaped{path="a \"quoted\\path\"\n"} NaN
+`
+
+func (*metricsSuite) TestParse(c *gc.C) {
+	scrape, err := metricstesting.Parse([]byte(exposition))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(scrape.Types, jc.DeepEquals, map[string]string{
+		"http_requests_total": "counter",
+		"queue_length":        "gauge",
+		"request_seconds":     "histogram",
+	})
+	c.Assert(scrape.Series[:4], jc.DeepEquals, []metricstesting.Series{
+		{Name: "http_requests_total", Labels: metricstesting.Labels{"code": "200", "method": "GET"}, Value: 3},
+		{Name: "http_requests_total", Labels: metricstesting.Labels{"code": "500", "method": "POST"}, Value: 1},
+		{Name: "queue_length", Value: 7},
+		{Name: "request_seconds_bucket", Labels: metricstesting.Labels{"le": "0.1"}, Value: 2},
+	})
+	last := scrape.Series[len(scrape.Series)-1]
+	c.Assert(last.Labels, jc.DeepEquals, metricstesting.Labels{"path": "a \"quoted\\path\"\n"})
+	c.Assert(math.IsNaN(last.Value), jc.IsTrue)
+	c.Assert(last.String(), gc.Equals, `escaped{path="a \"quoted\\path\"\n"} NaN`)
+}
+
+var parseErrorTests = []struct {
+	about string
+	data  string
+	err   string
+}{{
+	about: "no value",
+	data:  "foo\n",
+	err:   `line 1: cannot parse sample "foo"`,
+}, {
+	about: "bad value",
+	data:  "# TYPE foo counter\nfoo bar\n",
+	err:   `line 2: cannot parse value of foo: strconv.ParseFloat: parsing "bar": invalid syntax`,
+}, {
+	about: "unterminated label",
+	data:  `foo{a="b} 1`,
+	err:   `line 1: cannot parse labels of foo: unterminated value of label a`,
+}, {
+	about: "unquoted label",
+	data:  `foo{a=b} 1`,
+	err:   `line 1: cannot parse labels of foo: expected label name and quoted value in "a=b} 1"`,
+}}
+
+func (*metricsSuite) TestParseErrors(c *gc.C) {
+	for i, test := range parseErrorTests {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := metricstesting.Parse([]byte(test.data))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (*metricsSuite) TestFindAndValue(c *gc.C) {
+	scrape, err := metricstesting.Parse([]byte(exposition))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(scrape.Find("http_requests_total", nil), gc.HasLen, 2)
+	c.Assert(scrape.Find("http_requests_total", metricstesting.Labels{"code": "500"}), jc.DeepEquals, []metricstesting.Series{
+		{Name: "http_requests_total", Labels: metricstesting.Labels{"code": "500", "method": "POST"}, Value: 1},
+	})
+
+	v, ok := scrape.Value("queue_length", nil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(v, gc.Equals, 7.0)
+	_, ok = scrape.Value("http_requests_total", metricstesting.Labels{"code": "500"})
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (*metricsSuite) TestHistogram(c *gc.C) {
+	scrape, err := metricstesting.Parse([]byte(exposition))
+	c.Assert(err, jc.ErrorIsNil)
+	h, err := scrape.Histogram("request_seconds", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(h, jc.DeepEquals, metricstesting.Histogram{
+		Buckets: map[float64]float64{0.1: 2, 1: 3, math.Inf(1): 4},
+		Sum:     2.75,
+		Count:   4,
+	})
+
+	_, err = scrape.Histogram("missing", nil)
+	c.Assert(err, gc.ErrorMatches, "histogram missing not found")
+}
+
+func (*metricsSuite) TestDelta(c *gc.C) {
+	before, err := metricstesting.Parse([]byte(`
+requests_total{code="200"} 3
+queue_length 7
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	after, err := metricstesting.Parse([]byte(`
+requests_total{code="200"} 5
+requests_total{code="500"} 1
+queue_length 4
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metricstesting.Delta(before, after).Series, jc.DeepEquals, []metricstesting.Series{
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "200"}, Value: 2},
+		{Name: "requests_total", Labels: metricstesting.Labels{"code": "500"}, Value: 1},
+		{Name: "queue_length", Value: -3},
+	})
+}
+
+func (*metricsSuite) TestScrapeHandler(c *gc.C) {
+	var accept string
+	scrape, err := metricstesting.ScrapeHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		accept = req.Header.Get("Accept")
+		w.Write([]byte("queue_length 7\n"))
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(accept, gc.Equals, "text/plain")
+	c.Assert(scrape.Series, jc.DeepEquals, []metricstesting.Series{{Name: "queue_length", Value: 7}})
+
+	_, err = metricstesting.ScrapeHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "broken", http.StatusInternalServerError)
+	}))
+	c.Assert(err, gc.ErrorMatches, "cannot scrape metrics: status 500: broken")
+}
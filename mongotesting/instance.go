@@ -0,0 +1,212 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package mongotesting provides fixtures for tests that need a
+// MongoDB server.
+package mongotesting
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+// MongodPath holds the path of the mongod binary used to start
+// instances. It is taken from the TEST_MONGOD environment variable
+// when the package is initialized, and defaults to "mongod", which is
+// looked up in $PATH.
+var MongodPath = func() string {
+	if path := os.Getenv("TEST_MONGOD"); path != "" {
+		return path
+	}
+	return "mongod"
+}()
+
+// MongoURL holds the URL of an existing MongoDB server, such as one
+// running in a container, for MongoSuite to use instead of starting
+// its own. It is taken from the TEST_MONGO_URL environment variable
+// when the package is initialized.
+var MongoURL = os.Getenv("TEST_MONGO_URL")
+
+// Available reports whether MongoSuite can run: whether MongoURL is
+// set or MongodPath names an executable.
+func Available() bool {
+	if MongoURL != "" {
+		return true
+	}
+	_, err := exec.LookPath(MongodPath)
+	return err == nil
+}
+
+// Instance is a throwaway mongod process, listening on a reserved
+// loopback port and storing its data in a temporary directory:
+//
+//	inst := &mongotesting.Instance{}
+//	inst.Start(c)
+//	defer inst.Destroy()
+//	client := inst.Dial(c)
+type Instance struct {
+	// Path holds the path of the mongod binary. If it is empty,
+	// MongodPath is used.
+	Path string
+
+	// Params holds extra command line arguments for mongod.
+	Params []string
+
+	// StartTimeout holds how long to wait for mongod to accept
+	// connections. If it is zero, testing.LongWait is used.
+	StartTimeout time.Duration
+
+	// Dir holds the data directory of the running instance.
+	Dir string
+
+	// Port holds the port on which the running instance listens.
+	Port int
+
+	cmd    *exec.Cmd
+	output lockedBuffer
+	// exited is closed when the process exits, after err is set.
+	exited chan struct{}
+	err    error
+}
+
+// Addr returns the address of the running instance.
+func (inst *Instance) Addr() string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(inst.Port))
+}
+
+// URL returns the connection URL of the running instance.
+func (inst *Instance) URL() string {
+	return "mongodb://" + inst.Addr() + "/?directConnection=true"
+}
+
+// Start starts mongod and waits until it accepts connections. The test
+// is failed if it cannot be started; the failure includes mongod's
+// output. The caller should call Destroy when finished with the
+// instance.
+func (inst *Instance) Start(c *gc.C) {
+	if inst.cmd != nil {
+		c.Fatalf("mongod instance already started")
+	}
+	path := inst.Path
+	if path == "" {
+		path = MongodPath
+	}
+	inst.Dir = c.MkDir()
+	inst.Port = testing.ReservePort(c)
+	args := []string{
+		"--dbpath", inst.Dir,
+		"--port", strconv.Itoa(inst.Port),
+		"--bind_ip", "127.0.0.1",
+		"--nounixsocket",
+		"--quiet",
+	}
+	inst.output.Reset()
+	inst.cmd = exec.Command(path, append(args, inst.Params...)...)
+	inst.cmd.Stdout = &inst.output
+	inst.cmd.Stderr = &inst.output
+	if err := inst.cmd.Start(); err != nil {
+		inst.cmd = nil
+		c.Fatalf("cannot start mongod: %v", err)
+	}
+	inst.exited = make(chan struct{})
+	go func() {
+		inst.err = inst.cmd.Wait()
+		close(inst.exited)
+	}()
+	if err := inst.waitReady(); err != nil {
+		inst.Destroy()
+		c.Fatalf("mongod did not start: %v; output:\n%s", err, inst.output.String())
+	}
+}
+
+// waitReady waits until the instance answers a ping, or exits.
+func (inst *Instance) waitReady() error {
+	timeout := inst.StartTimeout
+	if timeout == 0 {
+		timeout = testing.LongWait
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	client, err := mongo.Connect(inst.clientOptions().SetServerSelectionTimeout(timeout))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+	pinged := make(chan error, 1)
+	go func() {
+		pinged <- client.Ping(ctx, readpref.Primary())
+	}()
+	select {
+	case err := <-pinged:
+		return err
+	case <-inst.exited:
+		return fmt.Errorf("mongod exited: %v", inst.err)
+	}
+}
+
+func (inst *Instance) clientOptions() *options.ClientOptions {
+	return options.Client().ApplyURI(inst.URL())
+}
+
+// Dial returns a client connected to the instance. The caller should
+// disconnect it when finished with it.
+func (inst *Instance) Dial(c *gc.C) *mongo.Client {
+	client, err := mongo.Connect(inst.clientOptions())
+	c.Assert(err, gc.IsNil)
+	return client
+}
+
+// Output returns everything mongod has written so far.
+func (inst *Instance) Output() string {
+	return inst.output.String()
+}
+
+// Destroy kills the mongod process and waits for it to exit. The data
+// directory is removed with the test's other temporary directories.
+func (inst *Instance) Destroy() {
+	if inst.cmd == nil {
+		return
+	}
+	inst.cmd.Process.Kill()
+	<-inst.exited
+	inst.cmd = nil
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use, so
+// that mongod's output can be read while it is being written.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *lockedBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
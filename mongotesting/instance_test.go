@@ -0,0 +1,61 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package mongotesting_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/mongotesting"
+)
+
+type instanceSuite struct{}
+
+var _ = gc.Suite(&instanceSuite{})
+
+// fakeMongod writes a shell script to stand in for mongod, and
+// returns its path.
+func fakeMongod(c *gc.C, script string) string {
+	if runtime.GOOS == "windows" {
+		c.Skip("fake mongod requires a shell")
+	}
+	path := filepath.Join(c.MkDir(), "mongod")
+	err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755)
+	c.Assert(err, gc.IsNil)
+	return path
+}
+
+func (s *instanceSuite) TestStartExits(c *gc.C) {
+	inst := &mongotesting.Instance{
+		Path: fakeMongod(c, `echo "bad option" >&2; exit 2`),
+	}
+	c.ExpectFailure("mongod exits")
+	inst.Start(c)
+}
+
+func (s *instanceSuite) TestStartTimeout(c *gc.C) {
+	inst := &mongotesting.Instance{
+		Path:         fakeMongod(c, "exec sleep 60"),
+		StartTimeout: 100 * time.Millisecond,
+	}
+	c.ExpectFailure("mongod never listens")
+	inst.Start(c)
+}
+
+func (s *instanceSuite) TestStartNotFound(c *gc.C) {
+	inst := &mongotesting.Instance{
+		Path: filepath.Join(c.MkDir(), "mongod"),
+	}
+	c.ExpectFailure("mongod not found")
+	inst.Start(c)
+}
+
+func (s *instanceSuite) TestDestroyNotStarted(c *gc.C) {
+	inst := &mongotesting.Instance{}
+	inst.Destroy()
+}
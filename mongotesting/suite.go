@@ -0,0 +1,87 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package mongotesting
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+// MongoSuite provides a MongoDB server for the tests in a suite, and a
+// database of their own for each test. The server is the one at
+// MongoURL if it is set, and otherwise a throwaway Instance started
+// for the suite. If neither is available, the suite's tests are
+// skipped.
+//
+// Each test gets a new, uniquely named database in DB, which is
+// dropped when the test finishes, so that tests never see each
+// other's data.
+type MongoSuite struct {
+	// Instance holds the instance started for the suite, or nil
+	// if the suite is using the server at MongoURL.
+	Instance *Instance
+
+	// Client holds a client connected to the server.
+	Client *mongo.Client
+
+	// DB holds the database for the current test.
+	DB *mongo.Database
+}
+
+func (s *MongoSuite) SetUpSuite(c *gc.C) {
+	if !Available() {
+		c.Skip("mongod not found; set TEST_MONGOD or TEST_MONGO_URL to run MongoDB tests")
+	}
+	url := MongoURL
+	if url == "" {
+		s.Instance = &Instance{}
+		s.Instance.Start(c)
+		url = s.Instance.URL()
+	}
+	client, err := mongo.Connect(options.Client().ApplyURI(url))
+	c.Assert(err, gc.IsNil)
+	s.Client = client
+	ctx, cancel := context.WithTimeout(context.Background(), testing.LongWait)
+	defer cancel()
+	err = client.Ping(ctx, readpref.Primary())
+	c.Assert(err, gc.IsNil, gc.Commentf("cannot reach MongoDB at %s", url))
+}
+
+func (s *MongoSuite) TearDownSuite(c *gc.C) {
+	if s.Client != nil {
+		err := s.Client.Disconnect(context.Background())
+		c.Check(err, gc.IsNil)
+		s.Client = nil
+	}
+	if s.Instance != nil {
+		s.Instance.Destroy()
+		s.Instance = nil
+	}
+}
+
+func (s *MongoSuite) SetUpTest(c *gc.C) {
+	buf := make([]byte, 8)
+	_, err := rand.Read(buf)
+	c.Assert(err, gc.IsNil)
+	s.DB = s.Client.Database("test_" + hex.EncodeToString(buf))
+}
+
+func (s *MongoSuite) TearDownTest(c *gc.C) {
+	if s.DB == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), testing.LongWait)
+	defer cancel()
+	err := s.DB.Drop(ctx)
+	c.Check(err, gc.IsNil)
+	s.DB = nil
+}
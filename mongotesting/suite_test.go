@@ -0,0 +1,46 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package mongotesting_test
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	"github.com/juju/testing/mongotesting"
+)
+
+type mongoSuite struct {
+	mongotesting.MongoSuite
+}
+
+var _ = gc.Suite(&mongoSuite{})
+
+func (s *mongoSuite) TestInsertFind(c *gc.C) {
+	ctx, cancel := context.WithTimeout(context.Background(), testing.LongWait)
+	defer cancel()
+	coll := s.DB.Collection("widgets")
+	_, err := coll.InsertOne(ctx, bson.M{"name": "sprocket"})
+	c.Assert(err, gc.IsNil)
+	var doc struct {
+		Name string `bson:"name"`
+	}
+	err = coll.FindOne(ctx, bson.M{}).Decode(&doc)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc.Name, gc.Equals, "sprocket")
+}
+
+func (s *mongoSuite) TestDatabasePerTest(c *gc.C) {
+	ctx, cancel := context.WithTimeout(context.Background(), testing.LongWait)
+	defer cancel()
+	// Whichever test runs first, neither sees the other's data.
+	n, err := s.DB.Collection("widgets").CountDocuments(ctx, bson.M{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(n, gc.Equals, int64(0))
+	names, err := s.Client.ListDatabaseNames(ctx, bson.M{"name": s.DB.Name()})
+	c.Assert(err, gc.IsNil)
+	c.Assert(names, gc.HasLen, 0)
+}
@@ -0,0 +1,340 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+)
+
+// MutationClass names a kind of mutation made to a sample input.
+type MutationClass string
+
+const (
+	// DropField mutations remove a field of an object.
+	DropField MutationClass = "drop field"
+
+	// TypeFlip mutations replace a value with a value of another
+	// type.
+	TypeFlip MutationClass = "type flip"
+
+	// BoundaryValue mutations replace a value with an extreme value
+	// of the same type, such as the largest integer or an empty
+	// string.
+	BoundaryValue MutationClass = "boundary value"
+
+	// Truncate mutations cut the encoded input short, leaving it
+	// malformed.
+	Truncate MutationClass = "truncate"
+)
+
+// mutationClasses holds the mutation classes in the order in which
+// their mutations are made.
+var mutationClasses = []MutationClass{DropField, TypeFlip, BoundaryValue, Truncate}
+
+// longMutationString holds the long string used as a boundary value
+// for strings.
+var longMutationString = strings.Repeat("x", 1024)
+
+// Mutation is a mutation of a sample input, made by JSONMutations,
+// YAMLMutations or ValueMutations.
+type Mutation struct {
+	// Class holds the kind of mutation.
+	Class MutationClass
+
+	// Path holds the location of the mutated value in the input, such
+	// as $.ports[0].name, where $ is the whole input. It is empty for
+	// Truncate mutations.
+	Path string
+
+	// Description describes the mutation.
+	Description string
+
+	// Data holds the mutated input, encoded in the format of the
+	// sample.
+	Data []byte
+}
+
+// String returns a description of the mutation, including its class
+// and path.
+func (m Mutation) String() string {
+	if m.Path == "" {
+		return fmt.Sprintf("%s: %s", m.Class, m.Description)
+	}
+	return fmt.Sprintf("%s at %s: %s", m.Class, m.Path, m.Description)
+}
+
+// JSONMutations returns systematic mutations of the given valid JSON
+// input. The mutations drop each field of each object, replace each
+// value with values of other types and with boundary values of its own
+// type, and truncate the encoded input.
+func JSONMutations(sample []byte) ([]Mutation, error) {
+	dec := json.NewDecoder(bytes.NewReader(sample))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("cannot decode JSON sample: %v", err)
+	}
+	return mutations(sample, normaliseData(v), json.Marshal)
+}
+
+// YAMLMutations is like JSONMutations, but for a YAML input.
+func YAMLMutations(sample []byte) ([]Mutation, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(sample, &v); err != nil {
+		return nil, fmt.Errorf("cannot decode YAML sample: %v", err)
+	}
+	return mutations(sample, normaliseData(v), yaml.Marshal)
+}
+
+// ValueMutations is like JSONMutations, but the input is the JSON
+// encoding of the given Go value, such as a struct, and the mutations
+// are encoded as JSON.
+func ValueMutations(sample interface{}) ([]Mutation, error) {
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode sample: %v", err)
+	}
+	return JSONMutations(data)
+}
+
+// mutations returns the mutations of the sample, which decodes to v,
+// encoded with marshal.
+func mutations(sample []byte, v interface{}, marshal func(interface{}) ([]byte, error)) ([]Mutation, error) {
+	byClass := make(map[MutationClass][]Mutation)
+	var err error
+	add := func(class MutationClass, path, description string, mutated interface{}) {
+		if err != nil {
+			return
+		}
+		var data []byte
+		data, err = marshal(mutated)
+		if err != nil {
+			err = fmt.Errorf("cannot encode mutation: %v", err)
+			return
+		}
+		byClass[class] = append(byClass[class], Mutation{
+			Class:       class,
+			Path:        path,
+			Description: description,
+			Data:        data,
+		})
+	}
+	mutateAt(v, "$", func(value interface{}) interface{} { return value }, add)
+	if err != nil {
+		return nil, err
+	}
+	data := bytes.TrimSpace(sample)
+	seen := make(map[int]bool)
+	for _, n := range []int{0, len(data) / 4, len(data) / 2, len(data) * 3 / 4, len(data) - 1} {
+		if n < 0 || seen[n] {
+			continue
+		}
+		seen[n] = true
+		byClass[Truncate] = append(byClass[Truncate], Mutation{
+			Class:       Truncate,
+			Description: fmt.Sprintf("cut to %d of %d bytes", n, len(data)),
+			Data:        append([]byte(nil), data[:n]...),
+		})
+	}
+	var all []Mutation
+	for _, class := range mutationClasses {
+		all = append(all, byClass[class]...)
+	}
+	return all, nil
+}
+
+// mutateAt makes the mutations of v, found at the given path, and of
+// the values within it. The replace function returns the whole input
+// with v replaced by the given value.
+func mutateAt(v interface{}, path string, replace func(interface{}) interface{}, add func(class MutationClass, path, description string, mutated interface{})) {
+	for _, flip := range typeFlips(v) {
+		add(TypeFlip, path, fmt.Sprintf("set to %s", describeMutationValue(flip)), replace(flip))
+	}
+	for _, b := range boundaryValues(v) {
+		add(BoundaryValue, path, fmt.Sprintf("set to %s", describeMutationValue(b)), replace(b))
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			dropped := make(map[string]interface{}, len(v)-1)
+			for k, value := range v {
+				if k != key {
+					dropped[k] = value
+				}
+			}
+			add(DropField, path+"."+key, "removed", replace(dropped))
+		}
+		for _, key := range keys {
+			key := key
+			mutateAt(v[key], path+"."+key, func(value interface{}) interface{} {
+				m := make(map[string]interface{}, len(v))
+				for k, kv := range v {
+					m[k] = kv
+				}
+				m[key] = value
+				return replace(m)
+			}, add)
+		}
+	case []interface{}:
+		for i := range v {
+			i := i
+			mutateAt(v[i], fmt.Sprintf("%s[%d]", path, i), func(value interface{}) interface{} {
+				s := append([]interface{}(nil), v...)
+				s[i] = value
+				return replace(s)
+			}, add)
+		}
+	}
+}
+
+// typeFlips returns values of each type other than that of v.
+func typeFlips(v interface{}) []interface{} {
+	var flips []interface{}
+	if v != nil {
+		flips = append(flips, nil)
+	}
+	if _, ok := v.(bool); !ok {
+		flips = append(flips, true)
+	}
+	switch v.(type) {
+	case int, float64:
+	default:
+		flips = append(flips, 0)
+	}
+	if _, ok := v.(string); !ok {
+		flips = append(flips, "")
+	}
+	if _, ok := v.([]interface{}); !ok {
+		flips = append(flips, []interface{}{})
+	}
+	if _, ok := v.(map[string]interface{}); !ok {
+		flips = append(flips, map[string]interface{}{})
+	}
+	return flips
+}
+
+// boundaryValues returns the extreme values of the type of v, other
+// than v itself.
+func boundaryValues(v interface{}) []interface{} {
+	var values []interface{}
+	switch v := v.(type) {
+	case int:
+		values = []interface{}{0, -1, int64(math.MaxInt64), int64(math.MinInt64)}
+	case float64:
+		values = []interface{}{0.0, -1.0, math.MaxFloat64, -math.MaxFloat64, math.SmallestNonzeroFloat64}
+	case string:
+		values = []interface{}{"", longMutationString, "é世\U0001F600\u0000"}
+	case []interface{}:
+		if len(v) > 0 {
+			values = []interface{}{[]interface{}{}}
+		}
+		if len(v) > 1 {
+			values = append(values, v[:1])
+		}
+		return values
+	case map[string]interface{}:
+		if len(v) > 0 {
+			values = []interface{}{map[string]interface{}{}}
+		}
+		return values
+	}
+	var boundary []interface{}
+	for _, b := range values {
+		// The values are all of the type of v, except for the
+		// large integers, so they can be compared as printed.
+		if fmt.Sprint(b) != fmt.Sprint(v) {
+			boundary = append(boundary, b)
+		}
+	}
+	return boundary
+}
+
+// describeMutationValue returns a short description of a value used in
+// a mutation.
+func describeMutationValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		if v == longMutationString {
+			return fmt.Sprintf("a string of %d bytes", len(v))
+		}
+		return fmt.Sprintf("%q", v)
+	case []interface{}:
+		switch len(v) {
+		case 0:
+			return "an empty array"
+		case 1:
+			return "an array of its first element"
+		}
+	case map[string]interface{}:
+		return "an empty object"
+	}
+	return fmt.Sprint(v)
+}
+
+// CheckMutations runs f for each of the given mutations, and returns
+// whether it succeeded for all of them. If it failed for any, the
+// failures are reported, with the number of mutations of each class
+// that failed, so that it is clear which kinds of bad input break the
+// invariant that f asserts:
+//
+//	mutations, err := testing.JSONMutations([]byte(`{"name": "foo", "port": 80}`))
+//	c.Assert(err, jc.ErrorIsNil)
+//	testing.CheckMutations(c, mutations, func(c *gc.C, m testing.Mutation) {
+//		_, err := ParseConfig(m.Data)
+//		c.Assert(err, gc.NotNil)
+//	})
+//
+// As f is run as a separate gocheck test for each mutation, it is
+// given its own *gc.C, which must be used in place of the test's.
+func CheckMutations(c *gc.C, mutations []Mutation, f func(c *gc.C, m Mutation)) bool {
+	total := make(map[MutationClass]int)
+	failed := make(map[MutationClass]int)
+	var failures []string
+	for _, m := range mutations {
+		total[m.Class]++
+		output, ok := checkPropertyValue(m, f)
+		if ok {
+			continue
+		}
+		failed[m.Class]++
+		failures = append(failures, fmt.Sprintf("%s\ninput:\n%s\nfailure:\n%s",
+			m, indent(string(m.Data)), indent(strings.TrimSpace(output))))
+	}
+	if len(failures) == 0 {
+		c.Logf("invariant held for %d mutations", len(mutations))
+		return true
+	}
+	var summary []string
+	for _, class := range mutationClasses {
+		if failed[class] > 0 {
+			summary = append(summary, fmt.Sprintf("\t%s: %d of %d", class, failed[class], total[class]))
+		}
+	}
+	c.Errorf("invariant broken by %d of %d mutations:\n%s\n\n%s",
+		len(failures), len(mutations), strings.Join(summary, "\n"), strings.Join(failures, "\n\n"))
+	return false
+}
+
+// AssertMutations is like CheckMutations, but stops the test if f
+// failed for any mutation.
+func AssertMutations(c *gc.C, mutations []Mutation, f func(c *gc.C, m Mutation)) {
+	if !CheckMutations(c, mutations, f) {
+		c.FailNow()
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type mutateSuite struct{}
+
+var _ = gc.Suite(&mutateSuite{})
+
+// mutationStrings returns the descriptions and data of the given
+// mutations.
+func mutationStrings(mutations []testing.Mutation) []string {
+	s := make([]string, len(mutations))
+	for i, m := range mutations {
+		s[i] = fmt.Sprintf("%s: %s", m, m.Data)
+	}
+	return s
+}
+
+func (*mutateSuite) TestJSONMutations(c *gc.C) {
+	mutations, err := testing.JSONMutations([]byte(`{"port": 80, "hosts": [true, false]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mutationStrings(mutations), jc.DeepEquals, []string{
+		`drop field at $.hosts: removed: {"port":80}`,
+		`drop field at $.port: removed: {"hosts":[true,false]}`,
+		`type flip at $: set to null: null`,
+		`type flip at $: set to true: true`,
+		`type flip at $: set to 0: 0`,
+		`type flip at $: set to "": ""`,
+		`type flip at $: set to an empty array: []`,
+		`type flip at $.hosts: set to null: {"hosts":null,"port":80}`,
+		`type flip at $.hosts: set to true: {"hosts":true,"port":80}`,
+		`type flip at $.hosts: set to 0: {"hosts":0,"port":80}`,
+		`type flip at $.hosts: set to "": {"hosts":"","port":80}`,
+		`type flip at $.hosts: set to an empty object: {"hosts":{},"port":80}`,
+		`type flip at $.hosts[0]: set to null: {"hosts":[null,false],"port":80}`,
+		`type flip at $.hosts[0]: set to 0: {"hosts":[0,false],"port":80}`,
+		`type flip at $.hosts[0]: set to "": {"hosts":["",false],"port":80}`,
+		`type flip at $.hosts[0]: set to an empty array: {"hosts":[[],false],"port":80}`,
+		`type flip at $.hosts[0]: set to an empty object: {"hosts":[{},false],"port":80}`,
+		`type flip at $.hosts[1]: set to null: {"hosts":[true,null],"port":80}`,
+		`type flip at $.hosts[1]: set to 0: {"hosts":[true,0],"port":80}`,
+		`type flip at $.hosts[1]: set to "": {"hosts":[true,""],"port":80}`,
+		`type flip at $.hosts[1]: set to an empty array: {"hosts":[true,[]],"port":80}`,
+		`type flip at $.hosts[1]: set to an empty object: {"hosts":[true,{}],"port":80}`,
+		`type flip at $.port: set to null: {"hosts":[true,false],"port":null}`,
+		`type flip at $.port: set to true: {"hosts":[true,false],"port":true}`,
+		`type flip at $.port: set to "": {"hosts":[true,false],"port":""}`,
+		`type flip at $.port: set to an empty array: {"hosts":[true,false],"port":[]}`,
+		`type flip at $.port: set to an empty object: {"hosts":[true,false],"port":{}}`,
+		`boundary value at $: set to an empty object: {}`,
+		`boundary value at $.hosts: set to an empty array: {"hosts":[],"port":80}`,
+		`boundary value at $.hosts: set to an array of its first element: {"hosts":[true],"port":80}`,
+		`boundary value at $.port: set to 0: {"hosts":[true,false],"port":0}`,
+		`boundary value at $.port: set to -1: {"hosts":[true,false],"port":-1}`,
+		`boundary value at $.port: set to 9223372036854775807: {"hosts":[true,false],"port":9223372036854775807}`,
+		`boundary value at $.port: set to -9223372036854775808: {"hosts":[true,false],"port":-9223372036854775808}`,
+		`truncate: cut to 0 of 36 bytes: `,
+		`truncate: cut to 9 of 36 bytes: {"port": `,
+		`truncate: cut to 18 of 36 bytes: {"port": 80, "host`,
+		`truncate: cut to 27 of 36 bytes: {"port": 80, "hosts": [true`,
+		`truncate: cut to 35 of 36 bytes: {"port": 80, "hosts": [true, false]`,
+	})
+}
+
+func (*mutateSuite) TestBoundaryValuesSkipSample(c *gc.C) {
+	mutations, err := testing.JSONMutations([]byte(`{"count": 0, "ratio": -1.0, "name": ""}`))
+	c.Assert(err, jc.ErrorIsNil)
+	var boundary []string
+	for _, m := range mutations {
+		if m.Class == testing.BoundaryValue {
+			boundary = append(boundary, m.String())
+		}
+	}
+	c.Assert(boundary, jc.DeepEquals, []string{
+		`boundary value at $: set to an empty object`,
+		`boundary value at $.count: set to -1`,
+		`boundary value at $.count: set to 9223372036854775807`,
+		`boundary value at $.count: set to -9223372036854775808`,
+		`boundary value at $.name: set to a string of 1024 bytes`,
+		`boundary value at $.name: set to "é世😀\x00"`,
+		`boundary value at $.ratio: set to 0`,
+		`boundary value at $.ratio: set to 1.7976931348623157e+308`,
+		`boundary value at $.ratio: set to -1.7976931348623157e+308`,
+		`boundary value at $.ratio: set to 5e-324`,
+	})
+}
+
+func (*mutateSuite) TestYAMLMutations(c *gc.C) {
+	mutations, err := testing.YAMLMutations([]byte("name: foo\nport: 80\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mutations[0].String(), gc.Equals, "drop field at $.name: removed")
+	c.Assert(string(mutations[0].Data), gc.Equals, "port: 80\n")
+	for _, m := range mutations {
+		if m.String() == "type flip at $.port: set to an empty array" {
+			c.Assert(string(m.Data), gc.Equals, "name: foo\nport: []\n")
+			return
+		}
+	}
+	c.Fatalf("type flip of port not found")
+}
+
+type mutateConfig struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+func (*mutateSuite) TestValueMutations(c *gc.C) {
+	mutations, err := testing.ValueMutations(mutateConfig{Name: "foo", Port: 80})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mutations[0].String(), gc.Equals, "drop field at $.name: removed")
+	c.Assert(string(mutations[0].Data), gc.Equals, `{"port":80}`)
+}
+
+func (*mutateSuite) TestMutationsErrors(c *gc.C) {
+	_, err := testing.JSONMutations([]byte(`{"port":`))
+	c.Assert(err, gc.ErrorMatches, `cannot decode JSON sample: unexpected EOF`)
+	_, err = testing.YAMLMutations([]byte("port: [\n"))
+	c.Assert(err, gc.ErrorMatches, `cannot decode YAML sample: .*`)
+	_, err = testing.ValueMutations(func() {})
+	c.Assert(err, gc.ErrorMatches, `cannot encode sample: json: unsupported type: func\(\)`)
+}
+
+// parseMutateConfig parses a configuration, but does not check that
+// the port is in range.
+func parseMutateConfig(data []byte) (mutateConfig, error) {
+	var config mutateConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&config); err != nil {
+		return config, err
+	}
+	if config.Name == "" {
+		return config, fmt.Errorf("no name")
+	}
+	return config, nil
+}
+
+func (*mutateSuite) TestCheckMutationsHolds(c *gc.C) {
+	mutations, err := testing.ValueMutations(mutateConfig{Name: "foo", Port: 80})
+	c.Assert(err, jc.ErrorIsNil)
+	ok := testing.CheckMutations(c, mutations, func(c *gc.C, m testing.Mutation) {
+		// The parser must not panic.
+		parseMutateConfig(m.Data)
+	})
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(c.GetTestLog(), gc.Equals, fmt.Sprintf("invariant held for %d mutations\n", len(mutations)))
+}
+
+// failingMutationsSuite is run by TestCheckMutationsFails rather than
+// being registered with gocheck.
+type failingMutationsSuite struct{}
+
+func (*failingMutationsSuite) TestMutations(c *gc.C) {
+	mutations, err := testing.ValueMutations(mutateConfig{Name: "foo", Port: 80})
+	c.Assert(err, jc.ErrorIsNil)
+	testing.AssertMutations(c, mutations, func(c *gc.C, m testing.Mutation) {
+		config, err := parseMutateConfig(m.Data)
+		if err != nil {
+			return
+		}
+		c.Assert(config.Port >= 0 && config.Port < 65536, jc.IsTrue)
+	})
+	c.Fatalf("AssertMutations did not stop the test")
+}
+
+func (*mutateSuite) TestCheckMutationsFails(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&failingMutationsSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, `(?s).*invariant broken by 3 of \d+ mutations:\n`+
+		`\tboundary value: 3 of \d+\n\n`+
+		`boundary value at \$\.port: set to -1\ninput:\n\t\{"name":"foo","port":-1\}\nfailure:\n.*`+
+		`boundary value at \$\.port: set to 9223372036854775807\n.*`+
+		`boundary value at \$\.port: set to -9223372036854775808\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*AssertMutations did not stop the test.*`)
+}
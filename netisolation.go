@@ -0,0 +1,115 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+
+	gc "gopkg.in/check.v1"
+)
+
+// NetworkIsolationSuite prevents tests from reaching the network
+// beyond the loopback interface. For the duration of each test, any
+// attempt to dial a non-loopback address through http.DefaultTransport
+// fails immediately with an error naming the destination and holding
+// the stack of the dialing goroutine, and DNS lookups through
+// net.DefaultResolver fail, although names in /etc/hosts, such as
+// localhost, still resolve.
+//
+// Go provides no hook for net.Dial itself, so code that dials directly
+// or uses its own transport is not covered; such code can use
+// LoopbackOnlyDialContext or PatchLoopbackOnlyTransport instead.
+type NetworkIsolationSuite struct {
+	restore Restorer
+}
+
+func (s *NetworkIsolationSuite) SetUpSuite(c *gc.C) {}
+
+func (s *NetworkIsolationSuite) TearDownSuite(c *gc.C) {}
+
+func (s *NetworkIsolationSuite) SetUpTest(c *gc.C) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		c.Fatalf("http.DefaultTransport is a %T, not an *http.Transport", http.DefaultTransport)
+	}
+	restoreTransport := PatchLoopbackOnlyTransport(transport)
+	restoreResolver := PatchValue(&net.DefaultResolver, &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return nil, fmt.Errorf("test attempted DNS lookup via %s %q from:\n%s", network, address, debug.Stack())
+		},
+	})
+	s.restore = func() {
+		restoreResolver()
+		restoreTransport()
+	}
+}
+
+func (s *NetworkIsolationSuite) TearDownTest(c *gc.C) {
+	if s.restore != nil {
+		s.restore()
+		s.restore = nil
+	}
+}
+
+// DialContextFunc is the type of net.Dialer.DialContext.
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// LoopbackOnlyDialContext returns a dial function that calls dial only
+// for loopback and unix socket addresses, and fails for any other
+// address with an error naming the destination and holding the
+// dialing goroutine's stack. If dial is nil, a zero net.Dialer is used.
+func LoopbackOnlyDialContext(dial DialContextFunc) DialContextFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if !isLoopbackAddress(network, address) {
+			return nil, dialRefusedError(network, address)
+		}
+		return dial(ctx, network, address)
+	}
+}
+
+// PatchLoopbackOnlyTransport patches the dial functions of the given
+// transport so that it can only connect to loopback addresses, as
+// LoopbackOnlyDialContext does, and returns a function that restores
+// them.
+func PatchLoopbackOnlyTransport(t *http.Transport) Restorer {
+	restoreDial := PatchValue(&t.DialContext, LoopbackOnlyDialContext(t.DialContext))
+	if t.DialTLSContext == nil {
+		return restoreDial
+	}
+	restoreDialTLS := PatchValue(&t.DialTLSContext, LoopbackOnlyDialContext(t.DialTLSContext))
+	return func() {
+		restoreDialTLS()
+		restoreDial()
+	}
+}
+
+// isLoopbackAddress reports whether the given address, in the form
+// accepted by net.Dial, refers to the local host.
+func isLoopbackAddress(network, address string) bool {
+	switch network {
+	case "unix", "unixgram", "unixpacket":
+		return true
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func dialRefusedError(network, address string) error {
+	return fmt.Errorf("test attempted to dial non-loopback address %s %q from:\n%s", network, address, debug.Stack())
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type networkIsolationSuite struct {
+	testing.NetworkIsolationSuite
+}
+
+var _ = gc.Suite(&networkIsolationSuite{})
+
+func (s *networkIsolationSuite) TestLoopbackAllowed(c *gc.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(body), gc.Equals, "hello")
+}
+
+func (s *networkIsolationSuite) TestOutboundRefused(c *gc.C) {
+	_, err := http.Get("http://192.0.2.1/")
+	c.Assert(err, gc.ErrorMatches, `(?s).*test attempted to dial non-loopback address tcp "192.0.2.1:80" from:\n.*`)
+}
+
+func (s *networkIsolationSuite) TestDNSLookupRefused(c *gc.C) {
+	_, err := net.LookupHost("example.invalid")
+	c.Assert(err, gc.ErrorMatches, `(?s).*test attempted DNS lookup via .*`)
+}
+
+type loopbackDialSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&loopbackDialSuite{})
+
+var errDialed = errors.New("dialed")
+
+func dialRecorder(dialed *[]string) testing.DialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		*dialed = append(*dialed, address)
+		return nil, errDialed
+	}
+}
+
+func (s *loopbackDialSuite) TestLoopbackOnlyDialContext(c *gc.C) {
+	var dialed []string
+	dial := testing.LoopbackOnlyDialContext(dialRecorder(&dialed))
+	for _, addr := range []string{"127.0.0.1:80", "[::1]:443", "localhost:8080", "127.1.2.3:22"} {
+		_, err := dial(context.Background(), "tcp", addr)
+		c.Check(err, gc.Equals, errDialed)
+	}
+	_, err := dial(context.Background(), "unix", "/tmp/socket")
+	c.Check(err, gc.Equals, errDialed)
+	for _, addr := range []string{"10.0.0.1:80", "example.com:443", "[2001:db8::1]:80"} {
+		_, err := dial(context.Background(), "tcp", addr)
+		c.Check(err, gc.ErrorMatches, `(?s)test attempted to dial non-loopback address tcp "`+regexp.QuoteMeta(addr)+`" from:\n.*`)
+	}
+	c.Assert(dialed, jc.DeepEquals, []string{"127.0.0.1:80", "[::1]:443", "localhost:8080", "127.1.2.3:22", "/tmp/socket"})
+}
+
+func (s *loopbackDialSuite) TestPatchLoopbackOnlyTransport(c *gc.C) {
+	var dialed []string
+	t := &http.Transport{DialContext: dialRecorder(&dialed)}
+	restore := testing.PatchLoopbackOnlyTransport(t)
+	_, err := t.DialContext(context.Background(), "tcp", "10.0.0.1:80")
+	c.Assert(err, gc.ErrorMatches, `(?s)test attempted to dial non-loopback address.*`)
+	c.Assert(dialed, gc.HasLen, 0)
+
+	restore()
+	_, err = t.DialContext(context.Background(), "tcp", "10.0.0.1:80")
+	c.Assert(err, gc.Equals, errDialed)
+	c.Assert(dialed, jc.DeepEquals, []string{"10.0.0.1:80"})
+}
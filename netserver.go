@@ -0,0 +1,320 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// TCPServer is a TCP server listening on an ephemeral loopback port,
+// for testing clients of low-level protocols. It either echoes back
+// what it receives or silently swallows it, and records the bytes
+// received on all connections.
+type TCPServer struct {
+	listener net.Listener
+	echo     bool
+
+	// mu guards the fields below it.
+	mu sync.Mutex
+	// changed is closed and replaced whenever a connection is
+	// accepted or data is received.
+	changed chan struct{}
+	closed  bool
+	// conns holds the connections currently open.
+	conns map[net.Conn]bool
+	// accepted holds the number of connections accepted so far.
+	accepted int
+	// received holds the bytes received from all connections, in
+	// the order they arrived.
+	received []byte
+	// disconnectAfter holds the number of bytes after which each
+	// connection is closed, or zero.
+	disconnectAfter int
+}
+
+// NewTCPEchoServer returns a running TCPServer that writes everything
+// it receives back to the sender. The caller should call Close when
+// finished with it.
+func NewTCPEchoServer(c *gc.C) *TCPServer {
+	return newTCPServer(c, true)
+}
+
+// NewTCPSinkServer returns a running TCPServer that discards
+// everything it receives. The caller should call Close when finished
+// with it.
+func NewTCPSinkServer(c *gc.C) *TCPServer {
+	return newTCPServer(c, false)
+}
+
+func newTCPServer(c *gc.C, echo bool) *TCPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s := &TCPServer{
+		listener: listener,
+		echo:     echo,
+		changed:  make(chan struct{}),
+		conns:    make(map[net.Conn]bool),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if !s.addConn(conn) {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// Addr returns the address of the server.
+func (s *TCPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Received returns the bytes received so far on all connections.
+func (s *TCPServer) Received() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte(nil), s.received...)
+}
+
+// Accepted returns the number of connections accepted so far.
+func (s *TCPServer) Accepted() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+// WaitReceived waits until at least n bytes have been received, and
+// returns all the bytes received.
+func (s *TCPServer) WaitReceived(c *gc.C, n int) []byte {
+	var received []byte
+	ok := waitChanged(&s.mu, func() (bool, chan struct{}) {
+		received = append([]byte(nil), s.received...)
+		return len(received) >= n, s.changed
+	})
+	if !ok {
+		c.Fatalf("timed out waiting for %d bytes; got %q", n, received)
+	}
+	return received
+}
+
+// WaitAccepted waits until at least n connections have been accepted.
+func (s *TCPServer) WaitAccepted(c *gc.C, n int) {
+	var accepted int
+	ok := waitChanged(&s.mu, func() (bool, chan struct{}) {
+		accepted = s.accepted
+		return accepted >= n, s.changed
+	})
+	if !ok {
+		c.Fatalf("timed out waiting for %d connections; got %d", n, accepted)
+	}
+}
+
+// DisconnectAfter causes each connection to be closed by the server
+// once it has received n bytes on that connection. Bytes beyond the
+// limit are discarded, not recorded or echoed. If n is zero,
+// connections are not closed.
+func (s *TCPServer) DisconnectAfter(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disconnectAfter = n
+}
+
+// CloseConns closes all the connections that are currently open. The
+// server continues to accept new connections.
+func (s *TCPServer) CloseConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// Close stops the server and closes any open connections.
+func (s *TCPServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	return s.listener.Close()
+}
+
+// addConn records a newly accepted connection, and reports whether
+// the server is still open.
+func (s *TCPServer) addConn(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		conn.Close()
+		return false
+	}
+	s.conns[conn] = true
+	s.accepted++
+	s.notify()
+	return true
+}
+
+func (s *TCPServer) serve(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	total := 0
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := conn.Read(buf)
+		data := buf[:n]
+		s.mu.Lock()
+		limit := s.disconnectAfter
+		if limit > 0 && total+len(data) > limit {
+			data = data[:limit-total]
+		}
+		total += len(data)
+		s.received = append(s.received, data...)
+		if len(data) > 0 {
+			s.notify()
+		}
+		s.mu.Unlock()
+		if s.echo && len(data) > 0 {
+			if _, err := conn.Write(data); err != nil {
+				return
+			}
+		}
+		if err != nil || (limit > 0 && total >= limit) {
+			return
+		}
+	}
+}
+
+// notify wakes any goroutines waiting for a change.
+// It must be called with s.mu held.
+func (s *TCPServer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// UDPServer is a UDP server listening on an ephemeral loopback port,
+// for testing clients of datagram protocols such as syslog and statsd.
+// It either echoes each datagram back to its sender or silently
+// swallows it, and records the datagrams received.
+type UDPServer struct {
+	conn net.PacketConn
+	echo bool
+
+	// mu guards the fields below it.
+	mu sync.Mutex
+	// changed is closed and replaced whenever a datagram is
+	// received.
+	changed   chan struct{}
+	datagrams [][]byte
+}
+
+// NewUDPEchoServer returns a running UDPServer that sends each
+// datagram it receives back to the sender. The caller should call
+// Close when finished with it.
+func NewUDPEchoServer(c *gc.C) *UDPServer {
+	return newUDPServer(c, true)
+}
+
+// NewUDPSinkServer returns a running UDPServer that discards the
+// datagrams it receives. The caller should call Close when finished
+// with it.
+func NewUDPSinkServer(c *gc.C) *UDPServer {
+	return newUDPServer(c, false)
+}
+
+func newUDPServer(c *gc.C, echo bool) *UDPServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s := &UDPServer{
+		conn:    conn,
+		echo:    echo,
+		changed: make(chan struct{}),
+	}
+	go func() {
+		buf := make([]byte, 64*1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			datagram := append([]byte(nil), buf[:n]...)
+			s.mu.Lock()
+			s.datagrams = append(s.datagrams, datagram)
+			close(s.changed)
+			s.changed = make(chan struct{})
+			s.mu.Unlock()
+			if echo {
+				conn.WriteTo(datagram, addr)
+			}
+		}
+	}()
+	return s
+}
+
+// Addr returns the address of the server.
+func (s *UDPServer) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// Datagrams returns the datagrams received so far.
+func (s *UDPServer) Datagrams() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.datagrams...)
+}
+
+// WaitDatagrams waits until at least n datagrams have been received,
+// and returns all the datagrams received.
+func (s *UDPServer) WaitDatagrams(c *gc.C, n int) [][]byte {
+	var datagrams [][]byte
+	ok := waitChanged(&s.mu, func() (bool, chan struct{}) {
+		datagrams = append([][]byte(nil), s.datagrams...)
+		return len(datagrams) >= n, s.changed
+	})
+	if !ok {
+		c.Fatalf("timed out waiting for %d datagrams; got %q", n, datagrams)
+	}
+	return datagrams
+}
+
+// Close stops the server.
+func (s *UDPServer) Close() error {
+	return s.conn.Close()
+}
+
+// waitChanged waits until check, called with mu held, returns true,
+// waiting between calls on the channel that it returns. It reports
+// whether check succeeded before LongWait elapsed.
+func waitChanged(mu *sync.Mutex, check func() (bool, chan struct{})) bool {
+	timeout := time.After(LongWait)
+	for {
+		mu.Lock()
+		done, changed := check()
+		mu.Unlock()
+		if done {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			return false
+		}
+	}
+}
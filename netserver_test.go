@@ -0,0 +1,138 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type netServerSuite struct{}
+
+var _ = gc.Suite(&netServerSuite{})
+
+func (*netServerSuite) TestTCPEchoServer(c *gc.C) {
+	srv := testing.NewTCPEchoServer(c)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	_, err = conn.Write([]byte("hello"))
+	c.Assert(err, jc.ErrorIsNil)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(buf), gc.Equals, "hello")
+
+	conn2, err := net.Dial("tcp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn2.Close()
+	_, err = conn2.Write([]byte(" world"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(string(srv.WaitReceived(c, 11)), gc.Equals, "hello world")
+	srv.WaitAccepted(c, 2)
+	c.Assert(srv.Accepted(), gc.Equals, 2)
+}
+
+func (*netServerSuite) TestTCPSinkServer(c *gc.C) {
+	srv := testing.NewTCPSinkServer(c)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	_, err = conn.Write([]byte("<13>msg"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(srv.WaitReceived(c, 7)), gc.Equals, "<13>msg")
+
+	// Nothing is sent back; closing our side gets EOF.
+	conn.(*net.TCPConn).CloseWrite()
+	data, err := ioutil.ReadAll(conn)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.HasLen, 0)
+	c.Assert(string(srv.Received()), gc.Equals, "<13>msg")
+}
+
+func (*netServerSuite) TestTCPDisconnectAfter(c *gc.C) {
+	srv := testing.NewTCPEchoServer(c)
+	defer srv.Close()
+	srv.DisconnectAfter(4)
+
+	conn, err := net.Dial("tcp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	_, err = conn.Write([]byte("abcdef"))
+	c.Assert(err, jc.ErrorIsNil)
+	data, _ := ioutil.ReadAll(conn)
+	c.Assert(string(data), gc.Equals, "abcd")
+	c.Assert(string(srv.Received()), gc.Equals, "abcd")
+}
+
+func (*netServerSuite) TestTCPCloseConns(c *gc.C) {
+	srv := testing.NewTCPEchoServer(c)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	srv.WaitAccepted(c, 1)
+	srv.CloseConns()
+	assertEOF(c, conn)
+
+	// New connections are still accepted.
+	conn, err = net.Dial("tcp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	assertEcho(c, conn)
+}
+
+func (*netServerSuite) TestTCPClose(c *gc.C) {
+	srv := testing.NewTCPEchoServer(c)
+	conn, err := net.Dial("tcp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	srv.WaitAccepted(c, 1)
+	srv.Close()
+	assertEOF(c, conn)
+	_, err = net.Dial("tcp", srv.Addr())
+	c.Assert(err, gc.ErrorMatches, ".*connection refused")
+}
+
+func (*netServerSuite) TestUDPEchoServer(c *gc.C) {
+	srv := testing.NewUDPEchoServer(c)
+	defer srv.Close()
+
+	conn, err := net.Dial("udp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	_, err = conn.Write([]byte("metric:1|c"))
+	c.Assert(err, jc.ErrorIsNil)
+	buf := make([]byte, 100)
+	n, err := conn.Read(buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(buf[:n]), gc.Equals, "metric:1|c")
+	c.Assert(srv.Datagrams(), jc.DeepEquals, [][]byte{[]byte("metric:1|c")})
+}
+
+func (*netServerSuite) TestUDPSinkServer(c *gc.C) {
+	srv := testing.NewUDPSinkServer(c)
+	defer srv.Close()
+
+	conn, err := net.Dial("udp", srv.Addr())
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	for _, m := range []string{"a:1|c", "b:2|g"} {
+		_, err = conn.Write([]byte(m))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(srv.WaitDatagrams(c, 2), jc.DeepEquals, [][]byte{[]byte("a:1|c"), []byte("b:2|g")})
+}
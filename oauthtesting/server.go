@@ -0,0 +1,570 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package oauthtesting provides a fake OAuth 2.0 and OpenID Connect
+// authorization server, so that code that obtains or checks bearer
+// tokens can be tested end to end over loopback.
+package oauthtesting
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// Client holds the registration of an OAuth client with a Server.
+type Client struct {
+	// ID and Secret hold the client's credentials.
+	ID     string
+	Secret string
+
+	// Scopes holds the scopes that the client may request. If it
+	// is nil, any scope may be requested.
+	Scopes []string
+
+	// RedirectURIs holds the URIs to which the authorization
+	// endpoint may redirect for the client.
+	RedirectURIs []string
+}
+
+// Error describes an OAuth error response, as used to inject failures
+// with Server.FailTokenRequests.
+type Error struct {
+	// Status holds the HTTP status of the response. If it is zero,
+	// http.StatusBadRequest is used.
+	Status int
+
+	// Code holds the OAuth error code, such as "invalid_grant" or
+	// "temporarily_unavailable".
+	Code string
+
+	// Description holds a human-readable description of the error.
+	Description string
+}
+
+// Claims holds the claims of tokens issued by a Server.
+type Claims struct {
+	Issuer   string `json:"iss"`
+	Subject  string `json:"sub"`
+	Audience string `json:"aud"`
+	IssuedAt int64  `json:"iat"`
+	Expiry   int64  `json:"exp"`
+	ID       string `json:"jti,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	Nonce    string `json:"nonce,omitempty"`
+}
+
+// Scopes returns the scopes in the claims' scope.
+func (claims Claims) Scopes() []string {
+	return strings.Fields(claims.Scope)
+}
+
+// TokenResponse holds a successful response from the token endpoint.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// Server is a fake OAuth 2.0 authorization server and OpenID
+// provider. It serves discovery, JWKS, authorization and token
+// endpoints, and issues JWT access tokens signed with ES256:
+//
+//	srv := oauthtesting.NewServer(c)
+//	defer srv.Close()
+//	srv.AddClient(oauthtesting.Client{ID: "svc", Secret: "s3cret", Scopes: []string{"read"}})
+//	... configure the code under test with srv.URL as its issuer ...
+//
+// It supports the client_credentials, authorization_code and
+// refresh_token grants. The authorization endpoint approves every
+// valid request immediately, on behalf of Subject, without any user
+// interaction.
+//
+// Token times are taken from Clock, so that a test using a
+// testclock.Clock can make tokens expire.
+type Server struct {
+	*httptest.Server
+
+	// Clock is used for the times in issued tokens. If it is nil,
+	// clock.WallClock is used.
+	Clock clock.Clock
+
+	// TokenLifetime holds the lifetime of issued access and ID
+	// tokens. If it is zero, an hour is used.
+	TokenLifetime time.Duration
+
+	// Subject holds the subject of tokens issued through the
+	// authorization endpoint. If it is empty, "user" is used.
+	Subject string
+
+	key   *ecdsa.PrivateKey
+	keyID string
+
+	mu       sync.Mutex
+	clients  map[string]Client
+	failures []Error
+	// codes and refreshTokens map issued codes and refresh tokens
+	// to the grants they represent.
+	codes         map[string]grant
+	refreshTokens map[string]grant
+}
+
+// grant holds what an authorization code or refresh token grants.
+type grant struct {
+	clientID    string
+	subject     string
+	scopes      []string
+	redirectURI string
+	nonce       string
+}
+
+// NewServer starts and returns a new Server with no clients. The
+// caller should call Close when finished with it.
+func NewServer(c *gc.C) *Server {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, gc.IsNil)
+	srv := &Server{
+		key:           key,
+		keyID:         randomString(c, 8),
+		clients:       make(map[string]Client),
+		codes:         make(map[string]grant),
+		refreshTokens: make(map[string]grant),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", srv.serveDiscovery)
+	mux.HandleFunc("/jwks", srv.serveJWKS)
+	mux.HandleFunc("/authorize", srv.serveAuthorize)
+	mux.HandleFunc("/token", srv.serveToken)
+	srv.Server = httptest.NewServer(mux)
+	return srv
+}
+
+// AddClient registers a client, replacing any with the same ID.
+func (srv *Server) AddClient(client Client) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.clients[client.ID] = client
+}
+
+// FailTokenRequests causes the next n requests to the token endpoint
+// to fail with the given error.
+func (srv *Server) FailTokenRequests(n int, e Error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for i := 0; i < n; i++ {
+		srv.failures = append(srv.failures, e)
+	}
+}
+
+// Issuer returns the issuer identifier of the server, which is its
+// URL.
+func (srv *Server) Issuer() string {
+	return srv.URL
+}
+
+// TokenURL returns the URL of the token endpoint.
+func (srv *Server) TokenURL() string {
+	return srv.URL + "/token"
+}
+
+// AuthorizeURL returns the URL of the authorization endpoint.
+func (srv *Server) AuthorizeURL() string {
+	return srv.URL + "/authorize"
+}
+
+// JWKSURL returns the URL of the JSON Web Key Set endpoint.
+func (srv *Server) JWKSURL() string {
+	return srv.URL + "/jwks"
+}
+
+// Token returns an access token issued to the given client for the
+// given scopes, as if the client had used the client_credentials
+// grant. It is useful for testing services that require a bearer
+// token, without going through the token endpoint.
+func (srv *Server) Token(c *gc.C, clientID string, scopes ...string) string {
+	token, err := srv.newToken(clientID, clientID, scopes, "")
+	c.Assert(err, gc.IsNil)
+	return token
+}
+
+// SignClaims returns a token holding the given claims, signed by the
+// server's key. It can be used to make tokens with unusual claims,
+// such as expired tokens or tokens for other audiences.
+func (srv *Server) SignClaims(c *gc.C, claims Claims) string {
+	token, err := srv.sign(claims)
+	c.Assert(err, gc.IsNil)
+	return token
+}
+
+// VerifyToken checks that the token was signed by the server and has
+// not expired, and returns its claims.
+func (srv *Server) VerifyToken(c *gc.C, token string) Claims {
+	claims, err := srv.verify(token)
+	c.Assert(err, gc.IsNil, gc.Commentf("invalid token %q", token))
+	return claims
+}
+
+func (srv *Server) clock() clock.Clock {
+	if srv.Clock == nil {
+		return clock.WallClock
+	}
+	return srv.Clock
+}
+
+func (srv *Server) tokenLifetime() time.Duration {
+	if srv.TokenLifetime == 0 {
+		return time.Hour
+	}
+	return srv.TokenLifetime
+}
+
+func (srv *Server) subject() string {
+	if srv.Subject == "" {
+		return "user"
+	}
+	return srv.Subject
+}
+
+func (srv *Server) serveDiscovery(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                srv.Issuer(),
+		"authorization_endpoint":                srv.AuthorizeURL(),
+		"token_endpoint":                        srv.TokenURL(),
+		"jwks_uri":                              srv.JWKSURL(),
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "client_credentials", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"ES256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+	})
+}
+
+func (srv *Server) serveJWKS(w http.ResponseWriter, req *http.Request) {
+	pub := srv.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "EC",
+			"crv": "P-256",
+			"alg": "ES256",
+			"use": "sig",
+			"kid": srv.keyID,
+			"x":   encodeSegment(pub.X.FillBytes(make([]byte, 32))),
+			"y":   encodeSegment(pub.Y.FillBytes(make([]byte, 32))),
+		}},
+	})
+}
+
+func (srv *Server) serveAuthorize(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	srv.mu.Lock()
+	client, ok := srv.clients[q.Get("client_id")]
+	srv.mu.Unlock()
+	redirectURI := q.Get("redirect_uri")
+	if !ok || !contains(client.RedirectURIs, redirectURI) {
+		// The redirect URI cannot be trusted, so the error is
+		// shown rather than redirected.
+		http.Error(w, "unknown client or redirect URI", http.StatusBadRequest)
+		return
+	}
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect URI", http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	if state := q.Get("state"); state != "" {
+		params.Set("state", state)
+	}
+	scopes := strings.Fields(q.Get("scope"))
+	switch {
+	case q.Get("response_type") != "code":
+		params.Set("error", "unsupported_response_type")
+	case !allowed(client, scopes):
+		params.Set("error", "invalid_scope")
+	default:
+		code, err := randomToken()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		srv.mu.Lock()
+		srv.codes[code] = grant{
+			clientID:    client.ID,
+			subject:     srv.subject(),
+			scopes:      scopes,
+			redirectURI: redirectURI,
+			nonce:       q.Get("nonce"),
+		}
+		srv.mu.Unlock()
+		params.Set("code", code)
+	}
+	target.RawQuery = params.Encode()
+	http.Redirect(w, req, target.String(), http.StatusFound)
+}
+
+func (srv *Server) serveToken(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	srv.mu.Lock()
+	if len(srv.failures) > 0 {
+		e := srv.failures[0]
+		srv.failures = srv.failures[1:]
+		srv.mu.Unlock()
+		writeError(w, e)
+		return
+	}
+	srv.mu.Unlock()
+
+	if err := req.ParseForm(); err != nil {
+		writeError(w, Error{Code: "invalid_request", Description: err.Error()})
+		return
+	}
+	clientID, secret, ok := req.BasicAuth()
+	if !ok {
+		clientID, secret = req.PostForm.Get("client_id"), req.PostForm.Get("client_secret")
+	}
+	srv.mu.Lock()
+	client, ok := srv.clients[clientID]
+	srv.mu.Unlock()
+	if !ok || client.Secret != secret {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauthtesting"`)
+		writeError(w, Error{Status: http.StatusUnauthorized, Code: "invalid_client"})
+		return
+	}
+
+	var g grant
+	switch req.PostForm.Get("grant_type") {
+	case "client_credentials":
+		g = grant{
+			clientID: client.ID,
+			subject:  client.ID,
+			scopes:   strings.Fields(req.PostForm.Get("scope")),
+		}
+		if !allowed(client, g.scopes) {
+			writeError(w, Error{Code: "invalid_scope"})
+			return
+		}
+	case "authorization_code":
+		code := req.PostForm.Get("code")
+		srv.mu.Lock()
+		g, ok = srv.codes[code]
+		// Codes may only be used once.
+		delete(srv.codes, code)
+		srv.mu.Unlock()
+		if !ok || g.clientID != client.ID || g.redirectURI != req.PostForm.Get("redirect_uri") {
+			writeError(w, Error{Code: "invalid_grant"})
+			return
+		}
+	case "refresh_token":
+		srv.mu.Lock()
+		g, ok = srv.refreshTokens[req.PostForm.Get("refresh_token")]
+		srv.mu.Unlock()
+		if !ok || g.clientID != client.ID {
+			writeError(w, Error{Code: "invalid_grant"})
+			return
+		}
+		if scope := req.PostForm.Get("scope"); scope != "" {
+			scopes := strings.Fields(scope)
+			if !subset(scopes, g.scopes) {
+				writeError(w, Error{Code: "invalid_scope"})
+				return
+			}
+			g.scopes = scopes
+		}
+	default:
+		writeError(w, Error{Code: "unsupported_grant_type"})
+		return
+	}
+	resp, err := srv.issue(g, req.PostForm.Get("grant_type"))
+	if err != nil {
+		writeError(w, Error{Status: http.StatusInternalServerError, Code: "server_error", Description: err.Error()})
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// issue returns the token response for the given grant.
+func (srv *Server) issue(g grant, grantType string) (*TokenResponse, error) {
+	token, err := srv.newToken(g.clientID, g.subject, g.scopes, "")
+	if err != nil {
+		return nil, err
+	}
+	resp := &TokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(srv.tokenLifetime() / time.Second),
+		Scope:       strings.Join(g.scopes, " "),
+	}
+	if grantType == "client_credentials" {
+		return resp, nil
+	}
+	if contains(g.scopes, "openid") {
+		if resp.IDToken, err = srv.newToken(g.clientID, g.subject, nil, g.nonce); err != nil {
+			return nil, err
+		}
+	}
+	if resp.RefreshToken, err = randomToken(); err != nil {
+		return nil, err
+	}
+	srv.mu.Lock()
+	srv.refreshTokens[resp.RefreshToken] = g
+	srv.mu.Unlock()
+	return resp, nil
+}
+
+// newToken returns a signed token for the given client and subject.
+func (srv *Server) newToken(clientID, subject string, scopes []string, nonce string) (string, error) {
+	id, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	now := srv.clock().Now()
+	return srv.sign(Claims{
+		Issuer:   srv.Issuer(),
+		Subject:  subject,
+		Audience: clientID,
+		IssuedAt: now.Unix(),
+		Expiry:   now.Add(srv.tokenLifetime()).Unix(),
+		ID:       id,
+		Scope:    strings.Join(scopes, " "),
+		Nonce:    nonce,
+	})
+}
+
+// sign returns a JWT holding the claims, signed with ES256.
+func (srv *Server) sign(claims Claims) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "ES256",
+		"typ": "JWT",
+		"kid": srv.keyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, srv.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	sig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return signingInput + "." + encodeSegment(sig), nil
+}
+
+// verify checks the token's signature and expiry and returns its
+// claims.
+func (srv *Server) verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("token has %d parts, not 3", len(parts))
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(sig) != 64 {
+		return Claims{}, fmt.Errorf("malformed signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(&srv.key.PublicKey, digest[:], r, s) {
+		return Claims{}, fmt.Errorf("bad signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, fmt.Errorf("malformed payload: %v", err)
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, fmt.Errorf("malformed claims: %v", err)
+	}
+	if now := srv.clock().Now().Unix(); now >= claims.Expiry {
+		return Claims{}, fmt.Errorf("token expired at %v", time.Unix(claims.Expiry, 0).UTC())
+	}
+	return claims, nil
+}
+
+// allowed reports whether the client may request the given scopes.
+func allowed(client Client, scopes []string) bool {
+	return client.Scopes == nil || subset(scopes, client.Scopes)
+}
+
+// subset reports whether every element of a is in b.
+func subset(a, b []string) bool {
+	for _, s := range a {
+		if !contains(b, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(ss []string, s string) bool {
+	for _, t := range ss {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, e Error) {
+	status := e.Status
+	if status == 0 {
+		status = http.StatusBadRequest
+	}
+	body := map[string]string{"error": e.Code}
+	if e.Description != "" {
+		body["error_description"] = e.Description
+	}
+	writeJSON(w, status, body)
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// randomToken returns a random string suitable for use as an
+// authorization code or refresh token.
+func randomToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func randomString(c *gc.C, n int) string {
+	buf := make([]byte, n)
+	_, err := rand.Read(buf)
+	c.Assert(err, gc.IsNil)
+	return hex.EncodeToString(buf)
+}
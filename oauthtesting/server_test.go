@@ -0,0 +1,344 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package oauthtesting_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/oauthtesting"
+	"github.com/juju/testing/testclock"
+)
+
+type serverSuite struct {
+	srv *oauthtesting.Server
+}
+
+var _ = gc.Suite(&serverSuite{})
+
+func (s *serverSuite) SetUpTest(c *gc.C) {
+	s.srv = oauthtesting.NewServer(c)
+	s.srv.AddClient(oauthtesting.Client{
+		ID:           "svc",
+		Secret:       "s3cret",
+		Scopes:       []string{"openid", "read", "write"},
+		RedirectURIs: []string{"http://app.example/callback"},
+	})
+}
+
+func (s *serverSuite) TearDownTest(c *gc.C) {
+	s.srv.Close()
+}
+
+// postToken posts the form to the token endpoint with the client's
+// credentials and decodes the JSON response.
+func (s *serverSuite) postToken(c *gc.C, form url.Values, id, secret string) (int, map[string]interface{}) {
+	req, err := http.NewRequest("POST", s.srv.TokenURL(), strings.NewReader(form.Encode()))
+	c.Assert(err, gc.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(id, secret)
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	c.Assert(err, gc.IsNil)
+	return resp.StatusCode, body
+}
+
+func (s *serverSuite) TestDiscovery(c *gc.C) {
+	resp, err := http.Get(s.srv.URL + "/.well-known/openid-configuration")
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	var doc map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&doc)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc["issuer"], gc.Equals, s.srv.Issuer())
+	c.Assert(doc["token_endpoint"], gc.Equals, s.srv.TokenURL())
+	c.Assert(doc["authorization_endpoint"], gc.Equals, s.srv.AuthorizeURL())
+	c.Assert(doc["jwks_uri"], gc.Equals, s.srv.JWKSURL())
+}
+
+func (s *serverSuite) TestJWKS(c *gc.C) {
+	resp, err := http.Get(s.srv.JWKSURL())
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	var jwks struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&jwks)
+	c.Assert(err, gc.IsNil)
+	c.Assert(jwks.Keys, gc.HasLen, 1)
+	key := jwks.Keys[0]
+	c.Assert(key["kty"], gc.Equals, "EC")
+	c.Assert(key["crv"], gc.Equals, "P-256")
+	c.Assert(key["alg"], gc.Equals, "ES256")
+	c.Assert(key["kid"], gc.Not(gc.Equals), "")
+	c.Assert(key["x"], gc.HasLen, 43)
+	c.Assert(key["y"], gc.HasLen, 43)
+}
+
+func (s *serverSuite) TestClientCredentials(c *gc.C) {
+	status, body := s.postToken(c, url.Values{
+		"grant_type": {"client_credentials"},
+		"scope":      {"read write"},
+	}, "svc", "s3cret")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body["token_type"], gc.Equals, "Bearer")
+	c.Assert(body["expires_in"], gc.Equals, float64(3600))
+	c.Assert(body["scope"], gc.Equals, "read write")
+	c.Assert(body["refresh_token"], gc.IsNil)
+
+	claims := s.srv.VerifyToken(c, body["access_token"].(string))
+	c.Assert(claims.Issuer, gc.Equals, s.srv.Issuer())
+	c.Assert(claims.Subject, gc.Equals, "svc")
+	c.Assert(claims.Audience, gc.Equals, "svc")
+	c.Assert(claims.Scopes(), jc.DeepEquals, []string{"read", "write"})
+}
+
+func (s *serverSuite) TestClientCredentialsInForm(c *gc.C) {
+	resp, err := http.PostForm(s.srv.TokenURL(), url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {"svc"},
+		"client_secret": {"s3cret"},
+	})
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Cache-Control"), gc.Equals, "no-store")
+}
+
+var tokenErrorTests = []struct {
+	about        string
+	id, secret   string
+	form         url.Values
+	expectStatus int
+	expectError  string
+}{{
+	about:        "unknown client",
+	id:           "other",
+	secret:       "s3cret",
+	form:         url.Values{"grant_type": {"client_credentials"}},
+	expectStatus: http.StatusUnauthorized,
+	expectError:  "invalid_client",
+}, {
+	about:        "wrong secret",
+	id:           "svc",
+	secret:       "wrong",
+	form:         url.Values{"grant_type": {"client_credentials"}},
+	expectStatus: http.StatusUnauthorized,
+	expectError:  "invalid_client",
+}, {
+	about:        "scope not allowed",
+	id:           "svc",
+	secret:       "s3cret",
+	form:         url.Values{"grant_type": {"client_credentials"}, "scope": {"read admin"}},
+	expectStatus: http.StatusBadRequest,
+	expectError:  "invalid_scope",
+}, {
+	about:        "unsupported grant",
+	id:           "svc",
+	secret:       "s3cret",
+	form:         url.Values{"grant_type": {"password"}},
+	expectStatus: http.StatusBadRequest,
+	expectError:  "unsupported_grant_type",
+}, {
+	about:        "unknown code",
+	id:           "svc",
+	secret:       "s3cret",
+	form:         url.Values{"grant_type": {"authorization_code"}, "code": {"nope"}},
+	expectStatus: http.StatusBadRequest,
+	expectError:  "invalid_grant",
+}, {
+	about:        "unknown refresh token",
+	id:           "svc",
+	secret:       "s3cret",
+	form:         url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"nope"}},
+	expectStatus: http.StatusBadRequest,
+	expectError:  "invalid_grant",
+}}
+
+func (s *serverSuite) TestTokenErrors(c *gc.C) {
+	for i, test := range tokenErrorTests {
+		c.Logf("test %d: %s", i, test.about)
+		status, body := s.postToken(c, test.form, test.id, test.secret)
+		c.Check(status, gc.Equals, test.expectStatus)
+		c.Check(body["error"], gc.Equals, test.expectError)
+	}
+}
+
+func (s *serverSuite) TestFailTokenRequests(c *gc.C) {
+	s.srv.FailTokenRequests(2, oauthtesting.Error{
+		Status:      http.StatusServiceUnavailable,
+		Code:        "temporarily_unavailable",
+		Description: "try later",
+	})
+	form := url.Values{"grant_type": {"client_credentials"}}
+	for i := 0; i < 2; i++ {
+		status, body := s.postToken(c, form, "svc", "s3cret")
+		c.Assert(status, gc.Equals, http.StatusServiceUnavailable)
+		c.Assert(body, jc.DeepEquals, map[string]interface{}{
+			"error":             "temporarily_unavailable",
+			"error_description": "try later",
+		})
+	}
+	status, _ := s.postToken(c, form, "svc", "s3cret")
+	c.Assert(status, gc.Equals, http.StatusOK)
+}
+
+// authorize requests a code from the authorization endpoint and
+// returns the query of the redirect.
+func (s *serverSuite) authorize(c *gc.C, params url.Values) url.Values {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(s.srv.AuthorizeURL() + "?" + params.Encode())
+	c.Assert(err, gc.IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusFound)
+	loc, err := resp.Location()
+	c.Assert(err, gc.IsNil)
+	c.Assert(loc.Host, gc.Equals, "app.example")
+	c.Assert(loc.Path, gc.Equals, "/callback")
+	return loc.Query()
+}
+
+func (s *serverSuite) TestAuthorizationCodeFlow(c *gc.C) {
+	s.srv.Subject = "alice"
+	q := s.authorize(c, url.Values{
+		"response_type": {"code"},
+		"client_id":     {"svc"},
+		"redirect_uri":  {"http://app.example/callback"},
+		"scope":         {"openid read"},
+		"state":         {"xyz"},
+		"nonce":         {"n-0S6"},
+	})
+	c.Assert(q.Get("state"), gc.Equals, "xyz")
+	code := q.Get("code")
+	c.Assert(code, gc.Not(gc.Equals), "")
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {"http://app.example/callback"},
+	}
+	status, body := s.postToken(c, form, "svc", "s3cret")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	claims := s.srv.VerifyToken(c, body["access_token"].(string))
+	c.Assert(claims.Subject, gc.Equals, "alice")
+	c.Assert(claims.Scope, gc.Equals, "openid read")
+	idClaims := s.srv.VerifyToken(c, body["id_token"].(string))
+	c.Assert(idClaims.Subject, gc.Equals, "alice")
+	c.Assert(idClaims.Audience, gc.Equals, "svc")
+	c.Assert(idClaims.Nonce, gc.Equals, "n-0S6")
+
+	// Codes can be used only once.
+	status, body = s.postToken(c, form, "svc", "s3cret")
+	c.Assert(status, gc.Equals, http.StatusBadRequest)
+	c.Assert(body["error"], gc.Equals, "invalid_grant")
+}
+
+func (s *serverSuite) TestAuthorizeErrors(c *gc.C) {
+	q := s.authorize(c, url.Values{
+		"response_type": {"code"},
+		"client_id":     {"svc"},
+		"redirect_uri":  {"http://app.example/callback"},
+		"scope":         {"admin"},
+		"state":         {"xyz"},
+	})
+	c.Assert(q.Get("error"), gc.Equals, "invalid_scope")
+	c.Assert(q.Get("state"), gc.Equals, "xyz")
+	c.Assert(q.Get("code"), gc.Equals, "")
+
+	resp, err := http.Get(s.srv.AuthorizeURL() + "?" + url.Values{
+		"response_type": {"code"},
+		"client_id":     {"svc"},
+		"redirect_uri":  {"http://evil.example/callback"},
+	}.Encode())
+	c.Assert(err, gc.IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusBadRequest)
+}
+
+func (s *serverSuite) TestRefreshToken(c *gc.C) {
+	q := s.authorize(c, url.Values{
+		"response_type": {"code"},
+		"client_id":     {"svc"},
+		"redirect_uri":  {"http://app.example/callback"},
+		"scope":         {"read write"},
+	})
+	status, body := s.postToken(c, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {q.Get("code")},
+		"redirect_uri": {"http://app.example/callback"},
+	}, "svc", "s3cret")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(body["id_token"], gc.IsNil)
+	refresh := body["refresh_token"].(string)
+
+	status, body = s.postToken(c, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refresh},
+		"scope":         {"read"},
+	}, "svc", "s3cret")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	claims := s.srv.VerifyToken(c, body["access_token"].(string))
+	c.Assert(claims.Subject, gc.Equals, "user")
+	c.Assert(claims.Scope, gc.Equals, "read")
+
+	// A refresh cannot widen the scope.
+	status, body = s.postToken(c, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refresh},
+		"scope":         {"read openid"},
+	}, "svc", "s3cret")
+	c.Assert(status, gc.Equals, http.StatusBadRequest)
+	c.Assert(body["error"], gc.Equals, "invalid_scope")
+}
+
+func (s *serverSuite) TestTokenExpiry(c *gc.C) {
+	clock := testclock.NewClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	s.srv.Clock = clock
+	s.srv.TokenLifetime = time.Minute
+	token := s.srv.Token(c, "svc", "read")
+	claims := s.srv.VerifyToken(c, token)
+	c.Assert(claims.IssuedAt, gc.Equals, clock.Now().Unix())
+	c.Assert(claims.Expiry, gc.Equals, clock.Now().Add(time.Minute).Unix())
+
+	clock.Advance(time.Minute)
+	c.ExpectFailure("token has expired")
+	s.srv.VerifyToken(c, token)
+}
+
+func (s *serverSuite) TestVerifyTokenBadSignature(c *gc.C) {
+	other := oauthtesting.NewServer(c)
+	defer other.Close()
+	token := other.Token(c, "svc")
+	c.ExpectFailure("token signed by another server")
+	s.srv.VerifyToken(c, token)
+}
+
+func (s *serverSuite) TestSignClaims(c *gc.C) {
+	token := s.srv.SignClaims(c, oauthtesting.Claims{
+		Issuer:   "https://elsewhere.example",
+		Subject:  "bob",
+		Audience: "api",
+		Expiry:   time.Now().Add(time.Hour).Unix(),
+	})
+	claims := s.srv.VerifyToken(c, token)
+	c.Assert(claims, jc.DeepEquals, oauthtesting.Claims{
+		Issuer:   "https://elsewhere.example",
+		Subject:  "bob",
+		Audience: "api",
+		Expiry:   claims.Expiry,
+	})
+}
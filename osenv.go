@@ -12,7 +12,14 @@ import (
 )
 
 // OsEnvSuite isolates the tests from the underlaying system environment.
-// Environment variables are reset in SetUpTest and restored in TearDownTest.
+// Environment variables are reset in SetUpTest and TearDownTest, and the
+// original environment is restored in TearDownSuite.
+//
+// Only the whitelisted variables below survive the reset. On platforms
+// other than windows, HOME and PATH are then set to fresh empty
+// directories for each test, so that tests cannot depend on the
+// contents of the developer's home directory or on the tools they have
+// installed.
 type OsEnvSuite struct {
 	oldEnvironment map[string]string
 }
@@ -64,6 +71,7 @@ var windowsVariables = []string{
 // used to control Juju tests, that will be retained if found.
 var testingVariables = []string{
 	"JUJU_MONGOD",
+	"TEST_TAGS",
 }
 
 func (s *OsEnvSuite) setEnviron() {
@@ -110,24 +118,22 @@ func (s *OsEnvSuite) osDependendClearenv() {
 }
 
 func (s *OsEnvSuite) SetUpSuite(c *gc.C) {
-	s.oldEnvironment = make(map[string]string)
-	for _, envvar := range os.Environ() {
-		parts := strings.SplitN(envvar, "=", 2)
-		s.oldEnvironment[parts[0]] = parts[1]
-	}
+	s.oldEnvironment = environMap()
 	s.osDependendClearenv()
 }
 
 func (s *OsEnvSuite) TearDownSuite(c *gc.C) {
-	os.Clearenv()
-	for name, value := range s.oldEnvironment {
-		os.Setenv(name, value)
-	}
+	restoreEnviron(s.oldEnvironment)
 }
 
 func (s *OsEnvSuite) SetUpTest(c *gc.C) {
 	s.osDependendClearenv()
+	if runtime.GOOS != "windows" {
+		os.Setenv("HOME", c.MkDir())
+		os.Setenv("PATH", c.MkDir())
+	}
 }
 
 func (s *OsEnvSuite) TearDownTest(c *gc.C) {
+	s.osDependendClearenv()
 }
@@ -10,6 +10,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
 )
 
 type osEnvSuite struct {
@@ -67,6 +68,30 @@ func (s *osEnvSuite) TestRestoresTestingVariables(c *gc.C) {
 	c.Assert(os.Getenv("JUJU_MONGOD"), gc.Equals, "")
 }
 
+func (s *osEnvSuite) TestHomeAndPathAreTempDirs(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("HOME and PATH are not replaced on windows")
+	}
+	restore := testing.PatchEnvironment("HOME", "/home/developer")
+	defer restore()
+	s.osEnvSuite.SetUpSuite(c)
+	s.osEnvSuite.SetUpTest(c)
+	home := os.Getenv("HOME")
+	c.Assert(home, jc.IsDirectory)
+	c.Assert(os.Getenv("PATH"), jc.IsDirectory)
+	c.Assert(os.Getenv("PATH"), gc.Not(gc.Equals), home)
+	s.osEnvSuite.TearDownTest(c)
+	c.Assert(os.Getenv("HOME"), gc.Equals, "")
+
+	// Each test gets a new home directory.
+	s.osEnvSuite.SetUpTest(c)
+	c.Assert(os.Getenv("HOME"), jc.IsDirectory)
+	c.Assert(os.Getenv("HOME"), gc.Not(gc.Equals), home)
+	s.osEnvSuite.TearDownTest(c)
+	s.osEnvSuite.TearDownSuite(c)
+	c.Assert(os.Getenv("HOME"), gc.Equals, "/home/developer")
+}
+
 func (s *osEnvSuite) TestWindowsPreservesPath(c *gc.C) {
 	if runtime.GOOS != "windows" {
 		c.Skip("Windows-specific test case")
@@ -0,0 +1,91 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"flag"
+	"regexp"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+)
+
+var paramFilter = flag.String("param.f", "", "Regular expression selecting which parameters of parameterized suites to run tests with")
+
+// ParamSuite is embedded in suites that are registered with
+// RegisterParamSuite, to hold the suite's parameter. Suites that
+// define their own SetUpTest method must call ParamSuite.SetUpTest:
+//
+//	type storeSuite struct {
+//		testing.ParamSuite[func(c *gc.C) Store]
+//		store Store
+//	}
+//
+//	var _ = testing.RegisterParamSuite(
+//		func() *storeSuite { return &storeSuite{} },
+//		map[string]func(c *gc.C) Store{
+//			"memory": newMemoryStore,
+//			"sqlite": newSQLiteStore,
+//		},
+//	)
+//
+//	func (s *storeSuite) SetUpTest(c *gc.C) {
+//		s.ParamSuite.SetUpTest(c)
+//		s.store = s.Param(c)
+//	}
+//
+// Since gocheck names tests after the type of their suite, the
+// parameter's name is not part of test names. Instead, SetUpTest logs
+// it, so that it is shown with any test failure, and skips the test if
+// the name does not match the regular expression given with the
+// -param.f flag.
+type ParamSuite[P any] struct {
+	// ParamName holds the name of the suite's parameter.
+	ParamName string
+
+	// Param holds the suite's parameter.
+	Param P
+}
+
+// SetUpTest logs the suite's parameter name, and skips the test if it
+// is not selected by the -param.f flag.
+func (s *ParamSuite[P]) SetUpTest(c *gc.C) {
+	if *paramFilter != "" {
+		filter, err := regexp.Compile(*paramFilter)
+		c.Assert(err, gc.IsNil, gc.Commentf("invalid -param.f flag"))
+		if !filter.MatchString(s.ParamName) {
+			c.Skip("parameter " + s.ParamName + " not selected by -param.f")
+		}
+	}
+	c.Logf("suite parameter: %s", s.ParamName)
+}
+
+func (s *ParamSuite[P]) setParam(name string, param P) {
+	s.ParamName = name
+	s.Param = param
+}
+
+// paramSuite is implemented by suites that embed ParamSuite.
+type paramSuite[P any] interface {
+	setParam(name string, param P)
+}
+
+// RegisterParamSuite registers a suite with gocheck for each of the
+// given parameters, in order of their names, using newSuite to create
+// each suite and setting its embedded ParamSuite from the parameter.
+// It returns the registered suites.
+func RegisterParamSuite[P any, S paramSuite[P]](newSuite func() S, params map[string]P) []interface{} {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	suites := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		suite := newSuite()
+		suite.setParam(name, params[name])
+		suites = append(suites, gc.Suite(suite))
+	}
+	return suites
+}
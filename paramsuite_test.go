@@ -0,0 +1,126 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"flag"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+// set is implemented by the backends of paramSetSuite.
+type set interface {
+	Add(s string)
+	Items() []string
+}
+
+type mapSet map[string]bool
+
+func (m mapSet) Add(s string) {
+	m[s] = true
+}
+
+func (m mapSet) Items() []string {
+	var items []string
+	for s := range m {
+		items = append(items, s)
+	}
+	sort.Strings(items)
+	return items
+}
+
+type sliceSet struct {
+	items []string
+}
+
+func (s *sliceSet) Add(item string) {
+	i := sort.SearchStrings(s.items, item)
+	if i < len(s.items) && s.items[i] == item {
+		return
+	}
+	s.items = append(s.items[:i], append([]string{item}, s.items[i:]...)...)
+}
+
+func (s *sliceSet) Items() []string {
+	return s.items
+}
+
+// paramSetSuite runs the same tests against each set implementation.
+type paramSetSuite struct {
+	testing.ParamSuite[func() set]
+	set set
+}
+
+var paramSetSuites = testing.RegisterParamSuite(
+	func() *paramSetSuite { return &paramSetSuite{} },
+	map[string]func() set{
+		"slice": func() set { return &sliceSet{} },
+		"map":   func() set { return make(mapSet) },
+	},
+)
+
+func (s *paramSetSuite) SetUpTest(c *gc.C) {
+	s.ParamSuite.SetUpTest(c)
+	s.set = s.Param()
+}
+
+func (s *paramSetSuite) TestAdd(c *gc.C) {
+	s.set.Add("b")
+	s.set.Add("a")
+	s.set.Add("b")
+	c.Assert(s.set.Items(), jc.DeepEquals, []string{"a", "b"})
+}
+
+type paramSuiteSuite struct{}
+
+var _ = gc.Suite(&paramSuiteSuite{})
+
+func (*paramSuiteSuite) TestRegisterParamSuite(c *gc.C) {
+	c.Assert(paramSetSuites, gc.HasLen, 2)
+	var names []string
+	for _, suite := range paramSetSuites {
+		names = append(names, suite.(*paramSetSuite).ParamName)
+	}
+	c.Assert(names, jc.DeepEquals, []string{"map", "slice"})
+	_, ok := paramSetSuites[1].(*paramSetSuite).Param().(*sliceSet)
+	c.Assert(ok, gc.Equals, true)
+}
+
+// loggedParamSuite is run by paramSuiteSuite rather than being
+// registered with gocheck.
+type loggedParamSuite struct {
+	testing.ParamSuite[int]
+}
+
+func (s *loggedParamSuite) TestParam(c *gc.C) {
+	c.Check(s.Param, gc.Equals, 42)
+}
+
+func (*paramSuiteSuite) TestSetUpTestLogsParam(c *gc.C) {
+	var output bytes.Buffer
+	suite := &loggedParamSuite{testing.ParamSuite[int]{ParamName: "answer", Param: 42}}
+	result := gc.Run(suite, &gc.RunConf{Output: &output, Stream: true})
+	c.Assert(result.Succeeded, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, "(?s).*suite parameter: answer\n.*")
+}
+
+func (*paramSuiteSuite) TestParamFilter(c *gc.C) {
+	err := flag.Set("param.f", "^(question|other)$")
+	c.Assert(err, gc.IsNil)
+	defer flag.Set("param.f", "")
+
+	suite := &loggedParamSuite{testing.ParamSuite[int]{ParamName: "answer", Param: 42}}
+	result := gc.Run(suite, &gc.RunConf{Output: &bytes.Buffer{}})
+	c.Assert(result.Skipped, gc.Equals, 1)
+	c.Assert(result.Succeeded, gc.Equals, 0)
+
+	suite.ParamName = "question"
+	result = gc.Run(suite, &gc.RunConf{Output: &bytes.Buffer{}})
+	c.Assert(result.Succeeded, gc.Equals, 1)
+}
@@ -4,9 +4,15 @@
 package testing
 
 import (
+	"fmt"
 	"os"
 	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+
+	gc "gopkg.in/check.v1"
 )
 
 // Restorer holds a function that can be used
@@ -44,8 +50,129 @@ func PatchValue(dest, value interface{}) Restorer {
 		valuev = reflect.Zero(destv.Type())
 	}
 	destv.Set(valuev)
-	return func() {
+	return trackPatch(destv.Type(), func() {
 		destv.Set(oldv)
+	})
+}
+
+// Patch sets the value pointed to by dest to the given value, and
+// returns a function to restore it to its original value. It is a
+// type-safe alternative to PatchValue: a value of the wrong type is
+// rejected at compile time rather than causing a panic.
+func Patch[T any](dest *T, value T) Restorer {
+	old := *dest
+	*dest = value
+	return trackPatch(reflect.TypeOf(dest).Elem(), func() {
+		*dest = old
+	})
+}
+
+// CleanupAdder is implemented by CleanupSuite and any suite embedding
+// it.
+type CleanupAdder interface {
+	AddCleanup(cleanup func(*gc.C))
+}
+
+// SuitePatch is like Patch except that the original value is restored
+// at test tear down time (or suite tear down time if called before
+// the first test) using a cleanup function added to s. Go does not
+// allow generic methods, hence this is not a method on CleanupSuite.
+func SuitePatch[T any](s CleanupAdder, dest *T, value T) {
+	restore := Patch(dest, value)
+	s.AddCleanup(func(*gc.C) { restore() })
+}
+
+// trackPatch records a patch of a value of type t in activePatches,
+// returning a Restorer that calls restore and forgets the patch.
+func trackPatch(t reflect.Type, restore func()) Restorer {
+	var id int
+	r := Restorer(func() {
+		restore()
+		activePatches.remove(id)
+	})
+	id = activePatches.add(fmt.Sprintf("%s patched at %s", t, patchCaller()), r)
+	return r
+}
+
+// activePatches holds the patches made by PatchValue that have not
+// yet been restored. It allows IsolationSuite to detect patches that
+// outlive the test that made them.
+var activePatches = &patchRegistry{
+	patches: make(map[int]activePatch),
+}
+
+// activePatch holds an outstanding patch along with a description of
+// where it was made.
+type activePatch struct {
+	description string
+	restore     Restorer
+}
+
+type patchRegistry struct {
+	mu      sync.Mutex
+	nextID  int
+	patches map[int]activePatch
+}
+
+func (r *patchRegistry) add(description string, restore Restorer) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.patches[r.nextID] = activePatch{
+		description: description,
+		restore:     restore,
+	}
+	return r.nextID
+}
+
+func (r *patchRegistry) remove(id int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.patches, id)
+}
+
+// ids returns the set of ids of all outstanding patches.
+func (r *patchRegistry) ids() map[int]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make(map[int]bool, len(r.patches))
+	for id := range r.patches {
+		ids[id] = true
+	}
+	return ids
+}
+
+// since returns all outstanding patches whose ids are not in the
+// given set, ordered by the time they were made.
+func (r *patchRegistry) since(ids map[int]bool) []activePatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var newIDs []int
+	for id := range r.patches {
+		if !ids[id] {
+			newIDs = append(newIDs, id)
+		}
+	}
+	sort.Ints(newIDs)
+	result := make([]activePatch, len(newIDs))
+	for i, id := range newIDs {
+		result[i] = r.patches[id]
+	}
+	return result
+}
+
+// patchCaller returns the location of the first caller outside this
+// package, so that patches made through CleanupSuite.PatchValue are
+// attributed to the test that made them.
+func patchCaller() string {
+	pcs := make([]uintptr, 10)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/juju/testing.") || !more {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
 	}
 }
 
@@ -64,6 +191,90 @@ func PatchEnvironment(name, value string) Restorer {
 	}
 }
 
+// EnvValue holds the value to give an environment variable patched
+// with PatchEnvironmentVars. The zero value sets the variable to the
+// empty string.
+type EnvValue struct {
+	// Value holds the value to set. It is ignored if Unset is true.
+	Value string
+
+	// Unset specifies that the variable should be removed from the
+	// environment entirely rather than set to Value.
+	Unset bool
+}
+
+// EnvUnset is an EnvValue that removes a variable from the environment.
+var EnvUnset = EnvValue{Unset: true}
+
+// EnvSet returns an EnvValue that sets a variable to the given value.
+func EnvSet(value string) EnvValue {
+	return EnvValue{Value: value}
+}
+
+// envPatches holds the stack of outstanding PatchEnvironmentVars
+// patches.
+var envPatches struct {
+	mu     sync.Mutex
+	nextID int
+	stack  []int
+}
+
+// PatchEnvironmentVars sets or unsets each of the given environment
+// variables and returns a function that restores all of them to what
+// they were before, including removing variables that were previously
+// unset.
+//
+// The environment is shared by the whole process, so
+// PatchEnvironmentVars must not be used by tests that run in parallel
+// with other tests in the same process. To help catch this, patches
+// must be restored in the reverse order to that in which they were
+// made; restoring them in any other order panics.
+func PatchEnvironmentVars(vars map[string]EnvValue) Restorer {
+	type oldValue struct {
+		value string
+		set   bool
+	}
+	old := make(map[string]oldValue, len(vars))
+	for name, v := range vars {
+		value, set := os.LookupEnv(name)
+		old[name] = oldValue{value, set}
+		if v.Unset {
+			_ = os.Unsetenv(name)
+		} else {
+			_ = os.Setenv(name, v.Value)
+		}
+	}
+
+	envPatches.mu.Lock()
+	envPatches.nextID++
+	id := envPatches.nextID
+	envPatches.stack = append(envPatches.stack, id)
+	envPatches.mu.Unlock()
+
+	restored := false
+	return func() {
+		if restored {
+			return
+		}
+		envPatches.mu.Lock()
+		n := len(envPatches.stack)
+		if n == 0 || envPatches.stack[n-1] != id {
+			envPatches.mu.Unlock()
+			panic("environment patches restored out of order; PatchEnvironmentVars cannot be used by parallel tests")
+		}
+		envPatches.stack = envPatches.stack[:n-1]
+		envPatches.mu.Unlock()
+		restored = true
+		for name, v := range old {
+			if v.set {
+				_ = os.Setenv(name, v.value)
+			} else {
+				_ = os.Unsetenv(name)
+			}
+		}
+	}
+}
+
 // PatchEnvPathPrepend provides a simple way to prepend path to the start of the
 // PATH environment variable. Returns a function that restores the environment
 // to what it was before.
@@ -10,6 +10,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
 )
 
 type PatchValueSuite struct{}
@@ -58,6 +59,31 @@ func (*PatchValueSuite) TestSetPanicsWhenNotAssignable(c *gc.C) {
 	c.Assert(func() { testing.PatchValue(&i, otherInt(88)) }, gc.PanicMatches, `reflect\.Set: value of type testing_test\.otherInt is not assignable to type int`)
 }
 
+func (*PatchValueSuite) TestPatchInt(c *gc.C) {
+	i := 99
+	restore := testing.Patch(&i, 88)
+	c.Assert(i, gc.Equals, 88)
+	restore()
+	c.Assert(i, gc.Equals, 99)
+}
+
+func (*PatchValueSuite) TestPatchErrorToNil(c *gc.C) {
+	oldErr := errors.New("foo")
+	err := oldErr
+	restore := testing.Patch(&err, nil)
+	c.Assert(err, gc.IsNil)
+	restore()
+	c.Assert(err, gc.Equals, oldErr)
+}
+
+func (*PatchValueSuite) TestPatchFunction(c *gc.C) {
+	f := func() string { return "original" }
+	restore := testing.Patch(&f, func() string { return "patched" })
+	c.Assert(f(), gc.Equals, "patched")
+	restore()
+	c.Assert(f(), gc.Equals, "original")
+}
+
 type PatchEnvironmentSuite struct{}
 
 var _ = gc.Suite(&PatchEnvironmentSuite{})
@@ -124,3 +150,56 @@ func (*PatchEnvironmentSuite) TestPatchEnvPathPrepend(c *gc.C) {
 	restore()
 	c.Check(os.Getenv("PATH"), gc.Equals, oldPath)
 }
+
+func (*PatchEnvironmentSuite) TestPatchEnvironmentVars(c *gc.C) {
+	const (
+		setName   = "TESTING_PATCH_ENVIRONMENT_SET"
+		emptyName = "TESTING_PATCH_ENVIRONMENT_EMPTY"
+		unsetName = "TESTING_PATCH_ENVIRONMENT_UNSET"
+	)
+	restore := testing.PatchEnvironmentVars(map[string]testing.EnvValue{
+		setName:   testing.EnvUnset,
+		emptyName: testing.EnvUnset,
+		unsetName: testing.EnvSet("initial"),
+	})
+	defer restore()
+
+	restoreVars := testing.PatchEnvironmentVars(map[string]testing.EnvValue{
+		setName:   testing.EnvSet("new value"),
+		emptyName: testing.EnvSet(""),
+		unsetName: testing.EnvUnset,
+	})
+	value, set := os.LookupEnv(setName)
+	c.Check(set, jc.IsTrue)
+	c.Check(value, gc.Equals, "new value")
+	value, set = os.LookupEnv(emptyName)
+	c.Check(set, jc.IsTrue)
+	c.Check(value, gc.Equals, "")
+	_, set = os.LookupEnv(unsetName)
+	c.Check(set, jc.IsFalse)
+
+	restoreVars()
+	_, set = os.LookupEnv(setName)
+	c.Check(set, jc.IsFalse)
+	_, set = os.LookupEnv(emptyName)
+	c.Check(set, jc.IsFalse)
+	value, set = os.LookupEnv(unsetName)
+	c.Check(set, jc.IsTrue)
+	c.Check(value, gc.Equals, "initial")
+}
+
+func (*PatchEnvironmentSuite) TestPatchEnvironmentVarsRestoreOutOfOrder(c *gc.C) {
+	const envName = "TESTING_PATCH_ENVIRONMENT"
+	restore := testing.PatchEnvironmentVars(map[string]testing.EnvValue{
+		envName: testing.EnvSet("first"),
+	})
+	restore1 := testing.PatchEnvironmentVars(map[string]testing.EnvValue{
+		envName: testing.EnvSet("second"),
+	})
+	c.Assert(func() { restore() }, gc.PanicMatches, "environment patches restored out of order; .*")
+	restore1()
+	c.Check(os.Getenv(envName), gc.Equals, "first")
+	restore()
+	_, set := os.LookupEnv(envName)
+	c.Check(set, jc.IsFalse)
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package pgtesting
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"github.com/lib/pq"
+	gc "gopkg.in/check.v1"
+)
+
+// PostgresSuite provides a PostgreSQL server for the tests in a suite,
+// and a database of their own for each test. The server is the one at
+// PostgresURL if it is set, and otherwise a Container started for the
+// suite. If neither is available, as when running unit tests without
+// docker, the suite's tests are skipped.
+//
+// Each test gets a new, uniquely named database, which is dropped when
+// the test finishes. Its connection URL is in URL, for passing to the
+// code under test, and a connection to it is in DB.
+type PostgresSuite struct {
+	// Container holds the container started for the suite, or nil
+	// if the suite is using the server at PostgresURL.
+	Container *Container
+
+	// Admin holds a connection to the server's initial database,
+	// used to create and drop the test databases.
+	Admin *sql.DB
+
+	// URL holds the connection URL of the current test's database.
+	URL string
+
+	// DB holds a connection to the current test's database.
+	DB *sql.DB
+
+	serverURL string
+	dbName    string
+}
+
+func (s *PostgresSuite) SetUpSuite(c *gc.C) {
+	if !Available() {
+		c.Skip("docker not available; set TEST_POSTGRES_URL to run PostgreSQL tests")
+	}
+	s.serverURL = PostgresURL
+	if s.serverURL == "" {
+		s.Container = &Container{}
+		s.Container.Start(c)
+		s.serverURL = s.Container.URL()
+	}
+	admin, err := sql.Open("postgres", s.serverURL)
+	c.Assert(err, gc.IsNil)
+	s.Admin = admin
+	err = admin.Ping()
+	c.Assert(err, gc.IsNil, gc.Commentf("cannot reach PostgreSQL"))
+}
+
+func (s *PostgresSuite) TearDownSuite(c *gc.C) {
+	if s.Admin != nil {
+		c.Check(s.Admin.Close(), gc.IsNil)
+		s.Admin = nil
+	}
+	if s.Container != nil {
+		s.Container.Destroy()
+		s.Container = nil
+	}
+}
+
+func (s *PostgresSuite) SetUpTest(c *gc.C) {
+	buf := make([]byte, 8)
+	_, err := rand.Read(buf)
+	c.Assert(err, gc.IsNil)
+	s.dbName = "test_" + hex.EncodeToString(buf)
+	_, err = s.Admin.Exec("CREATE DATABASE " + pq.QuoteIdentifier(s.dbName))
+	c.Assert(err, gc.IsNil)
+	s.URL, err = databaseURL(s.serverURL, s.dbName)
+	c.Assert(err, gc.IsNil)
+	s.DB, err = sql.Open("postgres", s.URL)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *PostgresSuite) TearDownTest(c *gc.C) {
+	if s.DB != nil {
+		c.Check(s.DB.Close(), gc.IsNil)
+		s.DB = nil
+	}
+	if s.dbName == "" {
+		return
+	}
+	// The code under test may have left connections open, which
+	// would prevent the database being dropped.
+	_, err := s.Admin.Exec(
+		"SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = $1 AND pid <> pg_backend_pid()",
+		s.dbName,
+	)
+	c.Check(err, gc.IsNil)
+	_, err = s.Admin.Exec("DROP DATABASE IF EXISTS " + pq.QuoteIdentifier(s.dbName))
+	c.Check(err, gc.IsNil)
+	s.dbName = ""
+	s.URL = ""
+}
+
+// databaseURL returns serverURL with its database replaced by name.
+func databaseURL(serverURL, name string) (string, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return "", fmt.Errorf("%q is not a postgres:// URL", serverURL)
+	}
+	u.Path = "/" + name
+	u.RawPath = ""
+	return u.String(), nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package pgtesting
+
+import (
+	"database/sql"
+
+	gc "gopkg.in/check.v1"
+)
+
+type suiteSuite struct {
+	PostgresSuite
+}
+
+var _ = gc.Suite(&suiteSuite{})
+
+func (s *suiteSuite) TestDatabase(c *gc.C) {
+	_, err := s.DB.Exec("CREATE TABLE widgets (name TEXT)")
+	c.Assert(err, gc.IsNil)
+	_, err = s.DB.Exec("INSERT INTO widgets VALUES ('sprocket')")
+	c.Assert(err, gc.IsNil)
+	var name string
+	err = s.DB.QueryRow("SELECT current_database()").Scan(&name)
+	c.Assert(err, gc.IsNil)
+	c.Assert(name, gc.Equals, s.dbName)
+}
+
+func (s *suiteSuite) TestDatabaseDroppedWithOpenConnections(c *gc.C) {
+	// The connection is left open, as by a careless test, but the
+	// database is still dropped at teardown.
+	db, err := sql.Open("postgres", s.URL)
+	c.Assert(err, gc.IsNil)
+	c.Assert(db.Ping(), gc.IsNil)
+}
+
+type urlSuite struct{}
+
+var _ = gc.Suite(&urlSuite{})
+
+var databaseURLTests = []struct {
+	about       string
+	serverURL   string
+	expectURL   string
+	expectError string
+}{{
+	about:     "database replaced",
+	serverURL: "postgres://postgres:pw@127.0.0.1:5432/postgres?sslmode=disable",
+	expectURL: "postgres://postgres:pw@127.0.0.1:5432/test_1?sslmode=disable",
+}, {
+	about:     "no database",
+	serverURL: "postgresql://user@db.example",
+	expectURL: "postgresql://user@db.example/test_1",
+}, {
+	about:       "key-value connection string",
+	serverURL:   "host=localhost user=postgres",
+	expectError: `"host=localhost user=postgres" is not a postgres:// URL`,
+}}
+
+func (s *urlSuite) TestDatabaseURL(c *gc.C) {
+	for i, test := range databaseURLTests {
+		c.Logf("test %d: %s", i, test.about)
+		u, err := databaseURL(test.serverURL, "test_1")
+		if test.expectError != "" {
+			c.Check(err, gc.ErrorMatches, test.expectError)
+			continue
+		}
+		c.Check(err, gc.IsNil)
+		c.Check(u, gc.Equals, test.expectURL)
+	}
+}
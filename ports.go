@@ -0,0 +1,121 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// Ports are reserved from a range below the ephemeral port ranges of
+// Linux, macOS and Windows, so that the operating system does not
+// hand them out to sockets bound to port 0. The range is divided into
+// blocks; each test process locks a block of its own, so that
+// processes running tests in parallel never reserve the same port.
+const (
+	portRangeStart = 20000
+	portRangeEnd   = 32000
+	portBlockSize  = 200
+)
+
+// portLockDir holds the directory holding the lock files for port
+// blocks. It is determined at init time, because OsEnvSuite clears
+// the environment that os.TempDir consults, and all processes must
+// agree on the directory.
+var portLockDir = filepath.Join(os.TempDir(), "juju-testing-ports")
+
+var ports struct {
+	mu sync.Mutex
+	// locks holds the lock files for the blocks used so far, which
+	// are held open for the life of the process.
+	locks []*os.File
+	// next and end hold the next port to try in the current block
+	// and the port after its end.
+	next, end int
+}
+
+// ReservePort returns a TCP port on the loopback interface that is
+// free, and that will not be returned again by ReservePort in this or
+// any other process running concurrently. It is intended for tests that
+// must tell a server, typically in another process, which port to
+// listen on. Where the server runs in process, ReserveListener is
+// better, as it does not leave a moment in which the port is unbound.
+//
+// Reserving ports this way avoids the race in the common pattern of
+// listening on port 0, closing the listener, and reusing its port
+// number: another process may be given the same port in the meantime.
+func ReservePort(c *gc.C) int {
+	ports.mu.Lock()
+	defer ports.mu.Unlock()
+	for {
+		if len(ports.locks) == 0 || ports.next >= ports.end {
+			if err := lockPortBlock(); err != nil {
+				c.Fatalf("cannot reserve port: %v", err)
+			}
+		}
+		port := ports.next
+		ports.next++
+		// Check that the port is free, in case a process that
+		// does not coordinate with us is using it.
+		if l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port))); err == nil {
+			l.Close()
+			return port
+		}
+	}
+}
+
+// ReserveListener returns a TCP listener on the loopback interface,
+// bound to a port reserved as by ReservePort. The caller should close
+// it when finished with it.
+func ReserveListener(c *gc.C) net.Listener {
+	for i := 0; ; i++ {
+		port := ReservePort(c)
+		l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		if err == nil {
+			return l
+		}
+		// The port was taken between the check in ReservePort
+		// and now; try another.
+		c.Assert(i < 10, jc.IsTrue, gc.Commentf("cannot listen on reserved port: %v", err))
+	}
+}
+
+// lockPortBlock locks the first free block of ports and makes it the
+// current block. It must be called with ports.mu held.
+func lockPortBlock() error {
+	if err := os.MkdirAll(portLockDir, 0777); err != nil {
+		return err
+	}
+	// Let test processes run by other users share the directory.
+	os.Chmod(portLockDir, 0777|os.ModeSticky)
+	start := portRangeStart
+	if len(ports.locks) > 0 {
+		// Move on from the exhausted block, keeping it locked
+		// so that no other process reuses its ports.
+		start = ports.end
+	}
+	for ; start+portBlockSize <= portRangeEnd; start += portBlockSize {
+		path := filepath.Join(portLockDir, fmt.Sprintf("%d.lock", start))
+		f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0666)
+		if err != nil {
+			return err
+		}
+		if err := tryLockFile(f); err != nil {
+			f.Close()
+			continue
+		}
+		ports.locks = append(ports.locks, f)
+		ports.next, ports.end = start, start+portBlockSize
+		return nil
+	}
+	return fmt.Errorf("all port blocks from %d to %d are in use", portRangeStart, portRangeEnd)
+}
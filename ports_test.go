@@ -0,0 +1,67 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+type portsSuite struct{}
+
+var _ = gc.Suite(&portsSuite{})
+
+func (*portsSuite) TestReservePort(c *gc.C) {
+	seen := make(map[int]bool)
+	for i := 0; i < 10; i++ {
+		port := ReservePort(c)
+		c.Assert(seen[port], jc.IsFalse, gc.Commentf("port %d reserved twice", port))
+		seen[port] = true
+		c.Assert(port >= portRangeStart && port < portRangeEnd, jc.IsTrue, gc.Commentf("port %d", port))
+
+		l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		c.Assert(err, jc.ErrorIsNil)
+		l.Close()
+	}
+}
+
+func (*portsSuite) TestReserveListener(c *gc.C) {
+	l := ReserveListener(c)
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+	c.Assert(port >= portRangeStart && port < portRangeEnd, jc.IsTrue, gc.Commentf("port %d", port))
+	c.Assert(ReservePort(c), gc.Not(gc.Equals), port)
+}
+
+func (*portsSuite) TestBlockLockedByProcess(c *gc.C) {
+	// Make sure this process holds a block.
+	ReservePort(c)
+	ports.mu.Lock()
+	start := ports.end - portBlockSize
+	ports.mu.Unlock()
+
+	// Another attempt to lock the block, as another process would
+	// make, fails.
+	f, err := os.Open(filepath.Join(portLockDir, strconv.Itoa(start)+".lock"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	c.Assert(tryLockFile(f), gc.NotNil)
+}
+
+func (*portsSuite) TestNextBlockWhenExhausted(c *gc.C) {
+	ReservePort(c)
+	ports.mu.Lock()
+	oldEnd := ports.end
+	ports.next = ports.end
+	ports.mu.Unlock()
+
+	port := ReservePort(c)
+	c.Assert(port >= oldEnd, jc.IsTrue, gc.Commentf("port %d, old block ended at %d", port, oldEnd))
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dimension is one of the inputs of the test cases generated by
+// Product.
+type Dimension struct {
+	// Name holds the name of the input.
+	Name string
+
+	// Values holds the values that the input takes.
+	Values []interface{}
+
+	// ValueNames optionally holds names for the values, in the same
+	// order, to be used in the names of test cases. If it is nil, the
+	// values are formatted with fmt.Sprint.
+	ValueNames []string
+}
+
+// ProductCase is a test case generated by Product, holding one value
+// of each dimension.
+type ProductCase struct {
+	// Name describes the test case, such as "tls=true port=443".
+	Name string
+
+	// Values maps the name of each dimension to its value in the
+	// test case.
+	Values map[string]interface{}
+}
+
+// ProductCases holds the test cases generated by Product.
+type ProductCases []ProductCase
+
+// Product returns the cross product of the dimensions' values, as test
+// cases with generated names, with the first dimension varying
+// slowest. The cases can be run with RunTable:
+//
+//	cases := testing.Product(
+//		testing.Dimension{Name: "tls", Values: []interface{}{true, false}},
+//		testing.Dimension{Name: "port", Values: []interface{}{0, 443, 70000}},
+//	).Without(func(test testing.ProductCase) bool {
+//		return test.Values["tls"] == false && test.Values["port"] == 443
+//	})
+//	testing.RunTable(c, cases, func(c *gc.C, test testing.ProductCase) {
+//		cfg := Config{TLS: test.Values["tls"].(bool), Port: test.Values["port"].(int)}
+//		...
+//	})
+//
+// Product panics if a dimension's ValueNames are given but do not
+// match its Values.
+func Product(dims ...Dimension) ProductCases {
+	cases := ProductCases{{Values: make(map[string]interface{})}}
+	var names [][]string
+	names = append(names, nil)
+	for _, dim := range dims {
+		if dim.ValueNames != nil && len(dim.ValueNames) != len(dim.Values) {
+			panic(fmt.Sprintf("testing: dimension %q has %d values but %d value names", dim.Name, len(dim.Values), len(dim.ValueNames)))
+		}
+		var next ProductCases
+		var nextNames [][]string
+		for i, c := range cases {
+			for j, v := range dim.Values {
+				values := make(map[string]interface{}, len(c.Values)+1)
+				for k, v := range c.Values {
+					values[k] = v
+				}
+				values[dim.Name] = v
+				valueName := fmt.Sprint(v)
+				if dim.ValueNames != nil {
+					valueName = dim.ValueNames[j]
+				}
+				next = append(next, ProductCase{Values: values})
+				nextNames = append(nextNames, append(names[i][:len(names[i]):len(names[i])], dim.Name+"="+valueName))
+			}
+		}
+		cases, names = next, nextNames
+	}
+	for i := range cases {
+		cases[i].Name = strings.Join(names[i], " ")
+	}
+	return cases
+}
+
+// Without returns the cases for which exclude returns false.
+func (cases ProductCases) Without(exclude func(test ProductCase) bool) ProductCases {
+	var result ProductCases
+	for _, c := range cases {
+		if !exclude(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type productSuite struct{}
+
+var _ = gc.Suite(&productSuite{})
+
+func (*productSuite) TestProduct(c *gc.C) {
+	cases := testing.Product(
+		testing.Dimension{Name: "tls", Values: []interface{}{true, false}},
+		testing.Dimension{Name: "port", Values: []interface{}{443, 8080}},
+	)
+	c.Assert(cases, jc.DeepEquals, testing.ProductCases{{
+		Name:   "tls=true port=443",
+		Values: map[string]interface{}{"tls": true, "port": 443},
+	}, {
+		Name:   "tls=true port=8080",
+		Values: map[string]interface{}{"tls": true, "port": 8080},
+	}, {
+		Name:   "tls=false port=443",
+		Values: map[string]interface{}{"tls": false, "port": 443},
+	}, {
+		Name:   "tls=false port=8080",
+		Values: map[string]interface{}{"tls": false, "port": 8080},
+	}})
+}
+
+func (*productSuite) TestProductValueNames(c *gc.C) {
+	type backend struct{ dsn string }
+	cases := testing.Product(
+		testing.Dimension{
+			Name:       "backend",
+			Values:     []interface{}{backend{"file::memory:"}, backend{"postgres://"}},
+			ValueNames: []string{"sqlite", "postgres"},
+		},
+		testing.Dimension{Name: "replicas", Values: []interface{}{1, 3, 5}},
+	)
+	var names []string
+	for _, test := range cases {
+		names = append(names, test.Name)
+	}
+	c.Assert(names, jc.DeepEquals, []string{
+		"backend=sqlite replicas=1",
+		"backend=sqlite replicas=3",
+		"backend=sqlite replicas=5",
+		"backend=postgres replicas=1",
+		"backend=postgres replicas=3",
+		"backend=postgres replicas=5",
+	})
+	c.Assert(cases[3].Values["backend"], gc.Equals, backend{"postgres://"})
+}
+
+func (*productSuite) TestProductMismatchedValueNames(c *gc.C) {
+	c.Assert(func() {
+		testing.Product(testing.Dimension{Name: "a", Values: []interface{}{1, 2}, ValueNames: []string{"one"}})
+	}, gc.PanicMatches, `testing: dimension "a" has 2 values but 1 value names`)
+}
+
+func (*productSuite) TestProductEmptyDimension(c *gc.C) {
+	cases := testing.Product(
+		testing.Dimension{Name: "a", Values: []interface{}{1, 2}},
+		testing.Dimension{Name: "b"},
+	)
+	c.Assert(cases, gc.HasLen, 0)
+}
+
+func (*productSuite) TestWithout(c *gc.C) {
+	cases := testing.Product(
+		testing.Dimension{Name: "tls", Values: []interface{}{true, false}},
+		testing.Dimension{Name: "port", Values: []interface{}{443, 8080}},
+	).Without(func(test testing.ProductCase) bool {
+		return test.Values["tls"] == false && test.Values["port"] == 443
+	})
+	var names []string
+	for _, test := range cases {
+		names = append(names, test.Name)
+	}
+	c.Assert(names, jc.DeepEquals, []string{
+		"tls=true port=443",
+		"tls=true port=8080",
+		"tls=false port=8080",
+	})
+}
+
+func (*productSuite) TestRunTable(c *gc.C) {
+	cases := testing.Product(
+		testing.Dimension{Name: "a", Values: []interface{}{1, 2}},
+		testing.Dimension{Name: "b", Values: []interface{}{"x"}},
+	)
+	var sums []int
+	testing.RunTable(c, cases, func(c *gc.C, test testing.ProductCase) {
+		sums = append(sums, test.Values["a"].(int)+len(test.Values["b"].(string)))
+	})
+	c.Assert(sums, jc.DeepEquals, []int{2, 3})
+	c.Assert(c.GetTestLog(), gc.Equals, "test 0: a=1 b=x\ntest 1: a=2 b=x\n")
+}
@@ -0,0 +1,488 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+var propertyRuns = flag.Int("property.runs", 100, "Number of random inputs with which to check each property")
+
+const (
+	// maxPropertySize holds the size passed to generators for the last
+	// input of a property check.
+	maxPropertySize = 100
+
+	// maxPropertyShrinks holds the maximum number of inputs that are
+	// tried when shrinking the input for which a property fails.
+	maxPropertyShrinks = 1000
+)
+
+// Gen generates random values of type T for checking properties with
+// CheckProperty, and shrinks them to simpler values, so that a failing
+// input can be reduced to a minimal counterexample. Generators for
+// common types are provided by GenInt, GenSlice, GenStruct and the
+// like, and others can be written with the Generate and Shrink
+// functions.
+type Gen[T any] struct {
+	// Generate returns a random value. The size grows over the course
+	// of a property check, from 0 to 100, and bounds the size of the
+	// values generated, such as the length of slices.
+	Generate func(r *rand.Rand, size int) T
+
+	// Shrink returns values that are simpler than v, simplest first.
+	// It may be nil if values cannot be shrunk.
+	Shrink func(v T) []T
+}
+
+// Generator is implemented by Gen, whatever its type parameter, so
+// that generators of different types can be given to GenStruct.
+type Generator interface {
+	valueType() reflect.Type
+	generateValue(r *rand.Rand, size int) reflect.Value
+	shrinkValue(v reflect.Value) []reflect.Value
+}
+
+func (g Gen[T]) valueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (g Gen[T]) generateValue(r *rand.Rand, size int) reflect.Value {
+	v := g.Generate(r, size)
+	return reflect.ValueOf(&v).Elem()
+}
+
+func (g Gen[T]) shrinkValue(v reflect.Value) []reflect.Value {
+	if g.Shrink == nil {
+		return nil
+	}
+	var shrunk []reflect.Value
+	for _, s := range g.Shrink(v.Interface().(T)) {
+		s := s
+		shrunk = append(shrunk, reflect.ValueOf(&s).Elem())
+	}
+	return shrunk
+}
+
+// CheckProperty checks that the property holds for values generated by
+// gen, by calling prop with each of a number of random values, 100 by
+// default or as given with the -property.runs flag. The property fails
+// for a value if prop fails the test that it is given. When it fails,
+// the value is shrunk to the simplest one for which it still fails,
+// which is reported along with the property's failure for that value.
+// CheckProperty returns whether the property held:
+//
+//	testing.CheckProperty(c, testing.GenSlice(testing.GenInt(-100, 100)), func(c *gc.C, v []int) {
+//		sorted := append([]int(nil), v...)
+//		sort.Ints(sorted)
+//		c.Assert(sorted, gc.HasLen, len(v))
+//		c.Assert(sort.IntsAreSorted(sorted), jc.IsTrue)
+//	})
+//
+// The random values are drawn from a generator returned by
+// NewSeededRand, so a failure can be reproduced by running the test
+// again with the logged seed.
+//
+// As prop is run as a separate gocheck test for each value, it is
+// given its own *gc.C, which must be used in place of the test's.
+func CheckProperty[T any](c *gc.C, gen Gen[T], prop func(c *gc.C, v T)) bool {
+	failure := findPropertyFailure(NewSeededRand(c), gen, prop)
+	if failure != nil {
+		c.Errorf("property failed after %d runs with input:\n\t%#v\nshrunk %d times from:\n\t%#v\nfailure:\n%s",
+			failure.runs, failure.input, failure.shrinks, failure.original, indent(failure.output))
+		return false
+	}
+	c.Logf("property held for %d inputs", *propertyRuns)
+	return true
+}
+
+// propertyFailure describes an input for which a property failed.
+type propertyFailure[T any] struct {
+	// runs holds the number of inputs checked up to and including
+	// the original failing input.
+	runs int
+
+	// original holds the generated input for which the property
+	// failed, and input holds the input it was shrunk to.
+	original, input T
+
+	// shrinks holds the number of times the input was shrunk.
+	shrinks int
+
+	// output holds the failure output of prop for the shrunk input.
+	output string
+}
+
+// findPropertyFailure checks prop with values generated by gen from r,
+// as described for CheckProperty, and returns the shrunk input for
+// which it failed, or nil if it held for all of them.
+func findPropertyFailure[T any](r *rand.Rand, gen Gen[T], prop func(c *gc.C, v T)) *propertyFailure[T] {
+	runs := *propertyRuns
+	for i := 0; i < runs; i++ {
+		size := maxPropertySize
+		if runs > 1 {
+			size = i * maxPropertySize / (runs - 1)
+		}
+		v := gen.Generate(r, size)
+		output, ok := checkPropertyValue(v, prop)
+		if ok {
+			continue
+		}
+		shrunk, output, shrinks := shrinkProperty(gen, v, output, prop)
+		return &propertyFailure[T]{
+			runs:     i + 1,
+			original: v,
+			input:    shrunk,
+			shrinks:  shrinks,
+			output:   strings.TrimSpace(output),
+		}
+	}
+	return nil
+}
+
+// AssertProperty is like CheckProperty, but stops the test if the
+// property does not hold.
+func AssertProperty[T any](c *gc.C, gen Gen[T], prop func(c *gc.C, v T)) {
+	if !CheckProperty(c, gen, prop) {
+		c.FailNow()
+	}
+}
+
+// shrinkProperty shrinks v, for which prop fails with the given output,
+// to the simplest value for which it still fails, and returns that
+// value, the output of prop for it, and the number of times it was
+// shrunk.
+func shrinkProperty[T any](gen Gen[T], v T, output string, prop func(c *gc.C, v T)) (T, string, int) {
+	if gen.Shrink == nil {
+		return v, output, 0
+	}
+	shrinks, tries := 0, 0
+	for {
+		shrunk := false
+		for _, s := range gen.Shrink(v) {
+			if tries++; tries > maxPropertyShrinks {
+				return v, output, shrinks
+			}
+			if sOutput, ok := checkPropertyValue(s, prop); !ok {
+				v, output = s, sOutput
+				shrinks++
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			return v, output, shrinks
+		}
+	}
+}
+
+// checkPropertyValue runs prop for a single value, and returns its
+// output and whether it succeeded.
+func checkPropertyValue[T any](v T, prop func(c *gc.C, v T)) (string, bool) {
+	var output bytes.Buffer
+	result := gc.Run(&propertySuite{func(c *gc.C) { prop(c, v) }}, &gc.RunConf{Output: &output})
+	return output.String(), result.Passed()
+}
+
+// propertySuite runs a property for a single value.
+type propertySuite struct {
+	f func(c *gc.C)
+}
+
+func (s *propertySuite) TestProperty(c *gc.C) {
+	s.f(c)
+}
+
+// GenInt returns a generator of integers between min and max
+// inclusive, which shrinks them towards zero, or the bound closest to
+// zero.
+func GenInt(min, max int) Gen[int] {
+	if min > max {
+		panic(fmt.Sprintf("testing: GenInt min %d is greater than max %d", min, max))
+	}
+	target := 0
+	if target < min {
+		target = min
+	} else if target > max {
+		target = max
+	}
+	return Gen[int]{
+		Generate: func(r *rand.Rand, size int) int {
+			// Small ranges are generated directly, and large
+			// ones are limited by size around the target.
+			lo, hi := min, max
+			if int64(hi)-int64(lo) > int64(2*size) {
+				lo = clampInt(target-size, min, max)
+				hi = clampInt(target+size, min, max)
+			}
+			return lo + int(r.Int63n(int64(hi)-int64(lo)+1))
+		},
+		Shrink: func(v int) []int {
+			if v == target {
+				return nil
+			}
+			shrunk := []int{target}
+			for d := (v - target) / 2; d != 0; d /= 2 {
+				shrunk = append(shrunk, v-d)
+			}
+			return shrunk
+		},
+	}
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// GenFloat64 returns a generator of floating point numbers between min
+// and max, which shrinks them towards zero, or the bound closest to
+// zero, and towards integers.
+func GenFloat64(min, max float64) Gen[float64] {
+	if min > max {
+		panic(fmt.Sprintf("testing: GenFloat64 min %v is greater than max %v", min, max))
+	}
+	target := math.Max(min, math.Min(0, max))
+	return Gen[float64]{
+		Generate: func(r *rand.Rand, size int) float64 {
+			return min + r.Float64()*(max-min)
+		},
+		Shrink: func(v float64) []float64 {
+			if v == target {
+				return nil
+			}
+			shrunk := []float64{target}
+			if t := math.Trunc(v); t != v && t != target && t >= min && t <= max {
+				shrunk = append(shrunk, t)
+			}
+			if mid := target + (v-target)/2; mid != v && mid != target {
+				shrunk = append(shrunk, mid)
+			}
+			return shrunk
+		},
+	}
+}
+
+// GenBool returns a generator of booleans, which shrinks true to false.
+func GenBool() Gen[bool] {
+	return Gen[bool]{
+		Generate: func(r *rand.Rand, size int) bool {
+			return r.Intn(2) == 1
+		},
+		Shrink: func(v bool) []bool {
+			if v {
+				return []bool{false}
+			}
+			return nil
+		},
+	}
+}
+
+// GenString returns a generator of strings of up to size characters
+// chosen from the given alphabet, or from printable ASCII if it is
+// empty. It shrinks strings by removing characters and by replacing
+// them with the first character of the alphabet.
+func GenString(alphabet string) Gen[string] {
+	chars := []rune(alphabet)
+	if len(chars) == 0 {
+		for ch := ' '; ch <= '~'; ch++ {
+			chars = append(chars, ch)
+		}
+	}
+	runes := GenSlice(GenOneOf(chars...))
+	return Gen[string]{
+		Generate: func(r *rand.Rand, size int) string {
+			return string(runes.Generate(r, size))
+		},
+		Shrink: func(v string) []string {
+			var shrunk []string
+			for _, s := range runes.Shrink([]rune(v)) {
+				shrunk = append(shrunk, string(s))
+			}
+			return shrunk
+		},
+	}
+}
+
+// GenOneOf returns a generator of the given values, which shrinks
+// values towards the first.
+func GenOneOf[T any](values ...T) Gen[T] {
+	if len(values) == 0 {
+		panic("testing: GenOneOf called with no values")
+	}
+	return Gen[T]{
+		Generate: func(r *rand.Rand, size int) T {
+			return values[r.Intn(len(values))]
+		},
+		Shrink: func(v T) []T {
+			for i, value := range values {
+				if reflect.DeepEqual(value, v) {
+					return values[:i:i]
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// GenSlice returns a generator of slices of up to size elements
+// generated by elem. It shrinks slices by removing elements and by
+// shrinking the elements.
+func GenSlice[T any](elem Gen[T]) Gen[[]T] {
+	return Gen[[]T]{
+		Generate: func(r *rand.Rand, size int) []T {
+			v := make([]T, r.Intn(size+1))
+			for i := range v {
+				v[i] = elem.Generate(r, size)
+			}
+			return v
+		},
+		Shrink: func(v []T) [][]T {
+			if len(v) == 0 {
+				return nil
+			}
+			shrunk := [][]T{{}}
+			if len(v) > 2 {
+				half := len(v) / 2
+				shrunk = append(shrunk, v[:half:half], v[half:])
+			}
+			if len(v) > 1 {
+				for i := range v {
+					shrunk = append(shrunk, append(v[:i:i], v[i+1:]...))
+				}
+			}
+			if elem.Shrink != nil {
+				for i := range v {
+					for _, s := range elem.Shrink(v[i]) {
+						sv := append([]T(nil), v...)
+						sv[i] = s
+						shrunk = append(shrunk, sv)
+					}
+				}
+			}
+			return shrunk
+		},
+	}
+}
+
+// GenMap returns a generator of maps of up to size entries, with keys
+// generated by key and values generated by value. It shrinks maps by
+// removing entries and by shrinking the values.
+func GenMap[K comparable, V any](key Gen[K], value Gen[V]) Gen[map[K]V] {
+	return Gen[map[K]V]{
+		Generate: func(r *rand.Rand, size int) map[K]V {
+			n := r.Intn(size + 1)
+			v := make(map[K]V, n)
+			for i := 0; i < n; i++ {
+				v[key.Generate(r, size)] = value.Generate(r, size)
+			}
+			return v
+		},
+		Shrink: func(v map[K]V) []map[K]V {
+			if len(v) == 0 {
+				return nil
+			}
+			// The keys are sorted so that maps are shrunk in
+			// the same way each time.
+			keys := make([]K, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool {
+				return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+			})
+			without := func(k K) map[K]V {
+				m := make(map[K]V, len(v))
+				for k1, v1 := range v {
+					if k1 != k {
+						m[k1] = v1
+					}
+				}
+				return m
+			}
+			shrunk := []map[K]V{{}}
+			if len(v) > 1 {
+				for _, k := range keys {
+					shrunk = append(shrunk, without(k))
+				}
+			}
+			if value.Shrink != nil {
+				for _, k := range keys {
+					for _, s := range value.Shrink(v[k]) {
+						m := without(k)
+						m[k] = s
+						shrunk = append(shrunk, m)
+					}
+				}
+			}
+			return shrunk
+		},
+	}
+}
+
+// GenStruct returns a generator of structs of type T, with the
+// exported fields named in fields generated by the corresponding
+// generators, and other fields left as zero values. It shrinks structs
+// by shrinking their fields:
+//
+//	gen := testing.GenStruct[Config](map[string]testing.Generator{
+//		"Name": testing.GenString(""),
+//		"Port": testing.GenInt(0, 65535),
+//	})
+//
+// GenStruct panics if T is not a struct type, or if a generator is
+// given for a field that T does not have, or of the wrong type.
+func GenStruct[T any](fields map[string]Generator) Gen[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("testing: GenStruct type %v is not a struct", t))
+	}
+	names := make([]string, 0, len(fields))
+	for name, gen := range fields {
+		field, ok := t.FieldByName(name)
+		if !ok || field.PkgPath != "" {
+			panic(fmt.Sprintf("testing: GenStruct type %v has no exported field %q", t, name))
+		}
+		if gen.valueType() != field.Type {
+			panic(fmt.Sprintf("testing: GenStruct field %s of type %v given generator of %v", name, field.Type, gen.valueType()))
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return Gen[T]{
+		Generate: func(r *rand.Rand, size int) T {
+			var v T
+			rv := reflect.ValueOf(&v).Elem()
+			for _, name := range names {
+				rv.FieldByName(name).Set(fields[name].generateValue(r, size))
+			}
+			return v
+		},
+		Shrink: func(v T) []T {
+			var shrunk []T
+			for _, name := range names {
+				for _, s := range fields[name].shrinkValue(reflect.ValueOf(v).FieldByName(name)) {
+					sv := v
+					reflect.ValueOf(&sv).Elem().FieldByName(name).Set(s)
+					shrunk = append(shrunk, sv)
+				}
+			}
+			return shrunk
+		},
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type propertySuite struct{}
+
+var _ = gc.Suite(&propertySuite{})
+
+func (*propertySuite) TestPropertyHolds(c *gc.C) {
+	n := 0
+	ok := testing.CheckProperty(c, testing.GenSlice(testing.GenInt(-100, 100)), func(c *gc.C, v []int) {
+		n++
+		sorted := append([]int(nil), v...)
+		sort.Ints(sorted)
+		c.Assert(sorted, gc.HasLen, len(v))
+		c.Assert(sort.IntsAreSorted(sorted), jc.IsTrue)
+	})
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(n, gc.Equals, 100)
+	c.Assert(c.GetTestLog(), gc.Matches, `random seed -?\d+ .*\nproperty held for 100 inputs\n`)
+}
+
+// failingPropertySuite is run by TestPropertyFails rather than being
+// registered with gocheck.
+type failingPropertySuite struct{}
+
+func (*failingPropertySuite) TestProperty(c *gc.C) {
+	// The property fails for any slice holding a number of 10 or
+	// more, so the minimal counterexample is []int{10}.
+	testing.AssertProperty(c, testing.GenSlice(testing.GenInt(-1000, 1000)), func(c *gc.C, v []int) {
+		for _, n := range v {
+			c.Assert(n, jc.LessThan, 10)
+		}
+	})
+	c.Fatalf("AssertProperty did not stop the test")
+}
+
+func (*propertySuite) TestPropertyFails(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&failingPropertySuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, `(?s).*property failed after \d+ runs with input:\n\t\[\]int\{10\}\nshrunk \d+ times from:\n\t\[\]int\{.*\}\nfailure:\n.*`+
+		`\tFAIL: .*\n.*\t\.\.\. obtained int = 10\n.*`)
+}
+
+type config struct {
+	Name    string
+	Port    int
+	Enabled bool
+	Tags    map[string]float64
+	private int
+}
+
+func (*propertySuite) TestGenStruct(c *gc.C) {
+	gen := testing.GenStruct[config](map[string]testing.Generator{
+		"Name":    testing.GenString("abc"),
+		"Port":    testing.GenInt(1, 65535),
+		"Enabled": testing.GenBool(),
+		"Tags":    testing.GenMap(testing.GenString("xyz"), testing.GenFloat64(-1, 1)),
+	})
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		v := gen.Generate(r, i)
+		c.Assert(v.Name, gc.Matches, "[abc]*")
+		c.Assert(len(v.Name) <= i, jc.IsTrue)
+		c.Assert(v.Port >= 1 && v.Port <= 65535, jc.IsTrue)
+		c.Assert(len(v.Tags) <= i, jc.IsTrue)
+		for k, f := range v.Tags {
+			c.Assert(k, gc.Matches, "[xyz]*")
+			c.Assert(f >= -1 && f <= 1, jc.IsTrue)
+		}
+	}
+
+	shrunk := gen.Shrink(config{Name: "b", Port: 3, Enabled: true, Tags: map[string]float64{"x": 0.5}})
+	c.Assert(shrunk, jc.DeepEquals, []config{
+		{Name: "b", Port: 3, Tags: map[string]float64{"x": 0.5}},
+		{Name: "", Port: 3, Enabled: true, Tags: map[string]float64{"x": 0.5}},
+		{Name: "a", Port: 3, Enabled: true, Tags: map[string]float64{"x": 0.5}},
+		{Name: "b", Port: 1, Enabled: true, Tags: map[string]float64{"x": 0.5}},
+		{Name: "b", Port: 2, Enabled: true, Tags: map[string]float64{"x": 0.5}},
+		{Name: "b", Port: 3, Enabled: true, Tags: map[string]float64{}},
+		{Name: "b", Port: 3, Enabled: true, Tags: map[string]float64{"x": 0}},
+		{Name: "b", Port: 3, Enabled: true, Tags: map[string]float64{"x": 0.25}},
+	})
+}
+
+func (*propertySuite) TestGenStructErrors(c *gc.C) {
+	c.Assert(func() {
+		testing.GenStruct[int](nil)
+	}, gc.PanicMatches, `testing: GenStruct type int is not a struct`)
+	c.Assert(func() {
+		testing.GenStruct[config](map[string]testing.Generator{"private": testing.GenInt(0, 1)})
+	}, gc.PanicMatches, `testing: GenStruct type testing_test.config has no exported field "private"`)
+	c.Assert(func() {
+		testing.GenStruct[config](map[string]testing.Generator{"Port": testing.GenBool()})
+	}, gc.PanicMatches, `testing: GenStruct field Port of type int given generator of bool`)
+}
+
+var shrinkTests = []struct {
+	about  string
+	shrink func() interface{}
+	expect interface{}
+}{{
+	about:  "int towards zero",
+	shrink: func() interface{} { return testing.GenInt(-100, 100).Shrink(-20) },
+	expect: []int{0, -10, -15, -18, -19},
+}, {
+	about:  "int towards the bound closest to zero",
+	shrink: func() interface{} { return testing.GenInt(5, 100).Shrink(9) },
+	expect: []int{5, 7, 8},
+}, {
+	about:  "int at target",
+	shrink: func() interface{} { return testing.GenInt(5, 100).Shrink(5) },
+	expect: []int(nil),
+}, {
+	about:  "float towards zero and integers",
+	shrink: func() interface{} { return testing.GenFloat64(-10, 10).Shrink(3.5) },
+	expect: []float64{0, 3, 1.75},
+}, {
+	about:  "bool",
+	shrink: func() interface{} { return testing.GenBool().Shrink(true) },
+	expect: []bool{false},
+}, {
+	about:  "one of",
+	shrink: func() interface{} { return testing.GenOneOf("x", "y", "z").Shrink("z") },
+	expect: []string{"x", "y"},
+}, {
+	about:  "slice",
+	shrink: func() interface{} { return testing.GenSlice(testing.GenBool()).Shrink([]bool{true, false, true}) },
+	expect: [][]bool{
+		{},
+		{true},
+		{false, true},
+		{false, true},
+		{true, true},
+		{true, false},
+		{false, false, true},
+		{true, false, false},
+	},
+}, {
+	about:  "string",
+	shrink: func() interface{} { return testing.GenString("ab").Shrink("bb") },
+	expect: []string{"", "b", "b", "ab", "ba"},
+}}
+
+func (*propertySuite) TestShrink(c *gc.C) {
+	for i, test := range shrinkTests {
+		c.Logf("test %d: %s", i, test.about)
+		c.Check(test.shrink(), jc.DeepEquals, test.expect)
+	}
+}
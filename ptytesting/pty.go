@@ -0,0 +1,174 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package ptytesting provides a pseudo-terminal fixture, so that code
+// that behaves differently when attached to a terminal, such as by
+// using colour, wrapping output to the terminal width, or prompting for
+// input, can be tested as a user would see it.
+package ptytesting
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+var errNotSupported = errors.New("pseudo-terminals are only supported on linux")
+
+// Control sequences that may be sent to the terminal with Send, as a
+// user would type them.
+const (
+	Enter     = "\r"
+	CtrlC     = "\x03"
+	CtrlD     = "\x04"
+	Escape    = "\x1b"
+	Backspace = "\x7f"
+	Up        = "\x1b[A"
+	Down      = "\x1b[B"
+	Right     = "\x1b[C"
+	Left      = "\x1b[D"
+)
+
+// PTY is a pseudo-terminal. Code under test uses TTY, which is a
+// terminal device, as its standard streams, and the test plays the
+// part of the user with Send, and reads what the terminal displays
+// with Output:
+//
+//	pty := ptytesting.New(c)
+//	defer pty.Close()
+//	pty.SetSize(c, 120, 40)
+//	cmd := exec.Command("myapp", "login")
+//	pty.Start(c, cmd)
+//	pty.WaitOutput(c, `Username: `)
+//	pty.Send(c, "alice"+ptytesting.Enter)
+//
+// The terminal starts in its default mode, in which input is echoed
+// to the output and newlines are written as "\r\n".
+type PTY struct {
+	// TTY holds the terminal device.
+	TTY *os.File
+
+	master *os.File
+	done   chan struct{}
+
+	mu      sync.Mutex
+	output  bytes.Buffer
+	changed chan struct{}
+}
+
+// New returns a new pseudo-terminal, 80 columns wide and 24 rows high.
+// The test is skipped if pseudo-terminals are not supported on this
+// platform. The caller should call Close when finished with it.
+func New(c *gc.C) *PTY {
+	master, tty, err := openPTY()
+	if err == errNotSupported {
+		c.Skip(err.Error())
+	}
+	c.Assert(err, gc.IsNil)
+	p := &PTY{
+		TTY:     tty,
+		master:  master,
+		done:    make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+	p.SetSize(c, 80, 24)
+	go p.read()
+	return p
+}
+
+// Close closes the terminal, and waits for the output written to it to
+// be read. Any commands started with Start must have exited first.
+func (p *PTY) Close() {
+	p.TTY.Close()
+	<-p.done
+	p.master.Close()
+}
+
+// SetSize sets the terminal's size. Processes for which the terminal is
+// the controlling terminal receive SIGWINCH.
+func (p *PTY) SetSize(c *gc.C, cols, rows int) {
+	c.Assert(setSize(p.master, cols, rows), gc.IsNil)
+}
+
+// Size returns the terminal's size.
+func (p *PTY) Size(c *gc.C) (cols, rows int) {
+	cols, rows, err := getSize(p.master)
+	c.Assert(err, gc.IsNil)
+	return cols, rows
+}
+
+// Send sends input to the terminal, as if typed by the user.
+func (p *PTY) Send(c *gc.C, input string) {
+	_, err := p.master.WriteString(input)
+	c.Assert(err, gc.IsNil)
+}
+
+// Output returns the raw output displayed by the terminal so far,
+// including any control sequences.
+func (p *PTY) Output() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.output.String()
+}
+
+// WaitOutput waits until the terminal's output contains a match for
+// the regular expression, and fails the test if it does not within
+// testing.LongWait.
+func (p *PTY) WaitOutput(c *gc.C, pattern string) {
+	re := regexp.MustCompile(pattern)
+	timeout := time.After(testing.LongWait)
+	for {
+		p.mu.Lock()
+		matched := re.Match(p.output.Bytes())
+		changed := p.changed
+		p.mu.Unlock()
+		if matched {
+			return
+		}
+		select {
+		case <-changed:
+		case <-p.done:
+			c.Fatalf("terminal closed before output matched %q; output: %q", pattern, p.Output())
+		case <-timeout:
+			c.Fatalf("timed out waiting for output to match %q; output: %q", pattern, p.Output())
+		}
+	}
+}
+
+// Start starts the command with the terminal as its standard streams
+// and controlling terminal, in a new session. The caller should wait
+// for the command to finish.
+func (p *PTY) Start(c *gc.C, cmd *exec.Cmd) {
+	cmd.Stdin = p.TTY
+	cmd.Stdout = p.TTY
+	cmd.Stderr = p.TTY
+	cmd.SysProcAttr = sysProcAttr()
+	c.Assert(cmd.Start(), gc.IsNil)
+}
+
+// read reads the terminal's output until the terminal is closed.
+func (p *PTY) read() {
+	defer close(p.done)
+	buf := make([]byte, 4096)
+	for {
+		n, err := p.master.Read(buf)
+		if n > 0 {
+			p.mu.Lock()
+			p.output.Write(buf[:n])
+			close(p.changed)
+			p.changed = make(chan struct{})
+			p.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !linux
+
+package ptytesting
+
+import (
+	"os"
+	"syscall"
+)
+
+func openPTY() (master, tty *os.File, err error) {
+	return nil, nil, errNotSupported
+}
+
+func setSize(master *os.File, cols, rows int) error {
+	return errNotSupported
+}
+
+func getSize(master *os.File) (cols, rows int, err error) {
+	return 0, 0, errNotSupported
+}
+
+func sysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package ptytesting_test
+
+import (
+	"bufio"
+	"os/exec"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/ptytesting"
+)
+
+type ptySuite struct{}
+
+var _ = gc.Suite(&ptySuite{})
+
+func (*ptySuite) TestOutput(c *gc.C) {
+	pty := ptytesting.New(c)
+	defer pty.Close()
+	_, err := pty.TTY.WriteString("hello\nworld\n")
+	c.Assert(err, gc.IsNil)
+	pty.WaitOutput(c, `world`)
+	c.Assert(pty.Output(), gc.Equals, "hello\r\nworld\r\n")
+}
+
+func (*ptySuite) TestSend(c *gc.C) {
+	pty := ptytesting.New(c)
+	defer pty.Close()
+	pty.Send(c, "alice"+ptytesting.Enter)
+	line, err := bufio.NewReader(pty.TTY).ReadString('\n')
+	c.Assert(err, gc.IsNil)
+	c.Assert(line, gc.Equals, "alice\n")
+	// The input is echoed.
+	pty.WaitOutput(c, `alice\r\n`)
+}
+
+func (*ptySuite) TestSize(c *gc.C) {
+	pty := ptytesting.New(c)
+	defer pty.Close()
+	cols, rows := pty.Size(c)
+	c.Assert(cols, gc.Equals, 80)
+	c.Assert(rows, gc.Equals, 24)
+	pty.SetSize(c, 132, 50)
+	cols, rows = pty.Size(c)
+	c.Assert(cols, gc.Equals, 132)
+	c.Assert(rows, gc.Equals, 50)
+}
+
+func (*ptySuite) TestStart(c *gc.C) {
+	pty := ptytesting.New(c)
+	defer pty.Close()
+	pty.SetSize(c, 100, 30)
+	cmd := exec.Command("/bin/sh", "-c", `test -t 0 && test -t 1 && stty size && read line && echo "got $line"`)
+	pty.Start(c, cmd)
+	pty.WaitOutput(c, `30 100\r\n`)
+	pty.Send(c, "yes"+ptytesting.Enter)
+	c.Assert(cmd.Wait(), gc.IsNil)
+	pty.WaitOutput(c, `got yes\r\n`)
+}
+
+func (*ptySuite) TestStartInterrupt(c *gc.C) {
+	pty := ptytesting.New(c)
+	defer pty.Close()
+	cmd := exec.Command("/bin/sh", "-c", `echo ready; read line`)
+	pty.Start(c, cmd)
+	pty.WaitOutput(c, `ready`)
+	pty.Send(c, ptytesting.CtrlC)
+	c.Assert(cmd.Wait(), gc.ErrorMatches, "signal: interrupt")
+}
+
+func (*ptySuite) TestWaitOutputClosed(c *gc.C) {
+	pty := ptytesting.New(c)
+	pty.Close()
+	c.ExpectFailure("the terminal is closed")
+	pty.WaitOutput(c, `never`)
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// randSeedConfig holds the value of TEST_RAND_SEED. It is read when
+// the package is initialised because OsEnvSuite clears the
+// environment before tests run.
+var randSeedConfig = os.Getenv("TEST_RAND_SEED")
+
+// randBaseSeed holds the base seed used when TEST_RAND_SEED is not
+// set, chosen once so that every random choice in the test binary can
+// be replayed with it.
+var randBaseSeed = time.Now().UnixNano()
+
+// randCalls counts the calls to NewSeededRand made by each test.
+var randCalls = struct {
+	sync.Mutex
+	n map[*gc.C]int
+}{n: make(map[*gc.C]int)}
+
+// baseRandSeed returns the base seed from which random choices are
+// derived: the value of TEST_RAND_SEED if it is set, or a seed chosen
+// for the test binary otherwise.
+func baseRandSeed() (int64, error) {
+	if randSeedConfig == "" {
+		return randBaseSeed, nil
+	}
+	seed, err := strconv.ParseInt(randSeedConfig, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TEST_RAND_SEED %q: %v", randSeedConfig, err)
+	}
+	return seed, nil
+}
+
+// NewSeededRand returns a random number generator for use in the
+// current test, and writes its seed to the test log, which gocheck
+// shows if the test fails. The seed is derived from a base seed, the
+// name of the test and the number of earlier calls made by the test,
+// so each call gets a different sequence of numbers, and each test
+// gets the same sequences whichever other tests are run. The base seed
+// is chosen when the test binary starts, unless it is given in the
+// TEST_RAND_SEED environment variable, so that a failure can be
+// reproduced exactly by running the test again with the logged base
+// seed:
+//
+//	TEST_RAND_SEED=1234 go test -check.f TestSomething
+//
+// The helpers in this package that make random choices, such as
+// CheckProperty and RunSuites, derive them from the same base seed.
+func NewSeededRand(c *gc.C) *rand.Rand {
+	base, err := baseRandSeed()
+	if err != nil {
+		c.Fatalf("%v", err)
+	}
+	randCalls.Lock()
+	call := randCalls.n[c]
+	randCalls.n[c]++
+	randCalls.Unlock()
+	seed := deriveRandSeed(base, c.TestName(), call)
+	c.Logf("random seed %d (set TEST_RAND_SEED=%d to replay)", seed, base)
+	return rand.New(rand.NewSource(seed))
+}
+
+// deriveRandSeed returns the seed for the given call to NewSeededRand
+// by the named test.
+func deriveRandSeed(base int64, testName string, call int) int64 {
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, base)
+	h.Write([]byte(testName))
+	binary.Write(h, binary.LittleEndian, int64(call))
+	return int64(h.Sum64())
+}
+
+// PatchSeededRand sets the generator pointed to by dest to one returned
+// by NewSeededRand, and returns a function that restores the original.
+// Code under test that needs random numbers should draw them from a
+// package variable so that tests can patch it:
+//
+//	var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+//
+// The top level functions of math/rand cannot be made deterministic
+// this way, because rand.Seed has no effect in recent Go releases.
+func PatchSeededRand(c *gc.C, dest **rand.Rand) Restorer {
+	return PatchValue(dest, NewSeededRand(c))
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"math/rand"
+
+	gc "gopkg.in/check.v1"
+)
+
+type randSuite struct {
+	CleanupSuite
+}
+
+var _ = gc.Suite(&randSuite{})
+
+func (s *randSuite) TestSeedLogged(c *gc.C) {
+	s.PatchValue(&randSeedConfig, "")
+	NewSeededRand(c)
+	c.Assert(c.GetTestLog(), gc.Matches, `random seed -?\d+ \(set TEST_RAND_SEED=-?\d+ to replay\)\n`)
+}
+
+func (s *randSuite) TestReplaySeed(c *gc.C) {
+	s.PatchValue(&randSeedConfig, "1234")
+	r1 := NewSeededRand(c)
+	r2 := NewSeededRand(c)
+	seed1 := deriveRandSeed(1234, c.TestName(), 0)
+	seed2 := deriveRandSeed(1234, c.TestName(), 1)
+	c.Assert(seed1, gc.Not(gc.Equals), seed2)
+	expect1 := rand.New(rand.NewSource(seed1))
+	expect2 := rand.New(rand.NewSource(seed2))
+	for i := 0; i < 10; i++ {
+		c.Assert(r1.Int63(), gc.Equals, expect1.Int63())
+		c.Assert(r2.Int63(), gc.Equals, expect2.Int63())
+	}
+	c.Assert(c.GetTestLog(), gc.Equals, fmt.Sprintf(
+		"random seed %d (set TEST_RAND_SEED=1234 to replay)\n"+
+			"random seed %d (set TEST_RAND_SEED=1234 to replay)\n",
+		seed1, seed2,
+	))
+}
+
+func (s *randSuite) TestDeriveRandSeed(c *gc.C) {
+	seed := deriveRandSeed(1234, "randSuite.TestSomething", 0)
+	c.Assert(deriveRandSeed(1234, "randSuite.TestSomething", 0), gc.Equals, seed)
+	c.Assert(deriveRandSeed(1235, "randSuite.TestSomething", 0), gc.Not(gc.Equals), seed)
+	c.Assert(deriveRandSeed(1234, "randSuite.TestOther", 0), gc.Not(gc.Equals), seed)
+	c.Assert(deriveRandSeed(1234, "randSuite.TestSomething", 1), gc.Not(gc.Equals), seed)
+}
+
+func (s *randSuite) TestBaseSeedDefault(c *gc.C) {
+	s.PatchValue(&randSeedConfig, "")
+	seed, err := baseRandSeed()
+	c.Assert(err, gc.IsNil)
+	c.Assert(seed, gc.Equals, randBaseSeed)
+}
+
+func (s *randSuite) TestInvalidSeed(c *gc.C) {
+	s.PatchValue(&randSeedConfig, "bad")
+	c.ExpectFailure("invalid seed")
+	NewSeededRand(c)
+}
+
+var random = rand.New(rand.NewSource(1))
+
+func (s *randSuite) TestPatchSeededRand(c *gc.C) {
+	s.PatchValue(&randSeedConfig, "42")
+	orig := random
+	var suite CleanupSuite
+	suite.SetUpSuite(c)
+	suite.SetUpTest(c)
+	r := suite.PatchSeededRand(c, &random)
+	c.Assert(random, gc.Equals, r)
+	c.Assert(random.Int63(), gc.Equals, rand.New(rand.NewSource(deriveRandSeed(42, c.TestName(), 0))).Int63())
+	suite.TearDownTest(c)
+	suite.TearDownSuite(c)
+	c.Assert(random, gc.Equals, orig)
+}
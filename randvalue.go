@@ -0,0 +1,671 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GenValue returns a generator of random values of type T, which may
+// be any type made of booleans, numbers, strings, time.Time values,
+// slices, arrays, maps, pointers and structs. Struct fields are filled
+// recursively, and are constrained by "testgen" struct tags holding
+// comma-separated options:
+//
+//	min=N, max=N        the range of numbers
+//	minlen=N, maxlen=N  the range of the length of strings, slices and maps
+//	enum=a|b|c          the values that a field may take
+//	format=F            the format of strings: alnum, email, hostname,
+//	                    url, ipv4 or uuid
+//	nonnil              a pointer, slice or map is never nil
+//	-                   the field is left as its zero value
+//
+// For example:
+//
+//	type Endpoint struct {
+//		Name     string `testgen:"minlen=1,maxlen=20"`
+//		Host     string `testgen:"format=hostname"`
+//		Port     int    `testgen:"min=1,max=65535"`
+//		Protocol string `testgen:"enum=tcp|udp"`
+//		Next     *Endpoint
+//		cache    map[string]string
+//	}
+//
+// Unexported fields are left as their zero values. Pointers, slices
+// and maps are nil a quarter of the time, unless they are nonnil, and
+// recursive types are bounded by the generator's size, which halves for
+// the elements of each pointer, slice and map. Values are shrunk
+// towards the simplest values that satisfy the constraints.
+//
+// Together with CheckProperty, GenValue allows round trip tests of
+// encoders and fuzz tests of validators:
+//
+//	testing.CheckProperty(c, testing.GenValue[Endpoint](), func(c *gc.C, ep Endpoint) {
+//		data, err := json.Marshal(ep)
+//		c.Assert(err, jc.ErrorIsNil)
+//		var decoded Endpoint
+//		err = json.Unmarshal(data, &decoded)
+//		c.Assert(err, jc.ErrorIsNil)
+//		c.Assert(decoded, jc.DeepEquals, ep)
+//	})
+//
+// GenValue panics if T holds channels, functions or interfaces, other
+// than in fields excluded with "-", or has an invalid testgen tag.
+func GenValue[T any]() Gen[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if err := checkRandomType(t, valueConstraints{}, make(map[reflect.Type]bool)); err != nil {
+		panic(fmt.Sprintf("testing: cannot generate values of type %v: %v", t, err))
+	}
+	return Gen[T]{
+		Generate: func(r *rand.Rand, size int) T {
+			var v T
+			setRandomValue(r, reflect.ValueOf(&v).Elem(), valueConstraints{}, size)
+			return v
+		},
+		Shrink: func(v T) []T {
+			var shrunk []T
+			for _, s := range shrinkRandomValue(reflect.ValueOf(v), valueConstraints{}) {
+				shrunk = append(shrunk, s.Interface().(T))
+			}
+			return shrunk
+		},
+	}
+}
+
+// RandomValue returns a random value of type T, as generated by
+// GenValue with the largest size.
+func RandomValue[T any](r *rand.Rand) T {
+	return GenValue[T]().Generate(r, maxPropertySize)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// valueConstraints holds the constraints on a value given by a testgen
+// struct tag.
+type valueConstraints struct {
+	min, max       string
+	minLen, maxLen int
+	hasMaxLen      bool
+	enum           []string
+	format         string
+	nonNil         bool
+	skip           bool
+}
+
+// parseConstraints parses a testgen struct tag.
+func parseConstraints(tag string) (valueConstraints, error) {
+	var cons valueConstraints
+	if tag == "-" {
+		cons.skip = true
+		return cons, nil
+	}
+	if tag == "" {
+		return cons, nil
+	}
+	for _, option := range strings.Split(tag, ",") {
+		name, value, _ := strings.Cut(option, "=")
+		var err error
+		switch name {
+		case "min":
+			cons.min = value
+		case "max":
+			cons.max = value
+		case "minlen":
+			cons.minLen, err = strconv.Atoi(value)
+		case "maxlen":
+			cons.maxLen, err = strconv.Atoi(value)
+			cons.hasMaxLen = true
+		case "enum":
+			cons.enum = strings.Split(value, "|")
+		case "format":
+			if _, ok := stringFormats[value]; !ok {
+				return cons, fmt.Errorf("unknown format %q", value)
+			}
+			cons.format = value
+		case "nonnil":
+			cons.nonNil = true
+		default:
+			return cons, fmt.Errorf("unknown option %q", name)
+		}
+		if err != nil {
+			return cons, fmt.Errorf("invalid %s: %v", name, err)
+		}
+	}
+	if cons.hasMaxLen && cons.minLen > cons.maxLen {
+		return cons, fmt.Errorf("minlen %d is greater than maxlen %d", cons.minLen, cons.maxLen)
+	}
+	return cons, nil
+}
+
+// fieldConstraints returns the constraints given by the testgen tag of
+// a struct field, which has already been checked by checkRandomType.
+func fieldConstraints(field reflect.StructField) valueConstraints {
+	cons, _ := parseConstraints(field.Tag.Get("testgen"))
+	return cons
+}
+
+// checkRandomType checks that random values of type t can be
+// generated with the given constraints, recording the struct types
+// checked in seen to avoid checking recursive types forever.
+func checkRandomType(t reflect.Type, cons valueConstraints, seen map[reflect.Type]bool) error {
+	if cons.min != "" || cons.max != "" || len(cons.enum) > 0 {
+		for _, s := range append([]string{cons.min, cons.max}, cons.enum...) {
+			if s == "" {
+				continue
+			}
+			if err := setFromString(reflect.New(t).Elem(), s); err != nil {
+				return err
+			}
+		}
+	}
+	if cons.format != "" && t.Kind() != reflect.String {
+		return fmt.Errorf("format given for %v", t)
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return nil
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return checkRandomType(t.Elem(), valueConstraints{}, seen)
+	case reflect.Map:
+		if err := checkRandomType(t.Key(), valueConstraints{}, seen); err != nil {
+			return err
+		}
+		return checkRandomType(t.Elem(), valueConstraints{}, seen)
+	case reflect.Struct:
+		if t == timeType || seen[t] {
+			return nil
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			cons, err := parseConstraints(field.Tag.Get("testgen"))
+			if err != nil {
+				return fmt.Errorf("invalid testgen tag on field %s of %v: %v", field.Name, t, err)
+			}
+			if field.PkgPath != "" || cons.skip {
+				continue
+			}
+			if err := checkRandomType(field.Type, cons, seen); err != nil {
+				return fmt.Errorf("field %s of %v: %v", field.Name, t, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported type %v", t)
+}
+
+// setFromString sets v, of a boolean, number or string type, to the
+// value represented by s.
+func setFromString(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		v.SetString(s)
+	default:
+		return fmt.Errorf("cannot constrain values of %v", v.Type())
+	}
+	return nil
+}
+
+// setRandomValue sets v to a random value satisfying the given
+// constraints.
+func setRandomValue(r *rand.Rand, v reflect.Value, cons valueConstraints, size int) {
+	if len(cons.enum) > 0 {
+		setFromString(v, cons.enum[r.Intn(len(cons.enum))])
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lo, hi := intRange(v, cons, size)
+		v.SetInt(lo + int64(randUint64n(r, uint64(hi-lo))))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		lo, hi := uintRange(v, cons, size)
+		v.SetUint(lo + randUint64n(r, hi-lo))
+	case reflect.Float32, reflect.Float64:
+		lo, hi := floatRange(v, cons, size)
+		v.SetFloat(lo + r.Float64()*(hi-lo))
+	case reflect.String:
+		if cons.format != "" {
+			v.SetString(stringFormats[cons.format](r))
+			return
+		}
+		n := randomLen(r, cons, size)
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alnum[r.Intn(len(alnum))]
+		}
+		v.SetString(string(b))
+	case reflect.Ptr:
+		if !cons.nonNil && (size == 0 || r.Intn(4) == 0) {
+			return
+		}
+		elem := reflect.New(v.Type().Elem())
+		setRandomValue(r, elem.Elem(), valueConstraints{}, size/2)
+		v.Set(elem)
+	case reflect.Slice:
+		if !cons.nonNil && r.Intn(4) == 0 && cons.minLen == 0 {
+			return
+		}
+		n := randomLen(r, cons, size)
+		s := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			setRandomValue(r, s.Index(i), valueConstraints{}, size/2)
+		}
+		v.Set(s)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			setRandomValue(r, v.Index(i), valueConstraints{}, size/2)
+		}
+	case reflect.Map:
+		if !cons.nonNil && r.Intn(4) == 0 && cons.minLen == 0 {
+			return
+		}
+		n := randomLen(r, cons, size)
+		m := reflect.MakeMapWithSize(v.Type(), n)
+		// Duplicate keys are retried a limited number of times, as
+		// there may not be enough distinct keys.
+		for tries := 0; m.Len() < n && tries < 10*n; tries++ {
+			key := reflect.New(v.Type().Key()).Elem()
+			setRandomValue(r, key, valueConstraints{}, size/2)
+			elem := reflect.New(v.Type().Elem()).Elem()
+			setRandomValue(r, elem, valueConstraints{}, size/2)
+			m.SetMapIndex(key, elem)
+		}
+		v.Set(m)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			// Times are in UTC and whole seconds, so that they
+			// survive encoding.
+			start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+			v.Set(reflect.ValueOf(time.Unix(start+r.Int63n(100*365*24*60*60), 0).UTC()))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			cons := fieldConstraints(field)
+			if field.PkgPath != "" || cons.skip {
+				continue
+			}
+			setRandomValue(r, v.Field(i), cons, size)
+		}
+	}
+}
+
+// randomLen returns a random length for a string, slice or map.
+func randomLen(r *rand.Rand, cons valueConstraints, size int) int {
+	hi := cons.minLen + size
+	if cons.hasMaxLen && hi > cons.maxLen {
+		hi = cons.maxLen
+	}
+	return cons.minLen + r.Intn(hi-cons.minLen+1)
+}
+
+// randUint64n returns a random number between 0 and n inclusive.
+func randUint64n(r *rand.Rand, n uint64) uint64 {
+	if n == math.MaxUint64 {
+		return r.Uint64()
+	}
+	return r.Uint64() % (n + 1)
+}
+
+// intRange returns the range of values of a signed integer. Bounds
+// that are not constrained are limited by the size, around zero or the
+// other bound.
+func intRange(v reflect.Value, cons valueConstraints, size int) (int64, int64) {
+	bits := v.Type().Bits()
+	min, max := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1
+	lo, hi := -int64(size), int64(size)
+	if cons.min != "" {
+		lo, _ = strconv.ParseInt(cons.min, 10, bits)
+		if cons.max == "" {
+			hi = lo + 2*int64(size)
+			if hi < lo || hi > max {
+				hi = max
+			}
+		}
+	}
+	if cons.max != "" {
+		hi, _ = strconv.ParseInt(cons.max, 10, bits)
+		if cons.min == "" {
+			lo = hi - 2*int64(size)
+			if lo > hi || lo < min {
+				lo = min
+			}
+		}
+	}
+	return lo, hi
+}
+
+// uintRange returns the range of values of an unsigned integer. Bounds
+// that are not constrained are limited by the size, above zero or the
+// minimum.
+func uintRange(v reflect.Value, cons valueConstraints, size int) (uint64, uint64) {
+	bits := v.Type().Bits()
+	max := uint64(math.MaxUint64) >> (64 - bits)
+	lo, hi := uint64(0), uint64(2*size)
+	if cons.min != "" {
+		lo, _ = strconv.ParseUint(cons.min, 10, bits)
+		hi = lo + 2*uint64(size)
+		if hi < lo || hi > max {
+			hi = max
+		}
+	}
+	if cons.max != "" {
+		hi, _ = strconv.ParseUint(cons.max, 10, bits)
+	}
+	return lo, hi
+}
+
+// floatRange returns the range of values of a floating point number.
+// Bounds that are not constrained are limited by the size, around zero
+// or the other bound.
+func floatRange(v reflect.Value, cons valueConstraints, size int) (float64, float64) {
+	lo, hi := -float64(size), float64(size)
+	if cons.min != "" {
+		lo, _ = strconv.ParseFloat(cons.min, 64)
+		if cons.max == "" {
+			hi = lo + 2*float64(size)
+		}
+	}
+	if cons.max != "" {
+		hi, _ = strconv.ParseFloat(cons.max, 64)
+		if cons.min == "" {
+			lo = hi - 2*float64(size)
+		}
+	}
+	return lo, hi
+}
+
+const alnum = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// stringFormats holds functions that return random strings in the
+// formats that may be given in testgen tags.
+var stringFormats = map[string]func(r *rand.Rand) string{
+	"alnum": func(r *rand.Rand) string {
+		return randomString(r, alnum, 1+r.Intn(16))
+	},
+	"email": func(r *rand.Rand) string {
+		return randomString(r, lower, 1+r.Intn(10)) + "@" + randomHostname(r)
+	},
+	"hostname": randomHostname,
+	"url": func(r *rand.Rand) string {
+		return "https://" + randomHostname(r) + "/" + randomString(r, lower, r.Intn(10))
+	},
+	"ipv4": func(r *rand.Rand) string {
+		return fmt.Sprintf("%d.%d.%d.%d", 1+r.Intn(254), r.Intn(256), r.Intn(256), 1+r.Intn(254))
+	},
+	"uuid": func(r *rand.Rand) string {
+		b := make([]byte, 16)
+		r.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	},
+}
+
+const lower = "abcdefghijklmnopqrstuvwxyz"
+
+func randomHostname(r *rand.Rand) string {
+	labels := make([]string, 2+r.Intn(2))
+	for i := range labels {
+		labels[i] = randomString(r, lower, 1+r.Intn(8))
+	}
+	return strings.Join(labels, ".")
+}
+
+func randomString(r *rand.Rand, chars string, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = chars[r.Intn(len(chars))]
+	}
+	return string(b)
+}
+
+// shrinkRandomValue returns values that are simpler than v and satisfy
+// the constraints, simplest first.
+func shrinkRandomValue(v reflect.Value, cons valueConstraints) []reflect.Value {
+	if len(cons.enum) > 0 {
+		var shrunk []reflect.Value
+		for _, s := range cons.enum {
+			e := reflect.New(v.Type()).Elem()
+			setFromString(e, s)
+			if reflect.DeepEqual(e.Interface(), v.Interface()) {
+				break
+			}
+			shrunk = append(shrunk, e)
+		}
+		return shrunk
+	}
+	newValue := func() reflect.Value {
+		return reflect.New(v.Type()).Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return []reflect.Value{newValue()}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		target := int64(0)
+		if lo, hi := intRange(v, cons, 0); cons.min != "" && target < lo {
+			target = lo
+		} else if cons.max != "" && target > hi {
+			target = hi
+		}
+		n := v.Int()
+		if n == target {
+			return nil
+		}
+		shrunk := []reflect.Value{newValue()}
+		shrunk[0].SetInt(target)
+		for d := (n - target) / 2; d != 0; d /= 2 {
+			s := newValue()
+			s.SetInt(n - d)
+			shrunk = append(shrunk, s)
+		}
+		return shrunk
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		target, _ := uintRange(v, cons, 0)
+		n := v.Uint()
+		if n <= target {
+			return nil
+		}
+		shrunk := []reflect.Value{newValue()}
+		shrunk[0].SetUint(target)
+		for d := (n - target) / 2; d != 0; d /= 2 {
+			s := newValue()
+			s.SetUint(n - d)
+			shrunk = append(shrunk, s)
+		}
+		return shrunk
+	case reflect.Float32, reflect.Float64:
+		lo, hi := floatRange(v, cons, 0)
+		target := 0.0
+		if cons.min != "" && target < lo {
+			target = lo
+		} else if cons.max != "" && target > hi {
+			target = hi
+		}
+		f := v.Float()
+		if f == target {
+			return nil
+		}
+		shrunk := []reflect.Value{newValue()}
+		shrunk[0].SetFloat(target)
+		inRange := func(f float64) bool {
+			return (cons.min == "" || f >= lo) && (cons.max == "" || f <= hi)
+		}
+		if t := math.Trunc(f); t != f && t != target && inRange(t) {
+			s := newValue()
+			s.SetFloat(t)
+			shrunk = append(shrunk, s)
+		}
+		if mid := target + (f-target)/2; mid != f && mid != target {
+			s := newValue()
+			s.SetFloat(mid)
+			shrunk = append(shrunk, s)
+		}
+		return shrunk
+	case reflect.String:
+		s := v.String()
+		if cons.format != "" || len(s) <= cons.minLen {
+			return nil
+		}
+		var shrunk []reflect.Value
+		for _, n := range []int{cons.minLen, (len(s) + cons.minLen) / 2, len(s) - 1} {
+			if n < len(s) && (len(shrunk) == 0 || n > len(shrunk[len(shrunk)-1].String())) {
+				sv := newValue()
+				sv.SetString(s[:n])
+				shrunk = append(shrunk, sv)
+			}
+		}
+		return shrunk
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		var shrunk []reflect.Value
+		if !cons.nonNil {
+			shrunk = append(shrunk, newValue())
+		}
+		for _, elem := range shrinkRandomValue(v.Elem(), valueConstraints{}) {
+			p := reflect.New(v.Type().Elem())
+			p.Elem().Set(elem)
+			shrunk = append(shrunk, p)
+		}
+		return shrunk
+	case reflect.Slice, reflect.Array:
+		return shrinkElems(v, cons)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		var shrunk []reflect.Value
+		if !cons.nonNil && cons.minLen == 0 {
+			shrunk = append(shrunk, newValue())
+		}
+		// The keys are sorted so that maps are shrunk in the same
+		// way each time.
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		without := func(key reflect.Value) reflect.Value {
+			m := reflect.MakeMapWithSize(v.Type(), v.Len())
+			for _, k := range keys {
+				if k != key {
+					m.SetMapIndex(k, v.MapIndex(k))
+				}
+			}
+			return m
+		}
+		if v.Len() > cons.minLen {
+			for _, k := range keys {
+				shrunk = append(shrunk, without(k))
+			}
+		}
+		for _, k := range keys {
+			for _, elem := range shrinkRandomValue(v.MapIndex(k), valueConstraints{}) {
+				m := without(reflect.Value{})
+				m.SetMapIndex(k, elem)
+				shrunk = append(shrunk, m)
+			}
+		}
+		return shrunk
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return nil
+		}
+		var shrunk []reflect.Value
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			cons := fieldConstraints(field)
+			if field.PkgPath != "" || cons.skip {
+				continue
+			}
+			for _, f := range shrinkRandomValue(v.Field(i), cons) {
+				s := newValue()
+				s.Set(v)
+				s.Field(i).Set(f)
+				shrunk = append(shrunk, s)
+			}
+		}
+		return shrunk
+	}
+	return nil
+}
+
+// shrinkElems shrinks a slice or array by removing elements, if it is
+// a slice, and by shrinking the elements.
+func shrinkElems(v reflect.Value, cons valueConstraints) []reflect.Value {
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return nil
+	}
+	var shrunk []reflect.Value
+	copyOf := func() reflect.Value {
+		if v.Kind() == reflect.Array {
+			c := reflect.New(v.Type()).Elem()
+			c.Set(v)
+			return c
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		return c
+	}
+	if v.Kind() == reflect.Slice {
+		if !cons.nonNil && cons.minLen == 0 {
+			shrunk = append(shrunk, reflect.New(v.Type()).Elem())
+		}
+		if v.Len() > cons.minLen {
+			for i := 0; i < v.Len(); i++ {
+				s := reflect.MakeSlice(v.Type(), 0, v.Len()-1)
+				s = reflect.AppendSlice(s, v.Slice(0, i))
+				s = reflect.AppendSlice(s, v.Slice(i+1, v.Len()))
+				shrunk = append(shrunk, s)
+			}
+		}
+	}
+	for i := 0; i < v.Len(); i++ {
+		for _, elem := range shrinkRandomValue(v.Index(i), valueConstraints{}) {
+			s := copyOf()
+			s.Index(i).Set(elem)
+			shrunk = append(shrunk, s)
+		}
+	}
+	return shrunk
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net"
+	"net/url"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type randValueSuite struct{}
+
+var _ = gc.Suite(&randValueSuite{})
+
+type endpoint struct {
+	Name     string            `testgen:"minlen=1,maxlen=20"`
+	Host     string            `testgen:"format=hostname"`
+	Email    string            `testgen:"format=email"`
+	URL      string            `testgen:"format=url"`
+	Address  string            `testgen:"format=ipv4"`
+	ID       string            `testgen:"format=uuid"`
+	Port     int               `testgen:"min=1,max=65535"`
+	Weight   float64           `testgen:"min=0,max=1"`
+	Retries  uint8             `testgen:"max=5"`
+	Protocol string            `testgen:"enum=tcp|udp"`
+	Priority int               `testgen:"enum=1|10|100"`
+	Labels   map[string]string `testgen:"nonnil"`
+	Aliases  []string          `testgen:"minlen=1,maxlen=3"`
+	Created  time.Time
+	Enabled  bool
+	Next     *endpoint
+	Ignored  chan int `testgen:"-"`
+	cache    map[string]string
+}
+
+func (*randValueSuite) TestGenValue(c *gc.C) {
+	gen := testing.GenValue[endpoint]()
+	r := rand.New(rand.NewSource(1))
+	sawNext := false
+	for i := 0; i < 200; i++ {
+		ep := gen.Generate(r, i%101)
+		for ; ep.Next != nil; ep = *ep.Next {
+			sawNext = true
+		}
+		c.Assert(len(ep.Name) >= 1 && len(ep.Name) <= 20, jc.IsTrue, gc.Commentf("%q", ep.Name))
+		c.Assert(ep.Host, gc.Matches, `[a-z]+(\.[a-z]+)+`)
+		c.Assert(ep.Email, gc.Matches, `[a-z]+@[a-z]+(\.[a-z]+)+`)
+		u, err := url.Parse(ep.URL)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(u.Scheme, gc.Equals, "https")
+		c.Assert(net.ParseIP(ep.Address).To4(), gc.NotNil)
+		c.Assert(ep.ID, gc.Matches, `[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}`)
+		c.Assert(ep.Port >= 1 && ep.Port <= 65535, jc.IsTrue)
+		c.Assert(ep.Weight >= 0 && ep.Weight <= 1, jc.IsTrue)
+		c.Assert(ep.Retries <= 5, jc.IsTrue)
+		c.Assert(ep.Protocol, gc.Matches, "tcp|udp")
+		c.Assert(ep.Priority == 1 || ep.Priority == 10 || ep.Priority == 100, jc.IsTrue)
+		c.Assert(ep.Labels, gc.NotNil)
+		c.Assert(len(ep.Aliases) >= 1 && len(ep.Aliases) <= 3, jc.IsTrue)
+		c.Assert(ep.Created.Location(), gc.Equals, time.UTC)
+		c.Assert(ep.Created.Year() >= 2000, jc.IsTrue)
+		c.Assert(ep.Ignored, gc.IsNil)
+		c.Assert(ep.cache, gc.IsNil)
+	}
+	c.Assert(sawNext, jc.IsTrue)
+}
+
+func (*randValueSuite) TestRandomValueDeterministic(c *gc.C) {
+	v1 := testing.RandomValue[endpoint](rand.New(rand.NewSource(42)))
+	v2 := testing.RandomValue[endpoint](rand.New(rand.NewSource(42)))
+	c.Assert(v1, jc.DeepEquals, v2)
+}
+
+type roundTrip struct {
+	Name    string `json:"name"`
+	Count   int64  `json:"count"`
+	Ratio   float32
+	Tags    []string
+	Limits  map[string]uint16
+	Created time.Time
+	Parent  *roundTrip
+	Matrix  [2][2]int8
+}
+
+func (*randValueSuite) TestJSONRoundTrip(c *gc.C) {
+	testing.AssertProperty(c, testing.GenValue[roundTrip](), func(c *gc.C, v roundTrip) {
+		data, err := json.Marshal(v)
+		c.Assert(err, jc.ErrorIsNil)
+		var decoded roundTrip
+		err = json.Unmarshal(data, &decoded)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(decoded, jc.DeepEquals, v)
+	})
+}
+
+type shrinkable struct {
+	N       int      `testgen:"min=3"`
+	Level   string   `testgen:"enum=low|mid|high"`
+	Items   []uint   `testgen:"minlen=1"`
+	Enabled bool     `testgen:"-"`
+	Ptr     *float64 `testgen:"nonnil"`
+}
+
+func (*randValueSuite) TestShrink(c *gc.C) {
+	gen := testing.GenValue[shrinkable]()
+	f := 2.5
+	shrunk := gen.Shrink(shrinkable{N: 6, Level: "high", Items: []uint{4, 0}, Enabled: true, Ptr: &f})
+	zero, two, half := 0.0, 2.0, 1.25
+	c.Assert(shrunk, jc.DeepEquals, []shrinkable{
+		{N: 3, Level: "high", Items: []uint{4, 0}, Enabled: true, Ptr: &f},
+		{N: 5, Level: "high", Items: []uint{4, 0}, Enabled: true, Ptr: &f},
+		{N: 6, Level: "low", Items: []uint{4, 0}, Enabled: true, Ptr: &f},
+		{N: 6, Level: "mid", Items: []uint{4, 0}, Enabled: true, Ptr: &f},
+		{N: 6, Level: "high", Items: []uint{0}, Enabled: true, Ptr: &f},
+		{N: 6, Level: "high", Items: []uint{4}, Enabled: true, Ptr: &f},
+		{N: 6, Level: "high", Items: []uint{0, 0}, Enabled: true, Ptr: &f},
+		{N: 6, Level: "high", Items: []uint{2, 0}, Enabled: true, Ptr: &f},
+		{N: 6, Level: "high", Items: []uint{3, 0}, Enabled: true, Ptr: &f},
+		{N: 6, Level: "high", Items: []uint{4, 0}, Enabled: true, Ptr: &zero},
+		{N: 6, Level: "high", Items: []uint{4, 0}, Enabled: true, Ptr: &two},
+		{N: 6, Level: "high", Items: []uint{4, 0}, Enabled: true, Ptr: &half},
+	})
+}
+
+type badTag struct {
+	Port int `testgen:"min=x"`
+}
+
+type badOption struct {
+	Name string `testgen:"pattern=[a-z]+"`
+}
+
+type badFormat struct {
+	Port int `testgen:"format=email"`
+}
+
+type unsupported struct {
+	Callback func()
+}
+
+func (*randValueSuite) TestGenValueErrors(c *gc.C) {
+	c.Assert(func() { testing.GenValue[badTag]() }, gc.PanicMatches,
+		`testing: cannot generate values of type testing_test.badTag: field Port of testing_test.badTag: strconv.ParseInt: parsing "x": invalid syntax`)
+	c.Assert(func() { testing.GenValue[badOption]() }, gc.PanicMatches,
+		`testing: cannot generate values of type testing_test.badOption: invalid testgen tag on field Name of testing_test.badOption: unknown option "pattern"`)
+	c.Assert(func() { testing.GenValue[badFormat]() }, gc.PanicMatches,
+		`testing: cannot generate values of type testing_test.badFormat: field Port of testing_test.badFormat: format given for int`)
+	c.Assert(func() { testing.GenValue[unsupported]() }, gc.PanicMatches,
+		`testing: cannot generate values of type testing_test.unsupported: field Callback of testing_test.unsupported: unsupported type func\(\)`)
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package redistesting
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	gc "gopkg.in/check.v1"
+)
+
+type hasKeyChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasKey checks that the obtained *miniredis.Miniredis holds the
+// given key in its selected database.
+var HasKey gc.Checker = &hasKeyChecker{
+	&gc.CheckerInfo{Name: "HasKey", Params: []string{"obtained", "key"}},
+}
+
+func (checker *hasKeyChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	m, key, errStr := serverAndKey(params)
+	if errStr != "" {
+		return false, errStr
+	}
+	return m.Exists(key), ""
+}
+
+type keyEqualsChecker struct {
+	*gc.CheckerInfo
+}
+
+// KeyEquals checks that the given key of the obtained
+// *miniredis.Miniredis holds the expected value, whose type depends on
+// the key's type:
+//
+//	string        string
+//	list          []string, in order
+//	set           []string, in any order
+//	hash          map[string]string
+//	sorted set    map[string]float64, mapping members to scores
+var KeyEquals gc.Checker = &keyEqualsChecker{
+	&gc.CheckerInfo{Name: "KeyEquals", Params: []string{"obtained", "key", "expected"}},
+}
+
+func (checker *keyEqualsChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	m, key, errStr := serverAndKey(params)
+	if errStr != "" {
+		return false, errStr
+	}
+	if !m.Exists(key) {
+		return false, fmt.Sprintf("key %q does not exist", key)
+	}
+	obtained, err := keyContents(m, key)
+	if err != nil {
+		return false, fmt.Sprintf("cannot read key %q: %v", key, err)
+	}
+	if expected, ok := params[2].([]string); ok && m.Type(key) == "set" {
+		// Sets are unordered.
+		expected = append([]string(nil), expected...)
+		sort.Strings(expected)
+		params[2] = expected
+	}
+	if reflect.TypeOf(obtained) != reflect.TypeOf(params[2]) {
+		return false, fmt.Sprintf("key %q holds a %s, which cannot be compared with %T", key, m.Type(key), params[2])
+	}
+	if !reflect.DeepEqual(obtained, params[2]) {
+		return false, fmt.Sprintf("key %q holds %#v", key, obtained)
+	}
+	return true, ""
+}
+
+// keyContents returns the contents of the key, as described for
+// KeyEquals. The members of a set are sorted.
+func keyContents(m *miniredis.Miniredis, key string) (interface{}, error) {
+	switch t := m.Type(key); t {
+	case "string":
+		return m.Get(key)
+	case "list":
+		return m.List(key)
+	case "set":
+		members, err := m.Members(key)
+		sort.Strings(members)
+		return members, err
+	case "hash":
+		return hashContents(m, key)
+	case "zset":
+		return m.SortedSet(key)
+	default:
+		return nil, fmt.Errorf("unsupported type %q", t)
+	}
+}
+
+// hashContents returns the fields and values of the hash at key.
+func hashContents(m *miniredis.Miniredis, key string) (map[string]string, error) {
+	fields, err := m.HKeys(key)
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[string]string)
+	for _, f := range fields {
+		contents[f] = m.HGet(key, f)
+	}
+	return contents, nil
+}
+
+type hasTTLChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasTTL checks that the given key of the obtained
+// *miniredis.Miniredis has exactly the expected time to live. An
+// expected TTL of zero checks that the key exists but does not
+// expire. Because RedisSuite drives expiry with a test clock, TTLs are
+// exact.
+var HasTTL gc.Checker = &hasTTLChecker{
+	&gc.CheckerInfo{Name: "HasTTL", Params: []string{"obtained", "key", "ttl"}},
+}
+
+func (checker *hasTTLChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	m, key, errStr := serverAndKey(params)
+	if errStr != "" {
+		return false, errStr
+	}
+	expected, ok := params[2].(time.Duration)
+	if !ok {
+		return false, fmt.Sprintf("ttl must be of type time.Duration, got %T", params[2])
+	}
+	if !m.Exists(key) {
+		return false, fmt.Sprintf("key %q does not exist", key)
+	}
+	if ttl := m.TTL(key); ttl != expected {
+		return false, fmt.Sprintf("key %q has TTL %v", key, ttl)
+	}
+	return true, ""
+}
+
+// serverAndKey returns the server and key from the checker
+// parameters.
+func serverAndKey(params []interface{}) (*miniredis.Miniredis, string, string) {
+	m, ok := params[0].(*miniredis.Miniredis)
+	if !ok {
+		return nil, "", fmt.Sprintf("obtained value must be of type *miniredis.Miniredis, got %T", params[0])
+	}
+	key, ok := params[1].(string)
+	if !ok {
+		return nil, "", fmt.Sprintf("key must be a string, got %T", params[1])
+	}
+	return m, key, ""
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package redistesting_test
+
+import (
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/redistesting"
+)
+
+type checkerSuite struct {
+	redis *miniredis.Miniredis
+}
+
+var _ = gc.Suite(&checkerSuite{})
+
+func (s *checkerSuite) SetUpTest(c *gc.C) {
+	// The checkers use only the direct API, so the server need not
+	// be started.
+	s.redis = miniredis.NewMiniRedis()
+	s.redis.Set("str", "hello")
+	s.redis.SetTTL("str", 30*time.Second)
+	s.redis.Lpush("list", "b")
+	s.redis.Lpush("list", "a")
+	s.redis.SetAdd("set", "x", "y", "z")
+	s.redis.HSet("hash", "f1", "v1", "f2", "v2")
+	s.redis.ZAdd("zset", 1.5, "m")
+}
+
+var checkerTests = []struct {
+	about    string
+	checker  gc.Checker
+	key      interface{}
+	expected interface{}
+	message  string
+}{{
+	about:   "key exists",
+	checker: redistesting.HasKey,
+	key:     "str",
+}, {
+	about:    "string",
+	checker:  redistesting.KeyEquals,
+	key:      "str",
+	expected: "hello",
+}, {
+	about:    "string differs",
+	checker:  redistesting.KeyEquals,
+	key:      "str",
+	expected: "goodbye",
+	message:  `key "str" holds "hello"`,
+}, {
+	about:    "list",
+	checker:  redistesting.KeyEquals,
+	key:      "list",
+	expected: []string{"a", "b"},
+}, {
+	about:    "list in wrong order",
+	checker:  redistesting.KeyEquals,
+	key:      "list",
+	expected: []string{"b", "a"},
+	message:  `key "list" holds \[\]string{"a", "b"}`,
+}, {
+	about:    "set in any order",
+	checker:  redistesting.KeyEquals,
+	key:      "set",
+	expected: []string{"z", "x", "y"},
+}, {
+	about:    "hash",
+	checker:  redistesting.KeyEquals,
+	key:      "hash",
+	expected: map[string]string{"f1": "v1", "f2": "v2"},
+}, {
+	about:    "sorted set",
+	checker:  redistesting.KeyEquals,
+	key:      "zset",
+	expected: map[string]float64{"m": 1.5},
+}, {
+	about:    "wrong expected type",
+	checker:  redistesting.KeyEquals,
+	key:      "hash",
+	expected: "v1",
+	message:  `key "hash" holds a hash, which cannot be compared with string`,
+}, {
+	about:    "missing key",
+	checker:  redistesting.KeyEquals,
+	key:      "nothing",
+	expected: "",
+	message:  `key "nothing" does not exist`,
+}, {
+	about:    "key not a string",
+	checker:  redistesting.KeyEquals,
+	key:      1,
+	expected: "",
+	message:  `key must be a string, got int`,
+}, {
+	about:    "TTL",
+	checker:  redistesting.HasTTL,
+	key:      "str",
+	expected: 30 * time.Second,
+}, {
+	about:    "TTL differs",
+	checker:  redistesting.HasTTL,
+	key:      "str",
+	expected: time.Minute,
+	message:  `key "str" has TTL 30s`,
+}, {
+	about:    "no TTL",
+	checker:  redistesting.HasTTL,
+	key:      "list",
+	expected: time.Duration(0),
+}, {
+	about:    "TTL of wrong type",
+	checker:  redistesting.HasTTL,
+	key:      "str",
+	expected: 30,
+	message:  `ttl must be of type time.Duration, got int`,
+}}
+
+func (s *checkerSuite) TestCheckers(c *gc.C) {
+	for i, test := range checkerTests {
+		c.Logf("test %d: %s", i, test.about)
+		params := []interface{}{s.redis, test.key}
+		if test.checker != redistesting.HasKey {
+			params = append(params, test.expected)
+		}
+		result, message := test.checker.Check(params, nil)
+		c.Check(result, gc.Equals, test.message == "")
+		c.Check(message, gc.Matches, test.message)
+	}
+}
+
+func (s *checkerSuite) TestHasKeyMissing(c *gc.C) {
+	result, message := redistesting.HasKey.Check([]interface{}{s.redis, "nothing"}, nil)
+	c.Assert(result, gc.Equals, false)
+	c.Assert(message, gc.Equals, "")
+}
+
+func (s *checkerSuite) TestObtainedWrongType(c *gc.C) {
+	result, message := redistesting.HasKey.Check([]interface{}{"redis", "str"}, nil)
+	c.Assert(result, gc.Equals, false)
+	c.Assert(message, gc.Equals, "obtained value must be of type *miniredis.Miniredis, got string")
+}
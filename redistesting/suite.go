@@ -0,0 +1,74 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package redistesting provides an in-process Redis server for tests,
+// backed by miniredis, with checkers for its contents.
+package redistesting
+
+import (
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/testclock"
+)
+
+// Epoch holds the time at which each test's Clock starts.
+var Epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// RedisSuite runs an in-process Redis server for the tests in a suite.
+// The server keeps its address for the life of the suite, so that
+// clients may be configured once, but is emptied before each test.
+//
+// Key expiry is driven by Clock, which is reset to Epoch for each
+// test: code under test that also uses Clock sees the same time as the
+// server, and Advance moves both forward together.
+type RedisSuite struct {
+	// Redis holds the server.
+	Redis *miniredis.Miniredis
+
+	// Clock holds the clock for the current test.
+	Clock *testclock.Clock
+}
+
+func (s *RedisSuite) SetUpSuite(c *gc.C) {
+	s.Redis = miniredis.NewMiniRedis()
+	err := s.Redis.Start()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *RedisSuite) TearDownSuite(c *gc.C) {
+	if s.Redis != nil {
+		s.Redis.Close()
+		s.Redis = nil
+	}
+}
+
+func (s *RedisSuite) SetUpTest(c *gc.C) {
+	s.Redis.FlushAll()
+	s.Redis.Select(0)
+	s.Redis.SetError("")
+	s.Clock = testclock.NewClock(Epoch)
+	s.Redis.SetTime(Epoch)
+}
+
+func (s *RedisSuite) TearDownTest(c *gc.C) {}
+
+// Addr returns the address of the server.
+func (s *RedisSuite) Addr() string {
+	return s.Redis.Addr()
+}
+
+// URL returns the redis:// URL of the server.
+func (s *RedisSuite) URL() string {
+	return "redis://" + s.Redis.Addr()
+}
+
+// Advance advances Clock by d, and reduces the TTLs of the server's
+// keys by d, expiring those that reach zero.
+func (s *RedisSuite) Advance(d time.Duration) {
+	s.Clock.Advance(d)
+	s.Redis.SetTime(s.Clock.Now())
+	s.Redis.FastForward(d)
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package redistesting_test
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/redistesting"
+)
+
+type suiteSuite struct {
+	redistesting.RedisSuite
+}
+
+var _ = gc.Suite(&suiteSuite{})
+
+// do sends a command to the server as a real client would, and
+// returns the first line of the reply.
+func (s *suiteSuite) do(c *gc.C, args ...string) string {
+	conn, err := net.Dial("tcp", s.Addr())
+	c.Assert(err, gc.IsNil)
+	defer conn.Close()
+	fmt.Fprintf(conn, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	c.Assert(err, gc.IsNil)
+	return strings.TrimSuffix(reply, "\r\n")
+}
+
+func (s *suiteSuite) TestURL(c *gc.C) {
+	c.Assert(s.URL(), gc.Equals, "redis://"+s.Addr())
+}
+
+func (s *suiteSuite) TestExpiry(c *gc.C) {
+	c.Assert(s.do(c, "SET", "session", "abc", "EX", "60"), gc.Equals, "+OK")
+	c.Assert(s.Redis, redistesting.KeyEquals, "session", "abc")
+	c.Assert(s.Redis, redistesting.HasTTL, "session", 60*time.Second)
+
+	s.Advance(59 * time.Second)
+	c.Assert(s.Redis, redistesting.HasTTL, "session", time.Second)
+	c.Assert(s.do(c, "TTL", "session"), gc.Equals, ":1")
+
+	s.Advance(time.Second)
+	c.Assert(s.Redis, gc.Not(redistesting.HasKey), "session")
+}
+
+func (s *suiteSuite) TestExpireAt(c *gc.C) {
+	// Absolute expiry times are compared with the suite's clock.
+	c.Assert(s.Clock.Now(), gc.Equals, redistesting.Epoch)
+	at := redistesting.Epoch.Add(10 * time.Second).Unix()
+	s.Redis.Set("k", "v")
+	c.Assert(s.do(c, "EXPIREAT", "k", strconv.FormatInt(at, 10)), gc.Equals, ":1")
+	c.Assert(s.Redis, redistesting.HasTTL, "k", 10*time.Second)
+	s.Advance(10 * time.Second)
+	c.Assert(s.Redis, gc.Not(redistesting.HasKey), "k")
+}
+
+func (s *suiteSuite) TestClockWaiters(c *gc.C) {
+	// Code under test that uses the suite's clock is woken by
+	// Advance.
+	ch := s.Clock.After(time.Minute)
+	s.Advance(time.Minute)
+	select {
+	case <-ch:
+	default:
+		c.Fatalf("clock did not fire")
+	}
+}
+
+// The two tests below each check that they do not see the other's
+// data, whichever runs first.
+
+func (s *suiteSuite) TestIsolation1(c *gc.C) {
+	c.Assert(s.Redis.Keys(), gc.HasLen, 0)
+	s.Redis.Set("shared", "1")
+	s.Redis.SetError("LOADING")
+}
+
+func (s *suiteSuite) TestIsolation2(c *gc.C) {
+	c.Assert(s.Redis.Keys(), gc.HasLen, 0)
+	c.Assert(s.do(c, "SET", "shared", "2"), gc.Equals, "+OK")
+	c.Assert(s.Redis.Keys(), jc.DeepEquals, []string{"shared"})
+}
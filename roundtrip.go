@@ -0,0 +1,101 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"unicode/utf8"
+
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// CheckRoundTrip checks that value, encoded with marshal and decoded
+// again with unmarshal into a new value of the same type, is unchanged,
+// and returns whether it is. If the value changes, the differences are
+// reported as jc.DeepEquals reports them, along with the encoding. The
+// functions have the signatures of json.Marshal and json.Unmarshal, so
+// any codec can be checked:
+//
+//	testing.CheckRoundTrip(c, cfg, toml.Marshal, toml.Unmarshal)
+//
+// CheckJSONRoundTrip, CheckYAMLRoundTrip and CheckGobRoundTrip check
+// round trips through the standard codecs.
+//
+// CheckRoundTrip combines well with CheckProperty, to check the round
+// trip of many values:
+//
+//	testing.AssertProperty(c, testing.GenValue[Config](), func(c *gc.C, cfg Config) {
+//		testing.CheckJSONRoundTrip(c, cfg)
+//	})
+func CheckRoundTrip[T any](c *gc.C, value T, marshal func(interface{}) ([]byte, error), unmarshal func([]byte, interface{}) error) bool {
+	data, err := marshal(value)
+	if err != nil {
+		c.Errorf("cannot marshal %T: %v", value, err)
+		return false
+	}
+	var decoded T
+	if err := unmarshal(data, &decoded); err != nil {
+		c.Errorf("cannot unmarshal %T: %v\nencoded as:\n%s", value, err, showEncoding(data))
+		return false
+	}
+	return c.Check(decoded, jc.DeepEquals, value, gc.Commentf("value changed by round trip; encoded as:\n%s", showEncoding(data)))
+}
+
+// AssertRoundTrip is like CheckRoundTrip, but stops the test if the
+// value does not survive the round trip.
+func AssertRoundTrip[T any](c *gc.C, value T, marshal func(interface{}) ([]byte, error), unmarshal func([]byte, interface{}) error) {
+	if !CheckRoundTrip(c, value, marshal, unmarshal) {
+		c.FailNow()
+	}
+}
+
+// CheckJSONRoundTrip checks the round trip of value through
+// encoding/json (see CheckRoundTrip).
+func CheckJSONRoundTrip[T any](c *gc.C, value T) bool {
+	return CheckRoundTrip(c, value, json.Marshal, json.Unmarshal)
+}
+
+// CheckYAMLRoundTrip checks the round trip of value through
+// gopkg.in/yaml.v2 (see CheckRoundTrip).
+func CheckYAMLRoundTrip[T any](c *gc.C, value T) bool {
+	return CheckRoundTrip(c, value, yaml.Marshal, yaml.Unmarshal)
+}
+
+// CheckGobRoundTrip checks the round trip of value through
+// encoding/gob (see CheckRoundTrip). Note that gob does not send the
+// zero values of struct fields, so empty slices and maps, and pointers
+// to zero values, held in struct fields are decoded as nil.
+func CheckGobRoundTrip[T any](c *gc.C, value T) bool {
+	return CheckRoundTrip(c, value, gobMarshal, gobUnmarshal)
+}
+
+// gobMarshal encodes v with encoding/gob.
+func gobMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gobUnmarshal decodes data encoded with gobMarshal into the value
+// pointed to by v.
+func gobUnmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// showEncoding returns encoded data as text, indented, or as a hex
+// dump if it is binary.
+func showEncoding(data []byte) string {
+	if utf8.Valid(data) && bytes.IndexByte(data, 0) == -1 {
+		return indent(string(data))
+	}
+	return indent(hex.Dump(data))
+}
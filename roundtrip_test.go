@@ -0,0 +1,134 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type roundTripSuite struct{}
+
+var _ = gc.Suite(&roundTripSuite{})
+
+type roundTripConfig struct {
+	Name   string            `json:"name" yaml:"name"`
+	Ports  []int             `json:"ports" yaml:"ports"`
+	Labels map[string]string `json:"labels" yaml:"labels"`
+	Parent *roundTripConfig  `json:"parent" yaml:"parent"`
+}
+
+var testRoundTripConfig = roundTripConfig{
+	Name:   "foo",
+	Ports:  []int{80, 443},
+	Labels: map[string]string{"a": "b"},
+	Parent: &roundTripConfig{Name: "bar", Ports: []int{1}, Labels: map[string]string{"c": "d"}},
+}
+
+func (*roundTripSuite) TestStandardCodecs(c *gc.C) {
+	c.Assert(testing.CheckJSONRoundTrip(c, testRoundTripConfig), jc.IsTrue)
+	c.Assert(testing.CheckYAMLRoundTrip(c, testRoundTripConfig), jc.IsTrue)
+	c.Assert(testing.CheckGobRoundTrip(c, testRoundTripConfig), jc.IsTrue)
+	c.Assert(testing.CheckJSONRoundTrip(c, &testRoundTripConfig), jc.IsTrue)
+	c.Assert(testing.CheckJSONRoundTrip(c, []string{"x"}), jc.IsTrue)
+}
+
+func (*roundTripSuite) TestCustomCodec(c *gc.C) {
+	marshal := func(v interface{}) ([]byte, error) {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	testing.AssertRoundTrip(c, testRoundTripConfig, marshal, json.Unmarshal)
+}
+
+// lossyConfig loses its secret in a round trip through JSON.
+type lossyConfig struct {
+	Name   string `json:"name"`
+	Secret string `json:"-"`
+}
+
+// gobLossyConfig loses its note in a round trip through gob.
+type gobLossyConfig struct {
+	Name string
+	note string
+}
+
+// badCodecValue cannot be decoded from JSON.
+type badCodecValue struct{}
+
+func (*badCodecValue) UnmarshalJSON([]byte) error {
+	return fmt.Errorf("no decoding")
+}
+
+// failingRoundTripSuite is run by TestRoundTripFails rather than being
+// registered with gocheck.
+type failingRoundTripSuite struct{}
+
+func (*failingRoundTripSuite) TestChanged(c *gc.C) {
+	ok := testing.CheckJSONRoundTrip(c, lossyConfig{Name: "foo", Secret: "x"})
+	c.Check(ok, jc.IsFalse)
+}
+
+func (*failingRoundTripSuite) TestBinary(c *gc.C) {
+	testing.CheckGobRoundTrip(c, gobLossyConfig{Name: "foo", note: "x"})
+}
+
+func (*failingRoundTripSuite) TestMarshalError(c *gc.C) {
+	testing.CheckJSONRoundTrip(c, make(chan int))
+}
+
+func (*failingRoundTripSuite) TestUnmarshalError(c *gc.C) {
+	testing.CheckJSONRoundTrip(c, badCodecValue{})
+}
+
+func (*failingRoundTripSuite) TestAssert(c *gc.C) {
+	testing.AssertRoundTrip(c, lossyConfig{Secret: "x"}, json.Marshal, json.Unmarshal)
+	c.Fatalf("AssertRoundTrip did not stop the test")
+}
+
+var roundTripFailureTests = []struct {
+	about  string
+	test   string
+	expect string
+}{{
+	about: "value changed",
+	test:  "TestChanged",
+	expect: `(?s).*value changed by round trip; encoded as:\n.*\t\{"name":"foo"\}\n` +
+		`.*mismatch at \.Secret: unequal; obtained ""; expected "x"\n.*`,
+}, {
+	about:  "binary encoding shown as hex",
+	test:   "TestBinary",
+	expect: `(?s).*value changed by round trip; encoded as:\n.*\t00000000  [0-9a-f ]+ .*`,
+}, {
+	about:  "marshal error",
+	test:   "TestMarshalError",
+	expect: `(?s).*cannot marshal chan int: json: unsupported type: chan int\n.*`,
+}, {
+	about:  "unmarshal error",
+	test:   "TestUnmarshalError",
+	expect: `(?s).*cannot unmarshal testing_test.badCodecValue: no decoding\n.*encoded as:\n.*\t\{\}\n.*`,
+}, {
+	about:  "assert stops the test",
+	test:   "TestAssert",
+	expect: `(?s).*mismatch at \.Secret.*`,
+}}
+
+func (*roundTripSuite) TestRoundTripFails(c *gc.C) {
+	for i, test := range roundTripFailureTests {
+		c.Logf("test %d: %s", i, test.about)
+		var output bytes.Buffer
+		result := gc.Run(&failingRoundTripSuite{}, &gc.RunConf{
+			Output: &output,
+			Filter: "failingRoundTripSuite." + test.test + "$",
+		})
+		c.Check(result.Failed, gc.Equals, 1)
+		c.Check(output.String(), gc.Matches, test.expect)
+		c.Check(output.String(), gc.Not(gc.Matches), `(?s).*did not stop the test.*`)
+	}
+}
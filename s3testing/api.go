@@ -0,0 +1,760 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package s3testing
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+	timeFormat  = "2006-01-02T15:04:05.000Z"
+)
+
+// s3Error is an error response from the S3 API.
+type s3Error struct {
+	XMLName  xml.Name `xml:"Error"`
+	status   int
+	Code     string
+	Message  string
+	Resource string `xml:",omitempty"`
+}
+
+func errNoSuchBucket(name string) *s3Error {
+	return &s3Error{status: http.StatusNotFound, Code: "NoSuchBucket", Message: "The specified bucket does not exist", Resource: name}
+}
+
+func errNoSuchKey(key string) *s3Error {
+	return &s3Error{status: http.StatusNotFound, Code: "NoSuchKey", Message: "The specified key does not exist.", Resource: key}
+}
+
+func errNoSuchUpload(id string) *s3Error {
+	return &s3Error{status: http.StatusNotFound, Code: "NoSuchUpload", Message: "The specified upload does not exist.", Resource: id}
+}
+
+func errInvalidArgument(msg string) *s3Error {
+	return &s3Error{status: http.StatusBadRequest, Code: "InvalidArgument", Message: msg}
+}
+
+// ServeHTTP implements http.Handler by serving the S3 API.
+func (srv *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	bucketName, key := splitPath(req.URL.Path)
+	op, handler := srv.route(req, bucketName, key)
+	srv.mu.Lock()
+	srv.operations = append(srv.operations, op)
+	srv.mu.Unlock()
+	if handler == nil {
+		writeError(w, &s3Error{
+			status:  http.StatusNotImplemented,
+			Code:    "NotImplemented",
+			Message: fmt.Sprintf("%s %s is not implemented by s3testing", req.Method, req.URL.Path),
+		})
+		return
+	}
+	body, err := readBody(req)
+	if err != nil {
+		writeError(w, errInvalidArgument(err.Error()))
+		return
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if err := handler(w, req, bucketName, key, body); err != nil {
+		writeError(w, err)
+	}
+}
+
+type handlerFunc func(w http.ResponseWriter, req *http.Request, bucketName, key string, body []byte) *s3Error
+
+// route returns the name of the operation requested and the handler
+// for it, which is nil if the operation is not supported.
+func (srv *Server) route(req *http.Request, bucketName, key string) (string, handlerFunc) {
+	q := req.URL.Query()
+	has := func(name string) bool {
+		_, ok := q[name]
+		return ok
+	}
+	switch {
+	case bucketName == "" && req.Method == "GET":
+		return "ListBuckets", srv.listBuckets
+	case bucketName == "":
+		return req.Method, nil
+	case key == "":
+		switch req.Method {
+		case "PUT":
+			return "CreateBucket", srv.createBucketOp
+		case "DELETE":
+			return "DeleteBucket", srv.deleteBucket
+		case "HEAD":
+			return "HeadBucket", srv.headBucket
+		case "GET":
+			switch {
+			case has("location"):
+				return "GetBucketLocation", srv.getBucketLocation
+			case q.Get("list-type") == "2":
+				return "ListObjectsV2", srv.listObjects
+			case len(q) == 0 || has("prefix") || has("delimiter") || has("marker") || has("max-keys") || has("encoding-type"):
+				return "ListObjects", srv.listObjects
+			}
+		case "POST":
+			if has("delete") {
+				return "DeleteObjects", srv.deleteObjects
+			}
+		}
+	default:
+		switch req.Method {
+		case "PUT":
+			switch {
+			case has("uploadId") && has("partNumber"):
+				return "UploadPart", srv.uploadPart
+			case req.Header.Get("X-Amz-Copy-Source") != "":
+				return "CopyObject", srv.copyObject
+			case len(q) == 0 || has("x-id"):
+				return "PutObject", srv.putObjectOp
+			}
+		case "GET", "HEAD":
+			if len(q) == 0 || has("x-id") {
+				if req.Method == "HEAD" {
+					return "HeadObject", srv.getObject
+				}
+				return "GetObject", srv.getObject
+			}
+		case "DELETE":
+			if has("uploadId") {
+				return "AbortMultipartUpload", srv.abortMultipartUpload
+			}
+			return "DeleteObject", srv.deleteObject
+		case "POST":
+			switch {
+			case has("uploads"):
+				return "CreateMultipartUpload", srv.createMultipartUpload
+			case has("uploadId"):
+				return "CompleteMultipartUpload", srv.completeMultipartUpload
+			}
+		}
+	}
+	return req.Method + " " + req.URL.RawQuery, nil
+}
+
+// splitPath splits a path-style request path into bucket name and
+// key.
+func splitPath(path string) (bucketName, key string) {
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return path, ""
+}
+
+// readBody returns the request body, decoding it if the client has
+// sent it in the aws-chunked encoding used for streaming signatures.
+func readBody(req *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(req.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") ||
+		strings.Contains(req.Header.Get("Content-Encoding"), "aws-chunked") {
+		return decodeAWSChunked(body)
+	}
+	return body, nil
+}
+
+// decodeAWSChunked decodes a body in the aws-chunked encoding, in
+// which each chunk is preceded by its size in hex, optionally followed
+// by a signature, and trailers may follow the last, empty, chunk.
+func decodeAWSChunked(data []byte) ([]byte, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	var out []byte
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("malformed aws-chunked body")
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if i := strings.IndexByte(line, ';'); i >= 0 {
+			line = line[:i]
+		}
+		size, err := strconv.ParseInt(line, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed aws-chunked chunk size %q", line)
+		}
+		if size == 0 {
+			// Any trailers hold checksums, which are not
+			// verified.
+			return out, nil
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, fmt.Errorf("malformed aws-chunked body")
+		}
+		out = append(out, chunk...)
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, fmt.Errorf("malformed aws-chunked body")
+		}
+	}
+}
+
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	io.WriteString(w, xml.Header)
+	w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, e *s3Error) {
+	writeXML(w, e.status, e)
+}
+
+func (srv *Server) bucket(name string) (*bucket, *s3Error) {
+	b := srv.buckets[name]
+	if b == nil {
+		return nil, errNoSuchBucket(name)
+	}
+	return b, nil
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Owner   struct {
+		ID          string
+		DisplayName string
+	}
+	Buckets []bucketInfo `xml:"Buckets>Bucket"`
+}
+
+type bucketInfo struct {
+	Name         string
+	CreationDate string
+}
+
+func (srv *Server) listBuckets(w http.ResponseWriter, req *http.Request, _, _ string, _ []byte) *s3Error {
+	result := listAllMyBucketsResult{Xmlns: s3Namespace}
+	result.Owner.ID = "s3testing"
+	result.Owner.DisplayName = "s3testing"
+	names := make([]string, 0, len(srv.buckets))
+	for name := range srv.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result.Buckets = append(result.Buckets, bucketInfo{
+			Name:         name,
+			CreationDate: srv.buckets[name].created.Format(timeFormat),
+		})
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+func (srv *Server) createBucketOp(w http.ResponseWriter, req *http.Request, bucketName, _ string, _ []byte) *s3Error {
+	if srv.buckets[bucketName] != nil {
+		return &s3Error{
+			status:   http.StatusConflict,
+			Code:     "BucketAlreadyOwnedByYou",
+			Message:  "Your previous request to create the named bucket succeeded and you already own it.",
+			Resource: bucketName,
+		}
+	}
+	srv.createBucket(bucketName)
+	w.Header().Set("Location", "/"+bucketName)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (srv *Server) deleteBucket(w http.ResponseWriter, req *http.Request, bucketName, _ string, _ []byte) *s3Error {
+	b, err := srv.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	if len(b.objects) > 0 {
+		return &s3Error{
+			status:   http.StatusConflict,
+			Code:     "BucketNotEmpty",
+			Message:  "The bucket you tried to delete is not empty",
+			Resource: bucketName,
+		}
+	}
+	delete(srv.buckets, bucketName)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (srv *Server) headBucket(w http.ResponseWriter, req *http.Request, bucketName, _ string, _ []byte) *s3Error {
+	if _, err := srv.bucket(bucketName); err != nil {
+		// HEAD responses have no body.
+		w.WriteHeader(err.status)
+		return nil
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (srv *Server) getBucketLocation(w http.ResponseWriter, req *http.Request, bucketName, _ string, _ []byte) *s3Error {
+	if _, err := srv.bucket(bucketName); err != nil {
+		return err
+	}
+	writeXML(w, http.StatusOK, struct {
+		XMLName xml.Name `xml:"LocationConstraint"`
+		Xmlns   string   `xml:"xmlns,attr"`
+	}{Xmlns: s3Namespace})
+	return nil
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Xmlns                 string   `xml:"xmlns,attr"`
+	Name                  string
+	Prefix                string
+	Delimiter             string `xml:",omitempty"`
+	MaxKeys               int
+	IsTruncated           bool
+	Marker                *string        `xml:",omitempty"`
+	NextMarker            string         `xml:",omitempty"`
+	KeyCount              *int           `xml:",omitempty"`
+	ContinuationToken     string         `xml:",omitempty"`
+	NextContinuationToken string         `xml:",omitempty"`
+	StartAfter            string         `xml:",omitempty"`
+	Contents              []objectInfo   `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type objectInfo struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int
+	StorageClass string
+}
+
+type commonPrefix struct {
+	Prefix string
+}
+
+// listObjects serves both versions of ListObjects. Keys and common
+// prefixes are merged into a single sorted list of entries, which is
+// paged through using the last entry returned as the continuation
+// token or marker.
+func (srv *Server) listObjects(w http.ResponseWriter, req *http.Request, bucketName, _ string, _ []byte) *s3Error {
+	b, err := srv.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	q := req.URL.Query()
+	v2 := q.Get("list-type") == "2"
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	maxKeys := 1000
+	if s := q.Get("max-keys"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return errInvalidArgument("invalid max-keys " + s)
+		}
+		if n < maxKeys {
+			maxKeys = n
+		}
+	}
+	result := listBucketResult{
+		Xmlns:     s3Namespace,
+		Name:      bucketName,
+		Prefix:    prefix,
+		Delimiter: delimiter,
+		MaxKeys:   maxKeys,
+	}
+	after := q.Get("marker")
+	if v2 {
+		after = q.Get("start-after")
+		result.StartAfter = after
+		if token := q.Get("continuation-token"); token != "" {
+			result.ContinuationToken = token
+			after = token
+		}
+	} else {
+		result.Marker = &after
+	}
+
+	// entries maps each entry to whether it is a common prefix.
+	entries := make(map[string]bool)
+	for key := range b.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				entries[key[:len(prefix)+i+len(delimiter)]] = true
+				continue
+			}
+		}
+		entries[key] = false
+	}
+	var names []string
+	for name := range entries {
+		if name > after {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) > maxKeys {
+		names = names[:maxKeys]
+		result.IsTruncated = true
+		if v2 {
+			result.NextContinuationToken = names[len(names)-1]
+		} else if delimiter != "" {
+			result.NextMarker = names[len(names)-1]
+		}
+	}
+	for _, name := range names {
+		if entries[name] {
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: name})
+			continue
+		}
+		obj := b.objects[name]
+		result.Contents = append(result.Contents, objectInfo{
+			Key:          obj.Key,
+			LastModified: obj.LastModified.Format(timeFormat),
+			ETag:         obj.ETag,
+			Size:         len(obj.Data),
+			StorageClass: "STANDARD",
+		})
+	}
+	if v2 {
+		n := len(names)
+		result.KeyCount = &n
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+func (srv *Server) putObjectOp(w http.ResponseWriter, req *http.Request, bucketName, key string, body []byte) *s3Error {
+	if _, err := srv.bucket(bucketName); err != nil {
+		return err
+	}
+	obj := &Object{
+		Key:         key,
+		Data:        body,
+		ContentType: req.Header.Get("Content-Type"),
+		Metadata:    metadataFromHeader(req.Header),
+	}
+	srv.putObject(bucketName, obj)
+	w.Header().Set("ETag", obj.ETag)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (srv *Server) copyObject(w http.ResponseWriter, req *http.Request, bucketName, key string, _ []byte) *s3Error {
+	if _, err := srv.bucket(bucketName); err != nil {
+		return err
+	}
+	source, err := url.PathUnescape(req.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		return errInvalidArgument("invalid copy source")
+	}
+	srcBucketName, srcKey := splitPath(source)
+	srcBucket, s3err := srv.bucket(srcBucketName)
+	if s3err != nil {
+		return s3err
+	}
+	src := srcBucket.objects[srcKey]
+	if src == nil {
+		return errNoSuchKey(srcKey)
+	}
+	obj := copyObject(src)
+	obj.Key = key
+	if req.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+		obj.ContentType = req.Header.Get("Content-Type")
+		obj.Metadata = metadataFromHeader(req.Header)
+	}
+	srv.putObject(bucketName, &obj)
+	writeXML(w, http.StatusOK, struct {
+		XMLName      xml.Name `xml:"CopyObjectResult"`
+		ETag         string
+		LastModified string
+	}{
+		ETag:         obj.ETag,
+		LastModified: obj.LastModified.Format(timeFormat),
+	})
+	return nil
+}
+
+// getObject serves GetObject and HeadObject, supporting single byte
+// ranges.
+func (srv *Server) getObject(w http.ResponseWriter, req *http.Request, bucketName, key string, _ []byte) *s3Error {
+	b, err := srv.bucket(bucketName)
+	if err == nil && b.objects[key] == nil {
+		err = errNoSuchKey(key)
+	}
+	if err != nil {
+		if req.Method == "HEAD" {
+			w.WriteHeader(err.status)
+			return nil
+		}
+		return err
+	}
+	obj := b.objects[key]
+	h := w.Header()
+	h.Set("Content-Type", obj.ContentType)
+	h.Set("ETag", obj.ETag)
+	h.Set("Last-Modified", obj.LastModified.Format(http.TimeFormat))
+	h.Set("Accept-Ranges", "bytes")
+	for k, v := range obj.Metadata {
+		h.Set("X-Amz-Meta-"+k, v)
+	}
+	data, status := obj.Data, http.StatusOK
+	if r := req.Header.Get("Range"); r != "" {
+		start, end, ok := parseRange(r, len(obj.Data))
+		if !ok {
+			h.Set("Content-Range", fmt.Sprintf("bytes */%d", len(obj.Data)))
+			return &s3Error{
+				status:  http.StatusRequestedRangeNotSatisfiable,
+				Code:    "InvalidRange",
+				Message: "The requested range is not satisfiable",
+			}
+		}
+		h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(obj.Data)))
+		data, status = obj.Data[start:end], http.StatusPartialContent
+	}
+	h.Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	if req.Method != "HEAD" {
+		w.Write(data)
+	}
+	return nil
+}
+
+// parseRange parses a Range header holding a single byte range, and
+// returns the start and end of the range within an object of the
+// given size.
+func parseRange(r string, size int) (start, end int, ok bool) {
+	spec := strings.TrimPrefix(r, "bytes=")
+	if spec == r || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	i := strings.IndexByte(spec, '-')
+	if i < 0 {
+		return 0, 0, false
+	}
+	first, last := spec[:i], spec[i+1:]
+	if first == "" {
+		// A suffix range.
+		n, err := strconv.Atoi(last)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, false
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, size, true
+	}
+	start, err := strconv.Atoi(first)
+	if err != nil || start >= size {
+		return 0, 0, false
+	}
+	end = size
+	if last != "" {
+		n, err := strconv.Atoi(last)
+		if err != nil || n < start {
+			return 0, 0, false
+		}
+		if n+1 < size {
+			end = n + 1
+		}
+	}
+	return start, end, true
+}
+
+func (srv *Server) deleteObject(w http.ResponseWriter, req *http.Request, bucketName, key string, _ []byte) *s3Error {
+	b, err := srv.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	// Deleting an object that does not exist succeeds.
+	delete(b.objects, key)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type deleteRequest struct {
+	Quiet   bool
+	Objects []struct {
+		Key string
+	} `xml:"Object"`
+}
+
+type deleteResult struct {
+	XMLName xml.Name `xml:"DeleteResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Deleted []struct {
+		Key string
+	} `xml:"Deleted"`
+}
+
+func (srv *Server) deleteObjects(w http.ResponseWriter, req *http.Request, bucketName, _ string, body []byte) *s3Error {
+	b, err := srv.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	var dreq deleteRequest
+	if err := xml.Unmarshal(body, &dreq); err != nil {
+		return &s3Error{status: http.StatusBadRequest, Code: "MalformedXML", Message: err.Error()}
+	}
+	result := deleteResult{Xmlns: s3Namespace}
+	for _, obj := range dreq.Objects {
+		delete(b.objects, obj.Key)
+		if !dreq.Quiet {
+			result.Deleted = append(result.Deleted, struct{ Key string }{obj.Key})
+		}
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+func (srv *Server) createMultipartUpload(w http.ResponseWriter, req *http.Request, bucketName, key string, _ []byte) *s3Error {
+	if _, err := srv.bucket(bucketName); err != nil {
+		return err
+	}
+	srv.nextUpload++
+	id := fmt.Sprintf("upload-%d", srv.nextUpload)
+	srv.uploads[id] = &upload{
+		bucket:      bucketName,
+		key:         key,
+		contentType: req.Header.Get("Content-Type"),
+		metadata:    metadataFromHeader(req.Header),
+		parts:       make(map[int][]byte),
+	}
+	writeXML(w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Xmlns    string   `xml:"xmlns,attr"`
+		Bucket   string
+		Key      string
+		UploadId string
+	}{
+		Xmlns:    s3Namespace,
+		Bucket:   bucketName,
+		Key:      key,
+		UploadId: id,
+	})
+	return nil
+}
+
+// upload returns the multipart upload with the ID given in the
+// request.
+func (srv *Server) upload(req *http.Request, bucketName, key string) (string, *upload, *s3Error) {
+	id := req.URL.Query().Get("uploadId")
+	u := srv.uploads[id]
+	if u == nil || u.bucket != bucketName || u.key != key {
+		return "", nil, errNoSuchUpload(id)
+	}
+	return id, u, nil
+}
+
+func (srv *Server) uploadPart(w http.ResponseWriter, req *http.Request, bucketName, key string, body []byte) *s3Error {
+	_, u, err := srv.upload(req, bucketName, key)
+	if err != nil {
+		return err
+	}
+	n, perr := strconv.Atoi(req.URL.Query().Get("partNumber"))
+	if perr != nil || n < 1 || n > 10000 {
+		return errInvalidArgument("Part number must be an integer between 1 and 10000, inclusive")
+	}
+	u.parts[n] = body
+	w.Header().Set("ETag", md5ETag(body))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+type completeMultipartUpload struct {
+	Parts []struct {
+		PartNumber int
+		ETag       string
+	} `xml:"Part"`
+}
+
+func (srv *Server) completeMultipartUpload(w http.ResponseWriter, req *http.Request, bucketName, key string, body []byte) *s3Error {
+	id, u, err := srv.upload(req, bucketName, key)
+	if err != nil {
+		return err
+	}
+	var complete completeMultipartUpload
+	if err := xml.Unmarshal(body, &complete); err != nil || len(complete.Parts) == 0 {
+		return &s3Error{status: http.StatusBadRequest, Code: "MalformedXML", Message: "The XML you provided was not well-formed or did not validate against our published schema."}
+	}
+	var parts [][]byte
+	var data []byte
+	for i, p := range complete.Parts {
+		if i > 0 && p.PartNumber <= complete.Parts[i-1].PartNumber {
+			return &s3Error{status: http.StatusBadRequest, Code: "InvalidPartOrder", Message: "The list of parts was not in ascending order."}
+		}
+		part, ok := u.parts[p.PartNumber]
+		if !ok || strings.Trim(p.ETag, `"`) != strings.Trim(md5ETag(part), `"`) {
+			return &s3Error{status: http.StatusBadRequest, Code: "InvalidPart", Message: fmt.Sprintf("Part %d could not be found or its ETag did not match.", p.PartNumber)}
+		}
+		parts = append(parts, part)
+		data = append(data, part...)
+	}
+	obj := &Object{
+		Key:         key,
+		Data:        data,
+		ContentType: u.contentType,
+		Metadata:    u.metadata,
+		ETag:        multipartETag(parts),
+	}
+	srv.putObject(bucketName, obj)
+	delete(srv.uploads, id)
+	writeXML(w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+		Xmlns    string   `xml:"xmlns,attr"`
+		Location string
+		Bucket   string
+		Key      string
+		ETag     string
+	}{
+		Xmlns:    s3Namespace,
+		Location: srv.URL + "/" + bucketName + "/" + key,
+		Bucket:   bucketName,
+		Key:      key,
+		ETag:     obj.ETag,
+	})
+	return nil
+}
+
+func (srv *Server) abortMultipartUpload(w http.ResponseWriter, req *http.Request, bucketName, key string, _ []byte) *s3Error {
+	id, _, err := srv.upload(req, bucketName, key)
+	if err != nil {
+		return err
+	}
+	delete(srv.uploads, id)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// metadataFromHeader returns the user metadata held in X-Amz-Meta-*
+// headers, keyed by lower case name.
+func metadataFromHeader(h http.Header) map[string]string {
+	var metadata map[string]string
+	for name, values := range h {
+		lower := strings.ToLower(name)
+		if !strings.HasPrefix(lower, "x-amz-meta-") {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[strings.TrimPrefix(lower, "x-amz-meta-")] = strings.Join(values, ",")
+	}
+	return metadata
+}
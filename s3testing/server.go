@@ -0,0 +1,280 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package s3testing provides an in-memory object store that speaks
+// enough of the Amazon S3 API for S3 client code to be tested without
+// modification.
+package s3testing
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// Object holds an object stored by a Server.
+type Object struct {
+	Key          string
+	Data         []byte
+	ContentType  string
+	Metadata     map[string]string
+	ETag         string
+	LastModified time.Time
+}
+
+// Server is an in-memory S3-compatible object store, serving the
+// bucket, object, listing and multipart upload operations over
+// loopback HTTP:
+//
+//	srv := s3testing.NewServer()
+//	defer srv.Close()
+//	srv.CreateBucket("uploads")
+//	... point the client under test at srv.URL, using path-style
+//	    addressing and any credentials ...
+//	srv.AssertObject(c, "uploads", "report.csv", "a,b\n1,2\n")
+//	c.Assert(srv.Count("PutObject"), gc.Equals, 1)
+//
+// Requests are not authenticated, so any credentials are accepted.
+// Bucket names must appear in the request path, so clients must be
+// configured to use path-style rather than virtual-hosted addressing.
+type Server struct {
+	*httptest.Server
+
+	// Clock is used for the modification times of objects. If it is
+	// nil, clock.WallClock is used.
+	Clock clock.Clock
+
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	uploads    map[string]*upload
+	nextUpload int
+	operations []string
+}
+
+type bucket struct {
+	created time.Time
+	objects map[string]*Object
+}
+
+// upload holds a multipart upload in progress.
+type upload struct {
+	bucket, key string
+	contentType string
+	metadata    map[string]string
+	parts       map[int][]byte
+}
+
+// NewServer starts and returns a new Server with no buckets. The
+// caller should call Close when finished with it.
+func NewServer() *Server {
+	srv := &Server{
+		buckets: make(map[string]*bucket),
+		uploads: make(map[string]*upload),
+	}
+	srv.Server = httptest.NewServer(srv)
+	return srv
+}
+
+func (srv *Server) now() time.Time {
+	if srv.Clock == nil {
+		return clock.WallClock.Now().UTC()
+	}
+	return srv.Clock.Now().UTC()
+}
+
+// CreateBucket creates a bucket, if it does not already exist.
+func (srv *Server) CreateBucket(name string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.createBucket(name)
+}
+
+func (srv *Server) createBucket(name string) {
+	if srv.buckets[name] == nil {
+		srv.buckets[name] = &bucket{
+			created: srv.now(),
+			objects: make(map[string]*Object),
+		}
+	}
+}
+
+// PutObject stores an object, creating its bucket if necessary, so
+// that tests can seed the store.
+func (srv *Server) PutObject(bucket, key string, data []byte) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.createBucket(bucket)
+	srv.putObject(bucket, &Object{Key: key, Data: data})
+}
+
+// putObject stores obj in the existing bucket, setting its ETag and
+// modification time.
+func (srv *Server) putObject(bucketName string, obj *Object) {
+	if obj.ETag == "" {
+		obj.ETag = md5ETag(obj.Data)
+	}
+	if obj.ContentType == "" {
+		obj.ContentType = "binary/octet-stream"
+	}
+	obj.LastModified = srv.now()
+	srv.buckets[bucketName].objects[obj.Key] = obj
+}
+
+// Buckets returns the names of the buckets, in order.
+func (srv *Server) Buckets() []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	names := make([]string, 0, len(srv.buckets))
+	for name := range srv.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Object returns the object with the given key, and whether it
+// exists.
+func (srv *Server) Object(bucket, key string) (Object, bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	b := srv.buckets[bucket]
+	if b == nil || b.objects[key] == nil {
+		return Object{}, false
+	}
+	return copyObject(b.objects[key]), true
+}
+
+// Objects returns the objects in the bucket, in key order.
+func (srv *Server) Objects(bucket string) []Object {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	b := srv.buckets[bucket]
+	if b == nil {
+		return nil
+	}
+	objects := make([]Object, 0, len(b.objects))
+	for _, key := range b.keys() {
+		objects = append(objects, copyObject(b.objects[key]))
+	}
+	return objects
+}
+
+// Keys returns the keys of the objects in the bucket, in order.
+func (srv *Server) Keys(bucket string) []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if b := srv.buckets[bucket]; b != nil {
+		return b.keys()
+	}
+	return nil
+}
+
+func (b *bucket) keys() []string {
+	keys := make([]string, 0, len(b.objects))
+	for key := range b.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copyObject(obj *Object) Object {
+	c := *obj
+	c.Data = append([]byte(nil), obj.Data...)
+	if obj.Metadata != nil {
+		c.Metadata = make(map[string]string)
+		for k, v := range obj.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	return c
+}
+
+// Uploads returns the keys of the multipart uploads to the bucket that
+// have been started but neither completed nor aborted, in order. It
+// can be used to check that client code cleans up failed uploads.
+func (srv *Server) Uploads(bucket string) []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	var keys []string
+	for _, u := range srv.uploads {
+		if u.bucket == bucket {
+			keys = append(keys, u.key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AssertObject checks that the object with the given key exists and
+// holds the given data.
+func (srv *Server) AssertObject(c *gc.C, bucket, key, data string) {
+	obj, ok := srv.Object(bucket, key)
+	if !ok {
+		c.Fatalf("object %s/%s does not exist; bucket holds %q", bucket, key, srv.Keys(bucket))
+	}
+	c.Assert(string(obj.Data), gc.Equals, data, gc.Commentf("object %s/%s", bucket, key))
+}
+
+// AssertNoObject checks that no object with the given key exists.
+func (srv *Server) AssertNoObject(c *gc.C, bucket, key string) {
+	if _, ok := srv.Object(bucket, key); ok {
+		c.Fatalf("object %s/%s exists", bucket, key)
+	}
+}
+
+// Operations returns the names of the S3 operations requested so far,
+// such as "PutObject" and "ListObjectsV2", in order. Requests that
+// failed are included.
+func (srv *Server) Operations() []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]string(nil), srv.operations...)
+}
+
+// Count returns the number of times the named operation has been
+// requested.
+func (srv *Server) Count(operation string) int {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	n := 0
+	for _, op := range srv.operations {
+		if op == operation {
+			n++
+		}
+	}
+	return n
+}
+
+// ResetOperations discards the operations recorded so far.
+func (srv *Server) ResetOperations() {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.operations = nil
+}
+
+// md5ETag returns the ETag that S3 gives an object uploaded in a
+// single part: its quoted MD5.
+func md5ETag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// multipartETag returns the ETag that S3 gives an object assembled
+// from the given parts: the MD5 of their MD5s, followed by the number
+// of parts.
+func multipartETag(parts [][]byte) string {
+	h := md5.New()
+	for _, p := range parts {
+		sum := md5.Sum(p)
+		h.Write(sum[:])
+	}
+	return fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(h.Sum(nil)), len(parts))
+}
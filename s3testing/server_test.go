@@ -0,0 +1,397 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package s3testing_test
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/s3testing"
+	"github.com/juju/testing/testclock"
+)
+
+type serverSuite struct {
+	srv *s3testing.Server
+}
+
+var _ = gc.Suite(&serverSuite{})
+
+func (s *serverSuite) SetUpTest(c *gc.C) {
+	s.srv = s3testing.NewServer()
+}
+
+func (s *serverSuite) TearDownTest(c *gc.C) {
+	s.srv.Close()
+}
+
+// do makes a request to the server and returns the response, whose
+// body has been read into the returned string.
+func (s *serverSuite) do(c *gc.C, method, path, body string, header http.Header) (*http.Response, string) {
+	req, err := http.NewRequest(method, s.srv.URL+path, strings.NewReader(body))
+	c.Assert(err, gc.IsNil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, gc.IsNil)
+	return resp, string(data)
+}
+
+// assertError checks that the response is an S3 error with the given
+// status and code.
+func assertError(c *gc.C, resp *http.Response, body string, status int, code string) {
+	c.Assert(resp.StatusCode, gc.Equals, status, gc.Commentf("body %q", body))
+	var e struct {
+		Code string
+	}
+	err := xml.Unmarshal([]byte(body), &e)
+	c.Assert(err, gc.IsNil)
+	c.Assert(e.Code, gc.Equals, code)
+}
+
+func (s *serverSuite) TestBuckets(c *gc.C) {
+	resp, _ := s.do(c, "PUT", "/b1", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	resp, body := s.do(c, "PUT", "/b1", "", nil)
+	assertError(c, resp, body, http.StatusConflict, "BucketAlreadyOwnedByYou")
+	s.srv.CreateBucket("b0")
+
+	resp, body = s.do(c, "GET", "/", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	var list struct {
+		Buckets []string `xml:"Buckets>Bucket>Name"`
+	}
+	err := xml.Unmarshal([]byte(body), &list)
+	c.Assert(err, gc.IsNil)
+	c.Assert(list.Buckets, jc.DeepEquals, []string{"b0", "b1"})
+
+	resp, _ = s.do(c, "HEAD", "/b1", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	resp, _ = s.do(c, "HEAD", "/nothing", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNotFound)
+
+	s.srv.PutObject("b1", "k", []byte("v"))
+	resp, body = s.do(c, "DELETE", "/b1", "", nil)
+	assertError(c, resp, body, http.StatusConflict, "BucketNotEmpty")
+	resp, _ = s.do(c, "DELETE", "/b0", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNoContent)
+	c.Assert(s.srv.Buckets(), jc.DeepEquals, []string{"b1"})
+}
+
+func (s *serverSuite) TestPutGetDeleteObject(c *gc.C) {
+	clock := testclock.NewClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	s.srv.Clock = clock
+	s.srv.CreateBucket("b")
+	resp, _ := s.do(c, "PUT", "/b/dir/report.csv", "a,b\n", http.Header{
+		"Content-Type":    {"text/csv"},
+		"X-Amz-Meta-Team": {"ops"},
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	etag := resp.Header.Get("ETag")
+	c.Assert(etag, gc.Equals, `"f69f5b72bc79a92dc70c63c9aa142e36"`)
+
+	s.srv.AssertObject(c, "b", "dir/report.csv", "a,b\n")
+	obj, ok := s.srv.Object("b", "dir/report.csv")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(obj, jc.DeepEquals, s3testing.Object{
+		Key:          "dir/report.csv",
+		Data:         []byte("a,b\n"),
+		ContentType:  "text/csv",
+		Metadata:     map[string]string{"team": "ops"},
+		ETag:         etag,
+		LastModified: clock.Now(),
+	})
+
+	resp, body := s.do(c, "GET", "/b/dir/report.csv", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(body, gc.Equals, "a,b\n")
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "text/csv")
+	c.Assert(resp.Header.Get("ETag"), gc.Equals, etag)
+	c.Assert(resp.Header.Get("X-Amz-Meta-Team"), gc.Equals, "ops")
+	c.Assert(resp.Header.Get("Last-Modified"), gc.Equals, "Sun, 01 Mar 2026 12:00:00 GMT")
+
+	resp, body = s.do(c, "HEAD", "/b/dir/report.csv", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.ContentLength, gc.Equals, int64(4))
+	c.Assert(body, gc.Equals, "")
+
+	resp, _ = s.do(c, "DELETE", "/b/dir/report.csv", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNoContent)
+	s.srv.AssertNoObject(c, "b", "dir/report.csv")
+
+	resp, body = s.do(c, "GET", "/b/dir/report.csv", "", nil)
+	assertError(c, resp, body, http.StatusNotFound, "NoSuchKey")
+	resp, _ = s.do(c, "HEAD", "/b/dir/report.csv", "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNotFound)
+	resp, body = s.do(c, "PUT", "/nothing/k", "v", nil)
+	assertError(c, resp, body, http.StatusNotFound, "NoSuchBucket")
+
+	c.Assert(s.srv.Operations(), jc.DeepEquals, []string{
+		"PutObject", "GetObject", "HeadObject", "DeleteObject", "GetObject", "HeadObject", "PutObject",
+	})
+	c.Assert(s.srv.Count("GetObject"), gc.Equals, 2)
+	s.srv.ResetOperations()
+	c.Assert(s.srv.Operations(), gc.HasLen, 0)
+}
+
+var rangeTests = []struct {
+	about        string
+	rangeHeader  string
+	expectBody   string
+	expectRange  string
+	expectStatus int
+}{{
+	about:        "closed range",
+	rangeHeader:  "bytes=2-4",
+	expectBody:   "234",
+	expectRange:  "bytes 2-4/10",
+	expectStatus: http.StatusPartialContent,
+}, {
+	about:        "open range",
+	rangeHeader:  "bytes=7-",
+	expectBody:   "789",
+	expectRange:  "bytes 7-9/10",
+	expectStatus: http.StatusPartialContent,
+}, {
+	about:        "suffix range",
+	rangeHeader:  "bytes=-2",
+	expectBody:   "89",
+	expectRange:  "bytes 8-9/10",
+	expectStatus: http.StatusPartialContent,
+}, {
+	about:        "range past end",
+	rangeHeader:  "bytes=5-100",
+	expectBody:   "56789",
+	expectRange:  "bytes 5-9/10",
+	expectStatus: http.StatusPartialContent,
+}, {
+	about:        "unsatisfiable range",
+	rangeHeader:  "bytes=10-",
+	expectRange:  "bytes */10",
+	expectStatus: http.StatusRequestedRangeNotSatisfiable,
+}}
+
+func (s *serverSuite) TestGetObjectRange(c *gc.C) {
+	s.srv.PutObject("b", "k", []byte("0123456789"))
+	for i, test := range rangeTests {
+		c.Logf("test %d: %s", i, test.about)
+		resp, body := s.do(c, "GET", "/b/k", "", http.Header{"Range": {test.rangeHeader}})
+		c.Check(resp.StatusCode, gc.Equals, test.expectStatus)
+		c.Check(resp.Header.Get("Content-Range"), gc.Equals, test.expectRange)
+		if test.expectStatus == http.StatusPartialContent {
+			c.Check(body, gc.Equals, test.expectBody)
+		}
+	}
+}
+
+func (s *serverSuite) TestCopyObject(c *gc.C) {
+	s.srv.PutObject("src", "a b", []byte("data"))
+	s.srv.CreateBucket("dst")
+	resp, body := s.do(c, "PUT", "/dst/copy", "", http.Header{
+		"X-Amz-Copy-Source": {"/src/a%20b"},
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK, gc.Commentf("%s", body))
+	c.Assert(body, jc.Contains, "<CopyObjectResult>")
+	s.srv.AssertObject(c, "dst", "copy", "data")
+	s.srv.AssertObject(c, "src", "a b", "data")
+
+	resp, body = s.do(c, "PUT", "/dst/copy", "", http.Header{
+		"X-Amz-Copy-Source": {"src/nothing"},
+	})
+	assertError(c, resp, body, http.StatusNotFound, "NoSuchKey")
+}
+
+type listResult struct {
+	Keys                  []string `xml:"Contents>Key"`
+	CommonPrefixes        []string `xml:"CommonPrefixes>Prefix"`
+	IsTruncated           bool
+	KeyCount              int
+	NextContinuationToken string
+	NextMarker            string
+}
+
+func (s *serverSuite) list(c *gc.C, query string) listResult {
+	resp, body := s.do(c, "GET", "/b?"+query, "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK, gc.Commentf("%s", body))
+	var result listResult
+	err := xml.Unmarshal([]byte(body), &result)
+	c.Assert(err, gc.IsNil)
+	return result
+}
+
+func (s *serverSuite) TestListObjects(c *gc.C) {
+	for _, key := range []string{"a", "dir/1", "dir/2", "dir/sub/3", "e", "f"} {
+		s.srv.PutObject("b", key, []byte(key))
+	}
+	c.Assert(s.list(c, "list-type=2"), jc.DeepEquals, listResult{
+		Keys:     []string{"a", "dir/1", "dir/2", "dir/sub/3", "e", "f"},
+		KeyCount: 6,
+	})
+	c.Assert(s.list(c, "list-type=2&delimiter=/"), jc.DeepEquals, listResult{
+		Keys:           []string{"a", "e", "f"},
+		CommonPrefixes: []string{"dir/"},
+		KeyCount:       4,
+	})
+	c.Assert(s.list(c, "list-type=2&prefix=dir/&delimiter=/"), jc.DeepEquals, listResult{
+		Keys:           []string{"dir/1", "dir/2"},
+		CommonPrefixes: []string{"dir/sub/"},
+		KeyCount:       3,
+	})
+	c.Assert(s.list(c, "list-type=2&start-after=dir/2"), jc.DeepEquals, listResult{
+		Keys:     []string{"dir/sub/3", "e", "f"},
+		KeyCount: 3,
+	})
+
+	// Paging through the listing with continuation tokens skips
+	// the keys under common prefixes already returned.
+	page := s.list(c, "list-type=2&delimiter=/&max-keys=2")
+	c.Assert(page, jc.DeepEquals, listResult{
+		Keys:                  []string{"a"},
+		CommonPrefixes:        []string{"dir/"},
+		IsTruncated:           true,
+		KeyCount:              2,
+		NextContinuationToken: "dir/",
+	})
+	page = s.list(c, "list-type=2&delimiter=/&max-keys=2&continuation-token="+page.NextContinuationToken)
+	c.Assert(page, jc.DeepEquals, listResult{
+		Keys:     []string{"e", "f"},
+		KeyCount: 2,
+	})
+
+	// Version 1 uses markers.
+	page = s.list(c, "max-keys=3&delimiter=/")
+	c.Assert(page, jc.DeepEquals, listResult{
+		Keys:           []string{"a", "e"},
+		CommonPrefixes: []string{"dir/"},
+		IsTruncated:    true,
+		NextMarker:     "e",
+	})
+	page = s.list(c, "marker=e")
+	c.Assert(page, jc.DeepEquals, listResult{
+		Keys: []string{"f"},
+	})
+	c.Assert(s.srv.Count("ListObjectsV2"), gc.Equals, 6)
+	c.Assert(s.srv.Count("ListObjects"), gc.Equals, 2)
+}
+
+func (s *serverSuite) TestDeleteObjects(c *gc.C) {
+	for _, key := range []string{"a", "b", "c"} {
+		s.srv.PutObject("b", key, []byte(key))
+	}
+	resp, body := s.do(c, "POST", "/b?delete", `<Delete><Object><Key>a</Key></Object><Object><Key>c</Key></Object></Delete>`, nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	var result struct {
+		Deleted []string `xml:"Deleted>Key"`
+	}
+	err := xml.Unmarshal([]byte(body), &result)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Deleted, jc.DeepEquals, []string{"a", "c"})
+	c.Assert(s.srv.Keys("b"), jc.DeepEquals, []string{"b"})
+}
+
+func (s *serverSuite) TestMultipartUpload(c *gc.C) {
+	s.srv.CreateBucket("b")
+	resp, body := s.do(c, "POST", "/b/big?uploads", "", http.Header{"Content-Type": {"text/plain"}})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	var initiate struct {
+		UploadId string
+	}
+	err := xml.Unmarshal([]byte(body), &initiate)
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.srv.Uploads("b"), jc.DeepEquals, []string{"big"})
+
+	var etags []string
+	for i, part := range []string{"hello ", "world"} {
+		resp, _ := s.do(c, "PUT", "/b/big?partNumber="+strconv.Itoa(i+1)+"&uploadId="+initiate.UploadId, part, nil)
+		c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+		etags = append(etags, resp.Header.Get("ETag"))
+	}
+	s.srv.AssertNoObject(c, "b", "big")
+
+	// Parts out of order are rejected.
+	complete := func(parts ...string) string {
+		return "<CompleteMultipartUpload>" + strings.Join(parts, "") + "</CompleteMultipartUpload>"
+	}
+	part := func(n, etag string) string {
+		return "<Part><PartNumber>" + n + "</PartNumber><ETag>" + etag + "</ETag></Part>"
+	}
+	resp, body = s.do(c, "POST", "/b/big?uploadId="+initiate.UploadId, complete(part("2", etags[1]), part("1", etags[0])), nil)
+	assertError(c, resp, body, http.StatusBadRequest, "InvalidPartOrder")
+	resp, body = s.do(c, "POST", "/b/big?uploadId="+initiate.UploadId, complete(part("1", etags[1])), nil)
+	assertError(c, resp, body, http.StatusBadRequest, "InvalidPart")
+
+	resp, body = s.do(c, "POST", "/b/big?uploadId="+initiate.UploadId, complete(part("1", etags[0]), part("2", etags[1])), nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK, gc.Commentf("%s", body))
+	s.srv.AssertObject(c, "b", "big", "hello world")
+	obj, _ := s.srv.Object("b", "big")
+	c.Assert(obj.ContentType, gc.Equals, "text/plain")
+	c.Assert(obj.ETag, gc.Matches, `"[0-9a-f]{32}-2"`)
+	var result struct {
+		ETag string
+	}
+	err = xml.Unmarshal([]byte(body), &result)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.ETag, gc.Equals, obj.ETag)
+	c.Assert(s.srv.Uploads("b"), gc.HasLen, 0)
+
+	resp, body = s.do(c, "PUT", "/b/big?partNumber=1&uploadId="+initiate.UploadId, "x", nil)
+	assertError(c, resp, body, http.StatusNotFound, "NoSuchUpload")
+}
+
+func (s *serverSuite) TestAbortMultipartUpload(c *gc.C) {
+	s.srv.CreateBucket("b")
+	_, body := s.do(c, "POST", "/b/big?uploads", "", nil)
+	var initiate struct {
+		UploadId string
+	}
+	err := xml.Unmarshal([]byte(body), &initiate)
+	c.Assert(err, gc.IsNil)
+	resp, _ := s.do(c, "DELETE", "/b/big?uploadId="+initiate.UploadId, "", nil)
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNoContent)
+	c.Assert(s.srv.Uploads("b"), gc.HasLen, 0)
+	c.Assert(s.srv.Operations(), jc.DeepEquals, []string{"CreateMultipartUpload", "AbortMultipartUpload"})
+}
+
+func (s *serverSuite) TestAWSChunkedBody(c *gc.C) {
+	s.srv.CreateBucket("b")
+	body := "5;chunk-signature=abc\r\nhello\r\n6;chunk-signature=def\r\n world\r\n0;chunk-signature=ghi\r\n\r\n"
+	resp, _ := s.do(c, "PUT", "/b/k", body, http.Header{
+		"X-Amz-Content-Sha256":         {"STREAMING-AWS4-HMAC-SHA256-PAYLOAD"},
+		"X-Amz-Decoded-Content-Length": {"11"},
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	s.srv.AssertObject(c, "b", "k", "hello world")
+
+	// Unsigned chunks may be followed by checksum trailers.
+	body = "b\r\nhello again\r\n0\r\nx-amz-checksum-crc32:AAAAAA==\r\n\r\n"
+	resp, _ = s.do(c, "PUT", "/b/k", body, http.Header{
+		"Content-Encoding":     {"aws-chunked"},
+		"X-Amz-Content-Sha256": {"STREAMING-UNSIGNED-PAYLOAD-TRAILER"},
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	s.srv.AssertObject(c, "b", "k", "hello again")
+}
+
+func (s *serverSuite) TestNotImplemented(c *gc.C) {
+	s.srv.CreateBucket("b")
+	resp, body := s.do(c, "GET", "/b?versioning", "", nil)
+	assertError(c, resp, body, http.StatusNotImplemented, "NotImplemented")
+	c.Assert(s.srv.Operations(), jc.DeepEquals, []string{"GET versioning"})
+}
+
+func (s *serverSuite) TestAssertObjectFailures(c *gc.C) {
+	s.srv.PutObject("b", "k", []byte("v"))
+	c.ExpectFailure("object does not exist")
+	s.srv.AssertObject(c, "b", "other", "v")
+}
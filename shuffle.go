@@ -0,0 +1,170 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	stdtesting "testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+var shuffleFlag = flag.String("shuffle.order", "off", `Run tests and table test cases in random order: "off", "on" to choose a seed, or a seed to replay an earlier order`)
+
+// shuffleSeed returns the seed with which to shuffle tests, and
+// whether they should be shuffled at all, as given by the
+// -shuffle.order flag.
+func shuffleSeed() (seed int64, shuffle bool, err error) {
+	switch *shuffleFlag {
+	case "", "off":
+		return 0, false, nil
+	case "on":
+		// The seed is the base of NewSeededRand, so that
+		// TEST_RAND_SEED also replays the order of tests.
+		seed, err = baseRandSeed()
+		return seed, err == nil, err
+	}
+	seed, err = strconv.ParseInt(*shuffleFlag, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf(`invalid -shuffle.order %q: must be "off", "on" or a seed`, *shuffleFlag)
+	}
+	return seed, true, nil
+}
+
+// RunSuites runs the tests in the given gocheck suites, as gc.TestingT
+// runs the suites registered with gc.Suite, and takes the same -check
+// flags. It is called instead of gc.TestingT from a package's test
+// function, and the suites must not also be registered with gc.Suite:
+//
+//	func TestPackage(t *stdtesting.T) {
+//		testing.RunSuites(t, &fooSuite{}, &barSuite{})
+//	}
+//
+// When the tests are run with -shuffle.order=on, the tests of all the
+// suites are run in a random order, which shows up tests that depend on
+// others having run first. The seed of the order is printed, and the
+// same order can be run again with -shuffle.order=<seed>. Each test is
+// run with its suite's SetUpSuite and TearDownSuite around it, but the
+// suite value itself is shared, as it is when the tests run in order.
+// The tests of suites embedding IsolationSuite also log the name of the
+// test that ran before them.
+//
+// Table test cases run with RunTable are shuffled too.
+func RunSuites(t *stdtesting.T, suites ...interface{}) {
+	seed, shuffle, err := shuffleSeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := &gc.RunConf{
+		Filter:  lookupFlag("check.f"),
+		Verbose: lookupFlag("check.v") == "true",
+		Stream:  lookupFlag("check.vv") == "true",
+	}
+	if shuffle {
+		fmt.Printf("shuffling tests with seed %d (run with -shuffle.order=%d to replay)\n", seed, seed)
+	}
+	result, err := runSuites(conf, seed, shuffle, suites)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintln(os.Stderr, result.String())
+	if !result.Passed() {
+		t.Fail()
+	}
+}
+
+// lookupFlag returns the value of the named flag, or "" if there is no
+// such flag.
+func lookupFlag(name string) string {
+	if f := flag.Lookup(name); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// runSuites runs the tests in the given suites with the given
+// configuration, in an order shuffled with the given seed if shuffle is
+// true.
+func runSuites(conf *gc.RunConf, seed int64, shuffle bool, suites []interface{}) (*gc.Result, error) {
+	result := &gc.Result{}
+	if !shuffle {
+		for _, suite := range suites {
+			result.Add(gc.Run(suite, conf))
+		}
+		return result, nil
+	}
+	var filter *regexp.Regexp
+	if conf.Filter != "" {
+		var err error
+		filter, err = regexp.Compile(conf.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -check.f flag: %v", err)
+		}
+	}
+	type suiteTest struct {
+		suite interface{}
+		name  string
+	}
+	var tests []suiteTest
+	for _, suite := range suites {
+		suiteName, methods := suiteTests(suite)
+		for _, method := range methods {
+			name := suiteName + "." + method
+			if filter == nil || filter.MatchString(suiteName) || filter.MatchString(method) || filter.MatchString(name) {
+				tests = append(tests, suiteTest{suite, name})
+			}
+		}
+	}
+	rand.New(rand.NewSource(seed)).Shuffle(len(tests), func(i, j int) {
+		tests[i], tests[j] = tests[j], tests[i]
+	})
+	for _, test := range tests {
+		testConf := *conf
+		testConf.Filter = "^" + regexp.QuoteMeta(test.name) + "$"
+		result.Add(gc.Run(test.suite, &testConf))
+	}
+	return result, nil
+}
+
+// suiteTests returns the name of a suite, as gocheck names it, and the
+// names of its test methods.
+func suiteTests(suite interface{}) (string, []string) {
+	t := reflect.TypeOf(suite)
+	suiteName := t.Name()
+	if t.Kind() == reflect.Ptr {
+		suiteName = t.Elem().Name()
+	}
+	var methods []string
+	for i := 0; i < t.NumMethod(); i++ {
+		if name := t.Method(i).Name; strings.HasPrefix(name, "Test") {
+			methods = append(methods, name)
+		}
+	}
+	return suiteName, methods
+}
+
+// shuffleCases shuffles table test cases if tests are being shuffled,
+// returning the shuffled indexes of the cases.
+func shuffleCases(c *gc.C, n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	seed, shuffle, err := shuffleSeed()
+	c.Assert(err, gc.IsNil)
+	if shuffle {
+		c.Logf("shuffling cases with seed %d (run with -shuffle.order=%d to replay)", seed, seed)
+		rand.New(rand.NewSource(seed)).Shuffle(n, func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+	return order
+}
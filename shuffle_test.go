@@ -0,0 +1,170 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+type shuffleSuite struct {
+	CleanupSuite
+}
+
+var _ = gc.Suite(&shuffleSuite{})
+
+func (s *shuffleSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.PatchEnvironment("TEST_TAGS", "")
+	s.PatchValue(&lastIsolatedTest, "")
+}
+
+// setShuffleFlag sets the -shuffle.order flag until the end of the
+// test.
+func (s *shuffleSuite) setShuffleFlag(c *gc.C, value string) {
+	err := flag.Set("shuffle.order", value)
+	c.Assert(err, gc.IsNil)
+	s.AddCleanup(func(*gc.C) { flag.Set("shuffle.order", "off") })
+}
+
+var shuffleSeedTests = []struct {
+	value   string
+	seed    int64
+	shuffle bool
+	err     string
+}{
+	{value: "off"},
+	{value: ""},
+	{value: "on", seed: randBaseSeed, shuffle: true},
+	{value: "1234", seed: 1234, shuffle: true},
+	{value: "-5", seed: -5, shuffle: true},
+	{value: "sometimes", err: `invalid -shuffle.order "sometimes": must be "off", "on" or a seed`},
+}
+
+func (s *shuffleSuite) TestShuffleSeed(c *gc.C) {
+	for i, test := range shuffleSeedTests {
+		c.Logf("test %d: %q", i, test.value)
+		s.setShuffleFlag(c, test.value)
+		seed, shuffle, err := shuffleSeed()
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, gc.IsNil)
+		c.Check(seed, gc.Equals, test.seed)
+		c.Check(shuffle, gc.Equals, test.shuffle)
+	}
+}
+
+// ranTests records the tests run by orderSuiteA and orderSuiteB.
+var ranTests []string
+
+type orderSuiteA struct{}
+
+func (*orderSuiteA) TestOne(c *gc.C)   { ranTests = append(ranTests, "A.One") }
+func (*orderSuiteA) TestTwo(c *gc.C)   { ranTests = append(ranTests, "A.Two") }
+func (*orderSuiteA) TestThree(c *gc.C) { ranTests = append(ranTests, "A.Three") }
+
+type orderSuiteB struct {
+	setUp int
+}
+
+func (s *orderSuiteB) SetUpSuite(c *gc.C) { s.setUp++ }
+func (*orderSuiteB) TestOne(c *gc.C)      { ranTests = append(ranTests, "B.One") }
+func (*orderSuiteB) TestTwo(c *gc.C)      { ranTests = append(ranTests, "B.Two") }
+func (*orderSuiteB) TestThree(c *gc.C)    { ranTests = append(ranTests, "B.Three") }
+
+func (s *shuffleSuite) runOrderSuites(c *gc.C, conf *gc.RunConf, seed int64, shuffle bool) []string {
+	s.PatchValue(&ranTests, nil)
+	conf.Output = &bytes.Buffer{}
+	suiteB := &orderSuiteB{}
+	result, err := runSuites(conf, seed, shuffle, []interface{}{&orderSuiteA{}, suiteB})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Succeeded, gc.Equals, len(ranTests))
+	if shuffle {
+		// Each test is run with its suite's fixtures.
+		ranB := 0
+		for _, name := range ranTests {
+			if strings.HasPrefix(name, "B.") {
+				ranB++
+			}
+		}
+		c.Assert(suiteB.setUp, gc.Equals, ranB)
+	}
+	return ranTests
+}
+
+func (s *shuffleSuite) TestRunSuitesInOrder(c *gc.C) {
+	ran := s.runOrderSuites(c, &gc.RunConf{}, 0, false)
+	c.Assert(ran, jc.DeepEquals, []string{"A.One", "A.Three", "A.Two", "B.One", "B.Three", "B.Two"})
+}
+
+func (s *shuffleSuite) TestRunSuitesShuffled(c *gc.C) {
+	ran := s.runOrderSuites(c, &gc.RunConf{}, 1, true)
+	c.Assert(ran, jc.SameContents, []string{"A.One", "A.Three", "A.Two", "B.One", "B.Three", "B.Two"})
+	c.Assert(sort.StringsAreSorted(ran), jc.IsFalse)
+
+	// The same seed gives the same order.
+	c.Assert(s.runOrderSuites(c, &gc.RunConf{}, 1, true), jc.DeepEquals, ran)
+
+	// Another seed gives a different order.
+	other := s.runOrderSuites(c, &gc.RunConf{}, 2, true)
+	c.Assert(other, jc.SameContents, ran)
+	c.Assert(other, gc.Not(jc.DeepEquals), ran)
+}
+
+func (s *shuffleSuite) TestRunSuitesShuffledFilter(c *gc.C) {
+	ran := s.runOrderSuites(c, &gc.RunConf{Filter: "orderSuiteA|TestTwo"}, 1, true)
+	c.Assert(ran, jc.SameContents, []string{"A.One", "A.Three", "A.Two", "B.Two"})
+
+	_, err := runSuites(&gc.RunConf{Filter: "("}, 1, true, []interface{}{&orderSuiteA{}})
+	c.Assert(err, gc.ErrorMatches, `invalid -check.f flag: .*`)
+}
+
+func (s *shuffleSuite) TestRunTableShuffled(c *gc.C) {
+	s.setShuffleFlag(c, "1")
+	var ran []int
+	RunTable(c, []int{0, 1, 2, 3, 4, 5}, func(c *gc.C, test int) {
+		ran = append(ran, test)
+	})
+	c.Assert(ran, jc.SameContents, []int{0, 1, 2, 3, 4, 5})
+	c.Assert(sort.IntsAreSorted(ran), jc.IsFalse)
+	expectLog := "shuffling cases with seed 1 (run with -shuffle.order=1 to replay)\n"
+	for _, i := range ran {
+		// Cases are logged with their original indexes.
+		expectLog += fmt.Sprintf("test %d: %d\n", i, i)
+	}
+	c.Assert(c.GetTestLog(), gc.Equals, expectLog)
+}
+
+// isolatedOrderSuite is run by TestIsolationSuiteShuffled rather than
+// being registered with gocheck.
+type isolatedOrderSuite struct {
+	IsolationSuite
+}
+
+func (s *isolatedOrderSuite) TestFirst(c *gc.C) {}
+
+func (s *isolatedOrderSuite) TestLeak(c *gc.C) {
+	i := 1
+	PatchValue(&i, 2)
+}
+
+func (s *shuffleSuite) TestIsolationSuiteShuffled(c *gc.C) {
+	s.setShuffleFlag(c, "3")
+	var output bytes.Buffer
+	conf := &gc.RunConf{Output: &output, Verbose: true}
+	result, err := runSuites(conf, 3, true, []interface{}{&isolatedOrderSuite{}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Passed(), jc.IsFalse)
+	c.Assert(output.String(), gc.Matches, `(?s).*tests are shuffled; previous test: isolatedOrderSuite.Test(First|Leak)\n.*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*test leaked patch: .*\n+tests are shuffled; run with -shuffle.order=3 to run them in the same order\n.*`)
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// SignalInterceptor stands in for the os/signal package so that tests
+// can deliver signals to the code under test without signalling the
+// test process. Its Notify, Stop and NotifyContext methods have the
+// same signatures and semantics as the functions of the same names in
+// os/signal. Code under test should call those functions through
+// package variables, which tests patch to use an interceptor:
+//
+//	var signalNotify = signal.Notify
+//
+//	...
+//
+//	signals := testing.NewSignalInterceptor()
+//	s.PatchValue(&signalNotify, signals.Notify)
+//	go runServer()
+//	signals.WaitForHandler(c, syscall.SIGTERM)
+//	signals.Deliver(syscall.SIGTERM)
+type SignalInterceptor struct {
+	mu       sync.Mutex
+	handlers map[chan<- os.Signal][]os.Signal
+	changed  chan struct{}
+}
+
+// NewSignalInterceptor returns a SignalInterceptor with no handlers.
+func NewSignalInterceptor() *SignalInterceptor {
+	return &SignalInterceptor{
+		handlers: make(map[chan<- os.Signal][]os.Signal),
+		changed:  make(chan struct{}),
+	}
+}
+
+// Notify registers ch to receive the given signals, or all signals if
+// none are given, as signal.Notify does.
+func (s *SignalInterceptor) Notify(ch chan<- os.Signal, sig ...os.Signal) {
+	if ch == nil {
+		panic("testing: Notify using nil channel")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(sig) == 0 {
+		s.handlers[ch] = nil
+	} else if sigs, ok := s.handlers[ch]; !ok || sigs != nil {
+		s.handlers[ch] = append(sigs, sig...)
+	}
+	s.notifyChanged()
+}
+
+// Stop stops delivery of signals to ch, as signal.Stop does.
+func (s *SignalInterceptor) Stop(ch chan<- os.Signal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.handlers, ch)
+	s.notifyChanged()
+}
+
+// NotifyContext returns a copy of ctx that is cancelled when one of
+// the given signals is delivered, as signal.NotifyContext does.
+func (s *SignalInterceptor) NotifyContext(ctx context.Context, sig ...os.Signal) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan os.Signal, 1)
+	s.Notify(ch, sig...)
+	go func() {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+	return ctx, func() {
+		s.Stop(ch)
+		cancel()
+	}
+}
+
+// Deliver sends sig to every channel registered for it and returns
+// the number of channels it was sent to. As with real signals, the
+// send does not block, so the signal is dropped for any channel
+// whose buffer is full.
+func (s *SignalInterceptor) Deliver(sig os.Signal) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for ch, sigs := range s.handlers {
+		if !wantsSignal(sigs, sig) {
+			continue
+		}
+		select {
+		case ch <- sig:
+			n++
+		default:
+		}
+	}
+	return n
+}
+
+// Handled reports whether any channel is registered to receive sig.
+func (s *SignalInterceptor) Handled(sig os.Signal) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sigs := range s.handlers {
+		if wantsSignal(sigs, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitForHandler waits until a channel is registered to receive sig,
+// failing the test if that does not happen within LongWait. This
+// allows a test to avoid delivering a signal before the code under
+// test is ready for it.
+func (s *SignalInterceptor) WaitForHandler(c *gc.C, sig os.Signal) {
+	timeout := time.After(LongWait)
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		s.mu.Unlock()
+		if s.Handled(sig) {
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			c.Fatalf("timed out waiting for a handler for signal %v", sig)
+		}
+	}
+}
+
+// notifyChanged wakes any goroutines in WaitForHandler.
+// It must be called with s.mu held.
+func (s *SignalInterceptor) notifyChanged() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// wantsSignal reports whether a channel registered for sigs should
+// receive sig. A nil sigs means all signals.
+func wantsSignal(sigs []os.Signal, sig os.Signal) bool {
+	if sigs == nil {
+		return true
+	}
+	for _, s := range sigs {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type signalSuite struct {
+	testing.IsolationSuite
+	signals *testing.SignalInterceptor
+}
+
+var _ = gc.Suite(&signalSuite{})
+
+func (s *signalSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.signals = testing.NewSignalInterceptor()
+}
+
+// signalNotify stands in for the package variable that code under
+// test would use to call signal.Notify.
+var signalNotify = signal.Notify
+
+func (s *signalSuite) TestDeliver(c *gc.C) {
+	s.PatchValue(&signalNotify, s.signals.Notify)
+	ch := make(chan os.Signal, 1)
+	signalNotify(ch, syscall.SIGTERM, syscall.SIGINT)
+
+	c.Assert(s.signals.Deliver(syscall.SIGHUP), gc.Equals, 0)
+	c.Assert(s.signals.Deliver(syscall.SIGTERM), gc.Equals, 1)
+	c.Assert(<-ch, gc.Equals, syscall.SIGTERM)
+
+	s.signals.Stop(ch)
+	c.Assert(s.signals.Deliver(syscall.SIGINT), gc.Equals, 0)
+}
+
+func (s *signalSuite) TestDeliverDoesNotBlock(c *gc.C) {
+	ch := make(chan os.Signal, 1)
+	s.signals.Notify(ch, syscall.SIGHUP)
+	c.Assert(s.signals.Deliver(syscall.SIGHUP), gc.Equals, 1)
+	c.Assert(s.signals.Deliver(syscall.SIGHUP), gc.Equals, 0)
+	c.Assert(<-ch, gc.Equals, syscall.SIGHUP)
+}
+
+func (s *signalSuite) TestNotifyAllSignals(c *gc.C) {
+	all := make(chan os.Signal, 1)
+	some := make(chan os.Signal, 1)
+	s.signals.Notify(all)
+	s.signals.Notify(some, syscall.SIGINT)
+	s.signals.Notify(all, syscall.SIGTERM)
+	c.Assert(s.signals.Deliver(syscall.SIGHUP), gc.Equals, 1)
+	c.Assert(<-all, gc.Equals, syscall.SIGHUP)
+	c.Assert(s.signals.Deliver(syscall.SIGINT), gc.Equals, 2)
+}
+
+func (s *signalSuite) TestWaitForHandler(c *gc.C) {
+	done := make(chan os.Signal)
+	go func() {
+		ch := make(chan os.Signal, 1)
+		s.signals.Notify(ch, syscall.SIGTERM)
+		done <- <-ch
+	}()
+	s.signals.WaitForHandler(c, syscall.SIGTERM)
+	c.Assert(s.signals.Handled(syscall.SIGTERM), jc.IsTrue)
+	c.Assert(s.signals.Handled(syscall.SIGINT), jc.IsFalse)
+	s.signals.Deliver(syscall.SIGTERM)
+	c.Assert(<-done, gc.Equals, syscall.SIGTERM)
+}
+
+func (s *signalSuite) TestNotifyContext(c *gc.C) {
+	ctx, stop := s.signals.NotifyContext(context.Background(), syscall.SIGINT)
+	defer stop()
+	c.Assert(ctx.Err(), gc.IsNil)
+	s.signals.Deliver(syscall.SIGINT)
+	select {
+	case <-ctx.Done():
+	case <-time.After(testing.LongWait):
+		c.Fatalf("context not cancelled")
+	}
+	stop()
+	c.Assert(s.signals.Handled(syscall.SIGINT), jc.IsFalse)
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"os"
+
+	gc "gopkg.in/check.v1"
+)
+
+// SkipUnlessRoot skips the test unless it is running as root, as is
+// needed, for example, to change the ownership of files.
+func SkipUnlessRoot(c *gc.C) {
+	if os.Geteuid() != 0 {
+		c.Skip("test must be run as root")
+	}
+}
+
+// SkipIfRoot skips the test if it is running as root, for which
+// permission checks always succeed.
+func SkipIfRoot(c *gc.C) {
+	if os.Geteuid() == 0 {
+		c.Skip("test cannot be run as root")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"os"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+type skipSuite struct{}
+
+var _ = gc.Suite(&skipSuite{})
+
+var skipped = make(map[string]bool)
+
+func (*skipSuite) TestSkipUnlessRoot(c *gc.C) {
+	skipped["unless"] = true
+	testing.SkipUnlessRoot(c)
+	skipped["unless"] = false
+}
+
+func (*skipSuite) TestSkipIfRoot(c *gc.C) {
+	skipped["if"] = true
+	testing.SkipIfRoot(c)
+	skipped["if"] = false
+}
+
+func (*skipSuite) TestZZZCheckSkipped(c *gc.C) {
+	root := os.Geteuid() == 0
+	c.Assert(skipped["unless"], gc.Equals, !root)
+	c.Assert(skipped["if"], gc.Equals, root)
+}
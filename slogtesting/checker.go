@@ -0,0 +1,195 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package slogtesting
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// RecordMatch describes a record expected by the HasRecord and
+// RecordsMatch checkers.
+type RecordMatch struct {
+	// Level holds the expected level of the record.
+	// If it is nil, any level matches.
+	Level slog.Leveler
+
+	// Message holds a regular expression that must match
+	// the record's message. An empty expression matches
+	// any message.
+	Message string
+
+	// Attrs holds attributes that the record must have,
+	// keyed as in Record.Attrs. Values are compared after
+	// being resolved as slog would, so an int value matches
+	// an attribute added with slog.Int. The record may have
+	// other attributes too.
+	Attrs map[string]interface{}
+
+	// Where holds a predicate that the record must also satisfy.
+	// The zero Predicate is satisfied by any record.
+	Where Predicate
+}
+
+// String returns a description of the match.
+func (m RecordMatch) String() string {
+	level := "ANY"
+	if m.Level != nil {
+		level = m.Level.Level().String()
+	}
+	s := fmt.Sprintf("%s %q", level, m.Message)
+	if len(m.Attrs) > 0 {
+		s += " " + formatAttrs(m.Attrs)
+	}
+	if m.Where.match != nil {
+		s += " where " + m.Where.String()
+	}
+	return s
+}
+
+// String returns a description of the record.
+func (r Record) String() string {
+	s := fmt.Sprintf("%s %s", r.Level, r.Message)
+	if len(r.Attrs) > 0 {
+		s += " " + formatAttrs(r.Attrs)
+	}
+	return s
+}
+
+// matches reports whether the record satisfies m.
+func (m RecordMatch) matches(r Record) (bool, error) {
+	if m.Level != nil && r.Level != m.Level.Level() {
+		return false, nil
+	}
+	if ok, err := regexp.MatchString(m.Message, r.Message); err != nil {
+		return false, fmt.Errorf("bad message regexp %q: %v", m.Message, err)
+	} else if !ok {
+		return false, nil
+	}
+	for key, want := range m.Attrs {
+		got, ok := r.Attrs[key]
+		if !ok {
+			return false, nil
+		}
+		if ok, _ := jc.DeepEqual(got, slog.AnyValue(want).Resolve().Any()); !ok {
+			return false, nil
+		}
+	}
+	return m.Where.Match(r)
+}
+
+type hasRecordChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasRecord checks that at least one of the obtained []Record
+// satisfies the expected RecordMatch.
+var HasRecord gc.Checker = &hasRecordChecker{
+	&gc.CheckerInfo{Name: "HasRecord", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *hasRecordChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	records, ok := params[0].([]Record)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []slogtesting.Record, got %T", params[0])
+	}
+	expect, ok := params[1].(RecordMatch)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be of type slogtesting.RecordMatch, got %T", params[1])
+	}
+	for _, r := range records {
+		ok, err := expect.matches(r)
+		if err != nil {
+			return false, err.Error()
+		}
+		if ok {
+			return true, ""
+		}
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "no record matches %s; records:\n", expect)
+	for _, r := range records {
+		fmt.Fprintf(&buf, "    %s\n", r)
+	}
+	return false, buf.String()
+}
+
+type recordsMatchChecker struct {
+	*gc.CheckerInfo
+}
+
+// RecordsMatch checks that the obtained []Record satisfies each of
+// the expected []RecordMatch in the order given. As with
+// checkers.LogMatches, the records may contain additional entries
+// before and after each of the expected ones.
+//
+// On failure, the obtained records are listed with those that did
+// not satisfy an expectation prefixed with "+", followed by the
+// expectations that were not met, prefixed with "-".
+var RecordsMatch gc.Checker = &recordsMatchChecker{
+	&gc.CheckerInfo{Name: "RecordsMatch", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *recordsMatchChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	records, ok := params[0].([]Record)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []slogtesting.Record, got %T", params[0])
+	}
+	expected, ok := params[1].([]RecordMatch)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be of type []slogtesting.RecordMatch, got %T", params[1])
+	}
+	matched := make([]bool, len(records))
+	remaining := expected
+	for i, r := range records {
+		if len(remaining) == 0 {
+			break
+		}
+		ok, err := remaining[0].matches(r)
+		if err != nil {
+			return false, err.Error()
+		}
+		if ok {
+			matched[i] = true
+			remaining = remaining[1:]
+		}
+	}
+	if len(remaining) == 0 {
+		return true, ""
+	}
+	var buf strings.Builder
+	buf.WriteString("unmatched record expectations:\n")
+	for i, r := range records {
+		prefix := "+"
+		if matched[i] {
+			prefix = " "
+		}
+		fmt.Fprintf(&buf, "  %s %s\n", prefix, r)
+	}
+	for _, m := range remaining {
+		fmt.Fprintf(&buf, "  - %s\n", m)
+	}
+	return false, buf.String()
+}
+
+// formatAttrs formats attributes as key=value pairs sorted by key.
+func formatAttrs(attrs map[string]interface{}) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, attrs[k])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package slogtesting_test
+
+import (
+	"log/slog"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/slogtesting"
+)
+
+type checkerSuite struct{}
+
+var _ = gc.Suite(&checkerSuite{})
+
+var records = []slogtesting.Record{{
+	Level:   slog.LevelInfo,
+	Message: "starting worker",
+	Attrs:   map[string]interface{}{"name": "uniter"},
+}, {
+	Level:   slog.LevelDebug,
+	Message: "connecting",
+	Attrs:   map[string]interface{}{"attempt": int64(1)},
+}, {
+	Level:   slog.LevelError,
+	Message: "connection refused",
+	Attrs:   map[string]interface{}{"attempt": int64(1), "addr": "10.0.0.1"},
+}}
+
+func (*checkerSuite) TestHasRecord(c *gc.C) {
+	c.Check(records, slogtesting.HasRecord, slogtesting.RecordMatch{Message: "refused"})
+	c.Check(records, slogtesting.HasRecord, slogtesting.RecordMatch{Level: slog.LevelDebug})
+	c.Check(records, slogtesting.HasRecord, slogtesting.RecordMatch{
+		Level:   slog.LevelError,
+		Message: "connection .*",
+		Attrs:   map[string]interface{}{"attempt": 1},
+	})
+	c.Check(records, gc.Not(slogtesting.HasRecord), slogtesting.RecordMatch{Level: slog.LevelWarn})
+	c.Check(records, gc.Not(slogtesting.HasRecord), slogtesting.RecordMatch{
+		Attrs: map[string]interface{}{"attempt": 2},
+	})
+}
+
+func (*checkerSuite) TestHasRecordFailure(c *gc.C) {
+	result, msg := slogtesting.HasRecord.Check([]interface{}{records[:1], slogtesting.RecordMatch{
+		Level:   slog.LevelWarn,
+		Message: "stop",
+	}}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, `no record matches WARN "stop"; records:
+    INFO starting worker name=uniter
+`)
+}
+
+func (*checkerSuite) TestHasRecordBadParams(c *gc.C) {
+	result, msg := slogtesting.HasRecord.Check([]interface{}{"foo", slogtesting.RecordMatch{}}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, "obtained value must be of type []slogtesting.Record, got string")
+
+	result, msg = slogtesting.HasRecord.Check([]interface{}{records, slogtesting.RecordMatch{Message: "[]"}}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Matches, `bad message regexp "\[\]": .*`)
+}
+
+func (*checkerSuite) TestRecordsMatch(c *gc.C) {
+	c.Check(records, slogtesting.RecordsMatch, []slogtesting.RecordMatch{
+		{Message: "starting"},
+		{Level: slog.LevelError, Attrs: map[string]interface{}{"addr": "10.0.0.1"}},
+	})
+	c.Check(records, slogtesting.RecordsMatch, []slogtesting.RecordMatch{})
+	c.Check(records, gc.Not(slogtesting.RecordsMatch), []slogtesting.RecordMatch{
+		{Message: "refused"},
+		{Message: "starting"},
+	})
+}
+
+func (*checkerSuite) TestRecordsMatchFailure(c *gc.C) {
+	result, msg := slogtesting.RecordsMatch.Check([]interface{}{records, []slogtesting.RecordMatch{
+		{Message: "starting"},
+		{Level: slog.LevelWarn, Message: "connection", Attrs: map[string]interface{}{"attempt": 1}},
+	}}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, `unmatched record expectations:
+    INFO starting worker name=uniter
+  + DEBUG connecting attempt=1
+  + ERROR connection refused addr=10.0.0.1 attempt=1
+  - WARN "connection" attempt=1
+`)
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package slogtesting provides an slog.Handler that records log
+// records in memory, and checkers for making assertions about them.
+package slogtesting
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Record holds a log record captured by Handler.
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+
+	// Attrs holds the attributes of the record, including those
+	// added with Logger.With. Attributes within groups are keyed
+	// by their dot-separated path, for example "request.id".
+	// Values are resolved, so an attribute added with slog.Int
+	// has an int64 value.
+	Attrs map[string]interface{}
+}
+
+// Handler is an slog.Handler that records every enabled record in
+// memory. Handlers derived from it with WithAttrs and WithGroup
+// record into the same store.
+type Handler struct {
+	store  *recordStore
+	level  slog.Leveler
+	prefix string
+	attrs  map[string]interface{}
+}
+
+type recordStore struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewHandler returns a Handler that records log records at or above
+// the given level. If level is nil, all records are captured.
+func NewHandler(level slog.Leveler) *Handler {
+	if level == nil {
+		level = slog.Level(-1 << 31)
+	}
+	return &Handler{
+		store: &recordStore{},
+		level: level,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]interface{}, len(h.attrs)+r.NumAttrs())
+	for k, v := range h.attrs {
+		attrs[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(attrs, h.prefix, a)
+		return true
+	})
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	h.store.records = append(h.store.records, Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   attrs,
+	})
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(as []slog.Attr) slog.Handler {
+	h1 := *h
+	h1.attrs = make(map[string]interface{}, len(h.attrs)+len(as))
+	for k, v := range h.attrs {
+		h1.attrs[k] = v
+	}
+	for _, a := range as {
+		addAttr(h1.attrs, h.prefix, a)
+	}
+	return &h1
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h1 := *h
+	h1.prefix = h.prefix + name + "."
+	return &h1
+}
+
+// Records returns a copy of all the records captured so far.
+func (h *Handler) Records() []Record {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	return append([]Record(nil), h.store.records...)
+}
+
+// Clear discards all the records captured so far.
+func (h *Handler) Clear() {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	h.store.records = nil
+}
+
+// addAttr adds the attribute to attrs, flattening groups into
+// dot-separated keys.
+func addAttr(attrs map[string]interface{}, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addAttr(attrs, groupPrefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	attrs[prefix+a.Key] = v.Any()
+}
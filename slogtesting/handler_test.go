@@ -0,0 +1,84 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package slogtesting_test
+
+import (
+	"log/slog"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/slogtesting"
+)
+
+type handlerSuite struct{}
+
+var _ = gc.Suite(&handlerSuite{})
+
+func (*handlerSuite) TestRecords(c *gc.C) {
+	h := slogtesting.NewHandler(nil)
+	logger := slog.New(h)
+	logger.Debug("one", "n", 1)
+	logger.Warn("two", slog.String("s", "x"))
+
+	records := h.Records()
+	c.Assert(records, gc.HasLen, 2)
+	c.Assert(records[0].Level, gc.Equals, slog.LevelDebug)
+	c.Assert(records[0].Message, gc.Equals, "one")
+	c.Assert(records[0].Attrs, jc.DeepEquals, map[string]interface{}{"n": int64(1)})
+	c.Assert(records[1].Level, gc.Equals, slog.LevelWarn)
+	c.Assert(records[1].Attrs, jc.DeepEquals, map[string]interface{}{"s": "x"})
+
+	h.Clear()
+	c.Assert(h.Records(), gc.HasLen, 0)
+}
+
+func (*handlerSuite) TestLevel(c *gc.C) {
+	h := slogtesting.NewHandler(slog.LevelInfo)
+	logger := slog.New(h)
+	logger.Debug("hidden")
+	logger.Info("shown")
+	c.Assert(h.Records(), gc.HasLen, 1)
+	c.Assert(h.Records()[0].Message, gc.Equals, "shown")
+}
+
+func (*handlerSuite) TestWithAttrsAndGroups(c *gc.C) {
+	h := slogtesting.NewHandler(nil)
+	logger := slog.New(h).With("unit", "app/0").WithGroup("req")
+	logger.Info("handled", "id", 42, slog.Group("peer", "addr", "10.0.0.1"))
+	slog.New(h).Info("plain")
+
+	records := h.Records()
+	c.Assert(records, gc.HasLen, 2)
+	c.Assert(records[0].Attrs, jc.DeepEquals, map[string]interface{}{
+		"unit":          "app/0",
+		"req.id":        int64(42),
+		"req.peer.addr": "10.0.0.1",
+	})
+	c.Assert(records[1].Attrs, gc.HasLen, 0)
+}
+
+type suiteSuite struct {
+	slogtesting.Suite
+}
+
+var _ = gc.Suite(&suiteSuite{})
+
+func (s *suiteSuite) TestDefaultLoggerCaptured(c *gc.C) {
+	slog.Info("captured", "k", "v")
+	c.Assert(s.Records(), slogtesting.HasRecord, slogtesting.RecordMatch{
+		Level:   slog.LevelInfo,
+		Message: "captured",
+		Attrs:   map[string]interface{}{"k": "v"},
+	})
+}
+
+func (s *suiteSuite) TestDefaultLoggerRestored(c *gc.C) {
+	handler := s.Handler
+	s.TearDownTest(c)
+	slog.Info("not captured")
+	s.SetUpTest(c)
+	c.Assert(handler.Records(), gc.HasLen, 0)
+	c.Assert(s.Records(), gc.HasLen, 0)
+}
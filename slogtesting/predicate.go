@@ -0,0 +1,254 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package slogtesting
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// Predicate is a condition on a log record, built from the field
+// predicates below and combined with And, Or and Not. It is checked
+// by HasRecordWhere, or as part of a RecordMatch, so that records can
+// be matched on their attributes rather than on their rendered text:
+//
+//	c.Check(s.Records(), slogtesting.HasRecordWhere, slogtesting.And(
+//		slogtesting.FieldEquals("status", 503),
+//		slogtesting.FieldGreaterThan("elapsed", time.Second),
+//		slogtesting.Not(slogtesting.HasField("retry")),
+//	))
+type Predicate struct {
+	desc  string
+	match func(r Record) (bool, error)
+}
+
+// String returns a description of the predicate.
+func (p Predicate) String() string {
+	return p.desc
+}
+
+// Match reports whether the record satisfies the predicate. It returns
+// an error if the predicate is invalid, for example because it holds a
+// bad regular expression.
+func (p Predicate) Match(r Record) (bool, error) {
+	if p.match == nil {
+		return true, nil
+	}
+	return p.match(r)
+}
+
+// HasField returns a predicate satisfied by records with an attribute
+// with the given key, keyed as in Record.Attrs.
+func HasField(key string) Predicate {
+	return Predicate{
+		desc: "has " + key,
+		match: func(r Record) (bool, error) {
+			_, ok := r.Attrs[key]
+			return ok, nil
+		},
+	}
+}
+
+// FieldEquals returns a predicate satisfied by records with an
+// attribute with the given key and value. The value is resolved as
+// slog would, so an int value matches an attribute added with slog.Int.
+func FieldEquals(key string, value interface{}) Predicate {
+	want := slog.AnyValue(value).Resolve().Any()
+	return Predicate{
+		desc: fmt.Sprintf("%s=%v", key, value),
+		match: func(r Record) (bool, error) {
+			got, ok := r.Attrs[key]
+			if !ok {
+				return false, nil
+			}
+			ok, _ = jc.DeepEqual(got, want)
+			return ok, nil
+		},
+	}
+}
+
+// FieldMatches returns a predicate satisfied by records with an
+// attribute with the given key whose value, formatted with fmt.Sprint,
+// matches the given regular expression.
+func FieldMatches(key, pattern string) Predicate {
+	re, err := regexp.Compile(pattern)
+	return Predicate{
+		desc: fmt.Sprintf("%s=~%q", key, pattern),
+		match: func(r Record) (bool, error) {
+			if err != nil {
+				return false, fmt.Errorf("bad regexp for field %s: %v", key, err)
+			}
+			got, ok := r.Attrs[key]
+			return ok && re.MatchString(fmt.Sprint(got)), nil
+		},
+	}
+}
+
+// FieldLessThan returns a predicate satisfied by records with a
+// numeric attribute with the given key that is less than n, which may
+// be of any integer or floating point type, including time.Duration.
+func FieldLessThan(key string, n interface{}) Predicate {
+	return compareField(key, "<", n, func(got, want float64) bool {
+		return got < want
+	})
+}
+
+// FieldGreaterThan returns a predicate satisfied by records with a
+// numeric attribute with the given key that is greater than n, which
+// may be of any integer or floating point type, including
+// time.Duration.
+func FieldGreaterThan(key string, n interface{}) Predicate {
+	return compareField(key, ">", n, func(got, want float64) bool {
+		return got > want
+	})
+}
+
+func compareField(key, op string, n interface{}, compare func(got, want float64) bool) Predicate {
+	want, wantOK := number(n)
+	return Predicate{
+		desc: fmt.Sprintf("%s%s%v", key, op, n),
+		match: func(r Record) (bool, error) {
+			if !wantOK {
+				return false, fmt.Errorf("cannot compare field %s with non-numeric value %#v", key, n)
+			}
+			got, ok := number(r.Attrs[key])
+			return ok && compare(got, want), nil
+		},
+	}
+}
+
+// number returns v as a float64, if it is a number.
+func number(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// LevelAtLeast returns a predicate satisfied by records at or above the
+// given level.
+func LevelAtLeast(level slog.Leveler) Predicate {
+	return Predicate{
+		desc: "level>=" + level.Level().String(),
+		match: func(r Record) (bool, error) {
+			return r.Level >= level.Level(), nil
+		},
+	}
+}
+
+// MessageMatches returns a predicate satisfied by records whose message
+// matches the given regular expression.
+func MessageMatches(pattern string) Predicate {
+	re, err := regexp.Compile(pattern)
+	return Predicate{
+		desc: fmt.Sprintf("message=~%q", pattern),
+		match: func(r Record) (bool, error) {
+			if err != nil {
+				return false, fmt.Errorf("bad message regexp %q: %v", pattern, err)
+			}
+			return re.MatchString(r.Message), nil
+		},
+	}
+}
+
+// And returns a predicate satisfied by records that satisfy all of the
+// given predicates.
+func And(ps ...Predicate) Predicate {
+	return Predicate{
+		desc: joinPredicates(ps, " and "),
+		match: func(r Record) (bool, error) {
+			for _, p := range ps {
+				if ok, err := p.Match(r); err != nil || !ok {
+					return false, err
+				}
+			}
+			return true, nil
+		},
+	}
+}
+
+// Or returns a predicate satisfied by records that satisfy any of the
+// given predicates.
+func Or(ps ...Predicate) Predicate {
+	return Predicate{
+		desc: joinPredicates(ps, " or "),
+		match: func(r Record) (bool, error) {
+			for _, p := range ps {
+				if ok, err := p.Match(r); err != nil || ok {
+					return ok, err
+				}
+			}
+			return false, nil
+		},
+	}
+}
+
+// Not returns a predicate satisfied by records that do not satisfy p.
+func Not(p Predicate) Predicate {
+	return Predicate{
+		desc: "not " + p.desc,
+		match: func(r Record) (bool, error) {
+			ok, err := p.Match(r)
+			return !ok, err
+		},
+	}
+}
+
+func joinPredicates(ps []Predicate, sep string) string {
+	descs := make([]string, len(ps))
+	for i, p := range ps {
+		descs[i] = p.desc
+	}
+	return "(" + strings.Join(descs, sep) + ")"
+}
+
+type hasRecordWhereChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasRecordWhere checks that at least one of the obtained []Record
+// satisfies the expected Predicate. On failure, the records are
+// listed. Use gc.Not(HasRecordWhere) to check that no record does.
+var HasRecordWhere gc.Checker = &hasRecordWhereChecker{
+	&gc.CheckerInfo{Name: "HasRecordWhere", Params: []string{"obtained", "predicate"}},
+}
+
+func (checker *hasRecordWhereChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	records, ok := params[0].([]Record)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type []slogtesting.Record, got %T", params[0])
+	}
+	p, ok := params[1].(Predicate)
+	if !ok {
+		return false, fmt.Sprintf("predicate must be of type slogtesting.Predicate, got %T", params[1])
+	}
+	for _, r := range records {
+		ok, err := p.Match(r)
+		if err != nil {
+			return false, err.Error()
+		}
+		if ok {
+			return true, ""
+		}
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "no record satisfies %s; records:\n", p)
+	for _, r := range records {
+		fmt.Fprintf(&buf, "    %s\n", r)
+	}
+	return false, buf.String()
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package slogtesting_test
+
+import (
+	"log/slog"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/slogtesting"
+)
+
+type predicateSuite struct{}
+
+var _ = gc.Suite(&predicateSuite{})
+
+var request = slogtesting.Record{
+	Level:   slog.LevelWarn,
+	Message: "request failed",
+	Attrs: map[string]interface{}{
+		"status":     int64(503),
+		"elapsed":    1500 * time.Millisecond,
+		"path":       "/api/users",
+		"ratio":      0.25,
+		"request.id": "abc",
+	},
+}
+
+var predicateTests = []struct {
+	predicate slogtesting.Predicate
+	desc      string
+	match     bool
+}{
+	{slogtesting.HasField("request.id"), "has request.id", true},
+	{slogtesting.HasField("user"), "has user", false},
+	{slogtesting.FieldEquals("status", 503), "status=503", true},
+	{slogtesting.FieldEquals("status", 500), "status=500", false},
+	{slogtesting.FieldEquals("missing", 0), "missing=0", false},
+	{slogtesting.FieldMatches("path", "^/api/"), `path=~"^/api/"`, true},
+	{slogtesting.FieldMatches("status", "^5"), `status=~"^5"`, true},
+	{slogtesting.FieldMatches("missing", ""), `missing=~""`, false},
+	{slogtesting.FieldGreaterThan("elapsed", time.Second), "elapsed>1s", true},
+	{slogtesting.FieldLessThan("elapsed", time.Second), "elapsed<1s", false},
+	{slogtesting.FieldGreaterThan("status", 499), "status>499", true},
+	{slogtesting.FieldLessThan("ratio", 0.5), "ratio<0.5", true},
+	{slogtesting.FieldLessThan("path", 1), "path<1", false},
+	{slogtesting.LevelAtLeast(slog.LevelWarn), "level>=WARN", true},
+	{slogtesting.LevelAtLeast(slog.LevelError), "level>=ERROR", false},
+	{slogtesting.MessageMatches("fail"), `message=~"fail"`, true},
+	{slogtesting.Not(slogtesting.HasField("user")), "not has user", true},
+	{
+		slogtesting.And(slogtesting.FieldEquals("status", 503), slogtesting.Not(slogtesting.FieldLessThan("elapsed", time.Second))),
+		"(status=503 and not elapsed<1s)",
+		true,
+	}, {
+		slogtesting.And(slogtesting.HasField("status"), slogtesting.HasField("user")),
+		"(has status and has user)",
+		false,
+	}, {
+		slogtesting.Or(slogtesting.HasField("user"), slogtesting.FieldEquals("path", "/api/users")),
+		`(has user or path=/api/users)`,
+		true,
+	},
+	{slogtesting.Or(), "()", false},
+	{slogtesting.Predicate{}, "", true},
+}
+
+func (*predicateSuite) TestPredicates(c *gc.C) {
+	for i, test := range predicateTests {
+		c.Logf("test %d: %s", i, test.desc)
+		c.Check(test.predicate.String(), gc.Equals, test.desc)
+		ok, err := test.predicate.Match(request)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(ok, gc.Equals, test.match)
+	}
+}
+
+func (*predicateSuite) TestBadPredicates(c *gc.C) {
+	_, err := slogtesting.FieldMatches("path", "[").Match(request)
+	c.Check(err, gc.ErrorMatches, "bad regexp for field path: .*")
+	_, err = slogtesting.FieldLessThan("status", "500").Match(request)
+	c.Check(err, gc.ErrorMatches, `cannot compare field status with non-numeric value "500"`)
+	_, err = slogtesting.Not(slogtesting.MessageMatches("[")).Match(request)
+	c.Check(err, gc.ErrorMatches, `bad message regexp "\[": .*`)
+}
+
+func (*predicateSuite) TestHasRecordWhere(c *gc.C) {
+	c.Check(records, slogtesting.HasRecordWhere, slogtesting.And(
+		slogtesting.FieldEquals("attempt", 1),
+		slogtesting.FieldMatches("addr", `^10\.`),
+	))
+	c.Check(records, gc.Not(slogtesting.HasRecordWhere), slogtesting.FieldGreaterThan("attempt", 1))
+
+	result, msg := slogtesting.HasRecordWhere.Check([]interface{}{records[:1], slogtesting.HasField("attempt")}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, `no record satisfies has attempt; records:
+    INFO starting worker name=uniter
+`)
+
+	result, msg = slogtesting.HasRecordWhere.Check([]interface{}{records, "foo"}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, "predicate must be of type slogtesting.Predicate, got string")
+}
+
+func (*predicateSuite) TestRecordMatchWhere(c *gc.C) {
+	c.Check(records, slogtesting.RecordsMatch, []slogtesting.RecordMatch{
+		{Message: "connecting"},
+		{Level: slog.LevelError, Where: slogtesting.FieldEquals("addr", "10.0.0.1")},
+	})
+	result, msg := slogtesting.HasRecord.Check([]interface{}{records[:1], slogtesting.RecordMatch{
+		Message: "starting",
+		Where:   slogtesting.Not(slogtesting.HasField("name")),
+	}}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, `no record matches ANY "starting" where not has name; records:
+    INFO starting worker name=uniter
+`)
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package slogtesting
+
+import (
+	"io"
+	"log"
+	"log/slog"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Suite installs a fresh Handler as the handler of the default slog
+// logger for each test, and restores the previous default logger
+// afterwards. It is intended to be embedded in a gocheck suite
+// type, which should call its SetUpTest and TearDownTest methods.
+//
+// Tests using Suite cannot run in parallel, because they share the
+// default logger.
+type Suite struct {
+	// Handler holds the handler capturing records for the
+	// current test.
+	Handler *Handler
+
+	oldDefault  *slog.Logger
+	oldLogOut   io.Writer
+	oldLogFlags int
+}
+
+func (s *Suite) SetUpTest(c *gc.C) {
+	s.Handler = NewHandler(nil)
+	// Setting the default slog logger also redirects the output
+	// of the standard library's log package, which is not undone
+	// by restoring the previous default, so save that too.
+	s.oldDefault = slog.Default()
+	s.oldLogOut, s.oldLogFlags = log.Writer(), log.Flags()
+	slog.SetDefault(slog.New(s.Handler))
+}
+
+func (s *Suite) TearDownTest(c *gc.C) {
+	if s.oldDefault != nil {
+		slog.SetDefault(s.oldDefault)
+		log.SetOutput(s.oldLogOut)
+		log.SetFlags(s.oldLogFlags)
+		s.oldDefault, s.oldLogOut = nil, nil
+	}
+}
+
+// Records returns the records logged so far in the current test.
+func (s *Suite) Records() []Record {
+	return s.Handler.Records()
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// artifactsDir holds the directory in which SlowTestProfileSuite writes
+// profiles by default, as given by $TEST_ARTIFACTS_DIR. It is read when
+// the package is initialised because OsEnvSuite clears the environment
+// before tests run.
+var artifactsDir = func() string {
+	if dir := os.Getenv("TEST_ARTIFACTS_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "test-artifacts")
+}()
+
+// SlowTestProfileSuite captures profiles of any test that takes longer
+// than Threshold, so that a slow test can be diagnosed without running
+// it again with profiling flags. A CPU profile of the whole test is
+// written to cpu.pprof, for go tool pprof, and the stacks of all
+// goroutines at the moment the test became slow are written to
+// goroutines.txt, in a directory named after the test:
+//
+//	type storeSuite struct {
+//		testing.SlowTestProfileSuite
+//	}
+//
+//	var _ = gc.Suite(&storeSuite{
+//		SlowTestProfileSuite: testing.SlowTestProfileSuite{Threshold: 10 * time.Second},
+//	})
+//
+// The directory is made in Dir, or if that is empty, in the directory
+// given by $TEST_ARTIFACTS_DIR, or test-artifacts in the system
+// temporary directory if that is not set, so that CI can collect it.
+//
+// The CPU profile is not captured if another one is already being
+// taken, such as when the tests are run with -test.cpuprofile.
+type SlowTestProfileSuite struct {
+	// Threshold holds the duration after which a test is slow. If it
+	// is zero, profiles are not captured.
+	Threshold time.Duration
+
+	// Dir holds the directory in which to write the profiles.
+	Dir string
+
+	start      time.Time
+	cpuProfile *bytes.Buffer
+	timer      *time.Timer
+
+	// goroutines holds the goroutine stacks captured when the timer
+	// fires, and may only be read once captured is closed.
+	goroutines []byte
+	captured   chan struct{}
+}
+
+func (s *SlowTestProfileSuite) SetUpSuite(c *gc.C) {}
+
+func (s *SlowTestProfileSuite) TearDownSuite(c *gc.C) {}
+
+func (s *SlowTestProfileSuite) SetUpTest(c *gc.C) {
+	s.cpuProfile = nil
+	s.goroutines = nil
+	s.timer = nil
+	if s.Threshold == 0 {
+		return
+	}
+	var cpuProfile bytes.Buffer
+	if err := pprof.StartCPUProfile(&cpuProfile); err != nil {
+		c.Logf("not capturing CPU profile of slow test: %v", err)
+	} else {
+		s.cpuProfile = &cpuProfile
+	}
+	s.start = time.Now()
+	captured := make(chan struct{})
+	s.captured = captured
+	s.timer = time.AfterFunc(s.Threshold, func() {
+		defer close(captured)
+		var goroutines bytes.Buffer
+		pprof.Lookup("goroutine").WriteTo(&goroutines, 2)
+		s.goroutines = goroutines.Bytes()
+	})
+}
+
+func (s *SlowTestProfileSuite) TearDownTest(c *gc.C) {
+	if s.timer == nil {
+		return
+	}
+	elapsed := time.Since(s.start)
+	if !s.timer.Stop() {
+		<-s.captured
+	}
+	s.timer = nil
+	if s.cpuProfile != nil {
+		pprof.StopCPUProfile()
+	}
+	if elapsed <= s.Threshold {
+		return
+	}
+	dir := s.Dir
+	if dir == "" {
+		dir = artifactsDir
+	}
+	dir = filepath.Join(dir, c.TestName())
+	err := os.MkdirAll(dir, 0755)
+	c.Assert(err, gc.IsNil)
+	if s.cpuProfile != nil {
+		err := ioutil.WriteFile(filepath.Join(dir, "cpu.pprof"), s.cpuProfile.Bytes(), 0644)
+		c.Assert(err, gc.IsNil)
+	}
+	// The timer may not have fired if the test only just became
+	// slow.
+	if s.goroutines != nil {
+		err := ioutil.WriteFile(filepath.Join(dir, "goroutines.txt"), s.goroutines, 0644)
+		c.Assert(err, gc.IsNil)
+	}
+	c.Logf("test took %v, longer than the slow test threshold of %v; profiles written to %s", elapsed, s.Threshold, dir)
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type slowProfileSuite struct{}
+
+var _ = gc.Suite(&slowProfileSuite{})
+
+// profiledSuite is run by the tests of slowProfileSuite rather than
+// being registered with gocheck.
+type profiledSuite struct {
+	testing.SlowTestProfileSuite
+}
+
+func (*profiledSuite) TestFast(c *gc.C) {}
+
+func (*profiledSuite) TestSlow(c *gc.C) {
+	time.Sleep(100 * time.Millisecond)
+}
+
+func (*slowProfileSuite) TestProfilesSlowTests(c *gc.C) {
+	dir := c.MkDir()
+	var output bytes.Buffer
+	result := gc.Run(&profiledSuite{testing.SlowTestProfileSuite{
+		Threshold: 50 * time.Millisecond,
+		Dir:       dir,
+	}}, &gc.RunConf{Output: &output, Verbose: true, Stream: true})
+	c.Assert(result.Passed(), jc.IsTrue)
+
+	slowDir := filepath.Join(dir, "profiledSuite.TestSlow")
+	c.Assert(output.String(), gc.Matches, `(?s).*test took .*, longer than the slow test threshold of 50ms; profiles written to `+slowDir+`\n.*`)
+	data, err := ioutil.ReadFile(filepath.Join(slowDir, "goroutines.txt"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Matches, `(?s)goroutine \d+ .*\(\*profiledSuite\)\.TestSlow.*`)
+	info, err := os.Stat(filepath.Join(slowDir, "cpu.pprof"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size() > 0, jc.IsTrue)
+
+	_, err = os.Stat(filepath.Join(dir, "profiledSuite.TestFast"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (*slowProfileSuite) TestNoThreshold(c *gc.C) {
+	dir := c.MkDir()
+	result := gc.Run(&profiledSuite{testing.SlowTestProfileSuite{
+		Dir: dir,
+	}}, &gc.RunConf{Output: &bytes.Buffer{}})
+	c.Assert(result.Passed(), jc.IsTrue)
+	entries, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
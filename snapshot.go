@@ -0,0 +1,116 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+var updateSnapshots = flag.Bool("snapshot.update", false, "Update snapshot files instead of checking against them")
+
+// snapshotDir holds the directory containing snapshot files. It is
+// found when the package is initialised, as tests may change the
+// working directory.
+var snapshotDir = func() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.Join("testdata", "snapshots")
+	}
+	return filepath.Join(wd, "testdata", "snapshots")
+}()
+
+// CheckSnapshot checks that value, serialised as indented JSON, is the
+// same as the snapshot stored for the current test, and reports any
+// differences line by line. It returns whether the check succeeded.
+//
+// Snapshots are stored in the testdata/snapshots directory of the
+// package being tested, in a file named after the test, such as
+// "mySuite.TestFoo.json". If a test checks more than one snapshot, it
+// should give each a different name, which is added to the file name.
+//
+// When the tests are run with the -snapshot.update flag, snapshots are
+// written instead of checked, creating them if they do not exist. The
+// updated files should be reviewed before being committed.
+//
+// Since values are serialised as JSON, unexported struct fields are not
+// included in the snapshot.
+func CheckSnapshot(c *gc.C, name string, value interface{}) bool {
+	data, err := json.MarshalIndent(value, "", "\t")
+	if !c.Check(err, gc.IsNil, gc.Commentf("cannot serialise snapshot value")) {
+		return false
+	}
+	return checkSnapshot(c, snapshotPath(c, name, ".json"), string(data)+"\n")
+}
+
+// AssertSnapshot is like CheckSnapshot, but stops the test if the
+// check fails.
+func AssertSnapshot(c *gc.C, name string, value interface{}) {
+	if !CheckSnapshot(c, name, value) {
+		c.FailNow()
+	}
+}
+
+// CheckTextSnapshot is like CheckSnapshot, but checks text, such as
+// the output of a command, which is stored as it is, in a file with a
+// ".txt" extension.
+func CheckTextSnapshot(c *gc.C, name string, text string) bool {
+	return checkSnapshot(c, snapshotPath(c, name, ".txt"), text)
+}
+
+// AssertTextSnapshot is like CheckTextSnapshot, but stops the test if
+// the check fails.
+func AssertTextSnapshot(c *gc.C, name string, text string) {
+	if !CheckTextSnapshot(c, name, text) {
+		c.FailNow()
+	}
+}
+
+// checkSnapshot checks that the snapshot file at path holds obtained,
+// or updates it when snapshots are being updated.
+func checkSnapshot(c *gc.C, path string, obtained string) bool {
+	if *updateSnapshots {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, []byte(obtained), 0644)
+		}
+		return c.Check(err, gc.IsNil, gc.Commentf("cannot update snapshot"))
+	}
+	expected, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		c.Errorf("no snapshot at %s; run the tests with -snapshot.update to create it", path)
+		return false
+	}
+	if !c.Check(err, gc.IsNil) {
+		return false
+	}
+	return c.Check(
+		strings.Split(obtained, "\n"), jc.ListEquals, strings.Split(string(expected), "\n"),
+		gc.Commentf("snapshot %s differs; run the tests with -snapshot.update to update it", path),
+	)
+}
+
+// snapshotPath returns the path of the named snapshot for the
+// current test, with the given file extension.
+func snapshotPath(c *gc.C, name, ext string) string {
+	return testFilePath(c, snapshotDir, name, ext)
+}
+
+// testFilePath returns the path of a file in dir named after the
+// current test and the given name, with the given file extension.
+func testFilePath(c *gc.C, dir, name, ext string) string {
+	file := c.TestName()
+	if name != "" {
+		file += "." + name
+	}
+	return filepath.Join(dir, file+ext)
+}
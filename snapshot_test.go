@@ -0,0 +1,110 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+)
+
+type snapshotSuite struct {
+	CleanupSuite
+	dir string
+}
+
+var _ = gc.Suite(&snapshotSuite{})
+
+func (s *snapshotSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.PatchValue(&snapshotDir, s.dir)
+}
+
+type snapshotValue struct {
+	Name  string
+	Tags  map[string]int
+	Items []string
+}
+
+var testSnapshotValue = snapshotValue{
+	Name:  "foo",
+	Tags:  map[string]int{"b": 2, "a": 1},
+	Items: []string{"x", "y"},
+}
+
+const testSnapshot = `{
+	"Name": "foo",
+	"Tags": {
+		"a": 1,
+		"b": 2
+	},
+	"Items": [
+		"x",
+		"y"
+	]
+}
+`
+
+func (s *snapshotSuite) TestUpdate(c *gc.C) {
+	s.PatchValue(updateSnapshots, true)
+	c.Assert(CheckSnapshot(c, "", testSnapshotValue), gc.Equals, true)
+	c.Assert(CheckSnapshot(c, "other", []int{1}), gc.Equals, true)
+
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, "snapshotSuite.TestUpdate.json"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, testSnapshot)
+	data, err = ioutil.ReadFile(filepath.Join(s.dir, "snapshotSuite.TestUpdate.other.json"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "[\n\t1\n]\n")
+}
+
+func (s *snapshotSuite) TestMatch(c *gc.C) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, "snapshotSuite.TestMatch.json"), []byte(testSnapshot), 0644)
+	c.Assert(err, gc.IsNil)
+	AssertSnapshot(c, "", testSnapshotValue)
+}
+
+func (s *snapshotSuite) TestMismatch(c *gc.C) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, "snapshotSuite.TestMismatch.json"), []byte(testSnapshot), 0644)
+	c.Assert(err, gc.IsNil)
+	value := testSnapshotValue
+	value.Name = "bar"
+	c.ExpectFailure("snapshot differs")
+	CheckSnapshot(c, "", value)
+}
+
+func (s *snapshotSuite) TestMissing(c *gc.C) {
+	c.ExpectFailure("no snapshot file")
+	AssertSnapshot(c, "", testSnapshotValue)
+}
+
+func (s *snapshotSuite) TestSnapshotPath(c *gc.C) {
+	c.Assert(snapshotPath(c, "", ".json"), gc.Equals, filepath.Join(s.dir, "snapshotSuite.TestSnapshotPath.json"))
+	c.Assert(snapshotPath(c, "x", ".json"), gc.Equals, filepath.Join(s.dir, "snapshotSuite.TestSnapshotPath.x.json"))
+	c.Assert(snapshotPath(c, "x", ".txt"), gc.Equals, filepath.Join(s.dir, "snapshotSuite.TestSnapshotPath.x.txt"))
+}
+
+func (s *snapshotSuite) TestTextUpdate(c *gc.C) {
+	s.PatchValue(updateSnapshots, true)
+	c.Assert(CheckTextSnapshot(c, "help", "usage: foo\n"), gc.Equals, true)
+
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, "snapshotSuite.TestTextUpdate.help.txt"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "usage: foo\n")
+}
+
+func (s *snapshotSuite) TestTextMatch(c *gc.C) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, "snapshotSuite.TestTextMatch.txt"), []byte("a\nb"), 0644)
+	c.Assert(err, gc.IsNil)
+	AssertTextSnapshot(c, "", "a\nb")
+}
+
+func (s *snapshotSuite) TestTextMismatch(c *gc.C) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, "snapshotSuite.TestTextMismatch.txt"), []byte("a\nb\n"), 0644)
+	c.Assert(err, gc.IsNil)
+	c.ExpectFailure("snapshot differs")
+	CheckTextSnapshot(c, "", "a\nc\n")
+}
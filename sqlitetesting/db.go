@@ -0,0 +1,261 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package sqlitetesting provides fixtures for tests of code that
+// stores data in SQLite.
+package sqlitetesting
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	_ "github.com/mattn/go-sqlite3"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+// Row holds a table row, mapping column names to values.
+type Row map[string]interface{}
+
+// String returns the row's columns and values, in column order.
+func (r Row) String() string {
+	names, values := r.columns()
+	var buf strings.Builder
+	buf.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if b, ok := values[i].([]byte); ok {
+			fmt.Fprintf(&buf, "%s: x'%x'", name, b)
+		} else {
+			fmt.Fprintf(&buf, "%s: %#v", name, values[i])
+		}
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// dbCount is used to give each in-memory database a unique name.
+var dbCount int64
+
+// NewDB opens a new in-memory database and applies the schema files in
+// schemaDir to it: every file whose name ends in ".sql", in lexical
+// order, so that migrations named 0001_init.sql, 0002_users.sql and
+// so on are applied in sequence. If schemaDir is empty, the database is
+// left empty. Foreign key constraints are enforced.
+//
+// The database lives until it is closed; the caller should close it
+// when finished with it.
+func NewDB(c *gc.C, schemaDir string) *sql.DB {
+	// Connections to the same named in-memory database with a
+	// shared cache see the same data, so the database stays
+	// consistent whichever pooled connection is used.
+	name := fmt.Sprintf("file:sqlitetesting%d?mode=memory&cache=shared&_foreign_keys=1", atomic.AddInt64(&dbCount, 1))
+	db, err := sql.Open("sqlite3", name)
+	c.Assert(err, gc.IsNil)
+	// The database is discarded when its last connection closes,
+	// so one connection is kept open.
+	db.SetConnMaxLifetime(0)
+	db.SetMaxIdleConns(1)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		c.Fatalf("cannot open database: %v", err)
+	}
+	if schemaDir != "" {
+		if err := applySchema(db, schemaDir); err != nil {
+			db.Close()
+			c.Fatalf("%v", err)
+		}
+	}
+	return db
+}
+
+// applySchema applies the schema files in dir to db, each in a
+// transaction of its own.
+func applySchema(db *sql.DB, dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no schema files found in %s", dir)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(string(data)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("cannot apply schema file %s: %v", path, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("cannot apply schema file %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// Seed inserts the given rows into the table, in a single transaction.
+// Rows need not have the same columns; columns that a row omits take
+// their default values.
+func Seed(c *gc.C, db *sql.DB, table string, rows ...Row) {
+	tx, err := db.Begin()
+	c.Assert(err, gc.IsNil)
+	for i, row := range rows {
+		names, values := row.columns()
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdent(table), quoteIdents(names), placeholders)
+		if len(names) == 0 {
+			query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", quoteIdent(table))
+		}
+		if _, err := tx.Exec(query, values...); err != nil {
+			tx.Rollback()
+			c.Fatalf("cannot insert row %d %v into %s: %v", i, row, table, err)
+		}
+	}
+	err = tx.Commit()
+	c.Assert(err, gc.IsNil)
+}
+
+// AssertTable checks that the table holds exactly the expected rows, in
+// any order. Only the columns named in the expected rows are compared,
+// so that, for example, generated keys can be left out; every expected
+// row must name the same columns. On failure, the test reports a diff
+// of the sorted rows.
+func AssertTable(c *gc.C, db *sql.DB, table string, expected ...Row) {
+	var names []string
+	if len(expected) > 0 {
+		names, _ = expected[0].columns()
+		for i, row := range expected[1:] {
+			if rowNames, _ := row.columns(); strings.Join(rowNames, ",") != strings.Join(names, ",") {
+				c.Fatalf("expected row %d has columns %q, but row 0 has %q", i+1, rowNames, names)
+			}
+		}
+	}
+	columns := "*"
+	if len(names) > 0 {
+		columns = quoteIdents(names)
+	}
+	obtained := queryRows(c, db, fmt.Sprintf("SELECT %s FROM %s", columns, quoteIdent(table)))
+	sortRows(obtained)
+	expected = normalizeRows(expected)
+	sortRows(expected)
+	c.Assert(obtained, jc.ListEquals, expected, gc.Commentf("contents of table %s", table))
+}
+
+// AssertQuery checks that the query returns exactly the expected rows,
+// in order. On failure, the test reports a diff of the rows.
+func AssertQuery(c *gc.C, db *sql.DB, expected []Row, query string, args ...interface{}) {
+	obtained := queryRows(c, db, query, args...)
+	c.Assert(obtained, jc.ListEquals, normalizeRows(expected), gc.Commentf("results of query %q", query))
+}
+
+// queryRows returns the rows returned by the query.
+func queryRows(c *gc.C, db *sql.DB, query string, args ...interface{}) []Row {
+	rows, err := db.Query(query, args...)
+	c.Assert(err, gc.IsNil, gc.Commentf("query %q", query))
+	defer rows.Close()
+	names, err := rows.Columns()
+	c.Assert(err, gc.IsNil)
+	result := []Row{}
+	for rows.Next() {
+		values := make([]interface{}, len(names))
+		ptrs := make([]interface{}, len(names))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		err := rows.Scan(ptrs...)
+		c.Assert(err, gc.IsNil)
+		row := make(Row)
+		for i, name := range names {
+			row[name] = values[i]
+		}
+		result = append(result, row)
+	}
+	c.Assert(rows.Err(), gc.IsNil)
+	return result
+}
+
+// normalizeRows returns a copy of rows holding values of the types
+// that the driver returns, so that, for example, an expected int
+// equals the int64 read from the database.
+func normalizeRows(rows []Row) []Row {
+	result := make([]Row, len(rows))
+	for i, row := range rows {
+		result[i] = make(Row)
+		for name, v := range row {
+			result[i][name] = normalizeValue(v)
+		}
+	}
+	return result
+}
+
+func normalizeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	}
+	return v
+}
+
+// sortRows sorts rows by their string forms, so that tables can be
+// compared regardless of the order in which rows are returned.
+func sortRows(rows []Row) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].String() < rows[j].String()
+	})
+}
+
+// columns returns the row's column names in sorted order, and the
+// corresponding values.
+func (r Row) columns() ([]string, []interface{}) {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([]interface{}, len(names))
+	for i, name := range names {
+		values[i] = r[name]
+	}
+	return names, values
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
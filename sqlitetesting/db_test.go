@@ -0,0 +1,124 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package sqlitetesting_test
+
+import (
+	"os"
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/sqlitetesting"
+)
+
+type dbSuite struct{}
+
+var _ = gc.Suite(&dbSuite{})
+
+func (s *dbSuite) TestNewDBAppliesSchemaInOrder(c *gc.C) {
+	db := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db.Close()
+	sqlitetesting.AssertQuery(c, db, []sqlitetesting.Row{
+		{"name": "id"},
+		{"name": "name"},
+		{"name": "owner_id"},
+		{"name": "size"},
+	}, "SELECT name FROM pragma_table_info(?) ORDER BY cid", "widgets")
+}
+
+func (s *dbSuite) TestNewDBEmpty(c *gc.C) {
+	db := sqlitetesting.NewDB(c, "")
+	defer db.Close()
+	sqlitetesting.AssertTable(c, db, "sqlite_schema")
+}
+
+func (s *dbSuite) TestNewDBSeparateDatabases(c *gc.C) {
+	db1 := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db1.Close()
+	db2 := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db2.Close()
+	sqlitetesting.Seed(c, db1, "owners", sqlitetesting.Row{"name": "alice"})
+	sqlitetesting.AssertTable(c, db1, "owners", sqlitetesting.Row{"name": "alice"})
+	sqlitetesting.AssertTable(c, db2, "owners")
+}
+
+func (s *dbSuite) TestNewDBBadSchema(c *gc.C) {
+	dir := c.MkDir()
+	err := os.WriteFile(filepath.Join(dir, "0001_bad.sql"), []byte("CREATE TABLE"), 0644)
+	c.Assert(err, gc.IsNil)
+	c.ExpectFailure("schema file has a syntax error")
+	sqlitetesting.NewDB(c, dir)
+}
+
+func (s *dbSuite) TestNewDBNoSchemaFiles(c *gc.C) {
+	c.ExpectFailure("schema directory is empty")
+	sqlitetesting.NewDB(c, c.MkDir())
+}
+
+func (s *dbSuite) TestForeignKeysEnforced(c *gc.C) {
+	db := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db.Close()
+	_, err := db.Exec("INSERT INTO widgets (name, owner_id) VALUES ('cog', 99)")
+	c.Assert(err, gc.ErrorMatches, "FOREIGN KEY constraint failed")
+}
+
+func (s *dbSuite) TestSeedFails(c *gc.C) {
+	db := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db.Close()
+	c.ExpectFailure("row violates a constraint")
+	sqlitetesting.Seed(c, db, "owners",
+		sqlitetesting.Row{"name": "alice"},
+		sqlitetesting.Row{"name": "alice"},
+	)
+}
+
+func (s *dbSuite) TestSeedDefaultValues(c *gc.C) {
+	db := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db.Close()
+	_, err := db.Exec("CREATE TABLE counters (n INTEGER DEFAULT 7)")
+	c.Assert(err, gc.IsNil)
+	sqlitetesting.Seed(c, db, "counters", sqlitetesting.Row{})
+	sqlitetesting.AssertTable(c, db, "counters", sqlitetesting.Row{"n": 7})
+}
+
+func (s *dbSuite) TestAssertTableMismatch(c *gc.C) {
+	db := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db.Close()
+	sqlitetesting.Seed(c, db, "owners", sqlitetesting.Row{"name": "alice"}, sqlitetesting.Row{"name": "bob"})
+	c.ExpectFailure("table holds bob, not carol")
+	sqlitetesting.AssertTable(c, db, "owners",
+		sqlitetesting.Row{"name": "alice"},
+		sqlitetesting.Row{"name": "carol"},
+	)
+}
+
+func (s *dbSuite) TestAssertTableColumnMismatch(c *gc.C) {
+	db := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db.Close()
+	c.ExpectFailure("expected rows name different columns")
+	sqlitetesting.AssertTable(c, db, "owners",
+		sqlitetesting.Row{"name": "alice"},
+		sqlitetesting.Row{"id": 2, "name": "bob"},
+	)
+}
+
+func (s *dbSuite) TestAssertQueryOrder(c *gc.C) {
+	db := sqlitetesting.NewDB(c, "testdata/schema")
+	defer db.Close()
+	sqlitetesting.Seed(c, db, "owners", sqlitetesting.Row{"name": "alice"}, sqlitetesting.Row{"name": "bob"})
+	sqlitetesting.AssertQuery(c, db, []sqlitetesting.Row{
+		{"name": "bob"},
+		{"name": "alice"},
+	}, "SELECT name FROM owners ORDER BY name DESC")
+	c.ExpectFailure("query returns rows in a different order")
+	sqlitetesting.AssertQuery(c, db, []sqlitetesting.Row{
+		{"name": "alice"},
+		{"name": "bob"},
+	}, "SELECT name FROM owners ORDER BY name DESC")
+}
+
+func (s *dbSuite) TestRowString(c *gc.C) {
+	row := sqlitetesting.Row{"name": "cog", "size": 2.5, "id": int64(3), "data": []byte{1, 255}, "owner": nil}
+	c.Assert(row.String(), gc.Equals, `{data: x'01ff', id: 3, name: "cog", owner: <nil>, size: 2.5}`)
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package sqlitetesting
+
+import (
+	"database/sql"
+
+	gc "gopkg.in/check.v1"
+)
+
+// SQLiteSuite gives each test a new in-memory SQLite database, created
+// from the schema files in SchemaDir as by NewDB:
+//
+//	type storeSuite struct {
+//		sqlitetesting.SQLiteSuite
+//	}
+//
+//	var _ = gc.Suite(&storeSuite{
+//		SQLiteSuite: sqlitetesting.SQLiteSuite{SchemaDir: "testdata/schema"},
+//	})
+type SQLiteSuite struct {
+	// SchemaDir holds the directory holding the schema files.
+	SchemaDir string
+
+	// DB holds the database for the current test.
+	DB *sql.DB
+}
+
+func (s *SQLiteSuite) SetUpSuite(c *gc.C) {}
+
+func (s *SQLiteSuite) TearDownSuite(c *gc.C) {}
+
+func (s *SQLiteSuite) SetUpTest(c *gc.C) {
+	s.DB = NewDB(c, s.SchemaDir)
+}
+
+func (s *SQLiteSuite) TearDownTest(c *gc.C) {
+	if s.DB != nil {
+		c.Check(s.DB.Close(), gc.IsNil)
+		s.DB = nil
+	}
+}
+
+// Seed inserts rows into a table of the test's database. See the
+// package function of the same name.
+func (s *SQLiteSuite) Seed(c *gc.C, table string, rows ...Row) {
+	Seed(c, s.DB, table, rows...)
+}
+
+// AssertTable checks the contents of a table of the test's database.
+// See the package function of the same name.
+func (s *SQLiteSuite) AssertTable(c *gc.C, table string, expected ...Row) {
+	AssertTable(c, s.DB, table, expected...)
+}
+
+// AssertQuery checks the results of a query on the test's database.
+// See the package function of the same name.
+func (s *SQLiteSuite) AssertQuery(c *gc.C, expected []Row, query string, args ...interface{}) {
+	AssertQuery(c, s.DB, expected, query, args...)
+}
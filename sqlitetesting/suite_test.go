@@ -0,0 +1,43 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package sqlitetesting_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/sqlitetesting"
+)
+
+type suiteSuite struct {
+	sqlitetesting.SQLiteSuite
+}
+
+var _ = gc.Suite(&suiteSuite{
+	SQLiteSuite: sqlitetesting.SQLiteSuite{SchemaDir: "testdata/schema"},
+})
+
+func (s *suiteSuite) TestSeedAndAssert(c *gc.C) {
+	s.Seed(c, "owners", sqlitetesting.Row{"id": 1, "name": "alice"})
+	s.Seed(c, "widgets",
+		sqlitetesting.Row{"name": "sprocket", "owner_id": 1},
+		sqlitetesting.Row{"name": "cog", "size": 2.5},
+	)
+	s.AssertTable(c, "widgets",
+		sqlitetesting.Row{"name": "cog", "owner_id": nil, "size": 2.5},
+		sqlitetesting.Row{"name": "sprocket", "owner_id": 1, "size": 1.0},
+	)
+}
+
+// The two tests below each check that they do not see the other's
+// data, whichever runs first.
+
+func (s *suiteSuite) TestDatabasePerTest1(c *gc.C) {
+	s.AssertTable(c, "owners")
+	s.Seed(c, "owners", sqlitetesting.Row{"id": 1, "name": "alice"})
+}
+
+func (s *suiteSuite) TestDatabasePerTest2(c *gc.C) {
+	s.AssertTable(c, "owners")
+	s.Seed(c, "owners", sqlitetesting.Row{"id": 1, "name": "alice"})
+}
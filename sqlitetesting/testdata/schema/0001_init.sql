@@ -0,0 +1,10 @@
+CREATE TABLE owners (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE widgets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	owner_id INTEGER REFERENCES owners(id)
+);
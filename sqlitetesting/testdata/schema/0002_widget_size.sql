@@ -0,0 +1 @@
+ALTER TABLE widgets ADD COLUMN size REAL NOT NULL DEFAULT 1.0;
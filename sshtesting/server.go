@@ -0,0 +1,330 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package sshtesting provides an SSH server listening on a loopback
+// port, with scripted command execution and an SFTP subsystem, for
+// testing SSH client code.
+package sshtesting
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	gc "gopkg.in/check.v1"
+)
+
+// Command records a command executed on a Server.
+type Command struct {
+	// User holds the name of the user that executed the command.
+	User string
+
+	// Command holds the command line, as sent by the client.
+	Command string
+}
+
+// Response holds the scripted output of a command.
+type Response struct {
+	Stdout     string
+	Stderr     string
+	ExitStatus int
+}
+
+// CommandFunc runs a scripted command, reading the command's standard
+// input from stdin and writing its output to stdout and stderr, and
+// returns its exit status.
+type CommandFunc func(stdin io.Reader, stdout, stderr io.Writer) int
+
+// Server is an SSH server listening on a loopback port. It runs no real
+// commands; instead, each command that a client executes is recorded
+// and answered as scripted with Handle or HandleFunc. Interactive
+// shells are refused. The "sftp" subsystem serves the files in Root:
+//
+//	srv := sshtesting.NewServer(c)
+//	defer srv.Close()
+//	srv.AddPassword("deploy", "s3cret")
+//	srv.Handle("uname -r", sshtesting.Response{Stdout: "6.8.0\n"})
+//	... point the client under test at srv.Addr(), using
+//	    srv.HostKeyCallback() to check the host key ...
+//	c.Assert(srv.Commands(), jc.DeepEquals, []sshtesting.Command{
+//		{User: "deploy", Command: "uname -r"},
+//	})
+//
+// Commands that have not been scripted fail with exit status 127.
+type Server struct {
+	// HostKeys holds the server's host keys.
+	HostKeys []ssh.Signer
+
+	// Root holds the directory served by the SFTP subsystem, which
+	// clients see as "/". Tests may create and inspect files in it
+	// directly.
+	Root string
+
+	config   *ssh.ServerConfig
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu        sync.Mutex
+	passwords map[string]string
+	keys      map[string][]ssh.PublicKey
+	handlers  map[string]CommandFunc
+	commands  []Command
+	conns     map[net.Conn]bool
+	closed    bool
+}
+
+// NewKey returns a new Ed25519 key, for use as a host key or client
+// key.
+func NewKey(c *gc.C) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, gc.IsNil)
+	signer, err := ssh.NewSignerFromKey(key)
+	c.Assert(err, gc.IsNil)
+	return signer
+}
+
+// NewServer starts and returns a new Server that presents the given
+// host keys, or a new Ed25519 key if none are given. No users can log
+// in until they are added with AddPassword or AddAuthorizedKey. The
+// caller should call Close when finished with it.
+func NewServer(c *gc.C, hostKeys ...ssh.Signer) *Server {
+	if len(hostKeys) == 0 {
+		hostKeys = []ssh.Signer{NewKey(c)}
+	}
+	srv := &Server{
+		HostKeys:  hostKeys,
+		Root:      c.MkDir(),
+		passwords: make(map[string]string),
+		keys:      make(map[string][]ssh.PublicKey),
+		handlers:  make(map[string]CommandFunc),
+		conns:     make(map[net.Conn]bool),
+	}
+	srv.config = &ssh.ServerConfig{
+		PasswordCallback:  srv.checkPassword,
+		PublicKeyCallback: srv.checkPublicKey,
+	}
+	for _, key := range hostKeys {
+		srv.config.AddHostKey(key)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, gc.IsNil)
+	srv.listener = listener
+	srv.wg.Add(1)
+	go srv.serve()
+	return srv
+}
+
+// Addr returns the address on which the server listens.
+func (srv *Server) Addr() string {
+	return srv.listener.Addr().String()
+}
+
+// HostKeyCallback returns a callback for ssh.ClientConfig that accepts
+// only the server's host keys.
+func (srv *Server) HostKeyCallback() ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		for _, hostKey := range srv.HostKeys {
+			if bytes.Equal(hostKey.PublicKey().Marshal(), key.Marshal()) {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown host key %s", ssh.FingerprintSHA256(key))
+	}
+}
+
+// KnownHosts returns the contents of a known_hosts file that lists the
+// server's host keys, for clients that read one.
+func (srv *Server) KnownHosts() string {
+	var lines []string
+	for _, key := range srv.HostKeys {
+		lines = append(lines, knownhosts.Line([]string{srv.Addr()}, key.PublicKey()))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// AddPassword allows the user to log in with the given password.
+func (srv *Server) AddPassword(user, password string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.passwords[user] = password
+}
+
+// AddAuthorizedKey allows the user to log in with the given public
+// key.
+func (srv *Server) AddAuthorizedKey(user string, key ssh.PublicKey) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.keys[user] = append(srv.keys[user], key)
+}
+
+// Handle scripts the response to the given command line. The command's
+// standard input is discarded.
+func (srv *Server) Handle(command string, resp Response) {
+	srv.HandleFunc(command, func(stdin io.Reader, stdout, stderr io.Writer) int {
+		io.Copy(io.Discard, stdin)
+		io.WriteString(stdout, resp.Stdout)
+		io.WriteString(stderr, resp.Stderr)
+		return resp.ExitStatus
+	})
+}
+
+// HandleFunc scripts the given command line to be run by f, replacing
+// any earlier script for it.
+func (srv *Server) HandleFunc(command string, f CommandFunc) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.handlers[command] = f
+}
+
+// Commands returns the commands executed so far, in order, including
+// those that were not scripted.
+func (srv *Server) Commands() []Command {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]Command(nil), srv.commands...)
+}
+
+// Close stops the server, closing any client connections, and waits
+// for their goroutines to finish.
+func (srv *Server) Close() {
+	srv.mu.Lock()
+	srv.closed = true
+	srv.listener.Close()
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	srv.mu.Unlock()
+	srv.wg.Wait()
+}
+
+func (srv *Server) checkPassword(meta ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if p, ok := srv.passwords[meta.User()]; ok && p == string(password) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("password rejected for %s", meta.User())
+}
+
+func (srv *Server) checkPublicKey(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for _, k := range srv.keys[meta.User()] {
+		if bytes.Equal(k.Marshal(), key.Marshal()) {
+			return nil, nil
+		}
+	}
+	return nil, fmt.Errorf("public key rejected for %s", meta.User())
+}
+
+func (srv *Server) serve() {
+	defer srv.wg.Done()
+	for {
+		conn, err := srv.listener.Accept()
+		if err != nil {
+			return
+		}
+		srv.mu.Lock()
+		if srv.closed {
+			srv.mu.Unlock()
+			conn.Close()
+			return
+		}
+		srv.conns[conn] = true
+		srv.wg.Add(1)
+		srv.mu.Unlock()
+		go func() {
+			defer srv.wg.Done()
+			srv.serveConn(conn)
+			srv.mu.Lock()
+			delete(srv.conns, conn)
+			srv.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+func (srv *Server) serveConn(netConn net.Conn) {
+	conn, chans, reqs, err := ssh.NewServerConn(netConn, srv.config)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	go ssh.DiscardRequests(reqs)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			newChan.Reject(ssh.UnknownChannelType, "only session channels are supported")
+			continue
+		}
+		ch, reqs, err := newChan.Accept()
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer ch.Close()
+			srv.serveSession(conn.User(), ch, reqs)
+		}()
+	}
+}
+
+// serveSession serves requests on a session channel until a command or
+// subsystem has run.
+func (srv *Server) serveSession(user string, ch ssh.Channel, reqs <-chan *ssh.Request) {
+	for req := range reqs {
+		switch req.Type {
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			req.Reply(true, nil)
+			go ssh.DiscardRequests(reqs)
+			srv.exec(user, payload.Command, ch)
+			return
+		case "subsystem":
+			var payload struct{ Name string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil || payload.Name != "sftp" {
+				req.Reply(false, nil)
+				continue
+			}
+			req.Reply(true, nil)
+			go ssh.DiscardRequests(reqs)
+			srv.serveSFTP(ch)
+			return
+		case "env", "pty-req":
+			// Accepted, but they make no difference.
+			req.Reply(true, nil)
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+// exec runs the command on the channel, and sends its exit status.
+func (srv *Server) exec(user, command string, ch ssh.Channel) {
+	srv.mu.Lock()
+	srv.commands = append(srv.commands, Command{User: user, Command: command})
+	f, ok := srv.handlers[command]
+	srv.mu.Unlock()
+	if !ok {
+		f = func(stdin io.Reader, stdout, stderr io.Writer) int {
+			fmt.Fprintf(stderr, "sshtesting: unexpected command %q\n", command)
+			return 127
+		}
+	}
+	status := f(ch, ch, ch.Stderr())
+	ch.CloseWrite()
+	ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package sshtesting_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/sshtesting"
+)
+
+type serverSuite struct {
+	srv *sshtesting.Server
+}
+
+var _ = gc.Suite(&serverSuite{})
+
+func (s *serverSuite) SetUpTest(c *gc.C) {
+	s.srv = sshtesting.NewServer(c)
+	s.srv.AddPassword("deploy", "s3cret")
+}
+
+func (s *serverSuite) TearDownTest(c *gc.C) {
+	s.srv.Close()
+}
+
+func (s *serverSuite) dial(c *gc.C, user string, auth ...ssh.AuthMethod) *ssh.Client {
+	client, err := ssh.Dial("tcp", s.srv.Addr(), &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: s.srv.HostKeyCallback(),
+	})
+	c.Assert(err, gc.IsNil)
+	return client
+}
+
+// run runs the command in a new session and returns its output.
+func run(c *gc.C, client *ssh.Client, command, stdin string) (stdout, stderr string, err error) {
+	session, err := client.NewSession()
+	c.Assert(err, gc.IsNil)
+	defer session.Close()
+	var outBuf, errBuf bytes.Buffer
+	session.Stdin = strings.NewReader(stdin)
+	session.Stdout = &outBuf
+	session.Stderr = &errBuf
+	err = session.Run(command)
+	return outBuf.String(), errBuf.String(), err
+}
+
+func (s *serverSuite) TestHandle(c *gc.C) {
+	s.srv.Handle("uname -r", sshtesting.Response{Stdout: "6.8.0\n"})
+	s.srv.Handle("false", sshtesting.Response{Stderr: "failed\n", ExitStatus: 3})
+	client := s.dial(c, "deploy", ssh.Password("s3cret"))
+	defer client.Close()
+
+	stdout, stderr, err := run(c, client, "uname -r", "")
+	c.Assert(err, gc.IsNil)
+	c.Assert(stdout, gc.Equals, "6.8.0\n")
+	c.Assert(stderr, gc.Equals, "")
+
+	_, stderr, err = run(c, client, "false", "")
+	c.Assert(err, gc.FitsTypeOf, &ssh.ExitError{})
+	c.Assert(err.(*ssh.ExitError).ExitStatus(), gc.Equals, 3)
+	c.Assert(stderr, gc.Equals, "failed\n")
+
+	_, stderr, err = run(c, client, "rm -rf /", "")
+	c.Assert(err, gc.FitsTypeOf, &ssh.ExitError{})
+	c.Assert(err.(*ssh.ExitError).ExitStatus(), gc.Equals, 127)
+	c.Assert(stderr, gc.Equals, "sshtesting: unexpected command \"rm -rf /\"\n")
+
+	c.Assert(s.srv.Commands(), jc.DeepEquals, []sshtesting.Command{
+		{User: "deploy", Command: "uname -r"},
+		{User: "deploy", Command: "false"},
+		{User: "deploy", Command: "rm -rf /"},
+	})
+}
+
+func (s *serverSuite) TestHandleFunc(c *gc.C) {
+	s.srv.HandleFunc("tr a-z A-Z", func(stdin io.Reader, stdout, stderr io.Writer) int {
+		data, err := io.ReadAll(stdin)
+		c.Check(err, gc.IsNil)
+		io.WriteString(stdout, strings.ToUpper(string(data)))
+		return 0
+	})
+	client := s.dial(c, "deploy", ssh.Password("s3cret"))
+	defer client.Close()
+	stdout, _, err := run(c, client, "tr a-z A-Z", "hello")
+	c.Assert(err, gc.IsNil)
+	c.Assert(stdout, gc.Equals, "HELLO")
+}
+
+func (s *serverSuite) TestShellRefused(c *gc.C) {
+	client := s.dial(c, "deploy", ssh.Password("s3cret"))
+	defer client.Close()
+	session, err := client.NewSession()
+	c.Assert(err, gc.IsNil)
+	defer session.Close()
+	err = session.Shell()
+	c.Assert(err, gc.ErrorMatches, "ssh: could not start shell")
+}
+
+func (s *serverSuite) TestPublicKeyAuth(c *gc.C) {
+	key := sshtesting.NewKey(c)
+	s.srv.AddAuthorizedKey("ci", key.PublicKey())
+	s.srv.Handle("id -un", sshtesting.Response{Stdout: "ci\n"})
+	client := s.dial(c, "ci", ssh.PublicKeys(key))
+	defer client.Close()
+	_, _, err := run(c, client, "id -un", "")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.srv.Commands(), jc.DeepEquals, []sshtesting.Command{{User: "ci", Command: "id -un"}})
+}
+
+func (s *serverSuite) TestAuthFailures(c *gc.C) {
+	config := &ssh.ClientConfig{
+		User:            "deploy",
+		Auth:            []ssh.AuthMethod{ssh.Password("wrong")},
+		HostKeyCallback: s.srv.HostKeyCallback(),
+	}
+	_, err := ssh.Dial("tcp", s.srv.Addr(), config)
+	c.Assert(err, gc.ErrorMatches, ".*unable to authenticate.*")
+
+	// Keys are authorized per user.
+	key := sshtesting.NewKey(c)
+	s.srv.AddAuthorizedKey("ci", key.PublicKey())
+	config.Auth = []ssh.AuthMethod{ssh.PublicKeys(key)}
+	_, err = ssh.Dial("tcp", s.srv.Addr(), config)
+	c.Assert(err, gc.ErrorMatches, ".*unable to authenticate.*")
+}
+
+func (s *serverSuite) TestHostKeys(c *gc.C) {
+	hostKey := sshtesting.NewKey(c)
+	srv := sshtesting.NewServer(c, hostKey)
+	defer srv.Close()
+	srv.AddPassword("u", "p")
+	c.Assert(srv.HostKeys, jc.DeepEquals, []ssh.Signer{hostKey})
+
+	// The host key callback of another server rejects this one.
+	_, err := ssh.Dial("tcp", srv.Addr(), &ssh.ClientConfig{
+		User:            "u",
+		Auth:            []ssh.AuthMethod{ssh.Password("p")},
+		HostKeyCallback: s.srv.HostKeyCallback(),
+	})
+	c.Assert(err, gc.ErrorMatches, ".*unknown host key SHA256:.*")
+
+	// The known_hosts file is accepted by knownhosts.
+	path := filepath.Join(c.MkDir(), "known_hosts")
+	err = os.WriteFile(path, []byte(srv.KnownHosts()), 0600)
+	c.Assert(err, gc.IsNil)
+	callback, err := knownhosts.New(path)
+	c.Assert(err, gc.IsNil)
+	client, err := ssh.Dial("tcp", srv.Addr(), &ssh.ClientConfig{
+		User:            "u",
+		Auth:            []ssh.AuthMethod{ssh.Password("p")},
+		HostKeyCallback: callback,
+	})
+	c.Assert(err, gc.IsNil)
+	client.Close()
+}
+
+func (s *serverSuite) TestSFTP(c *gc.C) {
+	err := os.WriteFile(filepath.Join(s.srv.Root, "existing.txt"), []byte("hello"), 0644)
+	c.Assert(err, gc.IsNil)
+	client := s.dial(c, "deploy", ssh.Password("s3cret"))
+	defer client.Close()
+	sc, err := sftp.NewClient(client)
+	c.Assert(err, gc.IsNil)
+	defer sc.Close()
+
+	f, err := sc.Open("/existing.txt")
+	c.Assert(err, gc.IsNil)
+	data, err := io.ReadAll(f)
+	f.Close()
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "hello")
+
+	err = sc.Mkdir("/etc")
+	c.Assert(err, gc.IsNil)
+	f, err = sc.Create("/etc/app.conf")
+	c.Assert(err, gc.IsNil)
+	_, err = f.Write([]byte("port = 80\n"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(f.Close(), gc.IsNil)
+	err = sc.Chmod("/etc/app.conf", 0600)
+	c.Assert(err, gc.IsNil)
+	err = sc.Rename("/etc/app.conf", "/etc/app.cfg")
+	c.Assert(err, gc.IsNil)
+
+	data, err = os.ReadFile(filepath.Join(s.srv.Root, "etc", "app.cfg"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "port = 80\n")
+	info, err := os.Stat(filepath.Join(s.srv.Root, "etc", "app.cfg"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	infos, err := sc.ReadDir("/")
+	c.Assert(err, gc.IsNil)
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	c.Assert(names, jc.SameContents, []string{"etc", "existing.txt"})
+
+	err = sc.Remove("/existing.txt")
+	c.Assert(err, gc.IsNil)
+	_, err = os.Stat(filepath.Join(s.srv.Root, "existing.txt"))
+	c.Assert(os.IsNotExist(err), jc.IsTrue)
+
+	// Paths cannot escape the root.
+	f, err = sc.Create("../../outside.txt")
+	c.Assert(err, gc.IsNil)
+	c.Assert(f.Close(), gc.IsNil)
+	_, err = os.Stat(filepath.Join(s.srv.Root, "outside.txt"))
+	c.Assert(err, gc.IsNil)
+
+	// Commands are only recorded for exec requests.
+	c.Assert(s.srv.Commands(), gc.HasLen, 0)
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package sshtesting
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/sftp"
+)
+
+// serveSFTP serves the SFTP subsystem on the channel, confined to
+// srv.Root.
+func (srv *Server) serveSFTP(rwc io.ReadWriteCloser) {
+	fs := rootFS(srv.Root)
+	server := sftp.NewRequestServer(rwc, sftp.Handlers{
+		FileGet:  fs,
+		FilePut:  fs,
+		FileCmd:  fs,
+		FileList: fs,
+	})
+	server.Serve()
+	server.Close()
+}
+
+// rootFS implements the SFTP request handlers on the files under a
+// directory.
+type rootFS string
+
+// path returns the local path of the given SFTP path. The request
+// server cleans paths, so they cannot refer to files outside the root.
+func (fs rootFS) path(p string) string {
+	return filepath.Join(string(fs), filepath.FromSlash(p))
+}
+
+func (fs rootFS) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	return os.Open(fs.path(r.Filepath))
+}
+
+func (fs rootFS) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	pflags := r.Pflags()
+	flags := os.O_WRONLY
+	if pflags.Creat {
+		flags |= os.O_CREATE
+	}
+	if pflags.Trunc {
+		flags |= os.O_TRUNC
+	}
+	if pflags.Excl {
+		flags |= os.O_EXCL
+	}
+	return os.OpenFile(fs.path(r.Filepath), flags, 0644)
+}
+
+func (fs rootFS) Filecmd(r *sftp.Request) error {
+	path := fs.path(r.Filepath)
+	switch r.Method {
+	case "Setstat":
+		attrs, flags := r.Attributes(), r.AttrFlags()
+		if flags.Size {
+			if err := os.Truncate(path, int64(attrs.Size)); err != nil {
+				return err
+			}
+		}
+		if flags.Permissions {
+			if err := os.Chmod(path, attrs.FileMode().Perm()); err != nil {
+				return err
+			}
+		}
+		if flags.Acmodtime {
+			atime, mtime := time.Unix(int64(attrs.Atime), 0), time.Unix(int64(attrs.Mtime), 0)
+			if err := os.Chtimes(path, atime, mtime); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Rename":
+		return os.Rename(path, fs.path(r.Target))
+	case "Rmdir", "Remove":
+		return os.Remove(path)
+	case "Mkdir":
+		return os.Mkdir(path, 0755)
+	}
+	return sftp.ErrSSHFxOpUnsupported
+}
+
+func (fs rootFS) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	path := fs.path(r.Filepath)
+	switch r.Method {
+	case "List":
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		infos := make(listerAt, 0, len(entries))
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, info)
+		}
+		return infos, nil
+	case "Stat":
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		return listerAt{info}, nil
+	}
+	return nil, sftp.ErrSSHFxOpUnsupported
+}
+
+// listerAt implements sftp.ListerAt on a slice of file information.
+type listerAt []os.FileInfo
+
+func (l listerAt) ListAt(dest []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(dest, l[offset:])
+	if n < len(dest) {
+		return n, io.EOF
+	}
+	return n, nil
+}
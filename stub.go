@@ -186,12 +186,13 @@ func (f *Stub) SetErrors(errors ...error) {
 
 // CheckCalls verifies that the history of calls on the stub's methods
 // matches the expected calls. The receivers are not checked. If they
-// are significant then check Stub.Receivers separately.
+// are significant then check Stub.Receivers separately. On failure, a
+// diff of the expected and recorded calls is reported.
 func (f *Stub) CheckCalls(c *gc.C, expected []StubCall) {
 	if !f.CheckCallNames(c, stubCallNames(expected...)...) {
 		return
 	}
-	c.Check(f.calls, jc.DeepEquals, expected)
+	c.Check(f.Calls(), jc.ListEquals, expected)
 }
 
 // CheckCallsUnordered verifies that the history of calls on the stub's methods
@@ -201,7 +202,7 @@ func (f *Stub) CheckCalls(c *gc.C, expected []StubCall) {
 // whether they have been made.
 func (f *Stub) CheckCallsUnordered(c *gc.C, expected []StubCall) {
 	// Take a copy of all calls made to the stub.
-	calls := f.calls[:]
+	calls := f.Calls()
 	checkCallMade := func(call StubCall) {
 		for i, madeCall := range calls {
 			if reflect.DeepEqual(call, madeCall) {
@@ -240,12 +241,13 @@ func (f *Stub) CheckCall(c *gc.C, index int, funcName string, args ...interface{
 }
 
 // CheckCallNames verifies that the in-order list of called method names
-// matches the expected calls.
+// matches the expected calls. On failure, a diff of the expected and
+// recorded names is reported.
 func (f *Stub) CheckCallNames(c *gc.C, expected ...string) bool {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	funcNames := stubCallNames(f.calls...)
-	return c.Check(funcNames, jc.DeepEquals, expected)
+	return c.Check(funcNames, jc.ListEquals, expected)
 }
 
 // CheckNoCalls verifies that none of the stub's methods have been called.
@@ -468,3 +468,20 @@ func (s *stubSuite) TestMethodCallsUnorderedDuplicateFail(c *gc.C) {
 	}})
 	c.ExpectFailure("should have failed as expected calls differ from calls made")
 }
+
+func (s *stubSuite) TestCheckCallsUnorderedLeavesCallsIntact(c *gc.C) {
+	s.stub.AddCall("aFunc", "arg")
+	s.stub.AddCall("Method2")
+
+	s.stub.CheckCallsUnordered(c, []testing.StubCall{{
+		FuncName: "Method2",
+	}, {
+		FuncName: "aFunc",
+		Args:     []interface{}{"arg"},
+	}})
+	s.stub.CheckCallNames(c, "aFunc", "Method2")
+}
+
+func (s *stubSuite) TestCheckCallsUnorderedNoCalls(c *gc.C) {
+	s.stub.CheckCallsUnordered(c, nil)
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+var tableFilter = flag.String("table.f", "", "Regular expression selecting which table test cases to run by name")
+
+// caseNameFields holds the names of the fields, matched
+// case-insensitively, from which RunTable takes the name of a case, in
+// order of preference.
+var caseNameFields = []string{"about", "name", "description", "summary"}
+
+// RunTable calls f for each of the test cases in turn, logging the
+// index and name of each case before running it, so that failures are
+// shown under the case that caused them. A case is named after the
+// first of its about, name, description or summary string fields that
+// it has, matched case-insensitively, and cases that are not structs
+// are named after their value:
+//
+//	type parseTest struct {
+//		about  string
+//		input  string
+//		expect int
+//	}
+//
+//	var parseTests = []parseTest{...}
+//
+//	testing.RunTable(c, parseTests, func(c *gc.C, test parseTest) {
+//		n, err := parse(test.input)
+//		c.Assert(err, gc.IsNil)
+//		c.Assert(n, gc.Equals, test.expect)
+//	})
+//
+// Each case runs in its own goroutine, so a case that stops with
+// c.Assert or panics does not stop the following cases. After all the
+// cases have run, the failed cases are listed.
+//
+// When the tests are run with the -table.f flag, only the cases with
+// names that match its regular expression are run. Cases with a tags
+// field of type []string, matched case-insensitively, are skipped
+// unless their tags are selected by the current tag filter (see
+// TagFilter); the skipped cases are logged.
+//
+// Cases with a timeout field of type time.Duration, matched
+// case-insensitively, fail if they have not finished within that time
+// (see RunTableTimeout).
+//
+// Cases with a non-empty xfail string field, matched case-insensitively,
+// are expected to fail because of the known bug that it refers to (see
+// XFail).
+//
+// When the tests are run with the -shuffle.order flag, the cases are
+// run in a random order (see RunSuites), but are still logged with
+// their original indexes.
+func RunTable[T any](c *gc.C, cases []T, f func(c *gc.C, test T)) {
+	RunTableTimeout(c, 0, cases, f)
+}
+
+// RunTableTimeout is like RunTable, but fails any case that has not
+// finished within the given timeout, unless the case has a non-zero
+// timeout field of its own, which is used instead. When a case times
+// out, the stacks of all goroutines are logged, to show where it is
+// stuck, and the following cases are run; the stuck case is left
+// running in the background. This shows which case hung, rather than
+// leaving the whole test binary to be killed by the go test timeout. A
+// zero timeout means that cases without their own timeout may run for
+// any time.
+func RunTableTimeout[T any](c *gc.C, timeout time.Duration, cases []T, f func(c *gc.C, test T)) {
+	var filter *regexp.Regexp
+	if *tableFilter != "" {
+		var err error
+		filter, err = regexp.Compile(*tableFilter)
+		c.Assert(err, gc.IsNil, gc.Commentf("invalid -table.f flag"))
+	}
+	tagFilter, err := CurrentTagFilter()
+	c.Assert(err, gc.IsNil)
+	var failed []string
+	skipped := 0
+	for _, i := range shuffleCases(c, len(cases)) {
+		test := cases[i]
+		name := caseName(test)
+		if filter != nil && !filter.MatchString(name) {
+			continue
+		}
+		if tags := caseTags(test); !tagFilter.Selects(tags...) {
+			c.Logf("test %d: %s: skipped: tags [%s] not selected by tag filter %q", i, name, strings.Join(tags, " "), tagFilter)
+			skipped++
+			continue
+		}
+		c.Logf("test %d: %s", i, name)
+		d := timeout
+		if t := caseTimeout(test); t > 0 {
+			d = t
+		}
+		run := f
+		if issue := caseXFail(test); issue != "" {
+			run = func(c *gc.C, test T) {
+				XFail(c, issue, func(c *gc.C) { f(c, test) })
+			}
+		}
+		if !runCase(c, test, d, run) {
+			failed = append(failed, fmt.Sprintf("%d (%s)", i, name))
+		}
+	}
+	if skipped > 0 {
+		c.Logf("%d of %d cases skipped by tag filter %q", skipped, len(cases), tagFilter)
+	}
+	if len(failed) > 0 {
+		c.Logf("%d of %d cases failed: %s", len(failed), len(cases), strings.Join(failed, ", "))
+	}
+}
+
+// runCase runs a single case, and reports whether it succeeded. If
+// the timeout is non-zero, the case fails if it has not finished within
+// that time.
+func runCase[T any](c *gc.C, test T, timeout time.Duration, f func(c *gc.C, test T)) bool {
+	// Clear the test's failure while the case runs, so that a case
+	// that fails with c.Check can be told apart from an earlier one,
+	// and restore it afterwards.
+	if c.Failed() {
+		c.Succeed()
+		defer c.Fail()
+	}
+	returned := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				c.Errorf("test case panicked: %v\n%s", r, debug.Stack())
+			}
+		}()
+		f(c, test)
+		returned = true
+	}()
+	var timedOut <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timedOut = timer.C
+	}
+	select {
+	case <-done:
+	case <-timedOut:
+		c.Errorf("test case timed out after %v; goroutines:\n%s", timeout, goroutineStacks())
+		return false
+	}
+	return returned && !c.Failed()
+}
+
+// goroutineStacks returns the stacks of all goroutines.
+func goroutineStacks() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// caseName returns the name of a table test case.
+func caseName(test interface{}) string {
+	v, ok := caseStruct(test)
+	if !ok {
+		return fmt.Sprintf("%v", test)
+	}
+	for _, want := range caseNameFields {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if strings.EqualFold(field.Name, want) && field.Type.Kind() == reflect.String {
+				return v.Field(i).String()
+			}
+		}
+	}
+	return ""
+}
+
+// caseTags returns the tags of a table test case, held in its tags
+// field.
+func caseTags(test interface{}) []string {
+	v, ok := caseField(test, "tags", reflect.TypeOf([]string(nil)))
+	if !ok {
+		return nil
+	}
+	// The field is usually unexported, so its value cannot be
+	// obtained with Interface.
+	tags := make([]string, v.Len())
+	for i := range tags {
+		tags[i] = v.Index(i).String()
+	}
+	return tags
+}
+
+// caseTimeout returns the timeout of a table test case, held in its
+// timeout field.
+func caseTimeout(test interface{}) time.Duration {
+	v, ok := caseField(test, "timeout", reflect.TypeOf(time.Duration(0)))
+	if !ok {
+		return 0
+	}
+	return time.Duration(v.Int())
+}
+
+// caseXFail returns the issue because of which a table test case is
+// expected to fail, held in its xfail field.
+func caseXFail(test interface{}) string {
+	v, ok := caseField(test, "xfail", reflect.TypeOf(""))
+	if !ok {
+		return ""
+	}
+	return v.String()
+}
+
+// caseField returns the field of a table test case with the given
+// name, matched case-insensitively, and type, and whether there is
+// such a field.
+func caseField(test interface{}, name string, t reflect.Type) (reflect.Value, bool) {
+	v, ok := caseStruct(test)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if strings.EqualFold(field.Name, name) && field.Type == t {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// caseStruct returns the struct value of a table test case, and whether
+// it is a struct or a pointer to one.
+func caseStruct(test interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(test)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"flag"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type tableSuite struct{}
+
+var _ = gc.Suite(&tableSuite{})
+
+type tableCase struct {
+	about string
+	fail  bool
+	panic bool
+}
+
+var tableCases = []tableCase{
+	{about: "a"},
+	{about: "b", fail: true},
+	{about: "c"},
+	{about: "d", panic: true},
+}
+
+func (*tableSuite) TestRunTable(c *gc.C) {
+	var ran []string
+	testing.RunTable(c, tableCases[:1], func(c *gc.C, test tableCase) {
+		ran = append(ran, test.about)
+	})
+	testing.RunTable(c, []string{"x", "y"}, func(c *gc.C, test string) {
+		ran = append(ran, test)
+	})
+	c.Assert(ran, jc.DeepEquals, []string{"a", "x", "y"})
+	c.Assert(c.GetTestLog(), gc.Matches, "test 0: a\ntest 0: x\ntest 1: y\n")
+}
+
+func (*tableSuite) TestRunTableFilter(c *gc.C) {
+	err := flag.Set("table.f", "^[bc]$")
+	c.Assert(err, gc.IsNil)
+	defer flag.Set("table.f", "")
+	var ran []string
+	testing.RunTable(c, tableCases, func(c *gc.C, test tableCase) {
+		ran = append(ran, test.about)
+	})
+	c.Assert(ran, jc.DeepEquals, []string{"b", "c"})
+}
+
+// failingTableSuite is run by TestRunTableFailures rather than being
+// registered with gocheck.
+type failingTableSuite struct {
+	ran []string
+}
+
+func (s *failingTableSuite) TestTable(c *gc.C) {
+	testing.RunTable(c, tableCases, func(c *gc.C, test tableCase) {
+		s.ran = append(s.ran, test.about)
+		if test.panic {
+			panic("boom")
+		}
+		c.Assert(test.fail, gc.Equals, false)
+	})
+}
+
+func (*tableSuite) TestRunTableFailures(c *gc.C) {
+	var output bytes.Buffer
+	suite := &failingTableSuite{}
+	result := gc.Run(suite, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	// Every case runs, despite the earlier failures.
+	c.Assert(suite.ran, jc.DeepEquals, []string{"a", "b", "c", "d"})
+	c.Assert(output.String(), gc.Matches, `(?s).*test 1: b\n.*test 2: c\ntest 3: d\n.*test case panicked: boom\n.*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*\n2 of 4 cases failed: 1 \(b\), 3 \(d\)\n.*`)
+}
+
+// checkFailingTableSuite is run by TestRunTableCheckFailures rather
+// than being registered with gocheck.
+type checkFailingTableSuite struct{}
+
+func (*checkFailingTableSuite) TestTable(c *gc.C) {
+	testing.RunTable(c, []string{"first bad", "good", "second bad"}, func(c *gc.C, test string) {
+		c.Check(test, gc.Equals, "good")
+	})
+}
+
+func (*tableSuite) TestRunTableCheckFailures(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&checkFailingTableSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, `(?s).*\n2 of 3 cases failed: 0 \(first bad\), 2 \(second bad\)\n.*`)
+}
+
+type timeoutCase struct {
+	about   string
+	timeout time.Duration
+	stuck   bool
+}
+
+// timingOutTableSuite is run by TestRunTableTimeout rather than being
+// registered with gocheck.
+type timingOutTableSuite struct {
+	release chan struct{}
+	ran     []string
+}
+
+func (s *timingOutTableSuite) TestTable(c *gc.C) {
+	cases := []timeoutCase{
+		{about: "quick"},
+		{about: "stuck with case timeout", timeout: time.Millisecond, stuck: true},
+		{about: "stuck with table timeout", stuck: true},
+		{about: "after"},
+	}
+	testing.RunTableTimeout(c, testing.ShortWait, cases, func(c *gc.C, test timeoutCase) {
+		if test.stuck {
+			<-s.release
+			return
+		}
+		s.ran = append(s.ran, test.about)
+	})
+}
+
+func (*tableSuite) TestRunTableTimeout(c *gc.C) {
+	var output bytes.Buffer
+	suite := &timingOutTableSuite{release: make(chan struct{})}
+	defer close(suite.release)
+	result := gc.Run(suite, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(suite.ran, jc.DeepEquals, []string{"quick", "after"})
+	c.Assert(output.String(), gc.Matches, `(?s).*test 1: stuck with case timeout\n.*test case timed out after 1ms; goroutines:\ngoroutine .*timingOutTableSuite.*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*test 2: stuck with table timeout\n.*test case timed out after 50ms; goroutines:\ngoroutine .*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*\n2 of 4 cases failed: 1 \(stuck with case timeout\), 2 \(stuck with table timeout\)\n.*`)
+}
+
+type namedCase struct {
+	Name string
+}
+
+type describedCase struct {
+	Summary     string
+	Description string
+}
+
+var caseNameTests = []struct {
+	about  string
+	test   interface{}
+	expect string
+}{{
+	about:  "about field",
+	test:   tableCase{about: "first"},
+	expect: "first",
+}, {
+	about:  "exported name field",
+	test:   namedCase{Name: "second"},
+	expect: "second",
+}, {
+	about:  "description preferred to summary",
+	test:   describedCase{Summary: "summary", Description: "description"},
+	expect: "description",
+}, {
+	about:  "pointer to struct",
+	test:   &namedCase{Name: "third"},
+	expect: "third",
+}, {
+	about:  "struct without a name field",
+	test:   struct{ input int }{1},
+	expect: "",
+}, {
+	about:  "non-struct",
+	test:   42,
+	expect: "42",
+}}
+
+func (*tableSuite) TestCaseNames(c *gc.C) {
+	for i, test := range caseNameTests {
+		c.Logf("test %d: %s", i, test.about)
+		var output bytes.Buffer
+		result := gc.Run(&caseNameSuite{test: test.test}, &gc.RunConf{Output: &output, Stream: true})
+		c.Check(result.Succeeded, gc.Equals, 1)
+		c.Check(output.String(), gc.Matches, "(?s).*\ntest 0: "+test.expect+"\n.*")
+	}
+}
+
+// caseNameSuite logs the name given to its test case by RunTable.
+type caseNameSuite struct {
+	test interface{}
+}
+
+func (s *caseNameSuite) TestName(c *gc.C) {
+	testing.RunTable(c, []interface{}{s.test}, func(c *gc.C, test interface{}) {})
+}
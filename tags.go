@@ -0,0 +1,137 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+var tagFilterFlag = flag.String("tag.f", "", "Tag filter selecting which tagged suites, tests and table test cases to run; overrides $TEST_TAGS")
+
+// TagFilter selects tests by their tags, such as "slow" or
+// "requires-docker". It is parsed from a comma-separated list of
+// terms: a term of the form "tag" selects tests with that tag, and one
+// of the form "!tag" excludes tests with that tag. A test is selected
+// if it has none of the excluded tags and, if any tags are selected,
+// at least one of them:
+//
+//	"slow"                   only tests tagged slow
+//	"!slow,!requires-docker" all tests except slow ones and those needing docker
+//	"linux,!slow"            tests tagged linux that are not slow
+//
+// Untagged tests are selected unless the filter selects tags.
+type TagFilter struct {
+	include []string
+	exclude []string
+	source  string
+}
+
+// ParseTagFilter parses a tag filter.
+func ParseTagFilter(s string) (*TagFilter, error) {
+	f := &TagFilter{source: s}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if strings.HasPrefix(term, "!") {
+			tag := strings.TrimSpace(term[1:])
+			if tag == "" {
+				return nil, fmt.Errorf("invalid tag filter %q: empty excluded tag", s)
+			}
+			f.exclude = append(f.exclude, tag)
+		} else {
+			f.include = append(f.include, term)
+		}
+	}
+	return f, nil
+}
+
+// Selects reports whether a test with the given tags is selected by the
+// filter.
+func (f *TagFilter) Selects(tags ...string) bool {
+	for _, tag := range tags {
+		for _, excluded := range f.exclude {
+			if tag == excluded {
+				return false
+			}
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		for _, included := range f.include {
+			if tag == included {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String returns the filter as it was parsed.
+func (f *TagFilter) String() string {
+	return f.source
+}
+
+// CurrentTagFilter returns the tag filter given with the -tag.f flag,
+// or, if that is not given, in the TEST_TAGS environment variable. If
+// neither is given, the filter selects every test. OsEnvSuite keeps
+// TEST_TAGS when it clears the environment, so that the filter applies
+// within its tests.
+func CurrentTagFilter() (*TagFilter, error) {
+	s := *tagFilterFlag
+	if s == "" {
+		s = os.Getenv("TEST_TAGS")
+	}
+	return ParseTagFilter(s)
+}
+
+// SkipUnlessTagsSelected skips the test, or all the tests of the suite
+// when called from SetUpSuite, unless the given tags are selected by
+// the current tag filter.
+func SkipUnlessTagsSelected(c *gc.C, tags ...string) {
+	if reason := tagsSkipReason(c, tags); reason != "" {
+		c.Skip(reason)
+	}
+}
+
+// tagsSkipReason returns why tests with the given tags are not
+// selected by the current tag filter, or "" if they are.
+func tagsSkipReason(c *gc.C, tags []string) string {
+	filter, err := CurrentTagFilter()
+	c.Assert(err, gc.IsNil)
+	if filter.Selects(tags...) {
+		return ""
+	}
+	return fmt.Sprintf("tags [%s] not selected by tag filter %q", strings.Join(tags, " "), filter)
+}
+
+// TaggedSuite may be embedded in a suite to tag all of its tests.
+// Suites that define their own SetUpSuite method must call
+// TaggedSuite.SetUpSuite:
+//
+//	type dockerSuite struct {
+//		testing.TaggedSuite
+//	}
+//
+//	var _ = gc.Suite(&dockerSuite{
+//		TaggedSuite: testing.TaggedSuite{Tags: []string{"requires-docker", "slow"}},
+//	})
+type TaggedSuite struct {
+	// Tags holds the tags of every test in the suite.
+	Tags []string
+}
+
+// SetUpSuite skips all the tests in the suite unless its tags are
+// selected by the current tag filter.
+func (s *TaggedSuite) SetUpSuite(c *gc.C) {
+	SkipUnlessTagsSelected(c, s.Tags...)
+}
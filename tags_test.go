@@ -0,0 +1,188 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"flag"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type tagsSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&tagsSuite{})
+
+func (s *tagsSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.PatchEnvironment("TEST_TAGS", "")
+}
+
+// setTagFilterFlag sets the -tag.f flag until the end of the test.
+func (s *tagsSuite) setTagFilterFlag(c *gc.C, filter string) {
+	err := flag.Set("tag.f", filter)
+	c.Assert(err, gc.IsNil)
+	s.AddCleanup(func(*gc.C) { flag.Set("tag.f", "") })
+}
+
+var tagFilterTests = []struct {
+	filter  string
+	tags    []string
+	selects bool
+}{
+	{"", nil, true},
+	{"", []string{"slow"}, true},
+	{"slow", nil, false},
+	{"slow", []string{"slow"}, true},
+	{"slow", []string{"fast"}, false},
+	{"slow,linux", []string{"linux"}, true},
+	{"!slow", nil, true},
+	{"!slow", []string{"slow"}, false},
+	{"!slow", []string{"linux", "slow"}, false},
+	{"!slow, !requires-docker", []string{"requires-docker"}, false},
+	{"!slow, !requires-docker", []string{"linux"}, true},
+	{"linux,!slow", []string{"linux"}, true},
+	{"linux,!slow", []string{"linux", "slow"}, false},
+	{"linux,!slow", []string{"windows-only"}, false},
+}
+
+func (s *tagsSuite) TestTagFilter(c *gc.C) {
+	for i, test := range tagFilterTests {
+		c.Logf("test %d: %q selecting %v", i, test.filter, test.tags)
+		f, err := testing.ParseTagFilter(test.filter)
+		c.Assert(err, gc.IsNil)
+		c.Check(f.Selects(test.tags...), gc.Equals, test.selects)
+		c.Check(f.String(), gc.Equals, test.filter)
+	}
+}
+
+func (s *tagsSuite) TestParseTagFilterError(c *gc.C) {
+	_, err := testing.ParseTagFilter("slow,!")
+	c.Assert(err, gc.ErrorMatches, `invalid tag filter "slow,!": empty excluded tag`)
+}
+
+func (s *tagsSuite) TestCurrentTagFilter(c *gc.C) {
+	f, err := testing.CurrentTagFilter()
+	c.Assert(err, gc.IsNil)
+	c.Assert(f.String(), gc.Equals, "")
+
+	s.PatchEnvironment("TEST_TAGS", "!slow")
+	f, err = testing.CurrentTagFilter()
+	c.Assert(err, gc.IsNil)
+	c.Assert(f.String(), gc.Equals, "!slow")
+
+	// The flag overrides the environment.
+	s.setTagFilterFlag(c, "slow")
+	f, err = testing.CurrentTagFilter()
+	c.Assert(err, gc.IsNil)
+	c.Assert(f.String(), gc.Equals, "slow")
+}
+
+// taggedTestSuite is run by tagsSuite rather than being registered
+// with gocheck.
+type taggedTestSuite struct {
+	testing.TaggedSuite
+	ran []string
+}
+
+func (s *taggedTestSuite) TestSlow(c *gc.C) {
+	testing.SkipUnlessTagsSelected(c, "slow")
+	s.ran = append(s.ran, "TestSlow")
+}
+
+func (s *taggedTestSuite) TestUntagged(c *gc.C) {
+	s.ran = append(s.ran, "TestUntagged")
+}
+
+func (s *tagsSuite) TestSkipUnlessTagsSelected(c *gc.C) {
+	s.PatchEnvironment("TEST_TAGS", "!slow")
+	var output bytes.Buffer
+	suite := &taggedTestSuite{}
+	result := gc.Run(suite, &gc.RunConf{Output: &output, Verbose: true})
+	c.Assert(result.Succeeded, gc.Equals, 1)
+	c.Assert(result.Skipped, gc.Equals, 1)
+	c.Assert(suite.ran, jc.DeepEquals, []string{"TestUntagged"})
+	c.Assert(output.String(), gc.Matches, `(?s).*SKIP: .*taggedTestSuite.TestSlow \(tags \[slow\] not selected by tag filter "!slow"\).*`)
+}
+
+func (s *tagsSuite) TestTaggedSuite(c *gc.C) {
+	s.PatchEnvironment("TEST_TAGS", "!requires-docker")
+	var output bytes.Buffer
+	suite := &taggedTestSuite{
+		TaggedSuite: testing.TaggedSuite{Tags: []string{"requires-docker"}},
+	}
+	result := gc.Run(suite, &gc.RunConf{Output: &output})
+	c.Assert(result.Passed(), gc.Equals, true)
+	c.Assert(result.Skipped, gc.Equals, 2)
+	c.Assert(suite.ran, gc.HasLen, 0)
+
+	s.PatchEnvironment("TEST_TAGS", "requires-docker")
+	suite.ran = nil
+	result = gc.Run(suite, &gc.RunConf{Output: &output})
+	c.Assert(result.Succeeded, gc.Equals, 1)
+	c.Assert(suite.ran, jc.DeepEquals, []string{"TestUntagged"})
+}
+
+type taggedCase struct {
+	about string
+	tags  []string
+}
+
+func (s *tagsSuite) TestRunTableTags(c *gc.C) {
+	s.setTagFilterFlag(c, "!slow")
+	cases := []taggedCase{
+		{about: "quick"},
+		{about: "slow", tags: []string{"slow"}},
+		{about: "linux", tags: []string{"linux"}},
+	}
+	var ran []string
+	testing.RunTable(c, cases, func(c *gc.C, test taggedCase) {
+		ran = append(ran, test.about)
+	})
+	c.Assert(ran, jc.DeepEquals, []string{"quick", "linux"})
+	c.Assert(c.GetTestLog(), gc.Equals, ""+
+		"test 0: quick\n"+
+		"test 1: slow: skipped: tags [slow] not selected by tag filter \"!slow\"\n"+
+		"test 2: linux\n"+
+		"1 of 3 cases skipped by tag filter \"!slow\"\n",
+	)
+}
+
+// isolatedTagsSuite is run by TestTagsUnderIsolationSuite rather than
+// being registered with gocheck. OsEnvSuite clears the environment
+// before its tests run.
+type isolatedTagsSuite struct {
+	testing.IsolationSuite
+	ran []string
+}
+
+func (s *isolatedTagsSuite) TestTagged(c *gc.C) {
+	testing.SkipUnlessTagsSelected(c, "slow")
+	s.ran = append(s.ran, "TestTagged")
+}
+
+func (s *isolatedTagsSuite) TestTable(c *gc.C) {
+	cases := []taggedCase{
+		{about: "quick"},
+		{about: "slow", tags: []string{"slow"}},
+	}
+	testing.RunTable(c, cases, func(c *gc.C, test taggedCase) {
+		s.ran = append(s.ran, test.about)
+	})
+}
+
+func (s *tagsSuite) TestTagsUnderIsolationSuite(c *gc.C) {
+	s.PatchEnvironment("TEST_TAGS", "!slow")
+	var output bytes.Buffer
+	suite := &isolatedTagsSuite{}
+	result := gc.Run(suite, &gc.RunConf{Output: &output})
+	c.Assert(result.Passed(), jc.IsTrue, gc.Commentf("%s", output.String()))
+	c.Assert(result.Skipped, gc.Equals, 1)
+	c.Assert(suite.ran, jc.DeepEquals, []string{"quick"})
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// TempBudgetSuite fails any test that writes more than Budget bytes to
+// its temporary directories, reporting the largest files it wrote. This
+// catches tests that silently fill the disk on CI.
+//
+// Directories made with c.MkDir during the test are measured
+// automatically; others, such as those made with os.MkdirTemp, can be
+// added with Track. Usage is measured by apparent file size when the
+// test finishes, so files removed by the test itself are not counted.
+//
+// Since it runs in TearDownTest, TempBudgetSuite should be torn down
+// before any suite whose cleanups remove the files to be measured.
+type TempBudgetSuite struct {
+	// Budget holds the number of bytes that each test may write.
+	// If it is zero, usage is not checked.
+	Budget int64
+
+	mkdirRoot string
+	existing  map[string]bool
+	tracked   []string
+}
+
+// tempBudgetReportFiles holds how many of the largest files are
+// reported when a test exceeds its budget.
+const tempBudgetReportFiles = 5
+
+func (s *TempBudgetSuite) SetUpSuite(c *gc.C) {}
+
+func (s *TempBudgetSuite) TearDownSuite(c *gc.C) {}
+
+func (s *TempBudgetSuite) SetUpTest(c *gc.C) {
+	s.tracked = nil
+	s.existing = nil
+	s.mkdirRoot = ""
+	if s.Budget == 0 {
+		return
+	}
+	// All directories made by c.MkDir share a parent, which also
+	// holds those made by earlier tests.
+	s.mkdirRoot = filepath.Dir(c.MkDir())
+	entries, err := os.ReadDir(s.mkdirRoot)
+	c.Assert(err, gc.IsNil)
+	s.existing = make(map[string]bool)
+	for _, entry := range entries {
+		s.existing[entry.Name()] = true
+	}
+}
+
+func (s *TempBudgetSuite) TearDownTest(c *gc.C) {
+	if s.Budget == 0 || s.mkdirRoot == "" {
+		return
+	}
+	dirs := append([]string(nil), s.tracked...)
+	entries, err := os.ReadDir(s.mkdirRoot)
+	c.Assert(err, gc.IsNil)
+	for _, entry := range entries {
+		if !s.existing[entry.Name()] {
+			dirs = append(dirs, filepath.Join(s.mkdirRoot, entry.Name()))
+		}
+	}
+	s.mkdirRoot = ""
+	s.tracked = nil
+
+	files, err := tempFileSizes(dirs)
+	c.Assert(err, gc.IsNil)
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	if total <= s.Budget {
+		return
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].size > files[j].size
+	})
+	if len(files) > tempBudgetReportFiles {
+		files = files[:tempBudgetReportFiles]
+	}
+	var largest strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&largest, "\n  %s (%s)", f.path, formatBytes(f.size))
+	}
+	c.Errorf("test wrote %s to temporary directories, over its budget of %s; largest files:%s",
+		formatBytes(total), formatBytes(s.Budget), largest.String())
+}
+
+// Track adds dir to the directories measured when the current test
+// finishes.
+func (s *TempBudgetSuite) Track(dir string) {
+	s.tracked = append(s.tracked, dir)
+}
+
+// tempFile holds the size of a file measured by TempBudgetSuite.
+type tempFile struct {
+	path string
+	size int64
+}
+
+// tempFileSizes returns the size of every regular file under dirs,
+// counting each file only once.
+func tempFileSizes(dirs []string) ([]tempFile, error) {
+	var files []tempFile
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() || seen[path] {
+				return nil
+			}
+			seen[path] = true
+			info, err := d.Info()
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			files = append(files, tempFile{path, info.Size()})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// formatBytes formats n as a number of bytes with a binary unit.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+type tempBudgetSuite struct {
+	suite TempBudgetSuite
+}
+
+var _ = gc.Suite(&tempBudgetSuite{})
+
+func (s *tempBudgetSuite) SetUpTest(c *gc.C) {
+	// Files written before the test starts are not counted.
+	writeSizedFile(c, filepath.Join(c.MkDir(), "earlier"), 2048)
+	s.suite = TempBudgetSuite{Budget: 1024}
+	s.suite.SetUpSuite(c)
+	s.suite.SetUpTest(c)
+}
+
+func (s *tempBudgetSuite) TearDownTest(c *gc.C) {
+	s.suite.TearDownSuite(c)
+}
+
+func writeSizedFile(c *gc.C, path string, size int) {
+	err := ioutil.WriteFile(path, []byte(strings.Repeat("x", size)), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *tempBudgetSuite) TestWithinBudget(c *gc.C) {
+	dir := c.MkDir()
+	writeSizedFile(c, filepath.Join(dir, "a"), 512)
+	writeSizedFile(c, filepath.Join(dir, "b"), 512)
+	s.suite.TearDownTest(c)
+}
+
+func (s *tempBudgetSuite) TestOverBudget(c *gc.C) {
+	writeSizedFile(c, filepath.Join(c.MkDir(), "a"), 1000)
+	writeSizedFile(c, filepath.Join(c.MkDir(), "b"), 1000)
+	c.ExpectFailure("test wrote too much")
+	s.suite.TearDownTest(c)
+}
+
+func (s *tempBudgetSuite) TestRemovedFilesNotCounted(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "a")
+	writeSizedFile(c, path, 4096)
+	err := os.Remove(path)
+	c.Assert(err, jc.ErrorIsNil)
+	s.suite.TearDownTest(c)
+}
+
+func (s *tempBudgetSuite) TestTrack(c *gc.C) {
+	dir, err := ioutil.TempDir("", "tempbudget")
+	c.Assert(err, jc.ErrorIsNil)
+	defer os.RemoveAll(dir)
+	s.suite.Track(dir)
+	writeSizedFile(c, filepath.Join(dir, "a"), 4096)
+	c.ExpectFailure("tracked directory over budget")
+	s.suite.TearDownTest(c)
+}
+
+func (s *tempBudgetSuite) TestNoBudget(c *gc.C) {
+	suite := TempBudgetSuite{}
+	suite.SetUpTest(c)
+	writeSizedFile(c, filepath.Join(c.MkDir(), "a"), 4096)
+	suite.TearDownTest(c)
+}
+
+type formatBytesSuite struct{}
+
+var _ = gc.Suite(&formatBytesSuite{})
+
+func (*formatBytesSuite) TestFormatBytes(c *gc.C) {
+	for n, expected := range map[int64]string{
+		0:         "0 B",
+		1023:      "1023 B",
+		1024:      "1.0 KiB",
+		1536:      "1.5 KiB",
+		5 << 20:   "5.0 MiB",
+		3 << 30:   "3.0 GiB",
+		1<<40 + 1: "1.0 TiB",
+	} {
+		c.Check(formatBytes(n), gc.Equals, expected)
+	}
+}
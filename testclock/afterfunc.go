@@ -0,0 +1,285 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testclock
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// AfterFuncTrap is a clock.Clock that captures the functions passed to
+// AfterFunc rather than scheduling them, so that a test can check what
+// was scheduled and run the functions itself, synchronously and in the
+// order it chooses, instead of advancing time and depending on how
+// their goroutines interleave. Its other methods are those of the
+// underlying Clock:
+//
+//	trap := testclock.NewAfterFuncTrap(testclock.NewClock(start))
+//	w := NewWorker(trap)
+//	fns := trap.WaitPending(c, 2, testing.LongWait)
+//	c.Assert(fns[0].Delay(), gc.Equals, time.Second)
+//	fns[1].Run(c)
+//	fns[0].Run(c)
+type AfterFuncTrap struct {
+	*Clock
+
+	mu    sync.Mutex
+	funcs []*TrappedFunc
+	// changed is closed, and replaced, whenever a function is
+	// registered, reset or stopped.
+	changed chan struct{}
+}
+
+var _ clock.Clock = (*AfterFuncTrap)(nil)
+
+// NewAfterFuncTrap returns a trap for the AfterFunc calls made on it,
+// which otherwise behaves as clk.
+func NewAfterFuncTrap(clk *Clock) *AfterFuncTrap {
+	return &AfterFuncTrap{
+		Clock:   clk,
+		changed: make(chan struct{}),
+	}
+}
+
+// TrappedFunc holds a function passed to AfterFuncTrap.AfterFunc.
+type TrappedFunc struct {
+	trap *AfterFuncTrap
+	f    func()
+
+	// ID identifies the function. Functions are numbered from 1 in
+	// the order they were registered.
+	ID int
+
+	// The following fields are guarded by the trap's mutex.
+	delay    time.Duration
+	deadline time.Time
+	pending  bool
+	stopped  bool
+	runs     int
+}
+
+// AfterFunc implements clock.Clock.AfterFunc. It records f without
+// scheduling it; f is only called when the test runs it.
+func (trap *AfterFuncTrap) AfterFunc(d time.Duration, f func()) clock.Timer {
+	now := trap.Monotonic()
+	trap.mu.Lock()
+	defer trap.mu.Unlock()
+	fn := &TrappedFunc{
+		trap:     trap,
+		f:        f,
+		ID:       len(trap.funcs) + 1,
+		delay:    d,
+		deadline: now.Add(d),
+		pending:  true,
+	}
+	trap.funcs = append(trap.funcs, fn)
+	trap.notifyChanged()
+	return trappedTimer{fn}
+}
+
+// Funcs returns every function registered with the trap, in the order
+// they were registered.
+func (trap *AfterFuncTrap) Funcs() []*TrappedFunc {
+	trap.mu.Lock()
+	defer trap.mu.Unlock()
+	return append([]*TrappedFunc(nil), trap.funcs...)
+}
+
+// Pending returns the functions that are neither stopped nor run, in
+// the order their deadlines fall.
+func (trap *AfterFuncTrap) Pending() []*TrappedFunc {
+	trap.mu.Lock()
+	defer trap.mu.Unlock()
+	return trap.pending()
+}
+
+func (trap *AfterFuncTrap) pending() []*TrappedFunc {
+	var pending []*TrappedFunc
+	for _, fn := range trap.funcs {
+		if fn.pending {
+			pending = append(pending, fn)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].deadline.Before(pending[j].deadline)
+	})
+	return pending
+}
+
+// WaitPending waits for exactly n functions to be pending, so that a
+// test can synchronise with code registering them in another
+// goroutine, and returns them in the order their deadlines fall. If n
+// functions are not pending within the given timeout, the test fails.
+func (trap *AfterFuncTrap) WaitPending(c *gc.C, n int, timeout time.Duration) []*TrappedFunc {
+	deadline := time.After(timeout)
+	for {
+		trap.mu.Lock()
+		pending := trap.pending()
+		changed := trap.changed
+		trap.mu.Unlock()
+		if len(pending) == n {
+			return pending
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			c.Fatalf("got %d pending AfterFunc calls after waiting %s: wanted %d\n%s",
+				len(pending), timeout, n, trap.Dump())
+		}
+	}
+}
+
+// RunPending runs the pending functions in the order their deadlines
+// fall, as advancing the clock past them would, but synchronously. It
+// returns the number of functions run. Functions registered by the
+// functions run are not run.
+func (trap *AfterFuncTrap) RunPending(c *gc.C) int {
+	pending := trap.Pending()
+	for _, fn := range pending {
+		fn.Run(c)
+	}
+	return len(pending)
+}
+
+// Dump returns a readable description of the functions registered with
+// the trap, for use in failure messages.
+func (trap *AfterFuncTrap) Dump() string {
+	trap.mu.Lock()
+	defer trap.mu.Unlock()
+	if len(trap.funcs) == 0 {
+		return "AfterFunc calls:\n  (none)\n"
+	}
+	var buf strings.Builder
+	buf.WriteString("AfterFunc calls:\n")
+	for _, fn := range trap.funcs {
+		fmt.Fprintf(&buf, "  %s\n", fn.describe())
+	}
+	return buf.String()
+}
+
+// notifyChanged wakes up anything waiting for the registered functions
+// to change. It must be called with the mutex held.
+func (trap *AfterFuncTrap) notifyChanged() {
+	close(trap.changed)
+	trap.changed = make(chan struct{})
+}
+
+// Run calls the function, in the calling goroutine. The test fails if
+// the function has been stopped or already run since it was last
+// reset.
+func (fn *TrappedFunc) Run(c *gc.C) {
+	fn.trap.mu.Lock()
+	if !fn.pending {
+		desc := fn.describe()
+		fn.trap.mu.Unlock()
+		c.Fatalf("cannot run AfterFunc %s", desc)
+	}
+	fn.pending = false
+	fn.runs++
+	fn.trap.notifyChanged()
+	fn.trap.mu.Unlock()
+	fn.f()
+}
+
+// Delay returns the duration the function was registered with, or last
+// reset to.
+func (fn *TrappedFunc) Delay() time.Duration {
+	fn.trap.mu.Lock()
+	defer fn.trap.mu.Unlock()
+	return fn.delay
+}
+
+// Deadline returns the monotonic time of the underlying clock at which
+// the function would have been called had it been scheduled.
+func (fn *TrappedFunc) Deadline() time.Time {
+	fn.trap.mu.Lock()
+	defer fn.trap.mu.Unlock()
+	return fn.deadline
+}
+
+// Stopped reports whether the function was stopped, since it was last
+// reset, before it was run.
+func (fn *TrappedFunc) Stopped() bool {
+	fn.trap.mu.Lock()
+	defer fn.trap.mu.Unlock()
+	return fn.stopped
+}
+
+// Runs returns the number of times the function has been run. It may
+// be run once each time it is registered or reset.
+func (fn *TrappedFunc) Runs() int {
+	fn.trap.mu.Lock()
+	defer fn.trap.mu.Unlock()
+	return fn.runs
+}
+
+// String returns a description of the function and its state, such as
+// "#1 after 1s (pending)".
+func (fn *TrappedFunc) String() string {
+	fn.trap.mu.Lock()
+	defer fn.trap.mu.Unlock()
+	return fn.describe()
+}
+
+// describe implements String. It must be called with the trap's mutex
+// held.
+func (fn *TrappedFunc) describe() string {
+	state := "run"
+	switch {
+	case fn.pending:
+		state = "pending"
+	case fn.stopped:
+		state = "stopped"
+	}
+	return fmt.Sprintf("#%d after %v (%s)", fn.ID, fn.delay, state)
+}
+
+// trappedTimer implements clock.Timer for a TrappedFunc.
+type trappedTimer struct {
+	fn *TrappedFunc
+}
+
+// Chan is part of the clock.Timer interface. As for the timers
+// returned by time.AfterFunc, it returns nil.
+func (t trappedTimer) Chan() <-chan time.Time {
+	return nil
+}
+
+// Reset is part of the clock.Timer interface. It makes the function
+// pending again, with the new delay, and reports whether it was
+// pending.
+func (t trappedTimer) Reset(d time.Duration) bool {
+	trap := t.fn.trap
+	now := trap.Monotonic()
+	trap.mu.Lock()
+	defer trap.mu.Unlock()
+	wasPending := t.fn.pending
+	t.fn.delay = d
+	t.fn.deadline = now.Add(d)
+	t.fn.pending = true
+	t.fn.stopped = false
+	trap.notifyChanged()
+	return wasPending
+}
+
+// Stop is part of the clock.Timer interface. It stops the function
+// being run, and reports whether it was pending.
+func (t trappedTimer) Stop() bool {
+	trap := t.fn.trap
+	trap.mu.Lock()
+	defer trap.mu.Unlock()
+	wasPending := t.fn.pending
+	if wasPending {
+		t.fn.pending = false
+		t.fn.stopped = true
+		trap.notifyChanged()
+	}
+	return wasPending
+}
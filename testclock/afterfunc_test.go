@@ -0,0 +1,135 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testclock_test
+
+import (
+	"bytes"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+)
+
+type afterFuncTrapSuite struct {
+	testing.IsolationSuite
+	start time.Time
+	trap  *testclock.AfterFuncTrap
+}
+
+var _ = gc.Suite(&afterFuncTrapSuite{})
+
+func (s *afterFuncTrapSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.start = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.trap = testclock.NewAfterFuncTrap(testclock.NewClock(s.start))
+}
+
+func (s *afterFuncTrapSuite) TestRunInChosenOrder(c *gc.C) {
+	var called []string
+	s.trap.AfterFunc(time.Minute, func() { called = append(called, "a") })
+	s.trap.AfterFunc(time.Second, func() { called = append(called, "b") })
+	c.Assert(s.trap.Clock, testclock.HasWaiters, 0)
+
+	funcs := s.trap.Funcs()
+	c.Assert(funcs, gc.HasLen, 2)
+	c.Assert(funcs[0].ID, gc.Equals, 1)
+	c.Assert(funcs[0].Delay(), gc.Equals, time.Minute)
+	c.Assert(funcs[0].Deadline(), gc.Equals, s.start.Add(time.Minute))
+	c.Assert(s.trap.Pending(), jc.DeepEquals, []*testclock.TrappedFunc{funcs[1], funcs[0]})
+
+	funcs[0].Run(c)
+	funcs[1].Run(c)
+	c.Assert(called, jc.DeepEquals, []string{"a", "b"})
+	c.Assert(funcs[0].Runs(), gc.Equals, 1)
+	c.Assert(s.trap.Pending(), gc.HasLen, 0)
+}
+
+func (s *afterFuncTrapSuite) TestRunPending(c *gc.C) {
+	var called []string
+	s.trap.AfterFunc(time.Minute, func() { called = append(called, "a") })
+	s.trap.AfterFunc(time.Second, func() {
+		called = append(called, "b")
+		s.trap.AfterFunc(time.Second, func() { called = append(called, "c") })
+	})
+	c.Assert(s.trap.RunPending(c), gc.Equals, 2)
+	c.Assert(called, jc.DeepEquals, []string{"b", "a"})
+	c.Assert(s.trap.RunPending(c), gc.Equals, 1)
+	c.Assert(called, jc.DeepEquals, []string{"b", "a", "c"})
+}
+
+func (s *afterFuncTrapSuite) TestStopAndReset(c *gc.C) {
+	called := 0
+	t := s.trap.AfterFunc(time.Second, func() { called++ })
+	c.Assert(t.Chan(), gc.IsNil)
+	fn := s.trap.Funcs()[0]
+	c.Assert(t.Stop(), jc.IsTrue)
+	c.Assert(t.Stop(), jc.IsFalse)
+	c.Assert(fn.Stopped(), jc.IsTrue)
+	c.Assert(s.trap.Pending(), gc.HasLen, 0)
+
+	s.trap.Advance(time.Second)
+	c.Assert(t.Reset(time.Minute), jc.IsFalse)
+	c.Assert(fn.Stopped(), jc.IsFalse)
+	c.Assert(fn.Delay(), gc.Equals, time.Minute)
+	c.Assert(fn.Deadline(), gc.Equals, s.start.Add(time.Minute+time.Second))
+	c.Assert(t.Reset(time.Second), jc.IsTrue)
+	fn.Run(c)
+	c.Assert(t.Stop(), jc.IsFalse)
+	c.Assert(fn.Stopped(), jc.IsFalse)
+	c.Assert(called, gc.Equals, 1)
+}
+
+func (s *afterFuncTrapSuite) TestWaitPending(c *gc.C) {
+	go func() {
+		s.trap.AfterFunc(time.Second, func() {})
+		s.trap.AfterFunc(time.Millisecond, func() {})
+	}()
+	funcs := s.trap.WaitPending(c, 2, testing.LongWait)
+	c.Assert(funcs[0].Delay(), gc.Equals, time.Millisecond)
+	c.Assert(funcs[1].Delay(), gc.Equals, time.Second)
+}
+
+func (s *afterFuncTrapSuite) TestDump(c *gc.C) {
+	c.Assert(s.trap.Dump(), gc.Equals, "AfterFunc calls:\n  (none)\n")
+	s.trap.AfterFunc(time.Second, func() {}).Stop()
+	s.trap.AfterFunc(time.Minute, func() {})
+	s.trap.AfterFunc(time.Hour, func() {})
+	s.trap.Funcs()[2].Run(c)
+	c.Assert(s.trap.Funcs()[1].String(), gc.Equals, "#2 after 1m0s (pending)")
+	c.Assert(s.trap.Dump(), gc.Equals, `AfterFunc calls:
+  #1 after 1s (stopped)
+  #2 after 1m0s (pending)
+  #3 after 1h0m0s (run)
+`)
+}
+
+// trapFailureSuite is run by the tests of afterFuncTrapSuite rather
+// than being registered with gocheck.
+type trapFailureSuite struct{}
+
+func (*trapFailureSuite) TestRunStopped(c *gc.C) {
+	trap := testclock.NewAfterFuncTrap(testclock.NewClock(time.Time{}))
+	trap.AfterFunc(time.Second, func() {}).Stop()
+	trap.Funcs()[0].Run(c)
+}
+
+func (*trapFailureSuite) TestWaitPendingTimeout(c *gc.C) {
+	trap := testclock.NewAfterFuncTrap(testclock.NewClock(time.Time{}))
+	trap.AfterFunc(time.Second, func() {})
+	trap.WaitPending(c, 2, testing.ShortWait)
+}
+
+func (s *afterFuncTrapSuite) TestFailures(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&trapFailureSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 2)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`\.\.\. Error: cannot run AfterFunc #1 after 1s \(stopped\)\n.*`+
+		`\.\.\. Error: got 1 pending AfterFunc calls after waiting 50ms: wanted 2\n`+
+		`AfterFunc calls:\n`+
+		`  #1 after 1s \(pending\)\n.*`)
+}
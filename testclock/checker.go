@@ -0,0 +1,144 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testclock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+)
+
+type hasWaitersChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasWaiters checks that the obtained *Clock has the expected number
+// of timers, tickers and AfterFunc calls waiting to fire. For example:
+//
+//	c.Assert(clock, testclock.HasWaiters, 2)
+var HasWaiters gc.Checker = &hasWaitersChecker{
+	&gc.CheckerInfo{Name: "HasWaiters", Params: []string{"obtained", "n"}},
+}
+
+func (checker *hasWaitersChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	clock, ok := params[0].(*Clock)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be *testclock.Clock, got %T", params[0])
+	}
+	n, ok := params[1].(int)
+	if !ok {
+		return false, fmt.Sprintf("n must be an int, got %T", params[1])
+	}
+	alarms := clock.PendingAlarms()
+	if len(alarms) != n {
+		return false, fmt.Sprintf("clock has %d waiters; pending alarms: %v", len(alarms), alarms)
+	}
+	return true, ""
+}
+
+type hasPendingAlarmsChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasPendingAlarms checks that the alarms pending on the obtained
+// *Clock, expressed as the time remaining until each one fires, match
+// the expected []time.Duration in firing order. On failure, a diff of
+// the expected and pending alarms is reported. For example:
+//
+//	c.Assert(clock, testclock.HasPendingAlarms, []time.Duration{time.Second, time.Minute})
+var HasPendingAlarms gc.Checker = &hasPendingAlarmsChecker{
+	&gc.CheckerInfo{Name: "HasPendingAlarms", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *hasPendingAlarmsChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	clock, ok := params[0].(*Clock)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be *testclock.Clock, got %T", params[0])
+	}
+	expected, ok := params[1].([]time.Duration)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be []time.Duration, got %T", params[1])
+	}
+	return jc.ListEquals.Check([]interface{}{clock.PendingAlarms(), expected}, names)
+}
+
+type firedInOrderChecker struct {
+	*gc.CheckerInfo
+}
+
+// FiredInOrder checks that the timers of the obtained *Clock have
+// fired in the expected order, given as a []int of timer ids as
+// reported by Clock.Timers. On failure, the clock's schedule is
+// reported. For example:
+//
+//	c.Assert(clock, testclock.FiredInOrder, []int{2, 1, 2})
+var FiredInOrder gc.Checker = &firedInOrderChecker{
+	&gc.CheckerInfo{Name: "FiredInOrder", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *firedInOrderChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	clock, ok := params[0].(*Clock)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be *testclock.Clock, got %T", params[0])
+	}
+	expected, ok := params[1].([]int)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be []int, got %T", params[1])
+	}
+	fired := clock.FiringOrder()
+	if ok, _ := jc.DeepEqual(fired, expected); ok {
+		return true, ""
+	}
+	return false, fmt.Sprintf("timers fired in order %v, expected %v\n%s", fired, expected, clock.Schedule())
+}
+
+type hasTimerEventsChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasTimerEvents checks that the timer of the obtained *Clock with the
+// given id has the expected history, a []TimerEvent. On failure, the
+// clock's schedule is reported. For example:
+//
+//	c.Assert(clock, testclock.HasTimerEvents, 1, []testclock.TimerEvent{
+//		{Op: testclock.TimerCreated, Duration: time.Minute},
+//		{Op: testclock.TimerReset, At: time.Second, Duration: time.Minute},
+//		{Op: testclock.TimerStopped, At: 2 * time.Second},
+//	})
+var HasTimerEvents gc.Checker = &hasTimerEventsChecker{
+	&gc.CheckerInfo{Name: "HasTimerEvents", Params: []string{"obtained", "id", "expected"}},
+}
+
+func (checker *hasTimerEventsChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	clock, ok := params[0].(*Clock)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be *testclock.Clock, got %T", params[0])
+	}
+	id, ok := params[1].(int)
+	if !ok {
+		return false, fmt.Sprintf("id must be an int, got %T", params[1])
+	}
+	expected, ok := params[2].([]TimerEvent)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be []testclock.TimerEvent, got %T", params[2])
+	}
+	timers := clock.Timers()
+	if id < 1 || id > len(timers) {
+		return false, fmt.Sprintf("no timer #%d\n%s", id, clock.Schedule())
+	}
+	events := timers[id-1].Events
+	if ok, _ := jc.DeepEqual(events, expected); ok {
+		return true, ""
+	}
+	want := make([]string, len(expected))
+	for i, e := range expected {
+		want[i] = e.String()
+	}
+	return false, fmt.Sprintf("timer #%d history does not match; expected: %s\n%s",
+		id, strings.Join(want, ", "), clock.Schedule())
+}
@@ -0,0 +1,358 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package testclock provides a fake clock whose time only moves when
+// the test says so, allowing time-dependent code to be tested
+// deterministically.
+package testclock
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// Ticker is the interface implemented by the tickers returned from
+// Clock.NewTicker. It follows time.Ticker's methods but provides
+// easier mocking.
+type Ticker interface {
+	// Chan returns the channel on which the ticks are delivered.
+	Chan() <-chan time.Time
+
+	// Reset stops the ticker and resets its period to d. The next
+	// tick will arrive after d has elapsed.
+	Reset(d time.Duration)
+
+	// Stop turns off the ticker. No more ticks will be sent.
+	Stop()
+}
+
+// Clock implements clock.Clock for testing purposes. Time only moves
+// forward when Advance is called, at which point every timer, ticker
+// and AfterFunc call that has become due is triggered, in deadline
+// order.
+type Clock struct {
+	mu sync.Mutex
+	// now holds the current monotonic time of the clock, by which
+	// timers are scheduled.
+	now time.Time
+	// skew holds the difference between the wall clock time returned
+	// by Now and the monotonic time.
+	skew time.Duration
+	// drift holds the rate at which the wall clock gains on the
+	// monotonic time as the clock advances.
+	drift float64
+	// waiting holds the timers waiting to fire, sorted by deadline.
+	waiting []*timer
+	// notifyAlarms receives a value every time a timer is added or
+	// reset.
+	notifyAlarms chan struct{}
+	// changed is closed, and replaced, whenever the set of waiting
+	// timers changes.
+	changed chan struct{}
+	// start holds the time the clock was created with, from which the
+	// times of timer events are measured.
+	start time.Time
+	// timers holds every timer created on the clock, in creation order.
+	timers []*timer
+	// fired holds the ids of the timers in the order they fired.
+	fired []int
+}
+
+var _ clock.Clock = (*Clock)(nil)
+
+// NewClock returns a new clock set to the supplied time. If the code
+// under test sets more than 10000 timers, the Alarms channel must be
+// read to keep its buffer from filling up.
+func NewClock(now time.Time) *Clock {
+	return &Clock{
+		now:          now,
+		start:        now,
+		notifyAlarms: make(chan struct{}, 10000),
+		changed:      make(chan struct{}),
+	}
+}
+
+// Now is part of the clock.Clock interface. It returns the wall clock
+// time, which differs from the monotonic time by which timers are
+// scheduled once the wall clock has been stepped or has drifted.
+func (clock *Clock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.wallNow()
+}
+
+// After is part of the clock.Clock interface.
+func (clock *Clock) After(d time.Duration) <-chan time.Time {
+	return clock.newTimer(KindAfter, d).Chan()
+}
+
+// NewTimer is part of the clock.Clock interface.
+func (clock *Clock) NewTimer(d time.Duration) clock.Timer {
+	return clock.newTimer(KindTimer, d)
+}
+
+func (clock *Clock) newTimer(kind TimerKind, d time.Duration) *timer {
+	c := make(chan time.Time, 1)
+	return clock.addTimer(kind, d, 0, c, func(now time.Time) {
+		send(c, now)
+	})
+}
+
+// AfterFunc is part of the clock.Clock interface.
+func (clock *Clock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return clock.addTimer(KindAfterFunc, d, 0, nil, func(time.Time) {
+		go f()
+	})
+}
+
+// NewTicker returns a Ticker that sends the current time on its
+// channel every d, as measured by the clock. As with time.Ticker, ticks
+// are dropped if the receiver falls behind. It panics if d is not
+// positive.
+func (clock *Clock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	c := make(chan time.Time, 1)
+	return ticker{clock.addTimer(KindTicker, d, d, c, func(now time.Time) {
+		send(c, now)
+	})}
+}
+
+// Advance advances the clock by the supplied duration, and triggers
+// every timer whose deadline is no longer in the future. The result of
+// Now advances by the same duration, adjusted by any drift set with
+// SetDrift.
+func (clock *Clock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.advance(d)
+	clock.triggerAll()
+}
+
+// WaitAdvance waits for exactly n timers, tickers and AfterFunc calls
+// to be waiting on the clock and then advances it by d. This allows a
+// test to synchronise with code running in another goroutine without
+// sleeping. If n waiters do not appear within the given timeout, the
+// test fails, reporting the alarms that were pending.
+func (clock *Clock) WaitAdvance(c *gc.C, d, timeout time.Duration, n int) {
+	deadline := time.After(timeout)
+	for {
+		clock.mu.Lock()
+		got := len(clock.waiting)
+		changed := clock.changed
+		if got == n {
+			clock.advance(d)
+			clock.triggerAll()
+			clock.mu.Unlock()
+			return
+		}
+		clock.mu.Unlock()
+		select {
+		case <-changed:
+		case <-deadline:
+			c.Fatalf("got %d waiters after waiting %s: wanted %d; pending alarms: %v\n%s",
+				got, timeout, n, clock.PendingAlarms(), clock.Schedule())
+		}
+	}
+}
+
+// WaiterCount returns the number of timers, tickers and AfterFunc
+// calls currently waiting to fire.
+func (clock *Clock) WaiterCount() int {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return len(clock.waiting)
+}
+
+// PendingAlarms returns, for each timer, ticker and AfterFunc call
+// currently waiting to fire, the time remaining until it fires, in the
+// order they will fire.
+func (clock *Clock) PendingAlarms() []time.Duration {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	alarms := make([]time.Duration, len(clock.waiting))
+	for i, t := range clock.waiting {
+		alarms[i] = t.deadline.Sub(clock.now)
+	}
+	return alarms
+}
+
+// Alarms returns a channel on which a value is sent for every call to
+// After, AfterFunc, NewTimer and NewTicker, and for every Reset of a
+// timer or ticker, made on this clock. Reading from it allows a test to
+// wait until code running in another goroutine has started waiting.
+func (clock *Clock) Alarms() <-chan struct{} {
+	return clock.notifyAlarms
+}
+
+// timer implements clock.Timer. It also holds the state of tickers.
+type timer struct {
+	clock *Clock
+	// id identifies the timer in the clock's schedule.
+	id   int
+	kind TimerKind
+	// duration holds the duration the timer was created with.
+	duration time.Duration
+	// events holds the history of the timer.
+	events   []TimerEvent
+	deadline time.Time
+	// period holds the interval between ticks, or zero if the timer
+	// fires only once.
+	period time.Duration
+	c      chan time.Time
+	// trigger is called when the timer fires. It is called with the
+	// clock mutex held and must not block.
+	trigger func(now time.Time)
+}
+
+// Chan is part of the clock.Timer and Ticker interfaces.
+func (t *timer) Chan() <-chan time.Time {
+	return t.c
+}
+
+// Reset is part of the clock.Timer interface.
+func (t *timer) Reset(d time.Duration) bool {
+	return t.clock.reset(t, d)
+}
+
+// Stop is part of the clock.Timer interface.
+func (t *timer) Stop() bool {
+	return t.clock.stop(t)
+}
+
+// ticker implements Ticker.
+type ticker struct {
+	*timer
+}
+
+// Reset is part of the Ticker interface.
+func (t ticker) Reset(d time.Duration) {
+	t.clock.reset(t.timer, d)
+}
+
+// Stop is part of the Ticker interface.
+func (t ticker) Stop() {
+	t.clock.stop(t.timer)
+}
+
+func (clock *Clock) addTimer(kind TimerKind, d, period time.Duration, c chan time.Time, trigger func(time.Time)) *timer {
+	defer clock.notifyAlarm()
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	t := &timer{
+		clock:    clock,
+		id:       len(clock.timers) + 1,
+		kind:     kind,
+		duration: d,
+		deadline: clock.now.Add(d),
+		period:   period,
+		c:        c,
+		trigger:  trigger,
+	}
+	clock.timers = append(clock.timers, t)
+	clock.record(t, TimerCreated, d)
+	clock.insert(t)
+	clock.triggerAll()
+	return t
+}
+
+// reset is the underlying implementation of Reset for timers and
+// tickers backed by this clock.
+func (clock *Clock) reset(t *timer, d time.Duration) bool {
+	defer clock.notifyAlarm()
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	found := clock.remove(t)
+	if t.period != 0 {
+		if d <= 0 {
+			panic("non-positive interval for Ticker.Reset")
+		}
+		t.period = d
+	}
+	t.deadline = clock.now.Add(d)
+	clock.record(t, TimerReset, d)
+	clock.insert(t)
+	clock.triggerAll()
+	return found
+}
+
+// stop is the underlying implementation of Stop for timers and
+// tickers backed by this clock.
+func (clock *Clock) stop(t *timer) bool {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.record(t, TimerStopped, 0)
+	return clock.remove(t)
+}
+
+// triggerAll triggers all the timers that are due, in deadline order,
+// rescheduling tickers for their next tick. It must be called with the
+// mutex held.
+func (clock *Clock) triggerAll() {
+	for len(clock.waiting) > 0 && !clock.now.Before(clock.waiting[0].deadline) {
+		t := clock.waiting[0]
+		clock.waiting = clock.waiting[1:]
+		clock.notifyChanged()
+		t.events = append(t.events, TimerEvent{Op: TimerFired, At: t.deadline.Sub(clock.start)})
+		clock.fired = append(clock.fired, t.id)
+		t.trigger(clock.wallNow())
+		if t.period != 0 {
+			t.deadline = t.deadline.Add(t.period)
+			clock.insert(t)
+		}
+	}
+}
+
+// insert adds t to the waiting timers, keeping them sorted by deadline.
+// Timers with the same deadline fire in the order they were inserted.
+func (clock *Clock) insert(t *timer) {
+	i := sort.Search(len(clock.waiting), func(i int) bool {
+		return clock.waiting[i].deadline.After(t.deadline)
+	})
+	clock.waiting = append(clock.waiting, nil)
+	copy(clock.waiting[i+1:], clock.waiting[i:])
+	clock.waiting[i] = t
+	clock.notifyChanged()
+}
+
+// remove removes t from the waiting timers, reporting whether it was
+// found.
+func (clock *Clock) remove(t *timer) bool {
+	for i, wt := range clock.waiting {
+		if wt == t {
+			clock.waiting = append(clock.waiting[:i], clock.waiting[i+1:]...)
+			clock.notifyChanged()
+			return true
+		}
+	}
+	return false
+}
+
+// notifyChanged wakes up anything waiting for the set of waiting timers
+// to change. It must be called with the mutex held.
+func (clock *Clock) notifyChanged() {
+	close(clock.changed)
+	clock.changed = make(chan struct{})
+}
+
+// notifyAlarm sends a value on the channel exposed by Alarms.
+func (clock *Clock) notifyAlarm() {
+	select {
+	case clock.notifyAlarms <- struct{}{}:
+	default:
+		panic("alarm notification buffer full")
+	}
+}
+
+// send sends now on c unless c already holds an unread value.
+func send(c chan time.Time, now time.Time) {
+	select {
+	case c <- now:
+	default:
+	}
+}
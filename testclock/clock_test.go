@@ -0,0 +1,197 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testclock_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+)
+
+type clockSuite struct {
+	testing.IsolationSuite
+	start time.Time
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&clockSuite{})
+
+func (s *clockSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.start = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.clock = testclock.NewClock(s.start)
+}
+
+func (s *clockSuite) TestNow(c *gc.C) {
+	c.Assert(s.clock.Now(), gc.Equals, s.start)
+	s.clock.Advance(time.Minute)
+	c.Assert(s.clock.Now(), gc.Equals, s.start.Add(time.Minute))
+}
+
+func (s *clockSuite) TestAfter(c *gc.C) {
+	ch := s.clock.After(time.Second)
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 1)
+	s.clock.Advance(999 * time.Millisecond)
+	assertNotReceived(c, ch)
+	s.clock.Advance(time.Millisecond)
+	c.Assert(receive(c, ch), gc.Equals, s.start.Add(time.Second))
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 0)
+}
+
+func (s *clockSuite) TestNewTimerStopAndReset(c *gc.C) {
+	t := s.clock.NewTimer(time.Second)
+	c.Assert(t.Stop(), jc.IsTrue)
+	c.Assert(t.Stop(), jc.IsFalse)
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 0)
+	s.clock.Advance(time.Second)
+	assertNotReceived(c, t.Chan())
+
+	c.Assert(t.Reset(time.Second), jc.IsFalse)
+	c.Assert(t.Reset(2*time.Second), jc.IsTrue)
+	s.clock.Advance(time.Second)
+	assertNotReceived(c, t.Chan())
+	s.clock.Advance(time.Second)
+	c.Assert(receive(c, t.Chan()), gc.Equals, s.start.Add(3*time.Second))
+}
+
+func (s *clockSuite) TestNewTimerNonPositiveFiresImmediately(c *gc.C) {
+	t := s.clock.NewTimer(0)
+	c.Assert(receive(c, t.Chan()), gc.Equals, s.start)
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 0)
+}
+
+func (s *clockSuite) TestAfterFunc(c *gc.C) {
+	called := make(chan struct{})
+	s.clock.AfterFunc(time.Second, func() { close(called) })
+	s.clock.Advance(time.Second)
+	select {
+	case <-called:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("AfterFunc function not called")
+	}
+}
+
+func (s *clockSuite) TestTicker(c *gc.C) {
+	t := s.clock.NewTicker(time.Second)
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 1)
+	s.clock.Advance(time.Second)
+	c.Assert(receive(c, t.Chan()), gc.Equals, s.start.Add(time.Second))
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 1)
+
+	// Ticks are dropped if they are not read.
+	s.clock.Advance(3 * time.Second)
+	c.Assert(receive(c, t.Chan()), gc.Equals, s.start.Add(4*time.Second))
+	assertNotReceived(c, t.Chan())
+
+	t.Reset(time.Minute)
+	s.clock.Advance(59 * time.Second)
+	assertNotReceived(c, t.Chan())
+	s.clock.Advance(time.Second)
+	c.Assert(receive(c, t.Chan()), gc.Equals, s.start.Add(64*time.Second))
+
+	t.Stop()
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 0)
+	s.clock.Advance(time.Hour)
+	assertNotReceived(c, t.Chan())
+}
+
+func (s *clockSuite) TestNewTickerPanicsOnNonPositiveInterval(c *gc.C) {
+	c.Assert(func() { s.clock.NewTicker(0) }, gc.PanicMatches, "non-positive interval for NewTicker")
+}
+
+func (s *clockSuite) TestAdvanceFiresAllDueTimers(c *gc.C) {
+	ch1 := s.clock.After(2 * time.Second)
+	ch2 := s.clock.After(time.Second)
+	ch3 := s.clock.After(time.Minute)
+	s.clock.Advance(5 * time.Second)
+	c.Assert(receive(c, ch1), gc.Equals, s.start.Add(5*time.Second))
+	c.Assert(receive(c, ch2), gc.Equals, s.start.Add(5*time.Second))
+	assertNotReceived(c, ch3)
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 1)
+}
+
+func (s *clockSuite) TestAlarms(c *gc.C) {
+	go s.clock.After(time.Second)
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(testing.LongWait):
+		c.Fatalf("no alarm notification")
+	}
+	c.Assert(s.clock.WaiterCount(), gc.Equals, 1)
+}
+
+func receive(c *gc.C, ch <-chan time.Time) time.Time {
+	select {
+	case t := <-ch:
+		return t
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for time")
+	}
+	panic("unreachable")
+}
+
+func assertNotReceived(c *gc.C, ch <-chan time.Time) {
+	select {
+	case t := <-ch:
+		c.Fatalf("unexpected time received: %v", t)
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *clockSuite) TestWaitAdvance(c *gc.C) {
+	done := make(chan time.Time)
+	go func() {
+		done <- <-s.clock.After(time.Second)
+	}()
+	s.clock.WaitAdvance(c, time.Second, testing.LongWait, 1)
+	select {
+	case t := <-done:
+		c.Assert(t, gc.Equals, s.start.Add(time.Second))
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timer did not fire")
+	}
+}
+
+func (s *clockSuite) TestWaitAdvanceTimeout(c *gc.C) {
+	s.clock.After(time.Minute)
+	c.ExpectFailure("too few waiters")
+	s.clock.WaitAdvance(c, time.Second, testing.ShortWait, 2)
+}
+
+func (s *clockSuite) TestPendingAlarms(c *gc.C) {
+	s.clock.After(time.Minute)
+	s.clock.NewTicker(time.Second)
+	s.clock.AfterFunc(time.Hour, func() {})
+	c.Assert(s.clock.PendingAlarms(), gc.DeepEquals, []time.Duration{time.Second, time.Minute, time.Hour})
+	s.clock.Advance(1500 * time.Millisecond)
+	c.Assert(s.clock.PendingAlarms(), gc.DeepEquals, []time.Duration{
+		500 * time.Millisecond, 58500 * time.Millisecond, time.Hour - 1500*time.Millisecond,
+	})
+}
+
+func (s *clockSuite) TestHasWaiters(c *gc.C) {
+	c.Assert(s.clock, testclock.HasWaiters, 0)
+	s.clock.After(time.Minute)
+	c.Assert(s.clock, testclock.HasWaiters, 1)
+	result, msg := testclock.HasWaiters.Check([]interface{}{s.clock, 2}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, "clock has 1 waiters; pending alarms: [1m0s]")
+}
+
+func (s *clockSuite) TestHasPendingAlarms(c *gc.C) {
+	s.clock.After(time.Minute)
+	s.clock.After(time.Second)
+	c.Assert(s.clock, testclock.HasPendingAlarms, []time.Duration{time.Second, time.Minute})
+	result, msg := testclock.HasPendingAlarms.Check([]interface{}{s.clock, []time.Duration{time.Second, time.Hour}}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, `difference:
+    [0] 1s
+  - [1] 1h0m0s
+  + [1] 1m0s
+`)
+}
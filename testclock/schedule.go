@@ -0,0 +1,167 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testclock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimerKind says how a timer was created.
+type TimerKind string
+
+const (
+	KindAfter     TimerKind = "After"
+	KindAfterFunc TimerKind = "AfterFunc"
+	KindTimer     TimerKind = "timer"
+	KindTicker    TimerKind = "ticker"
+)
+
+// TimerOp identifies an event in the history of a timer.
+type TimerOp string
+
+const (
+	TimerCreated TimerOp = "created"
+	TimerReset   TimerOp = "reset"
+	TimerStopped TimerOp = "stopped"
+	TimerFired   TimerOp = "fired"
+)
+
+// TimerEvent records an event in the history of a timer.
+type TimerEvent struct {
+	Op TimerOp
+
+	// At holds the monotonic time of the event, measured from the time
+	// the clock was created with. A timer fires at its deadline, even when the
+	// clock is advanced past it, and a ticker that falls behind fires
+	// once for each deadline passed.
+	At time.Duration
+
+	// Duration holds the duration given when the timer was created or
+	// reset.
+	Duration time.Duration
+}
+
+// String returns a description of the event, such as "reset to 2s at
+// +1s".
+func (e TimerEvent) String() string {
+	switch e.Op {
+	case TimerCreated:
+		return fmt.Sprintf("created with %v at +%v", e.Duration, e.At)
+	case TimerReset:
+		return fmt.Sprintf("reset to %v at +%v", e.Duration, e.At)
+	}
+	return fmt.Sprintf("%s at +%v", e.Op, e.At)
+}
+
+// TimerInfo describes a timer, ticker or AfterFunc call made on a
+// Clock.
+type TimerInfo struct {
+	// ID identifies the timer. Timers are numbered from 1 in the order
+	// they were created.
+	ID   int
+	Kind TimerKind
+
+	// Duration holds the duration the timer was created with.
+	Duration time.Duration
+
+	// Pending holds whether the timer is waiting to fire, and Deadline
+	// the monotonic time at which it will do so.
+	Pending  bool
+	Deadline time.Time
+
+	// Events holds the history of the timer, oldest first. Stopping a
+	// timer is recorded even if it was not pending.
+	Events []TimerEvent
+}
+
+// String returns a description of the timer and its history, such as
+// "#1 timer: created with 1s at +0s, fired at +1s".
+func (t TimerInfo) String() string {
+	events := make([]string, len(t.Events))
+	for i, e := range t.Events {
+		events[i] = e.String()
+	}
+	return fmt.Sprintf("#%d %s: %s", t.ID, t.Kind, strings.Join(events, ", "))
+}
+
+// Timers returns a description of every timer, ticker and AfterFunc
+// call made on the clock, in the order they were made, so that a test
+// can check the durations they were given and when they were reset,
+// stopped and fired.
+func (clock *Clock) Timers() []TimerInfo {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	infos := make([]TimerInfo, len(clock.timers))
+	for i, t := range clock.timers {
+		infos[i] = clock.info(t)
+	}
+	return infos
+}
+
+// FiringOrder returns the ids of the timers that have fired, in the
+// order they fired. A ticker appears once for each tick.
+func (clock *Clock) FiringOrder() []int {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return append([]int(nil), clock.fired...)
+}
+
+// Schedule returns a readable description of the timers pending on the
+// clock, in the order they will fire, followed by the history of every
+// timer, for use in failure messages.
+func (clock *Clock) Schedule() string {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "clock at +%v", clock.now.Sub(clock.start))
+	if clock.skew != 0 {
+		fmt.Fprintf(&buf, ", wall clock skewed by %v", clock.skew)
+	}
+	buf.WriteString("; pending timers:\n")
+	if len(clock.waiting) == 0 {
+		buf.WriteString("  (none)\n")
+	}
+	for _, t := range clock.waiting {
+		fmt.Fprintf(&buf, "  #%d %s fires at +%v (in %v)\n",
+			t.id, t.kind, t.deadline.Sub(clock.start), t.deadline.Sub(clock.now))
+	}
+	buf.WriteString("timer history:\n")
+	if len(clock.timers) == 0 {
+		buf.WriteString("  (none)\n")
+	}
+	for _, t := range clock.timers {
+		fmt.Fprintf(&buf, "  %s\n", clock.info(t))
+	}
+	return buf.String()
+}
+
+// info returns a description of t. It must be called with the mutex
+// held.
+func (clock *Clock) info(t *timer) TimerInfo {
+	info := TimerInfo{
+		ID:       t.id,
+		Kind:     t.kind,
+		Duration: t.duration,
+		Events:   append([]TimerEvent(nil), t.events...),
+	}
+	for _, wt := range clock.waiting {
+		if wt == t {
+			info.Pending = true
+			info.Deadline = t.deadline
+		}
+	}
+	return info
+}
+
+// record adds an event to the history of t. It must be called with the
+// mutex held.
+func (clock *Clock) record(t *timer, op TimerOp, d time.Duration) {
+	t.events = append(t.events, TimerEvent{
+		Op:       op,
+		At:       clock.now.Sub(clock.start),
+		Duration: d,
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testclock_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+)
+
+func (s *clockSuite) TestTimers(c *gc.C) {
+	t := s.clock.NewTimer(time.Minute)
+	s.clock.After(time.Second)
+	tk := s.clock.NewTicker(2 * time.Second)
+	s.clock.AfterFunc(time.Hour, func() {})
+
+	s.clock.Advance(time.Second)
+	t.Reset(3 * time.Second)
+	s.clock.Advance(3 * time.Second)
+	tk.Stop()
+	t.Stop()
+
+	c.Assert(s.clock.Timers(), jc.DeepEquals, []testclock.TimerInfo{{
+		ID:       1,
+		Kind:     testclock.KindTimer,
+		Duration: time.Minute,
+		Events: []testclock.TimerEvent{
+			{Op: testclock.TimerCreated, Duration: time.Minute},
+			{Op: testclock.TimerReset, At: time.Second, Duration: 3 * time.Second},
+			{Op: testclock.TimerFired, At: 4 * time.Second},
+			{Op: testclock.TimerStopped, At: 4 * time.Second},
+		},
+	}, {
+		ID:       2,
+		Kind:     testclock.KindAfter,
+		Duration: time.Second,
+		Events: []testclock.TimerEvent{
+			{Op: testclock.TimerCreated, Duration: time.Second},
+			{Op: testclock.TimerFired, At: time.Second},
+		},
+	}, {
+		ID:       3,
+		Kind:     testclock.KindTicker,
+		Duration: 2 * time.Second,
+		Events: []testclock.TimerEvent{
+			{Op: testclock.TimerCreated, Duration: 2 * time.Second},
+			{Op: testclock.TimerFired, At: 2 * time.Second},
+			{Op: testclock.TimerFired, At: 4 * time.Second},
+			{Op: testclock.TimerStopped, At: 4 * time.Second},
+		},
+	}, {
+		ID:       4,
+		Kind:     testclock.KindAfterFunc,
+		Duration: time.Hour,
+		Pending:  true,
+		Deadline: s.start.Add(time.Hour),
+		Events: []testclock.TimerEvent{
+			{Op: testclock.TimerCreated, Duration: time.Hour},
+		},
+	}})
+	c.Assert(s.clock.FiringOrder(), jc.DeepEquals, []int{2, 3, 1, 3})
+}
+
+func (s *clockSuite) TestSchedule(c *gc.C) {
+	c.Assert(s.clock.Schedule(), gc.Equals, `clock at +0s; pending timers:
+  (none)
+timer history:
+  (none)
+`)
+	t := s.clock.NewTimer(time.Minute)
+	s.clock.NewTicker(time.Second)
+	s.clock.Advance(1500 * time.Millisecond)
+	t.Reset(time.Second)
+	c.Assert(s.clock.Schedule(), gc.Equals, `clock at +1.5s; pending timers:
+  #2 ticker fires at +2s (in 500ms)
+  #1 timer fires at +2.5s (in 1s)
+timer history:
+  #1 timer: created with 1m0s at +0s, reset to 1s at +1.5s
+  #2 ticker: created with 1s at +0s, fired at +1s
+`)
+}
+
+func (s *clockSuite) TestFiredInOrder(c *gc.C) {
+	s.clock.After(2 * time.Second)
+	s.clock.After(time.Second)
+	s.clock.Advance(time.Second)
+	c.Assert(s.clock, testclock.FiredInOrder, []int{2})
+	s.clock.Advance(time.Second)
+	c.Assert(s.clock, testclock.FiredInOrder, []int{2, 1})
+
+	result, msg := testclock.FiredInOrder.Check([]interface{}{s.clock, []int{1, 2}}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, `timers fired in order [2 1], expected [1 2]
+clock at +2s; pending timers:
+  (none)
+timer history:
+  #1 After: created with 2s at +0s, fired at +2s
+  #2 After: created with 1s at +0s, fired at +1s
+`)
+}
+
+func (s *clockSuite) TestHasTimerEvents(c *gc.C) {
+	t := s.clock.NewTimer(time.Minute)
+	s.clock.Advance(time.Second)
+	t.Stop()
+	c.Assert(s.clock, testclock.HasTimerEvents, 1, []testclock.TimerEvent{
+		{Op: testclock.TimerCreated, Duration: time.Minute},
+		{Op: testclock.TimerStopped, At: time.Second},
+	})
+
+	result, msg := testclock.HasTimerEvents.Check([]interface{}{s.clock, 1, []testclock.TimerEvent{
+		{Op: testclock.TimerCreated, Duration: time.Minute},
+		{Op: testclock.TimerFired, At: time.Minute},
+	}}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Equals, `timer #1 history does not match; expected: created with 1m0s at +0s, fired at +1m0s
+clock at +1s; pending timers:
+  (none)
+timer history:
+  #1 timer: created with 1m0s at +0s, stopped at +1s
+`)
+
+	result, msg = testclock.HasTimerEvents.Check([]interface{}{s.clock, 2, []testclock.TimerEvent(nil)}, nil)
+	c.Check(result, jc.IsFalse)
+	c.Check(msg, gc.Matches, `no timer #2\n(?s).*`)
+}
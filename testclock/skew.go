@@ -0,0 +1,63 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testclock
+
+import (
+	"time"
+)
+
+// StepWall steps the wall clock time returned by Now by d, which may be
+// negative, as NTP does when correcting a clock that is too far out.
+// As on a real system, the monotonic time by which timers, tickers and
+// AfterFunc calls are scheduled does not move, so none of them fire
+// early or late. The time.Time values returned by Now carry no
+// monotonic reading, so durations computed by subtracting them include
+// the step, as they do on a real system for times that have been
+// stored, serialised or received from another host.
+func (clock *Clock) StepWall(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.skew += d
+}
+
+// SetDrift sets the rate at which the wall clock drifts from the
+// monotonic time as the clock is advanced: advancing the clock by d
+// moves the wall clock by d plus d times rate. For example, a rate of
+// 0.001 makes the wall clock gain a millisecond every second, and a
+// rate of -0.001 makes it lose one.
+func (clock *Clock) SetDrift(rate float64) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.drift = rate
+}
+
+// Skew returns the difference between the wall clock time returned by
+// Now and the monotonic time, as accumulated by StepWall and drift.
+func (clock *Clock) Skew() time.Duration {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.skew
+}
+
+// Monotonic returns the monotonic time of the clock, by which timers,
+// tickers and AfterFunc calls are scheduled. It starts at the time the
+// clock was created with, and is unaffected by StepWall and drift.
+func (clock *Clock) Monotonic() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+// advance advances the monotonic time by d, and the wall clock by d
+// adjusted for drift. It must be called with the mutex held.
+func (clock *Clock) advance(d time.Duration) {
+	clock.now = clock.now.Add(d)
+	clock.skew += time.Duration(float64(d) * clock.drift)
+}
+
+// wallNow returns the wall clock time. It must be called with the mutex
+// held.
+func (clock *Clock) wallNow() time.Time {
+	return clock.now.Add(clock.skew)
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testclock_test
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+)
+
+func (s *clockSuite) TestStepWall(c *gc.C) {
+	ch := s.clock.After(time.Minute)
+	s.clock.StepWall(time.Hour)
+	c.Assert(s.clock.Now(), gc.Equals, s.start.Add(time.Hour))
+	c.Assert(s.clock.Monotonic(), gc.Equals, s.start)
+	c.Assert(s.clock.Skew(), gc.Equals, time.Hour)
+	c.Assert(s.clock, testclock.HasPendingAlarms, []time.Duration{time.Minute})
+	assertNotReceived(c, ch)
+
+	// A backwards step does not fire or delay timers either, and the
+	// time they deliver is the wall clock time.
+	s.clock.StepWall(-2 * time.Hour)
+	s.clock.Advance(time.Minute)
+	c.Assert(receive(c, ch), gc.Equals, s.start.Add(time.Minute-time.Hour))
+	c.Assert(s.clock.Monotonic(), gc.Equals, s.start.Add(time.Minute))
+	c.Assert(s.clock.Schedule(), gc.Equals, `clock at +1m0s, wall clock skewed by -1h0m0s; pending timers:
+  (none)
+timer history:
+  #1 After: created with 1m0s at +0s, fired at +1m0s
+`)
+}
+
+func (s *clockSuite) TestDrift(c *gc.C) {
+	t := s.clock.NewTicker(time.Second)
+	s.clock.SetDrift(0.001)
+	s.clock.Advance(10 * time.Second)
+	c.Assert(s.clock.Skew(), gc.Equals, 10*time.Millisecond)
+	c.Assert(s.clock.Now(), gc.Equals, s.start.Add(10010*time.Millisecond))
+	c.Assert(s.clock.Monotonic(), gc.Equals, s.start.Add(10*time.Second))
+	c.Assert(receive(c, t.Chan()), gc.Equals, s.start.Add(10010*time.Millisecond))
+	c.Assert(s.clock, testclock.HasPendingAlarms, []time.Duration{time.Second})
+
+	s.clock.SetDrift(-0.5)
+	s.clock.Advance(2 * time.Second)
+	c.Assert(s.clock.Skew(), gc.Equals, 10*time.Millisecond-time.Second)
+	c.Assert(s.clock.Now(), gc.Equals, s.start.Add(11010*time.Millisecond))
+
+	s.clock.SetDrift(0)
+	s.clock.Advance(time.Second)
+	c.Assert(s.clock.Skew(), gc.Equals, 10*time.Millisecond-time.Second)
+}
+
+func (s *clockSuite) TestLeaseExpiryUnderSkew(c *gc.C) {
+	// A lease whose expiry is stored as a wall clock time appears to
+	// expire early when the wall clock steps forward, while a timer for
+	// the same expiry fires on time.
+	expiry := s.clock.Now().Add(time.Minute)
+	timer := s.clock.NewTimer(time.Minute)
+	s.clock.StepWall(2 * time.Minute)
+	c.Assert(s.clock.Now().After(expiry), jc.IsTrue)
+	assertNotReceived(c, timer.Chan())
+	s.clock.Advance(time.Minute)
+	receive(c, timer.Chan())
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// ThroughputMeter measures the rate at which items and bytes pass
+// through a pipeline, and the time spent in each of its stages, for
+// checking with CheckThroughput. It is safe to use concurrently.
+type ThroughputMeter struct {
+	clock clock.Clock
+
+	mu      sync.Mutex
+	start   time.Time
+	stop    time.Time
+	stopped bool
+	items   int64
+	bytes   int64
+	stages  map[string]*StageTiming
+}
+
+// StageTiming holds the time spent in a stage of a pipeline measured
+// by a ThroughputMeter.
+type StageTiming struct {
+	// Name holds the name of the stage.
+	Name string
+
+	// Calls holds the number of times the stage was timed.
+	Calls int
+
+	// Total holds the total time spent in the stage.
+	Total time.Duration
+}
+
+// NewThroughputMeter returns a meter that measures time with clk,
+// starting now. If clk is nil, clock.WallClock is used. With a
+// testclock.Clock, the interval measured and the time spent in each
+// stage are the times by which the test, or the code under test,
+// advances the clock.
+func NewThroughputMeter(clk clock.Clock) *ThroughputMeter {
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	return &ThroughputMeter{
+		clock:  clk,
+		start:  clk.Now(),
+		stages: make(map[string]*StageTiming),
+	}
+}
+
+// Add records that the given number of items, of the given total size
+// in bytes, have passed through the pipeline.
+func (m *ThroughputMeter) Add(items int, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items += int64(items)
+	m.bytes += bytes
+}
+
+// TimeStage starts timing a call of the named stage of the pipeline,
+// and returns a function that stops timing it:
+//
+//	defer meter.TimeStage("decode")()
+func (m *ThroughputMeter) TimeStage(name string) func() {
+	start := m.clock.Now()
+	return func() {
+		elapsed := m.clock.Now().Sub(start)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		stage := m.stages[name]
+		if stage == nil {
+			stage = &StageTiming{Name: name}
+			m.stages[name] = stage
+		}
+		stage.Calls++
+		stage.Total += elapsed
+	}
+}
+
+// Stop ends the interval over which throughput is measured, so that
+// work done afterwards, such as shutting the pipeline down, is not
+// counted. If it is not called, the interval ends when the throughput
+// is checked.
+func (m *ThroughputMeter) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.stopped {
+		m.stop = m.clock.Now()
+		m.stopped = true
+	}
+}
+
+// Throughput returns the throughput measured so far.
+func (m *ThroughputMeter) Throughput() Throughput {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stop := m.stop
+	if !m.stopped {
+		stop = m.clock.Now()
+	}
+	t := Throughput{
+		Items:   m.items,
+		Bytes:   m.bytes,
+		Elapsed: stop.Sub(m.start),
+	}
+	for _, stage := range m.stages {
+		t.Stages = append(t.Stages, *stage)
+	}
+	sort.Slice(t.Stages, func(i, j int) bool {
+		if t.Stages[i].Total != t.Stages[j].Total {
+			return t.Stages[i].Total > t.Stages[j].Total
+		}
+		return t.Stages[i].Name < t.Stages[j].Name
+	})
+	return t
+}
+
+// Throughput holds the throughput of a pipeline measured by a
+// ThroughputMeter.
+type Throughput struct {
+	// Items and Bytes hold the number of items and bytes that passed
+	// through the pipeline.
+	Items, Bytes int64
+
+	// Elapsed holds the interval over which they were measured.
+	Elapsed time.Duration
+
+	// Stages holds the time spent in each stage of the pipeline,
+	// slowest first.
+	Stages []StageTiming
+}
+
+// ItemsPerSecond returns the rate at which items passed through the
+// pipeline.
+func (t Throughput) ItemsPerSecond() float64 {
+	return perSecond(t.Items, t.Elapsed)
+}
+
+// BytesPerSecond returns the rate at which bytes passed through the
+// pipeline.
+func (t Throughput) BytesPerSecond() float64 {
+	return perSecond(t.Bytes, t.Elapsed)
+}
+
+// perSecond returns the rate of n over the interval d, which is zero if
+// the interval is empty.
+func perSecond(n int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
+
+// ThroughputBound is a minimum throughput checked by CheckThroughput.
+type ThroughputBound struct {
+	// Unit holds the unit of the throughput, "items" or "bytes".
+	Unit string
+
+	// Min holds the minimum number of units per second.
+	Min float64
+}
+
+// MinItemsPerSecond returns a bound of min on the rate at which items
+// pass through a pipeline.
+func MinItemsPerSecond(min float64) ThroughputBound {
+	return ThroughputBound{Unit: "items", Min: min}
+}
+
+// MinBytesPerSecond returns a bound of min on the rate at which bytes
+// pass through a pipeline.
+func MinBytesPerSecond(min float64) ThroughputBound {
+	return ThroughputBound{Unit: "bytes", Min: min}
+}
+
+// CheckThroughput checks that the throughput measured by m meets each
+// of the given bounds, reporting the measured rates and the time spent
+// in each stage of the pipeline if not, so that the bottleneck can be
+// found. It returns whether the check succeeded:
+//
+//	meter := testing.NewThroughputMeter(nil)
+//	for _, record := range records {
+//		done := meter.TimeStage("encode")
+//		data := encode(record)
+//		done()
+//		done = meter.TimeStage("write")
+//		w.Write(data)
+//		done()
+//		meter.Add(1, int64(len(data)))
+//	}
+//	testing.CheckThroughput(c, meter, testing.MinItemsPerSecond(1000), testing.MinBytesPerSecond(1<<20))
+func CheckThroughput(c *gc.C, m *ThroughputMeter, bounds ...ThroughputBound) bool {
+	t := m.Throughput()
+	if t.Elapsed <= 0 {
+		c.Errorf("cannot check throughput measured over %v", t.Elapsed)
+		return false
+	}
+	ok := true
+	for _, bound := range bounds {
+		var rate float64
+		switch bound.Unit {
+		case "items":
+			rate = t.ItemsPerSecond()
+		case "bytes":
+			rate = t.BytesPerSecond()
+		default:
+			c.Errorf("unknown throughput unit %q", bound.Unit)
+			ok = false
+			continue
+		}
+		if rate < bound.Min {
+			ok = false
+			c.Errorf("throughput of %s under its minimum of %s", formatRate(rate, bound.Unit), formatRate(bound.Min, bound.Unit))
+		}
+	}
+	summary := fmt.Sprintf("throughput over %v: %d items (%s), %s (%s)",
+		t.Elapsed, t.Items, formatRate(t.ItemsPerSecond(), "items"),
+		formatBytes(t.Bytes), formatRate(t.BytesPerSecond(), "bytes"))
+	if ok {
+		c.Logf("%s", summary)
+		return true
+	}
+	if len(t.Stages) == 0 {
+		c.Logf("%s", summary)
+		return false
+	}
+	var stages strings.Builder
+	for _, stage := range t.Stages {
+		fmt.Fprintf(&stages, "\n  %s: %v over %d calls (%v per call)",
+			stage.Name, stage.Total, stage.Calls, stage.Total/time.Duration(stage.Calls))
+	}
+	c.Logf("%s; time by stage, slowest first:%s", summary, stages.String())
+	return false
+}
+
+// AssertThroughput is like CheckThroughput, but stops the test if the
+// throughput is under any of the bounds.
+func AssertThroughput(c *gc.C, m *ThroughputMeter, bounds ...ThroughputBound) {
+	if !CheckThroughput(c, m, bounds...) {
+		c.FailNow()
+	}
+}
+
+// formatRate formats a rate in the given unit per second.
+func formatRate(rate float64, unit string) string {
+	if unit == "bytes" {
+		return formatBytes(int64(rate)) + "/s"
+	}
+	return fmt.Sprintf("%.1f %s/s", rate, unit)
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+)
+
+type throughputSuite struct{}
+
+var _ = gc.Suite(&throughputSuite{})
+
+// runPipeline passes n items of 1 KiB through a pipeline whose decode
+// stage takes 2ms and whose write stage takes 8ms per item, as measured
+// by the returned meter.
+func runPipeline(n int) *testing.ThroughputMeter {
+	clk := testclock.NewClock(time.Time{})
+	meter := testing.NewThroughputMeter(clk)
+	for i := 0; i < n; i++ {
+		done := meter.TimeStage("decode")
+		clk.Advance(2 * time.Millisecond)
+		done()
+		done = meter.TimeStage("write")
+		clk.Advance(8 * time.Millisecond)
+		done()
+		meter.Add(1, 1024)
+	}
+	return meter
+}
+
+func (*throughputSuite) TestThroughput(c *gc.C) {
+	t := runPipeline(100).Throughput()
+	c.Assert(t.Items, gc.Equals, int64(100))
+	c.Assert(t.Bytes, gc.Equals, int64(100*1024))
+	c.Assert(t.Elapsed, gc.Equals, time.Second)
+	c.Assert(t.ItemsPerSecond(), gc.Equals, 100.0)
+	c.Assert(t.BytesPerSecond(), gc.Equals, 102400.0)
+	c.Assert(t.Stages, jc.DeepEquals, []testing.StageTiming{
+		{Name: "write", Calls: 100, Total: 800 * time.Millisecond},
+		{Name: "decode", Calls: 100, Total: 200 * time.Millisecond},
+	})
+}
+
+func (*throughputSuite) TestStop(c *gc.C) {
+	clk := testclock.NewClock(time.Time{})
+	meter := testing.NewThroughputMeter(clk)
+	meter.Add(10, 0)
+	clk.Advance(time.Second)
+	meter.Stop()
+	clk.Advance(time.Second)
+	meter.Stop()
+	c.Assert(meter.Throughput().ItemsPerSecond(), gc.Equals, 10.0)
+}
+
+func (*throughputSuite) TestWithinBounds(c *gc.C) {
+	ok := testing.CheckThroughput(c, runPipeline(100), testing.MinItemsPerSecond(100), testing.MinBytesPerSecond(100<<10))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(c.GetTestLog(), gc.Equals, "throughput over 1s: 100 items (100.0 items/s), 100.0 KiB (100.0 KiB/s)\n")
+}
+
+func (*throughputSuite) TestWallClock(c *gc.C) {
+	meter := testing.NewThroughputMeter(nil)
+	meter.Add(1, 1)
+	time.Sleep(time.Millisecond)
+	testing.AssertThroughput(c, meter, testing.MinItemsPerSecond(0.001))
+}
+
+// slowThroughputSuite is run by TestUnderBound rather than being
+// registered with gocheck.
+type slowThroughputSuite struct{}
+
+func (*slowThroughputSuite) TestThroughput(c *gc.C) {
+	testing.AssertThroughput(c, runPipeline(100), testing.MinItemsPerSecond(200), testing.MinBytesPerSecond(1<<20), testing.MinItemsPerSecond(50))
+	c.Fatalf("AssertThroughput did not stop the test")
+}
+
+func (*slowThroughputSuite) TestNoInterval(c *gc.C) {
+	testing.CheckThroughput(c, testing.NewThroughputMeter(testclock.NewClock(time.Time{})), testing.MinItemsPerSecond(1))
+}
+
+func (*throughputSuite) TestUnderBound(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&slowThroughputSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 2)
+	c.Assert(output.String(), gc.Matches, `(?s).*`+
+		`\.\.\. Error: throughput of 100\.0 items/s under its minimum of 200\.0 items/s\n.*`+
+		`\.\.\. Error: throughput of 100\.0 KiB/s under its minimum of 1\.0 MiB/s\n.*`+
+		`throughput over 1s: 100 items \(100\.0 items/s\), 100\.0 KiB \(100\.0 KiB/s\); time by stage, slowest first:\n`+
+		`  write: 800ms over 100 calls \(8ms per call\)\n`+
+		`  decode: 200ms over 100 calls \(2ms per call\)\n.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*minimum of 50\.0.*`)
+	c.Assert(output.String(), gc.Not(gc.Matches), `(?s).*did not stop the test.*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*cannot check throughput measured over 0s\n.*`)
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Time zones that commonly expose bugs in time handling, for use with
+// PatchTimezone.
+const (
+	// TimezoneKolkata is UTC+5:30 all year.
+	TimezoneKolkata = "Asia/Kolkata"
+
+	// TimezoneKathmandu is UTC+5:45 all year.
+	TimezoneKathmandu = "Asia/Kathmandu"
+
+	// TimezoneStJohns is UTC-3:30, or UTC-2:30 during daylight
+	// saving time.
+	TimezoneStJohns = "America/St_Johns"
+
+	// TimezoneLordHowe is UTC+10:30, or UTC+11 during daylight
+	// saving time, which moves the clocks by only 30 minutes.
+	TimezoneLordHowe = "Australia/Lord_Howe"
+
+	// TimezoneChatham is UTC+12:45, or UTC+13:45 during daylight
+	// saving time, so local dates are often a day ahead of UTC.
+	TimezoneChatham = "Pacific/Chatham"
+
+	// TimezoneNewYork is UTC-5, or UTC-4 during daylight saving
+	// time, with transitions in March and November.
+	TimezoneNewYork = "America/New_York"
+)
+
+// LoadTimezone returns the named IANA time zone, such as one of the
+// Timezone constants, failing the test if it cannot be loaded, for
+// example because the system has no time zone database; importing
+// time/tzdata in the test avoids that. Code under test that takes the
+// location to use, rather than using time.Local, can be given the
+// result directly:
+//
+//	loc := testing.LoadTimezone(c, testing.TimezoneChatham)
+//	c.Assert(formatDate(t, loc), gc.Equals, "2024-01-02")
+func LoadTimezone(c *gc.C, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		c.Fatalf("cannot load time zone %q: %v", name, err)
+	}
+	return loc
+}
+
+// PatchTimezone makes the named IANA time zone the local time zone by
+// setting both time.Local and the TZ environment variable, which is
+// seen by subprocesses. It returns a function that restores both. The
+// test fails if the zone cannot be loaded.
+//
+// PatchTimezone is a last resort for code that cannot be given a
+// location from LoadTimezone. time.Local is written without
+// synchronisation, and is read by time.Now and friends, so it is only
+// safe to call when no other goroutine uses the time package, including
+// goroutines left running by earlier tests; the race detector will
+// otherwise report a data race. Running the test with RunSubprocess
+// avoids the latter.
+func PatchTimezone(c *gc.C, name string) Restorer {
+	loc := LoadTimezone(c, name)
+	restoreLocal := PatchValue(&time.Local, loc)
+	restoreTZ := PatchEnvironment("TZ", name)
+	return func() {
+		restoreTZ()
+		restoreLocal()
+	}
+}
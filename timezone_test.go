@@ -0,0 +1,61 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"os"
+	"time"
+	_ "time/tzdata"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+type timezoneSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&timezoneSuite{})
+
+func (s *timezoneSuite) TestLoadTimezone(c *gc.C) {
+	loc := testing.LoadTimezone(c, testing.TimezoneKathmandu)
+	c.Assert(loc.String(), gc.Equals, "Asia/Kathmandu")
+	t := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).In(loc)
+	_, offset := t.Zone()
+	c.Assert(offset, gc.Equals, 5*3600+45*60)
+	c.Assert(t.Format("15:04"), gc.Equals, "05:45")
+}
+
+func (s *timezoneSuite) TestDaylightSaving(c *gc.C) {
+	loc := testing.LoadTimezone(c, testing.TimezoneLordHowe)
+	winter := time.Date(2024, 7, 1, 12, 0, 0, 0, loc)
+	summer := time.Date(2024, 1, 1, 12, 0, 0, 0, loc)
+	_, winterOffset := winter.Zone()
+	_, summerOffset := summer.Zone()
+	c.Assert(winterOffset, gc.Equals, 10*3600+30*60)
+	c.Assert(summerOffset, gc.Equals, 11*3600)
+}
+
+func (s *timezoneSuite) TestUnknownTimezone(c *gc.C) {
+	c.ExpectFailure("unknown zone")
+	testing.LoadTimezone(c, "Nowhere/Special")
+}
+
+func (s *timezoneSuite) TestPatchTimezone(c *gc.C) {
+	// Goroutines left running by other tests may use time.Local, so
+	// patch it in a process of its own.
+	result := testing.RunSubprocess(c, func() {
+		oldLocal := time.Local
+		restore := testing.PatchTimezone(c, testing.TimezoneKathmandu)
+		c.Assert(time.Local.String(), gc.Equals, "Asia/Kathmandu")
+		c.Assert(os.Getenv("TZ"), gc.Equals, "Asia/Kathmandu")
+		t := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Local()
+		c.Assert(t.Format("15:04"), gc.Equals, "05:45")
+
+		restore()
+		c.Assert(time.Local, gc.Equals, oldLocal)
+	})
+	c.Assert(result.Exited, gc.Equals, false, gc.Commentf("%s", result.Stdout))
+}
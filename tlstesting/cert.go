@@ -0,0 +1,245 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package tlstesting generates throwaway certificate authorities and
+// certificates in memory, so that tests of TLS and mutual TLS code do
+// not depend on checked-in certificates that expire.
+package tlstesting
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// Validity describes when a generated certificate is valid.
+type Validity int
+
+const (
+	// Valid certificates are valid from an hour ago until a day
+	// from now.
+	Valid Validity = iota
+
+	// Expired certificates expired an hour ago.
+	Expired
+
+	// NotYetValid certificates become valid in an hour.
+	NotYetValid
+)
+
+// period returns the validity period of a certificate made at now.
+func (v Validity) period(now time.Time) (notBefore, notAfter time.Time) {
+	switch v {
+	case Expired:
+		return now.Add(-48 * time.Hour), now.Add(-time.Hour)
+	case NotYetValid:
+		return now.Add(time.Hour), now.Add(48 * time.Hour)
+	}
+	return now.Add(-time.Hour), now.Add(24 * time.Hour)
+}
+
+// Cert holds a generated certificate and its private key.
+type Cert struct {
+	// Cert holds the parsed certificate.
+	Cert *x509.Certificate
+
+	// Key holds the certificate's private key.
+	Key crypto.Signer
+
+	// CertPEM and KeyPEM hold the certificate and key in PEM
+	// form, as they would be written to files.
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// TLSCertificate returns the certificate in the form used by
+// tls.Config.
+func (cert *Cert) TLSCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{cert.Cert.Raw},
+		PrivateKey:  cert.Key,
+		Leaf:        cert.Cert,
+	}
+}
+
+// CA is a certificate authority that issues certificates.
+type CA struct {
+	Cert
+}
+
+// NewCA returns a new self-signed certificate authority with the
+// given common name.
+func NewCA(c *gc.C, name string) *CA {
+	return NewCAWithValidity(c, name, Valid)
+}
+
+// NewCAWithValidity is like NewCA but makes a certificate authority
+// whose own certificate has the given validity.
+func NewCAWithValidity(c *gc.C, name string, validity Validity) *CA {
+	notBefore, notAfter := validity.period(time.Now())
+	template := &x509.Certificate{
+		SerialNumber:          newSerial(c),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	key := newKey(c)
+	return &CA{*makeCert(c, template, template, key, key)}
+}
+
+// CertPool returns a pool holding only the CA's certificate.
+func (ca *CA) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Cert.Cert)
+	return pool
+}
+
+// CertParams holds the parameters of a certificate issued by a CA.
+type CertParams struct {
+	// CommonName holds the subject common name of the certificate.
+	CommonName string
+
+	// Hosts holds the DNS names and IP addresses for which the
+	// certificate is valid.
+	Hosts []string
+
+	// Server and Client specify whether the certificate may be
+	// used by TLS servers and clients respectively.
+	Server bool
+	Client bool
+
+	// Validity specifies when the certificate is valid.
+	Validity Validity
+}
+
+// NewCert issues a certificate with the given parameters.
+func (ca *CA) NewCert(c *gc.C, p CertParams) *Cert {
+	notBefore, notAfter := p.Validity.period(time.Now())
+	template := &x509.Certificate{
+		SerialNumber: newSerial(c),
+		Subject:      pkix.Name{CommonName: p.CommonName},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if p.Server {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+	}
+	if p.Client {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+	}
+	for _, host := range p.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	return makeCert(c, template, ca.Cert.Cert, newKey(c), ca.Key)
+}
+
+// NewServerCert issues a server certificate valid for the given DNS
+// names and IP addresses. The first host is used as the common name.
+func (ca *CA) NewServerCert(c *gc.C, hosts ...string) *Cert {
+	var name string
+	if len(hosts) > 0 {
+		name = hosts[0]
+	}
+	return ca.NewCert(c, CertParams{
+		CommonName: name,
+		Hosts:      hosts,
+		Server:     true,
+	})
+}
+
+// NewClientCert issues a client certificate with the given common
+// name.
+func (ca *CA) NewClientCert(c *gc.C, name string) *Cert {
+	return ca.NewCert(c, CertParams{
+		CommonName: name,
+		Client:     true,
+	})
+}
+
+// ServerConfig returns a configuration for a TLS server that presents
+// the given certificate. If any clientCAs are given, clients must
+// present a certificate issued by one of them.
+func ServerConfig(cert *Cert, clientCAs ...*CA) *tls.Config {
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert.TLSCertificate()},
+	}
+	if len(clientCAs) > 0 {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.ClientCAs = x509.NewCertPool()
+		for _, ca := range clientCAs {
+			config.ClientCAs.AddCert(ca.Cert.Cert)
+		}
+	}
+	return config
+}
+
+// ClientConfig returns a configuration for a TLS client that trusts
+// only the given CA and, if cert is not nil, presents cert to the
+// server.
+func ClientConfig(ca *CA, cert *Cert) *tls.Config {
+	config := &tls.Config{
+		RootCAs: ca.CertPool(),
+	}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{cert.TLSCertificate()}
+	}
+	return config
+}
+
+// NewMutualTLSConfigs returns matching server and client
+// configurations for mutual TLS, using a new CA that issues both a
+// server certificate valid for the given hosts and a client
+// certificate.
+func NewMutualTLSConfigs(c *gc.C, hosts ...string) (server, client *tls.Config) {
+	ca := NewCA(c, "tlstesting CA")
+	server = ServerConfig(ca.NewServerCert(c, hosts...), ca)
+	client = ClientConfig(ca, ca.NewClientCert(c, "tlstesting client"))
+	return server, client
+}
+
+func newKey(c *gc.C) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, gc.IsNil)
+	return key
+}
+
+func newSerial(c *gc.C) *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	c.Assert(err, gc.IsNil)
+	return serial
+}
+
+// makeCert signs template with signerKey as the parent certificate
+// and returns the result.
+func makeCert(c *gc.C, template, parent *x509.Certificate, key *ecdsa.PrivateKey, signerKey crypto.Signer) *Cert {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signerKey)
+	c.Assert(err, gc.IsNil)
+	cert, err := x509.ParseCertificate(der)
+	c.Assert(err, gc.IsNil)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	c.Assert(err, gc.IsNil)
+	return &Cert{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tlstesting_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/tlstesting"
+)
+
+type certSuite struct{}
+
+var _ = gc.Suite(&certSuite{})
+
+// get makes a request to a TLS server with the given configuration
+// using a client with the given configuration.
+func get(c *gc.C, server, client *tls.Config) error {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	srv.TLS = server
+	// Handshake failures are expected by some tests.
+	srv.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: client}}
+	defer httpClient.CloseIdleConnections()
+	resp, err := httpClient.Get(srv.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *certSuite) TestMutualTLS(c *gc.C) {
+	server, client := tlstesting.NewMutualTLSConfigs(c, "127.0.0.1")
+	err := get(c, server, client)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *certSuite) TestClientCertRequired(c *gc.C) {
+	ca := tlstesting.NewCA(c, "ca")
+	server := tlstesting.ServerConfig(ca.NewServerCert(c, "127.0.0.1"), ca)
+	err := get(c, server, tlstesting.ClientConfig(ca, nil))
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *certSuite) TestClientCertFromOtherCA(c *gc.C) {
+	ca := tlstesting.NewCA(c, "ca")
+	other := tlstesting.NewCA(c, "other")
+	server := tlstesting.ServerConfig(ca.NewServerCert(c, "127.0.0.1"), ca)
+	err := get(c, server, tlstesting.ClientConfig(ca, other.NewClientCert(c, "client")))
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *certSuite) TestServerOnly(c *gc.C) {
+	ca := tlstesting.NewCA(c, "ca")
+	server := tlstesting.ServerConfig(ca.NewServerCert(c, "127.0.0.1"))
+	err := get(c, server, tlstesting.ClientConfig(ca, nil))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *certSuite) TestWrongHost(c *gc.C) {
+	ca := tlstesting.NewCA(c, "ca")
+	server := tlstesting.ServerConfig(ca.NewServerCert(c, "example.com"))
+	err := get(c, server, tlstesting.ClientConfig(ca, nil))
+	c.Assert(err, gc.ErrorMatches, `.*cannot validate certificate for 127.0.0.1 because it doesn't contain any IP SANs`)
+}
+
+func (s *certSuite) TestValidity(c *gc.C) {
+	ca := tlstesting.NewCA(c, "ca")
+	for _, validity := range []tlstesting.Validity{tlstesting.Expired, tlstesting.NotYetValid} {
+		cert := ca.NewCert(c, tlstesting.CertParams{
+			Hosts:    []string{"127.0.0.1"},
+			Server:   true,
+			Validity: validity,
+		})
+		err := get(c, tlstesting.ServerConfig(cert), tlstesting.ClientConfig(ca, nil))
+		c.Check(err, gc.ErrorMatches, `.*certificate has expired or is not yet valid.*`)
+	}
+}
+
+func (s *certSuite) TestExpiredCA(c *gc.C) {
+	ca := tlstesting.NewCAWithValidity(c, "ca", tlstesting.Expired)
+	_, err := ca.NewServerCert(c, "localhost").Cert.Verify(x509.VerifyOptions{
+		DNSName: "localhost",
+		Roots:   ca.CertPool(),
+	})
+	c.Assert(err, gc.ErrorMatches, `.*certificate has expired or is not yet valid.*`)
+}
+
+func (s *certSuite) TestCertFields(c *gc.C) {
+	ca := tlstesting.NewCA(c, "ca")
+	cert := ca.NewServerCert(c, "example.com", "10.0.0.1", "::1")
+	c.Assert(cert.Cert.Subject.CommonName, gc.Equals, "example.com")
+	c.Assert(cert.Cert.DNSNames, jc.DeepEquals, []string{"example.com"})
+	c.Assert(cert.Cert.IPAddresses, gc.HasLen, 2)
+	c.Assert(cert.Cert.ExtKeyUsage, jc.DeepEquals, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	c.Assert(ca.Cert.Cert.IsCA, jc.IsTrue)
+
+	client := ca.NewClientCert(c, "client")
+	c.Assert(client.Cert.ExtKeyUsage, jc.DeepEquals, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})
+}
+
+func (s *certSuite) TestPEM(c *gc.C) {
+	ca := tlstesting.NewCA(c, "ca")
+	cert := ca.NewServerCert(c, "example.com")
+	pair, err := tls.X509KeyPair(cert.CertPEM, cert.KeyPEM)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pair.Certificate[0], jc.DeepEquals, cert.Cert.Raw)
+
+	pool := x509.NewCertPool()
+	c.Assert(pool.AppendCertsFromPEM(ca.CertPEM), jc.IsTrue)
+	_, err = cert.Cert.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: pool})
+	c.Assert(err, jc.ErrorIsNil)
+}
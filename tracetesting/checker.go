@@ -0,0 +1,256 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tracetesting
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// ExpectedSpan describes a span checked for by HasSpans. Fields with
+// their zero values are not checked.
+type ExpectedSpan struct {
+	Name string
+
+	// Parent holds the name of the span's parent, which must be in
+	// the same trace.
+	Parent string
+
+	// Root holds whether the span must have no parent.
+	Root bool
+
+	Kind   SpanKind
+	Status StatusCode
+
+	// Attributes holds attributes that the span must have, among
+	// others. Integer values match int64 attributes and float32
+	// values match float64 attributes.
+	Attributes map[string]interface{}
+}
+
+// String returns a description of the expected span.
+func (expected ExpectedSpan) String() string {
+	var conditions []string
+	if expected.Root {
+		conditions = append(conditions, "root")
+	}
+	if expected.Parent != "" {
+		conditions = append(conditions, fmt.Sprintf("parent %q", expected.Parent))
+	}
+	if expected.Kind != SpanKindUnspecified {
+		conditions = append(conditions, fmt.Sprintf("kind %d", expected.Kind))
+	}
+	if expected.Status != StatusUnset {
+		conditions = append(conditions, "status "+expected.Status.String())
+	}
+	if len(expected.Attributes) > 0 {
+		conditions = append(conditions, "attributes "+formatAttributes(expected.Attributes))
+	}
+	if len(conditions) == 0 {
+		return fmt.Sprintf("%q", expected.Name)
+	}
+	return fmt.Sprintf("%q (%s)", expected.Name, strings.Join(conditions, ", "))
+}
+
+type hasSpansChecker struct {
+	*gc.CheckerInfo
+}
+
+// HasSpans checks that for each of the expected []ExpectedSpan, a
+// different one of the obtained spans matches it. The obtained value
+// may be a *Collector or a []Span. Other spans are ignored.
+//
+// On failure, the spans that were received are shown as a tree of
+// parents and children, so that missing or misplaced spans can be
+// found. For example:
+//
+//	no span matches "db.query" (parent "GET /users")
+//	spans received:
+//	  GET /users status=OK {http.method="GET"}
+//	  db.query {db.system="postgresql"}
+var HasSpans gc.Checker = &hasSpansChecker{
+	&gc.CheckerInfo{Name: "HasSpans", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *hasSpansChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	var spans []Span
+	switch obtained := params[0].(type) {
+	case *Collector:
+		if obtained == nil {
+			return false, "obtained value is nil"
+		}
+		spans = obtained.Spans()
+	case []Span:
+		spans = obtained
+	default:
+		return false, fmt.Sprintf("obtained value must be *tracetesting.Collector or []tracetesting.Span, got %T", params[0])
+	}
+	expected, ok := params[1].([]ExpectedSpan)
+	if !ok {
+		return false, fmt.Sprintf("expected value must be []tracetesting.ExpectedSpan, got %T", params[1])
+	}
+
+	// candidates[i] holds the indexes of the spans that match
+	// expected[i].
+	candidates := make([][]int, len(expected))
+	var unmatched []string
+	for i, e := range expected {
+		for j, span := range spans {
+			if spanMatches(span, e, spans) {
+				candidates[i] = append(candidates[i], j)
+			}
+		}
+		if len(candidates[i]) == 0 {
+			unmatched = append(unmatched, fmt.Sprintf("no span matches %v\n", e))
+		}
+	}
+	if len(unmatched) == 0 {
+		if assignSpans(candidates, make(map[int]bool)) {
+			return true, ""
+		}
+		unmatched = append(unmatched, "expected spans do not each match a different span\n")
+	}
+	return false, strings.Join(unmatched, "") + "spans received:\n" + formatSpanTree(spans)
+}
+
+// assignSpans reports whether each remaining list of candidates can be
+// given a different span that is not already used.
+func assignSpans(candidates [][]int, used map[int]bool) bool {
+	if len(candidates) == 0 {
+		return true
+	}
+	for _, j := range candidates[0] {
+		if used[j] {
+			continue
+		}
+		used[j] = true
+		if assignSpans(candidates[1:], used) {
+			return true
+		}
+		delete(used, j)
+	}
+	return false
+}
+
+// spanMatches reports whether span, one of spans, matches expected.
+func spanMatches(span Span, expected ExpectedSpan, spans []Span) bool {
+	if span.Name != expected.Name {
+		return false
+	}
+	if expected.Root && span.ParentSpanID != "" {
+		return false
+	}
+	if expected.Parent != "" {
+		parent, ok := findSpan(spans, span.TraceID, span.ParentSpanID)
+		if !ok || parent.Name != expected.Parent {
+			return false
+		}
+	}
+	if expected.Kind != SpanKindUnspecified && span.Kind != expected.Kind {
+		return false
+	}
+	if expected.Status != StatusUnset && span.Status != expected.Status {
+		return false
+	}
+	for key, value := range expected.Attributes {
+		v, ok := span.Attributes[key]
+		if !ok || !reflect.DeepEqual(v, normaliseAttribute(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// findSpan returns the span with the given IDs, if there is one.
+func findSpan(spans []Span, traceID, spanID string) (Span, bool) {
+	if spanID == "" {
+		return Span{}, false
+	}
+	for _, span := range spans {
+		if span.TraceID == traceID && span.SpanID == spanID {
+			return span, true
+		}
+	}
+	return Span{}, false
+}
+
+// normaliseAttribute converts an expected attribute value to the type
+// in which a value of its kind is decoded.
+func normaliseAttribute(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(v.Uint())
+	case reflect.Float32:
+		return v.Float()
+	}
+	return value
+}
+
+// formatSpanTree formats spans as a tree, with each span followed by
+// its children, indented.
+func formatSpanTree(spans []Span) string {
+	if len(spans) == 0 {
+		return "  (none)\n"
+	}
+	children := make(map[string][]int)
+	var roots []int
+	for i, span := range spans {
+		if _, ok := findSpan(spans, span.TraceID, span.ParentSpanID); ok {
+			key := span.TraceID + "/" + span.ParentSpanID
+			children[key] = append(children[key], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+	var buf strings.Builder
+	var write func(i int, depth int)
+	write = func(i int, depth int) {
+		span := spans[i]
+		buf.WriteString(strings.Repeat("  ", depth+1))
+		buf.WriteString(span.Name)
+		if span.ParentSpanID != "" && depth == 0 {
+			fmt.Fprintf(&buf, " (parent %s not received)", span.ParentSpanID)
+		}
+		if span.Status != StatusUnset {
+			fmt.Fprintf(&buf, " status=%v", span.Status)
+		}
+		if len(span.Attributes) > 0 {
+			buf.WriteString(" " + formatAttributes(span.Attributes))
+		}
+		buf.WriteString("\n")
+		for _, child := range children[span.TraceID+"/"+span.SpanID] {
+			write(child, depth+1)
+		}
+	}
+	for _, i := range roots {
+		write(i, 0)
+	}
+	return buf.String()
+}
+
+// formatAttributes formats attributes sorted by key, such as
+// `{http.method="GET", http.status_code=200}`.
+func formatAttributes(attrs map[string]interface{}) string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		if s, ok := attrs[key].(string); ok {
+			pairs[i] = fmt.Sprintf("%s=%q", key, s)
+		} else {
+			pairs[i] = fmt.Sprintf("%s=%v", key, attrs[key])
+		}
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tracetesting_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/tracetesting"
+)
+
+type checkerSuite struct{}
+
+var _ = gc.Suite(&checkerSuite{})
+
+var requestSpans = []tracetesting.Span{{
+	TraceID:    "t1",
+	SpanID:     "a",
+	Name:       "GET /users",
+	Kind:       tracetesting.SpanKindServer,
+	Status:     tracetesting.StatusOK,
+	Attributes: map[string]interface{}{"http.method": "GET", "http.status_code": int64(200)},
+}, {
+	TraceID:      "t1",
+	SpanID:       "b",
+	ParentSpanID: "a",
+	Name:         "db.query",
+	Attributes:   map[string]interface{}{"db.system": "postgresql"},
+}, {
+	TraceID:      "t1",
+	SpanID:       "c",
+	ParentSpanID: "a",
+	Name:         "db.query",
+	Status:       tracetesting.StatusError,
+}, {
+	TraceID:      "t2",
+	SpanID:       "d",
+	ParentSpanID: "x",
+	Name:         "consume",
+}}
+
+func (*checkerSuite) TestHasSpans(c *gc.C) {
+	c.Check(requestSpans, tracetesting.HasSpans, []tracetesting.ExpectedSpan{
+		{Name: "GET /users", Root: true, Kind: tracetesting.SpanKindServer, Status: tracetesting.StatusOK,
+			Attributes: map[string]interface{}{"http.status_code": 200}},
+		{Name: "db.query", Parent: "GET /users", Status: tracetesting.StatusError},
+		{Name: "db.query", Parent: "GET /users", Attributes: map[string]interface{}{"db.system": "postgresql"}},
+	})
+	c.Check(requestSpans, tracetesting.HasSpans, []tracetesting.ExpectedSpan{
+		{Name: "db.query", Parent: "GET /users"},
+		{Name: "db.query", Parent: "GET /users"},
+	})
+
+	collector := tracetesting.NewCollector()
+	defer collector.Close()
+	collector.Add(requestSpans...)
+	c.Check(collector, tracetesting.HasSpans, []tracetesting.ExpectedSpan{{Name: "consume"}})
+}
+
+var hasSpansFailureTests = []struct {
+	about    string
+	expected []tracetesting.ExpectedSpan
+	msg      string
+}{{
+	about: "wrong parent",
+	expected: []tracetesting.ExpectedSpan{
+		{Name: "db.query", Parent: "consume"},
+	},
+	msg: `no span matches "db.query" \(parent "consume"\)
+spans received:
+  GET /users status=OK {http.method="GET", http.status_code=200}
+    db.query {db.system="postgresql"}
+    db.query status=Error
+  consume \(parent x not received\)
+`,
+}, {
+	about: "attributes and root",
+	expected: []tracetesting.ExpectedSpan{
+		{Name: "GET /users", Attributes: map[string]interface{}{"http.status_code": 500}},
+		{Name: "consume", Root: true},
+	},
+	msg: `no span matches "GET /users" \(attributes {http.status_code=500}\)
+no span matches "consume" \(root\)
+spans received:
+.*`,
+}, {
+	about: "too few spans",
+	expected: []tracetesting.ExpectedSpan{
+		{Name: "consume"},
+		{Name: "consume"},
+	},
+	msg: `expected spans do not each match a different span
+spans received:
+.*`,
+}}
+
+func (*checkerSuite) TestHasSpansFailure(c *gc.C) {
+	for i, test := range hasSpansFailureTests {
+		c.Logf("test %d: %s", i, test.about)
+		result, msg := tracetesting.HasSpans.Check([]interface{}{requestSpans, test.expected}, nil)
+		c.Check(result, jc.IsFalse)
+		c.Check(msg, gc.Matches, `(?s)`+test.msg)
+	}
+}
+
+func (*checkerSuite) TestNoSpans(c *gc.C) {
+	result, msg := tracetesting.HasSpans.Check([]interface{}{[]tracetesting.Span(nil), []tracetesting.ExpectedSpan{{Name: "x"}}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, "no span matches \"x\"\nspans received:\n  (none)\n")
+}
+
+func (*checkerSuite) TestBadParams(c *gc.C) {
+	result, msg := tracetesting.HasSpans.Check([]interface{}{"foo", []tracetesting.ExpectedSpan{}}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, "obtained value must be *tracetesting.Collector or []tracetesting.Span, got string")
+
+	result, msg = tracetesting.HasSpans.Check([]interface{}{requestSpans, "foo"}, nil)
+	c.Assert(result, jc.IsFalse)
+	c.Assert(msg, gc.Equals, "expected value must be []tracetesting.ExpectedSpan, got string")
+}
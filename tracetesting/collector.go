@@ -0,0 +1,199 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package tracetesting provides an in-memory OpenTelemetry collector,
+// and checkers for the spans exported to it, so that tracing
+// instrumentation can be tested.
+//
+// The collector receives spans over OTLP/HTTP, so code under test can
+// export to it with the OpenTelemetry SDK's otlptracehttp exporter,
+// without this module depending on the SDK:
+//
+//	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(s.Collector.URL))
+//	c.Assert(err, jc.ErrorIsNil)
+//	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+//	...
+//	c.Check(s.Collector, tracetesting.HasSpans, []tracetesting.ExpectedSpan{
+//		{Name: "GET /users", Root: true, Status: tracetesting.StatusOK},
+//		{Name: "db.query", Parent: "GET /users", Attributes: map[string]interface{}{"db.system": "postgresql"}},
+//	})
+package tracetesting
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	gc "gopkg.in/check.v1"
+)
+
+// SpanKind holds the kind of a span, as defined by OTLP.
+type SpanKind int
+
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+// StatusCode holds the status of a span, as defined by OTLP.
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+func (code StatusCode) String() string {
+	switch code {
+	case StatusUnset:
+		return "Unset"
+	case StatusOK:
+		return "OK"
+	case StatusError:
+		return "Error"
+	}
+	return fmt.Sprintf("StatusCode(%d)", int(code))
+}
+
+// Span holds a span received by a Collector.
+type Span struct {
+	// TraceID, SpanID and ParentSpanID hold the IDs of the span, in
+	// hex. ParentSpanID is empty for a root span.
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+
+	Name  string
+	Kind  SpanKind
+	Start time.Time
+	End   time.Time
+
+	// Attributes holds the attributes of the span. Their values are
+	// strings, bools, int64s, float64s, []bytes, []interface{}s or
+	// map[string]interface{}s.
+	Attributes map[string]interface{}
+
+	Status        StatusCode
+	StatusMessage string
+
+	// Scope holds the name of the instrumentation scope, the name
+	// given to the tracer that created the span.
+	Scope string
+
+	// Resource holds the attributes of the resource that produced
+	// the span, such as "service.name".
+	Resource map[string]interface{}
+}
+
+// Collector is an in-memory OpenTelemetry collector, which records the
+// spans exported to it over OTLP/HTTP with protobuf encoding.
+type Collector struct {
+	// URL holds the URL to which spans should be exported.
+	URL string
+
+	server *httptest.Server
+
+	mu    sync.Mutex
+	spans []Span
+}
+
+// NewCollector starts a collector. It should be closed after use.
+func NewCollector() *Collector {
+	collector := &Collector{}
+	collector.server = httptest.NewServer(http.HandlerFunc(collector.serveTraces))
+	collector.URL = collector.server.URL + "/v1/traces"
+	return collector
+}
+
+// Close stops the collector.
+func (collector *Collector) Close() {
+	collector.server.Close()
+}
+
+// Spans returns the spans received so far, in the order in which they
+// were received.
+func (collector *Collector) Spans() []Span {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	return append([]Span(nil), collector.spans...)
+}
+
+// Reset discards the spans received so far.
+func (collector *Collector) Reset() {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.spans = nil
+}
+
+// Add records spans as if they had been exported to the collector.
+func (collector *Collector) Add(spans ...Span) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.spans = append(collector.spans, spans...)
+}
+
+func (collector *Collector) serveTraces(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" || req.URL.Path != "/v1/traces" {
+		http.NotFound(w, req)
+		return
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/x-protobuf" {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", ct), http.StatusUnsupportedMediaType)
+		return
+	}
+	var body io.Reader = req.Body
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	spans, err := decodeExportRequest(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	collector.Add(spans...)
+	// An empty ExportTraceServiceResponse reports success.
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+// Suite starts a fresh Collector for each test. It is intended to be
+// embedded in a gocheck suite type, which should call its SetUpTest
+// and TearDownTest methods.
+type Suite struct {
+	// Collector holds the collector for the current test.
+	Collector *Collector
+}
+
+func (s *Suite) SetUpSuite(c *gc.C) {}
+
+func (s *Suite) TearDownSuite(c *gc.C) {}
+
+func (s *Suite) SetUpTest(c *gc.C) {
+	s.Collector = NewCollector()
+}
+
+func (s *Suite) TearDownTest(c *gc.C) {
+	if s.Collector != nil {
+		s.Collector.Close()
+		s.Collector = nil
+	}
+}
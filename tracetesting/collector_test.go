@@ -0,0 +1,175 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tracetesting_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"math"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	gc "gopkg.in/check.v1"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/tracetesting"
+)
+
+type collectorSuite struct {
+	tracetesting.Suite
+}
+
+var _ = gc.Suite(&collectorSuite{})
+
+// The functions below encode OTLP messages as the SDK's exporter does.
+
+func message(fields ...[]byte) []byte {
+	return bytes.Join(fields, nil)
+}
+
+func bytesField(num protowire.Number, b []byte) []byte {
+	data := protowire.AppendTag(nil, num, protowire.BytesType)
+	return protowire.AppendBytes(data, b)
+}
+
+func varintField(num protowire.Number, v uint64) []byte {
+	data := protowire.AppendTag(nil, num, protowire.VarintType)
+	return protowire.AppendVarint(data, v)
+}
+
+func fixed64Field(num protowire.Number, v uint64) []byte {
+	data := protowire.AppendTag(nil, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(data, v)
+}
+
+func keyValue(key string, value []byte) []byte {
+	return bytesField(9, message(bytesField(1, []byte(key)), bytesField(2, value)))
+}
+
+func stringValue(s string) []byte {
+	return bytesField(1, []byte(s))
+}
+
+func intValue(i int64) []byte {
+	return varintField(3, uint64(i))
+}
+
+var (
+	traceID  = []byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	rootID   = []byte{0, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	childID  = []byte{1, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	start    = time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	exported = message(
+		bytesField(1, message(
+			bytesField(2, message(
+				bytesField(2, message(
+					bytesField(1, traceID),
+					bytesField(2, childID),
+					bytesField(4, rootID),
+					bytesField(5, []byte("db.query")),
+					varintField(6, uint64(tracetesting.SpanKindClient)),
+					fixed64Field(7, uint64(start.Add(time.Millisecond).UnixNano())),
+					fixed64Field(8, uint64(start.Add(2*time.Millisecond).UnixNano())),
+					keyValue("db.system", stringValue("postgresql")),
+					keyValue("db.rows", intValue(3)),
+					keyValue("db.ratio", fixed64Field(4, math.Float64bits(0.5))),
+					keyValue("db.cached", varintField(2, 1)),
+					keyValue("db.tables", bytesField(5, message(
+						bytesField(1, stringValue("users")),
+						bytesField(1, stringValue("groups")),
+					))),
+				)),
+				bytesField(2, message(
+					bytesField(1, traceID),
+					bytesField(2, rootID),
+					bytesField(5, []byte("GET /users")),
+					varintField(6, uint64(tracetesting.SpanKindServer)),
+					fixed64Field(7, uint64(start.UnixNano())),
+					fixed64Field(8, uint64(start.Add(3*time.Millisecond).UnixNano())),
+					keyValue("http.method", stringValue("GET")),
+					bytesField(15, message(
+						bytesField(2, []byte("all good")),
+						varintField(3, uint64(tracetesting.StatusOK)),
+					)),
+				)),
+				// The scope follows the spans.
+				bytesField(1, message(bytesField(1, []byte("example.com/api")))),
+			)),
+			bytesField(1, message(
+				bytesField(1, message(bytesField(1, []byte("service.name")), bytesField(2, stringValue("api")))),
+			)),
+		)),
+	)
+)
+
+func (s *collectorSuite) post(c *gc.C, body []byte, header http.Header) *http.Response {
+	req, err := http.NewRequest("POST", s.Collector.URL, bytes.NewReader(body))
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header = header
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	return resp
+}
+
+func (s *collectorSuite) TestExport(c *gc.C) {
+	resp := s.post(c, exported, http.Header{"Content-Type": {"application/x-protobuf"}})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	resource := map[string]interface{}{"service.name": "api"}
+	c.Assert(s.Collector.Spans(), jc.DeepEquals, []tracetesting.Span{{
+		TraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:       "01f067aa0ba902b7",
+		ParentSpanID: "00f067aa0ba902b7",
+		Name:         "db.query",
+		Kind:         tracetesting.SpanKindClient,
+		Start:        start.Add(time.Millisecond),
+		End:          start.Add(2 * time.Millisecond),
+		Attributes: map[string]interface{}{
+			"db.system": "postgresql",
+			"db.rows":   int64(3),
+			"db.ratio":  0.5,
+			"db.cached": true,
+			"db.tables": []interface{}{"users", "groups"},
+		},
+		Scope:    "example.com/api",
+		Resource: resource,
+	}, {
+		TraceID:       "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:        "00f067aa0ba902b7",
+		Name:          "GET /users",
+		Kind:          tracetesting.SpanKindServer,
+		Start:         start,
+		End:           start.Add(3 * time.Millisecond),
+		Attributes:    map[string]interface{}{"http.method": "GET"},
+		Status:        tracetesting.StatusOK,
+		StatusMessage: "all good",
+		Scope:         "example.com/api",
+		Resource:      resource,
+	}})
+
+	s.Collector.Reset()
+	c.Assert(s.Collector.Spans(), gc.HasLen, 0)
+}
+
+func (s *collectorSuite) TestExportGzip(c *gc.C) {
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	zw.Write(exported)
+	zw.Close()
+	resp := s.post(c, body.Bytes(), http.Header{
+		"Content-Type":     {"application/x-protobuf"},
+		"Content-Encoding": {"gzip"},
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(s.Collector.Spans(), gc.HasLen, 2)
+}
+
+func (s *collectorSuite) TestBadRequests(c *gc.C) {
+	resp := s.post(c, []byte("{}"), http.Header{"Content-Type": {"application/json"}})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusUnsupportedMediaType)
+	resp = s.post(c, []byte{0xff}, http.Header{"Content-Type": {"application/x-protobuf"}})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusBadRequest)
+	c.Assert(s.Collector.Spans(), gc.HasLen, 0)
+}
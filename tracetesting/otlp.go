@@ -0,0 +1,281 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tracetesting
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The field numbers of the OTLP trace protocol messages decoded here,
+// from opentelemetry/proto/collector/trace/v1/trace_service.proto and
+// the messages it refers to. Other fields are skipped.
+const (
+	exportRequestResourceSpans = 1
+
+	resourceSpansResource   = 1
+	resourceSpansScopeSpans = 2
+
+	resourceAttributes = 1
+
+	scopeSpansScope = 1
+	scopeSpansSpans = 2
+
+	scopeName = 1
+
+	spanTraceID      = 1
+	spanSpanID       = 2
+	spanParentSpanID = 4
+	spanName         = 5
+	spanKind         = 6
+	spanStartTime    = 7
+	spanEndTime      = 8
+	spanAttributes   = 9
+	spanStatus       = 15
+
+	statusMessage = 2
+	statusCode    = 3
+
+	keyValueKey   = 1
+	keyValueValue = 2
+
+	anyValueString = 1
+	anyValueBool   = 2
+	anyValueInt    = 3
+	anyValueDouble = 4
+	anyValueArray  = 5
+	anyValueKVList = 6
+	anyValueBytes  = 7
+
+	arrayValues  = 1
+	kvListValues = 1
+)
+
+// field holds a field of a protobuf message. Bytes holds the value of
+// a length-delimited field, and Int the value of any other field.
+type field struct {
+	num   protowire.Number
+	bytes []byte
+	int   uint64
+}
+
+// decodeFields calls f for each field of the encoded protobuf message
+// b, stopping at the first error.
+func decodeFields(b []byte, f func(field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		fld := field{num: num}
+		switch typ {
+		case protowire.VarintType:
+			fld.int, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			fld.int, n = protowire.ConsumeFixed64(b)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(b)
+			fld.int = uint64(v)
+		case protowire.BytesType:
+			fld.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := f(fld); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeExportRequest decodes the spans in an encoded
+// ExportTraceServiceRequest.
+func decodeExportRequest(b []byte) ([]Span, error) {
+	var spans []Span
+	err := decodeFields(b, func(f field) error {
+		if f.num != exportRequestResourceSpans {
+			return nil
+		}
+		resourceSpans, err := decodeResourceSpans(f.bytes)
+		spans = append(spans, resourceSpans...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode OTLP trace request: %v", err)
+	}
+	return spans, nil
+}
+
+func decodeResourceSpans(b []byte) ([]Span, error) {
+	var resource map[string]interface{}
+	var scopeSpans [][]byte
+	err := decodeFields(b, func(f field) error {
+		switch f.num {
+		case resourceSpansResource:
+			return decodeFields(f.bytes, func(f field) error {
+				if f.num != resourceAttributes {
+					return nil
+				}
+				if resource == nil {
+					resource = make(map[string]interface{})
+				}
+				return decodeKeyValue(f.bytes, resource)
+			})
+		case resourceSpansScopeSpans:
+			// The resource may follow the spans, so decode them
+			// once it is known.
+			scopeSpans = append(scopeSpans, f.bytes)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var spans []Span
+	for _, b := range scopeSpans {
+		// The scope may also follow the spans.
+		var scope string
+		var scoped []Span
+		err := decodeFields(b, func(f field) error {
+			switch f.num {
+			case scopeSpansScope:
+				return decodeFields(f.bytes, func(f field) error {
+					if f.num == scopeName {
+						scope = string(f.bytes)
+					}
+					return nil
+				})
+			case scopeSpansSpans:
+				span, err := decodeSpan(f.bytes)
+				scoped = append(scoped, span)
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, span := range scoped {
+			span.Scope = scope
+			span.Resource = resource
+			spans = append(spans, span)
+		}
+	}
+	return spans, nil
+}
+
+func decodeSpan(b []byte) (Span, error) {
+	var span Span
+	err := decodeFields(b, func(f field) error {
+		switch f.num {
+		case spanTraceID:
+			span.TraceID = hex.EncodeToString(f.bytes)
+		case spanSpanID:
+			span.SpanID = hex.EncodeToString(f.bytes)
+		case spanParentSpanID:
+			span.ParentSpanID = hex.EncodeToString(f.bytes)
+		case spanName:
+			span.Name = string(f.bytes)
+		case spanKind:
+			span.Kind = SpanKind(f.int)
+		case spanStartTime:
+			span.Start = time.Unix(0, int64(f.int))
+		case spanEndTime:
+			span.End = time.Unix(0, int64(f.int))
+		case spanAttributes:
+			if span.Attributes == nil {
+				span.Attributes = make(map[string]interface{})
+			}
+			return decodeKeyValue(f.bytes, span.Attributes)
+		case spanStatus:
+			return decodeFields(f.bytes, func(f field) error {
+				switch f.num {
+				case statusMessage:
+					span.StatusMessage = string(f.bytes)
+				case statusCode:
+					span.Status = StatusCode(f.int)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	return span, err
+}
+
+// decodeKeyValue decodes an encoded KeyValue into attrs.
+func decodeKeyValue(b []byte, attrs map[string]interface{}) error {
+	var key string
+	var value interface{}
+	err := decodeFields(b, func(f field) error {
+		switch f.num {
+		case keyValueKey:
+			key = string(f.bytes)
+		case keyValueValue:
+			v, err := decodeAnyValue(f.bytes)
+			value = v
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	attrs[key] = value
+	return nil
+}
+
+// decodeAnyValue decodes an encoded AnyValue into a string, bool,
+// int64, float64, []byte, []interface{} or map[string]interface{}.
+func decodeAnyValue(b []byte) (interface{}, error) {
+	var value interface{}
+	err := decodeFields(b, func(f field) error {
+		switch f.num {
+		case anyValueString:
+			value = string(f.bytes)
+		case anyValueBool:
+			value = f.int != 0
+		case anyValueInt:
+			value = int64(f.int)
+		case anyValueDouble:
+			value = math.Float64frombits(f.int)
+		case anyValueBytes:
+			value = append([]byte{}, f.bytes...)
+		case anyValueArray:
+			values := []interface{}{}
+			err := decodeFields(f.bytes, func(f field) error {
+				if f.num != arrayValues {
+					return nil
+				}
+				v, err := decodeAnyValue(f.bytes)
+				values = append(values, v)
+				return err
+			})
+			value = values
+			return err
+		case anyValueKVList:
+			values := make(map[string]interface{})
+			err := decodeFields(f.bytes, func(f field) error {
+				if f.num != kvListValues {
+					return nil
+				}
+				return decodeKeyValue(f.bytes, values)
+			})
+			value = values
+			return err
+		}
+		return nil
+	})
+	return value, err
+}
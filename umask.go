@@ -0,0 +1,29 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"os"
+)
+
+// PatchUmask sets the process umask to mask and returns a function
+// that restores the original umask. It does nothing on windows, which
+// has no umask.
+func PatchUmask(mask os.FileMode) Restorer {
+	oldMask := currentUmask()
+	setUmask(int(mask.Perm()))
+	var id int
+	restored := false
+	restore := func() {
+		if restored {
+			return
+		}
+		restored = true
+		activePatches.remove(id)
+		setUmask(oldMask)
+	}
+	id = activePatches.add(fmt.Sprintf("umask patched at %s", patchCaller()), restore)
+	return restore
+}
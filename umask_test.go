@@ -0,0 +1,50 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !windows
+
+package testing_test
+
+import (
+	"os"
+	"syscall"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+)
+
+type umaskSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&umaskSuite{})
+
+func umask() int {
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+	return mask
+}
+
+func (s *umaskSuite) TestPatchUmask(c *gc.C) {
+	old := umask()
+	restore := testing.PatchUmask(os.FileMode(old ^ 0077))
+	c.Assert(umask(), gc.Equals, old^0077)
+	restore()
+	c.Assert(umask(), gc.Equals, old)
+	// Restoring again does nothing.
+	restore()
+	c.Assert(umask(), gc.Equals, old)
+}
+
+func (s *umaskSuite) TestCleanupSuitePatchUmask(c *gc.C) {
+	old := umask()
+	var suite testing.CleanupSuite
+	suite.SetUpSuite(c)
+	suite.SetUpTest(c)
+	suite.PatchUmask(0)
+	c.Assert(umask(), gc.Equals, 0)
+	suite.TearDownTest(c)
+	suite.TearDownSuite(c)
+	c.Assert(umask(), gc.Equals, old)
+}
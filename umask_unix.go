@@ -0,0 +1,23 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+//go:build !windows
+
+package testing
+
+import (
+	"syscall"
+)
+
+// currentUmask returns the process umask. There is no way of reading
+// the umask without setting it, so it is briefly set to zero.
+func currentUmask() int {
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+	return mask
+}
+
+// setUmask sets the process umask.
+func setUmask(mask int) {
+	syscall.Umask(mask)
+}
@@ -0,0 +1,13 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+// currentUmask always returns zero, as there is no umask on windows.
+func currentUmask() int {
+	return 0
+}
+
+// setUmask does nothing, as there is no umask on windows.
+func setUmask(mask int) {
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+
+	gc "gopkg.in/check.v1"
+)
+
+// UserHome describes a temporary home directory set up by
+// PatchUserHome.
+type UserHome struct {
+	// Dir holds the path of the home directory.
+	Dir string
+
+	// ConfigDir, DataDir, CacheDir and StateDir hold the values
+	// of XDG_CONFIG_HOME, XDG_DATA_HOME, XDG_CACHE_HOME and
+	// XDG_STATE_HOME, which are the usual locations under Dir.
+	ConfigDir string
+	DataDir   string
+	CacheDir  string
+	StateDir  string
+
+	// RuntimeDir holds the value of XDG_RUNTIME_DIR, which is a
+	// separate temporary directory.
+	RuntimeDir string
+}
+
+// PatchUserHome creates an empty home directory for the current test
+// and points HOME (USERPROFILE on Windows) and the XDG base directory
+// variables at it, so that os.UserHomeDir, os.UserConfigDir and
+// os.UserCacheDir all return paths inside it.
+//
+// The standard library offers no way to change what user.Current
+// returns, so code that uses it to find the home directory should
+// call it through a package variable that tests patch with
+// UserHome.UserCurrent.
+func PatchUserHome(c *gc.C, patcher EnvironmentPatcher) *UserHome {
+	dir := c.MkDir()
+	h := &UserHome{
+		Dir:        dir,
+		ConfigDir:  filepath.Join(dir, ".config"),
+		DataDir:    filepath.Join(dir, ".local", "share"),
+		CacheDir:   filepath.Join(dir, ".cache"),
+		StateDir:   filepath.Join(dir, ".local", "state"),
+		RuntimeDir: c.MkDir(),
+	}
+	for _, d := range []string{h.ConfigDir, h.DataDir, h.CacheDir, h.StateDir} {
+		err := os.MkdirAll(d, 0700)
+		c.Assert(err, gc.IsNil)
+	}
+	if runtime.GOOS == "windows" {
+		patcher.PatchEnvironment("USERPROFILE", dir)
+		patcher.PatchEnvironment("APPDATA", h.ConfigDir)
+		patcher.PatchEnvironment("LOCALAPPDATA", h.CacheDir)
+	}
+	patcher.PatchEnvironment("HOME", dir)
+	patcher.PatchEnvironment("XDG_CONFIG_HOME", h.ConfigDir)
+	patcher.PatchEnvironment("XDG_DATA_HOME", h.DataDir)
+	patcher.PatchEnvironment("XDG_CACHE_HOME", h.CacheDir)
+	patcher.PatchEnvironment("XDG_STATE_HOME", h.StateDir)
+	patcher.PatchEnvironment("XDG_RUNTIME_DIR", h.RuntimeDir)
+	return h
+}
+
+// UserCurrent has the same signature as user.Current. It returns the
+// current user with HomeDir set to h.Dir. If the current user cannot
+// be determined, a user named "testuser" is returned.
+func (h *UserHome) UserCurrent() (*user.User, error) {
+	u := &user.User{
+		Uid:      "1000",
+		Gid:      "1000",
+		Username: "testuser",
+	}
+	if current, err := user.Current(); err == nil {
+		copied := *current
+		u = &copied
+	}
+	u.HomeDir = h.Dir
+	return u, nil
+}
+
+// Path returns the path of the given slash-separated path relative
+// to the home directory.
+func (h *UserHome) Path(path string) string {
+	return filepath.Join(h.Dir, filepath.FromSlash(path))
+}
+
+// AddFiles writes the given files, whose names are slash-separated
+// paths relative to the home directory, creating any missing parent
+// directories first.
+func (h *UserHome) AddFiles(c *gc.C, files ...TestFile) {
+	for _, f := range files {
+		writeHomeFile(c, h.Path(f.Name), f.Data, 0600)
+	}
+}
+
+// AddConfigFile writes a file at the given slash-separated path under
+// the application's directory in ConfigDir, for example
+// AddConfigFile(c, "juju", "credentials.yaml", data) writes
+// ~/.config/juju/credentials.yaml. It returns the path of the file.
+func (h *UserHome) AddConfigFile(c *gc.C, app, path, data string) string {
+	return writeHomeFile(c, filepath.Join(h.ConfigDir, app, filepath.FromSlash(path)), data, 0600)
+}
+
+// AddSSHFile writes a file with the given name to ~/.ssh, with the
+// restrictive permissions that ssh requires, and returns its path.
+func (h *UserHome) AddSSHFile(c *gc.C, name, data string) string {
+	return writeHomeFile(c, filepath.Join(h.Dir, ".ssh", name), data, 0600)
+}
+
+func writeHomeFile(c *gc.C, path, data string, perm os.FileMode) string {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	c.Assert(err, gc.IsNil)
+	err = ioutil.WriteFile(path, []byte(data), perm)
+	c.Assert(err, gc.IsNil)
+	return path
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type userHomeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&userHomeSuite{})
+
+func (s *userHomeSuite) TestPatchUserHome(c *gc.C) {
+	home := s.PatchUserHome(c)
+
+	dir, err := os.UserHomeDir()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dir, gc.Equals, home.Dir)
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		configDir, err := os.UserConfigDir()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(configDir, gc.Equals, filepath.Join(home.Dir, ".config"))
+		c.Assert(os.Getenv("XDG_DATA_HOME"), gc.Equals, filepath.Join(home.Dir, ".local", "share"))
+	}
+	c.Assert(home.ConfigDir, jc.IsDirectory)
+	c.Assert(home.CacheDir, jc.IsDirectory)
+	c.Assert(home.RuntimeDir, jc.IsDirectory)
+
+	u, err := home.UserCurrent()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.HomeDir, gc.Equals, home.Dir)
+	c.Assert(u.Username, gc.Not(gc.Equals), "")
+}
+
+func (s *userHomeSuite) TestPatchUserHomeRestored(c *gc.C) {
+	oldHome := os.Getenv("HOME")
+	restore := testing.PatchEnvironment("XDG_CONFIG_HOME", "/somewhere")
+	defer restore()
+
+	var suite testing.CleanupSuite
+	suite.SetUpSuite(c)
+	suite.SetUpTest(c)
+	suite.PatchUserHome(c)
+	c.Assert(os.Getenv("HOME"), gc.Not(gc.Equals), oldHome)
+	suite.TearDownTest(c)
+	suite.TearDownSuite(c)
+
+	c.Assert(os.Getenv("HOME"), gc.Equals, oldHome)
+	c.Assert(os.Getenv("XDG_CONFIG_HOME"), gc.Equals, "/somewhere")
+}
+
+func (s *userHomeSuite) TestAddFiles(c *gc.C) {
+	home := s.PatchUserHome(c)
+	home.AddFiles(c, testing.TestFile{Name: ".juju/environments.yaml", Data: "default: x\n"})
+	configPath := home.AddConfigFile(c, "juju", "clouds/local.yaml", "clouds: {}\n")
+	sshPath := home.AddSSHFile(c, "id_ed25519", "private key\n")
+
+	c.Assert(configPath, gc.Equals, filepath.Join(home.ConfigDir, "juju", "clouds", "local.yaml"))
+	c.Assert(sshPath, gc.Equals, home.Path(".ssh/id_ed25519"))
+	for path, data := range map[string]string{
+		home.Path(".juju/environments.yaml"): "default: x\n",
+		configPath:                           "clouds: {}\n",
+		sshPath:                              "private key\n",
+	} {
+		got, err := ioutil.ReadFile(path)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(string(got), gc.Equals, data)
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(sshPath)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+		info, err = os.Stat(filepath.Dir(sshPath))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0700))
+	}
+}
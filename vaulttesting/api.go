@@ -0,0 +1,468 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package vaulttesting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kvMount holds the path at which the key/value secrets engine is
+// mounted.
+const kvMount = "secret/"
+
+// apiError is an error response from the API.
+type apiError struct {
+	status int
+	errors []string
+}
+
+func errorf(status int, format string, args ...interface{}) *apiError {
+	return &apiError{status: status, errors: []string{fmt.Sprintf(format, args...)}}
+}
+
+// errNotFound is returned for secrets and paths that do not exist;
+// Vault's response holds no error messages.
+var errNotFound = &apiError{status: http.StatusNotFound}
+
+var errPermissionDenied = errorf(http.StatusForbidden, "permission denied")
+
+func (srv *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !strings.HasPrefix(req.URL.Path, "/v1/") {
+		writeError(w, errNotFound)
+		return
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/v1/")
+	if f, ok := srv.takeFault(path); ok {
+		if f.Delay > 0 {
+			select {
+			case <-time.After(f.Delay):
+			case <-req.Context().Done():
+				return
+			}
+		}
+		if f.Status != 0 {
+			writeError(w, &apiError{status: f.Status, errors: f.Errors})
+			return
+		}
+	}
+	method := req.Method
+	if method == "GET" && req.URL.Query().Get("list") == "true" {
+		method = "LIST"
+	}
+	var body map[string]json.RawMessage
+	if method == "POST" || method == "PUT" {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil && err != io.EOF {
+			writeError(w, errorf(http.StatusBadRequest, "failed to parse JSON input: %v", err))
+			return
+		}
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	tok, ok := srv.validToken(requestToken(req))
+	if !ok {
+		writeError(w, errPermissionDenied)
+		return
+	}
+	resp, err := srv.serve(method, path, req, body, tok)
+	switch {
+	case err != nil:
+		writeError(w, err)
+	case resp == nil:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// requestToken returns the token that authenticates the request.
+func requestToken(req *http.Request) string {
+	if t := req.Header.Get("X-Vault-Token"); t != "" {
+		return t
+	}
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
+
+// serve serves a request for the given API path and returns the
+// response body, or nil if the response has no content. It is called
+// with srv.mu held.
+func (srv *Server) serve(method, path string, req *http.Request, body map[string]json.RawMessage, tok *token) (map[string]interface{}, *apiError) {
+	write := method == "POST" || method == "PUT"
+	switch {
+	case path == "auth/token/create" && write:
+		return srv.tokenCreate(body)
+	case path == "auth/token/lookup-self" && method == "GET":
+		return map[string]interface{}{"data": srv.tokenData(tok)}, nil
+	case path == "auth/token/renew-self" && write:
+		return srv.tokenRenew(body, tok)
+	case path == "auth/token/revoke-self" && write:
+		delete(srv.tokens, tok.id)
+		return nil, nil
+	case path == "sys/leases/lookup" && write:
+		return srv.leaseLookup(body)
+	case path == "sys/leases/renew" && write:
+		return srv.leaseRenew(body)
+	case path == "sys/leases/revoke" && write:
+		return srv.leaseRevoke(body)
+	case strings.HasPrefix(path, kvMount+"data/"):
+		name := strings.TrimPrefix(path, kvMount+"data/")
+		switch {
+		case method == "GET":
+			return srv.kvRead(name, req.URL.Query().Get("version"))
+		case write:
+			return srv.kvWrite(name, body)
+		case method == "DELETE":
+			return srv.kvDelete(name)
+		}
+	case strings.HasPrefix(path, kvMount+"metadata/") || path == kvMount+"metadata":
+		name := strings.TrimPrefix(strings.TrimPrefix(path, kvMount+"metadata"), "/")
+		switch method {
+		case "LIST":
+			return srv.kvList(name)
+		case "GET":
+			return srv.kvMetadata(name)
+		case "DELETE":
+			return srv.kvDestroy(name)
+		}
+	case method == "GET":
+		if s, ok := srv.leased[path]; ok {
+			return srv.readLeased(path, s)
+		}
+	}
+	return nil, errNotFound
+}
+
+func (srv *Server) tokenCreate(body map[string]json.RawMessage) (map[string]interface{}, *apiError) {
+	ttl, err := parseTTL(body["ttl"])
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid ttl: %v", err)
+	}
+	renewable := true
+	if raw, ok := body["renewable"]; ok {
+		if err := json.Unmarshal(raw, &renewable); err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid renewable: %v", err)
+		}
+	}
+	id, e := randomID()
+	if e != nil {
+		return nil, errorf(http.StatusInternalServerError, "%v", e)
+	}
+	t := srv.createToken(id, ttl, renewable)
+	return map[string]interface{}{"auth": srv.tokenAuth(t)}, nil
+}
+
+func (srv *Server) tokenRenew(body map[string]json.RawMessage, t *token) (map[string]interface{}, *apiError) {
+	if !t.renewable || t.ttl == 0 {
+		return nil, errorf(http.StatusBadRequest, "lease is not renewable")
+	}
+	increment, err := parseTTL(body["increment"])
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid increment: %v", err)
+	}
+	if increment == 0 {
+		increment = t.ttl
+	}
+	t.expiry = srv.now().Add(increment)
+	return map[string]interface{}{"auth": srv.tokenAuth(t)}, nil
+}
+
+// tokenAuth returns the auth section of a response issuing or renewing
+// the token.
+func (srv *Server) tokenAuth(t *token) map[string]interface{} {
+	return map[string]interface{}{
+		"client_token":   t.id,
+		"policies":       []string{"default"},
+		"lease_duration": srv.remaining(t.expiry),
+		"renewable":      t.renewable && t.ttl > 0,
+	}
+}
+
+func (srv *Server) tokenData(t *token) map[string]interface{} {
+	data := map[string]interface{}{
+		"id":          t.id,
+		"policies":    []string{"default"},
+		"ttl":         srv.remaining(t.expiry),
+		"renewable":   t.renewable && t.ttl > 0,
+		"expire_time": nil,
+	}
+	if !t.expiry.IsZero() {
+		data["expire_time"] = formatTime(t.expiry)
+	}
+	return data
+}
+
+func (srv *Server) kvRead(name, versionParam string) (map[string]interface{}, *apiError) {
+	s := srv.secrets[name]
+	if s == nil {
+		return nil, errNotFound
+	}
+	n := len(s.versions)
+	if versionParam != "" && versionParam != "0" {
+		var err error
+		if n, err = strconv.Atoi(versionParam); err != nil || n < 1 {
+			return nil, errorf(http.StatusBadRequest, "invalid version %q", versionParam)
+		}
+		if n > len(s.versions) {
+			return nil, errNotFound
+		}
+	}
+	v := s.versions[n-1]
+	if !v.deleted.IsZero() {
+		return nil, errNotFound
+	}
+	srv.accesses = append(srv.accesses, Access{Op: "read", Path: kvMount + name})
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"data":     v.data,
+			"metadata": versionMetadata(v, n),
+		},
+	}, nil
+}
+
+func (srv *Server) kvWrite(name string, body map[string]json.RawMessage) (map[string]interface{}, *apiError) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(body["data"], &data); err != nil || data == nil {
+		return nil, errorf(http.StatusBadRequest, "no data provided")
+	}
+	var options struct {
+		CAS *int `json:"cas"`
+	}
+	if raw, ok := body["options"]; ok {
+		if err := json.Unmarshal(raw, &options); err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid options: %v", err)
+		}
+	}
+	current := 0
+	if s := srv.secrets[name]; s != nil {
+		current = len(s.versions)
+	}
+	if options.CAS != nil && *options.CAS != current {
+		return nil, errorf(http.StatusBadRequest, "check-and-set parameter did not match the current version")
+	}
+	v := srv.write(name, data)
+	srv.accesses = append(srv.accesses, Access{Op: "write", Path: kvMount + name})
+	return map[string]interface{}{
+		"data": versionMetadata(v, current+1),
+	}, nil
+}
+
+// kvDelete deletes the latest version of the secret; earlier versions
+// can still be read.
+func (srv *Server) kvDelete(name string) (map[string]interface{}, *apiError) {
+	if s := srv.secrets[name]; s != nil {
+		v := s.versions[len(s.versions)-1]
+		if v.deleted.IsZero() {
+			v.deleted = srv.now()
+		}
+		srv.accesses = append(srv.accesses, Access{Op: "delete", Path: kvMount + name})
+	}
+	return nil, nil
+}
+
+// kvDestroy deletes every version of the secret and its metadata.
+func (srv *Server) kvDestroy(name string) (map[string]interface{}, *apiError) {
+	if _, ok := srv.secrets[name]; ok {
+		delete(srv.secrets, name)
+		srv.accesses = append(srv.accesses, Access{Op: "delete", Path: kvMount + name})
+	}
+	return nil, nil
+}
+
+// kvList lists the secrets and directories immediately under the given
+// directory. Directories are listed with a trailing slash.
+func (srv *Server) kvList(dir string) (map[string]interface{}, *apiError) {
+	if dir != "" && !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+	seen := make(map[string]bool)
+	keys := []string{}
+	for name := range srv.secrets {
+		if !strings.HasPrefix(name, dir) {
+			continue
+		}
+		key := strings.TrimPrefix(name, dir)
+		if i := strings.Index(key, "/"); i >= 0 {
+			key = key[:i+1]
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errNotFound
+	}
+	sort.Strings(keys)
+	srv.accesses = append(srv.accesses, Access{Op: "list", Path: kvMount + dir})
+	return map[string]interface{}{
+		"data": map[string]interface{}{"keys": keys},
+	}, nil
+}
+
+func (srv *Server) kvMetadata(name string) (map[string]interface{}, *apiError) {
+	s := srv.secrets[name]
+	if s == nil {
+		return nil, errNotFound
+	}
+	versions := make(map[string]interface{})
+	for i, v := range s.versions {
+		m := versionMetadata(v, i+1)
+		delete(m, "version")
+		versions[strconv.Itoa(i+1)] = m
+	}
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"current_version": len(s.versions),
+			"oldest_version":  1,
+			"created_time":    formatTime(s.versions[0].created),
+			"updated_time":    formatTime(s.versions[len(s.versions)-1].created),
+			"versions":        versions,
+		},
+	}, nil
+}
+
+func versionMetadata(v *version, n int) map[string]interface{} {
+	deletionTime := ""
+	if !v.deleted.IsZero() {
+		deletionTime = formatTime(v.deleted)
+	}
+	return map[string]interface{}{
+		"version":       n,
+		"created_time":  formatTime(v.created),
+		"deletion_time": deletionTime,
+		"destroyed":     false,
+	}
+}
+
+func (srv *Server) readLeased(path string, s leasedSecret) (map[string]interface{}, *apiError) {
+	id, err := randomID()
+	if err != nil {
+		return nil, errorf(http.StatusInternalServerError, "%v", err)
+	}
+	l := &Lease{
+		ID:     path + "/" + id,
+		Path:   path,
+		Expiry: srv.now().Add(s.ttl),
+	}
+	srv.leases[l.ID] = l
+	srv.accesses = append(srv.accesses, Access{Op: "read", Path: path})
+	return map[string]interface{}{
+		"lease_id":       l.ID,
+		"lease_duration": srv.remaining(l.Expiry),
+		"renewable":      true,
+		"data":           s.data,
+	}, nil
+}
+
+// bodyLease returns the unexpired lease whose ID is given in the
+// request body.
+func (srv *Server) bodyLease(body map[string]json.RawMessage) (*Lease, *apiError) {
+	var id string
+	if err := json.Unmarshal(body["lease_id"], &id); err != nil || id == "" {
+		return nil, errorf(http.StatusBadRequest, "missing lease ID")
+	}
+	l, ok := srv.validLease(id)
+	if !ok {
+		return nil, errorf(http.StatusBadRequest, "lease not found")
+	}
+	return l, nil
+}
+
+func (srv *Server) leaseLookup(body map[string]json.RawMessage) (map[string]interface{}, *apiError) {
+	l, err := srv.bodyLease(body)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"id":          l.ID,
+			"expire_time": formatTime(l.Expiry),
+			"ttl":         srv.remaining(l.Expiry),
+			"renewable":   true,
+		},
+	}, nil
+}
+
+func (srv *Server) leaseRenew(body map[string]json.RawMessage) (map[string]interface{}, *apiError) {
+	l, err := srv.bodyLease(body)
+	if err != nil {
+		return nil, err
+	}
+	increment, e := parseTTL(body["increment"])
+	if e != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid increment: %v", e)
+	}
+	if increment == 0 {
+		increment = srv.leased[l.Path].ttl
+	}
+	l.Expiry = srv.now().Add(increment)
+	return map[string]interface{}{
+		"lease_id":       l.ID,
+		"lease_duration": srv.remaining(l.Expiry),
+		"renewable":      true,
+	}, nil
+}
+
+func (srv *Server) leaseRevoke(body map[string]json.RawMessage) (map[string]interface{}, *apiError) {
+	l, err := srv.bodyLease(body)
+	if err != nil {
+		return nil, err
+	}
+	delete(srv.leases, l.ID)
+	return nil, nil
+}
+
+// remaining returns the number of whole seconds until the given time,
+// or 0 if it is zero.
+func (srv *Server) remaining(t time.Time) int {
+	if t.IsZero() {
+		return 0
+	}
+	return int(t.Sub(srv.now()) / time.Second)
+}
+
+// parseTTL parses a time to live given as a number of seconds or as a
+// duration string such as "1h".
+func parseTTL(raw json.RawMessage) (time.Duration, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+	var seconds int64
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, err
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, e *apiError) {
+	errors := e.errors
+	if errors == nil {
+		errors = []string{}
+	}
+	writeJSON(w, e.status, map[string]interface{}{"errors": errors})
+}
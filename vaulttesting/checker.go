@@ -0,0 +1,50 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package vaulttesting
+
+import (
+	"fmt"
+
+	gc "gopkg.in/check.v1"
+)
+
+type accessChecker struct {
+	*gc.CheckerInfo
+	op   string
+	verb string
+}
+
+// HasRead checks that a secret at the given path, such as "secret/db"
+// or "database/creds/app", has been read from the obtained *Server.
+var HasRead gc.Checker = &accessChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "HasRead", Params: []string{"obtained", "path"}},
+	op:          "read",
+	verb:        "read",
+}
+
+// HasWritten checks that a key/value secret at the given path, such as
+// "secret/db", has been written to the obtained *Server.
+var HasWritten gc.Checker = &accessChecker{
+	CheckerInfo: &gc.CheckerInfo{Name: "HasWritten", Params: []string{"obtained", "path"}},
+	op:          "write",
+	verb:        "written",
+}
+
+func (checker *accessChecker) Check(params []interface{}, names []string) (result bool, error string) {
+	srv, ok := params[0].(*Server)
+	if !ok {
+		return false, fmt.Sprintf("obtained value must be of type *vaulttesting.Server, got %T", params[0])
+	}
+	path, ok := params[1].(string)
+	if !ok {
+		return false, fmt.Sprintf("path must be a string, got %T", params[1])
+	}
+	paths := srv.accessed(checker.op)
+	for _, p := range paths {
+		if p == path {
+			return true, ""
+		}
+	}
+	return false, fmt.Sprintf("secrets %s: %q", checker.verb, paths)
+}
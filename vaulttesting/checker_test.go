@@ -0,0 +1,90 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package vaulttesting_test
+
+import (
+	"net/http"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing/vaulttesting"
+)
+
+type checkerSuite struct{}
+
+var _ = gc.Suite(&checkerSuite{})
+
+var checkerTests = []struct {
+	about   string
+	checker gc.Checker
+	params  func(srv *vaulttesting.Server) []interface{}
+	message string
+}{{
+	about:   "HasRead with a secret that was read",
+	checker: vaulttesting.HasRead,
+	params: func(srv *vaulttesting.Server) []interface{} {
+		return []interface{}{srv, "secret/read"}
+	},
+}, {
+	about:   "HasRead with a secret that was only written",
+	checker: vaulttesting.HasRead,
+	params: func(srv *vaulttesting.Server) []interface{} {
+		return []interface{}{srv, "secret/written"}
+	},
+	message: `secrets read: \["secret/read"\]`,
+}, {
+	about:   "HasWritten with a secret that was written",
+	checker: vaulttesting.HasWritten,
+	params: func(srv *vaulttesting.Server) []interface{} {
+		return []interface{}{srv, "secret/written"}
+	},
+}, {
+	about:   "HasWritten with a secret that was only read",
+	checker: vaulttesting.HasWritten,
+	params: func(srv *vaulttesting.Server) []interface{} {
+		return []interface{}{srv, "secret/read"}
+	},
+	message: `secrets written: \["secret/written"\]`,
+}, {
+	about:   "obtained value of the wrong type",
+	checker: vaulttesting.HasRead,
+	params: func(srv *vaulttesting.Server) []interface{} {
+		return []interface{}{"srv", "secret/read"}
+	},
+	message: `obtained value must be of type \*vaulttesting.Server, got string`,
+}, {
+	about:   "path of the wrong type",
+	checker: vaulttesting.HasWritten,
+	params: func(srv *vaulttesting.Server) []interface{} {
+		return []interface{}{srv, 1}
+	},
+	message: `path must be a string, got int`,
+}}
+
+func (*checkerSuite) TestCheckers(c *gc.C) {
+	srv := vaulttesting.NewServer(c)
+	defer srv.Close()
+	// Seeding is not recorded, so the secrets are read and written
+	// through the API.
+	srv.Write("secret/read", map[string]interface{}{"a": "b"})
+	for _, r := range []struct{ method, path, body string }{
+		{"GET", "/v1/secret/data/read", ""},
+		{"POST", "/v1/secret/data/written", `{"data": {"c": "d"}}`},
+	} {
+		req, err := http.NewRequest(r.method, srv.URL+r.path, strings.NewReader(r.body))
+		c.Assert(err, gc.IsNil)
+		req.Header.Set("X-Vault-Token", srv.RootToken)
+		resp, err := http.DefaultClient.Do(req)
+		c.Assert(err, gc.IsNil)
+		resp.Body.Close()
+		c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	}
+	for i, test := range checkerTests {
+		c.Logf("test %d: %s", i, test.about)
+		result, message := test.checker.Check(test.params(srv), nil)
+		c.Check(result, gc.Equals, test.message == "")
+		c.Check(message, gc.Matches, test.message)
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+// Package vaulttesting provides a fake secrets backend speaking a
+// subset of the HashiCorp Vault HTTP API, for testing code that reads,
+// writes and rotates secrets.
+package vaulttesting
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// Server is a fake Vault server. It serves a version 2 key/value
+// secrets engine mounted at secret/, leased secrets at paths added
+// with AddLeasedSecret, the token auth method and the lease
+// endpoints:
+//
+//	srv := vaulttesting.NewServer(c)
+//	defer srv.Close()
+//	srv.Write("secret/db", map[string]interface{}{"password": "old"})
+//	... configure the code under test with srv.URL and srv.RootToken ...
+//	c.Assert(srv, vaulttesting.HasWritten, "secret/db")
+//
+// Every request must carry a valid token in the X-Vault-Token header,
+// but all tokens may access every path; policies are not enforced.
+//
+// Token and lease expiry times are taken from Clock, so that a test
+// using a testclock.Clock can make them expire.
+type Server struct {
+	*httptest.Server
+
+	// Clock is used to expire tokens and leases. If it is nil,
+	// clock.WallClock is used.
+	Clock clock.Clock
+
+	// RootToken holds a token that never expires.
+	RootToken string
+
+	mu       sync.Mutex
+	secrets  map[string]*secret
+	leased   map[string]leasedSecret
+	tokens   map[string]*token
+	leases   map[string]*Lease
+	faults   []fault
+	accesses []Access
+}
+
+// secret holds the versions of a key/value secret, oldest first.
+type secret struct {
+	versions []*version
+}
+
+type version struct {
+	data    map[string]interface{}
+	created time.Time
+	deleted time.Time
+}
+
+type leasedSecret struct {
+	data map[string]interface{}
+	ttl  time.Duration
+}
+
+type token struct {
+	id        string
+	ttl       time.Duration
+	expiry    time.Time
+	renewable bool
+}
+
+// Lease holds a lease issued by a Server when a leased secret is read.
+type Lease struct {
+	// ID holds the lease ID, which starts with the secret's path.
+	ID string
+
+	// Path holds the path of the secret that was read.
+	Path string
+
+	// Expiry holds the time at which the lease expires.
+	Expiry time.Time
+}
+
+// Access records a successful access to a secret.
+type Access struct {
+	// Op holds the kind of access: "read", "write", "delete" or
+	// "list".
+	Op string
+
+	// Path holds the path of the secret, such as "secret/db" for a
+	// key/value secret, without any "data" or "metadata" segment.
+	Path string
+}
+
+// Fault describes a failure or delay injected with
+// Server.InjectFault.
+type Fault struct {
+	// Delay holds how long to wait before responding. The wait is
+	// abandoned if the client gives up on the request.
+	Delay time.Duration
+
+	// Status holds the HTTP status with which to fail the request,
+	// such as http.StatusServiceUnavailable. If it is zero, the
+	// request is served as usual after the delay.
+	Status int
+
+	// Errors holds the error messages in the failed response.
+	Errors []string
+}
+
+type fault struct {
+	path string
+	Fault
+}
+
+// NewServer starts and returns a new Server with no secrets. The
+// caller should call Close when finished with it.
+func NewServer(c *gc.C) *Server {
+	srv := &Server{
+		RootToken: "root." + randomString(c),
+		secrets:   make(map[string]*secret),
+		leased:    make(map[string]leasedSecret),
+		tokens:    make(map[string]*token),
+		leases:    make(map[string]*Lease),
+	}
+	srv.tokens[srv.RootToken] = &token{id: srv.RootToken}
+	srv.Server = httptest.NewServer(srv)
+	return srv
+}
+
+func (srv *Server) now() time.Time {
+	if srv.Clock == nil {
+		return clock.WallClock.Now()
+	}
+	return srv.Clock.Now()
+}
+
+// CreateToken returns a new renewable token that expires after the
+// given time to live. If ttl is zero, the token never expires.
+func (srv *Server) CreateToken(c *gc.C, ttl time.Duration) string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.createToken(randomString(c), ttl, true).id
+}
+
+func (srv *Server) createToken(id string, ttl time.Duration, renewable bool) *token {
+	t := &token{
+		id:        "s." + id,
+		ttl:       ttl,
+		renewable: renewable,
+	}
+	if ttl > 0 {
+		t.expiry = srv.now().Add(ttl)
+	}
+	srv.tokens[t.id] = t
+	return t
+}
+
+// validToken returns the token with the given ID, if it exists and has
+// not expired.
+func (srv *Server) validToken(id string) (*token, bool) {
+	t, ok := srv.tokens[id]
+	if !ok {
+		return nil, false
+	}
+	if !t.expiry.IsZero() && !srv.now().Before(t.expiry) {
+		delete(srv.tokens, id)
+		return nil, false
+	}
+	return t, true
+}
+
+// Write writes a new version of the key/value secret at the given
+// path, such as "secret/db", so that tests can seed the server. It is
+// not recorded as an access.
+func (srv *Server) Write(path string, data map[string]interface{}) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.write(strings.TrimPrefix(path, kvMount), data)
+}
+
+func (srv *Server) write(name string, data map[string]interface{}) *version {
+	s := srv.secrets[name]
+	if s == nil {
+		s = &secret{}
+		srv.secrets[name] = s
+	}
+	v := &version{
+		data:    copyData(data),
+		created: srv.now(),
+	}
+	s.versions = append(s.versions, v)
+	return v
+}
+
+// Secret returns the data in the latest version of the key/value
+// secret at the given path, and whether that version exists and has
+// not been deleted.
+func (srv *Server) Secret(path string) (map[string]interface{}, bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	s := srv.secrets[strings.TrimPrefix(path, kvMount)]
+	if s == nil {
+		return nil, false
+	}
+	v := s.versions[len(s.versions)-1]
+	if !v.deleted.IsZero() {
+		return nil, false
+	}
+	return copyData(v.data), true
+}
+
+// Versions returns the number of versions of the key/value secret at
+// the given path, including deleted versions.
+func (srv *Server) Versions(path string) int {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if s := srv.secrets[strings.TrimPrefix(path, kvMount)]; s != nil {
+		return len(s.versions)
+	}
+	return 0
+}
+
+// AddLeasedSecret adds a secret at the given path, such as
+// "database/creds/app", outside the key/value engine. Each read of the
+// secret issues a new renewable lease with the given time to live, as
+// Vault does for dynamic secrets.
+func (srv *Server) AddLeasedSecret(path string, data map[string]interface{}, ttl time.Duration) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.leased[path] = leasedSecret{
+		data: copyData(data),
+		ttl:  ttl,
+	}
+}
+
+// Leases returns the leases that have been issued and have neither
+// expired nor been revoked, in order of ID.
+func (srv *Server) Leases() []Lease {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	var leases []Lease
+	for id := range srv.leases {
+		if l, ok := srv.validLease(id); ok {
+			leases = append(leases, *l)
+		}
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].ID < leases[j].ID
+	})
+	return leases
+}
+
+// validLease returns the lease with the given ID, if it exists and has
+// not expired.
+func (srv *Server) validLease(id string) (*Lease, bool) {
+	l, ok := srv.leases[id]
+	if !ok {
+		return nil, false
+	}
+	if !srv.now().Before(l.Expiry) {
+		delete(srv.leases, id)
+		return nil, false
+	}
+	return l, true
+}
+
+// InjectFault causes the next n requests to the given API path, such
+// as "secret/data/db" or "auth/token/renew-self", to be delayed or
+// failed as described by f. If path is empty, requests to any path
+// are affected. Faults are applied in the order they are injected.
+func (srv *Server) InjectFault(path string, n int, f Fault) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for i := 0; i < n; i++ {
+		srv.faults = append(srv.faults, fault{path: path, Fault: f})
+	}
+}
+
+// takeFault removes and returns the first fault injected for the given
+// API path.
+func (srv *Server) takeFault(path string) (Fault, bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for i, f := range srv.faults {
+		if f.path == "" || f.path == path {
+			srv.faults = append(srv.faults[:i:i], srv.faults[i+1:]...)
+			return f.Fault, true
+		}
+	}
+	return Fault{}, false
+}
+
+// Accesses returns the successful accesses to secrets made so far, in
+// order.
+func (srv *Server) Accesses() []Access {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]Access(nil), srv.accesses...)
+}
+
+// ResetAccesses discards the accesses recorded so far.
+func (srv *Server) ResetAccesses() {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.accesses = nil
+}
+
+// accessed returns the paths accessed with the given op, in order,
+// without duplicates.
+func (srv *Server) accessed(op string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, a := range srv.Accesses() {
+		if a.Op == op && !seen[a.Path] {
+			seen[a.Path] = true
+			paths = append(paths, a.Path)
+		}
+	}
+	return paths
+}
+
+func copyData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		c[k] = v
+	}
+	return c
+}
+
+// randomID returns a random string suitable for use as a token or in
+// a lease ID.
+func randomID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func randomString(c *gc.C) string {
+	id, err := randomID()
+	c.Assert(err, gc.IsNil)
+	return id
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package vaulttesting_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+	"github.com/juju/testing/vaulttesting"
+)
+
+type serverSuite struct {
+	srv   *vaulttesting.Server
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&serverSuite{})
+
+func (s *serverSuite) SetUpTest(c *gc.C) {
+	s.srv = vaulttesting.NewServer(c)
+	s.clock = testclock.NewClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	s.srv.Clock = s.clock
+}
+
+func (s *serverSuite) TearDownTest(c *gc.C) {
+	s.srv.Close()
+}
+
+// do makes a request to the API path with the given token, and returns
+// the response status and decoded body.
+func (s *serverSuite) do(c *gc.C, token, method, path, body string) (int, map[string]interface{}) {
+	req, err := http.NewRequest(method, s.srv.URL+"/v1/"+path, strings.NewReader(body))
+	c.Assert(err, gc.IsNil)
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, gc.IsNil)
+	defer resp.Body.Close()
+	var result map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		c.Assert(err, gc.IsNil)
+	}
+	return resp.StatusCode, result
+}
+
+func (s *serverSuite) TestKV(c *gc.C) {
+	root := s.srv.RootToken
+	status, _ := s.do(c, root, "GET", "secret/data/db", "")
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+
+	status, resp := s.do(c, root, "POST", "secret/data/db", `{"data": {"password": "one"}}`)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["data"], jc.DeepEquals, map[string]interface{}{
+		"version":       1.0,
+		"created_time":  "2026-03-01T12:00:00Z",
+		"deletion_time": "",
+		"destroyed":     false,
+	})
+	status, _ = s.do(c, root, "PUT", "secret/data/db", `{"data": {"password": "two"}}`)
+	c.Assert(status, gc.Equals, http.StatusOK)
+
+	status, resp = s.do(c, root, "GET", "secret/data/db", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	data := resp["data"].(map[string]interface{})
+	c.Assert(data["data"], jc.DeepEquals, map[string]interface{}{"password": "two"})
+	c.Assert(data["metadata"].(map[string]interface{})["version"], gc.Equals, 2.0)
+
+	status, resp = s.do(c, root, "GET", "secret/data/db?version=1", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["data"].(map[string]interface{})["data"], jc.DeepEquals, map[string]interface{}{"password": "one"})
+
+	// Deleting removes only the latest version.
+	status, _ = s.do(c, root, "DELETE", "secret/data/db", "")
+	c.Assert(status, gc.Equals, http.StatusNoContent)
+	status, _ = s.do(c, root, "GET", "secret/data/db", "")
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+	_, ok := s.srv.Secret("secret/db")
+	c.Assert(ok, jc.IsFalse)
+	status, _ = s.do(c, root, "GET", "secret/data/db?version=1", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+
+	// Deleting the metadata removes every version.
+	status, _ = s.do(c, root, "DELETE", "secret/metadata/db", "")
+	c.Assert(status, gc.Equals, http.StatusNoContent)
+	c.Assert(s.srv.Versions("secret/db"), gc.Equals, 0)
+
+	c.Assert(s.srv.Accesses(), jc.DeepEquals, []vaulttesting.Access{
+		{Op: "write", Path: "secret/db"},
+		{Op: "write", Path: "secret/db"},
+		{Op: "read", Path: "secret/db"},
+		{Op: "read", Path: "secret/db"},
+		{Op: "delete", Path: "secret/db"},
+		{Op: "read", Path: "secret/db"},
+		{Op: "delete", Path: "secret/db"},
+	})
+	s.srv.ResetAccesses()
+	c.Assert(s.srv.Accesses(), gc.HasLen, 0)
+}
+
+func (s *serverSuite) TestKVCheckAndSet(c *gc.C) {
+	root := s.srv.RootToken
+	status, _ := s.do(c, root, "POST", "secret/data/db", `{"data": {"a": "b"}, "options": {"cas": 1}}`)
+	c.Assert(status, gc.Equals, http.StatusBadRequest)
+	status, _ = s.do(c, root, "POST", "secret/data/db", `{"data": {"a": "b"}, "options": {"cas": 0}}`)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	status, resp := s.do(c, root, "POST", "secret/data/db", `{"data": {"a": "c"}, "options": {"cas": 0}}`)
+	c.Assert(status, gc.Equals, http.StatusBadRequest)
+	c.Assert(resp["errors"], jc.DeepEquals, []interface{}{"check-and-set parameter did not match the current version"})
+	status, _ = s.do(c, root, "POST", "secret/data/db", `{"data": {"a": "c"}, "options": {"cas": 1}}`)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	data, ok := s.srv.Secret("secret/db")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(data, jc.DeepEquals, map[string]interface{}{"a": "c"})
+}
+
+func (s *serverSuite) TestKVListAndMetadata(c *gc.C) {
+	s.srv.Write("secret/app/db", map[string]interface{}{"password": "p"})
+	s.srv.Write("secret/app/api/key", map[string]interface{}{"key": "k"})
+	s.srv.Write("secret/other", map[string]interface{}{"x": "y"})
+	s.clock.Advance(time.Minute)
+	s.srv.Write("secret/app/db", map[string]interface{}{"password": "q"})
+	c.Assert(s.srv.Accesses(), gc.HasLen, 0)
+
+	status, resp := s.do(c, s.srv.RootToken, "LIST", "secret/metadata/app", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["data"], jc.DeepEquals, map[string]interface{}{
+		"keys": []interface{}{"api/", "db"},
+	})
+	status, resp = s.do(c, s.srv.RootToken, "GET", "secret/metadata/?list=true", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["data"], jc.DeepEquals, map[string]interface{}{
+		"keys": []interface{}{"app/", "other"},
+	})
+	status, _ = s.do(c, s.srv.RootToken, "LIST", "secret/metadata/nothing", "")
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+
+	status, resp = s.do(c, s.srv.RootToken, "GET", "secret/metadata/app/db", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	data := resp["data"].(map[string]interface{})
+	c.Assert(data["current_version"], gc.Equals, 2.0)
+	c.Assert(data["created_time"], gc.Equals, "2026-03-01T12:00:00Z")
+	c.Assert(data["updated_time"], gc.Equals, "2026-03-01T12:01:00Z")
+	c.Assert(data["versions"], gc.HasLen, 2)
+
+	c.Assert(s.srv.Accesses(), jc.DeepEquals, []vaulttesting.Access{
+		{Op: "list", Path: "secret/app/"},
+		{Op: "list", Path: "secret/"},
+	})
+}
+
+func (s *serverSuite) TestTokens(c *gc.C) {
+	status, resp := s.do(c, "bad", "GET", "auth/token/lookup-self", "")
+	c.Assert(status, gc.Equals, http.StatusForbidden)
+	c.Assert(resp["errors"], jc.DeepEquals, []interface{}{"permission denied"})
+
+	status, resp = s.do(c, s.srv.RootToken, "POST", "auth/token/create", `{"ttl": "1h"}`)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	auth := resp["auth"].(map[string]interface{})
+	c.Assert(auth["lease_duration"], gc.Equals, 3600.0)
+	c.Assert(auth["renewable"], jc.IsTrue)
+	token := auth["client_token"].(string)
+
+	s.clock.Advance(30 * time.Minute)
+	status, resp = s.do(c, token, "GET", "auth/token/lookup-self", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["data"].(map[string]interface{})["ttl"], gc.Equals, 1800.0)
+
+	status, resp = s.do(c, token, "POST", "auth/token/renew-self", `{"increment": 7200}`)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["auth"].(map[string]interface{})["lease_duration"], gc.Equals, 7200.0)
+
+	s.clock.Advance(2 * time.Hour)
+	status, _ = s.do(c, token, "GET", "auth/token/lookup-self", "")
+	c.Assert(status, gc.Equals, http.StatusForbidden)
+
+	// The root token cannot be renewed, but never expires.
+	status, _ = s.do(c, s.srv.RootToken, "POST", "auth/token/renew-self", "")
+	c.Assert(status, gc.Equals, http.StatusBadRequest)
+
+	token = s.srv.CreateToken(c, time.Minute)
+	status, _ = s.do(c, token, "POST", "auth/token/revoke-self", "")
+	c.Assert(status, gc.Equals, http.StatusNoContent)
+	status, _ = s.do(c, token, "GET", "auth/token/lookup-self", "")
+	c.Assert(status, gc.Equals, http.StatusForbidden)
+}
+
+func (s *serverSuite) TestLeases(c *gc.C) {
+	s.srv.AddLeasedSecret("database/creds/app", map[string]interface{}{
+		"username": "app",
+		"password": "p",
+	}, time.Hour)
+	token := s.srv.CreateToken(c, 0)
+	status, resp := s.do(c, token, "GET", "database/creds/app", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["data"], jc.DeepEquals, map[string]interface{}{"username": "app", "password": "p"})
+	c.Assert(resp["lease_duration"], gc.Equals, 3600.0)
+	c.Assert(resp["renewable"], jc.IsTrue)
+	leaseID := resp["lease_id"].(string)
+	c.Assert(leaseID, jc.HasPrefix, "database/creds/app/")
+	c.Assert(s.srv.Leases(), jc.DeepEquals, []vaulttesting.Lease{{
+		ID:     leaseID,
+		Path:   "database/creds/app",
+		Expiry: s.clock.Now().Add(time.Hour),
+	}})
+	c.Assert(s.srv, vaulttesting.HasRead, "database/creds/app")
+
+	s.clock.Advance(50 * time.Minute)
+	status, resp = s.do(c, token, "PUT", "sys/leases/lookup", `{"lease_id": "`+leaseID+`"}`)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["data"].(map[string]interface{})["ttl"], gc.Equals, 600.0)
+
+	// Renewing without an increment extends the lease by its TTL.
+	status, resp = s.do(c, token, "PUT", "sys/leases/renew", `{"lease_id": "`+leaseID+`"}`)
+	c.Assert(status, gc.Equals, http.StatusOK)
+	c.Assert(resp["lease_duration"], gc.Equals, 3600.0)
+
+	s.clock.Advance(time.Hour)
+	c.Assert(s.srv.Leases(), gc.HasLen, 0)
+	status, resp = s.do(c, token, "PUT", "sys/leases/renew", `{"lease_id": "`+leaseID+`"}`)
+	c.Assert(status, gc.Equals, http.StatusBadRequest)
+	c.Assert(resp["errors"], jc.DeepEquals, []interface{}{"lease not found"})
+
+	_, resp = s.do(c, token, "GET", "database/creds/app", "")
+	leaseID = resp["lease_id"].(string)
+	status, _ = s.do(c, token, "PUT", "sys/leases/revoke", `{"lease_id": "`+leaseID+`"}`)
+	c.Assert(status, gc.Equals, http.StatusNoContent)
+	c.Assert(s.srv.Leases(), gc.HasLen, 0)
+}
+
+func (s *serverSuite) TestInjectFault(c *gc.C) {
+	s.srv.Write("secret/db", map[string]interface{}{"a": "b"})
+	s.srv.InjectFault("secret/data/db", 2, vaulttesting.Fault{
+		Status: http.StatusServiceUnavailable,
+		Errors: []string{"Vault is sealed"},
+	})
+	status, _ := s.do(c, s.srv.RootToken, "GET", "secret/data/other", "")
+	c.Assert(status, gc.Equals, http.StatusNotFound)
+	for i := 0; i < 2; i++ {
+		status, resp := s.do(c, s.srv.RootToken, "GET", "secret/data/db", "")
+		c.Assert(status, gc.Equals, http.StatusServiceUnavailable)
+		c.Assert(resp["errors"], jc.DeepEquals, []interface{}{"Vault is sealed"})
+	}
+	status, _ = s.do(c, s.srv.RootToken, "GET", "secret/data/db", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+	// Failed requests are not recorded as accesses.
+	c.Assert(s.srv.Accesses(), gc.HasLen, 1)
+}
+
+func (s *serverSuite) TestInjectDelay(c *gc.C) {
+	s.srv.Write("secret/db", map[string]interface{}{"a": "b"})
+	s.srv.InjectFault("", 1, vaulttesting.Fault{Delay: testing.LongWait})
+	ctx, cancel := context.WithTimeout(context.Background(), testing.ShortWait)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", s.srv.URL+"/v1/secret/data/db", nil)
+	c.Assert(err, gc.IsNil)
+	req.Header.Set("X-Vault-Token", s.srv.RootToken)
+	_, err = http.DefaultClient.Do(req)
+	c.Assert(err, gc.ErrorMatches, ".*context deadline exceeded")
+
+	// The delay applied to only one request.
+	status, _ := s.do(c, s.srv.RootToken, "GET", "secret/data/db", "")
+	c.Assert(status, gc.Equals, http.StatusOK)
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+)
+
+// WallClockGuard records uses of the wall clock made through a hook
+// patched by PatchWallClock, so that a test can check that the code
+// under test uses the clock it was given rather than the real time.
+type WallClockGuard struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+// PatchWallClock guards the wall clock hook pointed to by dest, which
+// must be a *clock.Clock, such as a package variable holding
+// clock.WallClock that code falls back to when no clock is injected,
+// or a *func() time.Time, such as a package variable holding time.Now.
+// The hook is replaced by one that behaves as before, but records the
+// call site of each use in the returned guard, until the returned
+// Restorer is called.
+//
+// Go provides no way to intercept calls to time.Now and the like made
+// directly, so only uses of the wall clock through the hook are
+// recorded.
+func PatchWallClock(dest interface{}) (*WallClockGuard, Restorer) {
+	g := &WallClockGuard{}
+	switch dest := dest.(type) {
+	case *clock.Clock:
+		clk := *dest
+		if clk == nil {
+			clk = clock.WallClock
+		}
+		return g, Patch(dest, clock.Clock(&guardedClock{clk, g}))
+	case *func() time.Time:
+		now := *dest
+		return g, Patch(dest, func() time.Time {
+			g.record("time.Now")
+			return now()
+		})
+	}
+	panic(fmt.Errorf("cannot guard wall clock hook of type %T", dest))
+}
+
+// record records a call of the named function by the caller of the
+// hook.
+func (g *WallClockGuard) record(name string) {
+	// Skip record and the hook to find the hook's caller.
+	site := "unknown location"
+	if pc, file, line, ok := runtime.Caller(2); ok {
+		site = fmt.Sprintf("%s:%d", file, line)
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			site += " (" + fn.Name() + ")"
+		}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls = append(g.calls, fmt.Sprintf("%s called at %s", name, site))
+}
+
+// Calls returns a description of each use of the wall clock recorded
+// so far, in the order they were made, naming the function called and
+// its call site.
+func (g *WallClockGuard) Calls() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.calls...)
+}
+
+// Check fails the test, listing the call sites, if the wall clock has
+// been used through the guarded hook, and returns whether it has not.
+// Repeated calls from the same site are listed once, with a count.
+func (g *WallClockGuard) Check(c *gc.C) bool {
+	calls := g.Calls()
+	if len(calls) == 0 {
+		return true
+	}
+	var sites []string
+	counts := make(map[string]int)
+	for _, call := range calls {
+		if counts[call] == 0 {
+			sites = append(sites, call)
+		}
+		counts[call]++
+	}
+	lines := make([]string, len(sites))
+	for i, site := range sites {
+		lines[i] = site
+		if n := counts[site]; n > 1 {
+			lines[i] += fmt.Sprintf(" (%d times)", n)
+		}
+	}
+	c.Errorf("wall clock used instead of an injected clock:\n  %s", strings.Join(lines, "\n  "))
+	return false
+}
+
+// guardedClock is a clock.Clock that records its uses in a
+// WallClockGuard.
+type guardedClock struct {
+	clk   clock.Clock
+	guard *WallClockGuard
+}
+
+// Now implements clock.Clock.
+func (clk *guardedClock) Now() time.Time {
+	clk.guard.record("Now")
+	return clk.clk.Now()
+}
+
+// After implements clock.Clock.
+func (clk *guardedClock) After(d time.Duration) <-chan time.Time {
+	clk.guard.record("After")
+	return clk.clk.After(d)
+}
+
+// AfterFunc implements clock.Clock.
+func (clk *guardedClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	clk.guard.record("AfterFunc")
+	return clk.clk.AfterFunc(d, f)
+}
+
+// NewTimer implements clock.Clock.
+func (clk *guardedClock) NewTimer(d time.Duration) clock.Timer {
+	clk.guard.record("NewTimer")
+	return clk.clk.NewTimer(d)
+}
+
+// PatchWallClock is like the package function of the same name, except
+// that the test fails if the wall clock is used through the hook before
+// the test finishes, when the hook is restored. It returns the guard,
+// so that the uses can also be checked part way through a test.
+func (s *CleanupSuite) PatchWallClock(dest interface{}) *WallClockGuard {
+	g, restore := PatchWallClock(dest)
+	s.AddCleanup(func(c *gc.C) {
+		restore()
+		g.Check(c)
+	})
+	return g
+}
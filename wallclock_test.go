@@ -0,0 +1,107 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/testing/testclock"
+)
+
+type wallClockSuite struct{}
+
+var _ = gc.Suite(&wallClockSuite{})
+
+// defaultClock and timeNow are hooks of the kind guarded by
+// PatchWallClock.
+var (
+	defaultClock clock.Clock = clock.WallClock
+	timeNow                  = time.Now
+)
+
+// deadline stands for code under test that falls back to the wall
+// clock when it is not given one.
+func deadline(clk clock.Clock) time.Time {
+	if clk == nil {
+		clk = defaultClock
+	}
+	return clk.Now().Add(time.Minute)
+}
+
+func (*wallClockSuite) TestPatchWallClockClock(c *gc.C) {
+	g, restore := testing.PatchWallClock(&defaultClock)
+	c.Assert(defaultClock, gc.Not(gc.Equals), clock.WallClock)
+
+	deadline(testclock.NewClock(time.Time{}))
+	c.Assert(g.Calls(), gc.HasLen, 0)
+
+	before := time.Now()
+	c.Assert(deadline(nil).After(before), jc.IsTrue)
+	<-defaultClock.After(0)
+	defaultClock.NewTimer(time.Hour).Stop()
+	defaultClock.AfterFunc(time.Hour, func() {}).Stop()
+	restore()
+	c.Assert(defaultClock, gc.Equals, clock.WallClock)
+	deadline(nil)
+
+	calls := g.Calls()
+	c.Assert(calls, gc.HasLen, 4)
+	c.Check(calls[0], gc.Matches, `Now called at .*/wallclock_test\.go:\d+ \(github\.com/juju/testing_test\.deadline\)`)
+	c.Check(calls[1], gc.Matches, `After called at .*/wallclock_test\.go:\d+ \(.*TestPatchWallClockClock\)`)
+	c.Check(calls[2], gc.Matches, `NewTimer called at .*`)
+	c.Check(calls[3], gc.Matches, `AfterFunc called at .*`)
+}
+
+func (*wallClockSuite) TestPatchWallClockFunc(c *gc.C) {
+	g, restore := testing.PatchWallClock(&timeNow)
+	before := time.Now()
+	c.Assert(timeNow().Before(before), jc.IsFalse)
+	restore()
+	timeNow()
+	c.Assert(g.Calls(), gc.HasLen, 1)
+	c.Check(g.Calls()[0], gc.Matches, `time\.Now called at .*/wallclock_test\.go:\d+ \(.*TestPatchWallClockFunc\)`)
+}
+
+func (*wallClockSuite) TestPatchWallClockBadHook(c *gc.C) {
+	var now time.Time
+	c.Assert(func() { testing.PatchWallClock(&now) }, gc.PanicMatches, `cannot guard wall clock hook of type \*time\.Time`)
+}
+
+// guardedSuite is run by the tests of wallClockSuite rather than being
+// registered with gocheck.
+type guardedSuite struct {
+	testing.CleanupSuite
+}
+
+func (s *guardedSuite) SetUpTest(c *gc.C) {
+	s.CleanupSuite.SetUpTest(c)
+	s.PatchWallClock(&defaultClock)
+}
+
+func (*guardedSuite) TestInjected(c *gc.C) {
+	deadline(testclock.NewClock(time.Time{}))
+}
+
+func (*guardedSuite) TestWallClock(c *gc.C) {
+	for i := 0; i < 3; i++ {
+		deadline(nil)
+	}
+}
+
+func (*wallClockSuite) TestCleanupSuitePatchWallClock(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&guardedSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Passed(), jc.IsFalse)
+	c.Assert(output.String(), gc.Matches, `(?s).*guardedSuite\.TearDownTest\n.*`+
+		`\.\.\. Error: wall clock used instead of an injected clock:\n`+
+		`  Now called at .*/wallclock_test\.go:\d+ \(github\.com/juju/testing_test\.deadline\) \(3 times\)\n.*`+
+		`PANIC: .*guardedSuite\.TestWallClock\n.*`)
+	c.Assert(defaultClock, gc.Equals, clock.WallClock)
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"fmt"
+	"os"
+
+	gc "gopkg.in/check.v1"
+)
+
+// PatchWd changes the working directory to dir, which is usually one
+// made with c.MkDir, and returns a function that changes it back. If
+// the working directory is no longer dir when the function is called,
+// something else, perhaps a test running in parallel, has changed it,
+// and the test fails. The original working directory is restored
+// regardless.
+func PatchWd(c *gc.C, dir string) Restorer {
+	restore := patchWd(c, dir)
+	return func() { restore(c) }
+}
+
+func patchWd(c *gc.C, dir string) func(*gc.C) {
+	oldWd, err := os.Getwd()
+	c.Assert(err, gc.IsNil)
+	err = os.Chdir(dir)
+	c.Assert(err, gc.IsNil)
+	// Record the directory as os.Getwd will report it, which may
+	// differ from dir if it is relative or holds symlinks.
+	newWd, err := os.Getwd()
+	c.Assert(err, gc.IsNil)
+
+	var id int
+	restored := false
+	restore := func(c *gc.C) {
+		if restored {
+			return
+		}
+		restored = true
+		activePatches.remove(id)
+		if wd, err := os.Getwd(); err != nil || wd != newWd {
+			c.Errorf("working directory changed by someone else: got %q, want %q", wd, newWd)
+		}
+		if err := os.Chdir(oldWd); err != nil {
+			c.Errorf("cannot restore working directory: %v", err)
+		}
+	}
+	// The leak check in IsolationSuite restores the working
+	// directory itself, so the registered restore does not need
+	// a *gc.C.
+	id = activePatches.add(fmt.Sprintf("working directory patched at %s", patchCaller()), func() {
+		restored = true
+		activePatches.remove(id)
+		os.Chdir(oldWd)
+	})
+	return restore
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"os"
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type wdSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&wdSuite{})
+
+func getwd(c *gc.C) string {
+	wd, err := os.Getwd()
+	c.Assert(err, jc.ErrorIsNil)
+	return wd
+}
+
+func (s *wdSuite) TestPatchWd(c *gc.C) {
+	oldWd := getwd(c)
+	dir := c.MkDir()
+	restore := testing.PatchWd(c, dir)
+	wantDir, err := filepath.EvalSymlinks(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	gotDir, err := filepath.EvalSymlinks(getwd(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotDir, gc.Equals, wantDir)
+
+	restore()
+	c.Assert(getwd(c), gc.Equals, oldWd)
+	// Restoring again does nothing.
+	restore()
+	c.Assert(getwd(c), gc.Equals, oldWd)
+}
+
+func (s *wdSuite) TestPatchWdChangedElsewhere(c *gc.C) {
+	oldWd := getwd(c)
+	restore := testing.PatchWd(c, c.MkDir())
+	err := os.Chdir(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		c.Assert(getwd(c), gc.Equals, oldWd)
+	}()
+	c.ExpectFailure("working directory changed by someone else")
+	restore()
+}
+
+func (s *wdSuite) TestCleanupSuitePatchWd(c *gc.C) {
+	oldWd := getwd(c)
+	var suite testing.CleanupSuite
+	suite.SetUpSuite(c)
+	suite.SetUpTest(c)
+	suite.PatchWd(c, c.MkDir())
+	c.Assert(getwd(c), gc.Not(gc.Equals), oldWd)
+	suite.TearDownTest(c)
+	suite.TearDownSuite(c)
+	c.Assert(getwd(c), gc.Equals, oldWd)
+}
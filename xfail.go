@@ -0,0 +1,53 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+)
+
+// XFail runs f, which is expected to fail because of a known bug
+// tracked by the given issue, such as a bug tracker URL. The test
+// succeeds if f fails, logging its failure so that it can be seen with
+// -check.v, and fails with an XPASS error if f unexpectedly succeeds,
+// so that the marker is removed once the bug is fixed:
+//
+//	func (s *fooSuite) TestUnicodeNames(c *gc.C) {
+//		testing.XFail(c, "https://bugs.launchpad.net/juju/+bug/1234", func(c *gc.C) {
+//			c.Assert(foo.Normalise("café"), gc.Equals, "cafe")
+//		})
+//	}
+//
+// This keeps the coverage of known bugs, rather than deleting or
+// skipping their tests. Unlike c.ExpectFailure, only the failures of f
+// are expected, and not those of the rest of the test.
+//
+// Since f is run as a separate gocheck test, it is given its own
+// *gc.C, which must be used in place of the test's.
+func XFail(c *gc.C, issue string, f func(c *gc.C)) {
+	var output bytes.Buffer
+	result := gc.Run(&xfailSuite{f}, &gc.RunConf{Output: &output})
+	if result.Passed() {
+		c.Errorf("XPASS: succeeded, but is expected to fail because of %s; remove the XFail if it has been fixed", issue)
+		return
+	}
+	c.Logf("XFAIL: failed as expected because of %s:\n%s", issue, indent(strings.TrimSpace(output.String())))
+}
+
+// xfailSuite runs the function passed to XFail.
+type xfailSuite struct {
+	f func(c *gc.C)
+}
+
+func (s *xfailSuite) TestXFail(c *gc.C) {
+	s.f(c)
+}
+
+// indent indents every line of s with a tab.
+func indent(s string) string {
+	return "\t" + strings.ReplaceAll(s, "\n", "\n\t")
+}
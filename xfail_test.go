@@ -0,0 +1,84 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"bytes"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+)
+
+type xfailSuite struct{}
+
+var _ = gc.Suite(&xfailSuite{})
+
+func (*xfailSuite) TestXFail(c *gc.C) {
+	ran := false
+	testing.XFail(c, "lp:1234", func(c *gc.C) {
+		ran = true
+		c.Assert(1, gc.Equals, 2)
+	})
+	c.Assert(ran, jc.IsTrue)
+	c.Assert(c.GetTestLog(), gc.Matches, `(?s)XFAIL: failed as expected because of lp:1234:\n\t-+\n\tFAIL: .*\n\t.*obtained int = 1\n.*`)
+}
+
+func (*xfailSuite) TestXFailPanic(c *gc.C) {
+	testing.XFail(c, "lp:1234", func(c *gc.C) {
+		panic("boom")
+	})
+	c.Assert(c.GetTestLog(), gc.Matches, `(?s)XFAIL: failed as expected because of lp:1234:\n.*PANIC: .*boom.*`)
+}
+
+// xpassSuite is run by TestXPass rather than being registered with
+// gocheck.
+type xpassSuite struct{}
+
+func (*xpassSuite) TestXPass(c *gc.C) {
+	testing.XFail(c, "lp:1234", func(c *gc.C) {})
+}
+
+func (*xfailSuite) TestXPass(c *gc.C) {
+	var output bytes.Buffer
+	result := gc.Run(&xpassSuite{}, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(output.String(), gc.Matches, `(?s).*XPASS: succeeded, but is expected to fail because of lp:1234; remove the XFail if it has been fixed\n.*`)
+}
+
+type xfailCase struct {
+	about string
+	fail  bool
+	xfail string
+}
+
+// xfailTableSuite is run by TestRunTableXFail rather than being
+// registered with gocheck.
+type xfailTableSuite struct {
+	ran []string
+}
+
+func (s *xfailTableSuite) TestTable(c *gc.C) {
+	cases := []xfailCase{
+		{about: "passes"},
+		{about: "known bug", fail: true, xfail: "lp:1"},
+		{about: "fixed bug", xfail: "lp:2"},
+	}
+	testing.RunTable(c, cases, func(c *gc.C, test xfailCase) {
+		s.ran = append(s.ran, test.about)
+		c.Assert(test.fail, jc.IsFalse)
+	})
+}
+
+func (*xfailSuite) TestRunTableXFail(c *gc.C) {
+	var output bytes.Buffer
+	suite := &xfailTableSuite{}
+	result := gc.Run(suite, &gc.RunConf{Output: &output})
+	c.Assert(result.Failed, gc.Equals, 1)
+	c.Assert(suite.ran, jc.DeepEquals, []string{"passes", "known bug", "fixed bug"})
+	c.Assert(output.String(), gc.Matches, `(?s).*test 1: known bug\nXFAIL: failed as expected because of lp:1:\n.*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*test 2: fixed bug\n.*XPASS: succeeded, but is expected to fail because of lp:2; .*`)
+	c.Assert(output.String(), gc.Matches, `(?s).*\n1 of 3 cases failed: 2 \(fixed bug\)\n.*`)
+}